@@ -40,9 +40,15 @@ import (
 
 	"github.com/openyurtio/openyurt/cmd/yurt-iot-dock/app/options"
 	"github.com/openyurtio/openyurt/pkg/apis"
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	iotv1beta1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1beta1"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
 	edgexclients "github.com/openyurtio/openyurt/pkg/yurtiotdock/clients/edgex-foundry"
+	mqttclients "github.com/openyurtio/openyurt/pkg/yurtiotdock/clients/mqtt"
 	"github.com/openyurtio/openyurt/pkg/yurtiotdock/controllers"
 	"github.com/openyurtio/openyurt/pkg/yurtiotdock/controllers/util"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/readings"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/writebuffer"
 )
 
 var (
@@ -55,6 +61,8 @@ func init() {
 
 	_ = apis.AddToScheme(clientgoscheme.Scheme)
 	_ = apis.AddToScheme(scheme)
+	// the device kinds are only served in iot/v1alpha1, which is not added by apis
+	_ = iotv1alpha1.AddToScheme(scheme)
 
 	// +kubebuilder:scaffold:scheme
 }
@@ -128,17 +136,26 @@ func Run(opts *options.YurtIoTDockOptions, stopCh <-chan struct{}) {
 		}
 	}
 
-	edgexdock := edgexclients.NewEdgexDock(opts.Version, opts.CoreMetadataAddr, opts.CoreCommandAddr)
+	iotdock := newIoTDock(opts)
+
+	controllers.RegisterMetrics()
+
+	// the writes to the apiserver are buffered while the nodepool is disconnected from the cloud
+	cli, err := newWriteBufferClient(mgr, opts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up write buffer")
+		os.Exit(1)
+	}
 
 	// setup the DeviceProfile Reconciler and Syncer
 	if err = (&controllers.DeviceProfileReconciler{
-		Client: mgr.GetClient(),
+		Client: cli,
 		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, opts, edgexdock); err != nil {
+	}).SetupWithManager(mgr, opts, iotdock); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DeviceProfile")
 		os.Exit(1)
 	}
-	dfs, err := controllers.NewDeviceProfileSyncer(mgr.GetClient(), opts, edgexdock)
+	dfs, err := controllers.NewDeviceProfileSyncer(cli, opts, iotdock)
 	if err != nil {
 		setupLog.Error(err, "unable to create syncer", "syncer", "DeviceProfile")
 		os.Exit(1)
@@ -151,13 +168,13 @@ func Run(opts *options.YurtIoTDockOptions, stopCh <-chan struct{}) {
 
 	// setup the Device Reconciler and Syncer
 	if err = (&controllers.DeviceReconciler{
-		Client: mgr.GetClient(),
+		Client: cli,
 		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, opts, edgexdock); err != nil {
+	}).SetupWithManager(mgr, opts, iotdock); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Device")
 		os.Exit(1)
 	}
-	ds, err := controllers.NewDeviceSyncer(mgr.GetClient(), opts, edgexdock)
+	ds, err := controllers.NewDeviceSyncer(cli, opts, iotdock)
 	if err != nil {
 		setupLog.Error(err, "unable to create syncer", "controller", "Device")
 		os.Exit(1)
@@ -170,13 +187,13 @@ func Run(opts *options.YurtIoTDockOptions, stopCh <-chan struct{}) {
 
 	// setup the DeviceService Reconciler and Syncer
 	if err = (&controllers.DeviceServiceReconciler{
-		Client: mgr.GetClient(),
+		Client: cli,
 		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr, opts, edgexdock); err != nil {
+	}).SetupWithManager(mgr, opts, iotdock); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DeviceService")
 		os.Exit(1)
 	}
-	dss, err := controllers.NewDeviceServiceSyncer(mgr.GetClient(), opts, edgexdock)
+	dss, err := controllers.NewDeviceServiceSyncer(cli, opts, iotdock)
 	if err != nil {
 		setupLog.Error(err, "unable to create syncer", "syncer", "DeviceService")
 		os.Exit(1)
@@ -186,6 +203,32 @@ func Run(opts *options.YurtIoTDockOptions, stopCh <-chan struct{}) {
 		setupLog.Error(err, "unable to create syncer runnable", "syncer", "DeviceService")
 		os.Exit(1)
 	}
+
+	// setup the DeviceCommand Reconciler
+	if err = (&controllers.DeviceCommandReconciler{
+		Client: cli,
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr, opts, iotdock); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DeviceCommand")
+		os.Exit(1)
+	}
+
+	// setup the DeviceSet Reconciler
+	if err = (&controllers.DeviceSetReconciler{
+		Client: cli,
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr, opts, iotdock); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DeviceSet")
+		os.Exit(1)
+	}
+
+	// setup the collector and server of device readings
+	if len(opts.ReadingsAddr) != 0 {
+		if err := setupDeviceReadings(mgr, opts, iotdock); err != nil {
+			setupLog.Error(err, "unable to set up device readings")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
@@ -204,6 +247,56 @@ func Run(opts *options.YurtIoTDockOptions, stopCh <-chan struct{}) {
 	}
 }
 
+func newWriteBufferClient(mgr ctrl.Manager, opts *options.YurtIoTDockOptions) (client.Client, error) {
+	if opts.WriteBufferMaxObjects == 0 {
+		return mgr.GetClient(), nil
+	}
+	buffer, err := writebuffer.NewBuffer(mgr.GetScheme(), writebuffer.Options{
+		Dir:        opts.WriteBufferDir,
+		MaxObjects: opts.WriteBufferMaxObjects,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the buffered writes are applied by the client of manager, and the latest objects are read from apiserver
+	if err := mgr.Add(writebuffer.NewFlusher(buffer, mgr.GetClient(), mgr.GetAPIReader(), opts.WriteBufferFlushPeriod)); err != nil {
+		return nil, err
+	}
+	return writebuffer.NewClient(mgr.GetClient(), buffer), nil
+}
+
+func setupDeviceReadings(mgr ctrl.Manager, opts *options.YurtIoTDockOptions, iotdock clients.IoTDock) error {
+	readingCli, err := iotdock.CreateDeviceReadingClient()
+	if err != nil {
+		setupLog.Info("device readings are not served, the readings of platform are unavailable", "reason", err.Error())
+		return nil
+	}
+	store, err := readings.NewStore(readings.Options{
+		MaxReadingsPerProperty: opts.ReadingsPerProperty,
+		Retention:              opts.ReadingsRetention,
+		DataDir:                opts.ReadingsDataDir,
+		MaxDiskBytes:           opts.ReadingsMaxDiskBytes,
+	})
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(readings.NewCollector(readingCli, store)); err != nil {
+		return err
+	}
+	// the requests of readings are authenticated and authorized by kube-apiserver
+	authn, authz, err := readings.NewDelegatingAuth(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	serving := readings.SecureServing{
+		CertFile:      opts.ReadingsCertFile,
+		KeyFile:       opts.ReadingsKeyFile,
+		Authenticator: authn,
+		Authorizer:    authz,
+	}
+	return mgr.Add(readings.NewServer(opts.ReadingsAddr, serving, mgr.GetClient(), store, opts.Namespace, opts.Nodepool))
+}
+
 func deleteCRsOnControllerShutdown(ctx context.Context, cli client.Client, opts *options.YurtIoTDockOptions) error {
 	setupLog.Info("[deleteCRsOnControllerShutdown] start delete device crd")
 	if err := controllers.DeleteDevicesOnControllerShutdown(ctx, cli, opts); err != nil {
@@ -248,6 +341,16 @@ func SetupSignalHandler(client client.Client, opts *options.YurtIoTDockOptions)
 	return ctx
 }
 
+// newIoTDock creates the dock of edge-side device platform selected by PlatformAdmin
+func newIoTDock(opts *options.YurtIoTDockOptions) clients.IoTDock {
+	switch opts.Platform {
+	case iotv1beta1.PlatformAdminPlatformMQTT:
+		return mqttclients.NewMQTTDock(opts.MQTTBrokerAddr, opts.MQTTTopicPrefix, "")
+	default:
+		return edgexclients.NewEdgexDock(opts.Version, opts.CoreMetadataAddr, opts.CoreCommandAddr, opts.MessageBusType, opts.MessageBusAddr)
+	}
+}
+
 func preflightCheck(mgr ctrl.Manager, opts *options.YurtIoTDockOptions) error {
 	client, err := kubernetes.NewForConfig(mgr.GetConfig())
 	if err != nil {
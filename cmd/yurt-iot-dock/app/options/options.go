@@ -19,42 +19,110 @@ package options
 import (
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/spf13/pflag"
+
+	iotv1beta1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1beta1"
 )
 
 // YurtIoTDockOptions is the main settings for the yurt-iot-dock
 type YurtIoTDockOptions struct {
-	MetricsAddr          string
-	ProbeAddr            string
-	EnableLeaderElection bool
-	Nodepool             string
-	Namespace            string
-	Version              string
-	CoreDataAddr         string
-	CoreMetadataAddr     string
-	CoreCommandAddr      string
-	EdgeSyncPeriod       uint
+	MetricsAddr            string
+	ProbeAddr              string
+	EnableLeaderElection   bool
+	Nodepool               string
+	Namespace              string
+	Version                string
+	CoreDataAddr           string
+	CoreMetadataAddr       string
+	CoreCommandAddr        string
+	EdgeSyncPeriod         uint
+	EdgeResyncPeriod       uint
+	MessageBusType         string
+	MessageBusAddr         string
+	Platform               string
+	MQTTBrokerAddr         string
+	MQTTTopicPrefix        string
+	ReadingsAddr           string
+	ReadingsCertFile       string
+	ReadingsKeyFile        string
+	ReadingsPerProperty    int
+	ReadingsRetention      time.Duration
+	ReadingsDataDir        string
+	ReadingsMaxDiskBytes   int64
+	WriteBufferDir         string
+	WriteBufferMaxObjects  int
+	WriteBufferFlushPeriod time.Duration
 }
 
 func NewYurtIoTDockOptions() *YurtIoTDockOptions {
 	return &YurtIoTDockOptions{
-		MetricsAddr:          ":8080",
-		ProbeAddr:            ":8080",
-		EnableLeaderElection: false,
-		Nodepool:             "",
-		Namespace:            "default",
-		Version:              "",
-		CoreDataAddr:         "edgex-core-data:59880",
-		CoreMetadataAddr:     "edgex-core-metadata:59881",
-		CoreCommandAddr:      "edgex-core-command:59882",
-		EdgeSyncPeriod:       5,
+		MetricsAddr:            ":8080",
+		ProbeAddr:              ":8080",
+		EnableLeaderElection:   false,
+		Nodepool:               "",
+		Namespace:              "default",
+		Version:                "",
+		CoreDataAddr:           "edgex-core-data:59880",
+		CoreMetadataAddr:       "edgex-core-metadata:59881",
+		CoreCommandAddr:        "edgex-core-command:59882",
+		EdgeSyncPeriod:         5,
+		EdgeResyncPeriod:       600,
+		MessageBusType:         "redis",
+		MessageBusAddr:         "edgex-redis:6379",
+		Platform:               iotv1beta1.PlatformAdminPlatformEdgeX,
+		MQTTBrokerAddr:         "mqtt-broker:1883",
+		MQTTTopicPrefix:        "openyurt/iot",
+		ReadingsAddr:           ":8443",
+		ReadingsCertFile:       "",
+		ReadingsKeyFile:        "",
+		ReadingsPerProperty:    100,
+		ReadingsRetention:      time.Hour,
+		ReadingsDataDir:        "",
+		ReadingsMaxDiskBytes:   32 * 1024 * 1024,
+		WriteBufferDir:         "",
+		WriteBufferMaxObjects:  10000,
+		WriteBufferFlushPeriod: 5 * time.Second,
 	}
 }
 
 func ValidateOptions(options *YurtIoTDockOptions) error {
-	if err := ValidateEdgePlatformAddress(options); err != nil {
-		return err
+	switch options.Platform {
+	case iotv1beta1.PlatformAdminPlatformEdgeX:
+		if err := ValidateEdgePlatformAddress(options); err != nil {
+			return err
+		}
+		if options.MessageBusType != "redis" && options.MessageBusType != "mqtt" {
+			return fmt.Errorf("unsupported message bus type %s, must be redis or mqtt", options.MessageBusType)
+		}
+	case iotv1beta1.PlatformAdminPlatformMQTT:
+		if _, _, err := net.SplitHostPort(options.MQTTBrokerAddr); err != nil {
+			return fmt.Errorf("invalid mqtt broker address: %s", err)
+		}
+	default:
+		return fmt.Errorf("unsupported platform %s, must be %s or %s", options.Platform,
+			iotv1beta1.PlatformAdminPlatformEdgeX, iotv1beta1.PlatformAdminPlatformMQTT)
+	}
+	if len(options.ReadingsAddr) != 0 {
+		if _, _, err := net.SplitHostPort(options.ReadingsAddr); err != nil {
+			return fmt.Errorf("invalid readings bind address: %s", err)
+		}
+		if (len(options.ReadingsCertFile) == 0) != (len(options.ReadingsKeyFile) == 0) {
+			return fmt.Errorf("readings-tls-cert-file and readings-tls-private-key-file should be specified together")
+		}
+		if options.ReadingsPerProperty <= 0 {
+			return fmt.Errorf("readings-per-property %d should be positive", options.ReadingsPerProperty)
+		}
+	}
+	if options.WriteBufferMaxObjects < 0 {
+		return fmt.Errorf("write-buffer-max-objects %d should not be negative", options.WriteBufferMaxObjects)
+	}
+	if options.WriteBufferMaxObjects > 0 && options.WriteBufferFlushPeriod <= 0 {
+		return fmt.Errorf("write-buffer-flush-period %s should be positive", options.WriteBufferFlushPeriod)
+	}
+	if options.EdgeResyncPeriod < options.EdgeSyncPeriod {
+		return fmt.Errorf("edge-resync-period %d should not be less than edge-sync-period %d", options.EdgeResyncPeriod, options.EdgeSyncPeriod)
 	}
 	return nil
 }
@@ -69,6 +137,22 @@ func (o *YurtIoTDockOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.CoreDataAddr, "core-data-address", "edgex-core-data:59880", "The address of edge core-data service.")
 	fs.StringVar(&o.CoreMetadataAddr, "core-metadata-address", "edgex-core-metadata:59881", "The address of edge core-metadata service.")
 	fs.StringVar(&o.CoreCommandAddr, "core-command-address", "edgex-core-command:59882", "The address of edge core-command service.")
+	fs.UintVar(&o.EdgeResyncPeriod, "edge-resync-period", o.EdgeResyncPeriod, "The period of the full resynchronization between the device management platform and the cloud when the change notifications of the platform are watched.(in seconds)")
+	fs.StringVar(&o.MessageBusType, "message-bus-type", o.MessageBusType, "The type of edgex message bus which delivers the system events, redis or mqtt.")
+	fs.StringVar(&o.MessageBusAddr, "message-bus-address", o.MessageBusAddr, "The address of edgex message bus which delivers the system events, the periodic synchronization is used if it's empty.")
+	fs.StringVar(&o.Platform, "platform", o.Platform, "The edge-side device platform, edgex or mqtt.")
+	fs.StringVar(&o.MQTTBrokerAddr, "mqtt-broker-address", o.MQTTBrokerAddr, "The address of mqtt broker which hosts the device registry, only used by mqtt platform.")
+	fs.StringVar(&o.MQTTTopicPrefix, "mqtt-topic-prefix", o.MQTTTopicPrefix, "The root topic of the device registry on mqtt broker, only used by mqtt platform.")
+	fs.StringVar(&o.ReadingsAddr, "readings-bind-address", o.ReadingsAddr, "The address the device readings API binds to, it's disabled if it's empty. The API is served by https, and the requests are authenticated and authorized by kube-apiserver.")
+	fs.StringVar(&o.ReadingsCertFile, "readings-tls-cert-file", o.ReadingsCertFile, "The serving certificate of the device readings API, a self-signed certificate is generated if it's empty.")
+	fs.StringVar(&o.ReadingsKeyFile, "readings-tls-private-key-file", o.ReadingsKeyFile, "The private key of readings-tls-cert-file.")
+	fs.IntVar(&o.ReadingsPerProperty, "readings-per-property", o.ReadingsPerProperty, "The number of recent readings kept for each device property.")
+	fs.DurationVar(&o.ReadingsRetention, "readings-retention", o.ReadingsRetention, "The duration the device readings are kept, 0 means the readings are only limited by readings-per-property.")
+	fs.StringVar(&o.ReadingsDataDir, "readings-data-dir", o.ReadingsDataDir, "The directory where the device readings are persisted, the readings are kept in memory only if it's empty.")
+	fs.Int64Var(&o.ReadingsMaxDiskBytes, "readings-max-disk-bytes", o.ReadingsMaxDiskBytes, "The maximum size of the device readings persisted in readings-data-dir.")
+	fs.StringVar(&o.WriteBufferDir, "write-buffer-dir", o.WriteBufferDir, "The directory where the writes to the apiserver are persisted while the apiserver is unreachable, they are kept in memory only if it's empty.")
+	fs.IntVar(&o.WriteBufferMaxObjects, "write-buffer-max-objects", o.WriteBufferMaxObjects, "The maximum number of objects whose writes are buffered while the apiserver is unreachable, 0 means the writes are not buffered.")
+	fs.DurationVar(&o.WriteBufferFlushPeriod, "write-buffer-flush-period", o.WriteBufferFlushPeriod, "The period of applying the buffered writes to the apiserver.")
 	fs.UintVar(&o.EdgeSyncPeriod, "edge-sync-period", 5, "The period of the device management platform synchronizing the device status to the cloud.(in seconds,not less than 5 seconds)")
 }
 
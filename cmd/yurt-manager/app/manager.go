@@ -24,6 +24,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -35,6 +37,7 @@ import (
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
@@ -48,6 +51,7 @@ import (
 	"github.com/openyurtio/openyurt/pkg/projectinfo"
 	"github.com/openyurtio/openyurt/pkg/util/profile"
 	controller "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/base"
+	ravenutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/raven/util"
 	"github.com/openyurtio/openyurt/pkg/yurtmanager/webhook"
 	"github.com/openyurtio/openyurt/pkg/yurtmanager/webhook/util"
 )
@@ -199,6 +203,16 @@ func Run(c *config.CompletedConfig, stopCh <-chan struct{}) error {
 		Logger: setupLog,
 		Cache: cache.Options{
 			DefaultTransform: trimManagedFields,
+			ByObject: map[client.Object]cache.ByObject{
+				// controllers only read node leases and the endpoint health leases of raven,
+				// so leases of other namespaces are not cached.
+				&coordinationv1.Lease{}: {
+					Namespaces: map[string]cache.Config{
+						corev1.NamespaceNodeLease:  {},
+						ravenutil.WorkingNamespace: {},
+					},
+				},
+			},
 		},
 	})
 	if err != nil {
@@ -17,7 +17,11 @@ limitations under the License.
 package options
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/raven/gatewaypickup/config"
 )
@@ -30,6 +34,7 @@ func NewGatewayPickupControllerOptions() *GatewayPickupControllerOptions {
 	return &GatewayPickupControllerOptions{
 		&config.GatewayPickupControllerConfiguration{
 			ConcurrentGatewayPickupWorkers: 1,
+			EndpointFailoverPeriod:         metav1.Duration{Duration: 30 * time.Second},
 		},
 	}
 }
@@ -41,6 +46,7 @@ func (g *GatewayPickupControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	}
 
 	fs.Int32Var(&g.ConcurrentGatewayPickupWorkers, "concurrent-gateway-pickup-workers", g.ConcurrentGatewayPickupWorkers, "The number of gateway objects that are allowed to reconcile concurrently. Larger number = more responsive gateway pickup, but more CPU (and network) load")
+	fs.DurationVar(&g.EndpointFailoverPeriod.Duration, "gateway-endpoint-failover-period", g.EndpointFailoverPeriod.Duration, "The period after the health lease of an active gateway endpoint expires before the endpoint is failed over to a standby endpoint.")
 
 }
 
@@ -51,6 +57,7 @@ func (g *GatewayPickupControllerOptions) ApplyTo(cfg *config.GatewayPickupContro
 	}
 
 	cfg.ConcurrentGatewayPickupWorkers = g.ConcurrentGatewayPickupWorkers
+	cfg.EndpointFailoverPeriod = g.EndpointFailoverPeriod
 	return nil
 }
 
@@ -60,5 +67,8 @@ func (g *GatewayPickupControllerOptions) Validate() []error {
 		return nil
 	}
 	var errs []error
+	if g.EndpointFailoverPeriod.Duration < 0 {
+		errs = append(errs, fmt.Errorf("gateway-endpoint-failover-period(%v) is invalid, should not be negative", g.EndpointFailoverPeriod.Duration))
+	}
 	return errs
 }
@@ -33,6 +33,7 @@ func NewNodeBucketControllerOptions() *NodeBucketControllerOptions {
 		&config.NodeBucketControllerConfiguration{
 			MaxNodesPerBucket:           100,
 			ConcurrentNodeBucketWorkers: 3,
+			BucketAssignmentPolicy:      config.FillPolicy,
 		},
 	}
 }
@@ -44,6 +45,7 @@ func (n *NodeBucketControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	}
 
 	fs.Int32Var(&n.MaxNodesPerBucket, "max-nodes-per-bucket", n.MaxNodesPerBucket, "The maximum number of nodes that will be added to a NodeBucket. More nodes per bucket will result in less node buckets, but larger resources. Defaults to 100.")
+	fs.StringVar(&n.BucketAssignmentPolicy, "node-bucket-assignment-policy", n.BucketAssignmentPolicy, "The policy of assigning nodes to NodeBuckets, Fill or ConsistentHash. ConsistentHash keeps the assignment stable so adding or removing a node only changes one NodeBucket. Defaults to Fill.")
 	fs.Int32Var(&n.ConcurrentNodeBucketWorkers, "concurrent-node-bucket-workers", n.ConcurrentNodeBucketWorkers, "The number of nodebucket objects that are allowed to reconcile concurrently. Larger number = more responsive nodebuckets, but more CPU (and network) load")
 }
 
@@ -55,6 +57,7 @@ func (o *NodeBucketControllerOptions) ApplyTo(cfg *config.NodeBucketControllerCo
 
 	cfg.MaxNodesPerBucket = o.MaxNodesPerBucket
 	cfg.ConcurrentNodeBucketWorkers = o.ConcurrentNodeBucketWorkers
+	cfg.BucketAssignmentPolicy = o.BucketAssignmentPolicy
 
 	return nil
 }
@@ -69,5 +72,8 @@ func (o *NodeBucketControllerOptions) Validate() []error {
 	if o.MaxNodesPerBucket <= 0 {
 		errs = append(errs, fmt.Errorf("max-nodes-per-bucket(%d) is invalid, should greater than 0", o.MaxNodesPerBucket))
 	}
+	if o.BucketAssignmentPolicy != config.FillPolicy && o.BucketAssignmentPolicy != config.ConsistentHashPolicy {
+		errs = append(errs, fmt.Errorf("node-bucket-assignment-policy(%s) is invalid, should be %s or %s", o.BucketAssignmentPolicy, config.FillPolicy, config.ConsistentHashPolicy))
+	}
 	return errs
 }
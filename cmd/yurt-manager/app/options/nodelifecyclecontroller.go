@@ -25,11 +25,13 @@ import (
 	"k8s.io/kube-controller-manager/config/v1alpha1"
 
 	"github.com/openyurtio/openyurt/cmd/yurt-manager/names"
+	nodelifecycleconfig "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/nodelifecycle/config"
 )
 
 // NodeLifecycleControllerOptions holds the NodeLifecycleController options.
 type NodeLifecycleControllerOptions struct {
 	*v1alpha1.NodeLifecycleControllerConfiguration
+	PoolPartition *nodelifecycleconfig.PoolPartitionConfiguration
 }
 
 func NewNodeLifecycleControllerOptions() *NodeLifecycleControllerOptions {
@@ -39,6 +41,11 @@ func NewNodeLifecycleControllerOptions() *NodeLifecycleControllerOptions {
 			NodeMonitorGracePeriod: metav1.Duration{Duration: 40 * time.Second},
 			NodeStartupGracePeriod: metav1.Duration{Duration: 60 * time.Second},
 		},
+		PoolPartition: &nodelifecycleconfig.PoolPartitionConfiguration{
+			PoolDisconnectedThreshold:      0.8,
+			PoolHeartbeatCorrelationWindow: metav1.Duration{Duration: 30 * time.Second},
+			PoolDisconnectedEvictionRate:   0,
+		},
 	}
 }
 
@@ -58,10 +65,19 @@ func (o *NodeLifecycleControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.Float32Var(&o.SecondaryNodeEvictionRate, "secondary-node-eviction-rate", 0.01, "Number of nodes per second on which pods are deleted in case of node failure when a zone is unhealthy (see --unhealthy-zone-threshold for definition of healthy/unhealthy). Zone refers to entire cluster in non-multizone clusters. This value is implicitly overridden to 0 if the cluster size is smaller than --large-cluster-size-threshold.")
 	fs.Int32Var(&o.LargeClusterSizeThreshold, "large-cluster-size-threshold", 50, fmt.Sprintf("Number of nodes from which %s treats the cluster as large for the eviction logic purposes. --secondary-node-eviction-rate is implicitly overridden to 0 for clusters this size or smaller.", names.NodeLifeCycleController))
 	fs.Float32Var(&o.UnhealthyZoneThreshold, "unhealthy-zone-threshold", 0.55, "Fraction of Nodes in a zone which needs to be not Ready (minimum 3) for zone to be treated as unhealthy. ")
+	fs.Float32Var(&o.PoolPartition.PoolDisconnectedThreshold, "pool-disconnected-threshold", o.PoolPartition.PoolDisconnectedThreshold,
+		"Fraction of Nodes in a NodePool (minimum 2) which need to stop renewing heartbeats together for the NodePool to be treated as disconnected from cloud.")
+	fs.DurationVar(&o.PoolPartition.PoolHeartbeatCorrelationWindow.Duration, "pool-heartbeat-correlation-window", o.PoolPartition.PoolHeartbeatCorrelationWindow.Duration,
+		"Max interval between the last heartbeats of Nodes in a NodePool for them to be treated as stopping renewing heartbeats together.")
+	fs.Float32Var(&o.PoolPartition.PoolDisconnectedEvictionRate, "pool-disconnected-eviction-rate", o.PoolPartition.PoolDisconnectedEvictionRate,
+		"Number of nodes per second on which pods are deleted in a disconnected NodePool. Evictions are suppressed in disconnected NodePools if it's 0.")
 }
 
 // ApplyTo fills up NodeLifecycleController config with options.
-func (o *NodeLifecycleControllerOptions) ApplyTo(cfg *v1alpha1.NodeLifecycleControllerConfiguration) error {
+func (o *NodeLifecycleControllerOptions) ApplyTo(
+	cfg *v1alpha1.NodeLifecycleControllerConfiguration,
+	partitionCfg *nodelifecycleconfig.PoolPartitionConfiguration,
+) error {
 	if o == nil {
 		return nil
 	}
@@ -72,6 +88,7 @@ func (o *NodeLifecycleControllerOptions) ApplyTo(cfg *v1alpha1.NodeLifecycleCont
 	cfg.SecondaryNodeEvictionRate = o.SecondaryNodeEvictionRate
 	cfg.LargeClusterSizeThreshold = o.LargeClusterSizeThreshold
 	cfg.UnhealthyZoneThreshold = o.UnhealthyZoneThreshold
+	*partitionCfg = *o.PoolPartition
 
 	return nil
 }
@@ -83,5 +100,14 @@ func (o *NodeLifecycleControllerOptions) Validate() []error {
 	}
 
 	errs := []error{}
+	if o.PoolPartition.PoolDisconnectedThreshold <= 0 || o.PoolPartition.PoolDisconnectedThreshold > 1 {
+		errs = append(errs, fmt.Errorf("pool-disconnected-threshold should be in (0, 1], but got %v", o.PoolPartition.PoolDisconnectedThreshold))
+	}
+	if o.PoolPartition.PoolHeartbeatCorrelationWindow.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("pool-heartbeat-correlation-window should be positive, but got %v", o.PoolPartition.PoolHeartbeatCorrelationWindow.Duration))
+	}
+	if o.PoolPartition.PoolDisconnectedEvictionRate < 0 {
+		errs = append(errs, fmt.Errorf("pool-disconnected-eviction-rate should not be negative, but got %v", o.PoolPartition.PoolDisconnectedEvictionRate))
+	}
 	return errs
 }
@@ -41,6 +41,7 @@ func (n *PlatformAdminControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	}
 
 	fs.Int32Var(&n.ConcurrentPlatformAdminWorkers, "concurrent-platform-administrator-workers", n.ConcurrentPlatformAdminWorkers, "Max concurrent workers for PlatformAdmin controller.")
+	fs.DurationVar(&n.UpgradeStepTimeout, "platform-administrator-upgrade-step-timeout", n.UpgradeStepTimeout, "How long a step of the PlatformAdmin version upgrade may take to become ready before the components are rolled back.")
 }
 
 // ApplyTo fills up nodePool config with options.
@@ -60,6 +61,10 @@ func (o *PlatformAdminControllerOptions) Validate() []error {
 	errs := []error{}
 	if o.PlatformAdminControllerConfiguration == nil {
 		errs = append(errs, errors.New("IoTControllerConfiguration can not be empty!"))
+		return errs
+	}
+	if o.UpgradeStepTimeout <= 0 {
+		errs = append(errs, errors.New("platform-administrator-upgrade-step-timeout must be greater than 0"))
 	}
 	return errs
 }
@@ -17,11 +17,14 @@ limitations under the License.
 package upgrade
 
 import (
+	"errors"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"k8s.io/klog/v2"
 
 	upgrade "github.com/openyurtio/openyurt/pkg/node-servant/static-pod-upgrade"
+	upgradeutil "github.com/openyurtio/openyurt/pkg/node-servant/static-pod-upgrade/util"
 )
 
 // NewUpgradeCmd generates a new upgrade command
@@ -45,6 +48,16 @@ func NewUpgradeCmd() *cobra.Command {
 			}
 
 			if err = ctrl.Upgrade(); err != nil {
+				// report the outcome through the status of worker pod
+				if err := upgradeutil.WriteTerminationMessage(err.Error()); err != nil {
+					klog.Errorf("could not write termination message, %v", err)
+				}
+
+				var reverted *upgrade.RevertedError
+				if errors.As(err, &reverted) {
+					klog.Errorf("could not upgrade static pod, %v", err)
+					klog.FlushAndExit(klog.ExitFlushTimeout, upgradeutil.RevertedExitCode)
+				}
 				klog.Fatalf("could not upgrade static pod, %v", err)
 			}
 
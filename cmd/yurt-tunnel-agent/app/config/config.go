@@ -18,11 +18,13 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/openyurtio/openyurt/pkg/projectinfo"
 	"github.com/openyurtio/openyurt/pkg/yurttunnel/constants"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/session"
 )
 
 // Config is the main context object for yurttunel-agent
@@ -34,6 +36,10 @@ type Config struct {
 	AgentIdentifiers string
 	AgentMetaAddr    string
 	CertDir          string
+	SyncInterval     time.Duration
+	ProbeInterval    time.Duration
+	// SessionConfig is nil if session resumption is disabled
+	SessionConfig *session.Config
 }
 
 type completedConfig struct {
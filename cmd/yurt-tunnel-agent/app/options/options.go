@@ -22,6 +22,7 @@ import (
 	"net"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	"k8s.io/klog/v2"
@@ -33,6 +34,7 @@ import (
 	utilip "github.com/openyurtio/openyurt/pkg/util/ip"
 	"github.com/openyurtio/openyurt/pkg/yurttunnel/constants"
 	kubeutil "github.com/openyurtio/openyurt/pkg/yurttunnel/kubernetes"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/session"
 )
 
 const defaultKubeconfig = "/etc/kubernetes/kubelet.conf"
@@ -49,12 +51,26 @@ type AgentOptions struct {
 	MetaHost         string
 	MetaPort         string
 	CertDir          string
+	SyncInterval     time.Duration
+	ProbeInterval    time.Duration
+
+	SessionResumption          bool
+	SessionResumeTimeout       time.Duration
+	SessionKeepaliveInterval   time.Duration
+	SessionReconnectBackoff    time.Duration
+	SessionMaxReconnectBackoff time.Duration
 }
 
 // NewAgentOptions creates a new AgentOptions with a default config.
 func NewAgentOptions() *AgentOptions {
 	o := &AgentOptions{
-		MetaPort: constants.YurttunnelAgentMetaPort,
+		MetaPort:                   constants.YurttunnelAgentMetaPort,
+		SyncInterval:               5 * time.Second,
+		ProbeInterval:              5 * time.Second,
+		SessionResumeTimeout:       session.DefaultResumeTimeout,
+		SessionKeepaliveInterval:   session.DefaultKeepaliveInterval,
+		SessionReconnectBackoff:    session.DefaultReconnectBackoff,
+		SessionMaxReconnectBackoff: session.DefaultMaxReconnectBackoff,
 	}
 
 	return o
@@ -84,6 +100,19 @@ func (o *AgentOptions) Validate() error {
 		return errors.New("--agent-identifiers are invalid, format should be host={node-name}")
 	}
 
+	if o.SyncInterval <= 0 || o.ProbeInterval <= 0 {
+		return errors.New("--sync-interval and --probe-interval should be positive")
+	}
+
+	if o.SessionResumption {
+		if o.SessionResumeTimeout <= 0 || o.SessionKeepaliveInterval <= 0 || o.SessionReconnectBackoff <= 0 {
+			return errors.New("--session-resume-timeout, --session-keepalive-interval and --session-reconnect-backoff should be positive")
+		}
+		if o.SessionMaxReconnectBackoff < o.SessionReconnectBackoff {
+			return errors.New("--session-max-reconnect-backoff should not be less than --session-reconnect-backoff")
+		}
+	}
+
 	return nil
 }
 
@@ -99,6 +128,13 @@ func (o *AgentOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.MetaHost, "meta-host", o.MetaHost, "The ip address on which listen for --meta-port port.")
 	fs.StringVar(&o.MetaPort, "meta-port", o.MetaPort, "The port on which to serve HTTP requests like profiling, metrics")
 	fs.StringVar(&o.CertDir, "cert-dir", o.CertDir, "The directory of certificate stored at.")
+	fs.DurationVar(&o.SyncInterval, "sync-interval", o.SyncInterval, fmt.Sprintf("The interval of syncing connections with %s, it is also the initial backoff of reconnecting after a connection is closed.", projectinfo.GetServerName()))
+	fs.DurationVar(&o.ProbeInterval, "probe-interval", o.ProbeInterval, fmt.Sprintf("The interval of probing the health of connections with %s.", projectinfo.GetServerName()))
+	fs.BoolVar(&o.SessionResumption, "session-resumption", o.SessionResumption, fmt.Sprintf("Carry the connections to %s by resumable sessions, so that in-flight requests like logs and exec survive short network disconnects.", projectinfo.GetServerName()))
+	fs.DurationVar(&o.SessionResumeTimeout, "session-resume-timeout", o.SessionResumeTimeout, "How long to keep trying to resume a session after its connection is lost.")
+	fs.DurationVar(&o.SessionKeepaliveInterval, "session-keepalive-interval", o.SessionKeepaliveInterval, "The interval of keepalive frames of sessions, a connection without any frame in three intervals is considered lost.")
+	fs.DurationVar(&o.SessionReconnectBackoff, "session-reconnect-backoff", o.SessionReconnectBackoff, "The initial backoff between attempts to resume a session, it doubles after every attempt.")
+	fs.DurationVar(&o.SessionMaxReconnectBackoff, "session-max-reconnect-backoff", o.SessionMaxReconnectBackoff, "The max backoff between attempts to resume a session.")
 }
 
 // agentIdentifiersIsValid verify agent identifiers are valid or not.
@@ -136,6 +172,16 @@ func (o *AgentOptions) Config() (*config.Config, error) {
 		AgentIdentifiers: o.AgentIdentifiers,
 		AgentMetaAddr:    net.JoinHostPort(o.MetaHost, o.MetaPort),
 		CertDir:          o.CertDir,
+		SyncInterval:     o.SyncInterval,
+		ProbeInterval:    o.ProbeInterval,
+	}
+
+	if o.SessionResumption {
+		c.SessionConfig = &session.Config{
+			ResumeTimeout:     o.SessionResumeTimeout,
+			KeepaliveInterval: o.SessionKeepaliveInterval,
+			Backoff:           session.NewBackoff(o.SessionReconnectBackoff, o.SessionMaxReconnectBackoff),
+		}
 	}
 
 	if len(c.AgentIdentifiers) == 0 {
@@ -16,7 +16,10 @@ limitations under the License.
 
 package options
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestAgentIdentifiersAreValid(t *testing.T) {
 	testcases := map[string]struct {
@@ -51,3 +54,59 @@ func TestAgentIdentifiersAreValid(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateSessionOptions(t *testing.T) {
+	testcases := map[string]struct {
+		modify  func(o *AgentOptions)
+		isError bool
+	}{
+		"default options": {
+			modify:  func(o *AgentOptions) {},
+			isError: false,
+		},
+		"session resumption with default options": {
+			modify: func(o *AgentOptions) {
+				o.SessionResumption = true
+			},
+			isError: false,
+		},
+		"invalid sync interval": {
+			modify: func(o *AgentOptions) {
+				o.SyncInterval = 0
+			},
+			isError: true,
+		},
+		"invalid resume timeout": {
+			modify: func(o *AgentOptions) {
+				o.SessionResumption = true
+				o.SessionResumeTimeout = 0
+			},
+			isError: true,
+		},
+		"invalid resume timeout without session resumption": {
+			modify: func(o *AgentOptions) {
+				o.SessionResumeTimeout = 0
+			},
+			isError: false,
+		},
+		"max backoff less than backoff": {
+			modify: func(o *AgentOptions) {
+				o.SessionResumption = true
+				o.SessionReconnectBackoff = 10 * time.Second
+				o.SessionMaxReconnectBackoff = time.Second
+			},
+			isError: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		o := NewAgentOptions()
+		o.NodeName = "node-test"
+		o.NodeIP = "192.168.0.1"
+		tc.modify(o)
+		err := o.Validate()
+		if (err != nil) != tc.isError {
+			t.Errorf("%s: expect error %v, but got %v", k, tc.isError, err)
+		}
+	}
+}
@@ -130,7 +130,8 @@ func Run(cfg *config.CompletedConfig, stopCh <-chan struct{}) error {
 	}
 
 	// 4. start the yurttunnel-agent
-	ta := agent.NewTunnelAgent(tlsCfg, tunnelServerAddr, cfg.NodeName, cfg.AgentIdentifiers)
+	ta := agent.NewTunnelAgent(tlsCfg, tunnelServerAddr, cfg.NodeName, cfg.AgentIdentifiers,
+		cfg.SyncInterval, cfg.ProbeInterval, cfg.SessionConfig)
 	ta.Run(stopCh)
 
 	// 5. start meta server
@@ -20,6 +20,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"net"
+	"time"
 
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
@@ -50,6 +51,9 @@ type Config struct {
 	ServerCount                 int
 	ProxyStrategy               string
 	InterceptorServerUDSFile    string
+	GrpcKeepAliveTime           time.Duration
+	GrpcKeepAliveTimeout        time.Duration
+	SessionResumeTimeout        time.Duration
 }
 
 type completedConfig struct {
@@ -35,6 +35,7 @@ import (
 	"github.com/openyurtio/openyurt/pkg/util/iptables"
 	"github.com/openyurtio/openyurt/pkg/yurttunnel/constants"
 	kubeutil "github.com/openyurtio/openyurt/pkg/yurttunnel/kubernetes"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/session"
 )
 
 // ServerOptions has the information that required by the yurttunel-server
@@ -57,6 +58,9 @@ type ServerOptions struct {
 	MetaPort               string
 	ServerCount            int
 	ProxyStrategy          string
+	GrpcKeepAliveTime      time.Duration
+	GrpcKeepAliveTimeout   time.Duration
+	SessionResumeTimeout   time.Duration
 }
 
 // NewServerOptions creates a new ServerOptions
@@ -73,6 +77,9 @@ func NewServerOptions() *ServerOptions {
 		InsecurePort:           constants.YurttunnelServerMasterInsecurePort,
 		MetaPort:               constants.YurttunnelServerMetaPort,
 		ProxyStrategy:          string(server.ProxyStrategyDestHost),
+		GrpcKeepAliveTime:      constants.YurttunnelANPGrpcKeepAliveTimeSec * time.Second,
+		GrpcKeepAliveTimeout:   0,
+		SessionResumeTimeout:   session.DefaultResumeTimeout,
 	}
 	return o
 }
@@ -86,6 +93,17 @@ func (o *ServerOptions) Validate() error {
 	if len(o.InsecureBindAddr) == 0 {
 		o.InsecureBindAddr = utilip.MustGetLoopbackIP(utilnet.IsIPv6String(o.BindAddr))
 	}
+	if o.GrpcKeepAliveTime <= 0 || o.GrpcKeepAliveTimeout < 0 {
+		return fmt.Errorf("--grpc-keepalive-time should be positive and --grpc-keepalive-timeout should not be negative")
+	}
+	if o.SessionResumeTimeout <= 0 {
+		return fmt.Errorf("--session-resume-timeout should be positive")
+	}
+	// the keepalive pings are not answered while the session is detached,
+	// the grpc connection would be closed before the session is resumed.
+	if o.GrpcKeepAliveTimeout != 0 && o.GrpcKeepAliveTimeout < o.SessionResumeTimeout {
+		return fmt.Errorf("--grpc-keepalive-timeout %v should not be less than --session-resume-timeout %v", o.GrpcKeepAliveTimeout, o.SessionResumeTimeout)
+	}
 	return nil
 }
 
@@ -109,6 +127,18 @@ func (o *ServerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.SecurePort, "secure-port", o.SecurePort, "The port on which to serve HTTPS requests from cloud clients like prometheus")
 	fs.StringVar(&o.InsecurePort, "insecure-port", o.InsecurePort, "The port on which to serve HTTP requests from cloud clients like metrics-server")
 	fs.StringVar(&o.MetaPort, "meta-port", o.MetaPort, "The port on which to serve HTTP requests like profiling, metrics")
+	fs.DurationVar(&o.GrpcKeepAliveTime, "grpc-keepalive-time", o.GrpcKeepAliveTime, "Ping the tunnel agent if the grpc connection is idle for this duration.")
+	fs.DurationVar(&o.GrpcKeepAliveTimeout, "grpc-keepalive-timeout", o.GrpcKeepAliveTimeout, "Close the grpc connection if the tunnel agent doesn't answer the ping within this duration. It should not be less than --session-resume-timeout, so that the sessions of agents survive the disconnects, 0 means the larger one of --session-resume-timeout and 5s.")
+	fs.DurationVar(&o.SessionResumeTimeout, "session-resume-timeout", o.SessionResumeTimeout, "How long to keep the session of a tunnel agent after its connection is lost. The sessions are kept by each server instance, so the agents should always be routed to the same instance when --server-count is larger than 1.")
+}
+
+// grpcKeepAliveTimeout returns the grpc keepalive timeout, it's derived from
+// the session resume timeout if it's not specified.
+func (o *ServerOptions) grpcKeepAliveTimeout() time.Duration {
+	if o.GrpcKeepAliveTimeout != 0 {
+		return o.GrpcKeepAliveTimeout
+	}
+	return max(o.SessionResumeTimeout, constants.YurttunnelANPGrpcKeepAliveTimeoutSec*time.Second)
 }
 
 func (o *ServerOptions) Config() (*config.Config, error) {
@@ -124,6 +154,9 @@ func (o *ServerOptions) Config() (*config.Config, error) {
 		CertDir:               o.CertDir,
 		ServerCount:           o.ServerCount,
 		ProxyStrategy:         o.ProxyStrategy,
+		GrpcKeepAliveTime:     o.GrpcKeepAliveTime,
+		GrpcKeepAliveTimeout:  o.grpcKeepAliveTimeout(),
+		SessionResumeTimeout:  o.SessionResumeTimeout,
 	}
 
 	if o.CertDNSNames != "" {
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/keepalive"
 	certificatesv1 "k8s.io/api/certificates/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -198,7 +199,12 @@ func Run(cfg *config.CompletedConfig, stopCh <-chan struct{}) error {
 		tlsCfg,
 		proxyClientTlsCfg,
 		wrappers,
-		cfg.ProxyStrategy)
+		cfg.ProxyStrategy,
+		keepalive.ServerParameters{
+			Time:    cfg.GrpcKeepAliveTime,
+			Timeout: cfg.GrpcKeepAliveTimeout,
+		},
+		cfg.SessionResumeTimeout)
 	if err := ts.Run(); err != nil {
 		return err
 	}
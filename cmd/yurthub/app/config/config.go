@@ -84,6 +84,7 @@ type YurtHubConfiguration struct {
 	FilterFinder                    filter.FilterFinder
 	MinRequestTimeout               time.Duration
 	NetworkMgr                      *network.NetworkManager
+	VIPMgr                          *network.VIPManager
 	CertManager                     certificate.YurtCertificateManager
 	YurtHubServerServing            *apiserver.DeprecatedInsecureServingInfo
 	YurtHubProxyServerServing       *apiserver.DeprecatedInsecureServingInfo
@@ -214,6 +215,7 @@ func Complete(options *options.YurtHubOptions, stopCh <-chan struct{}) (*YurtHub
 		// - filter finder: filter response from kube-apiserver according to request.
 		// - multiplexer: aggregating requests for pool scope metadata in order to reduce overhead of cloud kube-apiserver
 		// - network manager: ensuring a dummy interface in order to serve tls requests on the node.
+		// - vip manager: configuring and announcing virtual ips of PoolServices elected to the node.
 		// - others: prepare server servings.
 		configManager := configuration.NewConfigurationManager(options.NodeName, sharedFactory)
 		filterFinder, err := manager.NewFilterManager(
@@ -242,6 +244,15 @@ func Complete(options *options.YurtHubOptions, stopCh <-chan struct{}) (*YurtHub
 			cfg.NetworkMgr = networkMgr
 		}
 
+		if options.EnableVIPAnnouncer {
+			klog.V(2).Infof("create vip manager with interface %s", options.VIPIfName)
+			vipMgr, err := network.NewVIPManager(options, dynamicSharedFactory)
+			if err != nil {
+				return nil, fmt.Errorf("could not create vip manager, %w", err)
+			}
+			cfg.VIPMgr = vipMgr
+		}
+
 		if err = prepareServerServing(options, certMgr, cfg); err != nil {
 			return nil, err
 		}
@@ -338,7 +349,7 @@ func createClientAndSharedInformerFactories(
 	return client, informers.NewSharedInformerFactory(client, 24*time.Hour), dynamicInformerFactory, nil
 }
 
-// registerInformers reconstruct configmap/secret/pod/service informers on cloud and edge working mode.
+// registerInformers reconstruct configmap/secret/pod/node/service informers on cloud and edge working mode.
 func registerInformers(
 	informerFactory informers.SharedInformerFactory,
 	namespace string,
@@ -387,6 +398,17 @@ func registerInformers(
 		informerFactory.InformerFor(&corev1.Pod{}, newPodInformer)
 	}
 
+	// node informer is used for list/watching the node of yurthub, and used by serviceTopology Filter to resolve the zone of node.
+	newNodeInformer := func(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		listOptions := func(ops *metav1.ListOptions) {
+			ops.FieldSelector = fields.Set{"metadata.name": nodeName}.String()
+		}
+		informer := coreinformers.NewFilteredNodeInformer(client, resyncPeriod, nil, listOptions)
+		informer.SetTransform(pkgutil.TransformStripManagedFields())
+		return informer
+	}
+	informerFactory.InformerFor(&corev1.Node{}, newNodeInformer)
+
 	// service informer is used for list/watch all services in the cluster, and used by serviceTopology Filter on cloud and edge mode.
 	newServiceInformer := func(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
 		informer := coreinformers.NewFilteredServiceInformer(client, "", resyncPeriod, nil, nil)
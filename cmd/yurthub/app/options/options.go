@@ -72,6 +72,9 @@ type YurtHubOptions struct {
 	EnableIptables            bool
 	HubAgentDummyIfIP         string
 	HubAgentDummyIfName       string
+	EnableVIPAnnouncer        bool
+	VIPIfName                 string
+	VIPAnnounceIfName         string
 	HostControlPlaneAddr      string
 	DiskCachePath             string
 	EnableResourceFilter      bool
@@ -113,6 +116,8 @@ func NewYurtHubOptions() *YurtHubOptions {
 		EnableDummyIf:             true,
 		EnableIptables:            false,
 		HubAgentDummyIfName:       fmt.Sprintf("%s-dummy0", projectinfo.GetHubName()),
+		EnableVIPAnnouncer:        false,
+		VIPIfName:                 fmt.Sprintf("%s-vip0", projectinfo.GetHubName()),
 		DiskCachePath:             disk.CacheBaseDir,
 		EnableResourceFilter:      true,
 		DisabledResourceFilters:   make([]string, 0),
@@ -169,6 +174,10 @@ func (options *YurtHubOptions) Validate() error {
 			return fmt.Errorf("dummy name %s length should not be more than 15", options.HubAgentDummyIfName)
 		}
 
+		if options.EnableVIPAnnouncer && len(options.VIPIfName) > 15 {
+			return fmt.Errorf("vip interface name %s length should not be more than 15", options.VIPIfName)
+		}
+
 		if len(options.CACertHashes) == 0 && !options.UnsafeSkipCAVerification {
 			return fmt.Errorf("set --discovery-token-unsafe-skip-ca-verification flag as true or pass CACertHashes to continue")
 		}
@@ -223,6 +232,9 @@ func (o *YurtHubOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.MarkDeprecated("enable-iptables", "It is planned to be removed from OpenYurt in the future version")
 	fs.StringVar(&o.HubAgentDummyIfIP, "dummy-if-ip", o.HubAgentDummyIfIP, "the ip address of dummy interface that used for container connect hub agent(exclusive ips: 169.254.31.0/24, 169.254.1.1/32)")
 	fs.StringVar(&o.HubAgentDummyIfName, "dummy-if-name", o.HubAgentDummyIfName, "the name of dummy interface that is used for hub agent")
+	fs.BoolVar(&o.EnableVIPAnnouncer, "enable-vip-announcer", o.EnableVIPAnnouncer, "enable to configure and announce virtual ips of PoolServices which are elected to this node by yurt-manager")
+	fs.StringVar(&o.VIPIfName, "vip-if-name", o.VIPIfName, "the name of dummy interface that virtual ips of PoolServices are configured on")
+	fs.StringVar(&o.VIPAnnounceIfName, "vip-announce-if-name", o.VIPAnnounceIfName, "the name of interface that gratuitous ARP for virtual ips are sent on, if unset, the interface of node ip will be used.")
 	fs.StringVar(&o.DiskCachePath, "disk-cache-path", o.DiskCachePath, "the path for kubernetes to storage metadata")
 	fs.BoolVar(&o.EnableResourceFilter, "enable-resource-filter", o.EnableResourceFilter, "enable to filter response that comes back from reverse proxy")
 	fs.StringSliceVar(&o.DisabledResourceFilters, "disabled-resource-filters", o.DisabledResourceFilters, "disable resource filters to handle response")
@@ -54,6 +54,7 @@ func TestNewYurtHubOptions(t *testing.T) {
 		EnableDummyIf:             true,
 		EnableIptables:            false,
 		HubAgentDummyIfName:       fmt.Sprintf("%s-dummy0", projectinfo.GetHubName()),
+		VIPIfName:                 fmt.Sprintf("%s-vip0", projectinfo.GetHubName()),
 		DiskCachePath:             disk.CacheBaseDir,
 		EnableResourceFilter:      true,
 		DisabledResourceFilters:   make([]string, 0),
@@ -163,6 +163,12 @@ func Run(ctx context.Context, cfg *config.YurtHubConfiguration) error {
 			trace++
 		}
 
+		if cfg.VIPMgr != nil {
+			klog.Infof("%d. start vip manager for announcing virtual ips of pool services", trace)
+			cfg.VIPMgr.Run(ctx.Done())
+			trace++
+		}
+
 		// Start the informer factory if all informers have been registered
 		cfg.SharedFactory.Start(ctx.Done())
 		cfg.DynamicSharedFactory.Start(ctx.Done())
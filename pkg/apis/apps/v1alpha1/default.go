@@ -207,6 +207,21 @@ func SetDefaultsYurtStaticSet(obj *YurtStaticSet) {
 		strategy.MaxUnavailable = &v
 	}
 
+	if hc := strategy.HealthCheck; hc != nil {
+		if hc.TimeoutSeconds == 0 {
+			hc.TimeoutSeconds = 1
+		}
+		if hc.PeriodSeconds == 0 {
+			hc.PeriodSeconds = 5
+		}
+		if hc.SuccessThreshold == 0 {
+			hc.SuccessThreshold = 3
+		}
+		if hc.DeadlineSeconds == 0 {
+			hc.DeadlineSeconds = 300
+		}
+	}
+
 	// Set default RevisionHistoryLimit to 10
 	if obj.Spec.RevisionHistoryLimit == nil {
 		obj.Spec.RevisionHistoryLimit = new(int32)
@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PoolResourceQuotaSpec defines the hard limits of resources requested by pods of a namespace in a NodePool
+type PoolResourceQuotaSpec struct {
+	// NodePool is the name of NodePool in which the quota is enforced.
+	NodePool string `json:"nodePool"`
+
+	// Hard is the set of hard limits for each resource, only cpu, memory and pods are supported.
+	// +optional
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+}
+
+// PoolResourceQuotaStatus defines the observed usage of PoolResourceQuota
+type PoolResourceQuotaStatus struct {
+	// Used is the total requests of non-terminated pods of the namespace running on nodes in the NodePool.
+	// +optional
+	Used corev1.ResourceList `json:"used,omitempty"`
+}
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,path=poolresourcequotas,shortName=prq,categories=yurt
+// +kubebuilder:printcolumn:name="NODEPOOL",type="string",JSONPath=".spec.nodePool",description="The NodePool in which the quota is enforced."
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp",description="CreationTimestamp is a timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+
+// PoolResourceQuota limits the resources requested by pods of a namespace in a NodePool
+type PoolResourceQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PoolResourceQuotaSpec   `json:"spec,omitempty"`
+	Status PoolResourceQuotaStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PoolResourceQuotaList contains a list of PoolResourceQuota
+type PoolResourceQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PoolResourceQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PoolResourceQuota{}, &PoolResourceQuotaList{})
+}
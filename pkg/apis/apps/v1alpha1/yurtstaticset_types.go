@@ -30,6 +30,69 @@ type YurtStaticSetUpgradeStrategy struct {
 	// AdvancedRollingUpdate upgrade config params. Present only if type = "AdvancedRollingUpdate".
 	//+optional
 	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// Partition indicates the number of nodes that should be kept on the old revision.
+	// Nodes are upgraded in order (canary nodes first, then by node name) until only
+	// Partition nodes are left. Present only if type = "AdvancedRollingUpdate". Defaults to 0.
+	//+optional
+	Partition *int32 `json:"partition,omitempty"`
+
+	// Canary upgrades a selected set of nodes first, and pauses the rollout until it is
+	// approved or the health gate passes. Present only if type = "AdvancedRollingUpdate".
+	//+optional
+	Canary *YurtStaticSetCanaryStrategy `json:"canary,omitempty"`
+
+	// HealthCheck is performed against the new static pod after it is running on the node.
+	// If the check does not pass before the deadline, the previous manifest is restored.
+	// In OTA mode, the check is performed by YurtHub and exec is not supported.
+	//+optional
+	HealthCheck *YurtStaticSetHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// YurtStaticSetHealthCheck defines how the upgrader checks the new static pod.
+type YurtStaticSetHealthCheck struct {
+	// The action taken to check the new static pod, only exec, httpGet and tcpSocket are supported.
+	// The exec command runs in the first container of the new static pod, httpGet and tcpSocket
+	// connect to the IP of the new static pod if host is not specified.
+	corev1.ProbeHandler `json:",inline"`
+
+	// Number of seconds after which each check times out. Defaults to 1 second.
+	//+optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// How often (in seconds) to perform the check. Defaults to 5 seconds.
+	//+optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+	// Minimum consecutive successes for the check to be considered passed. Defaults to 3.
+	//+optional
+	SuccessThreshold int32 `json:"successThreshold,omitempty"`
+
+	// Number of seconds for the new static pod to pass the check after the manifest is replaced,
+	// otherwise the previous manifest is restored. Defaults to 300 seconds.
+	//+optional
+	DeadlineSeconds int32 `json:"deadlineSeconds,omitempty"`
+}
+
+// YurtStaticSetCanaryStrategy defines the canary nodes and how the rollout continues after them.
+type YurtStaticSetCanaryStrategy struct {
+	// NodeSelector selects canary nodes by node labels.
+	//+optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// NodePools selects canary nodes by the NodePool they belong to.
+	//+optional
+	NodePools []string `json:"nodePools,omitempty"`
+
+	// ApprovedRevision approves the rollout of the given revision to the non-canary nodes.
+	// It should be set to status.updateRevision once the canary nodes are verified.
+	//+optional
+	ApprovedRevision string `json:"approvedRevision,omitempty"`
+
+	// AutoPromoteSeconds is the health gate of the canary nodes. If set, the rollout continues
+	// automatically after all canary static pods are upgraded and have been ready for AutoPromoteSeconds.
+	//+optional
+	AutoPromoteSeconds *int32 `json:"autoPromoteSeconds,omitempty"`
 }
 
 // YurtStaticSetUpgradeStrategyType is a strategy according to which static pods gets upgraded.
@@ -75,6 +138,69 @@ type YurtStaticSetStatus struct {
 	// The most recent generation observed by the static pod controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// The hash of the latest static pod template.
+	// +optional
+	UpdateRevision string `json:"updateRevision,omitempty"`
+
+	// The number of canary nodes that are running the static pod.
+	// +optional
+	CanaryNumber int32 `json:"canaryNumber,omitempty"`
+
+	// Paused indicates that the canary nodes have been upgraded and the rollout is waiting
+	// for approval or the health gate before upgrading the other nodes.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// The upgrade progress on each node that is running the static pod.
+	// +optional
+	NodeStatuses []YurtStaticSetNodeStatus `json:"nodeStatuses,omitempty"`
+}
+
+// YurtStaticSetNodeUpgradePhase is the upgrade phase of the static pod on a node.
+type YurtStaticSetNodeUpgradePhase string
+
+const (
+	// NodeUpgradePending means the static pod on the node is waiting to be upgraded.
+	NodeUpgradePending YurtStaticSetNodeUpgradePhase = "Pending"
+	// NodeUpgradeUpgrading means the upgrade worker is running on the node.
+	NodeUpgradeUpgrading YurtStaticSetNodeUpgradePhase = "Upgrading"
+	// NodeUpgradeUpgraded means the node is running the latest static pod.
+	NodeUpgradeUpgraded YurtStaticSetNodeUpgradePhase = "Upgraded"
+	// NodeUpgradeFailed means the upgrade worker on the node failed.
+	NodeUpgradeFailed YurtStaticSetNodeUpgradePhase = "Failed"
+	// NodeUpgradeReverted means the new static pod failed the health check on the node,
+	// and the previous manifest has been restored by the upgrade worker.
+	NodeUpgradeReverted YurtStaticSetNodeUpgradePhase = "Reverted"
+)
+
+// YurtStaticSetNodeStatus describes the upgrade progress of the static pod on a node.
+type YurtStaticSetNodeStatus struct {
+	// NodeName is the name of the node.
+	NodeName string `json:"nodeName"`
+
+	// Canary indicates whether the node is a canary node.
+	// +optional
+	Canary bool `json:"canary,omitempty"`
+
+	// Revision is the hash of the static pod running on the node.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// Ready indicates whether the static pod on the node is ready.
+	Ready bool `json:"ready"`
+
+	// Phase is the upgrade phase of the static pod on the node.
+	// +optional
+	Phase YurtStaticSetNodeUpgradePhase `json:"phase,omitempty"`
+
+	// WorkerPod is the name of the latest upgrade worker pod on the node.
+	// +optional
+	WorkerPod string `json:"workerPod,omitempty"`
+
+	// Message is a human readable message about the outcome of the upgrade worker.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +genclient
@@ -86,6 +212,7 @@ type YurtStaticSetStatus struct {
 //+kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.totalNumber",description="The total number of static pods"
 //+kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyNumber",description="The number of ready static pods"
 //+kubebuilder:printcolumn:name="Upgraded",type="integer",JSONPath=".status.upgradedNumber",description="The number of static pods that have been upgraded"
+//+kubebuilder:printcolumn:name="Paused",type="boolean",JSONPath=".status.paused",description="Whether the rollout is paused after the canary nodes"
 
 // YurtStaticSet is the Schema for the yurtstaticsets API
 type YurtStaticSet struct {
@@ -274,6 +274,109 @@ func (in *Pool) DeepCopy() *Pool {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolResourceQuota) DeepCopyInto(out *PoolResourceQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolResourceQuota.
+func (in *PoolResourceQuota) DeepCopy() *PoolResourceQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolResourceQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PoolResourceQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolResourceQuotaList) DeepCopyInto(out *PoolResourceQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PoolResourceQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolResourceQuotaList.
+func (in *PoolResourceQuotaList) DeepCopy() *PoolResourceQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolResourceQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PoolResourceQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolResourceQuotaSpec) DeepCopyInto(out *PoolResourceQuotaSpec) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolResourceQuotaSpec.
+func (in *PoolResourceQuotaSpec) DeepCopy() *PoolResourceQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolResourceQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolResourceQuotaStatus) DeepCopyInto(out *PoolResourceQuotaStatus) {
+	*out = *in
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolResourceQuotaStatus.
+func (in *PoolResourceQuotaStatus) DeepCopy() *PoolResourceQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolResourceQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatefulSetTemplateSpec) DeepCopyInto(out *StatefulSetTemplateSpec) {
 	*out = *in
@@ -500,7 +603,7 @@ func (in *YurtStaticSet) DeepCopyInto(out *YurtStaticSet) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YurtStaticSet.
@@ -521,6 +624,52 @@ func (in *YurtStaticSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *YurtStaticSetCanaryStrategy) DeepCopyInto(out *YurtStaticSetCanaryStrategy) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutoPromoteSeconds != nil {
+		in, out := &in.AutoPromoteSeconds, &out.AutoPromoteSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YurtStaticSetCanaryStrategy.
+func (in *YurtStaticSetCanaryStrategy) DeepCopy() *YurtStaticSetCanaryStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(YurtStaticSetCanaryStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *YurtStaticSetHealthCheck) DeepCopyInto(out *YurtStaticSetHealthCheck) {
+	*out = *in
+	in.ProbeHandler.DeepCopyInto(&out.ProbeHandler)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YurtStaticSetHealthCheck.
+func (in *YurtStaticSetHealthCheck) DeepCopy() *YurtStaticSetHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(YurtStaticSetHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *YurtStaticSetList) DeepCopyInto(out *YurtStaticSetList) {
 	*out = *in
@@ -553,6 +702,21 @@ func (in *YurtStaticSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *YurtStaticSetNodeStatus) DeepCopyInto(out *YurtStaticSetNodeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YurtStaticSetNodeStatus.
+func (in *YurtStaticSetNodeStatus) DeepCopy() *YurtStaticSetNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(YurtStaticSetNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *YurtStaticSetSpec) DeepCopyInto(out *YurtStaticSetSpec) {
 	*out = *in
@@ -578,6 +742,11 @@ func (in *YurtStaticSetSpec) DeepCopy() *YurtStaticSetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *YurtStaticSetStatus) DeepCopyInto(out *YurtStaticSetStatus) {
 	*out = *in
+	if in.NodeStatuses != nil {
+		in, out := &in.NodeStatuses, &out.NodeStatuses
+		*out = make([]YurtStaticSetNodeStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YurtStaticSetStatus.
@@ -598,6 +767,21 @@ func (in *YurtStaticSetUpgradeStrategy) DeepCopyInto(out *YurtStaticSetUpgradeSt
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(YurtStaticSetCanaryStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(YurtStaticSetHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new YurtStaticSetUpgradeStrategy.
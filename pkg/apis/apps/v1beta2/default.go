@@ -27,3 +27,15 @@ func SetDefaultsNodePool(obj *NodePool) {
 		obj.Spec.LeaderReplicas = 1
 	}
 }
+
+// DefaultLeaderElectionScoring returns the scoring used by weighted leader election
+// when LeaderElectionScoring is not specified.
+func DefaultLeaderElectionScoring() *LeaderElectionScoring {
+	return &LeaderElectionScoring{
+		CPUWeight:         10,
+		MemoryWeight:      2,
+		ReadinessWeight:   20,
+		LatencyWeight:     10,
+		StickinessPercent: 20,
+	}
+}
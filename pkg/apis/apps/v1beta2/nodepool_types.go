@@ -30,12 +30,21 @@ const (
 	Edge  NodePoolType = "Edge"
 	Cloud NodePoolType = "Cloud"
 
-	ElectionStrategyMark   LeaderElectionStrategy = "mark"
-	ElectionStrategyRandom LeaderElectionStrategy = "random"
+	ElectionStrategyMark     LeaderElectionStrategy = "mark"
+	ElectionStrategyRandom   LeaderElectionStrategy = "random"
+	ElectionStrategyWeighted LeaderElectionStrategy = "weighted"
 
 	// LeaderStatus means the status of leader yurthub election.
 	// If it's ready the leader elected, otherwise no leader is elected.
 	LeaderStatus NodePoolConditionType = "LeaderReady"
+
+	// LeaderElectionScored records the scores of candidates and the reasoning of the latest
+	// election when LeaderElectionStrategy is weighted.
+	LeaderElectionScored NodePoolConditionType = "LeaderElectionScored"
+
+	// PoolDisconnected means that most nodes in the NodePool stop renewing heartbeats together,
+	// which is regarded as the whole site loses the connection to cloud rather than node failures.
+	PoolDisconnected NodePoolConditionType = "PoolDisconnected"
 )
 
 // NodePoolSpec defines the desired state of NodePool
@@ -74,9 +83,15 @@ type NodePoolSpec struct {
 	// LeaderElectionStrategy represents the policy how to elect a leader Yurthub in a nodepool.
 	// random: select one ready node as leader at random.
 	// mark: select one ready node as leader from nodes that are specified by labelselector.
+	// weighted: select ready nodes with the highest scores as leaders, the scores are calculated by LeaderElectionScoring.
 	// More strategies will be supported according to user's new requirements.
 	LeaderElectionStrategy string `json:"leaderElectionStrategy,omitempty"`
 
+	// LeaderElectionScoring is used only when LeaderElectionStrategy is weighted. It specifies how candidate
+	// nodes are scored, and how leaders are spread across failure domains.
+	// +optional
+	LeaderElectionScoring *LeaderElectionScoring `json:"leaderElectionScoring,omitempty"`
+
 	// LeaderNodeLabelSelector is used only when LeaderElectionStrategy is mark. leader Yurhub will be
 	// elected from nodes that filtered by this label selector.
 	LeaderNodeLabelSelector map[string]string `json:"leaderNodeLabelSelector,omitempty"`
@@ -96,6 +111,81 @@ type NodePoolSpec struct {
 	// If the field is not specified, the default value is 1.
 	// + optional
 	LeaderReplicas int32 `json:"leaderReplicas,omitempty"`
+
+	// NodeSelector selects the nodes which belong to this NodePool. If specified, nodes that match the selector
+	// are added into the NodePool automatically, and nodes of this NodePool that don't match the selector anymore
+	// are moved to another NodePool whose selector matches them. A node which matches the selectors of
+	// several NodePools is not assigned until the conflict is resolved.
+	// +optional
+	NodeSelector *NodePoolNodeSelector `json:"nodeSelector,omitempty"`
+
+	// Parent is the name of parent NodePool, it's used for organizing NodePools as a tree,
+	// e.g. a region NodePool contains several site NodePools. NodePools and nodes are labeled with
+	// ancestor.nodepool.openyurt.io/{ancestor}=true for each ancestor NodePool, so they can be selected by ancestors.
+	// +optional
+	Parent string `json:"parent,omitempty"`
+}
+
+// NodePoolNodeSelector selects nodes by their labels and InternalIP addresses.
+// A node is selected only if it matches all of the specified requirements.
+type NodePoolNodeSelector struct {
+	// LabelSelector selects nodes by labels, topology labels like topology.kubernetes.io/zone can be used
+	// to select all nodes in a failure domain.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// InternalIPCIDRs selects nodes whose InternalIP address is in one of the CIDRs.
+	// +optional
+	InternalIPCIDRs []string `json:"internalIPCIDRs,omitempty"`
+}
+
+// LeaderElectionScoring defines how candidate nodes are scored in weighted leader election.
+// The score of a node is the sum of:
+// CPUWeight * allocatable cpu cores + MemoryWeight * allocatable memory in GiB
+// + weights of matched LabelWeights
+// + ReadinessWeight * min(1, ready duration / 24h)
+// - LatencyWeight * cloud latency reported by yurthub in 100ms
+type LeaderElectionScoring struct {
+	// CPUWeight is the weight of each allocatable cpu core.
+	// +optional
+	CPUWeight int32 `json:"cpuWeight,omitempty"`
+
+	// MemoryWeight is the weight of each GiB of allocatable memory.
+	// +optional
+	MemoryWeight int32 `json:"memoryWeight,omitempty"`
+
+	// LabelWeights add weights to nodes which have the specified labels.
+	// +optional
+	LabelWeights []LabelWeight `json:"labelWeights,omitempty"`
+
+	// ReadinessWeight is the weight of nodes which have been ready for at least 24 hours,
+	// nodes which become ready recently get the proportional weight.
+	// +optional
+	ReadinessWeight int32 `json:"readinessWeight,omitempty"`
+
+	// LatencyWeight is the penalty of each 100ms network latency between the node and cloud,
+	// the latency is reported by yurthub in the annotation of node lease.
+	// +optional
+	LatencyWeight int32 `json:"latencyWeight,omitempty"`
+
+	// TopologyKey is the node label key of failure domains, e.g. racks. Leaders are spread across
+	// failure domains as much as possible. If it's not specified, leaders are not spread.
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// StickinessPercent is the score bonus in percent for current leaders, which avoids leader
+	// churn when the scores of candidates are close. A current leader is only replaced by a
+	// candidate which scores higher by this percent.
+	// +optional
+	StickinessPercent int32 `json:"stickinessPercent,omitempty"`
+}
+
+// LabelWeight adds Weight to nodes which have the label Key=Value.
+// If Value is empty, all nodes which have the label Key match.
+type LabelWeight struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Weight int32  `json:"weight"`
 }
 
 // NodePoolStatus defines the observed state of NodePool
@@ -126,6 +216,41 @@ type NodePoolStatus struct {
 	// current state that includes LeaderHubElection status.
 	// +optional
 	Conditions []NodePoolCondition `json:"conditions,omitempty"`
+
+	// ResourceSummary is the aggregated cpu, memory and pods of nodes and pods in the pool.
+	// +optional
+	ResourceSummary *NodePoolResourceSummary `json:"resourceSummary,omitempty"`
+
+	// Descendants is the aggregated status of descendant NodePools, it's only set when the pool has child NodePools.
+	// +optional
+	Descendants *NodePoolDescendantsStatus `json:"descendants,omitempty"`
+}
+
+// NodePoolDescendantsStatus represents the aggregated status of descendant NodePools
+type NodePoolDescendantsStatus struct {
+	// ChildPools is the names of NodePools whose parent is the pool.
+	// +optional
+	ChildPools []string `json:"childPools,omitempty"`
+
+	// Total number of ready nodes in all descendant NodePools.
+	// +optional
+	ReadyNodeNum int32 `json:"readyNodeNum"`
+
+	// Total number of unready nodes in all descendant NodePools.
+	// +optional
+	UnreadyNodeNum int32 `json:"unreadyNodeNum"`
+}
+
+// NodePoolResourceSummary represents the aggregated resources of a NodePool
+type NodePoolResourceSummary struct {
+	// Allocatable is the sum of allocatable resources of nodes in the pool.
+	// +optional
+	Allocatable v1.ResourceList `json:"allocatable,omitempty"`
+
+	// Requested is the sum of resource requests of non-terminated pods running on nodes in the pool,
+	// and the pods resource is the number of these pods.
+	// +optional
+	Requested v1.ResourceList `json:"requested,omitempty"`
 }
 
 // Leader represents the hub leader in a nodepool
@@ -26,6 +26,21 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelWeight) DeepCopyInto(out *LabelWeight) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelWeight.
+func (in *LabelWeight) DeepCopy() *LabelWeight {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelWeight)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Leader) DeepCopyInto(out *Leader) {
 	*out = *in
@@ -41,6 +56,26 @@ func (in *Leader) DeepCopy() *Leader {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderElectionScoring) DeepCopyInto(out *LeaderElectionScoring) {
+	*out = *in
+	if in.LabelWeights != nil {
+		in, out := &in.LabelWeights, &out.LabelWeights
+		*out = make([]LabelWeight, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElectionScoring.
+func (in *LeaderElectionScoring) DeepCopy() *LeaderElectionScoring {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderElectionScoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePool) DeepCopyInto(out *NodePool) {
 	*out = *in
@@ -84,6 +119,26 @@ func (in *NodePoolCondition) DeepCopy() *NodePoolCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolDescendantsStatus) DeepCopyInto(out *NodePoolDescendantsStatus) {
+	*out = *in
+	if in.ChildPools != nil {
+		in, out := &in.ChildPools, &out.ChildPools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolDescendantsStatus.
+func (in *NodePoolDescendantsStatus) DeepCopy() *NodePoolDescendantsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolDescendantsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePoolList) DeepCopyInto(out *NodePoolList) {
 	*out = *in
@@ -116,6 +171,60 @@ func (in *NodePoolList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolNodeSelector) DeepCopyInto(out *NodePoolNodeSelector) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InternalIPCIDRs != nil {
+		in, out := &in.InternalIPCIDRs, &out.InternalIPCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolNodeSelector.
+func (in *NodePoolNodeSelector) DeepCopy() *NodePoolNodeSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolNodeSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolResourceSummary) DeepCopyInto(out *NodePoolResourceSummary) {
+	*out = *in
+	if in.Allocatable != nil {
+		in, out := &in.Allocatable, &out.Allocatable
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Requested != nil {
+		in, out := &in.Requested, &out.Requested
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolResourceSummary.
+func (in *NodePoolResourceSummary) DeepCopy() *NodePoolResourceSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolResourceSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePoolSpec) DeepCopyInto(out *NodePoolSpec) {
 	*out = *in
@@ -140,6 +249,11 @@ func (in *NodePoolSpec) DeepCopyInto(out *NodePoolSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LeaderElectionScoring != nil {
+		in, out := &in.LeaderElectionScoring, &out.LeaderElectionScoring
+		*out = new(LeaderElectionScoring)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.LeaderNodeLabelSelector != nil {
 		in, out := &in.LeaderNodeLabelSelector, &out.LeaderNodeLabelSelector
 		*out = make(map[string]string, len(*in))
@@ -152,6 +266,11 @@ func (in *NodePoolSpec) DeepCopyInto(out *NodePoolSpec) {
 		*out = make([]metav1.GroupVersionResource, len(*in))
 		copy(*out, *in)
 	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(NodePoolNodeSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolSpec.
@@ -185,6 +304,16 @@ func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ResourceSummary != nil {
+		in, out := &in.ResourceSummary, &out.ResourceSummary
+		*out = new(NodePoolResourceSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Descendants != nil {
+		in, out := &in.Descendants, &out.Descendants
+		*out = new(NodePoolDescendantsStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolStatus.
@@ -35,6 +35,10 @@ const (
 	AnnotationPatchKey = "apps.openyurt.io/patch"
 
 	AnnotationRefNodePool = "apps.openyurt.io/ref-nodepool"
+
+	// AnnotationMaintenanceWindow declares the maintenance window in which pods are allowed to be upgraded,
+	// it can be added to NodePool, DaemonSet and YurtStaticSet.
+	AnnotationMaintenanceWindow = "apps.openyurt.io/maintenance-window"
 )
 
 // NodePool related labels and annotations
@@ -44,10 +48,65 @@ const (
 	NodePoolHostNetworkLabel = "nodepool.openyurt.io/hostnetwork"
 	NodePoolChangedEvent     = "NodePoolChanged"
 	NodePoolTypeLabel        = "nodepool.openyurt.io/type"
+
+	// NodePoolAncestorLabelPrefix is the prefix of labels which are added to NodePools and nodes for every
+	// ancestor NodePool, e.g. ancestor.nodepool.openyurt.io/region-east=true, so NodePools and nodes in
+	// a region can be selected by label selectors.
+	NodePoolAncestorLabelPrefix = "ancestor.nodepool.openyurt.io"
+
+	// NodePoolReassignedEvent is emitted when a node is assigned to a NodePool by the nodeSelector of NodePool.
+	NodePoolReassignedEvent = "NodePoolReassigned"
+	// NodePoolConflictEvent is emitted when a node matches the nodeSelector of several NodePools.
+	NodePoolConflictEvent = "NodePoolConflict"
+	// AnnotationNodePoolConflict is added to node by yurt-manager when the node can not be assigned to a NodePool
+	// by nodeSelectors, it records the conflict so that NodePoolConflictEvent is emitted only when the conflict
+	// first appears, and it's removed when the conflict is resolved.
+	AnnotationNodePoolConflict = "nodepool.openyurt.io/conflict"
+
+	// AnnotationCloudLatency is added to node lease by yurthub, it records the latency in milliseconds of
+	// the latest node lease renewal, and is used by weighted hub leader election.
+	AnnotationCloudLatency = "nodepool.openyurt.io/cloud-latency-ms"
+)
+
+// NodeBucket related labels and annotations
+const (
+	// AnnotationNodeBucketMaxNodes can be added to NodePool to override the maximum number of nodes per NodeBucket
+	// of yurt-manager for the NodePool.
+	AnnotationNodeBucketMaxNodes = "nodebucket.openyurt.io/max-nodes-per-bucket"
+
+	// AnnotationNodeBucketShardLabelKey can be added to NodePool to shard nodes into NodeBuckets by the value of
+	// the specified node label, e.g. topology.kubernetes.io/zone, NodeBuckets of a shard are labeled with
+	// nodebucket.openyurt.io/shard=<label value>, so consumers are able to watch the NodeBuckets they care about.
+	AnnotationNodeBucketShardLabelKey = "nodebucket.openyurt.io/shard-label-key"
+
+	// NodeBucketShardLabel records the shard of NodeBucket.
+	NodeBucketShardLabel = "nodebucket.openyurt.io/shard"
 )
 
 // Pod related labels and annotations
 const (
 	// AnnotationExcludeHostNetworkPool indicates the pod don't want to be scheduled to nodes in hostNetwork mode NodePool
 	AnnotationExcludeHostNetworkPool = "apps.openyurt.io/exclude-host-network-pool"
+
+	// AnnotationFailoverAfter declares how long pods are bound to a NotReady autonomous node before they are
+	// failed over to other nodes, it can be added to NodePool or the pod template of workloads, and the
+	// annotation of pod takes precedence.
+	AnnotationFailoverAfter = "apps.openyurt.io/failover-after"
+
+	// AnnotationFailoverBackupPool declares the NodePool to which pods can be failed over when there is no
+	// ready node in the NodePool of the failed node, it can be added to NodePool or the pod template of workloads.
+	AnnotationFailoverBackupPool = "apps.openyurt.io/failover-backup-pool"
+
+	// AnnotationFailoverTime is added to pod when it is failed over, the autonomy tolerations of pod are removed
+	// and the pod will be evicted from the failed node.
+	AnnotationFailoverTime = "apps.openyurt.io/failover-time"
+
+	// AnnotationFailoverTargetPools is added to pod when it is failed over, it holds the comma separated NodePools
+	// to which the replacement pod created by the same workload is scheduled. Only the pod created within
+	// 30 minutes after the failover is steered to these NodePools.
+	AnnotationFailoverTargetPools = "apps.openyurt.io/failover-target-pools"
+
+	// AnnotationFailoverReplacementOf is added to the replacement pod of a failed over pod by pod webhook,
+	// it holds the name of the failed over pod, so that each failed over pod is replaced only once.
+	AnnotationFailoverReplacementOf = "apps.openyurt.io/failover-replacement-of"
 )
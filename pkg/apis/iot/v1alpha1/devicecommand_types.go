@@ -0,0 +1,160 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeviceCommandAction is the action executed on the command or property of devices
+type DeviceCommandAction string
+
+const (
+	// DeviceCommandActionSet sets the command or property to the value
+	DeviceCommandActionSet DeviceCommandAction = "Set"
+	// DeviceCommandActionGet reads the value of the command or property
+	DeviceCommandActionGet DeviceCommandAction = "Get"
+)
+
+// DeviceCommandPhase is the phase of a DeviceCommand, or of the command on a target device
+type DeviceCommandPhase string
+
+const (
+	DeviceCommandPending   DeviceCommandPhase = "Pending"
+	DeviceCommandRunning   DeviceCommandPhase = "Running"
+	DeviceCommandSucceeded DeviceCommandPhase = "Succeeded"
+	DeviceCommandFailed    DeviceCommandPhase = "Failed"
+)
+
+const (
+	// DefaultDeviceCommandTimeoutSeconds is the default timeout of an attempt on a device
+	DefaultDeviceCommandTimeoutSeconds int32 = 30
+	// DefaultDeviceCommandBackoffLimit is the default number of retries on a device
+	DefaultDeviceCommandBackoffLimit int32 = 3
+)
+
+// DeviceCommandSpec defines the desired state of DeviceCommand
+type DeviceCommandSpec struct {
+	// NodePool indicates which nodePool the target devices come from,
+	// the command is executed by the yurt-iot-dock of the nodePool
+	NodePool string `json:"nodePool"`
+	// DeviceName is the name of the target device, it's exclusive with Selector
+	// +optional
+	DeviceName string `json:"deviceName,omitempty"`
+	// Selector selects the target devices by labels within the nodePool, it's exclusive with DeviceName
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Command is the name of the command or property of the target devices
+	Command string `json:"command"`
+	// Action is Set or Get, defaults to Set
+	// +optional
+	// +kubebuilder:validation:Enum=Set;Get
+	Action DeviceCommandAction `json:"action,omitempty"`
+	// Value is the parameter of the command when the action is Set
+	// +optional
+	Value string `json:"value,omitempty"`
+	// TimeoutSeconds is the timeout of each attempt on a device, defaults to 30
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// BackoffLimit is the number of retries on a device before the command is marked as failed on it, defaults to 3
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	// Issuer is the user who created the command, it's recorded by yurt-manager webhook
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+}
+
+// DeviceCommandResult is the result of the command on a target device
+type DeviceCommandResult struct {
+	// DeviceName is the name of the target device
+	DeviceName string `json:"deviceName"`
+	// Phase of the command on the device
+	Phase DeviceCommandPhase `json:"phase,omitempty"`
+	// Attempts is the number of executions on the device
+	Attempts int32 `json:"attempts,omitempty"`
+	// Value is the actual value of the command or property returned by the device
+	// +optional
+	Value string `json:"value,omitempty"`
+	// Message is a human readable message indicating why the last attempt failed
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastAttemptTime is the time of the last execution on the device
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+}
+
+// DeviceCommandStatus defines the observed state of DeviceCommand
+type DeviceCommandStatus struct {
+	// Phase of the command, it's Succeeded only if the command succeeds on all target devices
+	Phase DeviceCommandPhase `json:"phase,omitempty"`
+	// StartTime is the time when the command is started to execute
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time when the command is finished on all target devices
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Succeeded is the number of devices on which the command succeeds
+	Succeeded int32 `json:"succeeded,omitempty"`
+	// Failed is the number of devices on which the command fails
+	Failed int32 `json:"failed,omitempty"`
+	// Message is a human readable message about the command
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Results are the results of the command on target devices
+	// +optional
+	Results []DeviceCommandResult `json:"results,omitempty"`
+}
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=dc
+// +kubebuilder:printcolumn:name="NODEPOOL",type="string",JSONPath=".spec.nodePool",description="The nodepool of target devices"
+// +kubebuilder:printcolumn:name="COMMAND",type="string",JSONPath=".spec.command",description="The command or property of target devices"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.phase",description="The phase of command"
+// +kubebuilder:printcolumn:name="SUCCEEDED",type="integer",JSONPath=".status.succeeded",description="The number of devices on which the command succeeds"
+// +kubebuilder:printcolumn:name="FAILED",type="integer",JSONPath=".status.failed",description="The number of devices on which the command fails"
+// +kubebuilder:printcolumn:name="ISSUER",type="string",priority=1,JSONPath=".spec.issuer",description="The user who created the command"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// DeviceCommand is the Schema for the devicecommands API
+type DeviceCommand struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DeviceCommandSpec   `json:"spec,omitempty"`
+	Status DeviceCommandStatus `json:"status,omitempty"`
+}
+
+// IsFinished returns true if the command is finished on all target devices
+func (dc *DeviceCommand) IsFinished() bool {
+	return dc.Status.Phase == DeviceCommandSucceeded || dc.Status.Phase == DeviceCommandFailed
+}
+
+//+kubebuilder:object:root=true
+
+// DeviceCommandList contains a list of DeviceCommand
+type DeviceCommandList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeviceCommand `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DeviceCommand{}, &DeviceCommandList{})
+}
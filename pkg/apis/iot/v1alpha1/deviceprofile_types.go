@@ -49,7 +49,7 @@ type ResourceProperties struct {
 	ValueType    string `json:"valueType,omitempty"`
 }
 
-type DeviceCommand struct {
+type DeviceProfileCommand struct {
 	Name               string              `json:"name"`
 	IsHidden           bool                `json:"isHidden"`
 	ReadWrite          string              `json:"readWrite"`
@@ -72,9 +72,9 @@ type DeviceProfileSpec struct {
 	// Model of the device
 	Model string `json:"model,omitempty"`
 	// Labels used to search for groups of profiles on EdgeX Foundry
-	Labels          []string         `json:"labels,omitempty"`
-	DeviceResources []DeviceResource `json:"deviceResources,omitempty"`
-	DeviceCommands  []DeviceCommand  `json:"deviceCommands,omitempty"`
+	Labels          []string               `json:"labels,omitempty"`
+	DeviceResources []DeviceResource       `json:"deviceResources,omitempty"`
+	DeviceCommands  []DeviceProfileCommand `json:"deviceCommands,omitempty"`
 }
 
 // DeviceProfileStatus defines the observed state of DeviceProfile
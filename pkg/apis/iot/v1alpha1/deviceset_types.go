@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DeviceSetLabel is added to the devices provisioned by a DeviceSet, its value is the name of DeviceSet
+	DeviceSetLabel = "iot.openyurt.io/deviceset"
+	// DeviceSetIndexLabel is added to the devices provisioned by a DeviceSet, its value is the index of device in the nodepool
+	DeviceSetIndexLabel = "iot.openyurt.io/deviceset-index"
+
+	// DefaultDeviceSetNamePattern is the default pattern of the names of devices provisioned by a DeviceSet
+	DefaultDeviceSetNamePattern = "{set}-{pool}-{index}"
+)
+
+// DeviceSetSpec defines the desired state of DeviceSet
+type DeviceSetSpec struct {
+	// NodePoolSelector is a label query over nodepool in which devices should be provisioned.
+	// It must match the nodepool's labels.
+	// +optional
+	NodePoolSelector *metav1.LabelSelector `json:"nodepoolSelector,omitempty"`
+	// Pools is a list of selected nodepools specified with nodepool id in which devices should be provisioned.
+	// +optional
+	Pools []string `json:"pools,omitempty"`
+	// Count is the number of devices provisioned in each nodepool, defaults to 1
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+	// NamePattern is the pattern of the names of provisioned devices, the placeholders {set}, {pool}
+	// and {index} are replaced by the name of DeviceSet, the name of nodepool and the index of device.
+	// Defaults to {set}-{pool}-{index}
+	// +optional
+	NamePattern string `json:"namePattern,omitempty"`
+	// Template describes the devices that will be provisioned, the placeholders of NamePattern can be used
+	// in the description, location and protocol properties of the template.
+	Template DeviceTemplateSpec `json:"template"`
+	// Tweaks are the customizations applied to the devices in specified nodepools,
+	// the latter tweak takes precedence when a nodepool matches more than one tweak.
+	// +optional
+	Tweaks []DeviceSetTweak `json:"tweaks,omitempty"`
+}
+
+// DeviceTemplateSpec describes the devices provisioned by a DeviceSet
+type DeviceTemplateSpec struct {
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              DeviceSpec `json:"spec"`
+}
+
+// DeviceSetTweak describes the customization applied to the devices in a set of nodepools
+type DeviceSetTweak struct {
+	// NodePoolSelector is a label query over nodepool in which devices should be adjusted.
+	// +optional
+	NodePoolSelector *metav1.LabelSelector `json:"nodepoolSelector,omitempty"`
+	// Pools is a list of selected nodepools specified with nodepool id in which devices should be adjusted.
+	// +optional
+	Pools []string `json:"pools,omitempty"`
+	// Count overrides the number of devices provisioned in the nodepools
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+	// NamePattern overrides the pattern of the names of devices in the nodepools
+	// +optional
+	NamePattern string `json:"namePattern,omitempty"`
+	// Protocols are merged into the protocols of template, such as the addresses of devices in the nodepools
+	// +optional
+	Protocols map[string]ProtocolProperties `json:"protocols,omitempty"`
+	// Labels are added to the labels of devices in the nodepools
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// DeviceSetPoolStatus is the provisioning status of a DeviceSet in a nodepool
+type DeviceSetPoolStatus struct {
+	// NodePool is the name of nodepool
+	NodePool string `json:"nodePool"`
+	// ObservedGeneration is the generation of DeviceSet observed by the yurt-iot-dock of nodepool
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Desired is the number of devices that should be provisioned in the nodepool
+	Desired int32 `json:"desired"`
+	// Provisioned is the number of devices that are created and up to date with the template
+	Provisioned int32 `json:"provisioned"`
+	// Synced is the number of devices that are synced to the edge platform
+	Synced int32 `json:"synced"`
+	// Message is a human readable message indicating why the devices are not provisioned
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastUpdateTime is the last time the status of nodepool was updated
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// DeviceSetStatus defines the observed state of DeviceSet
+type DeviceSetStatus struct {
+	// Pools are the provisioning status of the nodepools, each of them is reported by the yurt-iot-dock of nodepool
+	// +optional
+	Pools []DeviceSetPoolStatus `json:"pools,omitempty"`
+}
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=dset
+// +kubebuilder:printcolumn:name="PROFILE",type="string",JSONPath=".spec.template.spec.profileName",description="The profile of provisioned devices"
+// +kubebuilder:printcolumn:name="SERVICE",type="string",JSONPath=".spec.template.spec.serviceName",description="The service of provisioned devices"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// DeviceSet is the Schema for the devicesets API
+type DeviceSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DeviceSetSpec   `json:"spec,omitempty"`
+	Status DeviceSetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DeviceSetList contains a list of DeviceSet
+type DeviceSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeviceSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DeviceSet{}, &DeviceSetList{})
+}
@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -101,21 +102,137 @@ func (in *Device) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceCommand) DeepCopyInto(out *DeviceCommand) {
 	*out = *in
-	if in.ResourceOperations != nil {
-		in, out := &in.ResourceOperations, &out.ResourceOperations
-		*out = make([]ResourceOperation, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceCommand.
+func (in *DeviceCommand) DeepCopy() *DeviceCommand {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceCommand)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceCommand) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceCommandList) DeepCopyInto(out *DeviceCommandList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceCommand, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceCommand.
-func (in *DeviceCommand) DeepCopy() *DeviceCommand {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceCommandList.
+func (in *DeviceCommandList) DeepCopy() *DeviceCommandList {
 	if in == nil {
 		return nil
 	}
-	out := new(DeviceCommand)
+	out := new(DeviceCommandList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceCommandList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceCommandResult) DeepCopyInto(out *DeviceCommandResult) {
+	*out = *in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceCommandResult.
+func (in *DeviceCommandResult) DeepCopy() *DeviceCommandResult {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceCommandResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceCommandSpec) DeepCopyInto(out *DeviceCommandSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceCommandSpec.
+func (in *DeviceCommandSpec) DeepCopy() *DeviceCommandSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceCommandSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceCommandStatus) DeepCopyInto(out *DeviceCommandStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]DeviceCommandResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceCommandStatus.
+func (in *DeviceCommandStatus) DeepCopy() *DeviceCommandStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceCommandStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -195,6 +312,28 @@ func (in *DeviceProfile) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceProfileCommand) DeepCopyInto(out *DeviceProfileCommand) {
+	*out = *in
+	if in.ResourceOperations != nil {
+		in, out := &in.ResourceOperations, &out.ResourceOperations
+		*out = make([]ResourceOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceProfileCommand.
+func (in *DeviceProfileCommand) DeepCopy() *DeviceProfileCommand {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceProfileCommand)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceProfileList) DeepCopyInto(out *DeviceProfileList) {
 	*out = *in
@@ -244,7 +383,7 @@ func (in *DeviceProfileSpec) DeepCopyInto(out *DeviceProfileSpec) {
 	}
 	if in.DeviceCommands != nil {
 		in, out := &in.DeviceCommands, &out.DeviceCommands
-		*out = make([]DeviceCommand, len(*in))
+		*out = make([]DeviceProfileCommand, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -416,6 +555,196 @@ func (in *DeviceServiceStatus) DeepCopy() *DeviceServiceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSet) DeepCopyInto(out *DeviceSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSet.
+func (in *DeviceSet) DeepCopy() *DeviceSet {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSetList) DeepCopyInto(out *DeviceSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetList.
+func (in *DeviceSetList) DeepCopy() *DeviceSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSetPoolStatus) DeepCopyInto(out *DeviceSetPoolStatus) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetPoolStatus.
+func (in *DeviceSetPoolStatus) DeepCopy() *DeviceSetPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSetPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSetSpec) DeepCopyInto(out *DeviceSetSpec) {
+	*out = *in
+	if in.NodePoolSelector != nil {
+		in, out := &in.NodePoolSelector, &out.NodePoolSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int32)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Tweaks != nil {
+		in, out := &in.Tweaks, &out.Tweaks
+		*out = make([]DeviceSetTweak, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetSpec.
+func (in *DeviceSetSpec) DeepCopy() *DeviceSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSetStatus) DeepCopyInto(out *DeviceSetStatus) {
+	*out = *in
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]DeviceSetPoolStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetStatus.
+func (in *DeviceSetStatus) DeepCopy() *DeviceSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSetTweak) DeepCopyInto(out *DeviceSetTweak) {
+	*out = *in
+	if in.NodePoolSelector != nil {
+		in, out := &in.NodePoolSelector, &out.NodePoolSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Protocols != nil {
+		in, out := &in.Protocols, &out.Protocols
+		*out = make(map[string]ProtocolProperties, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(ProtocolProperties, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetTweak.
+func (in *DeviceSetTweak) DeepCopy() *DeviceSetTweak {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSetTweak)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceSpec) DeepCopyInto(out *DeviceSpec) {
 	*out = *in
@@ -490,6 +819,23 @@ func (in *DeviceStatus) DeepCopy() *DeviceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceTemplateSpec) DeepCopyInto(out *DeviceTemplateSpec) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceTemplateSpec.
+func (in *DeviceTemplateSpec) DeepCopy() *DeviceTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlatformAdmin) DeepCopyInto(out *PlatformAdmin) {
 	*out = *in
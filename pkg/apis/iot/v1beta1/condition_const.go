@@ -29,4 +29,16 @@ const (
 	ComponentProvisioningReason = "ComponentProvisioning"
 
 	ComponentProvisioningFailedReason = "ComponentProvisioningFailed"
+	// UpgradeCompletedCondition documents whether the components run with the version in spec.
+	UpgradeCompletedCondition PlatformAdminConditionType = "UpgradeCompleted"
+
+	UpgradingReason = "Upgrading"
+
+	UpgradePreflightFailedReason = "UpgradePreflightFailed"
+
+	UpgradeRollingBackReason = "UpgradeRollingBack"
+
+	UpgradeRolledBackReason = "UpgradeRolledBack"
+
+	UpgradeRollbackFailedReason = "UpgradeRollbackFailed"
 )
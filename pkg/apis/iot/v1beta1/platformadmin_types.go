@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -31,8 +32,29 @@ const (
 // PlatformAdmin platform supported by openyurt
 const (
 	PlatformAdminPlatformEdgeX = "edgex"
+	PlatformAdminPlatformMQTT  = "mqtt"
 )
 
+// PlatformAdminUpgradePhase is the phase of a version upgrade of the PlatformAdmin components.
+type PlatformAdminUpgradePhase string
+
+const (
+	// PlatformAdminUpgrading means the components are being upgraded step by step.
+	PlatformAdminUpgrading PlatformAdminUpgradePhase = "Upgrading"
+	// PlatformAdminUpgradeRollingBack means a step of the upgrade failed and the components are being restored.
+	PlatformAdminUpgradeRollingBack PlatformAdminUpgradePhase = "RollingBack"
+	// PlatformAdminUpgradeSucceeded means all the components run with the target version.
+	PlatformAdminUpgradeSucceeded PlatformAdminUpgradePhase = "Succeeded"
+	// PlatformAdminUpgradeFailed means the upgrade was rejected by the pre-flight checks and nothing was changed,
+	// or the restored components were not ready in time during the rollback.
+	PlatformAdminUpgradeFailed PlatformAdminUpgradePhase = "Failed"
+	// PlatformAdminUpgradeRolledBack means the components were restored to the previous version.
+	PlatformAdminUpgradeRolledBack PlatformAdminUpgradePhase = "RolledBack"
+)
+
+// MaxPlatformAdminUpgradeHistory is the number of finished upgrades kept in the status.
+const MaxPlatformAdminUpgradeHistory = 10
+
 // PlatformAdminConditionType indicates valid conditions type of a PlatformAdmin.
 type PlatformAdminConditionType string
 type PlatformAdminConditionSeverity string
@@ -40,6 +62,65 @@ type PlatformAdminConditionSeverity string
 // Component defines the components of EdgeX
 type Component struct {
 	Name string `json:"name"`
+
+	// Override patches the template of the component.
+	// +optional
+	Override *ComponentOverride `json:"override,omitempty"`
+
+	// Definition fully defines the component, it is used to deploy the components that are not bundled
+	// or to replace the template of a bundled component.
+	// +optional
+	Definition *ComponentDefinition `json:"definition,omitempty"`
+}
+
+// ComponentOverride describes the changes made to the template of a component,
+// the container changes apply to the container named after the component, or to the first container if there is none.
+type ComponentOverride struct {
+	// Image replaces the image of the container.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Env is merged into the env of the container by name.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Resources replaces the resource requirements of the container.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector is merged into the node selector of the pods.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Affinity replaces the affinity of the pods.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Tolerations are appended to the tolerations of the pods.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// ComponentDefinition defines a component that is deployed in each node pool of the PlatformAdmin.
+type ComponentDefinition struct {
+	// Service exposes the component in the node pools.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Service *corev1.ServiceSpec `json:"service,omitempty"`
+
+	// Deployment is the template of the workload in each node pool.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Deployment appsv1.DeploymentSpec `json:"deployment"`
 }
 
 // PlatformAdminSpec defines the desired state of PlatformAdmin
@@ -77,6 +158,54 @@ type PlatformAdminStatus struct {
 	// Current PlatformAdmin state
 	// +optional
 	Conditions []PlatformAdminCondition `json:"conditions,omitempty"`
+
+	// CurrentVersion is the version that the components are running with.
+	// +optional
+	CurrentVersion string `json:"currentVersion,omitempty"`
+
+	// Upgrade is the latest version upgrade of the components.
+	// +optional
+	Upgrade *PlatformAdminUpgrade `json:"upgrade,omitempty"`
+
+	// UpgradeHistory records the finished version upgrades, the latest one comes first.
+	// +optional
+	UpgradeHistory []PlatformAdminUpgrade `json:"upgradeHistory,omitempty"`
+}
+
+// PlatformAdminUpgrade describes a version upgrade of the PlatformAdmin components.
+type PlatformAdminUpgrade struct {
+	// FromVersion is the version that the components ran with before the upgrade.
+	FromVersion string `json:"fromVersion"`
+
+	// ToVersion is the target version of the upgrade.
+	ToVersion string `json:"toVersion"`
+
+	// Phase of the upgrade.
+	Phase PlatformAdminUpgradePhase `json:"phase"`
+
+	// Steps are the groups of components that are upgraded one after another.
+	// +optional
+	Steps []string `json:"steps,omitempty"`
+
+	// CurrentStep is the index of the step in Steps that is being upgraded.
+	// +optional
+	CurrentStep int32 `json:"currentStep,omitempty"`
+
+	// StartTime is the time when the upgrade started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// StepStartTime is the time when the current step or the rollback started.
+	// +optional
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+
+	// CompletionTime is the time when the upgrade finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// A human readable message indicating details about the upgrade.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // PlatformAdminCondition describes current state of a PlatformAdmin.
@@ -105,6 +234,7 @@ type PlatformAdminCondition struct {
 // +kubebuilder:printcolumn:name="READY",type="boolean",JSONPath=".status.ready",description="The platformadmin ready status"
 // +kubebuilder:printcolumn:name="ReadyComponentNum",type="integer",JSONPath=".status.readyComponentNum",description="The Ready Component."
 // +kubebuilder:printcolumn:name="UnreadyComponentNum",type="integer",JSONPath=".status.unreadyComponentNum",description="The Unready Component."
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".status.currentVersion",description="The version that the components are running with."
 // +kubebuilder:storageversion
 
 // PlatformAdmin is the Schema for the samples API
@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AddressPoolSpec defines the desired state of AddressPool
+type AddressPoolSpec struct {
+	// NodePool is the name of NodePool, the PoolServices in the NodePool are allocated
+	// virtual IPs from this pool.
+	NodePool string `json:"nodePool"`
+
+	// Addresses is a list of IP addresses, CIDRs or ranges(such as 192.168.0.10-192.168.0.20)
+	// which can be allocated. The network and broadcast addresses of IPv4 CIDRs are excluded.
+	Addresses []string `json:"addresses"`
+}
+
+// AddressPoolStatus defines the observed state of AddressPool
+type AddressPoolStatus struct {
+	// Allocated is the number of addresses which are allocated to PoolServices.
+	Allocated int32 `json:"allocated,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,path=addresspools,shortName=ap,categories=yurt
+// +kubebuilder:printcolumn:name="NODEPOOL",type="string",JSONPath=".spec.nodePool",description="The NodePool which the addresses are allocated for."
+// +kubebuilder:printcolumn:name="ALLOCATED",type="integer",JSONPath=".status.allocated",description="The number of allocated addresses."
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp",description="CreationTimestamp is a timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+
+// AddressPool is the Schema for the virtual IPs of PoolServices in a NodePool
+type AddressPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AddressPoolSpec   `json:"spec,omitempty"`
+	Status AddressPoolStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AddressPoolList contains a list of AddressPool
+type AddressPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AddressPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AddressPool{}, &AddressPoolList{})
+}
@@ -20,4 +20,10 @@ const (
 	LabelServiceName           = "openyurt.io/service-name"
 	LabelNodePoolName          = "openyurt.io/pool-name"
 	AnnotationNodePoolSelector = "service.openyurt.io/nodepool-labelselector"
+
+	// VIPLoadBalancerClass is the load balancer class of services whose PoolServices are allocated
+	// virtual IPs from AddressPools by yurt-manager, and the virtual IPs are announced by yurthub.
+	VIPLoadBalancerClass = "service.openyurt.io/vip"
+	// AnnotationVIPNode is the annotation of PoolService which records the node announcing the virtual IP.
+	AnnotationVIPNode = "poolservice.openyurt.io/vip-node"
 )
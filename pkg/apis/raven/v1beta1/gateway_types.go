@@ -27,11 +27,29 @@ const (
 	EventActiveEndpointElected = "ActiveEndpointElected"
 	// EventActiveEndpointLost is the event indicating the active endpoint is lost.
 	EventActiveEndpointLost = "ActiveEndpointLost"
+	// EventActiveEndpointFailover is the event indicating the active endpoint is failed over to a standby endpoint.
+	EventActiveEndpointFailover = "ActiveEndpointFailover"
+)
+
+// Failover reason.
+const (
+	// FailoverReasonNodeNotReady means the node hosting the active endpoint is not ready.
+	FailoverReasonNodeNotReady = "NodeNotReady"
+	// FailoverReasonUnhealthy means the health of active endpoint is not reported for longer than the failover period.
+	FailoverReasonUnhealthy = "Unhealthy"
+	// FailoverReasonPreempted means an endpoint with higher priority becomes available.
+	FailoverReasonPreempted = "Preempted"
 )
 
 const (
 	ExposeTypePublicIP     = "PublicIP"
 	ExposeTypeLoadBalancer = "LoadBalancer"
+	// ExposeTypeNodePort exposes the active endpoints by NodePort services on their nodes, which
+	// are supposed to be cloud nodes with external IP addresses.
+	ExposeTypeNodePort = "NodePort"
+	// ExposeTypeStaticIPPool exposes the active endpoints by external IPs of services, which are
+	// allocated from the PublicIPPool of Gateway.
+	ExposeTypeStaticIPPool = "StaticIPPool"
 )
 
 const (
@@ -73,6 +91,9 @@ type GatewaySpec struct {
 	Endpoints []Endpoint `json:"endpoints,omitempty"`
 	// ExposeType determines how the Gateway is exposed.
 	ExposeType string `json:"exposeType,omitempty"`
+	// PublicIPPool is a list of public IP addresses or CIDRs, from which the public IP of active
+	// endpoints is allocated. It's required when ExposeType is StaticIPPool.
+	PublicIPPool []string `json:"publicIPPool,omitempty"`
 }
 
 // Endpoint stores all essential data for establishing the VPN tunnel and Proxy
@@ -93,6 +114,10 @@ type Endpoint struct {
 	PublicPort int `json:"publicPort,omitempty"`
 	// Config is a map to record config for the raven agent of node
 	Config map[string]string `json:"config,omitempty"`
+	// Priority is the preference of the endpoint to be elected as the active endpoint. Endpoints with higher
+	// priority are preferred, and the active endpoint is switched back to the endpoint with higher priority
+	// once it becomes available again. Defaults to 0.
+	Priority int `json:"priority,omitempty"`
 }
 
 // NodeInfo stores information of node managed by Gateway.
@@ -111,6 +136,22 @@ type GatewayStatus struct {
 	Nodes []NodeInfo `json:"nodes,omitempty"`
 	// ActiveEndpoints is the reference of the active endpoint.
 	ActiveEndpoints []*Endpoint `json:"activeEndpoints,omitempty"`
+	// Failovers records the latest failovers of active endpoints, the most recent one is the last.
+	Failovers []FailoverRecord `json:"failovers,omitempty"`
+}
+
+// FailoverRecord records a failover from an active endpoint to another endpoint.
+type FailoverRecord struct {
+	// Type is the type of endpoints, proxy or tunnel
+	Type string `json:"type"`
+	// From is the node hosting the previous active endpoint.
+	From string `json:"from"`
+	// To is the node hosting the new active endpoint, it's empty if there is no available endpoint.
+	To string `json:"to,omitempty"`
+	// Reason is the reason of failover.
+	Reason string `json:"reason"`
+	// Time is the time when the failover happened.
+	Time metav1.Time `json:"time"`
 }
 
 // +genclient
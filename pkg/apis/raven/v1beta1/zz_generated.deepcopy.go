@@ -47,6 +47,22 @@ func (in *Endpoint) DeepCopy() *Endpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverRecord) DeepCopyInto(out *FailoverRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverRecord.
+func (in *FailoverRecord) DeepCopy() *FailoverRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Gateway) DeepCopyInto(out *Gateway) {
 	*out = *in
@@ -123,6 +139,11 @@ func (in *GatewaySpec) DeepCopyInto(out *GatewaySpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PublicIPPool != nil {
+		in, out := &in.PublicIPPool, &out.PublicIPPool
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewaySpec.
@@ -156,6 +177,13 @@ func (in *GatewayStatus) DeepCopyInto(out *GatewayStatus) {
 			}
 		}
 	}
+	if in.Failovers != nil {
+		in, out := &in.Failovers, &out.Failovers
+		*out = make([]FailoverRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayStatus.
@@ -0,0 +1,269 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+
+	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/node-servant/static-pod-upgrade/util"
+)
+
+const (
+	defaultHealthCheckHost = "127.0.0.1"
+)
+
+var (
+	// getPod gets the static pod on this node, it can be replaced in unit tests
+	getPod = util.GetPodFromYurtHub
+	// waitForPodRunning waits for the static pod to be running, it can be replaced in unit tests
+	waitForPodRunning = util.WaitForPodRunning
+	// procRoot is the proc filesystem of host, the upgrader should share the PID namespace of host
+	procRoot = "/proc"
+)
+
+// RevertedError indicates that the upgrade failed and the previous manifest has been restored
+type RevertedError struct {
+	Err error
+}
+
+func (e *RevertedError) Error() string {
+	return fmt.Sprintf("upgrade is reverted, %v", e.Err)
+}
+
+func (e *RevertedError) Unwrap() error {
+	return e.Err
+}
+
+// waitForHealthy performs the health check against the new static pod until it passes SuccessThreshold
+// consecutive times. It fails when the deadline is exceeded or the containers of the new static pod restart.
+func (ctrl *Controller) waitForHealthy(deadline time.Time) error {
+	hc := ctrl.healthCheck
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	ticker := time.NewTicker(time.Duration(hc.PeriodSeconds) * time.Second)
+	defer ticker.Stop()
+
+	var (
+		successes int32
+		lastErr   error
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("static pod %s/%s did not pass health check before deadline, last error: %v",
+				ctrl.namespace, ctrl.name, lastErr)
+		case <-ticker.C:
+			pod, err := getPod(ctrl.namespace, ctrl.name)
+			if err != nil {
+				klog.V(4).Infof("Temporarily fail to get pod from YurtHub, %v", err)
+				successes, lastErr = 0, err
+				continue
+			}
+
+			if pod.Annotations[util.StaticPodHashAnnotation] != ctrl.hash {
+				successes, lastErr = 0, fmt.Errorf("static pod %s/%s is not running the latest hash %s", ctrl.namespace, ctrl.name, ctrl.hash)
+				continue
+			}
+
+			// a crash-looping static pod may pass a single check, so any restart of the new static pod is
+			// regarded as failure. The containers of the new static pod start with no restart.
+			if restarts := restartCount(pod); restarts > 0 {
+				return fmt.Errorf("containers of static pod %s/%s restarted %d times during health check",
+					ctrl.namespace, ctrl.name, restarts)
+			}
+
+			if err := probe(ctx, hc, pod); err != nil {
+				klog.Infof("Health check of static pod %s/%s failed, %v", ctrl.namespace, ctrl.name, err)
+				successes, lastErr = 0, err
+				continue
+			}
+			successes++
+			klog.Infof("Health check of static pod %s/%s succeeded %d times", ctrl.namespace, ctrl.name, successes)
+			if successes >= hc.SuccessThreshold {
+				return nil
+			}
+		}
+	}
+}
+
+// restartCount returns the total restart count of containers in the pod
+func restartCount(pod *corev1.Pod) int32 {
+	var count int32
+	for _, cs := range pod.Status.ContainerStatuses {
+		count += cs.RestartCount
+	}
+	return count
+}
+
+// probe performs the health check action once
+func probe(ctx context.Context, hc *appsv1alpha1.YurtStaticSetHealthCheck, pod *corev1.Pod) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(hc.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	switch {
+	case hc.Exec != nil:
+		return probeExec(ctx, hc.Exec, pod)
+	case hc.HTTPGet != nil:
+		return probeHTTP(ctx, hc.HTTPGet, pod)
+	case hc.TCPSocket != nil:
+		return probeTCP(ctx, hc.TCPSocket, pod)
+	}
+	return fmt.Errorf("no supported health check action is specified")
+}
+
+// probeExec runs the command in the first container of the static pod. The command is executed in the
+// namespaces of the container process by nsenter, so the upgrader should share the PID namespace of host.
+func probeExec(ctx context.Context, action *corev1.ExecAction, pod *corev1.Pod) error {
+	if len(action.Command) == 0 {
+		return fmt.Errorf("exec command is empty")
+	}
+	pid, err := containerPID(pod)
+	if err != nil {
+		return err
+	}
+	args := append([]string{"-t", strconv.Itoa(pid), "-m", "-u", "-i", "-n", "-p", "--"}, action.Command...)
+	out, err := exec.CommandContext(ctx, "nsenter", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("exec %v failed, %v, output: %s", action.Command, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// containerPID finds a process of the first container of the pod in the proc filesystem of host
+func containerPID(pod *corev1.Pod) (int, error) {
+	if len(pod.Spec.Containers) == 0 {
+		return 0, fmt.Errorf("static pod %s/%s has no container", pod.Namespace, pod.Name)
+	}
+	var containerID string
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == pod.Spec.Containers[0].Name && cs.State.Running != nil {
+			// the container id is in the format of <type>://<container_id>
+			_, containerID, _ = strings.Cut(cs.ContainerID, "://")
+		}
+	}
+	if len(containerID) == 0 {
+		return 0, fmt.Errorf("container %s of static pod %s/%s is not running", pod.Spec.Containers[0].Name, pod.Namespace, pod.Name)
+	}
+
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		// the cgroup of container process contains the container id with both cgroupfs and systemd drivers
+		data, err := os.ReadFile(filepath.Join(procRoot, entry.Name(), "cgroup"))
+		if err == nil && strings.Contains(string(data), containerID) {
+			return pid, nil
+		}
+	}
+	return 0, fmt.Errorf("could not find the process of container %s, the PID namespace of host is required for exec health check", containerID)
+}
+
+func probeHTTP(ctx context.Context, action *corev1.HTTPGetAction, pod *corev1.Pod) error {
+	port, err := resolvePort(action.Port, pod)
+	if err != nil {
+		return err
+	}
+	scheme := strings.ToLower(string(action.Scheme))
+	if scheme == "" {
+		scheme = "http"
+	}
+	u := &url.URL{
+		Scheme: scheme,
+		Host:   net.JoinHostPort(probeHost(action.Host, pod), strconv.Itoa(port)),
+		Path:   action.Path,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	for _, h := range action.HTTPHeaders {
+		req.Header.Add(h.Name, h.Value)
+	}
+	// the same as kubelet, the certificate of the static pod is not verified
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("http get %s returned status: %v", u.String(), resp.Status)
+	}
+	return nil
+}
+
+func probeTCP(ctx context.Context, action *corev1.TCPSocketAction, pod *corev1.Pod) error {
+	port, err := resolvePort(action.Port, pod)
+	if err != nil {
+		return err
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(probeHost(action.Host, pod), strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// probeHost returns the host to connect, the IP of the static pod is used if host is not specified
+func probeHost(host string, pod *corev1.Pod) string {
+	if host != "" {
+		return host
+	}
+	if pod.Status.PodIP != "" {
+		return pod.Status.PodIP
+	}
+	return defaultHealthCheckHost
+}
+
+// resolvePort resolves the number of port, a named port is looked up in the containers of the pod
+func resolvePort(port intstr.IntOrString, pod *corev1.Pod) (int, error) {
+	if port.Type == intstr.Int {
+		return port.IntValue(), nil
+	}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == port.StrVal {
+				return int(p.ContainerPort), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("could not find port %s in static pod %s/%s", port.StrVal, pod.Namespace, pod.Name)
+}
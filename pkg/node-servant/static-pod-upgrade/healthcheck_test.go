@@ -0,0 +1,252 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
+	upgradeUtil "github.com/openyurtio/openyurt/pkg/node-servant/static-pod-upgrade/util"
+)
+
+func newHealthCheckPod(restarts int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        TestPodName,
+			Namespace:   metav1.NamespaceDefault,
+			Annotations: map[string]string{upgradeUtil.StaticPodHashAnnotation: TestHashValue},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  TestPodName,
+				Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+			}},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: TestPodName, RestartCount: restarts}},
+		},
+	}
+}
+
+func TestWaitForHealthy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+
+	tests := []struct {
+		name     string
+		path     string
+		restarts []int32
+		wantErr  string
+	}{
+		{
+			name:     "health check passed",
+			path:     "/healthz",
+			restarts: []int32{0, 0},
+		},
+		{
+			name:     "static pod restarted",
+			path:     "/healthz",
+			restarts: []int32{0, 1},
+			wantErr:  "restarted 1 times",
+		},
+		{
+			name:     "static pod restarted before the first check",
+			path:     "/healthz",
+			restarts: []int32{1},
+			wantErr:  "restarted 1 times",
+		},
+		{
+			name:     "health check failed before deadline",
+			path:     "/unhealthy",
+			restarts: []int32{0, 0, 0, 0},
+			wantErr:  "did not pass health check before deadline",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			getPod = func(namespace, name string) (*corev1.Pod, error) {
+				pod := newHealthCheckPod(tt.restarts[min(calls, len(tt.restarts)-1)])
+				calls++
+				return pod, nil
+			}
+			defer func() { getPod = upgradeUtil.GetPodFromYurtHub }()
+
+			ctrl := New(TestPodName, metav1.NamespaceDefault, TestManifest, "AdvancedRollingUpdate")
+			ctrl.hash = TestHashValue
+			ctrl.healthCheck = &appsv1alpha1.YurtStaticSetHealthCheck{
+				ProbeHandler: corev1.ProbeHandler{
+					HTTPGet: &corev1.HTTPGetAction{Host: host, Path: tt.path, Port: intstr.FromInt32(int32(port))},
+				},
+				TimeoutSeconds:   1,
+				PeriodSeconds:    1,
+				SuccessThreshold: 2,
+			}
+
+			err := ctrl.waitForHealthy(time.Now().Add(2500 * time.Millisecond))
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("waitForHealthy() unexpected error %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("waitForHealthy() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolvePort(t *testing.T) {
+	pod := newHealthCheckPod(0)
+	if port, err := resolvePort(intstr.FromString("http"), pod); err != nil || port != 8080 {
+		t.Errorf("resolvePort() = %d, %v, want 8080", port, err)
+	}
+	if _, err := resolvePort(intstr.FromString("metrics"), pod); err == nil {
+		t.Errorf("resolvePort() expect error for unknown port")
+	}
+}
+
+func TestContainerPID(t *testing.T) {
+	procRoot = t.TempDir()
+	defer func() { procRoot = "/proc" }()
+	for pid, cgroup := range map[string]string{
+		"1":    "0::/init.scope\n",
+		"1024": "0::/kubepods.slice/kubepods-burstable.slice/cri-containerd-abc123.scope\n",
+		"self": "0::/\n",
+	} {
+		if err := os.MkdirAll(filepath.Join(procRoot, pid), 0755); err != nil {
+			t.Fatalf("could not create proc dir, %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(procRoot, pid, "cgroup"), []byte(cgroup), 0644); err != nil {
+			t.Fatalf("could not write cgroup, %v", err)
+		}
+	}
+
+	pod := newHealthCheckPod(0)
+	pod.Status.ContainerStatuses[0].ContainerID = "containerd://abc123"
+	pod.Status.ContainerStatuses[0].State.Running = &corev1.ContainerStateRunning{}
+	if pid, err := containerPID(pod); err != nil || pid != 1024 {
+		t.Errorf("containerPID() = %d, %v, want 1024", pid, err)
+	}
+
+	pod.Status.ContainerStatuses[0].ContainerID = "containerd://def456"
+	if _, err := containerPID(pod); err == nil {
+		t.Errorf("containerPID() expect error for unknown container")
+	}
+}
+
+func TestOTAUpgradeReverted(t *testing.T) {
+	DefaultManifestPath = t.TempDir()
+	DefaultConfigmapPath = t.TempDir()
+	DefaultUpgradePath = t.TempDir()
+	getPod = func(namespace, name string) (*corev1.Pod, error) {
+		return newHealthCheckPod(1), nil
+	}
+	waitForPodRunning = func(namespace, name, hash string, timeout time.Duration) (bool, error) {
+		return true, nil
+	}
+	defer func() {
+		getPod = upgradeUtil.GetPodFromYurtHub
+		waitForPodRunning = upgradeUtil.WaitForPodRunning
+	}()
+
+	ctrl := New(TestPodName, metav1.NamespaceDefault, TestManifest, "OTA")
+	ctrl.SetHash(TestHashValue)
+	ctrl.SetHealthCheck(&appsv1alpha1.YurtStaticSetHealthCheck{
+		ProbeHandler:     corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(8080)}},
+		TimeoutSeconds:   1,
+		PeriodSeconds:    1,
+		SuccessThreshold: 1,
+		DeadlineSeconds:  3,
+	})
+
+	manifest := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: nginx\n  annotations:\n    openyurt.io/static-pod-hash: " + TestHashValue +
+		"\nspec:\n  containers:\n  - name: nginx\n    image: nginx\n"
+	if err := os.WriteFile(ctrl.upgradeManifestPath, []byte(manifest), 0644); err != nil {
+		t.Fatalf("could not write manifest, %v", err)
+	}
+	if err := os.WriteFile(ctrl.manifestPath, []byte("old"), 0644); err != nil {
+		t.Fatalf("could not write manifest, %v", err)
+	}
+
+	// the new static pod restarted, so the upgrade is reverted
+	var reverted *RevertedError
+	if err := ctrl.Upgrade(); !errors.As(err, &reverted) {
+		t.Fatalf("Upgrade() error = %v, want RevertedError", err)
+	}
+	if data, _ := os.ReadFile(ctrl.manifestPath); string(data) != "old" {
+		t.Errorf("Upgrade() manifest = %s, want the previous manifest", string(data))
+	}
+}
+
+func TestCheckManifestAndRevert(t *testing.T) {
+	DefaultManifestPath = t.TempDir()
+	DefaultConfigmapPath = t.TempDir()
+	DefaultUpgradePath = t.TempDir()
+
+	ctrl := New(TestPodName, metav1.NamespaceDefault, TestManifest, "AdvancedRollingUpdate")
+	ctrl.hash = TestHashValue
+
+	manifest := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: nginx\n  annotations:\n    openyurt.io/static-pod-hash: %s\n" +
+		"spec:\n  containers:\n  - name: nginx\n    image: nginx\n"
+	for hash, wantErr := range map[string]bool{TestHashValue: false, "stale": true} {
+		if err := os.WriteFile(ctrl.upgradeManifestPath, []byte(strings.Replace(manifest, "%s", hash, 1)), 0644); err != nil {
+			t.Fatalf("could not write manifest, %v", err)
+		}
+		if err := ctrl.checkManifest(); (err != nil) != wantErr {
+			t.Errorf("checkManifest() with hash %s error = %v, wantErr %v", hash, err, wantErr)
+		}
+	}
+
+	if err := os.WriteFile(ctrl.bakManifestPath, []byte("old"), 0644); err != nil {
+		t.Fatalf("could not write backup manifest, %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(DefaultManifestPath, upgradeUtil.WithYamlSuffix(TestManifest)), []byte("new"), 0644); err != nil {
+		t.Fatalf("could not write manifest, %v", err)
+	}
+
+	err := ctrl.revert(errors.New("health check failed"))
+	var reverted *RevertedError
+	if !errors.As(err, &reverted) {
+		t.Fatalf("revert() error = %v, want RevertedError", err)
+	}
+	data, _ := os.ReadFile(ctrl.manifestPath)
+	if string(data) != "old" {
+		t.Errorf("revert() manifest = %s, want the previous manifest", string(data))
+	}
+}
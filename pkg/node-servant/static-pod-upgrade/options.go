@@ -21,6 +21,8 @@ import (
 	"time"
 
 	"github.com/spf13/pflag"
+
+	"github.com/openyurtio/openyurt/pkg/node-servant/static-pod-upgrade/util"
 )
 
 const (
@@ -35,6 +37,8 @@ type Options struct {
 	hash      string
 	mode      string
 	timeout   time.Duration
+	// health check of the new static pod, encoded by util.EncodeHealthCheck
+	healthCheck string
 }
 
 // NewUpgradeOptions creates a new Options
@@ -52,6 +56,8 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.hash, "hash", o.hash, "The hash value of new static pod specification")
 	fs.StringVar(&o.mode, "mode", o.mode, "The upgrade mode which is used")
 	fs.DurationVar(&o.timeout, "timeout", o.timeout, "The timeout for upgrade success check.")
+	fs.StringVar(&o.healthCheck, "health-check", o.healthCheck, "The encoded health check which is performed against the new static pod, "+
+		"the previous manifest is restored if the check is not passed.")
 }
 
 // Validate validates Options
@@ -61,5 +67,11 @@ func (o *Options) Validate() error {
 			o.name, o.namespace, o.manifest, o.hash, o.mode)
 	}
 
+	if len(o.healthCheck) != 0 {
+		if _, err := util.DecodeHealthCheck(o.healthCheck); err != nil {
+			return fmt.Errorf("could not decode health check %s, %v", o.healthCheck, err)
+		}
+	}
+
 	return nil
 }
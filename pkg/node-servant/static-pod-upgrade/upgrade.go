@@ -23,7 +23,9 @@ import (
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
 
 	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
 	"github.com/openyurtio/openyurt/pkg/node-servant/static-pod-upgrade/util"
@@ -57,12 +59,21 @@ type Controller struct {
 	configMapDataPath string
 	// The latest manifest path, default `/etc/kubernetes/manifests/openyurtio-upgrade/manifestName.upgrade`
 	upgradeManifestPath string
+	// Health check of the new static pod, the previous manifest is restored if the check is not passed
+	healthCheck *appsv1alpha1.YurtStaticSetHealthCheck
 }
 
 func NewWithOptions(o *Options) (*Controller, error) {
 	ctrl := New(o.name, o.namespace, o.manifest, o.mode)
 	ctrl.hash = o.hash
 	ctrl.timeout = o.timeout
+	if len(o.healthCheck) != 0 {
+		hc, err := util.DecodeHealthCheck(o.healthCheck)
+		if err != nil {
+			return nil, err
+		}
+		ctrl.healthCheck = hc
+	}
 	return ctrl, nil
 }
 
@@ -105,49 +116,69 @@ func (ctrl *Controller) AutoUpgrade() error {
 	}
 	klog.Info("Auto prepare upgrade manifest success")
 
+	return ctrl.applyManifest("Auto")
+}
+
+// OTAUpgrade upgrades the static pod with the latest manifest which has been prepared by YurtHub
+func (ctrl *Controller) OTAUpgrade() error {
+	return ctrl.applyManifest("OTA")
+}
+
+// SetHash sets the hash of the latest static pod
+func (ctrl *Controller) SetHash(hash string) {
+	ctrl.hash = hash
+}
+
+// SetTimeout sets the timeout for the latest static pod to be running
+func (ctrl *Controller) SetTimeout(timeout time.Duration) {
+	ctrl.timeout = timeout
+}
+
+// SetHealthCheck sets the health check of the latest static pod
+func (ctrl *Controller) SetHealthCheck(hc *appsv1alpha1.YurtStaticSetHealthCheck) {
+	ctrl.healthCheck = hc
+}
+
+// applyManifest replaces the manifest with the latest one, the previous manifest is restored
+// if the latest static pod is not running or doesn't pass the health check.
+func (ctrl *Controller) applyManifest(mode string) error {
+	// (1) Check the latest manifest before replacing, in case of breaking the running static pod
+	if err := ctrl.checkManifest(); err != nil {
+		return err
+	}
+	klog.Infof("%s upgrade checkManifest success", mode)
+
 	// (2) Back up the old manifest in case of upgrade failure
 	if err := ctrl.backupManifest(); err != nil {
 		return err
 	}
-	klog.Info("Auto upgrade backupManifest success")
+	klog.Infof("%s upgrade backupManifest success", mode)
 
 	// (3) Replace manifest and kubelet will upgrade the static pod automatically
 	if err := ctrl.replaceManifest(); err != nil {
 		return err
 	}
-	klog.Info("Auto upgrade replaceManifest success")
+	replacedAt := time.Now()
+	klog.Infof("%s upgrade replaceManifest success", mode)
 
 	// (4) Verify the new static pod is running
 	ok, err := ctrl.verify()
 	if err != nil {
-		if err := ctrl.rollbackManifest(); err != nil {
-			klog.Errorf("could not rollback manifest when upgrade failed, %v", err)
-		}
-		return err
+		return ctrl.revert(err)
 	}
 	if !ok {
-		if err := ctrl.rollbackManifest(); err != nil {
-			klog.Errorf("could not rollback manifest when upgrade failed, %v", err)
-		}
-		return fmt.Errorf("the latest static pod is not running")
+		return ctrl.revert(fmt.Errorf("the latest static pod is not running"))
 	}
-	klog.Info("Auto upgrade verify success")
-
-	return nil
-}
+	klog.Infof("%s upgrade verify success", mode)
 
-func (ctrl *Controller) OTAUpgrade() error {
-	// (1) Back up the old manifest in case of upgrade failure
-	if err := ctrl.backupManifest(); err != nil {
-		return err
-	}
-	klog.Info("OTA upgrade backupManifest success")
-
-	// (2) Replace manifest and kubelet will upgrade the static pod automatically
-	if err := ctrl.replaceManifest(); err != nil {
-		return err
+	// (5) Check the health of the new static pod
+	if ctrl.healthCheck != nil {
+		deadline := replacedAt.Add(time.Duration(ctrl.healthCheck.DeadlineSeconds) * time.Second)
+		if err := ctrl.waitForHealthy(deadline); err != nil {
+			return ctrl.revert(err)
+		}
+		klog.Infof("%s upgrade health check success", mode)
 	}
-	klog.Info("OTA upgrade replaceManifest success")
 
 	return nil
 }
@@ -183,8 +214,38 @@ func (ctrl *Controller) rollbackManifest() error {
 	return util.CopyFile(ctrl.bakManifestPath, ctrl.manifestPath)
 }
 
+// revert restores the previous manifest when the upgrade failed
+func (ctrl *Controller) revert(cause error) error {
+	klog.Errorf("Upgrade static pod %s/%s failed, restore the previous manifest, %v", ctrl.namespace, ctrl.name, cause)
+	if err := ctrl.rollbackManifest(); err != nil {
+		klog.Errorf("could not rollback manifest when upgrade failed, %v", err)
+		return fmt.Errorf("could not rollback manifest when upgrade failed: %v, %w", cause, err)
+	}
+	return &RevertedError{Err: cause}
+}
+
+// checkManifest make sure the latest manifest is a valid static pod with the latest hash
+func (ctrl *Controller) checkManifest() error {
+	data, err := os.ReadFile(ctrl.upgradeManifestPath)
+	if err != nil {
+		return err
+	}
+
+	pod := &corev1.Pod{}
+	if err := yaml.Unmarshal(data, pod); err != nil {
+		return fmt.Errorf("could not decode the latest manifest %s, %v", ctrl.upgradeManifestPath, err)
+	}
+	if pod.Kind != "Pod" || len(pod.Spec.Containers) == 0 {
+		return fmt.Errorf("the latest manifest %s is not a valid pod", ctrl.upgradeManifestPath)
+	}
+	if h := pod.Annotations[util.StaticPodHashAnnotation]; h != ctrl.hash {
+		return fmt.Errorf("the hash %s of the latest manifest doesn't match the expected hash %s", h, ctrl.hash)
+	}
+	return nil
+}
+
 // verify make sure the latest static pod is running
 // return false when the latest static pod failed or check status time out
 func (ctrl *Controller) verify() (bool, error) {
-	return util.WaitForPodRunning(ctrl.namespace, ctrl.name, ctrl.hash, ctrl.timeout)
+	return waitForPodRunning(ctrl.namespace, ctrl.name, ctrl.hash, ctrl.timeout)
 }
@@ -18,6 +18,8 @@ package util
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -25,6 +27,8 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
+
+	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
 )
 
 const (
@@ -33,6 +37,17 @@ const (
 	UpgradeSuffix string = ".upgrade"
 
 	StaticPodHashAnnotation = "openyurt.io/static-pod-hash"
+	// StaticPodHealthCheckAnnotation is the health check encoded by EncodeHealthCheck, it is set on the configmap
+	// of YurtStaticSet and used when the static pod is upgraded in OTA mode
+	StaticPodHealthCheckAnnotation = "openyurt.io/static-pod-health-check"
+
+	// RevertedExitCode is the exit code of upgrade worker when the upgrade failed and
+	// the previous manifest has been restored
+	RevertedExitCode = 3
+)
+
+var (
+	TerminationMessagePath = "/dev/termination-log"
 )
 
 func WithYamlSuffix(path string) string {
@@ -110,3 +125,31 @@ func WaitForPodRunning(namespace, name, hash string, timeout time.Duration) (boo
 		}
 	}
 }
+
+// EncodeHealthCheck encodes the health check into a string which can be passed as a flag of upgrade worker
+func EncodeHealthCheck(hc *appsv1alpha1.YurtStaticSetHealthCheck) (string, error) {
+	data, err := json.Marshal(hc)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecodeHealthCheck decodes the health check encoded by EncodeHealthCheck
+func DecodeHealthCheck(str string) (*appsv1alpha1.YurtStaticSetHealthCheck, error) {
+	data, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+	hc := &appsv1alpha1.YurtStaticSetHealthCheck{}
+	if err := json.Unmarshal(data, hc); err != nil {
+		return nil, err
+	}
+	return hc, nil
+}
+
+// WriteTerminationMessage writes the message to the termination message path of upgrade worker,
+// so the outcome of upgrade can be reported in the status of worker pod
+func WriteTerminationMessage(msg string) error {
+	return os.WriteFile(TerminationMessagePath, []byte(msg), 0644)
+}
@@ -41,11 +41,23 @@ type WantsNodesGetterAndSynced interface {
 	SetNodesGetterAndSynced(filter.NodesInPoolGetter, cache.InformerSynced, bool) error
 }
 
+// WantsPoolsInParentGetter is an interface for setting the getter of NodePools under the parent NodePool
+type WantsPoolsInParentGetter interface {
+	SetPoolsInParentGetter(filter.PoolsInParentGetter) error
+}
+
+// WantsCloudPoolsGetter is an interface for setting the getter of cloud NodePools
+type WantsCloudPoolsGetter interface {
+	SetCloudPoolsGetter(filter.CloudPoolsGetter) error
+}
+
 // imageCustomizationInitializer is responsible for initializing extra filters(except discardcloudservice, masterservice, servicetopology)
 type nodesInitializer struct {
 	enablePoolTopology bool
 	nodesGetter        filter.NodesInPoolGetter
 	nodesSynced        cache.InformerSynced
+	poolsGetter        filter.PoolsInParentGetter
+	cloudPoolsGetter   filter.CloudPoolsGetter
 }
 
 // NewNodesInitializer creates an filterInitializer object
@@ -72,10 +84,23 @@ func NewNodesInitializer(
 		}
 	}
 
+	poolsGetter := func(poolName string) ([]string, error) {
+		return []string{poolName}, nil
+	}
+	cloudPoolsGetter := func() ([]string, error) {
+		return []string{}, nil
+	}
+	if enableNodePool {
+		poolsGetter = createPoolsInParentGetter(dynamicInformerFactory)
+		cloudPoolsGetter = createCloudPoolsGetter(dynamicInformerFactory)
+	}
+
 	return &nodesInitializer{
 		enablePoolTopology: enablePoolTopology,
 		nodesGetter:        nodesGetter,
 		nodesSynced:        nodesSynced,
+		poolsGetter:        poolsGetter,
+		cloudPoolsGetter:   cloudPoolsGetter,
 	}
 }
 
@@ -153,11 +178,115 @@ func createNodeGetterAndSyncedByNodePool(
 	return nodesGetter, nodesSynced
 }
 
+func createPoolsInParentGetter(
+	dynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory,
+) filter.PoolsInParentGetter {
+	gvr := v1beta2.GroupVersion.WithResource("nodepools")
+	lister := dynamicInformerFactory.ForResource(gvr).Lister()
+	return func(poolName string) ([]string, error) {
+		runtimeObj, err := lister.Get(poolName)
+		if err != nil {
+			klog.Warningf("could not get nodepool %s, err: %v", poolName, err)
+			return nil, err
+		}
+		nodePool, err := toNodePool(runtimeObj)
+		if err != nil {
+			return nil, err
+		}
+		if len(nodePool.Spec.Parent) == 0 {
+			return []string{poolName}, nil
+		}
+
+		runtimeObjs, err := lister.List(labels.Everything())
+		if err != nil {
+			klog.Warningf("could not list nodepools, err: %v", err)
+			return nil, err
+		}
+		children := make(map[string][]string)
+		for i := range runtimeObjs {
+			np, err := toNodePool(runtimeObjs[i])
+			if err != nil {
+				return nil, err
+			}
+			children[np.Spec.Parent] = append(children[np.Spec.Parent], np.Name)
+		}
+
+		// collect the parent and all of its descendants
+		var pools []string
+		visited := make(map[string]bool)
+		queue := []string{nodePool.Spec.Parent}
+		for len(queue) != 0 {
+			pool := queue[0]
+			queue = queue[1:]
+			if visited[pool] {
+				continue
+			}
+			visited[pool] = true
+			pools = append(pools, pool)
+			queue = append(queue, children[pool]...)
+		}
+		return pools, nil
+	}
+}
+
+func createCloudPoolsGetter(
+	dynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory,
+) filter.CloudPoolsGetter {
+	gvr := v1beta2.GroupVersion.WithResource("nodepools")
+	lister := dynamicInformerFactory.ForResource(gvr).Lister()
+	return func() ([]string, error) {
+		runtimeObjs, err := lister.List(labels.Everything())
+		if err != nil {
+			klog.Warningf("could not list nodepools, err: %v", err)
+			return nil, err
+		}
+
+		var pools []string
+		for i := range runtimeObjs {
+			np, err := toNodePool(runtimeObjs[i])
+			if err != nil {
+				return nil, err
+			}
+			if np.Spec.Type == v1beta2.Cloud {
+				pools = append(pools, np.Name)
+			}
+		}
+		return pools, nil
+	}
+}
+
+func toNodePool(runtimeObj runtime.Object) (*v1beta2.NodePool, error) {
+	switch poolObj := runtimeObj.(type) {
+	case *v1beta2.NodePool:
+		return poolObj, nil
+	case *unstructured.Unstructured:
+		nodePool := new(v1beta2.NodePool)
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(poolObj.UnstructuredContent(), nodePool); err != nil {
+			klog.Warningf("object(%s) is not a v1beta2.NodePool, %v", poolObj.GetName(), err)
+			return nil, err
+		}
+		return nodePool, nil
+	default:
+		klog.Warningf("object(%s) is an unknown type", poolObj.GetObjectKind().GroupVersionKind().String())
+		return nil, errors.New("object is an unknown type")
+	}
+}
+
 func (ni *nodesInitializer) Initialize(ins filter.ObjectFilter) error {
 	if wants, ok := ins.(WantsNodesGetterAndSynced); ok {
 		if err := wants.SetNodesGetterAndSynced(ni.nodesGetter, ni.nodesSynced, ni.enablePoolTopology); err != nil {
 			return err
 		}
 	}
+	if wants, ok := ins.(WantsPoolsInParentGetter); ok {
+		if err := wants.SetPoolsInParentGetter(ni.poolsGetter); err != nil {
+			return err
+		}
+	}
+	if wants, ok := ins.(WantsCloudPoolsGetter); ok {
+		if err := wants.SetCloudPoolsGetter(ni.cloudPoolsGetter); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -26,6 +26,13 @@ import (
 
 type NodesInPoolGetter func(poolName string) ([]string, error)
 
+// PoolsInParentGetter returns the parent NodePool of specified NodePool and all descendants of the parent,
+// only the specified NodePool is returned if it has no parent.
+type PoolsInParentGetter func(poolName string) ([]string, error)
+
+// CloudPoolsGetter returns the NodePools of Cloud type.
+type CloudPoolsGetter func() ([]string, error)
+
 type Initializer interface {
 	Initialize(filter ObjectFilter) error
 }
@@ -55,6 +62,13 @@ type ObjectFilter interface {
 	Filter(obj runtime.Object, stopCh <-chan struct{}) runtime.Object
 }
 
+// ObjectObserver is implemented by ObjectFilters which keep the state of objects passing through them.
+// Observe is called with the objects in responses before they are filtered, the objects of a list are
+// all observed before any of them is filtered, and deleted is true for the objects of watch.Deleted events.
+type ObjectObserver interface {
+	Observe(obj runtime.Object, deleted bool)
+}
+
 type FilterFinder interface {
 	FindResponseFilter(req *http.Request) (ResponseFilter, bool)
 	FindObjectFilter(req *http.Request) (ObjectFilter, bool)
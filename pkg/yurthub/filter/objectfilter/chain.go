@@ -47,6 +47,14 @@ func (chain filterChain) SupportedResourceAndVerbs() map[string]sets.Set[string]
 	return map[string]sets.Set[string]{}
 }
 
+func (chain filterChain) Observe(obj runtime.Object, deleted bool) {
+	for i := range chain {
+		if observer, ok := chain[i].(filter.ObjectObserver); ok {
+			observer.Observe(obj, deleted)
+		}
+	}
+}
+
 func (chain filterChain) Filter(obj runtime.Object, stopCh <-chan struct{}) runtime.Object {
 	for i := range chain {
 		obj = chain[i].Filter(obj, stopCh)
@@ -136,6 +136,9 @@ func (frc *filterReadCloser) objectResponseFilter(rc io.ReadCloser) (*bytes.Buff
 		if err != nil || len(items) == 0 {
 			obj = frc.objectFilter.Filter(obj, frc.stopCh)
 		} else {
+			for i := range items {
+				frc.observe(items[i], false)
+			}
 			list := make([]runtime.Object, 0)
 			for i := range items {
 				newObj := frc.objectFilter.Filter(items[i], frc.stopCh)
@@ -149,6 +152,7 @@ func (frc *filterReadCloser) objectResponseFilter(rc io.ReadCloser) (*bytes.Buff
 			}
 		}
 	} else {
+		frc.observe(obj, false)
 		obj = frc.objectFilter.Filter(obj, frc.stopCh)
 	}
 	if yurtutil.IsNil(obj) {
@@ -178,6 +182,7 @@ func (frc *filterReadCloser) streamResponseFilter(rc io.ReadCloser, ch chan *byt
 		newObj := obj
 		// BOOKMARK and ERROR response are unnecessary to filter
 		if !(watchType == watch.Bookmark || watchType == watch.Error) {
+			frc.observe(obj, watchType == watch.Deleted)
 			if newObj = frc.objectFilter.Filter(obj, frc.stopCh); yurtutil.IsNil(newObj) {
 				// if an object is removed in the filter chain, it means that this object is not needed
 				// to return back to clients(like kube-proxy). but in order to update the client's local cache,
@@ -202,6 +207,13 @@ func (frc *filterReadCloser) streamResponseFilter(rc io.ReadCloser, ch chan *byt
 	}
 }
 
+// observe notifies the object filter of the object in response if it keeps the state of objects.
+func (frc *filterReadCloser) observe(obj runtime.Object, deleted bool) {
+	if observer, ok := frc.objectFilter.(filter.ObjectObserver); ok {
+		observer.Observe(obj, deleted)
+	}
+}
+
 func createSerializer(respContentType string, info *apirequest.RequestInfo, sm *serializer.SerializerManager) *serializer.Serializer {
 	if respContentType == "" || info == nil || info.APIVersion == "" || info.Resource == "" {
 		klog.Infof("CreateSerializer failed , info is :%+v", info)
@@ -18,12 +18,18 @@ package servicetopology
 
 import (
 	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
 
 	v1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
 	discoveryV1beta1 "k8s.io/api/discovery/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	listers "k8s.io/client-go/listers/core/v1"
@@ -44,6 +50,24 @@ const (
 	AnnotationServiceTopologyValueNode     = "kubernetes.io/hostname"
 	AnnotationServiceTopologyValueZone     = "kubernetes.io/zone"
 	AnnotationServiceTopologyValueNodePool = "openyurt.io/nodepool"
+	// AnnotationServiceTopologyValueParentNodePool means traffic is closed in the parent NodePool of
+	// the node's pool, e.g. the region that contains the site of node.
+	AnnotationServiceTopologyValueParentNodePool = "openyurt.io/parent-nodepool"
+	// AnnotationServiceTopologyValueCloud means traffic is closed in the cloud NodePools, it's only used as a fallback topology.
+	AnnotationServiceTopologyValueCloud = "openyurt.io/cloud"
+	// AnnotationServiceTopologyValueAll means traffic is not closed, it's only used as a fallback topology.
+	AnnotationServiceTopologyValueAll = "*"
+
+	// AnnotationServiceTopologyFallback specifies the ordered topologies which are used in turn when there is no ready
+	// endpoint in the topology of openyurt.io/topologyKeys, e.g. "openyurt.io/parent-nodepool,openyurt.io/cloud".
+	// Weighted alternatives can be separated by "|" in one fallback, e.g. "openyurt.io/parent-nodepool=3|openyurt.io/cloud=1",
+	// every node chooses one of the alternatives which have ready endpoints according to the weights, so traffic of nodes
+	// in the pool is distributed across the alternatives in proportion to their weights.
+	AnnotationServiceTopologyFallback = "openyurt.io/topology-fallback"
+
+	// outOfTopologyZone is the zone hint for endpoints out of the topology, kube-proxy will not route traffic
+	// to them unless there is no ready endpoint hinted for the zone of node.
+	outOfTopologyZone = "openyurt.io/out-of-topology"
 )
 
 // Register registers a filter
@@ -60,12 +84,23 @@ func NewServiceTopologyFilter() (filter.ObjectFilter, error) {
 type serviceTopologyFilter struct {
 	serviceLister      listers.ServiceLister
 	serviceSynced      cache.InformerSynced
+	nodeLister         listers.NodeLister
+	nodeSynced         cache.InformerSynced
 	enablePoolTopology bool
 	nodesGetter        filter.NodesInPoolGetter
 	nodesSynced        cache.InformerSynced
+	poolsGetter        filter.PoolsInParentGetter
+	cloudPoolsGetter   filter.CloudPoolsGetter
 	nodePoolName       string
 	nodeName           string
 	client             kubernetes.Interface
+
+	// readyNodes records the nodes of ready endpoints in every EndpointSlice of the services with
+	// topology, which are observed in responses, keyed by namespace/name of service and the name
+	// of EndpointSlice, so that the same topology is chosen for all EndpointSlices of a service
+	// without list/watching EndpointSlices of the whole cluster.
+	readyNodesLock sync.RWMutex
+	readyNodes     map[string]map[string]sets.Set[string]
 }
 
 func (stf *serviceTopologyFilter) Name() string {
@@ -73,11 +108,11 @@ func (stf *serviceTopologyFilter) Name() string {
 }
 
 func (stf *serviceTopologyFilter) HasSynced() bool {
-	if stf.nodesSynced == nil || stf.serviceSynced == nil {
+	if stf.nodesSynced == nil || stf.serviceSynced == nil || stf.nodeSynced == nil {
 		return false
 	}
 
-	if !stf.nodesSynced() || !stf.serviceSynced() {
+	if !stf.nodesSynced() || !stf.serviceSynced() || !stf.nodeSynced() {
 		return false
 	}
 
@@ -87,6 +122,9 @@ func (stf *serviceTopologyFilter) HasSynced() bool {
 func (stf *serviceTopologyFilter) SetSharedInformerFactory(factory informers.SharedInformerFactory) error {
 	stf.serviceLister = factory.Core().V1().Services().Lister()
 	stf.serviceSynced = factory.Core().V1().Services().Informer().HasSynced
+	// the node informer only list/watches the node of yurthub, it's used for resolving the zone of node.
+	stf.nodeLister = factory.Core().V1().Nodes().Lister()
+	stf.nodeSynced = factory.Core().V1().Nodes().Informer().HasSynced
 
 	return nil
 }
@@ -98,6 +136,16 @@ func (stf *serviceTopologyFilter) SetNodesGetterAndSynced(nodesGetter filter.Nod
 	return nil
 }
 
+func (stf *serviceTopologyFilter) SetPoolsInParentGetter(poolsGetter filter.PoolsInParentGetter) error {
+	stf.poolsGetter = poolsGetter
+	return nil
+}
+
+func (stf *serviceTopologyFilter) SetCloudPoolsGetter(cloudPoolsGetter filter.CloudPoolsGetter) error {
+	stf.cloudPoolsGetter = cloudPoolsGetter
+	return nil
+}
+
 func (stf *serviceTopologyFilter) SetNodeName(nodeName string) error {
 	stf.nodeName = nodeName
 
@@ -128,6 +176,52 @@ func (stf *serviceTopologyFilter) resolveNodePoolName() string {
 	return stf.nodePoolName
 }
 
+// resolveNodeZone returns the zone of node from the node informer, so the change of zone label takes effect.
+func (stf *serviceTopologyFilter) resolveNodeZone() string {
+	node, err := stf.nodeLister.Get(stf.nodeName)
+	if err != nil {
+		klog.Warningf("could not get node(%s) in serviceTopologyFilter filter, %v", stf.nodeName, err)
+		return ""
+	}
+	return node.Labels[v1.LabelTopologyZone]
+}
+
+// Observe records the nodes of ready endpoints in the EndpointSlice of services with topology,
+// the record is removed when the EndpointSlice is deleted.
+func (stf *serviceTopologyFilter) Observe(obj runtime.Object, deleted bool) {
+	var name string
+	switch v := obj.(type) {
+	case *discoveryV1beta1.EndpointSlice:
+		name = v.Name
+	case *discoveryv1.EndpointSlice:
+		name = v.Name
+	default:
+		return
+	}
+	svc := stf.resolveService(obj)
+	if svc == nil {
+		return
+	}
+	key := svc.Namespace + "/" + svc.Name
+
+	stf.readyNodesLock.Lock()
+	defer stf.readyNodesLock.Unlock()
+	if deleted || len(svc.Annotations[AnnotationServiceTopologyKey]) == 0 {
+		delete(stf.readyNodes[key], name)
+		if len(stf.readyNodes[key]) == 0 {
+			delete(stf.readyNodes, key)
+		}
+		return
+	}
+	if stf.readyNodes == nil {
+		stf.readyNodes = make(map[string]map[string]sets.Set[string])
+	}
+	if stf.readyNodes[key] == nil {
+		stf.readyNodes[key] = make(map[string]sets.Set[string])
+	}
+	stf.readyNodes[key][name] = readyEndpointNodes(obj)
+}
+
 func (stf *serviceTopologyFilter) Filter(obj runtime.Object, stopCh <-chan struct{}) runtime.Object {
 	switch v := obj.(type) {
 	case *v1.Endpoints, *discoveryV1beta1.EndpointSlice, *discoveryv1.EndpointSlice:
@@ -138,27 +232,81 @@ func (stf *serviceTopologyFilter) Filter(obj runtime.Object, stopCh <-chan struc
 }
 
 func (stf *serviceTopologyFilter) serviceTopologyHandler(obj runtime.Object) runtime.Object {
-	serviceTopologyType := stf.resolveServiceTopologyType(obj)
-	if len(serviceTopologyType) == 0 {
+	svc := stf.resolveService(obj)
+	if svc == nil || len(svc.Annotations[AnnotationServiceTopologyKey]) == 0 {
 		return obj
 	}
 
-	switch serviceTopologyType {
-	case AnnotationServiceTopologyValueNode:
-		// close traffic on the same node
-		return stf.nodeTopologyHandler(obj)
-	case AnnotationServiceTopologyValueNodePool, AnnotationServiceTopologyValueZone:
-		// close traffic on the same node pool
-		if stf.enablePoolTopology {
-			return stf.nodePoolTopologyHandler(obj)
+	// the scope of topologyKeys is preferred, and the fallback scopes are used in turn
+	// when there is no ready endpoint of the service in the former scopes.
+	fallbacks := append([][]FallbackScope{{{Scope: svc.Annotations[AnnotationServiceTopologyKey], Weight: 1}}},
+		ParseFallbackScopes(svc.Annotations[AnnotationServiceTopologyFallback])...)
+	for i, fallback := range fallbacks {
+		var resolved, candidates []scopeNodes
+		for _, fs := range fallback {
+			nodes, all, err := stf.resolveNodesInScope(fs.Scope)
+			if err != nil {
+				klog.Warningf("serviceTopologyFilter: %v", err)
+				return obj
+			}
+			sn := scopeNodes{FallbackScope: fs, nodes: nodes, all: all}
+			resolved = append(resolved, sn)
+			if all || stf.hasReadyEndpoints(svc, obj, nodes) {
+				candidates = append(candidates, sn)
+			}
 		}
-		return obj
-	default:
-		return obj
+
+		// the last fallback is used even if there is no ready endpoint in it
+		if len(candidates) == 0 && i == len(fallbacks)-1 {
+			candidates = resolved
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		chosen := chooseScope(candidates, strings.Join([]string{stf.nodeName, svc.Namespace, svc.Name}, "/"))
+		if i != 0 {
+			klog.V(4).Infof("serviceTopologyFilter: no ready endpoints in the preferred topology of service %s/%s, fall back to %s", svc.Namespace, svc.Name, chosen.Scope)
+		}
+		if chosen.all {
+			return obj
+		}
+		return stf.reassemble(obj, chosen.nodes, svc)
+	}
+	return obj
+}
+
+// scopeNodes is a fallback scope with the nodes in it, all is true if endpoints should not be filtered for the scope.
+type scopeNodes struct {
+	FallbackScope
+	nodes []string
+	all   bool
+}
+
+// chooseScope chooses one of the scopes according to their weights. The choice is stable for the
+// same key, so a node always routes traffic of a service to the same scope.
+func chooseScope(scopes []scopeNodes, key string) scopeNodes {
+	if len(scopes) == 1 {
+		return scopes[0]
+	}
+
+	total := 0
+	for _, scope := range scopes {
+		total += scope.Weight
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	n := int(h.Sum32() % uint32(total))
+	for _, scope := range scopes {
+		if n < scope.Weight {
+			return scope
+		}
+		n -= scope.Weight
 	}
+	return scopes[len(scopes)-1]
 }
 
-func (stf *serviceTopologyFilter) resolveServiceTopologyType(obj runtime.Object) string {
+func (stf *serviceTopologyFilter) resolveService(obj runtime.Object) *v1.Service {
 	var svcNamespace, svcName string
 	switch v := obj.(type) {
 	case *discoveryV1beta1.EndpointSlice:
@@ -171,47 +319,96 @@ func (stf *serviceTopologyFilter) resolveServiceTopologyType(obj runtime.Object)
 		svcNamespace = v.Namespace
 		svcName = v.Name
 	default:
-		return ""
+		return nil
 	}
 
 	svc, err := stf.serviceLister.Services(svcNamespace).Get(svcName)
 	if err != nil {
 		klog.Warningf("serviceTopologyFilterHandler: could not get service %s/%s, err: %v", svcNamespace, svcName, err)
-		return ""
-	}
-
-	if svc.Annotations != nil {
-		return svc.Annotations[AnnotationServiceTopologyKey]
+		return nil
 	}
-	return ""
+	return svc
 }
 
-func (stf *serviceTopologyFilter) nodeTopologyHandler(obj runtime.Object) runtime.Object {
-	switch v := obj.(type) {
-	case *discoveryV1beta1.EndpointSlice:
-		return reassembleV1beta1EndpointSlice(v, stf.nodeName, nil)
-	case *discoveryv1.EndpointSlice:
-		return reassembleEndpointSlice(v, stf.nodeName, nil)
-	case *v1.Endpoints:
-		return reassembleEndpoints(v, stf.nodeName, nil)
+// resolveNodesInScope returns the nodes in the specified topology scope, and true is returned
+// if endpoints should not be filtered for the scope.
+func (stf *serviceTopologyFilter) resolveNodesInScope(scope string) ([]string, bool, error) {
+	switch scope {
+	case AnnotationServiceTopologyValueNode:
+		// close traffic on the same node
+		return []string{stf.nodeName}, false, nil
+	case AnnotationServiceTopologyValueNodePool, AnnotationServiceTopologyValueZone, AnnotationServiceTopologyValueParentNodePool:
+		if !stf.enablePoolTopology {
+			return nil, true, nil
+		}
+
+		nodePoolName := stf.resolveNodePoolName()
+		if len(nodePoolName) == 0 {
+			klog.Infof("node(%s) is not added into node pool, so fall into node topology", stf.nodeName)
+			return []string{stf.nodeName}, false, nil
+		}
+
+		// close traffic on the same node pool
+		pools := []string{nodePoolName}
+		if scope == AnnotationServiceTopologyValueParentNodePool && stf.poolsGetter != nil {
+			// close traffic in the parent node pool
+			var err error
+			pools, err = stf.poolsGetter(nodePoolName)
+			if err != nil {
+				return nil, false, fmt.Errorf("could not get node pools in parent of node pool %s, err: %w", nodePoolName, err)
+			}
+		}
+		nodes, err := stf.nodesInPools(pools, nodePoolName)
+		return nodes, false, err
+	case AnnotationServiceTopologyValueCloud:
+		if !stf.enablePoolTopology || stf.cloudPoolsGetter == nil {
+			return nil, true, nil
+		}
+
+		pools, err := stf.cloudPoolsGetter()
+		if err != nil {
+			return nil, false, fmt.Errorf("could not get cloud node pools, err: %w", err)
+		}
+		nodes, err := stf.nodesInPools(pools, "")
+		return nodes, false, err
 	default:
-		return obj
+		return nil, true, nil
 	}
 }
 
-func (stf *serviceTopologyFilter) nodePoolTopologyHandler(obj runtime.Object) runtime.Object {
-	nodePoolName := stf.resolveNodePoolName()
-	if len(nodePoolName) == 0 {
-		klog.Infof("node(%s) is not added into node pool, so fall into node topology", stf.nodeName)
-		return stf.nodeTopologyHandler(obj)
+// nodesInPools returns nodes in the specified node pools, pools which have no nodes are skipped
+// except the pool of current node.
+func (stf *serviceTopologyFilter) nodesInPools(pools []string, nodePoolName string) ([]string, error) {
+	nodes := []string{}
+	for _, pool := range pools {
+		poolNodes, err := stf.nodesGetter(pool)
+		if err != nil {
+			if pool == nodePoolName {
+				return nil, fmt.Errorf("could not get nodes for node pool %s, err: %w", pool, err)
+			}
+			// node pools which only contain child pools may have no nodes
+			klog.V(4).Infof("serviceTopologyFilter: skip node pool %s, %v", pool, err)
+			continue
+		}
+		nodes = append(nodes, poolNodes...)
 	}
+	return nodes, nil
+}
 
-	nodes, err := stf.nodesGetter(nodePoolName)
-	if err != nil {
-		klog.Warningf("serviceTopologyFilter: could not get nodes for node pool %s, err: %v", nodePoolName, err)
-		return obj
+// reassemble discards endpoints that are not on the specified nodes. Endpoints of v1.EndpointSlice are
+// kept with zone hints instead if topology aware hints is enabled for the service and the zone of node is known,
+// so kube-proxy is able to fall back to all endpoints when there is no ready endpoint on the specified nodes.
+func (stf *serviceTopologyFilter) reassemble(obj runtime.Object, nodes []string, svc *v1.Service) runtime.Object {
+	if endpointSlice, ok := obj.(*discoveryv1.EndpointSlice); ok && isTopologyAwareHintsEnabled(svc) {
+		if zone := stf.resolveNodeZone(); len(zone) != 0 {
+			return hintEndpointSlice(endpointSlice, nodes, zone)
+		}
 	}
+	return reassembleByNodes(obj, nodes)
+}
 
+// reassembleByNodes will discard endpoints that are not on the specified nodes
+func reassembleByNodes(obj runtime.Object, nodes []string) runtime.Object {
 	switch v := obj.(type) {
 	case *discoveryV1beta1.EndpointSlice:
 		return reassembleV1beta1EndpointSlice(v, "", nodes)
@@ -331,6 +528,136 @@ func filterValidEndpointsAddr(addresses []v1.EndpointAddress, nodeName string, n
 	return newEpAddresses
 }
 
+// hintEndpointSlice sets zone hints for endpoints of v1.EndpointSlice, endpoints on the specified nodes
+// are hinted for the zone of current node, and other endpoints are hinted for a zone that doesn't exist.
+func hintEndpointSlice(endpointSlice *discoveryv1.EndpointSlice, nodes []string, zone string) *discoveryv1.EndpointSlice {
+	for i := range endpointSlice.Endpoints {
+		hintZone := outOfTopologyZone
+		if endpointSlice.Endpoints[i].NodeName != nil && inSameNodePool(*endpointSlice.Endpoints[i].NodeName, nodes) {
+			hintZone = zone
+		}
+		endpointSlice.Endpoints[i].Hints = &discoveryv1.EndpointHints{
+			ForZones: []discoveryv1.ForZone{{Name: hintZone}},
+		}
+	}
+	return endpointSlice
+}
+
+// hasReadyEndpoints checks whether the service has ready endpoints on the specified nodes. The other EndpointSlices
+// of the service observed in responses are checked too, so that the same topology is chosen for every EndpointSlice
+// of the service.
+func (stf *serviceTopologyFilter) hasReadyEndpoints(svc *v1.Service, obj runtime.Object, nodes []string) bool {
+	readyNodes := readyEndpointNodes(obj)
+	if readyNodes.HasAny(nodes...) {
+		return true
+	}
+
+	var name string
+	switch v := obj.(type) {
+	case *discoveryV1beta1.EndpointSlice:
+		name = v.Name
+	case *discoveryv1.EndpointSlice:
+		name = v.Name
+	default:
+		return false
+	}
+
+	stf.readyNodesLock.RLock()
+	defer stf.readyNodesLock.RUnlock()
+	for sliceName, readyNodes := range stf.readyNodes[svc.Namespace+"/"+svc.Name] {
+		// the EndpointSlice in response is newer than the one observed
+		if sliceName == name {
+			continue
+		}
+		if readyNodes.HasAny(nodes...) {
+			return true
+		}
+	}
+	return false
+}
+
+// readyEndpointNodes returns the nodes of ready endpoints
+func readyEndpointNodes(obj runtime.Object) sets.Set[string] {
+	nodes := sets.New[string]()
+	switch v := obj.(type) {
+	case *discoveryV1beta1.EndpointSlice:
+		for i := range v.Endpoints {
+			ready := v.Endpoints[i].Conditions.Ready == nil || *v.Endpoints[i].Conditions.Ready
+			if nodeName := v.Endpoints[i].Topology[v1.LabelHostname]; ready && len(nodeName) != 0 {
+				nodes.Insert(nodeName)
+			}
+		}
+	case *discoveryv1.EndpointSlice:
+		for i := range v.Endpoints {
+			ready := v.Endpoints[i].Conditions.Ready == nil || *v.Endpoints[i].Conditions.Ready
+			if ready && v.Endpoints[i].NodeName != nil {
+				nodes.Insert(*v.Endpoints[i].NodeName)
+			}
+		}
+	case *v1.Endpoints:
+		for i := range v.Subsets {
+			for j := range v.Subsets[i].Addresses {
+				if v.Subsets[i].Addresses[j].NodeName != nil {
+					nodes.Insert(*v.Subsets[i].Addresses[j].NodeName)
+				}
+			}
+		}
+	}
+	return nodes
+}
+
+// FallbackScope is a topology scope in openyurt.io/topology-fallback annotation with its weight
+type FallbackScope struct {
+	Scope  string
+	Weight int
+}
+
+// ParseFallbackScopes parses the value of openyurt.io/topology-fallback annotation into ordered fallbacks,
+// every fallback contains one or more weighted scopes. Unknown scopes and invalid weights are ignored.
+func ParseFallbackScopes(value string) [][]FallbackScope {
+	var fallbacks [][]FallbackScope
+	for _, item := range strings.Split(value, ",") {
+		var fallback []FallbackScope
+		for _, alternative := range strings.Split(item, "|") {
+			scope, weightStr, hasWeight := strings.Cut(strings.TrimSpace(alternative), "=")
+			scope = strings.TrimSpace(scope)
+			if len(scope) == 0 {
+				continue
+			}
+
+			weight := 1
+			if hasWeight {
+				w, err := strconv.Atoi(strings.TrimSpace(weightStr))
+				if err != nil || w <= 0 {
+					klog.Warningf("serviceTopologyFilter: fallback topology %q with invalid weight %q is ignored", scope, weightStr)
+					continue
+				}
+				weight = w
+			}
+
+			switch scope {
+			case AnnotationServiceTopologyValueNode, AnnotationServiceTopologyValueNodePool, AnnotationServiceTopologyValueZone,
+				AnnotationServiceTopologyValueParentNodePool, AnnotationServiceTopologyValueCloud, AnnotationServiceTopologyValueAll:
+				fallback = append(fallback, FallbackScope{Scope: scope, Weight: weight})
+			default:
+				klog.Warningf("serviceTopologyFilter: unknown fallback topology %q is ignored", scope)
+			}
+		}
+		if len(fallback) != 0 {
+			fallbacks = append(fallbacks, fallback)
+		}
+	}
+	return fallbacks
+}
+
+func isTopologyAwareHintsEnabled(svc *v1.Service) bool {
+	mode, ok := svc.Annotations[v1.AnnotationTopologyMode]
+	if !ok {
+		mode = svc.Annotations[v1.DeprecatedAnnotationTopologyAwareHints]
+	}
+	return mode == "Auto" || mode == "auto"
+}
+
 func inSameNodePool(nodeName string, nodeList []string) bool {
 	for _, n := range nodeList {
 		if nodeName == n {
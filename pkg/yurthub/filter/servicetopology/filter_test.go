@@ -17,6 +17,8 @@ limitations under the License.
 package servicetopology
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -72,6 +74,7 @@ func TestFilter(t *testing.T) {
 	currentNodeName := "node1"
 	nodeName2 := "node2"
 	nodeName3 := "node3"
+	notReady := false
 
 	testcases := map[string]struct {
 		enableNodePool            bool
@@ -1448,6 +1451,654 @@ func TestFilter(t *testing.T) {
 				},
 			},
 		},
+		"v1.EndpointSlice: topologyKeys is openyurt.io/parent-nodepool": {
+			enableNodePool: true,
+			responseObject: &discovery.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc1-np7sf",
+					Namespace: "default",
+					Labels: map[string]string{
+						discovery.LabelServiceName: "svc1",
+					},
+				},
+				Endpoints: []discovery.Endpoint{
+					{
+						Addresses: []string{
+							"10.244.1.2",
+						},
+						NodeName: &currentNodeName,
+					},
+					{
+						Addresses: []string{
+							"10.244.1.3",
+						},
+						NodeName: &nodeName2,
+					},
+					{
+						Addresses: []string{
+							"10.244.1.5",
+						},
+						NodeName: &nodeName3,
+					},
+				},
+			},
+			kubeClient: k8sfake.NewSimpleClientset(
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: currentNodeName,
+						Labels: map[string]string{
+							projectinfo.GetNodePoolLabel(): "hangzhou",
+						},
+					},
+				},
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "svc1",
+						Namespace: "default",
+						Annotations: map[string]string{
+							AnnotationServiceTopologyKey: AnnotationServiceTopologyValueParentNodePool,
+						},
+					},
+				},
+			),
+			yurtClient: fake.NewSimpleDynamicClientWithCustomListKinds(scheme, gvrToListKind,
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "east",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type: v1beta2.Edge,
+					},
+				},
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "hangzhou",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type:   v1beta2.Edge,
+						Parent: "east",
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							currentNodeName,
+						},
+					},
+				},
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "ningbo",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type:   v1beta2.Edge,
+						Parent: "east",
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							"node2",
+						},
+					},
+				},
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "beijing",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type: v1beta2.Edge,
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							"node3",
+						},
+					},
+				},
+			),
+			expectObject: &discovery.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc1-np7sf",
+					Namespace: "default",
+					Labels: map[string]string{
+						discovery.LabelServiceName: "svc1",
+					},
+				},
+				Endpoints: []discovery.Endpoint{
+					{
+						Addresses: []string{
+							"10.244.1.2",
+						},
+						NodeName: &currentNodeName,
+					},
+					{
+						Addresses: []string{
+							"10.244.1.3",
+						},
+						NodeName: &nodeName2,
+					},
+				},
+			},
+		},
+		"v1.EndpointSlice: fall back to parent nodepool when no ready endpoints in nodepool": {
+			enableNodePool: true,
+			responseObject: &discovery.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc1-np7sf",
+					Namespace: "default",
+					Labels: map[string]string{
+						discovery.LabelServiceName: "svc1",
+					},
+				},
+				Endpoints: []discovery.Endpoint{
+					{
+						Addresses: []string{
+							"10.244.1.2",
+						},
+						NodeName: &currentNodeName,
+						Conditions: discovery.EndpointConditions{
+							Ready: &notReady,
+						},
+					},
+					{
+						Addresses: []string{
+							"10.244.1.3",
+						},
+						NodeName: &nodeName2,
+					},
+					{
+						Addresses: []string{
+							"10.244.1.5",
+						},
+						NodeName: &nodeName3,
+					},
+				},
+			},
+			kubeClient: k8sfake.NewSimpleClientset(
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: currentNodeName,
+						Labels: map[string]string{
+							projectinfo.GetNodePoolLabel(): "hangzhou",
+						},
+					},
+				},
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "svc1",
+						Namespace: "default",
+						Annotations: map[string]string{
+							AnnotationServiceTopologyKey:      AnnotationServiceTopologyValueNodePool,
+							AnnotationServiceTopologyFallback: "openyurt.io/parent-nodepool, openyurt.io/cloud",
+						},
+					},
+				},
+			),
+			yurtClient: fake.NewSimpleDynamicClientWithCustomListKinds(scheme, gvrToListKind,
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "hangzhou",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type:   v1beta2.Edge,
+						Parent: "east",
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							currentNodeName,
+						},
+					},
+				},
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "ningbo",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type:   v1beta2.Edge,
+						Parent: "east",
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							"node2",
+						},
+					},
+				},
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cloud",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type: v1beta2.Cloud,
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							"node3",
+						},
+					},
+				},
+			),
+			expectObject: &discovery.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc1-np7sf",
+					Namespace: "default",
+					Labels: map[string]string{
+						discovery.LabelServiceName: "svc1",
+					},
+				},
+				Endpoints: []discovery.Endpoint{
+					{
+						Addresses: []string{
+							"10.244.1.2",
+						},
+						NodeName: &currentNodeName,
+						Conditions: discovery.EndpointConditions{
+							Ready: &notReady,
+						},
+					},
+					{
+						Addresses: []string{
+							"10.244.1.3",
+						},
+						NodeName: &nodeName2,
+					},
+				},
+			},
+		},
+		"v1.EndpointSlice: no fall back when other endpointslice of service has ready endpoints in nodepool": {
+			enableNodePool: true,
+			responseObject: &discovery.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc1-np7sf",
+					Namespace: "default",
+					Labels: map[string]string{
+						discovery.LabelServiceName: "svc1",
+					},
+				},
+				Endpoints: []discovery.Endpoint{
+					{
+						Addresses: []string{
+							"10.244.1.2",
+						},
+						NodeName: &currentNodeName,
+						Conditions: discovery.EndpointConditions{
+							Ready: &notReady,
+						},
+					},
+					{
+						Addresses: []string{
+							"10.244.1.3",
+						},
+						NodeName: &nodeName2,
+					},
+					{
+						Addresses: []string{
+							"10.244.1.5",
+						},
+						NodeName: &nodeName3,
+					},
+				},
+			},
+			kubeClient: k8sfake.NewSimpleClientset(
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: currentNodeName,
+						Labels: map[string]string{
+							projectinfo.GetNodePoolLabel(): "hangzhou",
+						},
+					},
+				},
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "svc1",
+						Namespace: "default",
+						Annotations: map[string]string{
+							AnnotationServiceTopologyKey:      AnnotationServiceTopologyValueNodePool,
+							AnnotationServiceTopologyFallback: "openyurt.io/parent-nodepool, openyurt.io/cloud",
+						},
+					},
+				},
+
+				&discovery.EndpointSlice{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "svc1-xk2lp",
+						Namespace: "default",
+						Labels: map[string]string{
+							discovery.LabelServiceName: "svc1",
+						},
+					},
+					Endpoints: []discovery.Endpoint{
+						{
+							Addresses: []string{
+								"10.244.1.6",
+							},
+							NodeName: &currentNodeName,
+						},
+					},
+				},
+			),
+			yurtClient: fake.NewSimpleDynamicClientWithCustomListKinds(scheme, gvrToListKind,
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "hangzhou",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type:   v1beta2.Edge,
+						Parent: "east",
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							currentNodeName,
+						},
+					},
+				},
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "ningbo",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type:   v1beta2.Edge,
+						Parent: "east",
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							"node2",
+						},
+					},
+				},
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cloud",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type: v1beta2.Cloud,
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							"node3",
+						},
+					},
+				},
+			),
+			expectObject: &discovery.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc1-np7sf",
+					Namespace: "default",
+					Labels: map[string]string{
+						discovery.LabelServiceName: "svc1",
+					},
+				},
+				Endpoints: []discovery.Endpoint{
+					{
+						Addresses: []string{
+							"10.244.1.2",
+						},
+						NodeName: &currentNodeName,
+						Conditions: discovery.EndpointConditions{
+							Ready: &notReady,
+						},
+					},
+				},
+			},
+		},
+		"v1.EndpointSlice: fall back to cloud when no ready endpoints in nodepool": {
+			enableNodePool: true,
+			responseObject: &discovery.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc1-np7sf",
+					Namespace: "default",
+					Labels: map[string]string{
+						discovery.LabelServiceName: "svc1",
+					},
+				},
+				Endpoints: []discovery.Endpoint{
+					{
+						Addresses: []string{
+							"10.244.1.2",
+						},
+						NodeName: &currentNodeName,
+						Conditions: discovery.EndpointConditions{
+							Ready: &notReady,
+						},
+					},
+					{
+						Addresses: []string{
+							"10.244.1.3",
+						},
+						NodeName: &nodeName2,
+					},
+					{
+						Addresses: []string{
+							"10.244.1.5",
+						},
+						NodeName: &nodeName3,
+					},
+				},
+			},
+			kubeClient: k8sfake.NewSimpleClientset(
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: currentNodeName,
+						Labels: map[string]string{
+							projectinfo.GetNodePoolLabel(): "hangzhou",
+						},
+					},
+				},
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "svc1",
+						Namespace: "default",
+						Annotations: map[string]string{
+							AnnotationServiceTopologyKey:      AnnotationServiceTopologyValueNodePool,
+							AnnotationServiceTopologyFallback: "openyurt.io/cloud",
+						},
+					},
+				},
+			),
+			yurtClient: fake.NewSimpleDynamicClientWithCustomListKinds(scheme, gvrToListKind,
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "hangzhou",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type: v1beta2.Edge,
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							currentNodeName,
+						},
+					},
+				},
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "shanghai",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type: v1beta2.Edge,
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							"node2",
+						},
+					},
+				},
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cloud",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type: v1beta2.Cloud,
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							"node3",
+						},
+					},
+				},
+			),
+			expectObject: &discovery.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc1-np7sf",
+					Namespace: "default",
+					Labels: map[string]string{
+						discovery.LabelServiceName: "svc1",
+					},
+				},
+				Endpoints: []discovery.Endpoint{
+					{
+						Addresses: []string{
+							"10.244.1.5",
+						},
+						NodeName: &nodeName3,
+					},
+				},
+			},
+		},
+		"v1.EndpointSlice: endpoints are hinted when topology aware hints is enabled": {
+			enableNodePool: true,
+			responseObject: &discovery.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc1-np7sf",
+					Namespace: "default",
+					Labels: map[string]string{
+						discovery.LabelServiceName: "svc1",
+					},
+				},
+				Endpoints: []discovery.Endpoint{
+					{
+						Addresses: []string{
+							"10.244.1.2",
+						},
+						NodeName: &currentNodeName,
+					},
+					{
+						Addresses: []string{
+							"10.244.1.3",
+						},
+						NodeName: &nodeName2,
+					},
+				},
+			},
+			kubeClient: k8sfake.NewSimpleClientset(
+				&corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: currentNodeName,
+						Labels: map[string]string{
+							projectinfo.GetNodePoolLabel(): "hangzhou",
+							corev1.LabelTopologyZone:       "zone-a",
+						},
+					},
+				},
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "svc1",
+						Namespace: "default",
+						Annotations: map[string]string{
+							AnnotationServiceTopologyKey:  AnnotationServiceTopologyValueNodePool,
+							corev1.AnnotationTopologyMode: "Auto",
+						},
+					},
+				},
+			),
+			yurtClient: fake.NewSimpleDynamicClientWithCustomListKinds(scheme, gvrToListKind,
+				&v1beta2.NodePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "hangzhou",
+					},
+					Spec: v1beta2.NodePoolSpec{
+						Type: v1beta2.Edge,
+					},
+					Status: v1beta2.NodePoolStatus{
+						Nodes: []string{
+							currentNodeName,
+						},
+					},
+				},
+			),
+			expectObject: &discovery.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc1-np7sf",
+					Namespace: "default",
+					Labels: map[string]string{
+						discovery.LabelServiceName: "svc1",
+					},
+				},
+				Endpoints: []discovery.Endpoint{
+					{
+						Addresses: []string{
+							"10.244.1.2",
+						},
+						NodeName: &currentNodeName,
+						Hints: &discovery.EndpointHints{
+							ForZones: []discovery.ForZone{{Name: "zone-a"}},
+						},
+					},
+					{
+						Addresses: []string{
+							"10.244.1.3",
+						},
+						NodeName: &nodeName2,
+						Hints: &discovery.EndpointHints{
+							ForZones: []discovery.ForZone{{Name: outOfTopologyZone}},
+						},
+					},
+				},
+			},
+		},
+		"v1.Endpoints: fall back to all endpoints when no ready endpoints on node": {
+			responseObject: &corev1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc1",
+					Namespace: "default",
+				},
+				Subsets: []corev1.EndpointSubset{
+					{
+						Addresses: []corev1.EndpointAddress{
+							{
+								IP:       "10.244.1.3",
+								NodeName: &nodeName2,
+							},
+						},
+						NotReadyAddresses: []corev1.EndpointAddress{
+							{
+								IP:       "10.244.1.2",
+								NodeName: &currentNodeName,
+							},
+						},
+					},
+				},
+			},
+			kubeClient: k8sfake.NewSimpleClientset(
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "svc1",
+						Namespace: "default",
+						Annotations: map[string]string{
+							AnnotationServiceTopologyKey:      AnnotationServiceTopologyValueNode,
+							AnnotationServiceTopologyFallback: AnnotationServiceTopologyValueAll,
+						},
+					},
+				},
+			),
+			yurtClient: fake.NewSimpleDynamicClientWithCustomListKinds(scheme, gvrToListKind),
+			expectObject: &corev1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "svc1",
+					Namespace: "default",
+				},
+				Subsets: []corev1.EndpointSubset{
+					{
+						Addresses: []corev1.EndpointAddress{
+							{
+								IP:       "10.244.1.3",
+								NodeName: &nodeName2,
+							},
+						},
+						NotReadyAddresses: []corev1.EndpointAddress{
+							{
+								IP:       "10.244.1.2",
+								NodeName: &currentNodeName,
+							},
+						},
+					},
+				},
+			},
+		},
 		"v1.EndpointSlice: topologyKeys is kubernetes.io/zone": {
 			enableNodePool: true,
 			responseObject: &discovery.EndpointSlice{
@@ -3339,6 +3990,15 @@ func TestFilter(t *testing.T) {
 			yurtFactory.Start(stopper2)
 			yurtFactory.WaitForCacheSync(stopper2)
 
+			// the other EndpointSlices of service have been observed in responses
+			endpointSlices, err := tt.kubeClient.DiscoveryV1().EndpointSlices("").List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("could not list endpointslices, %v", err)
+			}
+			for i := range endpointSlices.Items {
+				stf.Observe(&endpointSlices.Items[i], false)
+			}
+
 			stopCh := make(<-chan struct{})
 			newObj := stf.Filter(tt.responseObject, stopCh)
 			if util.IsNil(newObj) {
@@ -3350,3 +4010,117 @@ func TestFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFallbackScopes(t *testing.T) {
+	testcases := map[string]struct {
+		value  string
+		expect [][]FallbackScope
+	}{
+		"empty value": {},
+		"ordered fallbacks": {
+			value: "openyurt.io/parent-nodepool, openyurt.io/cloud",
+			expect: [][]FallbackScope{
+				{{Scope: AnnotationServiceTopologyValueParentNodePool, Weight: 1}},
+				{{Scope: AnnotationServiceTopologyValueCloud, Weight: 1}},
+			},
+		},
+		"weighted alternatives": {
+			value: "openyurt.io/parent-nodepool=3 | openyurt.io/cloud=1,*",
+			expect: [][]FallbackScope{
+				{{Scope: AnnotationServiceTopologyValueParentNodePool, Weight: 3}, {Scope: AnnotationServiceTopologyValueCloud, Weight: 1}},
+				{{Scope: AnnotationServiceTopologyValueAll, Weight: 1}},
+			},
+		},
+		"unknown scopes and invalid weights are ignored": {
+			value: "foo,openyurt.io/cloud=0|openyurt.io/parent-nodepool=a|openyurt.io/nodepool",
+			expect: [][]FallbackScope{
+				{{Scope: AnnotationServiceTopologyValueNodePool, Weight: 1}},
+			},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			if got := ParseFallbackScopes(tc.value); !reflect.DeepEqual(got, tc.expect) {
+				t.Errorf("expect fallbacks %v, but got %v", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestChooseScope(t *testing.T) {
+	scopes := []scopeNodes{
+		{FallbackScope: FallbackScope{Scope: AnnotationServiceTopologyValueParentNodePool, Weight: 3}},
+		{FallbackScope: FallbackScope{Scope: AnnotationServiceTopologyValueCloud, Weight: 1}},
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("node%d/default/svc1", i)
+		chosen := chooseScope(scopes, key)
+		if again := chooseScope(scopes, key); again.Scope != chosen.Scope {
+			t.Fatalf("expect stable choice for %s, but got %s and %s", key, chosen.Scope, again.Scope)
+		}
+		counts[chosen.Scope]++
+	}
+
+	if n := counts[AnnotationServiceTopologyValueParentNodePool]; n < 650 || n > 850 {
+		t.Errorf("expect about 750 nodes choose parent nodepool, but got %d", n)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	nodeName := "node1"
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "svc1",
+			Namespace: "default",
+			Annotations: map[string]string{
+				AnnotationServiceTopologyKey: AnnotationServiceTopologyValueNodePool,
+			},
+		},
+	}
+	newEndpointSlice := func(name string) *discovery.EndpointSlice {
+		return &discovery.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					discovery.LabelServiceName: "svc1",
+				},
+			},
+		}
+	}
+	ready := newEndpointSlice("svc1-ready")
+	ready.Endpoints = []discovery.Endpoint{{Addresses: []string{"10.244.1.2"}, NodeName: &nodeName}}
+	empty := newEndpointSlice("svc1-empty")
+
+	client := k8sfake.NewSimpleClientset(svc)
+	factory := informers.NewSharedInformerFactory(client, 24*time.Hour)
+	stf := &serviceTopologyFilter{}
+	stf.SetSharedInformerFactory(factory)
+	stopper := make(chan struct{})
+	defer close(stopper)
+	factory.Start(stopper)
+	factory.WaitForCacheSync(stopper)
+
+	if stf.hasReadyEndpoints(svc, empty, []string{nodeName}) {
+		t.Errorf("expect no ready endpoints before other endpointslices are observed")
+	}
+
+	stf.Observe(ready, false)
+	if !stf.hasReadyEndpoints(svc, empty, []string{nodeName}) {
+		t.Errorf("expect ready endpoints in the observed endpointslice")
+	}
+	if stf.hasReadyEndpoints(svc, empty, []string{"node2"}) {
+		t.Errorf("expect no ready endpoints on other nodes")
+	}
+
+	stf.Observe(ready, true)
+	if stf.hasReadyEndpoints(svc, empty, []string{nodeName}) {
+		t.Errorf("expect no ready endpoints after the endpointslice is deleted")
+	}
+	if len(stf.readyNodes) != 0 {
+		t.Errorf("expect records of service are removed, but got %v", stf.readyNodes)
+	}
+}
@@ -19,6 +19,7 @@ package cloudapiserver
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	coordinationv1 "k8s.io/api/coordination/v1"
@@ -30,6 +31,8 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
 	"k8s.io/utils/ptr"
+
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
 )
 
 const (
@@ -47,6 +50,9 @@ type nodeLeaseImpl struct {
 	leaseDurationSeconds int32
 	failedRetry          int
 	clock                clock.Clock
+	// latency is the duration of the latest successful lease update, it's recorded
+	// in the annotation of node lease and used by weighted hub leader election.
+	latency time.Duration
 }
 
 func NewNodeLease(client clientset.Interface, holderIdentity string, leaseDurationSeconds int32, failedRetry int) NodeLease {
@@ -81,8 +87,10 @@ func (nl *nodeLeaseImpl) retryUpdateLease(base *coordinationv1.Lease) (*coordina
 	var err error
 	var lease *coordinationv1.Lease
 	for i := 0; i < nl.failedRetry; i++ {
+		start := nl.clock.Now()
 		lease, err = nl.leaseClient.Update(context.Background(), nl.newLease(base), metav1.UpdateOptions{})
 		if err == nil {
+			nl.latency = nl.clock.Since(start)
 			return lease, nil
 		}
 		if apierrors.IsConflict(err) {
@@ -151,6 +159,12 @@ func (nl *nodeLeaseImpl) newLease(base *coordinationv1.Lease) *coordinationv1.Le
 	}
 
 	lease.Spec.RenewTime = &metav1.MicroTime{Time: nl.clock.Now()}
+	if nl.latency > 0 {
+		if lease.Annotations == nil {
+			lease.Annotations = make(map[string]string)
+		}
+		lease.Annotations[apps.AnnotationCloudLatency] = strconv.FormatInt(nl.latency.Milliseconds(), 10)
+	}
 	if len(lease.OwnerReferences) == 0 {
 		if node, err := nl.client.CoreV1().Nodes().Get(context.Background(), nl.holderIdentity, metav1.GetOptions{}); err == nil {
 			lease.OwnerReferences = []metav1.OwnerReference{
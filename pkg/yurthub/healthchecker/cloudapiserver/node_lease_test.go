@@ -18,6 +18,7 @@ package cloudapiserver
 
 import (
 	"testing"
+	"time"
 
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +29,9 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
+	testingclock "k8s.io/utils/clock/testing"
+
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
 )
 
 func TestNodeLeaseManager_Update(t *testing.T) {
@@ -96,3 +100,33 @@ func TestNodeLeaseManager_Update(t *testing.T) {
 	}
 
 }
+
+func TestNodeLeaseManager_UpdateLatency(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "foo", UID: types.UID("foo-uid")}}
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: corev1.NamespaceNodeLease}}
+
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	cl := fake.NewSimpleClientset(node, lease)
+	cl.PrependReactor("update", "leases", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		fakeClock.Step(120 * time.Millisecond)
+		return true, action.(clienttesting.UpdateAction).GetObject(), nil
+	})
+	nl := NewNodeLease(cl, "foo", 40, 3).(*nodeLeaseImpl)
+	nl.clock = fakeClock
+
+	// the latency of the first update is recorded in the next update
+	base, err := nl.Update(lease)
+	if err != nil {
+		t.Fatalf("could not update lease, %v", err)
+	}
+	if _, ok := base.Annotations[apps.AnnotationCloudLatency]; ok {
+		t.Errorf("expect no latency annotation in the first update")
+	}
+	updated, err := nl.Update(base)
+	if err != nil {
+		t.Fatalf("could not update lease, %v", err)
+	}
+	if v := updated.Annotations[apps.AnnotationCloudLatency]; v != "120" {
+		t.Errorf("expect latency annotation 120, but got %q", v)
+	}
+}
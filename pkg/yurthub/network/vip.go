@@ -0,0 +1,221 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/openyurtio/openyurt/cmd/yurthub/app/options"
+	netapi "github.com/openyurtio/openyurt/pkg/apis/network"
+	"github.com/openyurtio/openyurt/pkg/apis/network/v1alpha1"
+)
+
+// VIPManager ensures the virtual ips of PoolServices, which are elected to be announced by this node,
+// are configured on the vip interface, and announces them by gratuitous ARP.
+type VIPManager struct {
+	ifController   VIPInterfaceController
+	vipIfName      string
+	announceIfName string
+	nodeName       string
+	nodeIP         net.IP
+	lister         cache.GenericLister
+	synced         cache.InformerSynced
+	syncCh         chan struct{}
+	announced      sets.Set[string]
+}
+
+func NewVIPManager(options *options.YurtHubOptions, dynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory) (*VIPManager, error) {
+	gvr := v1alpha1.GroupVersion.WithResource("poolservices")
+	informer := dynamicInformerFactory.ForResource(gvr)
+	m := &VIPManager{
+		ifController:   NewVIPInterfaceController(),
+		vipIfName:      options.VIPIfName,
+		announceIfName: options.VIPAnnounceIfName,
+		nodeName:       options.NodeName,
+		nodeIP:         net.ParseIP(options.NodeIP),
+		lister:         informer.Lister(),
+		synced:         informer.Informer().HasSynced,
+		syncCh:         make(chan struct{}, 1),
+		announced:      sets.New[string](),
+	}
+
+	_, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			m.enqueue()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			m.enqueue()
+		},
+		DeleteFunc: func(obj interface{}) {
+			m.enqueue()
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *VIPManager) Run(stopCh <-chan struct{}) {
+	go func() {
+		if !cache.WaitForCacheSync(stopCh, m.synced) {
+			klog.Errorf("could not sync pool services for vip manager")
+			return
+		}
+
+		ticker := time.NewTicker(SyncNetworkPeriod * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stopCh:
+				klog.Infof("exit vip manager run goroutine normally")
+				if err := m.ifController.DeleteVIPInterface(m.vipIfName); err != nil {
+					klog.Errorf("could not delete vip interface %s, %v", m.vipIfName, err)
+				}
+				return
+			case <-ticker.C:
+				// announce all virtual ips periodically in case that arp caches of neighbors are expired or polluted
+				m.announced = sets.New[string]()
+			case <-m.syncCh:
+			}
+
+			if err := m.sync(); err != nil {
+				klog.Warningf("could not sync virtual ips, %v", err)
+			}
+		}
+	}()
+	m.enqueue()
+}
+
+func (m *VIPManager) enqueue() {
+	select {
+	case m.syncCh <- struct{}{}:
+	default:
+	}
+}
+
+func (m *VIPManager) sync() error {
+	vips, err := m.desiredVIPs()
+	if err != nil {
+		return err
+	}
+
+	if err := m.ifController.EnsureVIPs(m.vipIfName, vips); err != nil {
+		return fmt.Errorf("could not ensure virtual ips on interface %s, %w", m.vipIfName, err)
+	}
+
+	current := sets.New[string]()
+	for _, vip := range vips {
+		current.Insert(vip.String())
+		if m.announced.Has(vip.String()) {
+			continue
+		}
+
+		announceIfName, err := m.resolveAnnounceIfName()
+		if err != nil {
+			return err
+		}
+		if err := m.ifController.Announce(announceIfName, vip); err != nil {
+			klog.Warningf("could not announce virtual ip %s on interface %s, %v", vip.String(), announceIfName, err)
+			continue
+		}
+		klog.Infof("virtual ip %s is announced on interface %s", vip.String(), announceIfName)
+		m.announced.Insert(vip.String())
+	}
+	m.announced = m.announced.Intersection(current)
+	return nil
+}
+
+// desiredVIPs returns the virtual ips of PoolServices which are announced by this node.
+func (m *VIPManager) desiredVIPs() ([]net.IP, error) {
+	objs, err := m.lister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("could not list pool services, %w", err)
+	}
+
+	vips := make([]net.IP, 0)
+	for i := range objs {
+		var ps *v1alpha1.PoolService
+		switch obj := objs[i].(type) {
+		case *v1alpha1.PoolService:
+			ps = obj
+		case *unstructured.Unstructured:
+			ps = new(v1alpha1.PoolService)
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), ps); err != nil {
+				klog.Warningf("object(%s) is not a v1alpha1.PoolService, %v", obj.GetName(), err)
+				continue
+			}
+		default:
+			klog.Warningf("object(%s) is an unknown type", obj.GetObjectKind().GroupVersionKind().String())
+			continue
+		}
+
+		if ps.DeletionTimestamp != nil || ps.Annotations[netapi.AnnotationVIPNode] != m.nodeName {
+			continue
+		}
+		if ps.Spec.LoadBalancerClass == nil || *ps.Spec.LoadBalancerClass != netapi.VIPLoadBalancerClass {
+			continue
+		}
+		for _, ingress := range ps.Status.LoadBalancer.Ingress {
+			if vip := net.ParseIP(ingress.IP); vip != nil {
+				vips = append(vips, vip)
+			}
+		}
+	}
+	return vips, nil
+}
+
+// resolveAnnounceIfName returns the specified interface for announcing virtual ips, or the interface
+// which the node ip is configured on.
+func (m *VIPManager) resolveAnnounceIfName() (string, error) {
+	if len(m.announceIfName) != 0 {
+		return m.announceIfName, nil
+	}
+	if m.nodeIP == nil {
+		return "", errors.New("node ip is not specified for announcing virtual ips")
+	}
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(m.nodeIP) {
+				m.announceIfName = iface.Name
+				return m.announceIfName, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("could not find interface for node ip %s", m.nodeIP.String())
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+type VIPInterfaceController interface {
+	EnsureVIPs(ifName string, vips []net.IP) error
+	DeleteVIPInterface(ifName string) error
+	Announce(ifName string, vip net.IP) error
+}
+
+type vipInterfaceController struct {
+	netlink.Handle
+}
+
+// NewVIPInterfaceController returns an instance for managing virtual ips on a dummy net interface
+func NewVIPInterfaceController() VIPInterfaceController {
+	return &vipInterfaceController{
+		Handle: netlink.Handle{},
+	}
+}
+
+// EnsureVIPs make sure the dummy net interface with specified name exists, and only the specified virtual ips are configured on it
+func (vic *vipInterfaceController) EnsureVIPs(ifName string, vips []net.IP) error {
+	link, err := vic.LinkByName(ifName)
+	if err != nil {
+		var notFoundErr netlink.LinkNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			return err
+		}
+		if err := vic.LinkAdd(&netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: ifName}}); err != nil {
+			return err
+		}
+		if link, err = vic.LinkByName(ifName); err != nil {
+			return err
+		}
+	}
+	if link.Attrs().Flags&net.FlagUp == 0 {
+		if err := vic.LinkSetUp(link); err != nil {
+			return err
+		}
+	}
+
+	addrs, err := vic.AddrList(link, netlink.FAMILY_ALL)
+	if err != nil {
+		return err
+	}
+	desired := make(map[string]net.IP, len(vips))
+	for _, vip := range vips {
+		desired[vip.String()] = vip
+	}
+	for i := range addrs {
+		if _, ok := desired[addrs[i].IP.String()]; ok {
+			delete(desired, addrs[i].IP.String())
+			continue
+		}
+		if err := vic.AddrDel(link, &addrs[i]); err != nil {
+			return err
+		}
+	}
+	for _, vip := range desired {
+		if err := vic.AddrAdd(link, &netlink.Addr{IPNet: netlink.NewIPNet(vip)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteVIPInterface delete the dummy net interface with specified name
+func (vic *vipInterfaceController) DeleteVIPInterface(ifName string) error {
+	link, err := vic.LinkByName(ifName)
+	if err != nil {
+		var notFoundErr netlink.LinkNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil
+		}
+		return err
+	}
+	return vic.LinkDel(link)
+}
+
+// Announce sends a gratuitous ARP for the virtual ip on the interface specified by ifName, so neighbors
+// update their ARP caches. IPv6 virtual ips are resolved by neighbor discovery, so they are not announced.
+func (vic *vipInterfaceController) Announce(ifName string, vip net.IP) error {
+	vip4 := vip.To4()
+	if vip4 == nil {
+		return nil
+	}
+
+	iface, err := net.InterfaceByName(ifName)
+	if err != nil {
+		return err
+	}
+	if len(iface.HardwareAddr) != 6 {
+		return fmt.Errorf("interface %s has no ethernet address", ifName)
+	}
+
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_RAW, int(htons(unix.ETH_P_ARP)))
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+
+	broadcast := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	frame := make([]byte, 0, 42)
+	// ethernet header
+	frame = append(frame, broadcast...)
+	frame = append(frame, iface.HardwareAddr...)
+	frame = binary.BigEndian.AppendUint16(frame, unix.ETH_P_ARP)
+	// arp request whose sender and target are both the virtual ip
+	frame = binary.BigEndian.AppendUint16(frame, 1)
+	frame = binary.BigEndian.AppendUint16(frame, unix.ETH_P_IP)
+	frame = append(frame, 6, 4)
+	frame = binary.BigEndian.AppendUint16(frame, 1)
+	frame = append(frame, iface.HardwareAddr...)
+	frame = append(frame, vip4...)
+	frame = append(frame, 0, 0, 0, 0, 0, 0)
+	frame = append(frame, vip4...)
+
+	addr := &unix.SockaddrLinklayer{
+		Protocol: htons(unix.ETH_P_ARP),
+		Ifindex:  iface.Index,
+		Halen:    6,
+	}
+	copy(addr.Addr[:], broadcast)
+	return unix.Sendto(fd, frame, 0, addr)
+}
+
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
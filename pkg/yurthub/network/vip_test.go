@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"net"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	netapi "github.com/openyurtio/openyurt/pkg/apis/network"
+	"github.com/openyurtio/openyurt/pkg/apis/network/v1alpha1"
+)
+
+type fakeVIPInterfaceController struct {
+	vips      []string
+	announced []string
+}
+
+func (f *fakeVIPInterfaceController) EnsureVIPs(ifName string, vips []net.IP) error {
+	f.vips = make([]string, 0, len(vips))
+	for _, vip := range vips {
+		f.vips = append(f.vips, vip.String())
+	}
+	sort.Strings(f.vips)
+	return nil
+}
+
+func (f *fakeVIPInterfaceController) DeleteVIPInterface(ifName string) error {
+	f.vips = nil
+	return nil
+}
+
+func (f *fakeVIPInterfaceController) Announce(ifName string, vip net.IP) error {
+	f.announced = append(f.announced, vip.String())
+	return nil
+}
+
+func newVIPPoolService(name, class, node, vip string) *unstructured.Unstructured {
+	ps := &v1alpha1.PoolService{
+		TypeMeta: metav1.TypeMeta{Kind: "PoolService", APIVersion: v1alpha1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   metav1.NamespaceDefault,
+			Name:        name,
+			Annotations: map[string]string{netapi.AnnotationVIPNode: node},
+		},
+		Spec: v1alpha1.PoolServiceSpec{LoadBalancerClass: &class},
+		Status: v1alpha1.PoolServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: vip}}},
+		},
+	}
+	content, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(ps)
+	return &unstructured.Unstructured{Object: content}
+}
+
+func TestVIPManagerSync(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	objs := []*unstructured.Unstructured{
+		newVIPPoolService("foo", netapi.VIPLoadBalancerClass, "node1", "10.0.0.1"),
+		newVIPPoolService("bar", netapi.VIPLoadBalancerClass, "node1", "fd00::1"),
+		newVIPPoolService("baz", netapi.VIPLoadBalancerClass, "node2", "10.0.0.2"),
+		newVIPPoolService("elb", "elb", "node1", "10.0.0.3"),
+	}
+	for _, obj := range objs {
+		if err := indexer.Add(obj); err != nil {
+			t.Fatalf("could not add pool service, %v", err)
+		}
+	}
+
+	ifController := &fakeVIPInterfaceController{}
+	m := &VIPManager{
+		ifController:   ifController,
+		vipIfName:      "yurthub-vip0",
+		announceIfName: "eth0",
+		nodeName:       "node1",
+		lister:         cache.NewGenericLister(indexer, v1alpha1.GroupVersion.WithResource("poolservices").GroupResource()),
+		announced:      sets.New[string](),
+	}
+
+	if err := m.sync(); err != nil {
+		t.Fatalf("sync() error = %v", err)
+	}
+	if !sets.New(ifController.vips...).Equal(sets.New("10.0.0.1", "fd00::1")) {
+		t.Errorf("expected vips [10.0.0.1 fd00::1], but got %v", ifController.vips)
+	}
+	if len(ifController.announced) != 2 {
+		t.Errorf("expected 2 announcements, but got %v", ifController.announced)
+	}
+
+	// announced virtual ips are not announced again
+	if err := m.sync(); err != nil {
+		t.Fatalf("sync() error = %v", err)
+	}
+	if len(ifController.announced) != 2 {
+		t.Errorf("expected no more announcements, but got %v", ifController.announced)
+	}
+
+	// the virtual ip is moved to another node
+	if err := indexer.Update(newVIPPoolService("foo", netapi.VIPLoadBalancerClass, "node2", "10.0.0.1")); err != nil {
+		t.Fatalf("could not update pool service, %v", err)
+	}
+	if err := m.sync(); err != nil {
+		t.Fatalf("sync() error = %v", err)
+	}
+	if !sets.New(ifController.vips...).Equal(sets.New("fd00::1")) {
+		t.Errorf("expected vips [fd00::1], but got %v", ifController.vips)
+	}
+	if m.announced.Has("10.0.0.1") {
+		t.Errorf("expected 10.0.0.1 is removed from announced vips")
+	}
+}
@@ -0,0 +1,52 @@
+//go:build !linux
+// +build !linux
+
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"net"
+)
+
+type VIPInterfaceController interface {
+	EnsureVIPs(ifName string, vips []net.IP) error
+	DeleteVIPInterface(ifName string) error
+	Announce(ifName string, vip net.IP) error
+}
+
+type unsupportedVIPInterfaceController struct {
+}
+
+func NewVIPInterfaceController() VIPInterfaceController {
+	return &unsupportedVIPInterfaceController{}
+}
+
+// EnsureVIPs unimplemented
+func (uic *unsupportedVIPInterfaceController) EnsureVIPs(ifName string, vips []net.IP) error {
+	return nil
+}
+
+// DeleteVIPInterface unimplemented
+func (uic *unsupportedVIPInterfaceController) DeleteVIPInterface(ifName string) error {
+	return nil
+}
+
+// Announce unimplemented
+func (uic *unsupportedVIPInterfaceController) Announce(ifName string, vip net.IP) error {
+	return nil
+}
@@ -24,14 +24,12 @@ import (
 	"github.com/gorilla/mux"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
 	yurtutil "github.com/openyurtio/openyurt/pkg/util"
 	"github.com/openyurtio/openyurt/pkg/yurthub/cachemanager"
 	"github.com/openyurtio/openyurt/pkg/yurthub/healthchecker"
-	upgrade "github.com/openyurtio/openyurt/pkg/yurthub/otaupdate/upgrader"
 	"github.com/openyurtio/openyurt/pkg/yurthub/otaupdate/util"
 	"github.com/openyurtio/openyurt/pkg/yurthub/storage"
 	"github.com/openyurtio/openyurt/pkg/yurthub/transport"
@@ -98,42 +96,8 @@ func UpdatePod(clientset kubernetes.Interface, nodeName string) http.Handler {
 		namespace := params["ns"]
 		podName := params["podname"]
 
-		pod, ok := preCheck(clientset, namespace, podName, nodeName)
-		// Pod update is not allowed
-		if !ok {
-			util.WriteErr(w, "Pod is not-updatable", http.StatusForbidden)
-			return
-		}
-
-		var upgrader OTAUpgrader
-		kind := pod.GetOwnerReferences()[0].Kind
-		switch kind {
-		case StaticPod:
-			ok, staticName, err := upgrade.PreCheck(podName, nodeName, namespace, clientset)
-			if err != nil {
-				klog.Errorf("Static pod pre-check failed, %v", err)
-				util.WriteErr(w, "Static pod pre-check failed", http.StatusInternalServerError)
-				return
-			}
-			if !ok {
-				util.WriteErr(w, "Configmap for static pod does not exist", http.StatusForbidden)
-				return
-			}
-			upgrader = &upgrade.StaticPodUpgrader{Interface: clientset,
-				NamespacedName: types.NamespacedName{Namespace: namespace, Name: podName}, StaticName: staticName}
-
-		case DaemonPod:
-			upgrader = &upgrade.DaemonPodUpgrader{Interface: clientset,
-				NamespacedName: types.NamespacedName{Namespace: namespace, Name: podName}}
-		default:
-			util.WriteErr(w, fmt.Sprintf("Not support ota upgrade pod type %v", kind), http.StatusBadRequest)
-			return
-		}
-
-		if err := upgrader.Apply(); err != nil {
-			klog.Errorf("Apply update failed, %v", err)
-			// Pod update failed with error
-			util.WriteErr(w, "Apply update failed", http.StatusInternalServerError)
+		if code, err := applyUpdate(clientset, namespace, podName, nodeName); err != nil {
+			util.WriteErr(w, err.Error(), code)
 			return
 		}
 
@@ -145,7 +109,7 @@ func UpdatePod(clientset kubernetes.Interface, nodeName string) http.Handler {
 // preCheck will check the necessary requirements before apply upgrade
 // 1. target pod has not been deleted yet
 // 2. target pod belongs to current node
-// 3. check whether target pod is updatable
+// 3. check whether target pod is updatable and not waiting for maintenance window
 // At last, return the target pod to do further operation
 func preCheck(clientset kubernetes.Interface, namespace, podName, nodeName string) (*corev1.Pod, bool) {
 	pod, err := clientset.CoreV1().Pods(namespace).Get(context.TODO(), podName, metav1.GetOptions{})
@@ -166,7 +130,7 @@ func preCheck(clientset kubernetes.Interface, namespace, podName, nodeName strin
 		return nil, false
 	}
 
-	// Pod will not be updated without pod condition PodNeedUpgrade=true
+	// Pod will not be updated without pod condition PodNeedUpgrade=true, or when it is waiting for maintenance window
 	if !daemonpodupdater.IsPodUpdatable(pod) {
 		klog.Infof("Pod: %v/%v is not updatable", namespace, podName)
 		return nil, false
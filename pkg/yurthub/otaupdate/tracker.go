@@ -0,0 +1,346 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otaupdate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubectl/pkg/util/podutils"
+
+	upgrade "github.com/openyurtio/openyurt/pkg/node-servant/static-pod-upgrade"
+	spctrlutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/yurtstaticset/util"
+)
+
+const (
+	// DefaultUpdateTimeout is the time within which the new pod should be ready after the update is applied
+	DefaultUpdateTimeout = 10 * time.Minute
+	// completedRecordRetention is how long a completed update is kept for querying
+	completedRecordRetention = time.Hour
+
+	// PodOTAUpdated persists the update applied by OTA API on the pod, so the update status can be
+	// restored after YurtHub restarts. It is set on the new pod once it's created, or on the old pod
+	// when the update can not be applied.
+	PodOTAUpdated corev1.PodConditionType = "PodOTAUpdated"
+)
+
+type UpdatePhase string
+
+const (
+	UpdatePhaseUpgrading UpdatePhase = "Upgrading"
+	UpdatePhaseSucceeded UpdatePhase = "Succeeded"
+	UpdatePhaseFailed    UpdatePhase = "Failed"
+	// UpdatePhaseReverted means the previous manifest of static pod is restored because the new pod is not
+	// running or not healthy
+	UpdatePhaseReverted UpdatePhase = "Reverted"
+)
+
+// failedWaitingReasons are reasons of waiting containers which indicate that the new pod is broken
+var failedWaitingReasons = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CrashLoopBackOff":           true,
+	"CreateContainerError":       true,
+	"CreateContainerConfigError": true,
+}
+
+// UpdateStatus is the progress of a pod update applied by OTA API
+type UpdateStatus struct {
+	Phase          UpdatePhase  `json:"phase"`
+	Reason         string       `json:"reason,omitempty"`
+	StartTime      metav1.Time  `json:"startTime"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// DeepCopy returns a copy of the status
+func (s *UpdateStatus) DeepCopy() *UpdateStatus {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	if s.CompletionTime != nil {
+		t := *s.CompletionTime
+		out.CompletionTime = &t
+	}
+	return &out
+}
+
+// record tracks the update of a pod
+type record struct {
+	namespace string
+	name      string
+	kind      string
+	// owner is the name of DaemonSet or YurtStaticSet
+	owner    string
+	ownerUID types.UID
+	status   UpdateStatus
+	// async is true if the result of the update is reported by the upgrader when it's finished,
+	// rather than evaluated by the pods running on this node.
+	async bool
+}
+
+func newRecord(pod *corev1.Pod, nodeName string) *record {
+	ref := pod.OwnerReferences[0]
+	rec := &record{
+		namespace: pod.Namespace,
+		name:      pod.Name,
+		kind:      ref.Kind,
+		owner:     ref.Name,
+		ownerUID:  ref.UID,
+		status:    UpdateStatus{Phase: UpdatePhaseUpgrading, StartTime: metav1.Now()},
+	}
+	if rec.kind == StaticPod {
+		rec.owner = staticName(pod.Name, nodeName)
+	}
+	return rec
+}
+
+// persistedRecord is the message of condition PodOTAUpdated
+type persistedRecord struct {
+	// Pod is the name of the pod which is updated
+	Pod    string       `json:"pod"`
+	Owner  string       `json:"owner"`
+	Status UpdateStatus `json:"status"`
+}
+
+// recordFromPod restores the record persisted in condition PodOTAUpdated of pod, nil is returned if
+// there is no such condition.
+func recordFromPod(pod *corev1.Pod) *record {
+	if len(pod.OwnerReferences) == 0 {
+		return nil
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type != PodOTAUpdated {
+			continue
+		}
+		persisted := &persistedRecord{}
+		if err := json.Unmarshal([]byte(cond.Message), persisted); err != nil || len(persisted.Pod) == 0 {
+			return nil
+		}
+		ref := pod.OwnerReferences[0]
+		return &record{
+			namespace: pod.Namespace,
+			name:      persisted.Pod,
+			kind:      ref.Kind,
+			owner:     persisted.Owner,
+			ownerUID:  ref.UID,
+			status:    persisted.Status,
+		}
+	}
+	return nil
+}
+
+// condition returns the PodOTAUpdated condition which persists the record
+func (rec *record) condition() (*corev1.PodCondition, error) {
+	data, err := json.Marshal(&persistedRecord{Pod: rec.name, Owner: rec.owner, Status: rec.status})
+	if err != nil {
+		return nil, err
+	}
+	status := corev1.ConditionUnknown
+	switch rec.status.Phase {
+	case UpdatePhaseSucceeded:
+		status = corev1.ConditionTrue
+	case UpdatePhaseFailed, UpdatePhaseReverted:
+		status = corev1.ConditionFalse
+	}
+	return &corev1.PodCondition{
+		Type:    PodOTAUpdated,
+		Status:  status,
+		Reason:  string(rec.status.Phase),
+		Message: string(data),
+	}, nil
+}
+
+func (rec *record) key() string {
+	return rec.namespace + "/" + rec.name
+}
+
+func (rec *record) fail(reason string, now time.Time) {
+	rec.status.Phase = UpdatePhaseFailed
+	rec.status.Reason = reason
+	rec.status.CompletionTime = &metav1.Time{Time: now}
+}
+
+func (rec *record) revert(reason string, now time.Time) {
+	rec.status.Phase = UpdatePhaseReverted
+	rec.status.Reason = reason
+	rec.status.CompletionTime = &metav1.Time{Time: now}
+}
+
+func (rec *record) succeed(now time.Time) {
+	rec.status.Phase = UpdatePhaseSucceeded
+	rec.status.Reason = ""
+	rec.status.CompletionTime = &metav1.Time{Time: now}
+}
+
+// evaluate updates the status by the pods running on this node. The update succeeds when the new pod
+// is ready, and fails when the new pod is broken or not ready within DefaultUpdateTimeout.
+// targetHash is the hash of the latest static pod manifest, it's only used for static pods.
+// The new pod is returned if it has been created.
+func (rec *record) evaluate(pods []corev1.Pod, targetHash string, now time.Time) *corev1.Pod {
+	var newPod *corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Namespace != rec.namespace || pod.DeletionTimestamp != nil {
+			continue
+		}
+		switch rec.kind {
+		case DaemonPod:
+			// the daemon pod is recreated with a new name
+			if owner := metav1.GetControllerOf(pod); owner != nil && owner.UID == rec.ownerUID && pod.Name != rec.name {
+				newPod = pod
+			}
+		case StaticPod:
+			// the mirror pod keeps its name, and the hash annotation is changed to the latest hash
+			if pod.Name == rec.name && len(targetHash) != 0 && pod.Annotations[spctrlutil.StaticPodHashAnnotation] == targetHash {
+				newPod = pod
+			}
+		}
+	}
+
+	if newPod != nil {
+		for _, cs := range append(newPod.Status.InitContainerStatuses, newPod.Status.ContainerStatuses...) {
+			if cs.State.Waiting != nil && failedWaitingReasons[cs.State.Waiting.Reason] {
+				rec.fail(fmt.Sprintf("container %s of pod %s is waiting, %s: %s", cs.Name, newPod.Name,
+					cs.State.Waiting.Reason, cs.State.Waiting.Message), now)
+				return newPod
+			}
+		}
+		if podutils.IsPodReady(newPod) {
+			rec.succeed(now)
+			return newPod
+		}
+	}
+
+	if now.Sub(rec.status.StartTime.Time) > DefaultUpdateTimeout {
+		rec.fail(fmt.Sprintf("new pod is not ready within %v", DefaultUpdateTimeout), now)
+	}
+	return newPod
+}
+
+// tracker records pod updates on this node in memory, the records are also persisted
+// in condition PodOTAUpdated of pods and restored from them.
+type tracker struct {
+	sync.Mutex
+	records map[string]*record
+}
+
+var defaultTracker = newTracker()
+
+func newTracker() *tracker {
+	return &tracker{
+		records: make(map[string]*record),
+	}
+}
+
+func (t *tracker) add(rec *record) {
+	t.Lock()
+	defer t.Unlock()
+	t.records[rec.key()] = rec
+}
+
+// restore adds records persisted on pods which are not tracked, e.g. after YurtHub restarts.
+// Records completed for a long time are skipped.
+func (t *tracker) restore(pods []corev1.Pod, now time.Time) {
+	t.Lock()
+	defer t.Unlock()
+	for i := range pods {
+		rec := recordFromPod(&pods[i])
+		if rec == nil {
+			continue
+		}
+		if _, ok := t.records[rec.key()]; ok {
+			continue
+		}
+		if rec.status.CompletionTime != nil && now.Sub(rec.status.CompletionTime.Time) > completedRecordRetention {
+			continue
+		}
+		t.records[rec.key()] = rec
+	}
+}
+
+// list returns copies of all records
+func (t *tracker) list() []*record {
+	t.Lock()
+	defer t.Unlock()
+	records := make([]*record, 0, len(t.records))
+	for _, rec := range t.records {
+		copied := *rec
+		copied.status = *rec.status.DeepCopy()
+		records = append(records, &copied)
+	}
+	return records
+}
+
+// update writes back the status of records, and removes records completed for a long time
+func (t *tracker) update(records []*record, now time.Time) {
+	t.Lock()
+	defer t.Unlock()
+	for _, rec := range records {
+		old, ok := t.records[rec.key()]
+		// the record may be replaced by a new update, or finished by the upgrader
+		if !ok || !old.status.StartTime.Equal(&rec.status.StartTime) || old.async || old.status.Phase != UpdatePhaseUpgrading {
+			continue
+		}
+		t.records[rec.key()] = rec
+	}
+	for key, rec := range t.records {
+		if rec.status.CompletionTime != nil && now.Sub(rec.status.CompletionTime.Time) > completedRecordRetention {
+			delete(t.records, key)
+		}
+	}
+}
+
+// finish completes the record by the result of the update which is applied asynchronously, and returns a copy of
+// the record. nil is returned if the record has been replaced by a new update.
+func (t *tracker) finish(key string, startTime metav1.Time, err error, now time.Time) *record {
+	t.Lock()
+	defer t.Unlock()
+	rec, ok := t.records[key]
+	if !ok || !rec.status.StartTime.Equal(&startTime) {
+		return nil
+	}
+
+	var reverted *upgrade.RevertedError
+	switch {
+	case err == nil:
+		rec.succeed(now)
+	case errors.As(err, &reverted):
+		rec.revert(reverted.Err.Error(), now)
+	default:
+		rec.fail(fmt.Sprintf("upgrade failed, %v", err), now)
+	}
+	copied := *rec
+	copied.status = *rec.status.DeepCopy()
+	return &copied
+}
+
+func (t *tracker) status(namespace, name string) *UpdateStatus {
+	t.Lock()
+	defer t.Unlock()
+	if rec, ok := t.records[namespace+"/"+name]; ok {
+		return rec.status.DeepCopy()
+	}
+	return nil
+}
@@ -0,0 +1,486 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otaupdate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	upgrade "github.com/openyurtio/openyurt/pkg/yurthub/otaupdate/upgrader"
+	"github.com/openyurtio/openyurt/pkg/yurthub/otaupdate/util"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/daemonpodupdater"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/maintenancewindow"
+	podutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/pod"
+	spctrlutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/yurtstaticset/util"
+)
+
+// PodUpdate describes the pending or ongoing update of a pod on this node
+type PodUpdate struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Kind is the owner kind of pod, "DaemonSet" for daemon pods and "Node" for static pods
+	Kind string `json:"kind"`
+	// Owner is the name of DaemonSet or YurtStaticSet
+	Owner string `json:"owner"`
+	// Updatable is true if the pod can be upgraded now, it's consistent with condition PodNeedUpgrade
+	Updatable bool        `json:"updatable"`
+	Images    []ImageDiff `json:"images,omitempty"`
+	// Scheduled is the message of condition PodUpgradeScheduled if the upgrade waits for maintenance window
+	Scheduled      string        `json:"scheduled,omitempty"`
+	PostponedUntil *metav1.Time  `json:"postponedUntil,omitempty"`
+	Status         *UpdateStatus `json:"status,omitempty"`
+}
+
+// ImageDiff is the image change of a container
+type ImageDiff struct {
+	Container string `json:"container"`
+	Current   string `json:"current,omitempty"`
+	Target    string `json:"target,omitempty"`
+}
+
+// PodReference refers to a pod on this node
+type PodReference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// BatchUpdateRequest is the request body of batch update
+type BatchUpdateRequest struct {
+	Pods []PodReference `json:"pods"`
+}
+
+// BatchUpdateResult is the result of updating a pod in batch update
+type BatchUpdateResult struct {
+	PodReference
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ListUpdates lists pods on this node which need to be upgraded or are being upgraded,
+// together with the image diff between the running pod and the target template.
+func ListUpdates(clientset kubernetes.Interface, nodeName string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := syncUpdateStatus(clientset, nodeName); err != nil {
+			klog.Warningf("could not sync update status on node %s, %v", nodeName, err)
+		}
+
+		pods, err := listNodePods(clientset, nodeName)
+		if err != nil {
+			klog.Errorf("could not list pods on node %s, %v", nodeName, err)
+			util.WriteErr(w, "List pods failed", http.StatusInternalServerError)
+			return
+		}
+
+		updates := make([]PodUpdate, 0)
+		now := time.Now()
+		for i := range pods {
+			pod := &pods[i]
+			status := defaultTracker.status(pod.Namespace, pod.Name)
+			if !daemonpodupdater.IsPodUpgradeConditionTrue(pod.Status) && status == nil {
+				continue
+			}
+			if len(pod.OwnerReferences) == 0 {
+				continue
+			}
+			update := PodUpdate{
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				Kind:      pod.OwnerReferences[0].Kind,
+				Owner:     pod.OwnerReferences[0].Name,
+				Updatable: daemonpodupdater.IsPodUpdatable(pod),
+				Status:    status,
+			}
+			if update.Kind == StaticPod {
+				update.Owner = staticName(pod.Name, nodeName)
+			}
+			if cond := getScheduledCondition(pod); cond != nil && cond.Status == corev1.ConditionTrue {
+				update.Scheduled = cond.Message
+			}
+			if until, ok := maintenancewindow.PostponedUntil(pod, now); ok {
+				update.PostponedUntil = &metav1.Time{Time: until}
+				update.Updatable = false
+			}
+			if update.Images, err = imageDiff(clientset, pod, update.Kind, update.Owner); err != nil {
+				klog.Warningf("could not get image diff of pod %s/%s, %v", pod.Namespace, pod.Name, err)
+			}
+			updates = append(updates, update)
+		}
+
+		// pods which are being upgraded may have been deleted or replaced
+		for _, rec := range defaultTracker.list() {
+			if !containsPod(pods, rec.namespace, rec.name) {
+				updates = append(updates, PodUpdate{Namespace: rec.namespace, Name: rec.name, Kind: rec.kind,
+					Owner: rec.owner, Status: rec.status.DeepCopy()})
+			}
+		}
+		sort.Slice(updates, func(i, j int) bool {
+			if updates[i].Namespace != updates[j].Namespace {
+				return updates[i].Namespace < updates[j].Namespace
+			}
+			return updates[i].Name < updates[j].Name
+		})
+
+		writeJSON(w, updates)
+	})
+}
+
+// BatchUpdatePods upgrades several pods in one request, the result of every pod is returned.
+// The progress of accepted pods can be queried by GetUpdateStatus.
+func BatchUpdatePods(clientset kubernetes.Interface, nodeName string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &BatchUpdateRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil || len(req.Pods) == 0 {
+			util.WriteErr(w, "Invalid batch update request", http.StatusBadRequest)
+			return
+		}
+
+		results := make([]BatchUpdateResult, 0, len(req.Pods))
+		for _, ref := range req.Pods {
+			result := BatchUpdateResult{PodReference: ref, Accepted: true}
+			if code, err := applyUpdate(clientset, ref.Namespace, ref.Name, nodeName); err != nil {
+				klog.Infof("could not update pod %s/%s in batch, code %d, %v", ref.Namespace, ref.Name, code, err)
+				result.Accepted, result.Reason = false, err.Error()
+			}
+			results = append(results, result)
+		}
+
+		writeJSON(w, results)
+	})
+}
+
+// PostponeUpdate postpones the update of a pod, the pod is not updatable until the postponement expires.
+// The postponement is specified by query parameter "duration" (e.g. 2h) or "until" (RFC3339), and it is
+// stored in condition PodUpgradePostponed of the pod, so it is also respected by the cloud controllers.
+func PostponeUpdate(clientset kubernetes.Interface, nodeName string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		namespace, podName := params["ns"], params["podname"]
+
+		var until time.Time
+		if r.Method != http.MethodDelete {
+			var err error
+			if until, err = parsePostponement(r, time.Now()); err != nil {
+				util.WriteErr(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		pod, err := clientset.CoreV1().Pods(namespace).Get(context.TODO(), podName, metav1.GetOptions{})
+		if err != nil || pod.Spec.NodeName != nodeName {
+			util.WriteErr(w, "Pod is not found on this node", http.StatusNotFound)
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			if err := updatePodCondition(clientset, pod, maintenancewindow.NewPostponedCondition(false, until)); err != nil {
+				klog.Errorf("could not cancel postponement of pod %s/%s, %v", namespace, podName, err)
+				util.WriteErr(w, "Cancel postponement failed", http.StatusInternalServerError)
+				return
+			}
+			util.WriteJSONResponse(w, []byte(fmt.Sprintf("Cancel postponement of pod %v/%v", namespace, podName)))
+			return
+		}
+
+		if !daemonpodupdater.IsPodUpgradeConditionTrue(pod.Status) {
+			util.WriteErr(w, "Pod has no pending update", http.StatusForbidden)
+			return
+		}
+
+		if err := updatePodCondition(clientset, pod, maintenancewindow.NewPostponedCondition(true, until)); err != nil {
+			klog.Errorf("could not postpone update of pod %s/%s, %v", namespace, podName, err)
+			util.WriteErr(w, "Postpone update failed", http.StatusInternalServerError)
+			return
+		}
+		klog.Infof("Update of pod %s/%s is postponed until %s", namespace, podName, until.Format(time.RFC3339))
+		util.WriteJSONResponse(w, []byte(fmt.Sprintf("Postpone update of pod %v/%v until %s",
+			namespace, podName, until.Format(time.RFC3339))))
+	})
+}
+
+// GetUpdateStatus returns the progress and the failure reason of the update of a pod
+func GetUpdateStatus(clientset kubernetes.Interface, nodeName string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := mux.Vars(r)
+		namespace, podName := params["ns"], params["podname"]
+
+		if err := syncUpdateStatus(clientset, nodeName); err != nil {
+			klog.Errorf("could not sync update status on node %s, %v", nodeName, err)
+			util.WriteErr(w, "Sync update status failed", http.StatusInternalServerError)
+			return
+		}
+
+		status := defaultTracker.status(namespace, podName)
+		if status == nil {
+			util.WriteErr(w, "Pod update is not found", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, status)
+	})
+}
+
+// applyUpdate checks and applies the update of a pod, the update is tracked if it is applied successfully.
+// The returned http status code indicates the cause of error.
+func applyUpdate(clientset kubernetes.Interface, namespace, podName, nodeName string) (int, error) {
+	if pod, err := clientset.CoreV1().Pods(namespace).Get(context.TODO(), podName, metav1.GetOptions{}); err == nil {
+		if until, ok := maintenancewindow.PostponedUntil(pod, time.Now()); ok {
+			return http.StatusForbidden, fmt.Errorf("pod update is postponed until %s", until.Format(time.RFC3339))
+		}
+	}
+
+	pod, ok := preCheck(clientset, namespace, podName, nodeName)
+	if !ok {
+		return http.StatusForbidden, fmt.Errorf("pod is not-updatable")
+	}
+
+	rec := newRecord(pod, nodeName)
+	upgrader, code, err := newUpgrader(clientset, pod, nodeName, rec)
+	if err != nil {
+		return code, err
+	}
+
+	if err := upgrader.Apply(); err != nil {
+		klog.Errorf("Apply update failed, %v", err)
+		rec.fail(fmt.Sprintf("apply update failed, %v", err), time.Now())
+		defaultTracker.add(rec)
+		// the old pod is kept when the update can not be applied, so the failure is persisted on it
+		if err := persistRecord(clientset, rec, pod); err != nil {
+			klog.Warningf("could not persist update status of pod %s/%s, %v", namespace, podName, err)
+		}
+		return http.StatusInternalServerError, fmt.Errorf("apply update failed")
+	}
+	defaultTracker.add(rec)
+	return http.StatusOK, nil
+}
+
+// newUpgrader constructs the upgrader for the kind of pod. Static pods are upgraded asynchronously,
+// and the result of the upgrade is written back to rec when it's finished.
+func newUpgrader(clientset kubernetes.Interface, pod *corev1.Pod, nodeName string, rec *record) (OTAUpgrader, int, error) {
+	nn := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+	kind := pod.GetOwnerReferences()[0].Kind
+	switch kind {
+	case StaticPod:
+		ok, staticName, err := upgrade.PreCheck(pod.Name, nodeName, pod.Namespace, clientset)
+		if err != nil {
+			klog.Errorf("Static pod pre-check failed, %v", err)
+			return nil, http.StatusInternalServerError, fmt.Errorf("static pod pre-check failed")
+		}
+		if !ok {
+			return nil, http.StatusForbidden, fmt.Errorf("configmap for static pod does not exist")
+		}
+		rec.async = true
+		done := func(err error) {
+			finished := defaultTracker.finish(rec.key(), rec.status.StartTime, err, time.Now())
+			if finished == nil {
+				return
+			}
+			pod, err := clientset.CoreV1().Pods(nn.Namespace).Get(context.TODO(), nn.Name, metav1.GetOptions{})
+			if err == nil {
+				err = persistRecord(clientset, finished, pod)
+			}
+			if err != nil {
+				klog.Warningf("could not persist update status of pod %s, %v", nn, err)
+			}
+		}
+		return &upgrade.StaticPodUpgrader{Interface: clientset, NamespacedName: nn, StaticName: staticName, Done: done}, http.StatusOK, nil
+	case DaemonPod:
+		return &upgrade.DaemonPodUpgrader{Interface: clientset, NamespacedName: nn}, http.StatusOK, nil
+	}
+	return nil, http.StatusBadRequest, fmt.Errorf("not support ota upgrade pod type %v", kind)
+}
+
+// syncUpdateStatus refreshes the status of tracked updates by the pods running on this node,
+// and persists the status on the new pods. Updates persisted on pods are restored if they are not tracked.
+func syncUpdateStatus(clientset kubernetes.Interface, nodeName string) error {
+	pods, err := listNodePods(clientset, nodeName)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	defaultTracker.restore(pods, now)
+	records := defaultTracker.list()
+	for _, rec := range records {
+		// the result of asynchronous update is reported by the upgrader
+		if rec.status.Phase != UpdatePhaseUpgrading || rec.async {
+			continue
+		}
+		targetHash := ""
+		if rec.kind == StaticPod {
+			cm, err := clientset.CoreV1().ConfigMaps(rec.namespace).Get(context.TODO(),
+				spctrlutil.WithConfigMapPrefix(rec.owner), metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			targetHash = cm.Annotations[spctrlutil.StaticPodHashAnnotation]
+		}
+		if newPod := rec.evaluate(pods, targetHash, now); newPod != nil {
+			if err := persistRecord(clientset, rec, newPod); err != nil {
+				klog.Warningf("could not persist update status of pod %s on pod %s, %v", rec.key(), newPod.Name, err)
+			}
+		}
+	}
+	defaultTracker.update(records, now)
+	return nil
+}
+
+// imageDiff compares images of the running pod with the target template of its owner
+func imageDiff(clientset kubernetes.Interface, pod *corev1.Pod, kind, owner string) ([]ImageDiff, error) {
+	var target *corev1.PodSpec
+	switch kind {
+	case DaemonPod:
+		ds, err := clientset.AppsV1().DaemonSets(pod.Namespace).Get(context.TODO(), owner, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		target = &ds.Spec.Template.Spec
+	case StaticPod:
+		cm, err := clientset.CoreV1().ConfigMaps(pod.Namespace).Get(context.TODO(),
+			spctrlutil.WithConfigMapPrefix(owner), metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, data := range cm.Data {
+			manifest := &corev1.Pod{}
+			if err := yaml.Unmarshal([]byte(data), manifest); err != nil {
+				return nil, fmt.Errorf("could not decode manifest in configmap %s, %v", cm.Name, err)
+			}
+			target = &manifest.Spec
+		}
+	}
+	if target == nil {
+		return nil, nil
+	}
+
+	current := make(map[string]string)
+	for _, c := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		current[c.Name] = c.Image
+	}
+	var diffs []ImageDiff
+	for _, c := range append(target.InitContainers, target.Containers...) {
+		if image, ok := current[c.Name]; !ok || image != c.Image {
+			diffs = append(diffs, ImageDiff{Container: c.Name, Current: image, Target: c.Image})
+		}
+		delete(current, c.Name)
+	}
+	for name, image := range current {
+		diffs = append(diffs, ImageDiff{Container: name, Current: image})
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Container < diffs[j].Container })
+	return diffs, nil
+}
+
+// persistRecord stores the record in condition PodOTAUpdated of pod
+func persistRecord(clientset kubernetes.Interface, rec *record, pod *corev1.Pod) error {
+	cond, err := rec.condition()
+	if err != nil {
+		return err
+	}
+	return updatePodCondition(clientset, pod, cond)
+}
+
+// updatePodCondition updates the condition of pod by pods/status, it's a no-op if the condition is not changed
+func updatePodCondition(clientset kubernetes.Interface, pod *corev1.Pod, cond *corev1.PodCondition) error {
+	updated := pod.DeepCopy()
+	if !podutil.UpdatePodCondition(&updated.Status, cond) {
+		return nil
+	}
+	_, err := clientset.CoreV1().Pods(updated.Namespace).UpdateStatus(context.TODO(), updated, metav1.UpdateOptions{})
+	return err
+}
+
+func listNodePods(clientset kubernetes.Interface, nodeName string) ([]corev1.Pod, error) {
+	podList, err := clientset.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]corev1.Pod, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName == nodeName {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func parsePostponement(r *http.Request, now time.Time) (time.Time, error) {
+	query := r.URL.Query()
+	if v := query.Get("until"); v != "" {
+		until, err := time.Parse(time.RFC3339, v)
+		if err != nil || !until.After(now) {
+			return time.Time{}, fmt.Errorf("invalid postponement until %q, it should be a future RFC3339 time", v)
+		}
+		return until, nil
+	}
+	if v := query.Get("duration"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return time.Time{}, fmt.Errorf("invalid postponement duration %q", v)
+		}
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("postponement duration or until should be specified")
+}
+
+func getScheduledCondition(pod *corev1.Pod) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == daemonpodupdater.PodUpgradeScheduled {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func staticName(podName, nodeName string) string {
+	if ok, name := util.RemoveNodeNameFromStaticPod(podName, nodeName); ok {
+		return name
+	}
+	return podName
+}
+
+func containsPod(pods []corev1.Pod, namespace, name string) bool {
+	for i := range pods {
+		if pods[i].Namespace == namespace && pods[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		klog.Errorf("could not encode response, %v", err)
+		util.WriteErr(w, "Encode response failed", http.StatusInternalServerError)
+		return
+	}
+	util.WriteJSONResponse(w, data)
+}
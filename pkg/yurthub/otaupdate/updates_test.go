@@ -0,0 +1,279 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otaupdate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	upgrade "github.com/openyurtio/openyurt/pkg/node-servant/static-pod-upgrade"
+	"github.com/openyurtio/openyurt/pkg/yurthub/otaupdate/util"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/maintenancewindow"
+)
+
+const testNodeName = "node1"
+
+func newDaemonPod(name, image string, needUpgrade corev1.ConditionStatus) *corev1.Pod {
+	pod := util.NewPodWithCondition(name, DaemonPod, needUpgrade)
+	pod.OwnerReferences[0].Name = "ds"
+	pod.OwnerReferences[0].UID = types.UID("ds-uid")
+	pod.OwnerReferences[0].Controller = func(b bool) *bool { return &b }(true)
+	pod.Spec.NodeName = testNodeName
+	pod.Spec.Containers = []corev1.Container{{Name: "app", Image: image}}
+	return pod
+}
+
+func newTestDaemonSet(image string) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "ds", Namespace: metav1.NamespaceDefault, UID: types.UID("ds-uid")},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}},
+			},
+		},
+	}
+}
+
+func serve(t *testing.T, h http.Handler, method, target string, body interface{}, vars map[string]string) *httptest.ResponseRecorder {
+	var data []byte
+	if body != nil {
+		data, _ = json.Marshal(body)
+	}
+	req, err := http.NewRequest(method, target, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars != nil {
+		req = mux.SetURLVars(req, vars)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestListAndBatchUpdates(t *testing.T) {
+	defaultTracker = newTracker()
+	clientset := fake.NewSimpleClientset(
+		newTestDaemonSet("app:v2"),
+		newDaemonPod("pending", "app:v1", corev1.ConditionTrue),
+		newDaemonPod("latest", "app:v2", corev1.ConditionFalse),
+	)
+
+	rr := serve(t, ListUpdates(clientset, testNodeName), "GET", "/openyurt.io/v1/updates", nil, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var updates []PodUpdate
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &updates))
+	assert.Equal(t, 1, len(updates))
+	assert.Equal(t, "pending", updates[0].Name)
+	assert.True(t, updates[0].Updatable)
+	assert.Equal(t, []ImageDiff{{Container: "app", Current: "app:v1", Target: "app:v2"}}, updates[0].Images)
+
+	req := BatchUpdateRequest{Pods: []PodReference{
+		{Namespace: metav1.NamespaceDefault, Name: "pending"},
+		{Namespace: metav1.NamespaceDefault, Name: "latest"},
+	}}
+	rr = serve(t, BatchUpdatePods(clientset, testNodeName), "POST", "/openyurt.io/v1/updates/upgrade", req, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var results []BatchUpdateResult
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &results))
+	assert.Equal(t, 2, len(results))
+	assert.True(t, results[0].Accepted)
+	assert.False(t, results[1].Accepted)
+	assert.Equal(t, "pod is not-updatable", results[1].Reason)
+
+	vars := map[string]string{"ns": metav1.NamespaceDefault, "podname": "pending"}
+	rr = serve(t, GetUpdateStatus(clientset, testNodeName), "GET", "/openyurt.io/v1/namespaces/default/pods/pending/upgrade", nil, vars)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	status := &UpdateStatus{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), status))
+	assert.Equal(t, UpdatePhaseUpgrading, status.Phase)
+}
+
+func TestPostponeUpdate(t *testing.T) {
+	defaultTracker = newTracker()
+	clientset := fake.NewSimpleClientset(newTestDaemonSet("app:v2"), newDaemonPod("pending", "app:v1", corev1.ConditionTrue))
+	vars := map[string]string{"ns": metav1.NamespaceDefault, "podname": "pending"}
+	target := "/openyurt.io/v1/namespaces/default/pods/pending/postpone"
+
+	rr := serve(t, PostponeUpdate(clientset, testNodeName), "POST", target, nil, vars)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = serve(t, PostponeUpdate(clientset, testNodeName), "POST", target+"?duration=2h", nil, vars)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(t, UpdatePod(clientset, testNodeName), "POST", "/openyurt.io/v1/namespaces/default/pods/pending/upgrade", nil, vars)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = serve(t, ListUpdates(clientset, testNodeName), "GET", "/openyurt.io/v1/updates", nil, nil)
+	var updates []PodUpdate
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &updates))
+	assert.Equal(t, 1, len(updates))
+	assert.False(t, updates[0].Updatable)
+	assert.NotNil(t, updates[0].PostponedUntil)
+
+	// the postponement is kept on the pod, so it is still respected after YurtHub restarts
+	defaultTracker = newTracker()
+	pod, err := clientset.CoreV1().Pods(metav1.NamespaceDefault).Get(context.TODO(), "pending", metav1.GetOptions{})
+	assert.Nil(t, err)
+	_, postponed := maintenancewindow.PostponedUntil(pod, time.Now())
+	assert.True(t, postponed)
+
+	rr = serve(t, PostponeUpdate(clientset, testNodeName), "DELETE", target, nil, vars)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = serve(t, UpdatePod(clientset, testNodeName), "POST", "/openyurt.io/v1/namespaces/default/pods/pending/upgrade", nil, vars)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRecordEvaluate(t *testing.T) {
+	now := time.Now()
+	newPod := newDaemonPod("new", "app:v2", corev1.ConditionFalse)
+
+	tests := []struct {
+		name       string
+		start      time.Time
+		podStatus  corev1.PodStatus
+		wantPhase  UpdatePhase
+		wantReason string
+	}{
+		{
+			name:      "new pod is ready",
+			start:     now,
+			podStatus: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+			wantPhase: UpdatePhaseSucceeded,
+		},
+		{
+			name:  "new pod can not pull image",
+			start: now,
+			podStatus: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "app",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "not found"}}}}},
+			wantPhase:  UpdatePhaseFailed,
+			wantReason: "container app of pod new is waiting, ImagePullBackOff: not found",
+		},
+		{
+			name:      "new pod is starting",
+			start:     now,
+			wantPhase: UpdatePhaseUpgrading,
+		},
+		{
+			name:       "new pod is not ready in time",
+			start:      now.Add(-DefaultUpdateTimeout - time.Minute),
+			wantPhase:  UpdatePhaseFailed,
+			wantReason: "new pod is not ready within 10m0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := newRecord(newDaemonPod("old", "app:v1", corev1.ConditionTrue), testNodeName)
+			rec.status.StartTime = metav1.Time{Time: tt.start}
+			pod := newPod.DeepCopy()
+			pod.Status = tt.podStatus
+			rec.evaluate([]corev1.Pod{*pod}, "", now)
+			assert.Equal(t, tt.wantPhase, rec.status.Phase)
+			assert.Equal(t, tt.wantReason, rec.status.Reason)
+		})
+	}
+}
+
+func TestRestoreUpdateStatus(t *testing.T) {
+	defaultTracker = newTracker()
+	clientset := fake.NewSimpleClientset(newTestDaemonSet("app:v2"), newDaemonPod("pending", "app:v1", corev1.ConditionTrue))
+	vars := map[string]string{"ns": metav1.NamespaceDefault, "podname": "pending"}
+	target := "/openyurt.io/v1/namespaces/default/pods/pending/upgrade"
+
+	rr := serve(t, UpdatePod(clientset, testNodeName), "POST", target, nil, vars)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// the new pod is created by DaemonSet controller and becomes ready
+	newPod := newDaemonPod("new", "app:v2", corev1.ConditionFalse)
+	newPod.Status.Conditions = append(newPod.Status.Conditions, corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionTrue})
+	_, err := clientset.CoreV1().Pods(metav1.NamespaceDefault).Create(context.TODO(), newPod, metav1.CreateOptions{})
+	assert.Nil(t, err)
+
+	rr = serve(t, GetUpdateStatus(clientset, testNodeName), "GET", target, nil, vars)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// restart YurtHub, the update status is restored from the new pod
+	defaultTracker = newTracker()
+	rr = serve(t, GetUpdateStatus(clientset, testNodeName), "GET", target, nil, vars)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	status := &UpdateStatus{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), status))
+	assert.Equal(t, UpdatePhaseSucceeded, status.Phase)
+	assert.NotNil(t, status.CompletionTime)
+}
+
+func TestTrackerFinish(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		err        error
+		wantPhase  UpdatePhase
+		wantReason string
+	}{
+		{name: "static pod is upgraded", wantPhase: UpdatePhaseSucceeded},
+		{
+			name:       "static pod is reverted",
+			err:        &upgrade.RevertedError{Err: errors.New("the latest static pod is not running")},
+			wantPhase:  UpdatePhaseReverted,
+			wantReason: "the latest static pod is not running",
+		},
+		{
+			name:       "manifest can not be rolled back",
+			err:        errors.New("permission denied"),
+			wantPhase:  UpdatePhaseFailed,
+			wantReason: "upgrade failed, permission denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newTracker()
+			rec := newRecord(newDaemonPod("static", "app:v1", corev1.ConditionTrue), testNodeName)
+			rec.async = true
+			tr.add(rec)
+
+			// the record listed before the upgrade is finished doesn't overwrite the result
+			records := tr.list()
+			finished := tr.finish(rec.key(), rec.status.StartTime, tt.err, now)
+			tr.update(records, now)
+
+			assert.NotNil(t, finished)
+			status := tr.status(rec.namespace, rec.name)
+			assert.Equal(t, tt.wantPhase, status.Phase)
+			assert.Equal(t, tt.wantReason, status.Reason)
+			assert.NotNil(t, status.CompletionTime)
+
+			// the record has been replaced by a new update
+			assert.Nil(t, tr.finish(rec.key(), metav1.Time{Time: now.Add(-time.Hour)}, nil, now))
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sync"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -39,6 +40,10 @@ const OTA = "OTA"
 
 var (
 	DefaultUpgradePath = "/tmp/manifests"
+	// staticPodRunningTimeout is the timeout for the upgraded static pod to be running
+	staticPodRunningTimeout = upgrade.DefaultStaticPodRunningCheckTimeout
+	// upgradingStaticPods records the static pods being upgraded, a static pod is upgraded once at a time
+	upgradingStaticPods sync.Map
 )
 
 type StaticPodUpgrader struct {
@@ -46,13 +51,43 @@ type StaticPodUpgrader struct {
 	types.NamespacedName
 	// Name format of static pod is `staticName-nodeName`
 	StaticName string
+	// Done is called with the result of the upgrade when it's finished, the error is upgrade.RevertedError
+	// if the previous manifest is restored because the new static pod is not running or not healthy.
+	Done func(error)
 }
 
+// Apply prepares the latest manifest and starts the upgrade asynchronously, because waiting for the new
+// static pod to be running and healthy takes minutes. The result of the upgrade is reported by Done.
 func (s *StaticPodUpgrader) Apply() error {
+	key := s.NamespacedName.String()
+	if _, upgrading := upgradingStaticPods.LoadOrStore(key, struct{}{}); upgrading {
+		return fmt.Errorf("static pod %s is being upgraded", key)
+	}
+	ctrl, err := s.prepare()
+	if err != nil {
+		upgradingStaticPods.Delete(key)
+		return err
+	}
+
+	go func() {
+		err := ctrl.Upgrade()
+		upgradingStaticPods.Delete(key)
+		if err != nil {
+			klog.Errorf("could not upgrade static pod %s, %v", key, err)
+		}
+		if s.Done != nil {
+			s.Done(err)
+		}
+	}()
+	return nil
+}
+
+// prepare generates the latest manifest from configmap and returns the upgrade controller
+func (s *StaticPodUpgrader) prepare() (*upgrade.Controller, error) {
 	cm, err := s.CoreV1().ConfigMaps(s.Namespace).Get(context.TODO(),
 		spctrlutil.WithConfigMapPrefix(s.StaticName), metav1.GetOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	var manifest, data string
 	for k, v := range cm.Data {
@@ -60,24 +95,33 @@ func (s *StaticPodUpgrader) Apply() error {
 		data = v
 	}
 	if len(data) == 0 {
-		return fmt.Errorf("empty manifest in configmap %v", spctrlutil.WithConfigMapPrefix(s.StaticName))
+		return nil, fmt.Errorf("empty manifest in configmap %v", spctrlutil.WithConfigMapPrefix(s.StaticName))
 	}
 
 	// Make sure upgrade dir exist
 	if _, err := os.Stat(DefaultUpgradePath); os.IsNotExist(err) {
 		if err = os.Mkdir(DefaultUpgradePath, 0755); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	upgradeManifestPath := filepath.Join(DefaultUpgradePath, upgradeutil.WithUpgradeSuffix(manifest))
 	if err := genUpgradeManifest(upgradeManifestPath, data); err != nil {
-		return err
+		return nil, err
 	}
 	klog.V(5).Info("Generate upgrade manifest")
 
 	ctrl := upgrade.New(s.Name, s.Namespace, manifest, OTA)
-	return ctrl.Upgrade()
+	ctrl.SetHash(cm.Annotations[upgradeutil.StaticPodHashAnnotation])
+	ctrl.SetTimeout(staticPodRunningTimeout)
+	if encoded := cm.Annotations[upgradeutil.StaticPodHealthCheckAnnotation]; len(encoded) != 0 {
+		hc, err := upgradeutil.DecodeHealthCheck(encoded)
+		if err != nil {
+			return nil, err
+		}
+		ctrl.SetHealthCheck(hc)
+	}
+	return ctrl, nil
 }
 
 func PreCheck(name, nodename, namespace string, c kubernetes.Interface) (bool, string, error) {
@@ -17,9 +17,11 @@ limitations under the License.
 package upgrader
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,17 +35,23 @@ import (
 	spctrlutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/yurtstaticset/util"
 )
 
-func TestStaticPodUpgrader_ApplyManifestNotExist(t *testing.T) {
+func TestStaticPodUpgrader_ApplyReverted(t *testing.T) {
 	// Temporarily modify the manifest path in order to test
 	upgrade.DefaultUpgradePath = t.TempDir()
 	upgrade.DefaultManifestPath = t.TempDir()
 	DefaultUpgradePath = upgrade.DefaultUpgradePath
-	_, _ = os.Create(filepath.Join(upgrade.DefaultManifestPath, upgradeutil.WithYamlSuffix("nginx")))
+	staticPodRunningTimeout = time.Second
+	defer func() { staticPodRunningTimeout = upgrade.DefaultStaticPodRunningCheckTimeout }()
+	manifestPath := filepath.Join(upgrade.DefaultManifestPath, upgradeutil.WithYamlSuffix("nginx"))
+	if err := os.WriteFile(manifestPath, []byte("old"), 0644); err != nil {
+		t.Fatalf("could not write manifest, %v", err)
+	}
 
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: metav1.NamespaceDefault,
-			Name:      spctrlutil.WithConfigMapPrefix("nginx"),
+			Namespace:   metav1.NamespaceDefault,
+			Name:        spctrlutil.WithConfigMapPrefix("nginx"),
+			Annotations: map[string]string{upgradeutil.StaticPodHashAnnotation: "hash"},
 		},
 		Data: map[string]string{
 			"nginx": `
@@ -51,6 +59,8 @@ apiVersion: v1
 kind: Pod
 metadata:
   name: nginx
+  annotations:
+    openyurt.io/static-pod-hash: hash
 spec:
   containers:
     - name: web
@@ -60,17 +70,35 @@ spec:
 	}
 
 	clientset := fake.NewSimpleClientset(util.NewPodWithCondition("nginx-node", "Node", corev1.ConditionTrue), cm)
+	done := make(chan error, 1)
 	upgrader := StaticPodUpgrader{
 		Interface:      clientset,
 		NamespacedName: types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "nginx-node"},
 		StaticName:     "nginx",
+		Done:           func(err error) { done <- err },
+	}
+
+	// the upgrade is started asynchronously, and the static pod can not be upgraded again until it's finished
+	if err := upgrader.Apply(); err != nil {
+		t.Fatalf("expect the upgrade to be started, but got %v", err)
+	}
+	if err := upgrader.Apply(); err == nil {
+		t.Errorf("expect the static pod being upgraded can not be upgraded again")
 	}
 
-	t.Run("TestStaticPodUpgrader_ApplyManifestNotExist", func(t *testing.T) {
-		if err := upgrader.Apply(); err != nil {
-			t.Fatalf("Fail to ota upgrade static pod, %v", err)
+	// the new static pod can not be found because YurtHub is not running
+	var reverted *upgrade.RevertedError
+	select {
+	case err := <-done:
+		if !errors.As(err, &reverted) {
+			t.Fatalf("expect the upgrade to be reverted, but got %v", err)
 		}
-	})
+	case <-time.After(10 * time.Second):
+		t.Fatalf("the upgrade is not finished in time")
+	}
+	if data, _ := os.ReadFile(manifestPath); string(data) != "old" {
+		t.Errorf("expect the previous manifest to be restored, but got %s", string(data))
+	}
 }
 
 func Test_genUpgradeManifest(t *testing.T) {
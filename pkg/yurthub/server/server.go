@@ -104,6 +104,14 @@ func registerHandlers(c *mux.Router, cfg *config.YurtHubConfiguration, healthChe
 	}
 	c.Handle("/openyurt.io/v1/namespaces/{ns}/pods/{podname}/upgrade",
 		ota.HealthyCheck(healthChecker, cfg.TransportAndDirectClientManager, cfg.NodeName, ota.UpdatePod)).Methods("POST")
+	c.Handle("/openyurt.io/v1/namespaces/{ns}/pods/{podname}/upgrade",
+		ota.HealthyCheck(healthChecker, cfg.TransportAndDirectClientManager, cfg.NodeName, ota.GetUpdateStatus)).Methods("GET")
+	c.Handle("/openyurt.io/v1/namespaces/{ns}/pods/{podname}/postpone",
+		ota.HealthyCheck(healthChecker, cfg.TransportAndDirectClientManager, cfg.NodeName, ota.PostponeUpdate)).Methods("POST", "DELETE")
+	c.Handle("/openyurt.io/v1/updates",
+		ota.HealthyCheck(healthChecker, cfg.TransportAndDirectClientManager, cfg.NodeName, ota.ListUpdates)).Methods("GET")
+	c.Handle("/openyurt.io/v1/updates/upgrade",
+		ota.HealthyCheck(healthChecker, cfg.TransportAndDirectClientManager, cfg.NodeName, ota.BatchUpdatePods)).Methods("POST")
 }
 
 // healthz returns ok for healthz request
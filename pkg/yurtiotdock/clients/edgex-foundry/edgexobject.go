@@ -31,13 +31,19 @@ type EdgexDock struct {
 	Version          string
 	CoreMetadataAddr string
 	CoreCommandAddr  string
+	MessageBusType   string
+	MessageBusAddr   string
 }
 
-func NewEdgexDock(version string, coreMetadataAddr string, coreCommandAddr string) *EdgexDock {
+var _ clients.IoTDock = &EdgexDock{}
+
+func NewEdgexDock(version string, coreMetadataAddr string, coreCommandAddr string, messageBusType string, messageBusAddr string) *EdgexDock {
 	return &EdgexDock{
 		Version:          version,
 		CoreMetadataAddr: coreMetadataAddr,
 		CoreCommandAddr:  coreCommandAddr,
+		MessageBusType:   messageBusType,
+		MessageBusAddr:   messageBusAddr,
 	}
 }
 
@@ -67,3 +73,27 @@ func (ep *EdgexDock) CreateDeviceServiceClient() (clients.DeviceServiceInterface
 		return nil, fmt.Errorf("unsupported Edgex version: %v", ep.Version)
 	}
 }
+
+func (ep *EdgexDock) CreateSystemEventClient() (clients.SystemEventInterface, error) {
+	if len(ep.MessageBusAddr) == 0 {
+		return nil, fmt.Errorf("the address of Edgex message bus is not specified")
+	}
+	switch ep.Version {
+	case "napa", "minnesota":
+		return edgexcliv3.NewEdgexSystemEventClient(ep.MessageBusType, ep.MessageBusAddr), nil
+	default:
+		return nil, fmt.Errorf("unsupported Edgex version: %v", ep.Version)
+	}
+}
+
+func (ep *EdgexDock) CreateDeviceReadingClient() (clients.DeviceReadingInterface, error) {
+	if len(ep.MessageBusAddr) == 0 {
+		return nil, fmt.Errorf("the address of Edgex message bus is not specified")
+	}
+	switch ep.Version {
+	case "napa", "minnesota":
+		return edgexcliv3.NewEdgexDeviceReadingClient(ep.MessageBusType, ep.MessageBusAddr), nil
+	default:
+		return nil, fmt.Errorf("unsupported Edgex version: %v", ep.Version)
+	}
+}
@@ -155,9 +155,9 @@ func (efc *EdgexDeviceClient) Get(ctx context.Context, deviceName string, option
 }
 
 // Convert is used to convert the device information in the systemEvent of messageBus to the device object in the kubernetes cluster
-func (cdc *EdgexDeviceClient) Convert(ctx context.Context, systemEvent dtos.SystemEvent, opts devcli.GetOptions) (*v1alpha1.Device, error) {
+func (cdc *EdgexDeviceClient) Convert(ctx context.Context, systemEvent devcli.SystemEvent, opts devcli.GetOptions) (*v1alpha1.Device, error) {
 	dto := dtos.Device{}
-	err := systemEvent.DecodeDetails(&dto)
+	err := json.Unmarshal(systemEvent.Details, &dto)
 	if err != nil {
 		klog.V(3).ErrorS(err, "fail to decode device systemEvent details")
 		return nil, err
@@ -210,8 +210,10 @@ func Test_ConvertDeviceSystemEvents(t *testing.T) {
 	if err != nil {
 		return
 	}
+	event, err := ToSystemEvent(dse)
+	assert.Nil(t, err)
 
-	device, err := deviceClient.Convert(context.TODO(), dse, clients.GetOptions{Namespace: "default"})
+	device, err := deviceClient.Convert(context.TODO(), event, clients.GetOptions{Namespace: "default"})
 	assert.Nil(t, err)
 	assert.Equal(t, "my-camera-device", device.Name)
 	assert.Equal(t, "device-onvif-camera", device.Spec.Service)
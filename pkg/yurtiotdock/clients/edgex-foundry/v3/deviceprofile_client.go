@@ -90,9 +90,9 @@ func (cdc *EdgexDeviceProfile) Get(ctx context.Context, name string, opts devcli
 }
 
 // Convert is used to convert the device profile information in the systemEvent of messageBus to the device profile object in the kubernetes cluster
-func (cdc *EdgexDeviceProfile) Convert(ctx context.Context, systemEvent dtos.SystemEvent, opts devcli.GetOptions) (*v1alpha1.DeviceProfile, error) {
+func (cdc *EdgexDeviceProfile) Convert(ctx context.Context, systemEvent devcli.SystemEvent, opts devcli.GetOptions) (*v1alpha1.DeviceProfile, error) {
 	dto := dtos.DeviceProfile{}
-	err := systemEvent.DecodeDetails(&dto)
+	err := json.Unmarshal(systemEvent.Details, &dto)
 	if err != nil {
 		klog.V(3).ErrorS(err, "fail to decode deviceprofile systemEvent details")
 		return nil, err
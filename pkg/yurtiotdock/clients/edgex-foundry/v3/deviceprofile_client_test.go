@@ -115,8 +115,10 @@ func Test_ConvertProfileSystemEvents(t *testing.T) {
 	if err != nil {
 		return
 	}
+	event, err := ToSystemEvent(dpse)
+	assert.Nil(t, err)
 
-	profile, err := profileClient.Convert(context.TODO(), dpse, clients.GetOptions{Namespace: "default"})
+	profile, err := profileClient.Convert(context.TODO(), event, clients.GetOptions{Namespace: "default"})
 	assert.Nil(t, err)
 	assert.Equal(t, "random-boolean-device", profile.Name)
 	assert.Equal(t, "Example of Device-Virtual", profile.Spec.Description)
@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"k8s.io/klog/v2"
+
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+const (
+	// DeviceEventTopic is the topic where device services publish the events of readings,
+	// <ServiceName>/<ProfileName>/<DeviceName>/<SourceName> are appended
+	DeviceEventTopic = "edgex/events/device"
+
+	deviceReadingBufferSize = 100
+)
+
+// EdgexDeviceReadingClient watches the readings published by EdgeX device services on the message bus
+type EdgexDeviceReadingClient struct {
+	MessageBusType string
+	MessageBusAddr string
+}
+
+var _ clients.DeviceReadingInterface = &EdgexDeviceReadingClient{}
+
+func NewEdgexDeviceReadingClient(messageBusType, messageBusAddr string) *EdgexDeviceReadingClient {
+	return &EdgexDeviceReadingClient{
+		MessageBusType: messageBusType,
+		MessageBusAddr: messageBusAddr,
+	}
+}
+
+// addEventRequest is the payload of the device events, the validation of AddEventRequest is skipped
+type addEventRequest struct {
+	Event dtos.Event `json:"event"`
+}
+
+// Watch subscribes the events of devices on the message bus and converts the readings in them
+func (erc *EdgexDeviceReadingClient) Watch(ctx context.Context) (<-chan clients.DeviceReading, error) {
+	stream := clients.NewReadingStream(deviceReadingBufferSize)
+	handler := func(payload []byte) {
+		readings, err := decodeDeviceReadings(payload)
+		if err != nil {
+			klog.V(4).ErrorS(err, "could not decode the device event from message bus")
+			return
+		}
+		for i := range readings {
+			if !stream.Send(readings[i]) {
+				return
+			}
+		}
+	}
+
+	bus := &messageBus{Type: erc.MessageBusType, Addr: erc.MessageBusAddr}
+	if err := bus.subscribe(ctx, DeviceEventTopic+"/#", handler, stream.Done(), stream.Close); err != nil {
+		return nil, err
+	}
+	return stream.ResultChan(), nil
+}
+
+// decodeDeviceReadings converts the readings of the device event in the payload of message,
+// binary and object readings are skipped
+func decodeDeviceReadings(payload []byte) ([]clients.DeviceReading, error) {
+	var req addEventRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, err
+	}
+
+	readings := make([]clients.DeviceReading, 0, len(req.Event.Readings))
+	for _, r := range req.Event.Readings {
+		if len(r.BinaryValue) != 0 || r.ObjectValue != nil {
+			continue
+		}
+		readings = append(readings, clients.DeviceReading{
+			DeviceName:   r.DeviceName,
+			PropertyName: r.ResourceName,
+			Value:        r.Value,
+			ValueType:    r.ValueType,
+			Timestamp:    r.Origin,
+		})
+	}
+	return readings, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+const DeviceEvent = `{"apiVersion":"v3","event":{"apiVersion":"v3","id":"095090e4-de39-45a1-a0fa-18bc340104e6","deviceName":"Random-Float-Device","profileName":"Random-Float-Device","sourceName":"Float32","origin":1661851070562067780,"readings":[{"id":"972bf6be-3b01-49fc-b211-a43ed51d207d","origin":1661851070562067780,"deviceName":"Random-Float-Device","resourceName":"Float32","profileName":"Random-Float-Device","valueType":"Float32","value":"-2.038811e+38"},{"id":"a4b8a0e5-0a52-4d42-9b6d-7a1e0e0b1b39","origin":1661851070562067780,"deviceName":"Random-Float-Device","resourceName":"Snapshot","profileName":"Random-Float-Device","valueType":"Binary","binaryValue":"aGVsbG8=","mediaType":"image/jpeg"}]}}`
+
+func Test_DecodeDeviceReadings(t *testing.T) {
+	testcases := map[string]struct {
+		payload  []byte
+		err      bool
+		expected []clients.DeviceReading
+	}{
+		"simple readings are converted": {
+			payload: []byte(DeviceEvent),
+			expected: []clients.DeviceReading{
+				{
+					DeviceName:   "Random-Float-Device",
+					PropertyName: "Float32",
+					Value:        "-2.038811e+38",
+					ValueType:    "Float32",
+					Timestamp:    1661851070562067780,
+				},
+			},
+		},
+		"invalid event": {
+			payload: []byte(`{"event":"invalid"}`),
+			err:     true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			readings, err := decodeDeviceReadings(tc.payload)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, readings)
+		})
+	}
+}
@@ -82,9 +82,9 @@ func (eds *EdgexDeviceServiceClient) Create(ctx context.Context, deviceService *
 }
 
 // Convert is used to convert the device service information in the systemEvent of messageBus to the device service object in the kubernetes cluster
-func (cdc *EdgexDeviceServiceClient) Convert(ctx context.Context, systemEvent dtos.SystemEvent, opts devcli.GetOptions) (*v1alpha1.DeviceService, error) {
+func (cdc *EdgexDeviceServiceClient) Convert(ctx context.Context, systemEvent devcli.SystemEvent, opts devcli.GetOptions) (*v1alpha1.DeviceService, error) {
 	dto := dtos.DeviceService{}
-	err := systemEvent.DecodeDetails(&dto)
+	err := json.Unmarshal(systemEvent.Details, &dto)
 	if err != nil {
 		klog.V(3).ErrorS(err, "fail to decode deviceservice systemEvent details")
 		return nil, err
@@ -134,8 +134,10 @@ func Test_ConvertServiceSystemEvents(t *testing.T) {
 	if err != nil {
 		return
 	}
+	event, err := ToSystemEvent(dsse)
+	assert.Nil(t, err)
 
-	service, err := serviceClient.Convert(context.TODO(), dsse, clients.GetOptions{Namespace: "default"})
+	service, err := serviceClient.Convert(context.TODO(), event, clients.GetOptions{Namespace: "default"})
 	assert.Nil(t, err)
 	assert.Equal(t, "device-virtual", service.Name)
 	assert.Equal(t, "http://edgex-device-virtual:59900", service.Spec.BaseAddress)
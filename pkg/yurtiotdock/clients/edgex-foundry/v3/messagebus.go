@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/messaging"
+	"github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
+	"github.com/google/uuid"
+	"k8s.io/klog/v2"
+)
+
+const (
+	MessageBusTypeRedis = messaging.Redis
+	MessageBusTypeMQTT  = messaging.MQTT
+
+	messageBufferSize = 100
+	// messageBusCloseTimeout bounds the time to wait for the messaging client to stop its subscription
+	messageBusCloseTimeout = 5 * time.Second
+)
+
+// messageBus subscribes the topics on the EdgeX message bus by the messaging client of EdgeX,
+// which converts the topics for redis and reconnects to the broker when the connection is lost
+type messageBus struct {
+	Type string
+	Addr string
+}
+
+// newClient creates the messaging client of the message bus
+func (mb *messageBus) newClient() (messaging.MessageClient, error) {
+	host, port, err := net.SplitHostPort(mb.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address of edgex message bus %s, %w", mb.Addr, err)
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port of edgex message bus %s, %w", mb.Addr, err)
+	}
+
+	config := types.MessageBusConfig{
+		Broker:   types.HostInfo{Host: host, Port: portNum},
+		Type:     mb.Type,
+		Optional: map[string]string{},
+	}
+	switch mb.Type {
+	case MessageBusTypeRedis:
+		config.Broker.Protocol = "redis"
+	case MessageBusTypeMQTT:
+		config.Broker.Protocol = "tcp"
+		config.Optional["ClientId"] = "yurt-iot-dock-" + uuid.New().String()[:8]
+		config.Optional["AutoReconnect"] = "true"
+	default:
+		return nil, fmt.Errorf("unsupported edgex message bus type: %s", mb.Type)
+	}
+	return messaging.NewMessageClient(config)
+}
+
+// subscribe delivers the payloads of messages on topic to handler in background until ctx is done or stop is closed.
+// onClose is called when the subscription stops, it should make the pending handler return.
+func (mb *messageBus) subscribe(ctx context.Context, topic string, handler func(payload []byte), stop <-chan struct{}, onClose func()) error {
+	client, err := mb.newClient()
+	if err != nil {
+		return err
+	}
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("could not connect to edgex message bus %s, %w", mb.Addr, err)
+	}
+	messages := make(chan types.MessageEnvelope, messageBufferSize)
+	errs := make(chan error, messageBufferSize)
+	if err := client.Subscribe([]types.TopicChannel{{Topic: topic, Messages: messages}}, errs); err != nil {
+		client.Disconnect()
+		return fmt.Errorf("could not subscribe %s on edgex message bus %s, %w", topic, mb.Addr, err)
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop:
+		}
+		onClose()
+	}()
+	go func() {
+		defer closeClient(client, topic, messages, errs)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stop:
+				return
+			case message := <-messages:
+				handler(message.Payload)
+			case err := <-errs:
+				klog.V(3).ErrorS(err, "could not receive message from edgex message bus", "topic", topic)
+			}
+		}
+	}()
+	return nil
+}
+
+// closeClient unsubscribes the topic and disconnects from the message bus, the channels of subscription
+// are drained in the meantime so that the messaging client is not blocked by sending to them
+func closeClient(client messaging.MessageClient, topic string, messages <-chan types.MessageEnvelope, errs <-chan error) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := client.Unsubscribe(topic); err != nil {
+			klog.V(4).ErrorS(err, "could not unsubscribe from edgex message bus", "topic", topic)
+		}
+		if err := client.Disconnect(); err != nil {
+			klog.V(4).ErrorS(err, "could not disconnect from edgex message bus")
+		}
+	}()
+
+	timeout := time.After(messageBusCloseTimeout)
+	for {
+		select {
+		case <-messages:
+		case <-errs:
+		case <-done:
+			return
+		case <-timeout:
+			return
+		}
+	}
+}
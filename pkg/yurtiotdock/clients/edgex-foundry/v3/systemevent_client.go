@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"k8s.io/klog/v2"
+
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+const (
+	// SystemEventTopic is the topic where core-metadata publishes the system events,
+	// <SystemEventType>/<SystemEventAction>/<OwnerServiceName>/<ProfileName> are appended
+	SystemEventTopic = "edgex/system-events/core-metadata"
+
+	systemEventBufferSize = 100
+)
+
+// EdgexSystemEventClient watches the system events published by EdgeX core-metadata on the message bus
+type EdgexSystemEventClient struct {
+	MessageBusType string
+	MessageBusAddr string
+}
+
+var _ clients.SystemEventInterface = &EdgexSystemEventClient{}
+
+func NewEdgexSystemEventClient(messageBusType, messageBusAddr string) *EdgexSystemEventClient {
+	return &EdgexSystemEventClient{
+		MessageBusType: messageBusType,
+		MessageBusAddr: messageBusAddr,
+	}
+}
+
+// Watch subscribes the system events of core-metadata on the message bus
+func (esc *EdgexSystemEventClient) Watch(ctx context.Context, options clients.WatchOptions) (<-chan clients.SystemEvent, error) {
+	stream := clients.NewEventStream(systemEventBufferSize)
+	handler := func(payload []byte) {
+		event, err := decodeSystemEvent(payload)
+		if err != nil {
+			klog.V(4).ErrorS(err, "could not decode the system event from message bus")
+			return
+		}
+		if len(options.Type) != 0 && event.Type != options.Type {
+			return
+		}
+		stream.Send(event)
+	}
+
+	bus := &messageBus{Type: esc.MessageBusType, Addr: esc.MessageBusAddr}
+	if err := bus.subscribe(ctx, SystemEventTopic+"/#", handler, stream.Done(), stream.Close); err != nil {
+		return nil, err
+	}
+	return stream.ResultChan(), nil
+}
+
+// decodeSystemEvent converts the system event in the payload of message
+func decodeSystemEvent(payload []byte) (clients.SystemEvent, error) {
+	var dse dtos.SystemEvent
+	if err := json.Unmarshal(payload, &dse); err != nil {
+		return clients.SystemEvent{}, err
+	}
+	return ToSystemEvent(dse)
+}
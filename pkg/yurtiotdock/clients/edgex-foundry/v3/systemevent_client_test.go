@@ -0,0 +1,122 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+// newEnvelope encodes the payload in the message envelope published on the EdgeX message bus
+func newEnvelope(t *testing.T, payload string) []byte {
+	message, err := json.Marshal(types.NewMessageEnvelope([]byte(payload), context.TODO()))
+	if err != nil {
+		t.Fatalf("could not encode the message envelope, %v", err)
+	}
+	return message
+}
+
+func Test_DecodeSystemEvent(t *testing.T) {
+	testcases := map[string]struct {
+		payload []byte
+		err     bool
+		action  string
+	}{
+		"system event": {
+			payload: []byte(DeviceSystemEvent),
+			action:  clients.SystemEventActionAdd,
+		},
+		"invalid payload": {
+			payload: []byte(`not-json`),
+			err:     true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			event, err := decodeSystemEvent(tc.payload)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, clients.SystemEventTypeDevice, event.Type)
+			assert.Equal(t, tc.action, event.Action)
+			assert.Equal(t, int64(1639279435789056000), event.Timestamp)
+
+			device, err := deviceClient.Convert(context.TODO(), event, clients.GetOptions{Namespace: "default"})
+			assert.NoError(t, err)
+			assert.Equal(t, "my-camera-device", device.Name)
+		})
+	}
+}
+
+func Test_WatchMQTT(t *testing.T) {
+	server := mqttserver.New(&mqttserver.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	assert.NoError(t, server.AddHook(new(auth.AllowHook), nil))
+	listener := listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	assert.NoError(t, server.AddListener(listener))
+	assert.NoError(t, server.Serve())
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	esc := NewEdgexSystemEventClient(MessageBusTypeMQTT, listener.Address())
+	events, err := esc.Watch(ctx, clients.WatchOptions{Type: clients.SystemEventTypeDevice})
+	assert.NoError(t, err)
+
+	profileEvent := `{"apiVersion":"v3","type":"deviceprofile","action":"add","source":"core-metadata","details":{"name":"onvif-camera"},"timestamp":1639279435789056000}`
+	assert.NoError(t, server.Publish(SystemEventTopic+"/deviceprofile/add", newEnvelope(t, profileEvent), false, 0))
+	assert.NoError(t, server.Publish(SystemEventTopic+"/device/add/device-onvif-camera/onvif-camera", newEnvelope(t, DeviceSystemEvent), false, 0))
+
+	select {
+	case event := <-events:
+		assert.Equal(t, clients.SystemEventTypeDevice, event.Type)
+		assert.Equal(t, clients.SystemEventActionAdd, event.Action)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for the system event")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the channel of events is not closed after ctx is done")
+	}
+}
+
+func Test_WatchUnsupportedMessageBus(t *testing.T) {
+	esc := NewEdgexSystemEventClient("kafka", "edgex-kafka:9092")
+	_, err := esc.Watch(context.TODO(), clients.WatchOptions{})
+	assert.Error(t, err)
+}
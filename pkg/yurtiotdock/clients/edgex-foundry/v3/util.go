@@ -17,6 +17,7 @@ limitations under the License.
 package v3
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -27,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
 	util "github.com/openyurtio/openyurt/pkg/yurtiotdock/controllers/util"
 )
 
@@ -287,10 +289,10 @@ func toKubeDeviceProfile(dp *dtos.DeviceProfile, namespace string) iotv1alpha1.D
 	}
 }
 
-func toKubeDeviceCommand(dcs []dtos.DeviceCommand) []iotv1alpha1.DeviceCommand {
-	var ret []iotv1alpha1.DeviceCommand
+func toKubeDeviceCommand(dcs []dtos.DeviceCommand) []iotv1alpha1.DeviceProfileCommand {
+	var ret []iotv1alpha1.DeviceProfileCommand
 	for _, dc := range dcs {
-		ret = append(ret, iotv1alpha1.DeviceCommand{
+		ret = append(ret, iotv1alpha1.DeviceProfileCommand{
 			Name:               dc.Name,
 			ReadWrite:          dc.ReadWrite,
 			IsHidden:           dc.IsHidden,
@@ -300,7 +302,7 @@ func toKubeDeviceCommand(dcs []dtos.DeviceCommand) []iotv1alpha1.DeviceCommand {
 	return ret
 }
 
-func toEdgeXDeviceCommand(dcs []iotv1alpha1.DeviceCommand) []dtos.DeviceCommand {
+func toEdgeXDeviceCommand(dcs []iotv1alpha1.DeviceProfileCommand) []dtos.DeviceCommand {
 	var ret []dtos.DeviceCommand
 	for _, dc := range dcs {
 		ret = append(ret, dtos.DeviceCommand{
@@ -470,3 +472,19 @@ func makeEdgeXDeviceService(dss []*iotv1alpha1.DeviceService) []*requests.AddDev
 func toKubeName(edgexName string) string {
 	return strings.ReplaceAll(strings.ToLower(edgexName), "_", "-")
 }
+
+// ToSystemEvent converts the EdgeX system event to the platform neutral system event,
+// the details of event are kept in the format of EdgeX DTO
+func ToSystemEvent(event dtos.SystemEvent) (clients.SystemEvent, error) {
+	details, err := json.Marshal(event.Details)
+	if err != nil {
+		return clients.SystemEvent{}, err
+	}
+	return clients.SystemEvent{
+		Type:      event.Type,
+		Action:    event.Action,
+		Source:    event.Source,
+		Details:   details,
+		Timestamp: event.Timestamp,
+	}, nil
+}
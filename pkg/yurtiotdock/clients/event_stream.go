@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"sync"
+)
+
+// Stream delivers items such as system events or device readings to the watcher,
+// items can be sent and the stream can be closed concurrently
+type Stream[T any] struct {
+	result chan T
+	done   chan struct{}
+
+	lock      sync.Mutex
+	closeOnce sync.Once
+}
+
+// EventStream delivers system events to the watcher
+type EventStream = Stream[SystemEvent]
+
+// ReadingStream delivers device readings to the watcher
+type ReadingStream = Stream[DeviceReading]
+
+// NewStream creates a stream whose result channel buffers size items
+func NewStream[T any](size int) *Stream[T] {
+	return &Stream[T]{
+		result: make(chan T, size),
+		done:   make(chan struct{}),
+	}
+}
+
+// NewEventStream creates a stream whose result channel buffers size events
+func NewEventStream(size int) *EventStream {
+	return NewStream[SystemEvent](size)
+}
+
+// NewReadingStream creates a stream whose result channel buffers size readings
+func NewReadingStream(size int) *ReadingStream {
+	return NewStream[DeviceReading](size)
+}
+
+// ResultChan returns the channel which delivers items to the watcher
+func (s *Stream[T]) ResultChan() <-chan T {
+	return s.result
+}
+
+// Done returns a channel which is closed when the stream is closed
+func (s *Stream[T]) Done() <-chan struct{} {
+	return s.done
+}
+
+// Send blocks until the item is received by the watcher, it returns false if the stream is closed
+func (s *Stream[T]) Send(item T) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	select {
+	case <-s.done:
+		return false
+	default:
+	}
+
+	select {
+	case s.result <- item:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// Close stops the stream and closes the result channel
+func (s *Stream[T]) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		// wait for the pending Send to return before closing the result channel
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		close(s.result)
+	})
+}
@@ -19,8 +19,6 @@ package clients
 import (
 	"context"
 
-	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
-
 	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
 )
 
@@ -59,6 +57,42 @@ type ListOptions struct {
 	Namespace string
 }
 
+// WatchOptions defines additional options when watching system events
+type WatchOptions struct {
+	// Type restricts the events to the type of objects, such as device, deviceprofile or deviceservice.
+	// Defaults to all types.
+	// +optional
+	Type string
+}
+
+// SystemEvent is a platform neutral notification which indicates that an object is changed on edge-side platform
+type SystemEvent struct {
+	// Type is the kind of the changed object, such as device, deviceprofile or deviceservice
+	Type string
+	// Action is the change of the object, such as add, update or delete
+	Action string
+	// Source is the component of edge-side platform which emits the event
+	Source string
+	// Details is the changed object encoded in the format of edge-side platform
+	Details []byte
+	// Timestamp is the time in nanoseconds when the object is changed
+	Timestamp int64
+}
+
+// DeviceReading is a platform neutral value of the device property reported by the device on edge-side platform
+type DeviceReading struct {
+	// DeviceName is the name of device on edge-side platform
+	DeviceName string
+	// PropertyName is the name of the property, such as the resource name of EdgeX
+	PropertyName string
+	// Value is the reported value of the property in string format
+	Value string
+	// ValueType is the type of the value, such as Int32 or Float64, empty if it is unknown
+	ValueType string
+	// Timestamp is the time in nanoseconds when the value is reported
+	Timestamp int64
+}
+
 // DeviceInterface defines the interfaces which used to create, delete, update, get and list Device objects on edge-side platform
 type DeviceInterface interface {
 	DevicePropertyInterface
@@ -68,7 +102,7 @@ type DeviceInterface interface {
 	Get(ctx context.Context, name string, options GetOptions) (*iotv1alpha1.Device, error)
 	List(ctx context.Context, options ListOptions) ([]iotv1alpha1.Device, error)
 
-	Convert(ctx context.Context, systemEvent dtos.SystemEvent, options GetOptions) (*iotv1alpha1.Device, error)
+	Convert(ctx context.Context, systemEvent SystemEvent, options GetOptions) (*iotv1alpha1.Device, error)
 }
 
 // DevicePropertyInterface defines the interfaces which used to get, list and set the actual status value of the device properties
@@ -86,7 +120,7 @@ type DeviceServiceInterface interface {
 	Get(ctx context.Context, name string, options GetOptions) (*iotv1alpha1.DeviceService, error)
 	List(ctx context.Context, options ListOptions) ([]iotv1alpha1.DeviceService, error)
 
-	Convert(ctx context.Context, systemEvent dtos.SystemEvent, opts GetOptions) (*iotv1alpha1.DeviceService, error)
+	Convert(ctx context.Context, systemEvent SystemEvent, opts GetOptions) (*iotv1alpha1.DeviceService, error)
 }
 
 // DeviceProfileInterface defines the interfaces which used to create, delete, update, get and list DeviceProfile objects on edge-side platform
@@ -97,12 +131,40 @@ type DeviceProfileInterface interface {
 	Get(ctx context.Context, name string, options GetOptions) (*iotv1alpha1.DeviceProfile, error)
 	List(ctx context.Context, options ListOptions) ([]iotv1alpha1.DeviceProfile, error)
 
-	Convert(ctx context.Context, systemEvent dtos.SystemEvent, options GetOptions) (*iotv1alpha1.DeviceProfile, error)
+	Convert(ctx context.Context, systemEvent SystemEvent, options GetOptions) (*iotv1alpha1.DeviceProfile, error)
+}
+
+// Types and actions of SystemEvent
+const (
+	SystemEventTypeDevice        = "device"
+	SystemEventTypeDeviceProfile = "deviceprofile"
+	SystemEventTypeDeviceService = "deviceservice"
+
+	SystemEventActionAdd    = "add"
+	SystemEventActionUpdate = "update"
+	SystemEventActionDelete = "delete"
+)
+
+// SystemEventInterface defines the interfaces which used to watch the change notifications of objects on edge-side platform
+type SystemEventInterface interface {
+	// Watch subscribes the system events of edge-side platform. The returned channel is closed when
+	// the subscription is broken or ctx is done, callers should resync the objects before watching again.
+	Watch(ctx context.Context, options WatchOptions) (<-chan SystemEvent, error)
+}
+
+// DeviceReadingInterface defines the interfaces which used to watch the readings reported by devices on edge-side platform
+type DeviceReadingInterface interface {
+	// Watch subscribes the readings of all devices. The returned channel is closed when
+	// the subscription is broken or ctx is done.
+	Watch(ctx context.Context) (<-chan DeviceReading, error)
 }
 
-// IoTDock defines the interfaces which used to create deviceclient, deviceprofileclient, deviceserviceclient
+// IoTDock defines the interfaces which used to create deviceclient, deviceprofileclient, deviceserviceclient,
+// systemeventclient and devicereadingclient
 type IoTDock interface {
 	CreateDeviceClient() (DeviceInterface, error)
 	CreateDeviceProfileClient() (DeviceProfileInterface, error)
 	CreateDeviceServiceClient() (DeviceServiceInterface, error)
+	CreateSystemEventClient() (SystemEventInterface, error)
+	CreateDeviceReadingClient() (DeviceReadingInterface, error)
 }
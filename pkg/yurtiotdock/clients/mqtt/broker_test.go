@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+)
+
+// testBroker is an embedded MQTT broker used as the stand-in of the device registry in tests
+type testBroker struct {
+	server   *mqttserver.Server
+	listener *listeners.TCP
+}
+
+func newTestBroker(t *testing.T) *testBroker {
+	server := mqttserver.New(&mqttserver.Options{
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err := server.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatalf("could not add auth hook to test broker, %v", err)
+	}
+	listener := listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	if err := server.AddListener(listener); err != nil {
+		t.Fatalf("could not start test broker, %v", err)
+	}
+	if err := server.Serve(); err != nil {
+		t.Fatalf("could not serve test broker, %v", err)
+	}
+	t.Cleanup(func() {
+		server.Close()
+	})
+	return &testBroker{server: server, listener: listener}
+}
+
+func (b *testBroker) addr() string {
+	return b.listener.Address()
+}
+
+// dropConnections closes all client connections to simulate the broker restart
+func (b *testBroker) dropConnections() {
+	for _, cl := range b.server.Clients.GetAll() {
+		if !cl.Net.Inline {
+			cl.Stop(errors.New("broker restarted"))
+		}
+	}
+}
+
+// retainedMessage returns the retained message of topic
+func (b *testBroker) retainedMessage(topic string) ([]byte, bool) {
+	pk, ok := b.server.Topics.Retained.Get(topic)
+	if !ok || len(pk.Payload) == 0 {
+		return nil, false
+	}
+	return pk.Payload, true
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	defaultKeepAlive   = 30 * time.Second
+	defaultDialTimeout = 10 * time.Second
+	// disconnectQuiesce is the time in milliseconds to wait for the in-flight work before disconnecting
+	disconnectQuiesce = 250
+)
+
+var errClientClosed = errors.New("mqtt client is closed")
+
+// MessageHandler is called for each message received from the subscribed topics
+type MessageHandler func(topic string, payload []byte, retained bool)
+
+// ConnectOptions defines the options used to connect to the MQTT broker
+type ConnectOptions struct {
+	ClientID  string
+	Username  string
+	Password  string
+	KeepAlive time.Duration
+}
+
+// Client wraps the paho MQTT client. The connection is not re-established automatically, the owner of
+// the client is expected to dial again after Done is closed, so that the retained messages are replayed
+// by the broker for the new subscription.
+type Client struct {
+	client paho.Client
+
+	lock    sync.Mutex
+	done    chan struct{}
+	closeMu sync.Once
+	err     error
+}
+
+// Dial connects to the MQTT broker at addr, the handler receives all messages of subscribed topics
+// in the order they are received
+func Dial(ctx context.Context, addr string, opts ConnectOptions, handler MessageHandler) (*Client, error) {
+	if opts.KeepAlive <= 0 {
+		opts.KeepAlive = defaultKeepAlive
+	}
+	c := &Client{done: make(chan struct{})}
+
+	options := paho.NewClientOptions().
+		AddBroker(brokerURL(addr)).
+		SetClientID(opts.ClientID).
+		SetUsername(opts.Username).
+		SetPassword(opts.Password).
+		SetKeepAlive(opts.KeepAlive).
+		SetConnectTimeout(defaultDialTimeout).
+		SetCleanSession(true).
+		SetOrderMatters(true).
+		SetAutoReconnect(false).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			c.shutdown(err)
+		})
+	if handler != nil {
+		options.SetDefaultPublishHandler(func(_ paho.Client, msg paho.Message) {
+			handler(msg.Topic(), msg.Payload(), msg.Retained())
+		})
+	}
+
+	c.client = paho.NewClient(options)
+	if err := wait(ctx, c.client.Connect()); err != nil {
+		c.client.Disconnect(0)
+		return nil, err
+	}
+	return c, nil
+}
+
+// Publish sends the payload to topic, the call blocks until PUBACK is received when qos is 1
+func (c *Client) Publish(ctx context.Context, topic string, payload []byte, qos byte, retain bool) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	return c.wait(ctx, c.client.Publish(topic, qos, retain, payload))
+}
+
+// Subscribe subscribes the topic filter, the call blocks until SUBACK is received.
+// Messages of the subscription are delivered to the handler passed to Dial.
+func (c *Client) Subscribe(ctx context.Context, filter string, qos byte) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	token := c.client.Subscribe(filter, qos, nil)
+	if err := c.wait(ctx, token); err != nil {
+		return err
+	}
+	if code, ok := token.(*paho.SubscribeToken).Result()[filter]; ok && code == 0x80 {
+		return fmt.Errorf("subscription of %s is rejected by broker", filter)
+	}
+	return nil
+}
+
+// Done returns a channel which is closed when the connection is lost or closed
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the reason why the connection is closed
+func (c *Client) Err() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.err
+}
+
+// Close disconnects from the broker
+func (c *Client) Close() error {
+	c.client.Disconnect(disconnectQuiesce)
+	c.shutdown(errClientClosed)
+	return nil
+}
+
+// wait waits for the token, and returns the error of connection if it is lost in the meantime
+func (c *Client) wait(ctx context.Context, token paho.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-c.done:
+		return c.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) shutdown(err error) {
+	c.closeMu.Do(func() {
+		c.lock.Lock()
+		c.err = err
+		c.lock.Unlock()
+		close(c.done)
+	})
+}
+
+func wait(ctx context.Context, token paho.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// brokerURL adds the tcp scheme to addr if it is a plain host:port
+func brokerURL(addr string) string {
+	if strings.Contains(addr, "://") {
+		return addr
+	}
+	return "tcp://" + addr
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+type MQTTDeviceClient struct {
+	registry *registry
+}
+
+var _ clients.DeviceInterface = &MQTTDeviceClient{}
+
+// Create function publishes the device record to the registry
+func (mdc *MQTTDeviceClient) Create(ctx context.Context, device *iotv1alpha1.Device, options clients.CreateOptions) (*iotv1alpha1.Device, error) {
+	name := getObjectName(device)
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	klog.V(5).Infof("will add the Device: %s", name)
+	if _, exist, err := mdc.getRecord(ctx, name); err != nil {
+		return nil, err
+	} else if exist {
+		return nil, fmt.Errorf("device %s already exists", name)
+	}
+
+	record := toDeviceRecord(device, "")
+	if err := mdc.publishRecord(ctx, record); err != nil {
+		return nil, err
+	}
+	createdDevice := device.DeepCopy()
+	createdDevice.Status.EdgeId = record.Id
+	createdDevice.Status.Synced = true
+	return createdDevice, nil
+}
+
+// Delete function clears the retained device record in the registry
+func (mdc *MQTTDeviceClient) Delete(ctx context.Context, name string, options clients.DeleteOptions) error {
+	klog.V(5).Infof("will delete the Device: %s", name)
+	if err := validateName(name); err != nil {
+		return err
+	}
+	return mdc.registry.publish(ctx, mdc.registry.topic(DevicesTopic, name), nil, true)
+}
+
+// Update function replaces the device record in the registry, the id and reported metrics are kept
+func (mdc *MQTTDeviceClient) Update(ctx context.Context, device *iotv1alpha1.Device, options clients.UpdateOptions) (*iotv1alpha1.Device, error) {
+	if device == nil {
+		return nil, nil
+	}
+	name := getObjectName(device)
+	old, exist, err := mdc.getRecord(ctx, name)
+	if err != nil {
+		return nil, err
+	} else if !exist {
+		return nil, fmt.Errorf("could not update device: %s, device not found", name)
+	}
+
+	record := toDeviceRecord(device, old.Id)
+	record.LastConnected, record.LastReported = old.LastConnected, old.LastReported
+	if err := mdc.publishRecord(ctx, record); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// Get is used to query the device information corresponding to the device name
+func (mdc *MQTTDeviceClient) Get(ctx context.Context, deviceName string, options clients.GetOptions) (*iotv1alpha1.Device, error) {
+	klog.V(5).Infof("will get Devices: %s", deviceName)
+	record, exist, err := mdc.getRecord(ctx, deviceName)
+	if err != nil {
+		return nil, err
+	} else if !exist {
+		return nil, fmt.Errorf("Device %s not found", deviceName)
+	}
+	device := toKubeDevice(record, options.Namespace)
+	return &device, nil
+}
+
+// List is used to get all device objects in the registry
+func (mdc *MQTTDeviceClient) List(ctx context.Context, options clients.ListOptions) ([]iotv1alpha1.Device, error) {
+	payloads, err := mdc.registry.list(ctx, mdc.registry.topic(DevicesTopic))
+	if err != nil {
+		return nil, err
+	}
+	var res []iotv1alpha1.Device
+	for name, payload := range payloads {
+		var record Device
+		if err := json.Unmarshal(payload, &record); err != nil {
+			klog.V(4).ErrorS(err, "could not decode the device record", "DeviceName", name)
+			continue
+		}
+		res = append(res, toKubeDevice(record, options.Namespace))
+	}
+	return res, nil
+}
+
+// Convert is used to convert the device record in the systemEvent to the device object in the kubernetes cluster
+func (mdc *MQTTDeviceClient) Convert(ctx context.Context, systemEvent clients.SystemEvent, opts clients.GetOptions) (*iotv1alpha1.Device, error) {
+	var record Device
+	if err := json.Unmarshal(systemEvent.Details, &record); err != nil {
+		klog.V(3).ErrorS(err, "fail to decode device systemEvent details")
+		return nil, err
+	}
+	device := toKubeDevice(record, opts.Namespace)
+	return &device, nil
+}
+
+// GetPropertyState returns the latest value reported by the device on the property topic
+func (mdc *MQTTDeviceClient) GetPropertyState(ctx context.Context, propertyName string, d *iotv1alpha1.Device, options clients.GetOptions) (*iotv1alpha1.ActualPropertyState, error) {
+	topic := mdc.registry.topic(DevicesTopic, getObjectName(d), PropertiesTopic, propertyName)
+	payload, exist, err := mdc.registry.get(ctx, topic)
+	if err != nil {
+		return nil, err
+	} else if !exist {
+		return nil, &clients.NotFoundError{}
+	}
+	return &iotv1alpha1.ActualPropertyState{
+		Name:        propertyName,
+		ActualValue: string(payload),
+	}, nil
+}
+
+// UpdatePropertyState publishes the desired value of the property to the set topic of the property
+func (mdc *MQTTDeviceClient) UpdatePropertyState(ctx context.Context, propertyName string, d *iotv1alpha1.Device, options clients.UpdateOptions) error {
+	dps, ok := d.Spec.DeviceProperties[propertyName]
+	if !ok {
+		return fmt.Errorf("desired state of property %s is not found", propertyName)
+	}
+	if err := validateName(dps.Name); err != nil {
+		return err
+	}
+	klog.V(5).Info("setting the property to desired value", "propertyName", dps.Name, "desiredValue", dps.DesiredValue)
+	topic := mdc.registry.topic(DevicesTopic, getObjectName(d), PropertiesTopic, dps.Name, SetTopic)
+	if err := mdc.registry.publish(ctx, topic, []byte(dps.DesiredValue), false); err != nil {
+		return fmt.Errorf("could not set property: %s, %w", dps.Name, err)
+	}
+	return nil
+}
+
+// ListPropertiesState gets all the actual property values reported by the device
+func (mdc *MQTTDeviceClient) ListPropertiesState(ctx context.Context, device *iotv1alpha1.Device, options clients.ListOptions) (map[string]iotv1alpha1.DesiredPropertyState, map[string]iotv1alpha1.ActualPropertyState, error) {
+	dpsm := map[string]iotv1alpha1.DesiredPropertyState{}
+	apsm := map[string]iotv1alpha1.ActualPropertyState{}
+	payloads, err := mdc.registry.list(ctx, mdc.registry.topic(DevicesTopic, getObjectName(device), PropertiesTopic))
+	if err != nil {
+		return dpsm, apsm, err
+	}
+	for name, payload := range payloads {
+		apsm[name] = iotv1alpha1.ActualPropertyState{Name: name, ActualValue: string(payload)}
+	}
+	return dpsm, apsm, nil
+}
+
+func (mdc *MQTTDeviceClient) getRecord(ctx context.Context, name string) (Device, bool, error) {
+	var record Device
+	payload, exist, err := mdc.registry.get(ctx, mdc.registry.topic(DevicesTopic, name))
+	if err != nil || !exist {
+		return record, exist, err
+	}
+	if err := json.Unmarshal(payload, &record); err != nil {
+		return record, false, err
+	}
+	return record, true, nil
+}
+
+func (mdc *MQTTDeviceClient) publishRecord(ctx context.Context, record Device) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return mdc.registry.publish(ctx, mdc.registry.topic(DevicesTopic, record.Name), payload, true)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+const DeviceRecord = `{"id":"2fff4f1a-7110-442f-b347-9f896338ba57","name":"Random-Float-Device","description":"Example of Device Virtual","adminState":"UNLOCKED","operatingState":"UP","protocols":{"other":{"Address":"device-virtual-float-01","Protocol":"300"}},"labels":["device-virtual-example"],"serviceName":"device-virtual","profileName":"Random-Float-Device"}`
+
+func newTestDevice(name string) *iotv1alpha1.Device {
+	return &iotv1alpha1.Device{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: iotv1alpha1.DeviceSpec{
+			Description:    "test device",
+			AdminState:     iotv1alpha1.UnLocked,
+			OperatingState: iotv1alpha1.Up,
+			Protocols: map[string]iotv1alpha1.ProtocolProperties{
+				"mqtt": {"topic": "sensors/" + name},
+			},
+			Service: "device-mqtt",
+			Profile: "sensor",
+			DeviceProperties: map[string]iotv1alpha1.DesiredPropertyState{
+				"switch": {Name: "switch", DesiredValue: "on"},
+			},
+		},
+	}
+}
+
+func newTestDeviceClient(t *testing.T, b *testBroker) *MQTTDeviceClient {
+	dock := NewMQTTDock(b.addr(), "", "")
+	cli, err := dock.CreateDeviceClient()
+	assert.Nil(t, err)
+	return cli.(*MQTTDeviceClient)
+}
+
+func Test_CreateAndGetDevice(t *testing.T) {
+	b := newTestBroker(t)
+	deviceClient := newTestDeviceClient(t, b)
+
+	created, err := deviceClient.Create(context.TODO(), newTestDevice("sensor-1"), clients.CreateOptions{})
+	assert.Nil(t, err)
+	assert.True(t, created.Status.Synced)
+	assert.NotEmpty(t, created.Status.EdgeId)
+
+	_, ok := b.retainedMessage("openyurt/iot/devices/sensor-1")
+	assert.True(t, ok)
+
+	_, err = deviceClient.Create(context.TODO(), newTestDevice("sensor-1"), clients.CreateOptions{})
+	assert.NotNil(t, err)
+
+	device, err := deviceClient.Get(context.TODO(), "sensor-1", clients.GetOptions{Namespace: "default"})
+	assert.Nil(t, err)
+	assert.Equal(t, created.Status.EdgeId, device.Status.EdgeId)
+	assert.Equal(t, "sensor-1", device.Labels[ObjectNameLabel])
+	assert.Equal(t, "device-mqtt", device.Spec.Service)
+
+	_, err = deviceClient.Get(context.TODO(), "sensor-2", clients.GetOptions{Namespace: "default"})
+	assert.True(t, clients.IsNotFoundErr(err))
+
+	_, err = deviceClient.Create(context.TODO(), newTestDevice("sensors/#"), clients.CreateOptions{})
+	assert.NotNil(t, err)
+}
+
+func Test_ListDevice(t *testing.T) {
+	b := newTestBroker(t)
+	// records published before the dock connects are replayed by the broker
+	publisher := newTestDeviceClient(t, b)
+	for _, name := range []string{"sensor-1", "sensor-2"} {
+		_, err := publisher.Create(context.TODO(), newTestDevice(name), clients.CreateOptions{})
+		assert.Nil(t, err)
+	}
+
+	deviceClient := newTestDeviceClient(t, b)
+	devices, err := deviceClient.List(context.TODO(), clients.ListOptions{Namespace: "default"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(devices))
+
+	assert.Nil(t, publisher.Delete(context.TODO(), "sensor-2", clients.DeleteOptions{}))
+	assert.Eventually(t, func() bool {
+		devices, err := deviceClient.List(context.TODO(), clients.ListOptions{Namespace: "default"})
+		return err == nil && len(devices) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_UpdateDevice(t *testing.T) {
+	b := newTestBroker(t)
+	deviceClient := newTestDeviceClient(t, b)
+
+	device := newTestDevice("sensor-1")
+	_, err := deviceClient.Update(context.TODO(), device, clients.UpdateOptions{})
+	assert.NotNil(t, err)
+
+	created, err := deviceClient.Create(context.TODO(), device, clients.CreateOptions{})
+	assert.Nil(t, err)
+
+	device.Spec.AdminState = iotv1alpha1.Locked
+	_, err = deviceClient.Update(context.TODO(), device, clients.UpdateOptions{})
+	assert.Nil(t, err)
+
+	updated, err := deviceClient.Get(context.TODO(), "sensor-1", clients.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, iotv1alpha1.Locked, updated.Spec.AdminState)
+	assert.Equal(t, created.Status.EdgeId, updated.Status.EdgeId)
+}
+
+func Test_DeleteDevice(t *testing.T) {
+	b := newTestBroker(t)
+	deviceClient := newTestDeviceClient(t, b)
+
+	_, err := deviceClient.Create(context.TODO(), newTestDevice("sensor-1"), clients.CreateOptions{})
+	assert.Nil(t, err)
+
+	err = deviceClient.Delete(context.TODO(), "sensor-1", clients.DeleteOptions{})
+	assert.Nil(t, err)
+
+	_, ok := b.retainedMessage("openyurt/iot/devices/sensor-1")
+	assert.False(t, ok)
+	_, err = deviceClient.Get(context.TODO(), "sensor-1", clients.GetOptions{})
+	assert.NotNil(t, err)
+}
+
+func Test_DevicePropertyState(t *testing.T) {
+	b := newTestBroker(t)
+	deviceClient := newTestDeviceClient(t, b)
+	device := newTestDevice("sensor-1")
+
+	_, err := deviceClient.GetPropertyState(context.TODO(), "temperature", device, clients.GetOptions{})
+	assert.True(t, clients.IsNotFoundErr(err))
+
+	// the device reports actual values and receives desired values through the broker
+	desired := make(chan string, 1)
+	dev, err := Dial(context.TODO(), b.addr(), ConnectOptions{ClientID: "sensor-1"}, func(topic string, payload []byte, retained bool) {
+		if topic == "openyurt/iot/devices/sensor-1/properties/switch/set" {
+			desired <- string(payload)
+		}
+	})
+	assert.Nil(t, err)
+	defer dev.Close()
+	assert.Nil(t, dev.Subscribe(context.TODO(), "openyurt/iot/devices/sensor-1/properties/+/set", 1))
+	assert.Nil(t, dev.Publish(context.TODO(), "openyurt/iot/devices/sensor-1/properties/temperature", []byte("25.5"), 1, true))
+	assert.Nil(t, dev.Publish(context.TODO(), "openyurt/iot/devices/sensor-1/properties/switch", []byte("off"), 1, true))
+
+	assert.Eventually(t, func() bool {
+		aps, err := deviceClient.GetPropertyState(context.TODO(), "temperature", device, clients.GetOptions{})
+		return err == nil && aps.ActualValue == "25.5"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		_, apsm, err := deviceClient.ListPropertiesState(context.TODO(), device, clients.ListOptions{})
+		return err == nil && len(apsm) == 2 && apsm["switch"].ActualValue == "off"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	err = deviceClient.UpdatePropertyState(context.TODO(), "switch", device, clients.UpdateOptions{})
+	assert.Nil(t, err)
+	select {
+	case value := <-desired:
+		assert.Equal(t, "on", value)
+	case <-time.After(5 * time.Second):
+		t.Errorf("desired value of property is not received by device")
+	}
+
+	err = deviceClient.UpdatePropertyState(context.TODO(), "unknown", device, clients.UpdateOptions{})
+	assert.NotNil(t, err)
+}
+
+func Test_DeviceClientReconnect(t *testing.T) {
+	b := newTestBroker(t)
+	deviceClient := newTestDeviceClient(t, b)
+
+	_, err := deviceClient.Create(context.TODO(), newTestDevice("sensor-1"), clients.CreateOptions{})
+	assert.Nil(t, err)
+
+	b.dropConnections()
+	assert.Eventually(t, func() bool {
+		_, err := deviceClient.Create(context.TODO(), newTestDevice("sensor-2"), clients.CreateOptions{})
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	devices, err := deviceClient.List(context.TODO(), clients.ListOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(devices))
+}
+
+func Test_ConvertDeviceSystemEvents(t *testing.T) {
+	deviceClient := &MQTTDeviceClient{}
+
+	event := clients.SystemEvent{
+		Type:    clients.SystemEventTypeDevice,
+		Action:  clients.SystemEventActionAdd,
+		Details: []byte(DeviceRecord),
+	}
+	device, err := deviceClient.Convert(context.TODO(), event, clients.GetOptions{Namespace: "default"})
+	assert.Nil(t, err)
+	assert.Equal(t, "random-float-device", device.Name)
+	assert.Equal(t, "Random-Float-Device", device.Labels[ObjectNameLabel])
+	assert.Equal(t, "2fff4f1a-7110-442f-b347-9f896338ba57", device.Status.EdgeId)
+	assert.True(t, device.Status.Synced)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+type MQTTDeviceProfileClient struct {
+	registry *registry
+}
+
+var _ clients.DeviceProfileInterface = &MQTTDeviceProfileClient{}
+
+// Create function publishes the device profile record to the registry
+func (mdpc *MQTTDeviceProfileClient) Create(ctx context.Context, deviceProfile *iotv1alpha1.DeviceProfile, options clients.CreateOptions) (*iotv1alpha1.DeviceProfile, error) {
+	name := getObjectName(deviceProfile)
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	klog.V(5).Infof("will add the DeviceProfile: %s", name)
+	if _, exist, err := mdpc.getRecord(ctx, name); err != nil {
+		return nil, err
+	} else if exist {
+		return nil, fmt.Errorf("device profile %s already exists", name)
+	}
+
+	record := toDeviceProfileRecord(deviceProfile, "")
+	if err := mdpc.publishRecord(ctx, record); err != nil {
+		return nil, err
+	}
+	created := deviceProfile.DeepCopy()
+	created.Status.EdgeId = record.Id
+	created.Status.Synced = true
+	return created, nil
+}
+
+// Delete function clears the retained device profile record in the registry
+func (mdpc *MQTTDeviceProfileClient) Delete(ctx context.Context, name string, options clients.DeleteOptions) error {
+	klog.V(5).Infof("will delete the DeviceProfile: %s", name)
+	if err := validateName(name); err != nil {
+		return err
+	}
+	return mdpc.registry.publish(ctx, mdpc.registry.topic(DeviceProfilesTopic, name), nil, true)
+}
+
+// Update function replaces the device profile record in the registry, the id of record is kept
+func (mdpc *MQTTDeviceProfileClient) Update(ctx context.Context, deviceProfile *iotv1alpha1.DeviceProfile, options clients.UpdateOptions) (*iotv1alpha1.DeviceProfile, error) {
+	if deviceProfile == nil {
+		return nil, nil
+	}
+	name := getObjectName(deviceProfile)
+	old, exist, err := mdpc.getRecord(ctx, name)
+	if err != nil {
+		return nil, err
+	} else if !exist {
+		return nil, fmt.Errorf("could not update device profile: %s, device profile not found", name)
+	}
+
+	record := toDeviceProfileRecord(deviceProfile, old.Id)
+	if err := mdpc.publishRecord(ctx, record); err != nil {
+		return nil, err
+	}
+	return deviceProfile, nil
+}
+
+// Get is used to query the device profile information corresponding to the name
+func (mdpc *MQTTDeviceProfileClient) Get(ctx context.Context, name string, options clients.GetOptions) (*iotv1alpha1.DeviceProfile, error) {
+	klog.V(5).Infof("will get DeviceProfile: %s", name)
+	record, exist, err := mdpc.getRecord(ctx, name)
+	if err != nil {
+		return nil, err
+	} else if !exist {
+		return nil, fmt.Errorf("DeviceProfile %s not found", name)
+	}
+	deviceProfile := toKubeDeviceProfile(record, options.Namespace)
+	return &deviceProfile, nil
+}
+
+// List is used to get all device profile objects in the registry
+func (mdpc *MQTTDeviceProfileClient) List(ctx context.Context, options clients.ListOptions) ([]iotv1alpha1.DeviceProfile, error) {
+	payloads, err := mdpc.registry.list(ctx, mdpc.registry.topic(DeviceProfilesTopic))
+	if err != nil {
+		return nil, err
+	}
+	var res []iotv1alpha1.DeviceProfile
+	for name, payload := range payloads {
+		var record DeviceProfile
+		if err := json.Unmarshal(payload, &record); err != nil {
+			klog.V(4).ErrorS(err, "could not decode the device profile record", "DeviceProfileName", name)
+			continue
+		}
+		res = append(res, toKubeDeviceProfile(record, options.Namespace))
+	}
+	return res, nil
+}
+
+// Convert is used to convert the device profile record in the systemEvent to the device profile object in the kubernetes cluster
+func (mdpc *MQTTDeviceProfileClient) Convert(ctx context.Context, systemEvent clients.SystemEvent, opts clients.GetOptions) (*iotv1alpha1.DeviceProfile, error) {
+	var record DeviceProfile
+	if err := json.Unmarshal(systemEvent.Details, &record); err != nil {
+		klog.V(3).ErrorS(err, "fail to decode device profile systemEvent details")
+		return nil, err
+	}
+	deviceProfile := toKubeDeviceProfile(record, opts.Namespace)
+	return &deviceProfile, nil
+}
+
+func (mdpc *MQTTDeviceProfileClient) getRecord(ctx context.Context, name string) (DeviceProfile, bool, error) {
+	var record DeviceProfile
+	payload, exist, err := mdpc.registry.get(ctx, mdpc.registry.topic(DeviceProfilesTopic, name))
+	if err != nil || !exist {
+		return record, exist, err
+	}
+	if err := json.Unmarshal(payload, &record); err != nil {
+		return record, false, err
+	}
+	return record, true, nil
+}
+
+func (mdpc *MQTTDeviceProfileClient) publishRecord(ctx context.Context, record DeviceProfile) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return mdpc.registry.publish(ctx, mdpc.registry.topic(DeviceProfilesTopic, record.Name), payload, true)
+}
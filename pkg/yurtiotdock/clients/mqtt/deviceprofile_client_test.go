@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+const DeviceProfileRecord = `{"id":"cf624c1f-c93a-48c0-b327-b00c7dc171f1","name":"Random-Boolean-Device","manufacturer":"IOTech","model":"Device-Virtual-01","labels":["device-virtual-example"],"deviceResources":[{"name":"Bool","description":"used to decide whether to re-generate a random value","isHidden":false,"properties":{"valueType":"Bool","readWrite":"RW","defaultValue":"true"}}],"deviceCommands":[{"name":"WriteBoolValue","isHidden":false,"readWrite":"W","resourceOperations":[{"deviceResource":"Bool","defaultValue":"false"}]}]}`
+
+func newTestDeviceProfile(name string) *iotv1alpha1.DeviceProfile {
+	return &iotv1alpha1.DeviceProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: iotv1alpha1.DeviceProfileSpec{
+			Manufacturer: "OpenYurt",
+			Model:        "sensor",
+			DeviceResources: []iotv1alpha1.DeviceResource{
+				{
+					Name: "temperature",
+					Properties: iotv1alpha1.ResourceProperties{
+						ValueType: "Float32",
+						ReadWrite: "R",
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_DeviceProfileClient(t *testing.T) {
+	b := newTestBroker(t)
+	cli, err := NewMQTTDock(b.addr(), "registry", "").CreateDeviceProfileClient()
+	assert.Nil(t, err)
+
+	profile := newTestDeviceProfile("sensor")
+	created, err := cli.Create(context.TODO(), profile, clients.CreateOptions{})
+	assert.Nil(t, err)
+	assert.True(t, created.Status.Synced)
+	_, ok := b.retainedMessage("registry/deviceprofiles/sensor")
+	assert.True(t, ok)
+
+	_, err = cli.Create(context.TODO(), profile, clients.CreateOptions{})
+	assert.NotNil(t, err)
+
+	profile.Spec.Model = "sensor-v2"
+	_, err = cli.Update(context.TODO(), profile, clients.UpdateOptions{})
+	assert.Nil(t, err)
+
+	got, err := cli.Get(context.TODO(), "sensor", clients.GetOptions{Namespace: "default"})
+	assert.Nil(t, err)
+	assert.Equal(t, "sensor-v2", got.Spec.Model)
+	assert.Equal(t, created.Status.EdgeId, got.Status.EdgeId)
+	assert.Equal(t, profile.Spec.DeviceResources, got.Spec.DeviceResources)
+
+	profiles, err := cli.List(context.TODO(), clients.ListOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(profiles))
+
+	assert.Nil(t, cli.Delete(context.TODO(), "sensor", clients.DeleteOptions{}))
+	_, err = cli.Get(context.TODO(), "sensor", clients.GetOptions{})
+	assert.True(t, clients.IsNotFoundErr(err))
+	_, err = cli.Update(context.TODO(), profile, clients.UpdateOptions{})
+	assert.NotNil(t, err)
+}
+
+func Test_ConvertDeviceProfileSystemEvents(t *testing.T) {
+	profileClient := &MQTTDeviceProfileClient{}
+
+	event := clients.SystemEvent{
+		Type:    clients.SystemEventTypeDeviceProfile,
+		Action:  clients.SystemEventActionAdd,
+		Details: []byte(DeviceProfileRecord),
+	}
+	profile, err := profileClient.Convert(context.TODO(), event, clients.GetOptions{Namespace: "default"})
+	assert.Nil(t, err)
+	assert.Equal(t, "random-boolean-device", profile.Name)
+	assert.Equal(t, "Random-Boolean-Device", profile.Labels[ObjectNameLabel])
+	assert.Equal(t, 1, len(profile.Spec.DeviceResources))
+	assert.Equal(t, 1, len(profile.Spec.DeviceCommands))
+
+	_, err = profileClient.Convert(context.TODO(), clients.SystemEvent{Details: []byte("{")}, clients.GetOptions{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+const deviceReadingBufferSize = 100
+
+// MQTTDeviceReadingClient watches the property values published by devices
+type MQTTDeviceReadingClient struct {
+	registry *registry
+}
+
+var _ clients.DeviceReadingInterface = &MQTTDeviceReadingClient{}
+
+// Watch generates the device readings from the property values published to the broker
+func (mrc *MQTTDeviceReadingClient) Watch(ctx context.Context) (<-chan clients.DeviceReading, error) {
+	stream := clients.NewReadingStream(deviceReadingBufferSize)
+	if err := mrc.registry.watchReadings(ctx, stream); err != nil {
+		return nil, err
+	}
+	return stream.ResultChan(), nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WatchDeviceReadings(t *testing.T) {
+	b := newTestBroker(t)
+	dev, err := Dial(context.TODO(), b.addr(), ConnectOptions{ClientID: "sensor-1"}, func(string, []byte, bool) {})
+	assert.Nil(t, err)
+	defer dev.Close()
+	// the retained value published before watching is not a new reading
+	assert.Nil(t, dev.Publish(context.TODO(), "openyurt/iot/devices/sensor-1/properties/temperature", []byte("25.5"), 1, true))
+
+	readingClient, err := NewMQTTDock(b.addr(), "", "").CreateDeviceReadingClient()
+	assert.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	readings, err := readingClient.Watch(ctx)
+	assert.Nil(t, err)
+
+	assert.Nil(t, dev.Publish(context.TODO(), "openyurt/iot/devices/sensor-1/properties/temperature/set", []byte("20"), 1, false))
+	assert.Nil(t, dev.Publish(context.TODO(), "openyurt/iot/devices/sensor-1/properties/temperature", []byte("26.0"), 1, true))
+	select {
+	case r := <-readings:
+		assert.Equal(t, "sensor-1", r.DeviceName)
+		assert.Equal(t, "temperature", r.PropertyName)
+		assert.Equal(t, "26.0", r.Value)
+		assert.NotZero(t, r.Timestamp)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for the device reading")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-readings:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the channel of readings is not closed after ctx is done")
+	}
+}
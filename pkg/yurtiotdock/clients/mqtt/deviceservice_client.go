@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+type MQTTDeviceServiceClient struct {
+	registry *registry
+}
+
+var _ clients.DeviceServiceInterface = &MQTTDeviceServiceClient{}
+
+// Create function publishes the device service record to the registry
+func (mdsc *MQTTDeviceServiceClient) Create(ctx context.Context, deviceService *iotv1alpha1.DeviceService, options clients.CreateOptions) (*iotv1alpha1.DeviceService, error) {
+	name := getObjectName(deviceService)
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	klog.V(5).Infof("will add the DeviceService: %s", name)
+	if _, exist, err := mdsc.getRecord(ctx, name); err != nil {
+		return nil, err
+	} else if exist {
+		return nil, fmt.Errorf("device service %s already exists", name)
+	}
+
+	record := toDeviceServiceRecord(deviceService, "")
+	if err := mdsc.publishRecord(ctx, record); err != nil {
+		return nil, err
+	}
+	created := deviceService.DeepCopy()
+	created.Status.EdgeId = record.Id
+	created.Status.Synced = true
+	return created, nil
+}
+
+// Delete function clears the retained device service record in the registry
+func (mdsc *MQTTDeviceServiceClient) Delete(ctx context.Context, name string, options clients.DeleteOptions) error {
+	klog.V(5).Infof("will delete the DeviceService: %s", name)
+	if err := validateName(name); err != nil {
+		return err
+	}
+	return mdsc.registry.publish(ctx, mdsc.registry.topic(DeviceServicesTopic, name), nil, true)
+}
+
+// Update function replaces the device service record in the registry, the id of record is kept
+func (mdsc *MQTTDeviceServiceClient) Update(ctx context.Context, deviceService *iotv1alpha1.DeviceService, options clients.UpdateOptions) (*iotv1alpha1.DeviceService, error) {
+	if deviceService == nil {
+		return nil, nil
+	}
+	name := getObjectName(deviceService)
+	old, exist, err := mdsc.getRecord(ctx, name)
+	if err != nil {
+		return nil, err
+	} else if !exist {
+		return nil, fmt.Errorf("could not update device service: %s, device service not found", name)
+	}
+
+	record := toDeviceServiceRecord(deviceService, old.Id)
+	record.LastConnected, record.LastReported = old.LastConnected, old.LastReported
+	if err := mdsc.publishRecord(ctx, record); err != nil {
+		return nil, err
+	}
+	return deviceService, nil
+}
+
+// Get is used to query the device service information corresponding to the name
+func (mdsc *MQTTDeviceServiceClient) Get(ctx context.Context, name string, options clients.GetOptions) (*iotv1alpha1.DeviceService, error) {
+	klog.V(5).Infof("will get DeviceService: %s", name)
+	record, exist, err := mdsc.getRecord(ctx, name)
+	if err != nil {
+		return nil, err
+	} else if !exist {
+		return nil, fmt.Errorf("DeviceService %s not found", name)
+	}
+	deviceService := toKubeDeviceService(record, options.Namespace)
+	return &deviceService, nil
+}
+
+// List is used to get all device service objects in the registry
+func (mdsc *MQTTDeviceServiceClient) List(ctx context.Context, options clients.ListOptions) ([]iotv1alpha1.DeviceService, error) {
+	payloads, err := mdsc.registry.list(ctx, mdsc.registry.topic(DeviceServicesTopic))
+	if err != nil {
+		return nil, err
+	}
+	var res []iotv1alpha1.DeviceService
+	for name, payload := range payloads {
+		var record DeviceService
+		if err := json.Unmarshal(payload, &record); err != nil {
+			klog.V(4).ErrorS(err, "could not decode the device service record", "DeviceServiceName", name)
+			continue
+		}
+		res = append(res, toKubeDeviceService(record, options.Namespace))
+	}
+	return res, nil
+}
+
+// Convert is used to convert the device service record in the systemEvent to the device service object in the kubernetes cluster
+func (mdsc *MQTTDeviceServiceClient) Convert(ctx context.Context, systemEvent clients.SystemEvent, opts clients.GetOptions) (*iotv1alpha1.DeviceService, error) {
+	var record DeviceService
+	if err := json.Unmarshal(systemEvent.Details, &record); err != nil {
+		klog.V(3).ErrorS(err, "fail to decode device service systemEvent details")
+		return nil, err
+	}
+	deviceService := toKubeDeviceService(record, opts.Namespace)
+	return &deviceService, nil
+}
+
+func (mdsc *MQTTDeviceServiceClient) getRecord(ctx context.Context, name string) (DeviceService, bool, error) {
+	var record DeviceService
+	payload, exist, err := mdsc.registry.get(ctx, mdsc.registry.topic(DeviceServicesTopic, name))
+	if err != nil || !exist {
+		return record, exist, err
+	}
+	if err := json.Unmarshal(payload, &record); err != nil {
+		return record, false, err
+	}
+	return record, true, nil
+}
+
+func (mdsc *MQTTDeviceServiceClient) publishRecord(ctx context.Context, record DeviceService) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return mdsc.registry.publish(ctx, mdsc.registry.topic(DeviceServicesTopic, record.Name), payload, true)
+}
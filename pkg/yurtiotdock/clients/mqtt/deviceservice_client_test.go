@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+const DeviceServiceRecord = `{"id":"74516e96-973d-4cad-bad1-afd4b3a8ea46","name":"device-virtual","baseAddress":"http://edgex-device-virtual:59900","adminState":"UNLOCKED","lastConnected":1661850999190}`
+
+func Test_DeviceServiceClient(t *testing.T) {
+	b := newTestBroker(t)
+	cli, err := NewMQTTDock(b.addr(), "", "").CreateDeviceServiceClient()
+	assert.Nil(t, err)
+
+	service := &iotv1alpha1.DeviceService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "device-mqtt",
+			Namespace: "default",
+			Labels: map[string]string{
+				ObjectNameLabel: "Device_MQTT",
+			},
+		},
+		Spec: iotv1alpha1.DeviceServiceSpec{
+			BaseAddress: "http://device-mqtt:59982",
+			AdminState:  iotv1alpha1.UnLocked,
+		},
+	}
+	created, err := cli.Create(context.TODO(), service, clients.CreateOptions{})
+	assert.Nil(t, err)
+	assert.True(t, created.Status.Synced)
+	// the name recorded in label is used as the name in registry
+	_, ok := b.retainedMessage("openyurt/iot/deviceservices/Device_MQTT")
+	assert.True(t, ok)
+
+	service.Spec.AdminState = iotv1alpha1.Locked
+	_, err = cli.Update(context.TODO(), service, clients.UpdateOptions{})
+	assert.Nil(t, err)
+
+	services, err := cli.List(context.TODO(), clients.ListOptions{Namespace: "default"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(services))
+	assert.Equal(t, "device-mqtt", services[0].Name)
+	assert.Equal(t, iotv1alpha1.Locked, services[0].Spec.AdminState)
+	assert.Equal(t, created.Status.EdgeId, services[0].Status.EdgeId)
+
+	assert.Nil(t, cli.Delete(context.TODO(), "Device_MQTT", clients.DeleteOptions{}))
+	services, err = cli.List(context.TODO(), clients.ListOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(services))
+}
+
+func Test_ConvertServiceSystemEvents(t *testing.T) {
+	serviceClient := &MQTTDeviceServiceClient{}
+
+	event := clients.SystemEvent{
+		Type:    clients.SystemEventTypeDeviceService,
+		Action:  clients.SystemEventActionUpdate,
+		Details: []byte(DeviceServiceRecord),
+	}
+	service, err := serviceClient.Convert(context.TODO(), event, clients.GetOptions{Namespace: "default"})
+	assert.Nil(t, err)
+	assert.Equal(t, "device-virtual", service.Name)
+	assert.Equal(t, "http://edgex-device-virtual:59900", service.Spec.BaseAddress)
+	assert.Equal(t, int64(1661850999190), service.Status.LastConnected)
+}
@@ -0,0 +1,401 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/klog/v2"
+
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+const (
+	// ObjectNameLabel records the name of the object in the registry, it is shared with the EdgeX backend
+	// so that controllers and syncers can resolve objects in the same way across platforms
+	ObjectNameLabel = "yurt-iot-dock/edgex-object.name"
+	// DefaultTopicPrefix is the root topic of the device registry
+	DefaultTopicPrefix = "openyurt/iot"
+
+	DevicesTopic        = "devices"
+	DeviceProfilesTopic = "deviceprofiles"
+	DeviceServicesTopic = "deviceservices"
+	PropertiesTopic     = "properties"
+	SetTopic            = "set"
+	syncTopic           = "_sync"
+
+	requestTimeout = 10 * time.Second
+)
+
+// MQTTDock creates clients of the MQTT based device registry. Devices, device profiles and device services
+// are stored as retained JSON messages on topics <prefix>/devices/<name>, <prefix>/deviceprofiles/<name>
+// and <prefix>/deviceservices/<name>. The actual values of device properties are reported by devices on
+// <prefix>/devices/<name>/properties/<property>, and the desired values are published to
+// <prefix>/devices/<name>/properties/<property>/set.
+type MQTTDock struct {
+	BrokerAddr  string
+	TopicPrefix string
+	ClientID    string
+
+	registryOnce sync.Once
+	registry     *registry
+}
+
+var _ clients.IoTDock = &MQTTDock{}
+
+func NewMQTTDock(brokerAddr, topicPrefix, clientID string) *MQTTDock {
+	if len(topicPrefix) == 0 {
+		topicPrefix = DefaultTopicPrefix
+	}
+	if len(clientID) == 0 {
+		clientID = "yurt-iot-dock-" + uuid.New().String()[:8]
+	}
+	return &MQTTDock{
+		BrokerAddr:  brokerAddr,
+		TopicPrefix: strings.TrimSuffix(topicPrefix, "/"),
+		ClientID:    clientID,
+	}
+}
+
+func (md *MQTTDock) CreateDeviceClient() (clients.DeviceInterface, error) {
+	return &MQTTDeviceClient{registry: md.getRegistry()}, nil
+}
+
+func (md *MQTTDock) CreateDeviceProfileClient() (clients.DeviceProfileInterface, error) {
+	return &MQTTDeviceProfileClient{registry: md.getRegistry()}, nil
+}
+
+func (md *MQTTDock) CreateDeviceServiceClient() (clients.DeviceServiceInterface, error) {
+	return &MQTTDeviceServiceClient{registry: md.getRegistry()}, nil
+}
+
+func (md *MQTTDock) CreateSystemEventClient() (clients.SystemEventInterface, error) {
+	return &MQTTSystemEventClient{registry: md.getRegistry()}, nil
+}
+
+func (md *MQTTDock) CreateDeviceReadingClient() (clients.DeviceReadingInterface, error) {
+	return &MQTTDeviceReadingClient{registry: md.getRegistry()}, nil
+}
+
+// getRegistry returns the registry shared by all clients created by the dock
+func (md *MQTTDock) getRegistry() *registry {
+	md.registryOnce.Do(func() {
+		md.registry = newRegistry(md.BrokerAddr, md.TopicPrefix, md.ClientID)
+	})
+	return md.registry
+}
+
+// registry keeps a local view of the retained messages under the topic prefix. The connection to the broker
+// is established lazily and re-established by the next request after it is lost, the retained messages
+// are replayed by the broker when the subscription is renewed.
+type registry struct {
+	brokerAddr string
+	prefix     string
+	clientID   string
+
+	connLock sync.Mutex
+	client   *Client
+
+	cacheLock sync.RWMutex
+	cache     map[string][]byte
+
+	// watchers receive the system events of the registry, keyed by the event stream and valued by the type of events
+	watchersLock sync.RWMutex
+	watchers     map[*clients.EventStream]string
+	// readingWatchers receive the property values reported by devices
+	readingWatchers map[*clients.ReadingStream]struct{}
+}
+
+func newRegistry(brokerAddr, prefix, clientID string) *registry {
+	return &registry{
+		brokerAddr: brokerAddr,
+		prefix:     prefix,
+		clientID:   clientID,
+		cache:      make(map[string][]byte),
+		watchers:   make(map[*clients.EventStream]string),
+
+		readingWatchers: make(map[*clients.ReadingStream]struct{}),
+	}
+}
+
+func (r *registry) connect(ctx context.Context) (*Client, error) {
+	r.connLock.Lock()
+	defer r.connLock.Unlock()
+	if r.client != nil {
+		select {
+		case <-r.client.Done():
+			klog.V(3).ErrorS(r.client.Err(), "connection to mqtt broker is lost, reconnecting", "broker", r.brokerAddr)
+			r.client = nil
+		default:
+			return r.client, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	// synced is closed when the marker message published after subscribing is received,
+	// which means the retained messages before it have been replayed by the broker
+	synced := make(chan struct{})
+	marker := r.topic(syncTopic, r.clientID)
+	handler := func(topic string, payload []byte, retained bool) {
+		if topic != marker {
+			r.handleMessage(topic, payload, retained)
+			return
+		}
+		select {
+		case <-synced:
+		default:
+			close(synced)
+		}
+	}
+	c, err := Dial(ctx, r.brokerAddr, ConnectOptions{ClientID: r.clientID}, handler)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to mqtt broker %s, %w", r.brokerAddr, err)
+	}
+	if err := c.Subscribe(ctx, r.prefix+"/#", 1); err != nil {
+		c.Close()
+		return nil, err
+	}
+	if err := c.Publish(ctx, marker, []byte(r.clientID), 0, false); err != nil {
+		c.Close()
+		return nil, err
+	}
+	select {
+	case <-synced:
+	case <-c.Done():
+		return nil, c.Err()
+	case <-ctx.Done():
+		c.Close()
+		return nil, fmt.Errorf("could not replay retained messages from mqtt broker %s, %w", r.brokerAddr, ctx.Err())
+	}
+	r.client = c
+	return c, nil
+}
+
+func (r *registry) handleMessage(topic string, payload []byte, retained bool) {
+	r.cacheLock.Lock()
+	old, exist := r.cache[topic]
+	if len(payload) == 0 {
+		delete(r.cache, topic)
+	} else {
+		r.cache[topic] = payload
+	}
+	event, changed := r.toSystemEvent(topic, old, exist, payload)
+	r.cacheLock.Unlock()
+
+	if changed {
+		r.notify(event)
+	}
+	// the retained values are replayed by the broker, only the values published
+	// after subscribing are new readings
+	if !retained && len(payload) != 0 {
+		r.notifyReading(topic, payload)
+	}
+}
+
+// toSystemEvent generates the system event for the change of message on topic, the caller must hold the cacheLock.
+// Changes of the property values are notified as the update of device.
+func (r *registry) toSystemEvent(topic string, old []byte, exist bool, payload []byte) (clients.SystemEvent, bool) {
+	event := clients.SystemEvent{
+		Source:    r.brokerAddr,
+		Timestamp: time.Now().UnixNano(),
+	}
+	levels := strings.Split(strings.TrimPrefix(topic, r.prefix+"/"), "/")
+	switch {
+	case len(levels) == 2:
+		switch levels[0] {
+		case DevicesTopic:
+			event.Type = clients.SystemEventTypeDevice
+		case DeviceProfilesTopic:
+			event.Type = clients.SystemEventTypeDeviceProfile
+		case DeviceServicesTopic:
+			event.Type = clients.SystemEventTypeDeviceService
+		default:
+			return event, false
+		}
+		switch {
+		case len(payload) == 0 && !exist:
+			return event, false
+		case len(payload) == 0:
+			event.Action, event.Details = clients.SystemEventActionDelete, old
+		case !exist:
+			event.Action, event.Details = clients.SystemEventActionAdd, payload
+		case bytes.Equal(old, payload):
+			return event, false
+		default:
+			event.Action, event.Details = clients.SystemEventActionUpdate, payload
+		}
+		return event, true
+	case len(levels) == 4 && levels[0] == DevicesTopic && levels[2] == PropertiesTopic:
+		record, ok := r.cache[r.topic(DevicesTopic, levels[1])]
+		if !ok || bytes.Equal(old, payload) {
+			return event, false
+		}
+		event.Type, event.Action, event.Details = clients.SystemEventTypeDevice, clients.SystemEventActionUpdate, record
+		return event, true
+	}
+	return event, false
+}
+
+// notify sends the system event to the watchers of its type
+func (r *registry) notify(event clients.SystemEvent) {
+	r.watchersLock.RLock()
+	defer r.watchersLock.RUnlock()
+	for stream, eventType := range r.watchers {
+		if len(eventType) == 0 || eventType == event.Type {
+			stream.Send(event)
+		}
+	}
+}
+
+// notifyReading sends the reading to the watchers if the topic is the value of device property
+func (r *registry) notifyReading(topic string, payload []byte) {
+	levels := strings.Split(strings.TrimPrefix(topic, r.prefix+"/"), "/")
+	if len(levels) != 4 || levels[0] != DevicesTopic || levels[2] != PropertiesTopic {
+		return
+	}
+	reading := clients.DeviceReading{
+		DeviceName:   levels[1],
+		PropertyName: levels[3],
+		Value:        string(payload),
+		Timestamp:    time.Now().UnixNano(),
+	}
+	r.watchersLock.RLock()
+	defer r.watchersLock.RUnlock()
+	for stream := range r.readingWatchers {
+		stream.Send(reading)
+	}
+}
+
+// watchReadings registers the stream to receive device readings until ctx is done or the connection is lost
+func (r *registry) watchReadings(ctx context.Context, stream *clients.ReadingStream) error {
+	c, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+	r.watchersLock.Lock()
+	r.readingWatchers[stream] = struct{}{}
+	r.watchersLock.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-c.Done():
+		case <-stream.Done():
+		}
+		stream.Close()
+		r.watchersLock.Lock()
+		delete(r.readingWatchers, stream)
+		r.watchersLock.Unlock()
+	}()
+	return nil
+}
+
+// watch registers the stream to receive system events until ctx is done or the connection is lost
+func (r *registry) watch(ctx context.Context, eventType string, stream *clients.EventStream) error {
+	c, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+	r.watchersLock.Lock()
+	r.watchers[stream] = eventType
+	r.watchersLock.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-c.Done():
+		case <-stream.Done():
+		}
+		// close the stream before unregistering it, so that the pending notification returns
+		stream.Close()
+		r.watchersLock.Lock()
+		delete(r.watchers, stream)
+		r.watchersLock.Unlock()
+	}()
+	return nil
+}
+
+// publish sends the payload with qos 1 and updates the local view when the broker acknowledges it
+func (r *registry) publish(ctx context.Context, topic string, payload []byte, retain bool) error {
+	c, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	if err := c.Publish(ctx, topic, payload, 1, retain); err != nil {
+		return err
+	}
+	if retain {
+		r.handleMessage(topic, payload, retain)
+	}
+	return nil
+}
+
+// get returns the retained message of topic
+func (r *registry) get(ctx context.Context, topic string) ([]byte, bool, error) {
+	if _, err := r.connect(ctx); err != nil {
+		return nil, false, err
+	}
+	r.cacheLock.RLock()
+	defer r.cacheLock.RUnlock()
+	payload, ok := r.cache[topic]
+	return payload, ok, nil
+}
+
+// list returns the messages whose topics are direct children of parent, keyed by the last topic level
+func (r *registry) list(ctx context.Context, parent string) (map[string][]byte, error) {
+	if _, err := r.connect(ctx); err != nil {
+		return nil, err
+	}
+	r.cacheLock.RLock()
+	defer r.cacheLock.RUnlock()
+	children := make(map[string][]byte)
+	for topic, payload := range r.cache {
+		if !strings.HasPrefix(topic, parent+"/") {
+			continue
+		}
+		name := strings.TrimPrefix(topic, parent+"/")
+		if len(name) == 0 || strings.Contains(name, "/") {
+			continue
+		}
+		children[name] = payload
+	}
+	return children, nil
+}
+
+func (r *registry) topic(levels ...string) string {
+	return strings.Join(append([]string{r.prefix}, levels...), "/")
+}
+
+// validateName checks that the name can be used as a topic level
+func validateName(name string) error {
+	if len(name) == 0 {
+		return fmt.Errorf("name of object can not be empty")
+	}
+	if strings.ContainsAny(name, "/+#") {
+		return fmt.Errorf("name %s of object can not contain '/', '+' or '#'", name)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+const systemEventBufferSize = 100
+
+// MQTTSystemEventClient watches the changes of records in the registry
+type MQTTSystemEventClient struct {
+	registry *registry
+}
+
+var _ clients.SystemEventInterface = &MQTTSystemEventClient{}
+
+// Watch generates the system events from the changes of retained records and property values,
+// the details of events are the records of objects
+func (msc *MQTTSystemEventClient) Watch(ctx context.Context, options clients.WatchOptions) (<-chan clients.SystemEvent, error) {
+	stream := clients.NewEventStream(systemEventBufferSize)
+	if err := msc.registry.watch(ctx, options.Type, stream); err != nil {
+		return nil, err
+	}
+	return stream.ResultChan(), nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+func nextEvent(t *testing.T, events <-chan clients.SystemEvent) clients.SystemEvent {
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatalf("the channel of events is closed unexpectedly")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for the system event")
+	}
+	return clients.SystemEvent{}
+}
+
+func Test_WatchDevices(t *testing.T) {
+	b := newTestBroker(t)
+	dock := NewMQTTDock(b.addr(), "", "")
+	deviceClient, _ := dock.CreateDeviceClient()
+	profileClient, _ := dock.CreateDeviceProfileClient()
+	eventClient, err := dock.CreateSystemEventClient()
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := eventClient.Watch(ctx, clients.WatchOptions{Type: clients.SystemEventTypeDevice})
+	assert.Nil(t, err)
+
+	// events of other types are filtered out
+	_, err = profileClient.Create(context.TODO(), newTestDeviceProfile("sensor"), clients.CreateOptions{})
+	assert.Nil(t, err)
+
+	created, err := deviceClient.Create(context.TODO(), newTestDevice("sensor-1"), clients.CreateOptions{})
+	assert.Nil(t, err)
+	event := nextEvent(t, events)
+	assert.Equal(t, clients.SystemEventTypeDevice, event.Type)
+	assert.Equal(t, clients.SystemEventActionAdd, event.Action)
+	device, err := deviceClient.Convert(context.TODO(), event, clients.GetOptions{Namespace: "default"})
+	assert.Nil(t, err)
+	assert.Equal(t, created.Status.EdgeId, device.Status.EdgeId)
+
+	created.Spec.Description = "updated"
+	_, err = deviceClient.Update(context.TODO(), created, clients.UpdateOptions{})
+	assert.Nil(t, err)
+	event = nextEvent(t, events)
+	assert.Equal(t, clients.SystemEventActionUpdate, event.Action)
+
+	// the reported property values are notified as the update of device
+	dev, err := Dial(context.TODO(), b.addr(), ConnectOptions{ClientID: "sensor-1"}, func(string, []byte, bool) {})
+	assert.Nil(t, err)
+	defer dev.Close()
+	assert.Nil(t, dev.Publish(context.TODO(), "openyurt/iot/devices/sensor-1/properties/switch", []byte("off"), 1, true))
+	event = nextEvent(t, events)
+	assert.Equal(t, clients.SystemEventActionUpdate, event.Action)
+
+	assert.Nil(t, deviceClient.Delete(context.TODO(), "sensor-1", clients.DeleteOptions{}))
+	event = nextEvent(t, events)
+	assert.Equal(t, clients.SystemEventActionDelete, event.Action)
+	device, err = deviceClient.Convert(context.TODO(), event, clients.GetOptions{Namespace: "default"})
+	assert.Nil(t, err)
+	assert.Equal(t, "sensor-1", device.Name)
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the channel of events is not closed after ctx is done")
+	}
+}
+
+func Test_WatchClosedWhenConnectionLost(t *testing.T) {
+	b := newTestBroker(t)
+	eventClient, _ := NewMQTTDock(b.addr(), "", "").CreateSystemEventClient()
+
+	events, err := eventClient.Watch(context.TODO(), clients.WatchOptions{})
+	assert.Nil(t, err)
+
+	b.dropConnections()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the channel of events is not closed after the connection is lost")
+	}
+}
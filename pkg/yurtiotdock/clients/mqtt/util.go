@@ -0,0 +1,206 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mqtt
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+)
+
+// Device is the record of device stored in the registry
+type Device struct {
+	Id             string                                    `json:"id"`
+	Name           string                                    `json:"name"`
+	Description    string                                    `json:"description,omitempty"`
+	AdminState     string                                    `json:"adminState,omitempty"`
+	OperatingState string                                    `json:"operatingState,omitempty"`
+	Protocols      map[string]iotv1alpha1.ProtocolProperties `json:"protocols,omitempty"`
+	Labels         []string                                  `json:"labels,omitempty"`
+	Location       string                                    `json:"location,omitempty"`
+	ServiceName    string                                    `json:"serviceName"`
+	ProfileName    string                                    `json:"profileName"`
+	Notify         bool                                      `json:"notify,omitempty"`
+	LastConnected  int64                                     `json:"lastConnected,omitempty"`
+	LastReported   int64                                     `json:"lastReported,omitempty"`
+}
+
+// DeviceProfile is the record of device profile stored in the registry
+type DeviceProfile struct {
+	Id              string                             `json:"id"`
+	Name            string                             `json:"name"`
+	Description     string                             `json:"description,omitempty"`
+	Manufacturer    string                             `json:"manufacturer,omitempty"`
+	Model           string                             `json:"model,omitempty"`
+	Labels          []string                           `json:"labels,omitempty"`
+	DeviceResources []iotv1alpha1.DeviceResource       `json:"deviceResources,omitempty"`
+	DeviceCommands  []iotv1alpha1.DeviceProfileCommand `json:"deviceCommands,omitempty"`
+}
+
+// DeviceService is the record of device service stored in the registry
+type DeviceService struct {
+	Id            string   `json:"id"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description,omitempty"`
+	BaseAddress   string   `json:"baseAddress"`
+	Labels        []string `json:"labels,omitempty"`
+	AdminState    string   `json:"adminState,omitempty"`
+	LastConnected int64    `json:"lastConnected,omitempty"`
+	LastReported  int64    `json:"lastReported,omitempty"`
+}
+
+func getObjectName(obj metav1.Object) string {
+	if name, ok := obj.GetLabels()[ObjectNameLabel]; ok {
+		return name
+	}
+	return obj.GetName()
+}
+
+func toKubeName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
+
+// newId keeps the id of the existing record, or generates a new one
+func newId(id string) string {
+	if len(id) != 0 {
+		return id
+	}
+	return uuid.New().String()
+}
+
+func toDeviceRecord(d *iotv1alpha1.Device, id string) Device {
+	return Device{
+		Id:             newId(id),
+		Name:           getObjectName(d),
+		Description:    d.Spec.Description,
+		AdminState:     string(d.Spec.AdminState),
+		OperatingState: string(d.Spec.OperatingState),
+		Protocols:      d.Spec.Protocols,
+		Labels:         d.Spec.Labels,
+		Location:       d.Spec.Location,
+		ServiceName:    d.Spec.Service,
+		ProfileName:    d.Spec.Profile,
+		Notify:         d.Spec.Notify,
+	}
+}
+
+func toKubeDevice(d Device, namespace string) iotv1alpha1.Device {
+	return iotv1alpha1.Device{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      toKubeName(d.Name),
+			Namespace: namespace,
+			Labels: map[string]string{
+				ObjectNameLabel: d.Name,
+			},
+		},
+		Spec: iotv1alpha1.DeviceSpec{
+			Description:    d.Description,
+			AdminState:     iotv1alpha1.AdminState(d.AdminState),
+			OperatingState: iotv1alpha1.OperatingState(d.OperatingState),
+			Protocols:      d.Protocols,
+			Labels:         d.Labels,
+			Location:       d.Location,
+			Service:        d.ServiceName,
+			Profile:        d.ProfileName,
+			Notify:         d.Notify,
+		},
+		Status: iotv1alpha1.DeviceStatus{
+			LastConnected:  d.LastConnected,
+			LastReported:   d.LastReported,
+			Synced:         true,
+			EdgeId:         d.Id,
+			AdminState:     iotv1alpha1.AdminState(d.AdminState),
+			OperatingState: iotv1alpha1.OperatingState(d.OperatingState),
+		},
+	}
+}
+
+func toDeviceProfileRecord(dp *iotv1alpha1.DeviceProfile, id string) DeviceProfile {
+	return DeviceProfile{
+		Id:              newId(id),
+		Name:            getObjectName(dp),
+		Description:     dp.Spec.Description,
+		Manufacturer:    dp.Spec.Manufacturer,
+		Model:           dp.Spec.Model,
+		Labels:          dp.Spec.Labels,
+		DeviceResources: dp.Spec.DeviceResources,
+		DeviceCommands:  dp.Spec.DeviceCommands,
+	}
+}
+
+func toKubeDeviceProfile(dp DeviceProfile, namespace string) iotv1alpha1.DeviceProfile {
+	return iotv1alpha1.DeviceProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      toKubeName(dp.Name),
+			Namespace: namespace,
+			Labels: map[string]string{
+				ObjectNameLabel: dp.Name,
+			},
+		},
+		Spec: iotv1alpha1.DeviceProfileSpec{
+			Description:     dp.Description,
+			Manufacturer:    dp.Manufacturer,
+			Model:           dp.Model,
+			Labels:          dp.Labels,
+			DeviceResources: dp.DeviceResources,
+			DeviceCommands:  dp.DeviceCommands,
+		},
+		Status: iotv1alpha1.DeviceProfileStatus{
+			EdgeId: dp.Id,
+			Synced: true,
+		},
+	}
+}
+
+func toDeviceServiceRecord(ds *iotv1alpha1.DeviceService, id string) DeviceService {
+	return DeviceService{
+		Id:          newId(id),
+		Name:        getObjectName(ds),
+		Description: ds.Spec.Description,
+		BaseAddress: ds.Spec.BaseAddress,
+		Labels:      ds.Spec.Labels,
+		AdminState:  string(ds.Spec.AdminState),
+	}
+}
+
+func toKubeDeviceService(ds DeviceService, namespace string) iotv1alpha1.DeviceService {
+	return iotv1alpha1.DeviceService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      toKubeName(ds.Name),
+			Namespace: namespace,
+			Labels: map[string]string{
+				ObjectNameLabel: ds.Name,
+			},
+		},
+		Spec: iotv1alpha1.DeviceServiceSpec{
+			Description: ds.Description,
+			Labels:      ds.Labels,
+			AdminState:  iotv1alpha1.AdminState(ds.AdminState),
+			BaseAddress: ds.BaseAddress,
+		},
+		Status: iotv1alpha1.DeviceServiceStatus{
+			Synced:        true,
+			EdgeId:        ds.Id,
+			LastConnected: ds.LastConnected,
+			LastReported:  ds.LastReported,
+			AdminState:    iotv1alpha1.AdminState(ds.AdminState),
+		},
+	}
+}
@@ -31,7 +31,6 @@ import (
 	"github.com/openyurtio/openyurt/cmd/yurt-iot-dock/app/options"
 	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
 	edgeCli "github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
-	edgexobj "github.com/openyurtio/openyurt/pkg/yurtiotdock/clients/edgex-foundry"
 	"github.com/openyurtio/openyurt/pkg/yurtiotdock/controllers/util"
 )
 
@@ -42,23 +41,34 @@ type DeviceSyncer struct {
 	NodePool string
 	// edge platform's client
 	deviceCli edgeCli.DeviceInterface
+	// edge platform's system event client, nil if the system events are unavailable
+	eventCli edgeCli.SystemEventInterface
 	// syncing period in seconds
 	syncPeriod time.Duration
-	Namespace  string
+	// full resyncing period in seconds when the system events are watched
+	resyncPeriod time.Duration
+	Namespace    string
 }
 
 // NewDeviceSyncer initialize a New DeviceSyncer
-func NewDeviceSyncer(client client.Client, opts *options.YurtIoTDockOptions, edgexdock *edgexobj.EdgexDock) (DeviceSyncer, error) {
-	devicelient, err := edgexdock.CreateDeviceClient()
+func NewDeviceSyncer(client client.Client, opts *options.YurtIoTDockOptions, iotdock edgeCli.IoTDock) (DeviceSyncer, error) {
+	devicelient, err := iotdock.CreateDeviceClient()
 	if err != nil {
 		return DeviceSyncer{}, err
 	}
+	eventclient, err := iotdock.CreateSystemEventClient()
+	if err != nil {
+		klog.V(2).InfoS("system events are unavailable, devices are synchronized periodically", "err", err)
+		eventclient = nil
+	}
 	return DeviceSyncer{
-		syncPeriod: time.Duration(opts.EdgeSyncPeriod) * time.Second,
-		deviceCli:  devicelient,
-		Client:     client,
-		NodePool:   opts.Nodepool,
-		Namespace:  opts.Namespace,
+		syncPeriod:   time.Duration(opts.EdgeSyncPeriod) * time.Second,
+		resyncPeriod: time.Duration(opts.EdgeResyncPeriod) * time.Second,
+		deviceCli:    devicelient,
+		eventCli:     eventclient,
+		Client:       client,
+		NodePool:     opts.Nodepool,
+		Namespace:    opts.Namespace,
 	}, nil
 }
 
@@ -72,44 +82,89 @@ func (ds *DeviceSyncer) NewDeviceSyncerRunnable() ctrlmgr.RunnableFunc {
 
 func (ds *DeviceSyncer) Run(stop <-chan struct{}) {
 	klog.V(1).Info("[Device] Starting the syncer...")
-	go func() {
-		for {
-			<-time.After(ds.syncPeriod)
-			klog.V(2).Info("[Device] Start a round of synchronization.")
-			// 1. get device on edge platform and OpenYurt
-			edgeDevices, kubeDevices, err := ds.getAllDevices()
-			if err != nil {
-				klog.V(3).ErrorS(err, "could not list the devices")
-				continue
-			}
+	es := &eventSyncer{
+		objectType:   edgeCli.SystemEventTypeDevice,
+		eventCli:     ds.eventCli,
+		syncPeriod:   ds.syncPeriod,
+		resyncPeriod: ds.resyncPeriod,
+		syncAll:      ds.syncAll,
+		handleEvent:  ds.handleEvent,
+	}
+	go es.run(stop)
 
-			// 2. find the device that need to be synchronized
-			redundantEdgeDevices, redundantKubeDevices, syncedDevices := ds.findDiffDevice(edgeDevices, kubeDevices)
-			klog.V(2).Infof("[Device] The number of objects waiting for synchronization { %s:%d, %s:%d, %s:%d }",
-				"Edge device should be added to OpenYurt", len(redundantEdgeDevices),
-				"OpenYurt device that should be deleted", len(redundantKubeDevices),
-				"Devices that should be synchronized", len(syncedDevices))
+	<-stop
+	klog.V(1).Info("[Device] Stopping the syncer")
+}
 
-			// 3. create device on OpenYurt which are exists in edge platform but not in OpenYurt
-			if err := ds.syncEdgeToKube(redundantEdgeDevices); err != nil {
-				klog.V(3).ErrorS(err, "could not create devices on OpenYurt")
-			}
+// syncAll runs a round of full synchronization between the edge platform and OpenYurt
+func (ds *DeviceSyncer) syncAll(ctx context.Context) error {
+	// 1. get device on edge platform and OpenYurt
+	edgeDevices, kubeDevices, err := ds.getAllDevices()
+	if err != nil {
+		return err
+	}
 
-			// 4. delete redundant device on OpenYurt
-			if err := ds.deleteDevices(redundantKubeDevices); err != nil {
-				klog.V(3).ErrorS(err, "could not delete redundant devices on OpenYurt")
-			}
+	// 2. find the device that need to be synchronized
+	redundantEdgeDevices, redundantKubeDevices, syncedDevices := ds.findDiffDevice(edgeDevices, kubeDevices)
+	klog.V(2).Infof("[Device] The number of objects waiting for synchronization { %s:%d, %s:%d, %s:%d }",
+		"Edge device should be added to OpenYurt", len(redundantEdgeDevices),
+		"OpenYurt device that should be deleted", len(redundantKubeDevices),
+		"Devices that should be synchronized", len(syncedDevices))
 
-			// 5. update device status on OpenYurt
-			if err := ds.updateDevices(syncedDevices); err != nil {
-				klog.V(3).ErrorS(err, "could not update devices status")
-			}
-			klog.V(2).Info("[Device] One round of synchronization is complete")
+	// 3. create device on OpenYurt which are exists in edge platform but not in OpenYurt
+	if err := ds.syncEdgeToKube(redundantEdgeDevices); err != nil {
+		klog.V(3).ErrorS(err, "could not create devices on OpenYurt")
+	}
+
+	// 4. delete redundant device on OpenYurt
+	if err := ds.deleteDevices(redundantKubeDevices); err != nil {
+		klog.V(3).ErrorS(err, "could not delete redundant devices on OpenYurt")
+	}
+
+	// 5. update device status on OpenYurt
+	if err := ds.updateDevices(syncedDevices); err != nil {
+		klog.V(3).ErrorS(err, "could not update devices status")
+	}
+	klog.V(2).Info("[Device] One round of synchronization is complete")
+	return nil
+}
+
+// handleEvent applies the change of a device on edge platform to OpenYurt
+func (ds *DeviceSyncer) handleEvent(ctx context.Context, event edgeCli.SystemEvent) error {
+	edgeDevice, err := ds.deviceCli.Convert(ctx, event, edgeCli.GetOptions{Namespace: ds.Namespace})
+	if err != nil {
+		return err
+	}
+	edgeName := util.GetEdgeDeviceName(edgeDevice, EdgeXObjectName)
+	kubeDevice, err := ds.getKubeDevice(ctx, edgeName)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case event.Action == edgeCli.SystemEventActionDelete:
+		if kubeDevice == nil || !kubeDevice.Status.Synced {
+			return nil
 		}
-	}()
+		return ds.deleteDevices(map[string]*iotv1alpha1.Device{edgeName: kubeDevice})
+	case kubeDevice == nil:
+		return ds.syncEdgeToKube(map[string]*iotv1alpha1.Device{edgeName: ds.completeCreateContent(edgeDevice)})
+	default:
+		return ds.updateDevices(map[string]*iotv1alpha1.Device{edgeName: ds.completeUpdateContent(kubeDevice, edgeDevice)})
+	}
+}
 
-	<-stop
-	klog.V(1).Info("[Device] Stopping the syncer")
+// getKubeDevice gets the device on OpenYurt by its name on edge platform, nil is returned if not found
+func (ds *DeviceSyncer) getKubeDevice(ctx context.Context, edgeName string) (*iotv1alpha1.Device, error) {
+	var kDevs iotv1alpha1.DeviceList
+	listOptions := client.MatchingFields{util.IndexerPathForEdgeName: util.EdgeNameIndexKey(ds.NodePool, edgeName)}
+	if err := ds.List(ctx, &kDevs, listOptions, client.InNamespace(ds.Namespace)); err != nil {
+		return nil, err
+	}
+	if len(kDevs.Items) == 0 {
+		return nil, nil
+	}
+	return &kDevs.Items[0], nil
 }
 
 // Get the existing Device on the Edge platform, as well as OpenYurt existing Device
@@ -0,0 +1,344 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openyurtio/openyurt/cmd/yurt-iot-dock/app/options"
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+	util "github.com/openyurtio/openyurt/pkg/yurtiotdock/controllers/util"
+)
+
+const (
+	// the maximum number of devices on which a command is executed concurrently
+	deviceCommandWorkers = 8
+	// the backoff of retries is doubled from deviceCommandBaseBackoff up to deviceCommandMaxBackoff
+	deviceCommandBaseBackoff = 2 * time.Second
+	deviceCommandMaxBackoff  = time.Minute
+
+	deviceCommandStartedReason   = "CommandStarted"
+	deviceCommandSucceededReason = "CommandSucceeded"
+	deviceCommandFailedReason    = "CommandFailed"
+	deviceCommandRetryReason     = "CommandRetry"
+)
+
+// DeviceCommandReconciler reconciles a DeviceCommand object
+type DeviceCommandReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	deviceCli clients.DevicePropertyInterface
+	recorder  record.EventRecorder
+	// which nodePool deviceCommandController is deployed in
+	NodePool  string
+	Namespace string
+}
+
+//+kubebuilder:rbac:groups=iot.openyurt.io,resources=devicecommands,verbs=get;list;watch
+//+kubebuilder:rbac:groups=iot.openyurt.io,resources=devicecommands/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+func (r *DeviceCommandReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var dc iotv1alpha1.DeviceCommand
+	if err := r.Get(ctx, req.NamespacedName, &dc); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// If objects doesn't belong to the Edge platform to which the controller is connected, the controller does not handle events for that object
+	if dc.Spec.NodePool != r.NodePool || dc.IsFinished() || !dc.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	klog.V(3).Infof("Reconciling the DeviceCommand: %s", dc.GetName())
+
+	newStatus := dc.Status.DeepCopy()
+	// 1. resolve the target devices once, the targets are fixed in the status of command
+	if newStatus.Phase == "" || newStatus.Phase == iotv1alpha1.DeviceCommandPending {
+		if err := r.resolveTargets(ctx, &dc, newStatus); err != nil {
+			return ctrl.Result{}, err
+		}
+		now := metav1.Now()
+		newStatus.StartTime = &now
+		if len(newStatus.Results) == 0 {
+			newStatus.Phase = iotv1alpha1.DeviceCommandFailed
+			newStatus.CompletionTime = &now
+			if newStatus.Message == "" {
+				newStatus.Message = "no device matches the command"
+			}
+			r.recorder.Event(&dc, corev1.EventTypeWarning, deviceCommandFailedReason, newStatus.Message)
+			return ctrl.Result{}, r.updateStatus(ctx, &dc, newStatus)
+		}
+		newStatus.Phase = iotv1alpha1.DeviceCommandRunning
+		r.recorder.Eventf(&dc, corev1.EventTypeNormal, deviceCommandStartedReason,
+			"%s %s on %d device(s), issued by %q", actionOf(&dc), dc.Spec.Command, len(newStatus.Results), dc.Spec.Issuer)
+	}
+
+	// 2. execute the command on the devices whose backoff has elapsed
+	now := time.Now()
+	var due []int
+	var requeueAfter time.Duration
+	for i := range newStatus.Results {
+		result := &newStatus.Results[i]
+		if result.Phase == iotv1alpha1.DeviceCommandSucceeded || result.Phase == iotv1alpha1.DeviceCommandFailed {
+			continue
+		}
+		if wait := nextAttemptAfter(result, now); wait > 0 {
+			if requeueAfter == 0 || wait < requeueAfter {
+				requeueAfter = wait
+			}
+			continue
+		}
+		due = append(due, i)
+	}
+	if len(due) != 0 {
+		// the attempts are saved before the command is executed, so that a conflicting
+		// status update never runs the command on the devices again
+		attemptTime := metav1.NewTime(now)
+		for _, i := range due {
+			result := &newStatus.Results[i]
+			result.Attempts++
+			result.LastAttemptTime = &attemptTime
+			result.Phase = iotv1alpha1.DeviceCommandRunning
+		}
+		if err := r.updateStatus(ctx, &dc, newStatus); err != nil {
+			if apierrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, err
+		}
+		newStatus = dc.Status.DeepCopy()
+
+		workqueue.ParallelizeUntil(ctx, deviceCommandWorkers, len(due), func(piece int) {
+			r.execute(ctx, &dc, &newStatus.Results[due[piece]])
+		})
+	}
+
+	// 3. aggregate the results of all target devices
+	backoffLimit := iotv1alpha1.DefaultDeviceCommandBackoffLimit
+	if dc.Spec.BackoffLimit != nil {
+		backoffLimit = *dc.Spec.BackoffLimit
+	}
+	newStatus.Succeeded, newStatus.Failed = 0, 0
+	finished := true
+	for _, i := range due {
+		result := &newStatus.Results[i]
+		if result.Phase != iotv1alpha1.DeviceCommandFailed {
+			continue
+		}
+		if result.Attempts <= backoffLimit {
+			// the device still has retries left
+			result.Phase = iotv1alpha1.DeviceCommandRunning
+			r.recorder.Eventf(&dc, corev1.EventTypeWarning, deviceCommandRetryReason,
+				"attempt %d on device %s failed: %s", result.Attempts, result.DeviceName, result.Message)
+			if wait := nextAttemptAfter(result, now); requeueAfter == 0 || wait < requeueAfter {
+				requeueAfter = wait
+			}
+		} else {
+			r.recorder.Eventf(&dc, corev1.EventTypeWarning, deviceCommandFailedReason,
+				"command failed on device %s after %d attempt(s): %s", result.DeviceName, result.Attempts, result.Message)
+		}
+	}
+	for i := range newStatus.Results {
+		switch newStatus.Results[i].Phase {
+		case iotv1alpha1.DeviceCommandSucceeded:
+			newStatus.Succeeded++
+		case iotv1alpha1.DeviceCommandFailed:
+			newStatus.Failed++
+		default:
+			finished = false
+		}
+	}
+
+	if finished {
+		completionTime := metav1.Now()
+		newStatus.CompletionTime = &completionTime
+		if newStatus.Failed == 0 {
+			newStatus.Phase = iotv1alpha1.DeviceCommandSucceeded
+			newStatus.Message = ""
+			r.recorder.Eventf(&dc, corev1.EventTypeNormal, deviceCommandSucceededReason,
+				"command succeeded on %d device(s)", newStatus.Succeeded)
+		} else {
+			newStatus.Phase = iotv1alpha1.DeviceCommandFailed
+			newStatus.Message = fmt.Sprintf("command failed on %d of %d device(s)", newStatus.Failed, len(newStatus.Results))
+			r.recorder.Event(&dc, corev1.EventTypeWarning, deviceCommandFailedReason, newStatus.Message)
+		}
+	}
+
+	// the outcomes of executed commands must not be lost, the latest command is
+	// fetched and its status is overwritten on conflict
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.updateStatus(ctx, &dc, newStatus)
+		if apierrors.IsConflict(err) {
+			if getErr := r.Get(ctx, req.NamespacedName, &dc); getErr != nil {
+				return getErr
+			}
+		}
+		return err
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	if !finished {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DeviceCommandReconciler) SetupWithManager(mgr ctrl.Manager, opts *options.YurtIoTDockOptions, iotdock clients.IoTDock) error {
+	deviceclient, err := iotdock.CreateDeviceClient()
+	if err != nil {
+		return err
+	}
+	r.deviceCli = deviceclient
+	r.recorder = mgr.GetEventRecorderFor("yurt-iot-dock")
+	r.NodePool = opts.Nodepool
+	r.Namespace = opts.Namespace
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&iotv1alpha1.DeviceCommand{}).
+		Complete(r)
+}
+
+// resolveTargets fills the results of command with the target devices,
+// the devices are selected by name or by labels within the nodePool of command
+func (r *DeviceCommandReconciler) resolveTargets(ctx context.Context, dc *iotv1alpha1.DeviceCommand, status *iotv1alpha1.DeviceCommandStatus) error {
+	var names []string
+	if dc.Spec.Selector == nil {
+		names = append(names, dc.Spec.DeviceName)
+	} else {
+		selector, err := metav1.LabelSelectorAsSelector(dc.Spec.Selector)
+		if err != nil {
+			status.Message = fmt.Sprintf("invalid selector: %v", err)
+			return nil
+		}
+		var deviceList iotv1alpha1.DeviceList
+		if err := r.List(ctx, &deviceList, client.InNamespace(dc.Namespace),
+			client.MatchingFields{util.IndexerPathForNodepool: dc.Spec.NodePool},
+			client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return err
+		}
+		for i := range deviceList.Items {
+			names = append(names, deviceList.Items[i].Name)
+		}
+	}
+
+	status.Results = make([]iotv1alpha1.DeviceCommandResult, 0, len(names))
+	for _, name := range names {
+		status.Results = append(status.Results, iotv1alpha1.DeviceCommandResult{
+			DeviceName: name,
+			Phase:      iotv1alpha1.DeviceCommandPending,
+		})
+	}
+	return nil
+}
+
+// execute runs the attempt recorded in result on the device, and records the outcome in result
+func (r *DeviceCommandReconciler) execute(ctx context.Context, dc *iotv1alpha1.DeviceCommand, result *iotv1alpha1.DeviceCommandResult) {
+	value, err := r.executeOnDevice(ctx, dc, result.DeviceName)
+	if err != nil {
+		klog.V(4).ErrorS(err, "could not execute command on device", "DeviceCommand", dc.GetName(), "DeviceName", result.DeviceName)
+		result.Phase = iotv1alpha1.DeviceCommandFailed
+		result.Message = err.Error()
+		return
+	}
+	klog.V(4).Infof("DeviceCommand: %s, successfully executed %s on device %s", dc.GetName(), dc.Spec.Command, result.DeviceName)
+	result.Phase = iotv1alpha1.DeviceCommandSucceeded
+	result.Value = value
+	result.Message = ""
+}
+
+func (r *DeviceCommandReconciler) executeOnDevice(ctx context.Context, dc *iotv1alpha1.DeviceCommand, deviceName string) (string, error) {
+	var d iotv1alpha1.Device
+	if err := r.Get(ctx, types.NamespacedName{Namespace: dc.Namespace, Name: deviceName}, &d); err != nil {
+		return "", err
+	}
+	if d.Spec.NodePool != dc.Spec.NodePool {
+		return "", fmt.Errorf("device %s does not belong to nodepool %s", deviceName, dc.Spec.NodePool)
+	}
+	if !d.Status.Synced {
+		return "", fmt.Errorf("device %s is not synced to the edge platform", deviceName)
+	}
+
+	timeout := iotv1alpha1.DefaultDeviceCommandTimeoutSeconds
+	if dc.Spec.TimeoutSeconds != nil {
+		timeout = *dc.Spec.TimeoutSeconds
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	if actionOf(dc) == iotv1alpha1.DeviceCommandActionGet {
+		actual, err := r.deviceCli.GetPropertyState(attemptCtx, dc.Spec.Command, &d, clients.GetOptions{Namespace: r.Namespace})
+		if err != nil {
+			return "", err
+		}
+		return actual.ActualValue, nil
+	}
+
+	// only the commanded property is set, the desired properties of device are left untouched
+	target := d.DeepCopy()
+	target.Spec.DeviceProperties = map[string]iotv1alpha1.DesiredPropertyState{
+		dc.Spec.Command: {
+			Name:         dc.Spec.Command,
+			DesiredValue: dc.Spec.Value,
+		},
+	}
+	if err := r.deviceCli.UpdatePropertyState(attemptCtx, dc.Spec.Command, target, clients.UpdateOptions{}); err != nil {
+		return "", err
+	}
+	return dc.Spec.Value, nil
+}
+
+func (r *DeviceCommandReconciler) updateStatus(ctx context.Context, dc *iotv1alpha1.DeviceCommand, status *iotv1alpha1.DeviceCommandStatus) error {
+	dc.Status = *status
+	return r.Status().Update(ctx, dc)
+}
+
+// nextAttemptAfter returns how long to wait before the next attempt on the device of result
+func nextAttemptAfter(result *iotv1alpha1.DeviceCommandResult, now time.Time) time.Duration {
+	if result.Attempts == 0 || result.LastAttemptTime == nil {
+		return 0
+	}
+	backoff := deviceCommandBaseBackoff
+	for i := int32(1); i < result.Attempts && backoff < deviceCommandMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > deviceCommandMaxBackoff {
+		backoff = deviceCommandMaxBackoff
+	}
+	return result.LastAttemptTime.Add(backoff).Sub(now)
+}
+
+func actionOf(dc *iotv1alpha1.DeviceCommand) iotv1alpha1.DeviceCommandAction {
+	if dc.Spec.Action == "" {
+		return iotv1alpha1.DeviceCommandActionSet
+	}
+	return dc.Spec.Action
+}
@@ -31,15 +31,18 @@ import (
 	"github.com/openyurtio/openyurt/cmd/yurt-iot-dock/app/options"
 	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
 	devcli "github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
-	edgexobj "github.com/openyurtio/openyurt/pkg/yurtiotdock/clients/edgex-foundry"
 	"github.com/openyurtio/openyurt/pkg/yurtiotdock/controllers/util"
 )
 
 type DeviceProfileSyncer struct {
 	// syncing period in seconds
 	syncPeriod time.Duration
+	// full resyncing period in seconds when the system events are watched
+	resyncPeriod time.Duration
 	// edge platform client
 	edgeClient devcli.DeviceProfileInterface
+	// edge platform's system event client, nil if the system events are unavailable
+	eventClient devcli.SystemEventInterface
 	// Kubernetes client
 	client.Client
 	NodePool  string
@@ -47,17 +50,24 @@ type DeviceProfileSyncer struct {
 }
 
 // NewDeviceProfileSyncer initialize a New DeviceProfileSyncer
-func NewDeviceProfileSyncer(client client.Client, opts *options.YurtIoTDockOptions, edgexdock *edgexobj.EdgexDock) (DeviceProfileSyncer, error) {
-	edgeclient, err := edgexdock.CreateDeviceProfileClient()
+func NewDeviceProfileSyncer(client client.Client, opts *options.YurtIoTDockOptions, iotdock devcli.IoTDock) (DeviceProfileSyncer, error) {
+	edgeclient, err := iotdock.CreateDeviceProfileClient()
 	if err != nil {
 		return DeviceProfileSyncer{}, err
 	}
+	eventclient, err := iotdock.CreateSystemEventClient()
+	if err != nil {
+		klog.V(2).InfoS("system events are unavailable, deviceProfiles are synchronized periodically", "err", err)
+		eventclient = nil
+	}
 	return DeviceProfileSyncer{
-		syncPeriod: time.Duration(opts.EdgeSyncPeriod) * time.Second,
-		edgeClient: edgeclient,
-		Client:     client,
-		NodePool:   opts.Nodepool,
-		Namespace:  opts.Namespace,
+		syncPeriod:   time.Duration(opts.EdgeSyncPeriod) * time.Second,
+		resyncPeriod: time.Duration(opts.EdgeResyncPeriod) * time.Second,
+		edgeClient:   edgeclient,
+		eventClient:  eventclient,
+		Client:       client,
+		NodePool:     opts.Nodepool,
+		Namespace:    opts.Namespace,
 	}, nil
 }
 
@@ -71,43 +81,88 @@ func (dps *DeviceProfileSyncer) NewDeviceProfileSyncerRunnable() ctrlmgr.Runnabl
 
 func (dps *DeviceProfileSyncer) Run(stop <-chan struct{}) {
 	klog.V(1).Info("[DeviceProfile] Starting the syncer...")
-	go func() {
-		for {
-			<-time.After(dps.syncPeriod)
-			klog.V(2).Info("[DeviceProfile] Start a round of synchronization.")
-
-			// 1. get deviceProfiles on edge platform and OpenYurt
-			edgeDeviceProfiles, kubeDeviceProfiles, err := dps.getAllDeviceProfiles()
-			if err != nil {
-				klog.V(3).ErrorS(err, "could not list the deviceProfiles")
-				continue
-			}
+	es := &eventSyncer{
+		objectType:   devcli.SystemEventTypeDeviceProfile,
+		eventCli:     dps.eventClient,
+		syncPeriod:   dps.syncPeriod,
+		resyncPeriod: dps.resyncPeriod,
+		syncAll:      dps.syncAll,
+		handleEvent:  dps.handleEvent,
+	}
+	go es.run(stop)
 
-			// 2. find the deviceProfiles that need to be synchronized
-			redundantEdgeDeviceProfiles, redundantKubeDeviceProfiles, syncedDeviceProfiles :=
-				dps.findDiffDeviceProfiles(edgeDeviceProfiles, kubeDeviceProfiles)
-			klog.V(2).Infof("[DeviceProfile] The number of objects waiting for synchronization { %s:%d, %s:%d, %s:%d }",
-				"Edge deviceProfiles should be added to OpenYurt", len(redundantEdgeDeviceProfiles),
-				"OpenYurt deviceProfiles that should be deleted", len(redundantKubeDeviceProfiles),
-				"DeviceProfiles that should be synchronized", len(syncedDeviceProfiles))
-
-			// 3. create deviceProfiles on OpenYurt which are exists in edge platform but not in OpenYurt
-			if err := dps.syncEdgeToKube(redundantEdgeDeviceProfiles); err != nil {
-				klog.V(3).ErrorS(err, "could not create deviceProfiles on OpenYurt")
-			}
+	<-stop
+	klog.V(1).Info("[DeviceProfile] Stopping the syncer")
+}
 
-			// 4. delete redundant deviceProfiles on OpenYurt
-			if err := dps.deleteDeviceProfiles(redundantKubeDeviceProfiles); err != nil {
-				klog.V(3).ErrorS(err, "could not delete redundant deviceProfiles on OpenYurt")
-			}
+// syncAll runs a round of full synchronization between the edge platform and OpenYurt
+func (dps *DeviceProfileSyncer) syncAll(ctx context.Context) error {
+	// 1. get deviceProfiles on edge platform and OpenYurt
+	edgeDeviceProfiles, kubeDeviceProfiles, err := dps.getAllDeviceProfiles()
+	if err != nil {
+		return err
+	}
+
+	// 2. find the deviceProfiles that need to be synchronized
+	redundantEdgeDeviceProfiles, redundantKubeDeviceProfiles, syncedDeviceProfiles :=
+		dps.findDiffDeviceProfiles(edgeDeviceProfiles, kubeDeviceProfiles)
+	klog.V(2).Infof("[DeviceProfile] The number of objects waiting for synchronization { %s:%d, %s:%d, %s:%d }",
+		"Edge deviceProfiles should be added to OpenYurt", len(redundantEdgeDeviceProfiles),
+		"OpenYurt deviceProfiles that should be deleted", len(redundantKubeDeviceProfiles),
+		"DeviceProfiles that should be synchronized", len(syncedDeviceProfiles))
+
+	// 3. create deviceProfiles on OpenYurt which are exists in edge platform but not in OpenYurt
+	if err := dps.syncEdgeToKube(redundantEdgeDeviceProfiles); err != nil {
+		klog.V(3).ErrorS(err, "could not create deviceProfiles on OpenYurt")
+	}
+
+	// 4. delete redundant deviceProfiles on OpenYurt
+	if err := dps.deleteDeviceProfiles(redundantKubeDeviceProfiles); err != nil {
+		klog.V(3).ErrorS(err, "could not delete redundant deviceProfiles on OpenYurt")
+	}
 
-			// 5. update deviceProfiles on OpenYurt
-			// TODO
+	// 5. update deviceProfiles on OpenYurt
+	// TODO
+	return nil
+}
+
+// handleEvent applies the change of a deviceProfile on edge platform to OpenYurt
+func (dps *DeviceProfileSyncer) handleEvent(ctx context.Context, event devcli.SystemEvent) error {
+	edgeDeviceProfile, err := dps.edgeClient.Convert(ctx, event, devcli.GetOptions{Namespace: dps.Namespace})
+	if err != nil {
+		return err
+	}
+	edgeName := util.GetEdgeDeviceProfileName(edgeDeviceProfile, EdgeXObjectName)
+	kubeDeviceProfile, err := dps.getKubeDeviceProfile(ctx, edgeName)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case event.Action == devcli.SystemEventActionDelete:
+		if kubeDeviceProfile == nil || !kubeDeviceProfile.Status.Synced {
+			return nil
 		}
-	}()
+		return dps.deleteDeviceProfiles(map[string]*iotv1alpha1.DeviceProfile{edgeName: kubeDeviceProfile})
+	case kubeDeviceProfile == nil:
+		return dps.syncEdgeToKube(map[string]*iotv1alpha1.DeviceProfile{edgeName: dps.completeCreateContent(edgeDeviceProfile)})
+	default:
+		// the same as full synchronization, deviceProfiles on OpenYurt are not updated
+		return nil
+	}
+}
 
-	<-stop
-	klog.V(1).Info("[DeviceProfile] Stopping the syncer")
+// getKubeDeviceProfile gets the deviceProfile on OpenYurt by its name on edge platform, nil is returned if not found
+func (dps *DeviceProfileSyncer) getKubeDeviceProfile(ctx context.Context, edgeName string) (*iotv1alpha1.DeviceProfile, error) {
+	var kDps iotv1alpha1.DeviceProfileList
+	listOptions := client.MatchingFields{util.IndexerPathForEdgeName: util.EdgeNameIndexKey(dps.NodePool, edgeName)}
+	if err := dps.List(ctx, &kDps, listOptions, client.InNamespace(dps.Namespace)); err != nil {
+		return nil, err
+	}
+	if len(kDps.Items) == 0 {
+		return nil, nil
+	}
+	return &kDps.Items[0], nil
 }
 
 // Get the existing DeviceProfile on the Edge platform, as well as OpenYurt existing DeviceProfile
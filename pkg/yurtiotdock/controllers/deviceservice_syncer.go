@@ -30,7 +30,6 @@ import (
 	"github.com/openyurtio/openyurt/cmd/yurt-iot-dock/app/options"
 	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
 	iotcli "github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
-	edgexobj "github.com/openyurtio/openyurt/pkg/yurtiotdock/clients/edgex-foundry"
 	"github.com/openyurtio/openyurt/pkg/yurtiotdock/controllers/util"
 )
 
@@ -38,20 +37,31 @@ type DeviceServiceSyncer struct {
 	// Kubernetes client
 	client.Client
 	// syncing period in seconds
-	syncPeriod       time.Duration
+	syncPeriod time.Duration
+	// full resyncing period in seconds when the system events are watched
+	resyncPeriod     time.Duration
 	deviceServiceCli iotcli.DeviceServiceInterface
-	NodePool         string
-	Namespace        string
+	// edge platform's system event client, nil if the system events are unavailable
+	eventCli  iotcli.SystemEventInterface
+	NodePool  string
+	Namespace string
 }
 
-func NewDeviceServiceSyncer(client client.Client, opts *options.YurtIoTDockOptions, edgexdock *edgexobj.EdgexDock) (DeviceServiceSyncer, error) {
-	deviceserviceclient, err := edgexdock.CreateDeviceServiceClient()
+func NewDeviceServiceSyncer(client client.Client, opts *options.YurtIoTDockOptions, iotdock iotcli.IoTDock) (DeviceServiceSyncer, error) {
+	deviceserviceclient, err := iotdock.CreateDeviceServiceClient()
 	if err != nil {
 		return DeviceServiceSyncer{}, err
 	}
+	eventclient, err := iotdock.CreateSystemEventClient()
+	if err != nil {
+		klog.V(2).InfoS("system events are unavailable, deviceServices are synchronized periodically", "err", err)
+		eventclient = nil
+	}
 	return DeviceServiceSyncer{
 		syncPeriod:       time.Duration(opts.EdgeSyncPeriod) * time.Second,
+		resyncPeriod:     time.Duration(opts.EdgeResyncPeriod) * time.Second,
 		deviceServiceCli: deviceserviceclient,
+		eventCli:         eventclient,
 		Client:           client,
 		NodePool:         opts.Nodepool,
 		Namespace:        opts.Namespace,
@@ -67,45 +77,90 @@ func (ds *DeviceServiceSyncer) NewDeviceServiceSyncerRunnable() ctrlmgr.Runnable
 
 func (ds *DeviceServiceSyncer) Run(stop <-chan struct{}) {
 	klog.V(1).Info("[DeviceService] Starting the syncer...")
-	go func() {
-		for {
-			<-time.After(ds.syncPeriod)
-			klog.V(2).Info("[DeviceService] Start a round of synchronization.")
-			// 1. get deviceServices on edge platform and OpenYurt
-			edgeDeviceServices, kubeDeviceServices, err := ds.getAllDeviceServices()
-			if err != nil {
-				klog.V(3).ErrorS(err, "could not list the deviceServices")
-				continue
-			}
+	es := &eventSyncer{
+		objectType:   iotcli.SystemEventTypeDeviceService,
+		eventCli:     ds.eventCli,
+		syncPeriod:   ds.syncPeriod,
+		resyncPeriod: ds.resyncPeriod,
+		syncAll:      ds.syncAll,
+		handleEvent:  ds.handleEvent,
+	}
+	go es.run(stop)
 
-			// 2. find the deviceServices that need to be synchronized
-			redundantEdgeDeviceServices, redundantKubeDeviceServices, syncedDeviceServices :=
-				ds.findDiffDeviceServices(edgeDeviceServices, kubeDeviceServices)
-			klog.V(2).Infof("[DeviceService] The number of objects waiting for synchronization { %s:%d, %s:%d, %s:%d }",
-				"Edge deviceServices should be added to OpenYurt", len(redundantEdgeDeviceServices),
-				"OpenYurt deviceServices that should be deleted", len(redundantKubeDeviceServices),
-				"DeviceServices that should be synchronized", len(syncedDeviceServices))
-
-			// 3. create deviceServices on OpenYurt which are exists in edge platform but not in OpenYurt
-			if err := ds.syncEdgeToKube(redundantEdgeDeviceServices); err != nil {
-				klog.V(3).ErrorS(err, "could not create deviceServices on OpenYurt")
-			}
+	<-stop
+	klog.V(1).Info("[DeviceService] Stopping the syncer")
+}
 
-			// 4. delete redundant deviceServices on OpenYurt
-			if err := ds.deleteDeviceServices(redundantKubeDeviceServices); err != nil {
-				klog.V(3).ErrorS(err, "could not delete redundant deviceServices on OpenYurt")
-			}
+// syncAll runs a round of full synchronization between the edge platform and OpenYurt
+func (ds *DeviceServiceSyncer) syncAll(ctx context.Context) error {
+	// 1. get deviceServices on edge platform and OpenYurt
+	edgeDeviceServices, kubeDeviceServices, err := ds.getAllDeviceServices()
+	if err != nil {
+		return err
+	}
 
-			// 5. update deviceService status on OpenYurt
-			if err := ds.updateDeviceServices(syncedDeviceServices); err != nil {
-				klog.V(3).ErrorS(err, "could not update deviceServices")
-			}
-			klog.V(2).Info("[DeviceService] One round of synchronization is complete")
+	// 2. find the deviceServices that need to be synchronized
+	redundantEdgeDeviceServices, redundantKubeDeviceServices, syncedDeviceServices :=
+		ds.findDiffDeviceServices(edgeDeviceServices, kubeDeviceServices)
+	klog.V(2).Infof("[DeviceService] The number of objects waiting for synchronization { %s:%d, %s:%d, %s:%d }",
+		"Edge deviceServices should be added to OpenYurt", len(redundantEdgeDeviceServices),
+		"OpenYurt deviceServices that should be deleted", len(redundantKubeDeviceServices),
+		"DeviceServices that should be synchronized", len(syncedDeviceServices))
+
+	// 3. create deviceServices on OpenYurt which are exists in edge platform but not in OpenYurt
+	if err := ds.syncEdgeToKube(redundantEdgeDeviceServices); err != nil {
+		klog.V(3).ErrorS(err, "could not create deviceServices on OpenYurt")
+	}
+
+	// 4. delete redundant deviceServices on OpenYurt
+	if err := ds.deleteDeviceServices(redundantKubeDeviceServices); err != nil {
+		klog.V(3).ErrorS(err, "could not delete redundant deviceServices on OpenYurt")
+	}
+
+	// 5. update deviceService status on OpenYurt
+	if err := ds.updateDeviceServices(syncedDeviceServices); err != nil {
+		klog.V(3).ErrorS(err, "could not update deviceServices")
+	}
+	klog.V(2).Info("[DeviceService] One round of synchronization is complete")
+	return nil
+}
+
+// handleEvent applies the change of a deviceService on edge platform to OpenYurt
+func (ds *DeviceServiceSyncer) handleEvent(ctx context.Context, event iotcli.SystemEvent) error {
+	edgeDeviceService, err := ds.deviceServiceCli.Convert(ctx, event, iotcli.GetOptions{Namespace: ds.Namespace})
+	if err != nil {
+		return err
+	}
+	edgeName := util.GetEdgeDeviceServiceName(edgeDeviceService, EdgeXObjectName)
+	kubeDeviceService, err := ds.getKubeDeviceService(ctx, edgeName)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case event.Action == iotcli.SystemEventActionDelete:
+		if kubeDeviceService == nil || !kubeDeviceService.Status.Synced {
+			return nil
 		}
-	}()
+		return ds.deleteDeviceServices(map[string]*iotv1alpha1.DeviceService{edgeName: kubeDeviceService})
+	case kubeDeviceService == nil:
+		return ds.syncEdgeToKube(map[string]*iotv1alpha1.DeviceService{edgeName: ds.completeCreateContent(edgeDeviceService)})
+	default:
+		return ds.updateDeviceServices(map[string]*iotv1alpha1.DeviceService{edgeName: ds.completeUpdateContent(kubeDeviceService, edgeDeviceService)})
+	}
+}
 
-	<-stop
-	klog.V(1).Info("[DeviceService] Stopping the syncer")
+// getKubeDeviceService gets the deviceService on OpenYurt by its name on edge platform, nil is returned if not found
+func (ds *DeviceServiceSyncer) getKubeDeviceService(ctx context.Context, edgeName string) (*iotv1alpha1.DeviceService, error) {
+	var kDevSs iotv1alpha1.DeviceServiceList
+	listOptions := client.MatchingFields{util.IndexerPathForEdgeName: util.EdgeNameIndexKey(ds.NodePool, edgeName)}
+	if err := ds.List(ctx, &kDevSs, listOptions, client.InNamespace(ds.Namespace)); err != nil {
+		return nil, err
+	}
+	if len(kDevSs.Items) == 0 {
+		return nil, nil
+	}
+	return &kDevSs.Items[0], nil
 }
 
 // Get the existing DeviceService on the Edge platform, as well as OpenYurt existing DeviceService
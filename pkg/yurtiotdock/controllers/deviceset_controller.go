@@ -0,0 +1,282 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openyurtio/openyurt/cmd/yurt-iot-dock/app/options"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+	util "github.com/openyurtio/openyurt/pkg/yurtiotdock/controllers/util"
+)
+
+// DeviceSetReconciler reconciles a DeviceSet object, it only provisions the devices
+// of the nodePool it is deployed in and reports the status of the nodePool.
+type DeviceSetReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// which nodePool deviceSetController is deployed in
+	NodePool  string
+	Namespace string
+}
+
+//+kubebuilder:rbac:groups=iot.openyurt.io,resources=devicesets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=iot.openyurt.io,resources=devicesets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=iot.openyurt.io,resources=devicesets/finalizers,verbs=update
+//+kubebuilder:rbac:groups=apps.openyurt.io,resources=nodepools,verbs=get;list;watch
+
+func (r *DeviceSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var ds iotv1alpha1.DeviceSet
+	if err := r.Get(ctx, req.NamespacedName, &ds); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// the provisioned devices are deleted by garbage collector with the DeviceSet
+	if !ds.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	klog.V(3).Infof("Reconciling the DeviceSet: %s", ds.GetName())
+
+	var pool appsv1beta2.NodePool
+	if err := r.Get(ctx, types.NamespacedName{Name: r.NodePool}, &pool); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	desired, renderErr := util.RenderDeviceSet(&ds, &pool)
+	if renderErr != nil {
+		klog.ErrorS(renderErr, "could not render devices", "DeviceSet", ds.GetName(), "NodePool", r.NodePool)
+		return ctrl.Result{}, r.updatePoolStatus(ctx, &ds, &iotv1alpha1.DeviceSetPoolStatus{
+			Message: renderErr.Error(),
+		})
+	}
+
+	var deviceList iotv1alpha1.DeviceList
+	if err := r.List(ctx, &deviceList, client.InNamespace(ds.Namespace),
+		client.MatchingLabels{iotv1alpha1.DeviceSetLabel: ds.Name},
+		client.MatchingFields{util.IndexerPathForNodepool: r.NodePool}); err != nil {
+		return ctrl.Result{}, err
+	}
+	existing := make(map[string]*iotv1alpha1.Device, len(deviceList.Items))
+	for i := range deviceList.Items {
+		if metav1.IsControlledBy(&deviceList.Items[i], &ds) {
+			existing[deviceList.Items[i].Name] = &deviceList.Items[i]
+		}
+	}
+
+	// 1. create the missing devices and update the drifted ones
+	status := &iotv1alpha1.DeviceSetPoolStatus{Desired: int32(len(desired))}
+	var failed []string
+	for _, device := range desired {
+		current := existing[device.Name]
+		delete(existing, device.Name)
+		synced, err := r.reconcileDevice(ctx, &ds, device, current)
+		if err != nil {
+			klog.ErrorS(err, "could not provision device", "DeviceSet", ds.GetName(), "DeviceName", device.Name)
+			failed = append(failed, device.Name)
+			continue
+		}
+		status.Provisioned++
+		if synced {
+			status.Synced++
+		}
+	}
+
+	// 2. delete the devices which are not desired any more
+	for _, device := range existing {
+		klog.V(4).Infof("DeviceSet: %s, deleting the device %s which is not desired", ds.GetName(), device.Name)
+		if err := r.Delete(ctx, device); client.IgnoreNotFound(err) != nil {
+			klog.ErrorS(err, "could not delete device", "DeviceSet", ds.GetName(), "DeviceName", device.Name)
+			failed = append(failed, device.Name)
+		}
+	}
+	if len(failed) != 0 {
+		status.Message = fmt.Sprintf("the following devices could not be provisioned: %v", failed)
+	}
+
+	if desired == nil && len(failed) == 0 {
+		// the nodePool is not selected by the DeviceSet any more
+		return ctrl.Result{}, r.removePoolStatus(ctx, &ds)
+	}
+	if err := r.updatePoolStatus(ctx, &ds, status); err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(failed) != 0 {
+		return ctrl.Result{}, fmt.Errorf("could not provision devices of DeviceSet %s: %v", ds.GetName(), failed)
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DeviceSetReconciler) SetupWithManager(mgr ctrl.Manager, opts *options.YurtIoTDockOptions, _ clients.IoTDock) error {
+	r.NodePool = opts.Nodepool
+	r.Namespace = opts.Namespace
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&iotv1alpha1.DeviceSet{}).
+		Owns(&iotv1alpha1.Device{}).
+		// the selection of DeviceSets may change with the labels of nodePool
+		Watches(&appsv1beta2.NodePool{}, handler.EnqueueRequestsFromMapFunc(r.mapNodePoolToDeviceSets)).
+		Complete(r)
+}
+
+func (r *DeviceSetReconciler) mapNodePoolToDeviceSets(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetName() != r.NodePool {
+		return nil
+	}
+	var dsList iotv1alpha1.DeviceSetList
+	if err := r.List(ctx, &dsList); err != nil {
+		klog.ErrorS(err, "could not list devicesets", "NodePool", r.NodePool)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(dsList.Items))
+	for i := range dsList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&dsList.Items[i])})
+	}
+	return requests
+}
+
+// reconcileDevice creates the device if it does not exist, or updates it if it's drifted from the template,
+// it returns whether the device has been synced to the edge platform
+func (r *DeviceSetReconciler) reconcileDevice(ctx context.Context, ds *iotv1alpha1.DeviceSet, desired, current *iotv1alpha1.Device) (bool, error) {
+	if current == nil {
+		if err := controllerutil.SetControllerReference(ds, desired, r.Scheme); err != nil {
+			return false, err
+		}
+		klog.V(4).Infof("DeviceSet: %s, creating the device %s", ds.GetName(), desired.Name)
+		if err := r.Create(ctx, desired); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return false, fmt.Errorf("device %s already exists and is not provisioned by the deviceset", desired.Name)
+			}
+			return false, err
+		}
+		return false, nil
+	}
+
+	if !isDeviceDrifted(desired, current) {
+		return current.Status.Synced, nil
+	}
+	klog.V(4).Infof("DeviceSet: %s, updating the drifted device %s", ds.GetName(), current.Name)
+	updated := current.DeepCopy()
+	updated.Spec = desired.Spec
+	if updated.Labels == nil {
+		updated.Labels = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		updated.Labels[k] = v
+	}
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	for k, v := range desired.Annotations {
+		updated.Annotations[k] = v
+	}
+	if err := r.Update(ctx, updated); err != nil {
+		return false, err
+	}
+	return updated.Status.Synced, nil
+}
+
+// isDeviceDrifted returns true if the spec, labels or annotations of device are different from the rendered one
+func isDeviceDrifted(desired, current *iotv1alpha1.Device) bool {
+	if !apiequality.Semantic.DeepEqual(desired.Spec, current.Spec) {
+		return true
+	}
+	for k, v := range desired.Labels {
+		if current.Labels[k] != v {
+			return true
+		}
+	}
+	for k, v := range desired.Annotations {
+		if current.Annotations[k] != v {
+			return true
+		}
+	}
+	return false
+}
+
+// updatePoolStatus sets the status of the nodePool in DeviceSet, the statuses of other nodePools
+// are reported by their own yurt-iot-dock, so conflicts are retried on the latest DeviceSet.
+func (r *DeviceSetReconciler) updatePoolStatus(ctx context.Context, ds *iotv1alpha1.DeviceSet, status *iotv1alpha1.DeviceSetPoolStatus) error {
+	status.NodePool = r.NodePool
+	status.ObservedGeneration = ds.Generation
+	status.LastUpdateTime = metav1.Now()
+	return r.mutatePoolStatus(ctx, ds, func(pools []iotv1alpha1.DeviceSetPoolStatus) []iotv1alpha1.DeviceSetPoolStatus {
+		for i := range pools {
+			if pools[i].NodePool == r.NodePool {
+				if isPoolStatusUnchanged(&pools[i], status) {
+					return nil
+				}
+				pools[i] = *status
+				return pools
+			}
+		}
+		pools = append(pools, *status)
+		sort.Slice(pools, func(i, j int) bool { return pools[i].NodePool < pools[j].NodePool })
+		return pools
+	})
+}
+
+func (r *DeviceSetReconciler) removePoolStatus(ctx context.Context, ds *iotv1alpha1.DeviceSet) error {
+	return r.mutatePoolStatus(ctx, ds, func(pools []iotv1alpha1.DeviceSetPoolStatus) []iotv1alpha1.DeviceSetPoolStatus {
+		for i := range pools {
+			if pools[i].NodePool == r.NodePool {
+				return append(pools[:i], pools[i+1:]...)
+			}
+		}
+		return nil
+	})
+}
+
+// mutatePoolStatus updates the pool statuses of DeviceSet with mutate, nothing is updated if mutate returns nil
+func (r *DeviceSetReconciler) mutatePoolStatus(ctx context.Context, ds *iotv1alpha1.DeviceSet, mutate func([]iotv1alpha1.DeviceSetPoolStatus) []iotv1alpha1.DeviceSetPoolStatus) error {
+	latest := ds.DeepCopy()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pools := mutate(latest.Status.Pools)
+		if pools == nil {
+			return nil
+		}
+		latest.Status.Pools = pools
+		err := r.Status().Update(ctx, latest)
+		if apierrors.IsConflict(err) {
+			if getErr := r.Get(ctx, client.ObjectKeyFromObject(ds), latest); getErr != nil {
+				return getErr
+			}
+		}
+		return err
+	})
+}
+
+func isPoolStatusUnchanged(old, new *iotv1alpha1.DeviceSetPoolStatus) bool {
+	return old.ObservedGeneration == new.ObservedGeneration && old.Desired == new.Desired &&
+		old.Provisioned == new.Provisioned && old.Synced == new.Synced && old.Message == new.Message
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	iotcli "github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+const (
+	// eventQueueSize is the number of system events buffered between the watcher and the processor,
+	// events beyond it are dropped and left to the next full resync.
+	eventQueueSize = 1024
+	// rewatchInterval is the waiting time before watching the system events again
+	rewatchInterval = 5 * time.Second
+)
+
+// eventSyncer keeps objects on OpenYurt in sync with the edge platform. It applies the system events
+// of edge platform incrementally and runs a full synchronization periodically as a safety net.
+// If the system events are unavailable, the full synchronization falls back to the syncing period.
+type eventSyncer struct {
+	// type of the synchronized objects, such as device, deviceprofile or deviceservice
+	objectType string
+	eventCli   iotcli.SystemEventInterface
+	// full synchronization period when the system events are unavailable
+	syncPeriod time.Duration
+	// full synchronization period when the system events are watched
+	resyncPeriod time.Duration
+	// syncAll synchronizes all objects between the edge platform and OpenYurt
+	syncAll func(ctx context.Context) error
+	// handleEvent applies a single system event to OpenYurt
+	handleEvent func(ctx context.Context, event iotcli.SystemEvent) error
+
+	watching atomic.Bool
+}
+
+func (es *eventSyncer) run(stop <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	queue := make(chan iotcli.SystemEvent, eventQueueSize)
+	// degraded is notified when events are lost, so the next full synchronization should be brought forward
+	degraded := make(chan struct{}, 1)
+	if es.eventCli != nil {
+		go es.receive(ctx, queue, degraded)
+	}
+
+	deadline := time.Now().Add(es.syncPeriod)
+	timer := time.NewTimer(es.syncPeriod)
+	defer timer.Stop()
+	reschedule := func(d time.Duration) {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		deadline = time.Now().Add(d)
+		timer.Reset(d)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-queue:
+			es.process(ctx, event, degraded)
+		case <-degraded:
+			if time.Until(deadline) > es.syncPeriod {
+				reschedule(es.syncPeriod)
+			}
+		case <-timer.C:
+			klog.V(2).InfoS("Start a round of full synchronization", "Type", es.objectType)
+			fullResyncs.WithLabelValues(es.objectType).Inc()
+			if err := es.syncAll(ctx); err != nil {
+				klog.V(3).ErrorS(err, "could not synchronize objects", "Type", es.objectType)
+				reschedule(es.syncPeriod)
+				continue
+			}
+			if es.watching.Load() {
+				reschedule(es.resyncPeriod)
+			} else {
+				reschedule(es.syncPeriod)
+			}
+		}
+	}
+}
+
+// receive watches the system events and forwards them into queue until ctx is done
+func (es *eventSyncer) receive(ctx context.Context, queue chan<- iotcli.SystemEvent, degraded chan<- struct{}) {
+	notify := func() {
+		select {
+		case degraded <- struct{}{}:
+		default:
+		}
+	}
+
+	for {
+		events, err := es.eventCli.Watch(ctx, iotcli.WatchOptions{Type: es.objectType})
+		if err != nil {
+			klog.V(3).ErrorS(err, "could not watch the system events", "Type", es.objectType)
+		} else {
+			klog.V(2).InfoS("Start watching the system events", "Type", es.objectType)
+			es.watching.Store(true)
+			for event := range events {
+				select {
+				case queue <- event:
+				default:
+					droppedEvents.WithLabelValues(es.objectType, dropReasonOverflow).Inc()
+					notify()
+				}
+			}
+			es.watching.Store(false)
+			klog.V(2).InfoS("Stop watching the system events", "Type", es.objectType)
+			// changes may be missed when the subscription is broken
+			notify()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(rewatchInterval):
+		}
+	}
+}
+
+// process applies a system event, a failed event is left to the next full synchronization
+func (es *eventSyncer) process(ctx context.Context, event iotcli.SystemEvent, degraded chan<- struct{}) {
+	if err := es.handleEvent(ctx, event); err != nil {
+		klog.V(3).ErrorS(err, "could not handle the system event", "Type", event.Type, "Action", event.Action)
+		droppedEvents.WithLabelValues(es.objectType, dropReasonFailed).Inc()
+		select {
+		case degraded <- struct{}{}:
+		default:
+		}
+		return
+	}
+	processedEvents.WithLabelValues(es.objectType, event.Action).Inc()
+	if event.Timestamp > 0 {
+		syncLag.WithLabelValues(es.objectType).Observe(time.Since(time.Unix(0, event.Timestamp)).Seconds())
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "yurt_iot_dock"
+	metricsSubsystem = "syncer"
+
+	// reasons why a system event is dropped by the syncer
+	dropReasonOverflow = "overflow"
+	dropReasonFailed   = "failed"
+)
+
+var (
+	syncLag = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "sync_lag_seconds",
+			Help:      "Duration between an object changed on the edge platform and the change applied to OpenYurt",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 2, 5, 10, 30, 60},
+		},
+		[]string{"type"})
+
+	processedEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "processed_events_total",
+			Help:      "Number of system events applied to OpenYurt",
+		},
+		[]string{"type", "action"})
+
+	droppedEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "dropped_events_total",
+			Help:      "Number of system events which are dropped and left to the next full resync",
+		},
+		[]string{"type", "reason"})
+
+	fullResyncs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "full_resyncs_total",
+			Help:      "Number of full synchronizations between the edge platform and OpenYurt",
+		},
+		[]string{"type"})
+)
+
+var registerMetrics sync.Once
+
+// RegisterMetrics registers the syncer metrics into the controller-runtime registry,
+// so they are exposed by the metrics server of yurt-iot-dock.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		ctrlmetrics.Registry.MustRegister(syncLag, processedEvents, droppedEvents, fullResyncs)
+	})
+}
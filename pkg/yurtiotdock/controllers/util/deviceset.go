@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+)
+
+// IsPoolSelected returns true if the nodepool is in pools or matches the nodePoolSelector
+func IsPoolSelected(pool *appsv1beta2.NodePool, pools []string, nodePoolSelector *metav1.LabelSelector) (bool, error) {
+	for _, name := range pools {
+		if name == pool.Name {
+			return true, nil
+		}
+	}
+	if nodePoolSelector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(nodePoolSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(pool.Labels)), nil
+}
+
+// RenderDeviceSet renders the devices that should be provisioned by the DeviceSet in the nodepool,
+// no device is returned if the nodepool is not selected by the DeviceSet
+func RenderDeviceSet(ds *iotv1alpha1.DeviceSet, pool *appsv1beta2.NodePool) ([]*iotv1alpha1.Device, error) {
+	selected, err := IsPoolSelected(pool, ds.Spec.Pools, ds.Spec.NodePoolSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nodepoolSelector: %v", err)
+	} else if !selected {
+		return nil, nil
+	}
+
+	count := int32(1)
+	if ds.Spec.Count != nil {
+		count = *ds.Spec.Count
+	}
+	namePattern := ds.Spec.NamePattern
+	if len(namePattern) == 0 {
+		namePattern = iotv1alpha1.DefaultDeviceSetNamePattern
+	}
+	protocols := map[string]iotv1alpha1.ProtocolProperties{}
+	mergeProtocols(protocols, ds.Spec.Template.Spec.Protocols)
+	deviceLabels := map[string]string{}
+	for k, v := range ds.Spec.Template.Labels {
+		deviceLabels[k] = v
+	}
+
+	// the tweaks are applied in order, so the latter tweak takes precedence
+	for i, tweak := range ds.Spec.Tweaks {
+		matched, err := IsPoolSelected(pool, tweak.Pools, tweak.NodePoolSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nodepoolSelector of tweaks[%d]: %v", i, err)
+		} else if !matched {
+			continue
+		}
+		if tweak.Count != nil {
+			count = *tweak.Count
+		}
+		if len(tweak.NamePattern) != 0 {
+			namePattern = tweak.NamePattern
+		}
+		mergeProtocols(protocols, tweak.Protocols)
+		for k, v := range tweak.Labels {
+			deviceLabels[k] = v
+		}
+	}
+	if count < 0 {
+		return nil, fmt.Errorf("count %d must not be negative", count)
+	}
+
+	devices := make([]*iotv1alpha1.Device, 0, count)
+	names := make(map[string]struct{}, count)
+	for index := 0; index < int(count); index++ {
+		replacer := strings.NewReplacer("{set}", ds.Name, "{pool}", pool.Name, "{index}", strconv.Itoa(index))
+		device := &iotv1alpha1.Device{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        replacer.Replace(namePattern),
+				Namespace:   ds.Namespace,
+				Labels:      map[string]string{},
+				Annotations: map[string]string{},
+			},
+			Spec: *ds.Spec.Template.Spec.DeepCopy(),
+		}
+		if errs := validation.IsDNS1123Subdomain(device.Name); len(errs) != 0 {
+			return nil, fmt.Errorf("invalid device name %q: %s", device.Name, strings.Join(errs, ", "))
+		} else if _, ok := names[device.Name]; ok {
+			return nil, fmt.Errorf("device name %q is duplicated, namePattern should contain {index}", device.Name)
+		}
+		names[device.Name] = struct{}{}
+
+		for k, v := range deviceLabels {
+			device.Labels[k] = v
+		}
+		device.Labels[iotv1alpha1.DeviceSetLabel] = ds.Name
+		device.Labels[iotv1alpha1.DeviceSetIndexLabel] = strconv.Itoa(index)
+		for k, v := range ds.Spec.Template.Annotations {
+			device.Annotations[k] = v
+		}
+
+		device.Spec.NodePool = pool.Name
+		device.Spec.Description = replacer.Replace(device.Spec.Description)
+		device.Spec.Location = replacer.Replace(device.Spec.Location)
+		device.Spec.Protocols = make(map[string]iotv1alpha1.ProtocolProperties, len(protocols))
+		for protocol, properties := range protocols {
+			rendered := make(iotv1alpha1.ProtocolProperties, len(properties))
+			for k, v := range properties {
+				rendered[k] = replacer.Replace(v)
+			}
+			device.Spec.Protocols[protocol] = rendered
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+func mergeProtocols(dst, src map[string]iotv1alpha1.ProtocolProperties) {
+	for protocol, properties := range src {
+		if dst[protocol] == nil {
+			dst[protocol] = iotv1alpha1.ProtocolProperties{}
+		}
+		for k, v := range properties {
+			dst[protocol][k] = v
+		}
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+)
+
+func newDeviceSet() *iotv1alpha1.DeviceSet {
+	return &iotv1alpha1.DeviceSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "meter", Namespace: "default"},
+		Spec: iotv1alpha1.DeviceSetSpec{
+			NodePoolSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"site": "factory"}},
+			Count:            ptr.To[int32](2),
+			Template: iotv1alpha1.DeviceTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"type": "meter"}},
+				Spec: iotv1alpha1.DeviceSpec{
+					Description: "meter {index} of {pool}",
+					Service:     "modbus",
+					Profile:     "meter-profile",
+					Protocols: map[string]iotv1alpha1.ProtocolProperties{
+						"modbus-tcp": {"Address": "10.0.0.{index}", "Port": "502"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newPool(name string, labels map[string]string) *appsv1beta2.NodePool {
+	return &appsv1beta2.NodePool{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestRenderDeviceSet(t *testing.T) {
+	tests := map[string]struct {
+		ds        func() *iotv1alpha1.DeviceSet
+		pool      *appsv1beta2.NodePool
+		names     []string
+		addresses []string
+		port      string
+		err       bool
+	}{
+		"nodepool is not selected": {
+			ds:   newDeviceSet,
+			pool: newPool("hangzhou", map[string]string{"site": "office"}),
+		},
+		"nodepool is selected by labels": {
+			ds:        newDeviceSet,
+			pool:      newPool("hangzhou", map[string]string{"site": "factory"}),
+			names:     []string{"meter-hangzhou-0", "meter-hangzhou-1"},
+			addresses: []string{"10.0.0.0", "10.0.0.1"},
+			port:      "502",
+		},
+		"nodepool is selected by name": {
+			ds: func() *iotv1alpha1.DeviceSet {
+				ds := newDeviceSet()
+				ds.Spec.NodePoolSelector = nil
+				ds.Spec.Pools = []string{"beijing"}
+				ds.Spec.Count = nil
+				return ds
+			},
+			pool:      newPool("beijing", nil),
+			names:     []string{"meter-beijing-0"},
+			addresses: []string{"10.0.0.0"},
+			port:      "502",
+		},
+		"tweaks are applied in order": {
+			ds: func() *iotv1alpha1.DeviceSet {
+				ds := newDeviceSet()
+				ds.Spec.Tweaks = []iotv1alpha1.DeviceSetTweak{
+					{
+						Pools:       []string{"hangzhou"},
+						Count:       ptr.To[int32](3),
+						NamePattern: "{pool}-meter-{index}",
+						Protocols: map[string]iotv1alpha1.ProtocolProperties{
+							"modbus-tcp": {"Address": "192.168.0.{index}"},
+						},
+					},
+					{
+						NodePoolSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"site": "factory"}},
+						Count:            ptr.To[int32](1),
+					},
+					{
+						Pools: []string{"shanghai"},
+						Count: ptr.To[int32](5),
+					},
+				}
+				return ds
+			},
+			pool:      newPool("hangzhou", map[string]string{"site": "factory"}),
+			names:     []string{"hangzhou-meter-0"},
+			addresses: []string{"192.168.0.0"},
+			port:      "502",
+		},
+		"duplicated names": {
+			ds: func() *iotv1alpha1.DeviceSet {
+				ds := newDeviceSet()
+				ds.Spec.NamePattern = "{set}-{pool}"
+				return ds
+			},
+			pool: newPool("hangzhou", map[string]string{"site": "factory"}),
+			err:  true,
+		},
+		"invalid names": {
+			ds: func() *iotv1alpha1.DeviceSet {
+				ds := newDeviceSet()
+				ds.Spec.NamePattern = "{set}_{index}"
+				return ds
+			},
+			pool: newPool("hangzhou", map[string]string{"site": "factory"}),
+			err:  true,
+		},
+		"invalid selector": {
+			ds: func() *iotv1alpha1.DeviceSet {
+				ds := newDeviceSet()
+				ds.Spec.NodePoolSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "site", Operator: "Unknown"}}}
+				return ds
+			},
+			pool: newPool("hangzhou", map[string]string{"site": "factory"}),
+			err:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ds := tt.ds()
+			devices, err := RenderDeviceSet(ds, tt.pool)
+			if tt.err {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, len(tt.names), len(devices))
+			for i, device := range devices {
+				assert.Equal(t, tt.names[i], device.Name)
+				assert.Equal(t, ds.Namespace, device.Namespace)
+				assert.Equal(t, tt.pool.Name, device.Spec.NodePool)
+				assert.Equal(t, ds.Name, device.Labels[iotv1alpha1.DeviceSetLabel])
+				assert.Equal(t, "meter", device.Labels["type"])
+				assert.Equal(t, tt.addresses[i], device.Spec.Protocols["modbus-tcp"]["Address"])
+				assert.Equal(t, tt.port, device.Spec.Protocols["modbus-tcp"]["Port"])
+			}
+			// the template is not modified by rendering
+			assert.Equal(t, "10.0.0.{index}", ds.Spec.Template.Spec.Protocols["modbus-tcp"]["Address"])
+		})
+	}
+}
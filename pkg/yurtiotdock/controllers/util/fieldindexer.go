@@ -27,8 +27,19 @@ import (
 
 const (
 	IndexerPathForNodepool = "spec.nodePool"
+	// IndexerPathForEdgeName indexes the objects by their node pool and names on edge platform,
+	// so that the object of an event on edge platform can be found without listing all objects
+	IndexerPathForEdgeName = "spec.nodePool.edgeName"
+
+	// EdgeObjectNameLabel records the name of the object on edge platform if it differs from the name on OpenYurt
+	EdgeObjectNameLabel = "yurt-iot-dock/edgex-object.name"
 )
 
+// EdgeNameIndexKey returns the key of IndexerPathForEdgeName for the object in nodePool
+func EdgeNameIndexKey(nodePool, edgeName string) string {
+	return nodePool + "/" + edgeName
+}
+
 var registerOnce sync.Once
 
 func RegisterFieldIndexers(fi client.FieldIndexer) error {
@@ -41,6 +52,12 @@ func RegisterFieldIndexers(fi client.FieldIndexer) error {
 		}); err != nil {
 			return
 		}
+		if err = fi.IndexField(context.TODO(), &iotv1alpha1.Device{}, IndexerPathForEdgeName, func(rawObj client.Object) []string {
+			device := rawObj.(*iotv1alpha1.Device)
+			return []string{EdgeNameIndexKey(device.Spec.NodePool, GetEdgeDeviceName(device, EdgeObjectNameLabel))}
+		}); err != nil {
+			return
+		}
 
 		// register the fieldIndexer for deviceService
 		if err = fi.IndexField(context.TODO(), &iotv1alpha1.DeviceService{}, IndexerPathForNodepool, func(rawObj client.Object) []string {
@@ -49,6 +66,12 @@ func RegisterFieldIndexers(fi client.FieldIndexer) error {
 		}); err != nil {
 			return
 		}
+		if err = fi.IndexField(context.TODO(), &iotv1alpha1.DeviceService{}, IndexerPathForEdgeName, func(rawObj client.Object) []string {
+			deviceService := rawObj.(*iotv1alpha1.DeviceService)
+			return []string{EdgeNameIndexKey(deviceService.Spec.NodePool, GetEdgeDeviceServiceName(deviceService, EdgeObjectNameLabel))}
+		}); err != nil {
+			return
+		}
 
 		// register the fieldIndexer for deviceProfile
 		if err = fi.IndexField(context.TODO(), &iotv1alpha1.DeviceProfile{}, IndexerPathForNodepool, func(rawObj client.Object) []string {
@@ -57,6 +80,12 @@ func RegisterFieldIndexers(fi client.FieldIndexer) error {
 		}); err != nil {
 			return
 		}
+		if err = fi.IndexField(context.TODO(), &iotv1alpha1.DeviceProfile{}, IndexerPathForEdgeName, func(rawObj client.Object) []string {
+			profile := rawObj.(*iotv1alpha1.DeviceProfile)
+			return []string{EdgeNameIndexKey(profile.Spec.NodePool, GetEdgeDeviceProfileName(profile, EdgeObjectNameLabel))}
+		}); err != nil {
+			return
+		}
 	})
 	return err
 }
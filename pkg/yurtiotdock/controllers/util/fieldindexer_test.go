@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+)
+
+type fakeFieldIndexer map[string]client.IndexerFunc
+
+func (fi fakeFieldIndexer) IndexField(_ context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	fi[field+"/"+typeName(obj)] = extractValue
+	return nil
+}
+
+func typeName(obj client.Object) string {
+	switch obj.(type) {
+	case *iotv1alpha1.Device:
+		return "Device"
+	case *iotv1alpha1.DeviceService:
+		return "DeviceService"
+	case *iotv1alpha1.DeviceProfile:
+		return "DeviceProfile"
+	}
+	return ""
+}
+
+func TestRegisterFieldIndexers(t *testing.T) {
+	fi := fakeFieldIndexer{}
+	assert.NoError(t, RegisterFieldIndexers(fi))
+
+	objects := map[string]client.Object{
+		"Device": &iotv1alpha1.Device{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool1-sensor", Labels: map[string]string{EdgeObjectNameLabel: "sensor"}},
+			Spec:       iotv1alpha1.DeviceSpec{NodePool: "pool1"},
+		},
+		"DeviceService": &iotv1alpha1.DeviceService{
+			ObjectMeta: metav1.ObjectMeta{Name: "modbus"},
+			Spec:       iotv1alpha1.DeviceServiceSpec{NodePool: "pool1"},
+		},
+		"DeviceProfile": &iotv1alpha1.DeviceProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool1-camera", Labels: map[string]string{EdgeObjectNameLabel: "camera"}},
+			Spec:       iotv1alpha1.DeviceProfileSpec{NodePool: "pool1"},
+		},
+	}
+	expected := map[string]string{
+		"Device":        "pool1/sensor",
+		"DeviceService": "pool1/modbus",
+		"DeviceProfile": "pool1/camera",
+	}
+	for kind, obj := range objects {
+		extract, ok := fi[IndexerPathForEdgeName+"/"+kind]
+		if !assert.True(t, ok, "edge name of %s is not indexed", kind) {
+			continue
+		}
+		assert.Equal(t, []string{expected[kind]}, extract(obj))
+		assert.Equal(t, []string{"pool1"}, fi[IndexerPathForNodepool+"/"+kind](obj))
+	}
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package controllers
 
+import "github.com/openyurtio/openyurt/pkg/yurtiotdock/controllers/util"
+
 const (
-	EdgeXObjectName = "yurt-iot-dock/edgex-object.name"
+	EdgeXObjectName = util.EdgeObjectNameLabel
 )
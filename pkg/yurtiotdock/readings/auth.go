@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readings
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/apis/apiserver"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/authenticatorfactory"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	authenticationv1 "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	authorizationv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// webhookRetryBackoff is the backoff of TokenReview and SubjectAccessReview requests
+var webhookRetryBackoff = &wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   1.5,
+	Jitter:   0.2,
+	Steps:    5,
+}
+
+// NewDelegatingAuth returns the authenticator and authorizer which delegate to kube-apiserver, the bearer tokens
+// of requests are authenticated by TokenReview and the requests are authorized by SubjectAccessReview.
+func NewDelegatingAuth(cfg *rest.Config) (authenticator.Request, authorizer.Authorizer, error) {
+	authenticationClient, err := authenticationv1.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	authorizationClient, err := authorizationv1.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	authn, _, err := authenticatorfactory.DelegatingAuthenticatorConfig{
+		Anonymous:                &apiserver.AnonymousAuthConfig{Enabled: false},
+		CacheTTL:                 time.Minute,
+		TokenAccessReviewClient:  authenticationClient,
+		TokenAccessReviewTimeout: 10 * time.Second,
+		WebhookRetryBackoff:      webhookRetryBackoff,
+	}.New()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create authenticator, %w", err)
+	}
+
+	authz, err := authorizerfactory.DelegatingAuthorizerConfig{
+		SubjectAccessReviewClient: authorizationClient,
+		AllowCacheTTL:             5 * time.Minute,
+		DenyCacheTTL:              30 * time.Second,
+		WebhookRetryBackoff:       webhookRetryBackoff,
+	}.New()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create authorizer, %w", err)
+	}
+	return authn, authz, nil
+}
+
+// withAuth authenticates and authorizes the requests before they are served by handler. The readings of a device
+// are authorized as the subresource devices/readings, the verb is watch for watch requests and get otherwise.
+func withAuth(handler http.Handler, authn authenticator.Request, authz authorizer.Authorizer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		resp, ok, err := authn.AuthenticateRequest(req)
+		if err != nil || !ok {
+			if err != nil {
+				klog.V(4).ErrorS(err, "could not authenticate the request of device readings")
+			}
+			writeStatus(w, apierrors.NewUnauthorized("Unauthorized"))
+			return
+		}
+
+		namespace, name, ok := parsePath(req.URL.Path)
+		if !ok {
+			writeStatus(w, apierrors.NewNotFound(deviceResource, req.URL.Path))
+			return
+		}
+		verb := "get"
+		if watch, _ := strconv.ParseBool(req.URL.Query().Get("watch")); watch {
+			verb = "watch"
+		}
+		attrs := authorizer.AttributesRecord{
+			User:            resp.User,
+			Verb:            verb,
+			Namespace:       namespace,
+			APIGroup:        iotv1alpha1.GroupVersion.Group,
+			APIVersion:      iotv1alpha1.GroupVersion.Version,
+			Resource:        deviceResource.Resource,
+			Subresource:     "readings",
+			Name:            name,
+			ResourceRequest: true,
+			Path:            req.URL.Path,
+		}
+		decision, reason, err := authz.Authorize(req.Context(), attrs)
+		if err != nil {
+			klog.V(4).ErrorS(err, "could not authorize the request of device readings", "user", resp.User.GetName())
+		}
+		if decision != authorizer.DecisionAllow {
+			writeStatus(w, apierrors.NewForbidden(deviceResource, name,
+				fmt.Errorf("user %q cannot %s devices/readings in namespace %q: %s", resp.User.GetName(), verb, namespace, reason)))
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
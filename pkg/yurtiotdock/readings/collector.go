@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readings
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+// rewatchInterval is the waiting time before watching the device readings again
+const rewatchInterval = 5 * time.Second
+
+// Collector feeds the readings reported on edge platform into the store
+type Collector struct {
+	readingCli clients.DeviceReadingInterface
+	store      *Store
+}
+
+func NewCollector(readingCli clients.DeviceReadingInterface, store *Store) *Collector {
+	return &Collector{
+		readingCli: readingCli,
+		store:      store,
+	}
+}
+
+// Start watches the device readings until ctx is done, it implements the manager.Runnable interface
+func (c *Collector) Start(ctx context.Context) error {
+	klog.V(1).Info("[DeviceReading] Starting the collector...")
+	for {
+		readings, err := c.readingCli.Watch(ctx)
+		if err != nil {
+			klog.V(3).ErrorS(err, "could not watch the device readings")
+		} else {
+			for r := range readings {
+				c.store.Add(r)
+			}
+			klog.V(3).Info("[DeviceReading] The watch of device readings is closed")
+		}
+
+		select {
+		case <-ctx.Done():
+			klog.V(1).Info("[DeviceReading] Stopping the collector")
+			return c.store.Close()
+		case <-time.After(rewatchInterval):
+		}
+	}
+}
+
+// NeedLeaderElection implements the manager.LeaderElectionRunnable interface, readings are
+// collected by every replica so that they can be served by every replica.
+func (c *Collector) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readings
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/controllers"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/controllers/util"
+)
+
+var deviceResource = schema.GroupResource{Group: iotv1alpha1.GroupVersion.Group, Resource: "devices"}
+
+// Server serves the readings of devices in the nodepool by the Kubernetes-style API:
+//
+//	GET /apis/iot.openyurt.io/v1alpha1/namespaces/{namespace}/devices/{device}/readings
+//
+// The query parameters are property, limit, watch, resourceVersion and timeoutSeconds.
+// The API is served by https, and the requests are authenticated and authorized by kube-apiserver,
+// so the apps in the cluster can read the readings with their service account tokens.
+type Server struct {
+	addr      string
+	serving   SecureServing
+	reader    client.Reader
+	store     *Store
+	namespace string
+	nodePool  string
+}
+
+// SecureServing is the https serving and the authentication and authorization of the server
+type SecureServing struct {
+	// CertFile and KeyFile are the serving certificate, a self-signed certificate is generated if they are empty
+	CertFile      string
+	KeyFile       string
+	Authenticator authenticator.Request
+	Authorizer    authorizer.Authorizer
+}
+
+func NewServer(addr string, serving SecureServing, reader client.Reader, store *Store, namespace, nodePool string) *Server {
+	return &Server{
+		addr:      addr,
+		serving:   serving,
+		reader:    reader,
+		store:     store,
+		namespace: namespace,
+		nodePool:  nodePool,
+	}
+}
+
+// Start serves the API until ctx is done, it implements the manager.Runnable interface
+func (s *Server) Start(ctx context.Context) error {
+	if s.serving.Authenticator == nil || s.serving.Authorizer == nil {
+		return errors.New("the authenticator and authorizer of device readings are not set")
+	}
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:              s.addr,
+		Handler:           withAuth(s, s.serving.Authenticator, s.serving.Authorizer),
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	klog.V(1).InfoS("[DeviceReading] Serving the device readings", "addr", s.addr)
+	if err := server.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// tlsConfig loads the serving certificate, or generates a self-signed one for the host name of pod
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	var cert tls.Certificate
+	var err error
+	if len(s.serving.CertFile) != 0 || len(s.serving.KeyFile) != 0 {
+		cert, err = tls.LoadX509KeyPair(s.serving.CertFile, s.serving.KeyFile)
+	} else {
+		host, _ := os.Hostname()
+		var certPEM, keyPEM []byte
+		if certPEM, keyPEM, err = certutil.GenerateSelfSignedCertKey(host, nil, nil); err == nil {
+			cert, err = tls.X509KeyPair(certPEM, keyPEM)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not load the serving certificate of device readings, %w", err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+}
+
+// NeedLeaderElection implements the manager.LeaderElectionRunnable interface
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// parsePath returns the namespace and name of device from the path of readings API
+func parsePath(path string) (string, string, bool) {
+	// apis/iot.openyurt.io/v1alpha1/namespaces/{namespace}/devices/{device}/readings
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 8 || parts[0] != "apis" || strings.Join(parts[1:3], "/") != GroupVersion ||
+		parts[3] != "namespaces" || parts[5] != "devices" || parts[7] != "readings" {
+		return "", "", false
+	}
+	return parts[4], parts[6], true
+}
+
+// ServeHTTP serves the readings API, the requests should have been authenticated and authorized
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	namespace, name, ok := parsePath(req.URL.Path)
+	if !ok {
+		writeStatus(w, apierrors.NewNotFound(schema.GroupResource{}, req.URL.Path))
+		return
+	}
+	if req.Method != http.MethodGet {
+		writeStatus(w, apierrors.NewMethodNotSupported(deviceResource, req.Method))
+		return
+	}
+
+	edgeName, err := s.resolveDevice(req.Context(), namespace, name)
+	if err != nil {
+		writeStatus(w, err)
+		return
+	}
+
+	query := req.URL.Query()
+	property := query.Get("property")
+	if watch, _ := strconv.ParseBool(query.Get("watch")); watch {
+		s.serveWatch(w, req, namespace, name, edgeName, property)
+		return
+	}
+
+	readings, resourceVersion := s.store.List(edgeName, property)
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit < len(readings) {
+		// the latest readings are returned
+		readings = readings[len(readings)-limit:]
+	}
+	list := DeviceReadingList{
+		TypeMeta: metav1.TypeMeta{Kind: KindDeviceReadingList, APIVersion: GroupVersion},
+		ListMeta: metav1.ListMeta{ResourceVersion: strconv.FormatUint(resourceVersion, 10)},
+		Items:    make([]DeviceReading, 0, len(readings)),
+	}
+	for i := range readings {
+		list.Items = append(list.Items, toDeviceReading(readings[i], namespace, name))
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// resolveDevice returns the name on edge platform of the device which is synchronized by this yurt-iot-dock
+func (s *Server) resolveDevice(ctx context.Context, namespace, name string) (string, error) {
+	notFound := apierrors.NewNotFound(deviceResource, name)
+	if namespace != s.namespace {
+		return "", notFound
+	}
+	var device iotv1alpha1.Device
+	if err := s.reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &device); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", notFound
+		}
+		return "", apierrors.NewInternalError(err)
+	}
+	if device.Spec.NodePool != s.nodePool {
+		return "", notFound
+	}
+	return util.GetEdgeDeviceName(&device, controllers.EdgeXObjectName), nil
+}
+
+func (s *Server) serveWatch(w http.ResponseWriter, req *http.Request, namespace, name, edgeName, property string) {
+	query := req.URL.Query()
+	var resourceVersion uint64
+	if rv := query.Get("resourceVersion"); len(rv) != 0 {
+		var err error
+		if resourceVersion, err = strconv.ParseUint(rv, 10, 64); err != nil {
+			writeStatus(w, apierrors.NewBadRequest(fmt.Sprintf("invalid resourceVersion %q", rv)))
+			return
+		}
+	}
+	ctx := req.Context()
+	if timeout, err := strconv.Atoi(query.Get("timeoutSeconds")); err == nil && timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+
+	watcher, err := s.store.Watch(edgeName, property, resourceVersion)
+	if errors.Is(err, ErrResourceVersionTooOld) {
+		writeStatus(w, apierrors.NewResourceExpired(fmt.Sprintf("too old resource version: %d", resourceVersion)))
+		return
+	} else if err != nil {
+		writeStatus(w, apierrors.NewInternalError(err))
+		return
+	}
+	defer watcher.Stop()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeStatus(w, apierrors.NewInternalError(errors.New("streaming is not supported")))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Transfer-Encoding", "chunked")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case r, ok := <-watcher.ResultChan():
+			if !ok {
+				// the watcher can not keep up with the readings, the client should list and watch again
+				status := apierrors.NewResourceExpired("the watch of device readings is terminated because it's too slow").ErrStatus
+				encoder.Encode(WatchEvent{Type: "ERROR", Object: status})
+				flusher.Flush()
+				return
+			}
+			if err := encoder.Encode(WatchEvent{Type: "ADDED", Object: toDeviceReading(r, namespace, name)}); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func toDeviceReading(r Reading, namespace, device string) DeviceReading {
+	return DeviceReading{
+		TypeMeta: metav1.TypeMeta{Kind: KindDeviceReading, APIVersion: GroupVersion},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s.%d", device, r.ResourceVersion),
+			Namespace:       namespace,
+			ResourceVersion: strconv.FormatUint(r.ResourceVersion, 10),
+		},
+		Device:    device,
+		Property:  r.PropertyName,
+		Value:     r.Value,
+		ValueType: r.ValueType,
+		Timestamp: metav1.NewMicroTime(time.Unix(0, r.Timestamp)),
+	}
+}
+
+func writeStatus(w http.ResponseWriter, err error) {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		status = apierrors.NewInternalError(err)
+	}
+	s := status.Status()
+	s.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	writeJSON(w, int(s.Code), s)
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		klog.V(4).ErrorS(err, "could not write the response")
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readings
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/controllers"
+)
+
+const readingsPath = "/apis/iot.openyurt.io/v1alpha1/namespaces/default/devices/"
+
+func newTestServer(t *testing.T) (*Server, *Store) {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, iotv1alpha1.AddToScheme(scheme))
+	devices := []*iotv1alpha1.Device{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hangzhou-sensor-1",
+				Namespace: "default",
+				Labels:    map[string]string{controllers.EdgeXObjectName: "Sensor-1"},
+			},
+			Spec: iotv1alpha1.DeviceSpec{NodePool: "hangzhou"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "beijing-sensor-1", Namespace: "default"},
+			Spec:       iotv1alpha1.DeviceSpec{NodePool: "beijing"},
+		},
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for i := range devices {
+		builder = builder.WithObjects(devices[i])
+	}
+	store, err := NewStore(Options{MaxReadingsPerProperty: 2})
+	assert.Nil(t, err)
+	return NewServer(":0", SecureServing{}, builder.Build(), store, "default", "hangzhou"), store
+}
+
+func Test_ServeList(t *testing.T) {
+	server, store := newTestServer(t)
+	store.Add(newReading("Sensor-1", "temperature", "1"))
+	store.Add(newReading("Sensor-1", "humidity", "2"))
+	store.Add(newReading("Sensor-1", "temperature", "3"))
+	store.Add(newReading("Sensor-2", "temperature", "4"))
+
+	testcases := map[string]struct {
+		method   string
+		path     string
+		code     int
+		expected []string
+	}{
+		"list readings of device": {
+			path:     readingsPath + "hangzhou-sensor-1/readings",
+			code:     http.StatusOK,
+			expected: []string{"1", "2", "3"},
+		},
+		"list readings of property": {
+			path:     readingsPath + "hangzhou-sensor-1/readings?property=temperature",
+			code:     http.StatusOK,
+			expected: []string{"1", "3"},
+		},
+		"list latest readings": {
+			path:     readingsPath + "hangzhou-sensor-1/readings?limit=1",
+			code:     http.StatusOK,
+			expected: []string{"3"},
+		},
+		"device not found": {
+			path: readingsPath + "hangzhou-sensor-2/readings",
+			code: http.StatusNotFound,
+		},
+		"device in other nodepool": {
+			path: readingsPath + "beijing-sensor-1/readings",
+			code: http.StatusNotFound,
+		},
+		"device in other namespace": {
+			path: "/apis/iot.openyurt.io/v1alpha1/namespaces/kube-system/devices/hangzhou-sensor-1/readings",
+			code: http.StatusNotFound,
+		},
+		"unknown path": {
+			path: "/apis/iot.openyurt.io/v1alpha1/namespaces/default/devices",
+			code: http.StatusNotFound,
+		},
+		"method not supported": {
+			method: http.MethodPost,
+			path:   readingsPath + "hangzhou-sensor-1/readings",
+			code:   http.StatusMethodNotAllowed,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			method := tc.method
+			if len(method) == 0 {
+				method = http.MethodGet
+			}
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, httptest.NewRequest(method, tc.path, nil))
+			assert.Equal(t, tc.code, rec.Code)
+			if tc.code != http.StatusOK {
+				var status metav1.Status
+				assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &status))
+				assert.Equal(t, int32(tc.code), status.Code)
+				return
+			}
+
+			var list DeviceReadingList
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &list))
+			assert.Equal(t, KindDeviceReadingList, list.Kind)
+			assert.Equal(t, "4", list.ResourceVersion)
+			result := make([]string, 0, len(list.Items))
+			for _, item := range list.Items {
+				assert.Equal(t, "hangzhou-sensor-1", item.Device)
+				result = append(result, item.Value)
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_ServeWatch(t *testing.T) {
+	server, store := newTestServer(t)
+	store.Add(newReading("Sensor-1", "temperature", "1"))
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + readingsPath + "hangzhou-sensor-1/readings?watch=true&resourceVersion=1&timeoutSeconds=10")
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Eventually(t, func() bool {
+		store.lock.RLock()
+		defer store.lock.RUnlock()
+		return len(store.watchers) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	store.Add(newReading("Sensor-1", "temperature", "2"))
+
+	scanner := bufio.NewScanner(resp.Body)
+	assert.True(t, scanner.Scan())
+	var event struct {
+		Type   string        `json:"type"`
+		Object DeviceReading `json:"object"`
+	}
+	assert.Nil(t, json.Unmarshal(scanner.Bytes(), &event))
+	assert.Equal(t, "ADDED", event.Type)
+	assert.Equal(t, "2", event.Object.Value)
+	assert.Equal(t, strconv.Itoa(2), event.Object.ResourceVersion)
+
+	// the readings after the resource version have been evicted
+	store.Add(newReading("Sensor-1", "temperature", "3"))
+	store.Add(newReading("Sensor-1", "temperature", "4"))
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, readingsPath+"hangzhou-sensor-1/readings?watch=1&resourceVersion=1", nil))
+	assert.Equal(t, http.StatusGone, rec.Code)
+}
+
+func Test_ServeWithAuth(t *testing.T) {
+	server, _ := newTestServer(t)
+	authn := authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+		if req.Header.Get("Authorization") != "Bearer token" {
+			return nil, false, nil
+		}
+		return &authenticator.Response{User: &user.DefaultInfo{Name: "reader"}}, true, nil
+	})
+	var attrs authorizer.Attributes
+	authz := authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		attrs = a
+		if a.GetName() == "hangzhou-sensor-1" {
+			return authorizer.DecisionAllow, "", nil
+		}
+		return authorizer.DecisionNoOpinion, "", nil
+	})
+	handler := withAuth(server, authn, authz)
+
+	tests := []struct {
+		name     string
+		path     string
+		token    string
+		wantCode int
+		wantVerb string
+	}{
+		{name: "unauthenticated", path: readingsPath + "hangzhou-sensor-1/readings", wantCode: http.StatusUnauthorized},
+		{name: "list readings", path: readingsPath + "hangzhou-sensor-1/readings", token: "token", wantCode: http.StatusOK, wantVerb: "get"},
+		{name: "watch readings", path: readingsPath + "hangzhou-sensor-1/readings?watch=1&timeoutSeconds=1", token: "token", wantCode: http.StatusOK, wantVerb: "watch"},
+		{name: "forbidden", path: readingsPath + "beijing-sensor-1/readings", token: "token", wantCode: http.StatusForbidden, wantVerb: "get"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			attrs = nil
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if len(tc.token) != 0 {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tc.wantCode, rec.Code)
+			if len(tc.wantVerb) == 0 {
+				assert.Nil(t, attrs)
+				return
+			}
+			assert.Equal(t, tc.wantVerb, attrs.GetVerb())
+			assert.Equal(t, "devices", attrs.GetResource())
+			assert.Equal(t, "readings", attrs.GetSubresource())
+			assert.Equal(t, "default", attrs.GetNamespace())
+		})
+	}
+}
@@ -0,0 +1,408 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readings
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+const (
+	journalFileName = "readings.log"
+	// watcherBufferSize is the number of readings buffered for a watcher, the watcher is
+	// terminated if it can not keep up with the new readings
+	watcherBufferSize = 100
+)
+
+// ErrResourceVersionTooOld means the readings after the requested resource version have been evicted
+var ErrResourceVersionTooOld = errors.New("too old resource version")
+
+// Options are the retention limits of the store
+type Options struct {
+	// MaxReadingsPerProperty is the number of latest readings kept for each device property
+	MaxReadingsPerProperty int
+	// Retention is the duration the readings are kept, readings are kept until they are
+	// evicted by MaxReadingsPerProperty if it's zero
+	Retention time.Duration
+	// DataDir is the directory where the readings are persisted, readings are kept
+	// in memory only if it's empty
+	DataDir string
+	// MaxDiskBytes is the maximum size of the journal file in DataDir
+	MaxDiskBytes int64
+}
+
+// Reading is a device reading kept by the store
+type Reading struct {
+	// ResourceVersion is increased for every reading added into the store
+	ResourceVersion uint64 `json:"resourceVersion"`
+	DeviceName      string `json:"deviceName"`
+	PropertyName    string `json:"propertyName"`
+	Value           string `json:"value"`
+	ValueType       string `json:"valueType,omitempty"`
+	Timestamp       int64  `json:"timestamp"`
+}
+
+type seriesKey struct {
+	device   string
+	property string
+}
+
+// series holds the latest readings of a device property in the order of resource version
+type series struct {
+	readings []Reading
+	// evicted is the resource version of the latest reading evicted from the series
+	evicted uint64
+}
+
+// Store keeps the recent readings of device properties in memory and persists them into
+// a journal file which is compacted when it exceeds the size limit
+type Store struct {
+	opts Options
+
+	lock            sync.RWMutex
+	resourceVersion uint64
+	series          map[seriesKey]*series
+	watchers        map[*Watcher]struct{}
+
+	journal     *os.File
+	journalSize int64
+}
+
+// NewStore creates a store and restores the readings persisted in the data dir
+func NewStore(opts Options) (*Store, error) {
+	if opts.MaxReadingsPerProperty <= 0 {
+		return nil, errors.New("max readings per property should be positive")
+	}
+	s := &Store{
+		opts:     opts,
+		series:   make(map[seriesKey]*series),
+		watchers: make(map[*Watcher]struct{}),
+	}
+	if len(opts.DataDir) == 0 {
+		return s, nil
+	}
+
+	if err := os.MkdirAll(opts.DataDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := s.restore(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Add adds the reading into the store and sends it to the watchers
+func (s *Store) Add(dr clients.DeviceReading) Reading {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.resourceVersion++
+	r := Reading{
+		ResourceVersion: s.resourceVersion,
+		DeviceName:      dr.DeviceName,
+		PropertyName:    dr.PropertyName,
+		Value:           dr.Value,
+		ValueType:       dr.ValueType,
+		Timestamp:       dr.Timestamp,
+	}
+	if r.Timestamp == 0 {
+		r.Timestamp = time.Now().UnixNano()
+	}
+	s.insert(r)
+	if s.journal != nil {
+		if err := s.appendJournal(r); err != nil {
+			klog.ErrorS(err, "could not persist the device reading", "Device", r.DeviceName, "Property", r.PropertyName)
+		}
+	}
+
+	for w := range s.watchers {
+		if w.matches(r) && !w.send(r) {
+			klog.V(4).InfoS("terminate the watcher of device readings which can not keep up", "Device", w.device)
+			delete(s.watchers, w)
+		}
+	}
+	return r
+}
+
+// List returns the unexpired readings of the device in the order of resource version, readings of
+// all properties are returned if property is empty. The current resource version of store is returned too.
+func (s *Store) List(device, property string) ([]Reading, uint64) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.collect(device, property, 0), s.resourceVersion
+}
+
+// Watch starts watching the readings of the device which are added after resourceVersion, the unexpired
+// readings are sent first if resourceVersion is zero. ErrResourceVersionTooOld is returned if the readings
+// after resourceVersion have been evicted.
+func (s *Store) Watch(device, property string, resourceVersion uint64) (*Watcher, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if resourceVersion != 0 {
+		for key, ser := range s.series {
+			if key.device == device && (len(property) == 0 || key.property == property) && resourceVersion < ser.evicted {
+				return nil, ErrResourceVersionTooOld
+			}
+		}
+	}
+
+	initial := s.collect(device, property, resourceVersion)
+	w := &Watcher{
+		store:    s,
+		device:   device,
+		property: property,
+		result:   make(chan Reading, watcherBufferSize+len(initial)),
+	}
+	for i := range initial {
+		w.result <- initial[i]
+	}
+	s.watchers[w] = struct{}{}
+	return w, nil
+}
+
+// Close stops all watchers and closes the journal file
+func (s *Store) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for w := range s.watchers {
+		w.close()
+		delete(s.watchers, w)
+	}
+	if s.journal == nil {
+		return nil
+	}
+	err := s.journal.Close()
+	s.journal = nil
+	return err
+}
+
+// collect returns the unexpired readings of the device after resourceVersion, the caller must hold the lock
+func (s *Store) collect(device, property string, resourceVersion uint64) []Reading {
+	expiration := s.expiration()
+	readings := make([]Reading, 0)
+	for key, ser := range s.series {
+		if key.device != device || (len(property) != 0 && key.property != property) {
+			continue
+		}
+		for _, r := range ser.readings {
+			if r.ResourceVersion > resourceVersion && r.Timestamp >= expiration {
+				readings = append(readings, r)
+			}
+		}
+	}
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].ResourceVersion < readings[j].ResourceVersion
+	})
+	return readings
+}
+
+// insert adds the reading into its series and evicts the readings beyond the retention limits
+func (s *Store) insert(r Reading) {
+	key := seriesKey{device: r.DeviceName, property: r.PropertyName}
+	ser, ok := s.series[key]
+	if !ok {
+		ser = &series{}
+		s.series[key] = ser
+	}
+	ser.readings = append(ser.readings, r)
+
+	// evict the readings beyond the limit of count, then the expired ones
+	evict := 0
+	if n := len(ser.readings) - s.opts.MaxReadingsPerProperty; n > 0 {
+		evict = n
+	}
+	expiration := s.expiration()
+	for evict < len(ser.readings) && ser.readings[evict].Timestamp < expiration {
+		evict++
+	}
+	if evict > 0 {
+		ser.evicted = ser.readings[evict-1].ResourceVersion
+		// copy the kept readings so the evicted ones can be released
+		ser.readings = append([]Reading(nil), ser.readings[evict:]...)
+	}
+}
+
+// expiration returns the timestamp before which the readings are expired
+func (s *Store) expiration() int64 {
+	if s.opts.Retention <= 0 {
+		return 0
+	}
+	return time.Now().Add(-s.opts.Retention).UnixNano()
+}
+
+func (s *Store) journalPath() string {
+	return filepath.Join(s.opts.DataDir, journalFileName)
+}
+
+// restore loads the readings from the journal file, the broken records are skipped
+func (s *Store) restore() error {
+	f, err := os.Open(s.journalPath())
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Reading
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			klog.V(4).ErrorS(err, "skip the broken record of device readings")
+			continue
+		}
+		if r.ResourceVersion <= s.resourceVersion {
+			continue
+		}
+		s.resourceVersion = r.ResourceVersion
+		s.insert(r)
+	}
+	return scanner.Err()
+}
+
+func (s *Store) appendJournal(r Reading) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	n, err := s.journal.Write(append(data, '\n'))
+	s.journalSize += int64(n)
+	if err != nil {
+		return err
+	}
+	if s.opts.MaxDiskBytes > 0 && s.journalSize > s.opts.MaxDiskBytes {
+		return s.compact()
+	}
+	return nil
+}
+
+// compact rewrites the journal file with the readings in memory. At most half of MaxDiskBytes is
+// used by the compacted file, the oldest readings are left out if they don't fit in.
+func (s *Store) compact() error {
+	var all []Reading
+	for key := range s.series {
+		all = append(all, s.collect(key.device, key.property, 0)...)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].ResourceVersion < all[j].ResourceVersion
+	})
+
+	lines := make([][]byte, 0, len(all))
+	var size int64
+	for i := len(all) - 1; i >= 0; i-- {
+		data, err := json.Marshal(all[i])
+		if err != nil {
+			return err
+		}
+		if s.opts.MaxDiskBytes > 0 && size+int64(len(data))+1 > s.opts.MaxDiskBytes/2 {
+			break
+		}
+		size += int64(len(data)) + 1
+		lines = append(lines, data)
+	}
+
+	tmp := s.journalPath() + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for i := len(lines) - 1; i >= 0; i-- {
+		w.Write(lines[i])
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if s.journal != nil {
+		s.journal.Close()
+		s.journal = nil
+	}
+	if err := os.Rename(tmp, s.journalPath()); err != nil {
+		return err
+	}
+	s.journal, err = os.OpenFile(s.journalPath(), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	s.journalSize = size
+	return nil
+}
+
+// Watcher receives the readings of a device added into the store
+type Watcher struct {
+	store    *Store
+	device   string
+	property string
+	result   chan Reading
+	closed   bool
+}
+
+// ResultChan returns the channel which delivers the readings, it's closed when the watcher is stopped
+// or it can not keep up with the new readings
+func (w *Watcher) ResultChan() <-chan Reading {
+	return w.result
+}
+
+// Stop stops the watcher and closes the result channel
+func (w *Watcher) Stop() {
+	w.store.lock.Lock()
+	defer w.store.lock.Unlock()
+	delete(w.store.watchers, w)
+	w.close()
+}
+
+func (w *Watcher) matches(r Reading) bool {
+	return r.DeviceName == w.device && (len(w.property) == 0 || r.PropertyName == w.property)
+}
+
+// send sends the reading without blocking, the watcher is closed and false is returned if its buffer is full.
+// The caller must hold the lock of store.
+func (w *Watcher) send(r Reading) bool {
+	select {
+	case w.result <- r:
+		return true
+	default:
+		w.close()
+		return false
+	}
+}
+
+func (w *Watcher) close() {
+	if !w.closed {
+		w.closed = true
+		close(w.result)
+	}
+}
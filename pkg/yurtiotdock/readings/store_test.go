@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readings
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openyurtio/openyurt/pkg/yurtiotdock/clients"
+)
+
+func newReading(device, property, value string) clients.DeviceReading {
+	return clients.DeviceReading{
+		DeviceName:   device,
+		PropertyName: property,
+		Value:        value,
+		ValueType:    "Int32",
+		Timestamp:    time.Now().UnixNano(),
+	}
+}
+
+func values(readings []Reading) []string {
+	result := make([]string, 0, len(readings))
+	for _, r := range readings {
+		result = append(result, r.Value)
+	}
+	return result
+}
+
+func Test_StoreRetention(t *testing.T) {
+	testcases := map[string]struct {
+		opts     Options
+		readings []clients.DeviceReading
+		property string
+		expected []string
+	}{
+		"readings of all properties": {
+			opts: Options{MaxReadingsPerProperty: 10},
+			readings: []clients.DeviceReading{
+				newReading("sensor-1", "temperature", "1"),
+				newReading("sensor-1", "humidity", "2"),
+				newReading("sensor-2", "temperature", "3"),
+				newReading("sensor-1", "temperature", "4"),
+			},
+			expected: []string{"1", "2", "4"},
+		},
+		"readings of a property": {
+			opts: Options{MaxReadingsPerProperty: 10},
+			readings: []clients.DeviceReading{
+				newReading("sensor-1", "temperature", "1"),
+				newReading("sensor-1", "humidity", "2"),
+				newReading("sensor-1", "temperature", "3"),
+			},
+			property: "temperature",
+			expected: []string{"1", "3"},
+		},
+		"readings beyond the limit of count are evicted": {
+			opts: Options{MaxReadingsPerProperty: 2},
+			readings: []clients.DeviceReading{
+				newReading("sensor-1", "temperature", "1"),
+				newReading("sensor-1", "temperature", "2"),
+				newReading("sensor-1", "temperature", "3"),
+				newReading("sensor-1", "humidity", "4"),
+			},
+			expected: []string{"2", "3", "4"},
+		},
+		"expired readings are evicted": {
+			opts: Options{MaxReadingsPerProperty: 10, Retention: time.Minute},
+			readings: []clients.DeviceReading{
+				{DeviceName: "sensor-1", PropertyName: "temperature", Value: "1", Timestamp: time.Now().Add(-time.Hour).UnixNano()},
+				newReading("sensor-1", "temperature", "2"),
+			},
+			expected: []string{"2"},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			store, err := NewStore(tc.opts)
+			assert.Nil(t, err)
+			for _, r := range tc.readings {
+				store.Add(r)
+			}
+			readings, resourceVersion := store.List("sensor-1", tc.property)
+			assert.Equal(t, tc.expected, values(readings))
+			assert.Equal(t, uint64(len(tc.readings)), resourceVersion)
+		})
+	}
+}
+
+func Test_StoreWatch(t *testing.T) {
+	store, err := NewStore(Options{MaxReadingsPerProperty: 2})
+	assert.Nil(t, err)
+	store.Add(newReading("sensor-1", "temperature", "1"))
+	_, resourceVersion := store.List("sensor-1", "")
+
+	// readings after the resource version are received
+	w, err := store.Watch("sensor-1", "temperature", resourceVersion)
+	assert.Nil(t, err)
+	store.Add(newReading("sensor-2", "temperature", "2"))
+	store.Add(newReading("sensor-1", "humidity", "3"))
+	store.Add(newReading("sensor-1", "temperature", "4"))
+	r := <-w.ResultChan()
+	assert.Equal(t, "4", r.Value)
+	w.Stop()
+	_, ok := <-w.ResultChan()
+	assert.False(t, ok)
+
+	// unexpired readings are received first without resource version
+	w, err = store.Watch("sensor-1", "temperature", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", (<-w.ResultChan()).Value)
+	assert.Equal(t, "4", (<-w.ResultChan()).Value)
+	w.Stop()
+
+	// the readings after the resource version have been evicted
+	store.Add(newReading("sensor-1", "temperature", "5"))
+	store.Add(newReading("sensor-1", "temperature", "6"))
+	_, err = store.Watch("sensor-1", "temperature", resourceVersion)
+	assert.Equal(t, ErrResourceVersionTooOld, err)
+}
+
+func Test_StoreTerminatesSlowWatcher(t *testing.T) {
+	store, err := NewStore(Options{MaxReadingsPerProperty: 1})
+	assert.Nil(t, err)
+	w, err := store.Watch("sensor-1", "", 0)
+	assert.Nil(t, err)
+	for i := 0; i <= watcherBufferSize; i++ {
+		store.Add(newReading("sensor-1", "temperature", strconv.Itoa(i)))
+	}
+
+	received := 0
+	for range w.ResultChan() {
+		received++
+	}
+	assert.Equal(t, watcherBufferSize, received)
+}
+
+func Test_StorePersistence(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{MaxReadingsPerProperty: 3, DataDir: dir, MaxDiskBytes: 4096}
+	store, err := NewStore(opts)
+	assert.Nil(t, err)
+	for i := 0; i < 100; i++ {
+		store.Add(newReading("sensor-1", "temperature", strconv.Itoa(i)))
+	}
+	assert.Nil(t, store.Close())
+
+	info, err := os.Stat(filepath.Join(dir, journalFileName))
+	assert.Nil(t, err)
+	assert.LessOrEqual(t, info.Size(), opts.MaxDiskBytes)
+
+	// the readings and resource version are restored
+	store, err = NewStore(opts)
+	assert.Nil(t, err)
+	defer store.Close()
+	readings, resourceVersion := store.List("sensor-1", "temperature")
+	assert.Equal(t, []string{"97", "98", "99"}, values(readings))
+	assert.Equal(t, uint64(100), resourceVersion)
+	assert.Equal(t, uint64(101), store.Add(newReading("sensor-1", "temperature", "100")).ResourceVersion)
+
+	// broken records are skipped
+	f, err := os.OpenFile(filepath.Join(dir, journalFileName), os.O_WRONLY|os.O_APPEND, 0644)
+	assert.Nil(t, err)
+	f.WriteString("{broken\n")
+	f.Close()
+	_, err = NewStore(opts)
+	assert.Nil(t, err)
+}
+
+func Test_NewStoreWithInvalidOptions(t *testing.T) {
+	_, err := NewStore(Options{})
+	assert.NotNil(t, err)
+}
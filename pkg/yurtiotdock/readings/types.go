@@ -0,0 +1,62 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readings
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GroupVersion is the api version of the device readings served by yurt-iot-dock
+	GroupVersion = "iot.openyurt.io/v1alpha1"
+
+	KindDeviceReading     = "DeviceReading"
+	KindDeviceReadingList = "DeviceReadingList"
+)
+
+// DeviceReading is a value of the device property reported by the device on edge platform
+type DeviceReading struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Device is the name of device on OpenYurt
+	Device string `json:"device"`
+	// Property is the name of device property
+	Property string `json:"property"`
+	// Value is the reported value of the property
+	Value string `json:"value"`
+	// ValueType is the type of the value, such as Int32 or Float64
+	// +optional
+	ValueType string `json:"valueType,omitempty"`
+	// Timestamp is the time when the value is reported
+	Timestamp metav1.MicroTime `json:"timestamp"`
+}
+
+// DeviceReadingList contains a list of DeviceReading
+type DeviceReadingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeviceReading `json:"items"`
+}
+
+// WatchEvent is an event of watching the device readings, it has the same format as the watch event of Kubernetes
+type WatchEvent struct {
+	// Type is ADDED for the new readings, or ERROR when the watch is terminated by server
+	Type string `json:"type"`
+	// Object is DeviceReading if Type is ADDED, or metav1.Status if Type is ERROR
+	Object interface{} `json:"object"`
+}
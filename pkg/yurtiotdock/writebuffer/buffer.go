@@ -0,0 +1,587 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package writebuffer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// OpType is the type of a buffered write
+type OpType string
+
+const (
+	OpCreate       OpType = "Create"
+	OpDelete       OpType = "Delete"
+	OpPatch        OpType = "Patch"
+	OpUpdate       OpType = "Update"
+	OpUpdateStatus OpType = "UpdateStatus"
+	OpPatchStatus  OpType = "PatchStatus"
+)
+
+const (
+	// stateFile is the name of file in which the snapshot of pending writes is persisted
+	stateFile = "pending.json"
+	// journalFile is the name of file to which the changes since the snapshot are appended
+	journalFile = "journal.jsonl"
+	// minCompactRecords is the min number of records in journal before it's compacted into the snapshot
+	minCompactRecords = 1024
+)
+
+// ErrBufferFull is returned when a write of a new object can not be buffered
+var ErrBufferFull = errors.New("write buffer is full")
+
+// Op is a write to an object that is not applied to the apiserver yet
+type Op struct {
+	// ID identifies the write, it increases with the order of writes
+	ID   uint64 `json:"id,omitempty"`
+	Type OpType `json:"type"`
+	// Object is the object to create, or the object which should be updated
+	Object json.RawMessage `json:"object,omitempty"`
+	// PatchType and Patch are the patch applied to the object
+	PatchType types.PatchType `json:"patchType,omitempty"`
+	Patch     []byte          `json:"patch,omitempty"`
+}
+
+// entry holds the pending writes of an object in order
+type entry struct {
+	Seq       uint64                  `json:"seq"`
+	GVK       schema.GroupVersionKind `json:"gvk"`
+	Namespace string                  `json:"namespace,omitempty"`
+	Name      string                  `json:"name"`
+	Ops       []Op                    `json:"ops"`
+	// inflight is the ID of the write being applied by Flush
+	inflight uint64
+}
+
+type state struct {
+	Seq     uint64   `json:"seq"`
+	Entries []*entry `json:"entries"`
+}
+
+// record is a change of the pending writes appended to the journal
+type record struct {
+	// Add is a write added to the object, and Inflight is the write being applied at that time
+	Add      *entry `json:"add,omitempty"`
+	Inflight uint64 `json:"inflight,omitempty"`
+	// Done is the ID of the write applied or dropped by Flush
+	Done *doneRecord `json:"done,omitempty"`
+}
+
+type doneRecord struct {
+	Key string `json:"key"`
+	ID  uint64 `json:"id"`
+}
+
+// Options are the options of Buffer
+type Options struct {
+	// Dir is the directory where the pending writes are persisted, they are kept in memory only if it's empty
+	Dir string
+	// MaxObjects is the maximum number of objects which have pending writes
+	MaxObjects int
+}
+
+// Buffer keeps the writes to the apiserver which could not be applied while the apiserver is unreachable.
+// The writes are coalesced per object and applied in the order of objects when Flush is called.
+// The pending writes are persisted as a snapshot and a journal of the changes since the snapshot.
+type Buffer struct {
+	sync.Mutex
+	// flushLock serializes Flush, the writers are not blocked by the apiserver calls of Flush
+	flushLock sync.Mutex
+	opts      Options
+	scheme    *runtime.Scheme
+	seq       uint64
+	entries   map[string]*entry
+	journal   *os.File
+	records   int
+}
+
+// NewBuffer creates a Buffer and restores the writes persisted in opts.Dir
+func NewBuffer(scheme *runtime.Scheme, opts Options) (*Buffer, error) {
+	registerMetrics()
+	b := &Buffer{
+		opts:    opts,
+		scheme:  scheme,
+		entries: make(map[string]*entry),
+	}
+	if len(opts.Dir) == 0 {
+		return b, nil
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := b.restore(); err != nil {
+		return nil, err
+	}
+	// start with a compacted snapshot and an empty journal
+	b.Lock()
+	defer b.Unlock()
+	if err := b.compact(); err != nil {
+		return nil, err
+	}
+	pendingObjects.Set(float64(len(b.entries)))
+	klog.Infof("restored the pending writes of %d objects from %s", len(b.entries), opts.Dir)
+	return b, nil
+}
+
+// restore loads the snapshot and replays the journal on it. The broken files should not stop
+// yurt-iot-dock, the objects are resynced from edge platform later.
+func (b *Buffer) restore() error {
+	data, err := os.ReadFile(filepath.Join(b.opts.Dir, stateFile))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		var s state
+		if err := json.Unmarshal(data, &s); err != nil {
+			klog.ErrorS(err, "could not restore the pending writes, they are discarded", "dir", b.opts.Dir)
+			return nil
+		}
+		b.seq = s.Seq
+		for _, e := range s.Entries {
+			b.entries[keyOf(e.GVK, e.Namespace, e.Name)] = e
+		}
+	}
+
+	f, err := os.Open(filepath.Join(b.opts.Dir, journalFile))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+	decoder := json.NewDecoder(f)
+	for {
+		var r record
+		if err := decoder.Decode(&r); err == io.EOF {
+			return nil
+		} else if err != nil {
+			// the last record may be partially written before a crash
+			klog.ErrorS(err, "could not replay the journal of pending writes, the rest is discarded", "dir", b.opts.Dir)
+			return nil
+		}
+		b.replay(&r)
+	}
+}
+
+// replay applies a record of journal, the records already in the snapshot are skipped
+func (b *Buffer) replay(r *record) {
+	switch {
+	case r.Add != nil && len(r.Add.Ops) == 1:
+		op := r.Add.Ops[0]
+		if op.ID <= b.seq {
+			return
+		}
+		key := keyOf(r.Add.GVK, r.Add.Namespace, r.Add.Name)
+		e, ok := b.entries[key]
+		if !ok {
+			e = &entry{Seq: op.ID, GVK: r.Add.GVK, Namespace: r.Add.Namespace, Name: r.Add.Name}
+			b.entries[key] = e
+		}
+		b.seq = op.ID
+		e.Ops = coalesce(e.Ops, op, r.Inflight)
+		if len(e.Ops) == 0 {
+			delete(b.entries, key)
+		}
+	case r.Done != nil:
+		if e, ok := b.entries[r.Done.Key]; ok {
+			b.done(r.Done.Key, e, r.Done.ID)
+		}
+	}
+}
+
+// Len returns the number of objects which have pending writes
+func (b *Buffer) Len() int {
+	b.Lock()
+	defer b.Unlock()
+	return len(b.entries)
+}
+
+// Has returns true if the object has pending writes, the later writes of the object
+// should be buffered too so that they are not applied before the pending ones.
+func (b *Buffer) Has(obj client.Object) bool {
+	gvk, err := apiutil.GVKForObject(obj, b.scheme)
+	if err != nil {
+		return false
+	}
+	b.Lock()
+	defer b.Unlock()
+	_, ok := b.entries[keyOf(gvk, obj.GetNamespace(), obj.GetName())]
+	return ok
+}
+
+// Add buffers a write of the object, it's coalesced with the pending writes of the object
+func (b *Buffer) Add(obj client.Object, op Op) error {
+	gvk, err := apiutil.GVKForObject(obj, b.scheme)
+	if err != nil {
+		return err
+	}
+	if op.Type == OpCreate || op.Type == OpUpdate || op.Type == OpUpdateStatus {
+		if op.Object, err = json.Marshal(obj); err != nil {
+			return err
+		}
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	key := keyOf(gvk, obj.GetNamespace(), obj.GetName())
+	e, ok := b.entries[key]
+	if !ok {
+		if b.opts.MaxObjects > 0 && len(b.entries) >= b.opts.MaxObjects {
+			droppedWrites.WithLabelValues(string(op.Type), dropReasonFull).Inc()
+			return ErrBufferFull
+		}
+		e = &entry{Seq: b.seq + 1, GVK: gvk, Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		b.entries[key] = e
+	}
+	b.seq++
+	op.ID = b.seq
+	e.Ops = coalesce(e.Ops, op, e.inflight)
+	if len(e.Ops) == 0 {
+		delete(b.entries, key)
+	}
+	bufferedWrites.WithLabelValues(string(op.Type)).Inc()
+	return b.append(&record{Add: &entry{GVK: gvk, Namespace: e.Namespace, Name: e.Name, Ops: []Op{op}}, Inflight: e.inflight})
+}
+
+// coalesce appends op to the pending writes of an object, inflight is the ID of the write being
+// applied which should be kept as it is:
+// 1. consecutive creates, updates or status updates are replaced by the latest one.
+// 2. a delete drops the writes since the last delete, and the object created in the buffer is dropped entirely.
+func coalesce(ops []Op, op Op, inflight uint64) []Op {
+	switch op.Type {
+	case OpCreate, OpUpdate, OpUpdateStatus:
+		if n := len(ops); n != 0 && ops[n-1].Type == op.Type && (inflight == 0 || ops[n-1].ID != inflight) {
+			ops[n-1] = op
+			return ops
+		}
+	case OpDelete:
+		last := -1
+		for i := range ops {
+			if ops[i].Type == OpDelete {
+				last = i
+			}
+		}
+		// the object being created may have reached the apiserver
+		createdInBuffer := last+1 < len(ops) && ops[last+1].Type == OpCreate &&
+			(inflight == 0 || ops[last+1].ID != inflight)
+		ops = ops[:last+1]
+		if createdInBuffer {
+			// the object never reached the apiserver since the last delete
+			return ops
+		}
+	}
+	return append(ops, op)
+}
+
+// Flush applies the pending writes in order, it stops at the first write which fails because
+// the apiserver is still unreachable. The other failed writes are dropped and logged.
+// The lock is not held while the writes are applied, so the writers are not blocked, the
+// writes added meanwhile are applied after the pending ones of the same object.
+func (b *Buffer) Flush(ctx context.Context, c client.Client, reader client.Reader) error {
+	b.flushLock.Lock()
+	defer b.flushLock.Unlock()
+
+	b.Lock()
+	entries := make([]*entry, 0, len(b.entries))
+	for _, e := range b.entries {
+		entries = append(entries, e)
+	}
+	b.Unlock()
+	if len(entries) == 0 {
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Seq < entries[j].Seq })
+
+	var flushErr error
+	for _, e := range entries {
+		if flushErr = b.flushEntry(ctx, c, reader, e); flushErr != nil {
+			break
+		}
+	}
+
+	b.Lock()
+	if len(b.opts.Dir) != 0 && b.records >= max(minCompactRecords, 2*len(b.entries)) {
+		if err := b.compact(); err != nil {
+			klog.ErrorS(err, "could not persist the pending writes")
+		}
+	}
+	b.Unlock()
+	if flushErr != nil {
+		return flushErr
+	}
+	klog.V(2).Infof("flushed the pending writes of %d objects", len(entries))
+	return nil
+}
+
+// flushEntry applies the pending writes of an object one by one, including the ones added while flushing
+func (b *Buffer) flushEntry(ctx context.Context, c client.Client, reader client.Reader, e *entry) error {
+	key := keyOf(e.GVK, e.Namespace, e.Name)
+	for {
+		b.Lock()
+		if b.entries[key] != e || len(e.Ops) == 0 {
+			b.Unlock()
+			return nil
+		}
+		op := e.Ops[0]
+		e.inflight = op.ID
+		b.Unlock()
+
+		err := b.apply(ctx, c, reader, e, op)
+		if IsUnreachable(err) {
+			b.Lock()
+			e.inflight = 0
+			b.Unlock()
+			return err
+		} else if err != nil {
+			klog.ErrorS(err, "could not apply the buffered write, it is dropped", "op", op.Type, "kind", e.GVK.Kind, "namespace", e.Namespace, "name", e.Name)
+			reason := dropReasonFailed
+			if apierrors.IsConflict(err) {
+				reason = dropReasonConflict
+			}
+			droppedWrites.WithLabelValues(string(op.Type), reason).Inc()
+		} else {
+			flushedWrites.WithLabelValues(string(op.Type)).Inc()
+		}
+
+		b.Lock()
+		e.inflight = 0
+		b.done(key, e, op.ID)
+		if err := b.append(&record{Done: &doneRecord{Key: key, ID: op.ID}}); err != nil {
+			klog.ErrorS(err, "could not persist the pending writes")
+		}
+		b.Unlock()
+	}
+}
+
+// done removes the applied write from the head of pending writes, the write may have been
+// replaced or dropped by the later writes meanwhile. It's called with the lock held.
+func (b *Buffer) done(key string, e *entry, id uint64) {
+	if len(e.Ops) != 0 && e.Ops[0].ID == id {
+		e.Ops = e.Ops[1:]
+	}
+	if len(e.Ops) == 0 && b.entries[key] == e {
+		delete(b.entries, key)
+	}
+	pendingObjects.Set(float64(len(b.entries)))
+}
+
+func (b *Buffer) apply(ctx context.Context, c client.Client, reader client.Reader, e *entry, op Op) error {
+	obj, err := b.newObject(e)
+	if err != nil {
+		return err
+	}
+	if len(op.Object) != 0 {
+		if err := json.Unmarshal(op.Object, obj); err != nil {
+			return err
+		}
+	}
+
+	switch op.Type {
+	case OpCreate:
+		obj.SetResourceVersion("")
+		err = c.Create(ctx, obj)
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	case OpDelete:
+		return client.IgnoreNotFound(c.Delete(ctx, obj))
+	case OpPatch:
+		return client.IgnoreNotFound(c.Patch(ctx, obj, client.RawPatch(op.PatchType, op.Patch)))
+	case OpPatchStatus:
+		return client.IgnoreNotFound(c.Status().Patch(ctx, obj, client.RawPatch(op.PatchType, op.Patch)))
+	case OpUpdate, OpUpdateStatus:
+		latest, err := b.newObject(e)
+		if err != nil {
+			return err
+		}
+		if err := reader.Get(ctx, client.ObjectKeyFromObject(obj), latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		// the buffered update is based on the object read before the outage, it would overwrite the spec
+		// changed in the cloud meanwhile, so it's dropped as a conflict when the generation has moved.
+		if op.Type == OpUpdate && latest.GetGeneration() != obj.GetGeneration() {
+			return apierrors.NewConflict(schema.GroupResource{Group: e.GVK.Group, Resource: e.GVK.Kind}, e.Name,
+				fmt.Errorf("the generation is changed from %d to %d while the update is buffered", obj.GetGeneration(), latest.GetGeneration()))
+		}
+		// the status is owned by yurt-iot-dock, and the generation is kept, so the buffered object
+		// overwrites the latest one with the resourceVersion taken from the apiserver
+		obj.SetResourceVersion(latest.GetResourceVersion())
+		if op.Type == OpUpdate {
+			return client.IgnoreNotFound(c.Update(ctx, obj))
+		}
+		return client.IgnoreNotFound(c.Status().Update(ctx, obj))
+	}
+	return fmt.Errorf("unknown buffered write %s", op.Type)
+}
+
+func (b *Buffer) newObject(e *entry) (client.Object, error) {
+	ro, err := b.scheme.New(e.GVK)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := ro.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a client.Object", e.GVK)
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	accessor.SetNamespace(e.Namespace)
+	accessor.SetName(e.Name)
+	return obj, nil
+}
+
+// append appends the record to the journal in Dir and syncs it to disk, so that the buffered write
+// survives a power loss of the node. It's called with the lock held.
+func (b *Buffer) append(r *record) error {
+	pendingObjects.Set(float64(len(b.entries)))
+	if b.journal == nil {
+		return nil
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := b.journal.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err := b.journal.Sync(); err != nil {
+		return err
+	}
+	b.records++
+	return nil
+}
+
+// compact writes the snapshot of pending writes into Dir and truncates the journal,
+// it's called with the lock held
+func (b *Buffer) compact() error {
+	s := state{Seq: b.seq, Entries: make([]*entry, 0, len(b.entries))}
+	for _, e := range b.entries {
+		s.Entries = append(s.Entries, e)
+	}
+	data, err := json.Marshal(&s)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(b.opts.Dir, stateFile+".tmp")
+	if err := writeFileSync(tmp, data); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(b.opts.Dir, stateFile)); err != nil {
+		return err
+	}
+
+	// the records in journal are all covered by the snapshot, they are skipped if the
+	// journal is not truncated before a crash
+	if b.journal != nil {
+		b.journal.Close()
+	}
+	b.journal, err = os.OpenFile(filepath.Join(b.opts.Dir, journalFile), os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_APPEND, 0600)
+	if err != nil {
+		b.journal = nil
+		return err
+	}
+	b.records = 0
+	return nil
+}
+
+// writeFileSync writes data to the file and syncs it to disk before it's renamed to the snapshot
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func keyOf(gvk schema.GroupVersionKind, namespace, name string) string {
+	return gvk.String() + "/" + namespace + "/" + name
+}
+
+// Flusher flushes the pending writes of Buffer periodically
+type Flusher struct {
+	buffer *Buffer
+	client client.Client
+	reader client.Reader
+	period time.Duration
+}
+
+// NewFlusher creates a Flusher, the writes are applied by client c and the latest objects are read by reader
+func NewFlusher(buffer *Buffer, c client.Client, reader client.Reader, period time.Duration) *Flusher {
+	return &Flusher{
+		buffer: buffer,
+		client: c,
+		reader: reader,
+		period: period,
+	}
+}
+
+// Start flushes the pending writes until ctx is done, it implements the manager.Runnable interface
+func (f *Flusher) Start(ctx context.Context) error {
+	klog.V(1).Info("[WriteBuffer] Starting the flusher...")
+	ticker := time.NewTicker(f.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			klog.V(1).Info("[WriteBuffer] Stopping the flusher")
+			return nil
+		case <-ticker.C:
+			if f.buffer.Len() == 0 {
+				continue
+			}
+			if err := f.buffer.Flush(ctx, f.client, f.reader); err != nil {
+				klog.V(3).InfoS("the apiserver is still unreachable, the pending writes are kept", "objects", f.buffer.Len(), "err", err)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements the manager.LeaderElectionRunnable interface, every replica
+// flushes the writes buffered by itself.
+func (f *Flusher) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,345 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package writebuffer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	iotv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+)
+
+var errRefused = &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, iotv1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+func newDevice(name string) *iotv1alpha1.Device {
+	return &iotv1alpha1.Device{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       iotv1alpha1.DeviceSpec{NodePool: "hangzhou"},
+	}
+}
+
+// newFakeClient returns a client whose writes fail with connection refused when offline is true
+func newFakeClient(scheme *runtime.Scheme, offline *atomic.Bool, objs ...client.Object) client.Client {
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(&iotv1alpha1.Device{}).Build()
+	return interceptor.NewClient(c, interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if offline.Load() {
+				return errRefused
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			if offline.Load() {
+				return errRefused
+			}
+			return c.Delete(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if offline.Load() {
+				return errRefused
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if offline.Load() {
+				return errRefused
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			if offline.Load() {
+				return errRefused
+			}
+			return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+		},
+	})
+}
+
+func TestIsUnreachable(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"nil error": {
+			err: nil,
+		},
+		"not found": {
+			err: apierrors.NewNotFound(schema.GroupResource{Resource: "devices"}, "foo"),
+		},
+		"conflict": {
+			err: apierrors.NewConflict(schema.GroupResource{Resource: "devices"}, "foo", errors.New("conflict")),
+		},
+		"service unavailable": {
+			err:      apierrors.NewServiceUnavailable("unavailable"),
+			expected: true,
+		},
+		"connection refused": {
+			err:      fmt.Errorf("post failed: %w", errRefused),
+			expected: true,
+		},
+		"deadline exceeded": {
+			err:      context.DeadlineExceeded,
+			expected: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsUnreachable(tt.err))
+		})
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	create := Op{Type: OpCreate, Object: []byte(`{"v":1}`)}
+	create2 := Op{Type: OpCreate, Object: []byte(`{"v":2}`)}
+	status := Op{Type: OpUpdateStatus, Object: []byte(`{"v":1}`)}
+	status2 := Op{Type: OpUpdateStatus, Object: []byte(`{"v":2}`)}
+	patch := Op{Type: OpPatch, Patch: []byte(`{}`)}
+	del := Op{Type: OpDelete}
+
+	tests := map[string]struct {
+		ops      []Op
+		op       Op
+		inflight uint64
+		expected []Op
+	}{
+		"first write": {
+			op:       status,
+			expected: []Op{status},
+		},
+		"creates are replaced by the latest one": {
+			ops:      []Op{create},
+			op:       create2,
+			expected: []Op{create2},
+		},
+		"status updates are replaced by the latest one": {
+			ops:      []Op{create, status},
+			op:       status2,
+			expected: []Op{create, status2},
+		},
+		"patches are kept in order": {
+			ops:      []Op{status, patch},
+			op:       status2,
+			expected: []Op{status, patch, status2},
+		},
+		"delete drops the pending writes": {
+			ops:      []Op{status, patch},
+			op:       del,
+			expected: []Op{del},
+		},
+		"delete drops the object created in buffer": {
+			ops:      []Op{create, status},
+			op:       del,
+			expected: []Op{},
+		},
+		"create being applied is not replaced": {
+			ops:      []Op{{ID: 1, Type: OpCreate}},
+			op:       Op{ID: 2, Type: OpCreate},
+			inflight: 1,
+			expected: []Op{{ID: 1, Type: OpCreate}, {ID: 2, Type: OpCreate}},
+		},
+		"delete keeps the object being created": {
+			ops:      []Op{{ID: 1, Type: OpCreate}, {ID: 2, Type: OpUpdateStatus}},
+			op:       Op{ID: 3, Type: OpDelete},
+			inflight: 1,
+			expected: []Op{{ID: 3, Type: OpDelete}},
+		},
+		"delete keeps the writes before the last delete": {
+			ops:      []Op{del, create, status},
+			op:       del,
+			expected: []Op{del},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, coalesce(append([]Op{}, tt.ops...), tt.op, tt.inflight))
+		})
+	}
+}
+
+func TestBufferAndFlush(t *testing.T) {
+	ctx := context.TODO()
+	scheme := newScheme(t)
+	dir := t.TempDir()
+	offline := &atomic.Bool{}
+
+	existing := newDevice("existing")
+	removed := newDevice("removed")
+	removed.Finalizers = []string{iotv1alpha1.DeviceFinalizer}
+	base := newFakeClient(scheme, offline, existing, removed)
+
+	buffer, err := NewBuffer(scheme, Options{Dir: dir, MaxObjects: 3})
+	assert.Nil(t, err)
+	c := NewClient(base, buffer)
+
+	// the writes are applied directly when the apiserver is reachable
+	assert.Nil(t, c.Create(ctx, newDevice("online")))
+	assert.Equal(t, 0, buffer.Len())
+
+	offline.Store(true)
+	// a device discovered during outage
+	assert.Nil(t, c.Create(ctx, newDevice("discovered")))
+	// the status updates of a device are coalesced
+	for _, state := range []iotv1alpha1.OperatingState{iotv1alpha1.Down, iotv1alpha1.Up} {
+		d := &iotv1alpha1.Device{}
+		assert.Nil(t, base.Get(ctx, client.ObjectKeyFromObject(existing), d))
+		d.Status.OperatingState = state
+		assert.Nil(t, c.Status().Update(ctx, d))
+	}
+	// the labels of a device are updated
+	d := &iotv1alpha1.Device{}
+	assert.Nil(t, base.Get(ctx, client.ObjectKeyFromObject(existing), d))
+	d.Labels = map[string]string{"zone": "east"}
+	assert.Nil(t, c.Update(ctx, d))
+	// a device created and deleted during outage never reaches the apiserver
+	assert.Nil(t, c.Create(ctx, newDevice("transient")))
+	assert.Nil(t, c.Delete(ctx, newDevice("transient")))
+	// a device deleted during outage, and its finalizer is removed after deletion
+	assert.Nil(t, c.Delete(ctx, removed.DeepCopy()))
+	assert.Nil(t, c.Patch(ctx, removed.DeepCopy(), client.RawPatch(types.MergePatchType, []byte(`{"metadata":{"finalizers":[]}}`))))
+	assert.Equal(t, 3, buffer.Len())
+	// the buffer is full
+	assert.True(t, errors.Is(c.Create(ctx, newDevice("overflow")), ErrBufferFull))
+
+	// the pending writes are restored after restart
+	restored, err := NewBuffer(scheme, Options{Dir: dir, MaxObjects: 3})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, restored.Len())
+
+	// the pending writes are kept when the apiserver is still unreachable
+	assert.True(t, IsUnreachable(restored.Flush(ctx, base, base)))
+	assert.Equal(t, 3, restored.Len())
+
+	offline.Store(false)
+	assert.Nil(t, restored.Flush(ctx, base, base))
+	assert.Equal(t, 0, restored.Len())
+
+	assert.Nil(t, base.Get(ctx, client.ObjectKey{Namespace: "default", Name: "discovered"}, d))
+	assert.Nil(t, base.Get(ctx, client.ObjectKeyFromObject(existing), d))
+	assert.Equal(t, iotv1alpha1.Up, d.Status.OperatingState)
+	assert.Equal(t, "east", d.Labels["zone"])
+	assert.True(t, apierrors.IsNotFound(base.Get(ctx, client.ObjectKey{Namespace: "default", Name: "transient"}, d)))
+	assert.True(t, apierrors.IsNotFound(base.Get(ctx, client.ObjectKeyFromObject(removed), d)))
+
+	// the flushed state is persisted too
+	restored, err = NewBuffer(scheme, Options{Dir: dir})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, restored.Len())
+}
+
+func TestFlushDropsConflictingUpdate(t *testing.T) {
+	ctx := context.TODO()
+	scheme := newScheme(t)
+	offline := &atomic.Bool{}
+
+	existing := newDevice("existing")
+	existing.Generation = 1
+	base := newFakeClient(scheme, offline, existing)
+
+	buffer, err := NewBuffer(scheme, Options{Dir: t.TempDir()})
+	assert.Nil(t, err)
+	c := NewClient(base, buffer)
+
+	offline.Store(true)
+	d := &iotv1alpha1.Device{}
+	assert.Nil(t, base.Get(ctx, client.ObjectKeyFromObject(existing), d))
+	d.Labels = map[string]string{"zone": "east"}
+	d.Spec.Notify = true
+	assert.Nil(t, c.Update(ctx, d))
+	assert.Equal(t, 1, buffer.Len())
+
+	// the spec is changed in the cloud during outage
+	offline.Store(false)
+	cloud := &iotv1alpha1.Device{}
+	assert.Nil(t, base.Get(ctx, client.ObjectKeyFromObject(existing), cloud))
+	cloud.Generation = 2
+	cloud.Spec.Description = "changed in cloud"
+	assert.Nil(t, base.Update(ctx, cloud))
+
+	// the buffered update is dropped rather than overwriting the change in the cloud
+	assert.Nil(t, buffer.Flush(ctx, base, base))
+	assert.Equal(t, 0, buffer.Len())
+	assert.Nil(t, base.Get(ctx, client.ObjectKeyFromObject(existing), d))
+	assert.Equal(t, "changed in cloud", d.Spec.Description)
+	assert.False(t, d.Spec.Notify)
+	assert.Empty(t, d.Labels["zone"])
+}
+
+func TestFlushDoesNotBlockWriters(t *testing.T) {
+	ctx := context.TODO()
+	scheme := newScheme(t)
+	offline := &atomic.Bool{}
+	base := newFakeClient(scheme, offline)
+
+	applying, release := make(chan struct{}), make(chan struct{})
+	blocking := interceptor.NewClient(base.(client.WithWatch), interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			close(applying)
+			<-release
+			return c.Create(ctx, obj, opts...)
+		},
+	})
+
+	buffer, err := NewBuffer(scheme, Options{Dir: t.TempDir()})
+	assert.Nil(t, err)
+	c := NewClient(base, buffer)
+	offline.Store(true)
+	assert.Nil(t, c.Create(ctx, newDevice("foo")))
+	offline.Store(false)
+
+	flushed := make(chan error)
+	go func() {
+		flushed <- buffer.Flush(ctx, blocking, base)
+	}()
+	<-applying
+
+	// the writes of the object being flushed are buffered without waiting for the flush
+	d := newDevice("foo")
+	d.Status.OperatingState = iotv1alpha1.Up
+	assert.Nil(t, c.Status().Update(ctx, d))
+	assert.Nil(t, c.Create(ctx, newDevice("bar")))
+	assert.Equal(t, 1, buffer.Len())
+
+	close(release)
+	assert.Nil(t, <-flushed)
+	assert.Equal(t, 0, buffer.Len())
+	assert.Nil(t, base.Get(ctx, client.ObjectKeyFromObject(d), d))
+	assert.Equal(t, iotv1alpha1.Up, d.Status.OperatingState)
+}
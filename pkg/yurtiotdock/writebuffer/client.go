@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package writebuffer
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IsUnreachable returns true if the error means the apiserver can not be reached,
+// the write may succeed when it's retried later.
+func IsUnreachable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) {
+		return true
+	}
+	if utilnet.IsConnectionRefused(err) || utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err) || utilnet.IsTimeout(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// Client writes objects through the embedded client, the creates, deletes, updates, patches and status
+// writes are buffered when the apiserver is unreachable, or when the object already has buffered writes.
+// The reads are served by the embedded client, which is backed by the local cache.
+type Client struct {
+	client.Client
+	buffer *Buffer
+}
+
+// NewClient wraps the client c with the buffer
+func NewClient(c client.Client, buffer *Buffer) *Client {
+	return &Client{Client: c, buffer: buffer}
+}
+
+func (c *Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.write(obj, Op{Type: OpCreate}, func() error {
+		return c.Client.Create(ctx, obj, opts...)
+	})
+}
+
+func (c *Client) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return c.write(obj, Op{Type: OpDelete}, func() error {
+		return c.Client.Delete(ctx, obj, opts...)
+	})
+}
+
+func (c *Client) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.write(obj, Op{Type: OpUpdate}, func() error {
+		return c.Client.Update(ctx, obj, opts...)
+	})
+}
+
+func (c *Client) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	op, err := patchOp(OpPatch, obj, patch)
+	if err != nil {
+		return err
+	}
+	return c.write(obj, op, func() error {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	})
+}
+
+func (c *Client) Status() client.SubResourceWriter {
+	return &statusWriter{SubResourceWriter: c.Client.Status(), client: c}
+}
+
+// write applies the write directly if the object has no buffered writes,
+// and buffers it if the object has buffered writes or the apiserver is unreachable.
+// nil is returned once the write is buffered, so the callers see the success before the write
+// is applied, and the buffered write may still be dropped when it's flushed, e.g. an update
+// which conflicts with the spec changed in the cloud during the outage.
+func (c *Client) write(obj client.Object, op Op, apply func() error) error {
+	if !c.buffer.Has(obj) {
+		err := apply()
+		if !IsUnreachable(err) {
+			return err
+		}
+		klog.V(4).InfoS("the apiserver is unreachable, the write is buffered", "op", op.Type, "namespace", obj.GetNamespace(), "name", obj.GetName(), "err", err)
+	}
+	return c.buffer.Add(obj, op)
+}
+
+func patchOp(opType OpType, obj client.Object, patch client.Patch) (Op, error) {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return Op{}, err
+	}
+	return Op{Type: opType, PatchType: patch.Type(), Patch: data}, nil
+}
+
+type statusWriter struct {
+	client.SubResourceWriter
+	client *Client
+}
+
+func (w *statusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return w.client.write(obj, Op{Type: OpUpdateStatus}, func() error {
+		return w.SubResourceWriter.Update(ctx, obj, opts...)
+	})
+}
+
+func (w *statusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	op, err := patchOp(OpPatchStatus, obj, patch)
+	if err != nil {
+		return err
+	}
+	return w.client.write(obj, op, func() error {
+		return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+	})
+}
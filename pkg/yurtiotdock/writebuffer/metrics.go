@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package writebuffer
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "yurt_iot_dock"
+	metricsSubsystem = "write_buffer"
+
+	// reasons why a write is dropped by the buffer
+	dropReasonFull     = "full"
+	dropReasonFailed   = "failed"
+	dropReasonConflict = "conflict"
+)
+
+var (
+	pendingObjects = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "pending_objects",
+			Help:      "Number of objects which have writes not applied to the apiserver",
+		})
+
+	bufferedWrites = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "buffered_writes_total",
+			Help:      "Number of writes buffered because the apiserver is unreachable",
+		},
+		[]string{"op"})
+
+	flushedWrites = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "flushed_writes_total",
+			Help:      "Number of buffered writes applied to the apiserver",
+		},
+		[]string{"op"})
+
+	droppedWrites = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "dropped_writes_total",
+			Help:      "Number of writes which are dropped by the buffer",
+		},
+		[]string{"op", "reason"})
+
+	registerOnce sync.Once
+)
+
+func registerMetrics() {
+	registerOnce.Do(func() {
+		ctrlmetrics.Registry.MustRegister(pendingObjects, bufferedWrites, flushedWrites, droppedWrites)
+	})
+}
@@ -28,6 +28,7 @@ import (
 	hubleaderrbacconfig "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/hubleaderrbac/config"
 	loadbalancersetconfig "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/loadbalancerset/loadbalancerset/config"
 	nodebucketconfig "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/nodebucket/config"
+	nodelifecycleconfig "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/nodelifecycle/config"
 	nodepoolconfig "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/nodepool/config"
 	platformadminconfig "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/platformadmin/config"
 	gatewaydnsconfig "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/raven/dns/config"
@@ -70,6 +71,9 @@ type YurtManagerConfiguration struct {
 	// NodeLifeCycleControllerConfiguration holds configuration for NodeLifeCycleController related features.
 	NodeLifeCycleController v1alpha1.NodeLifecycleControllerConfiguration
 
+	// NodePoolPartition holds configuration for detecting disconnected NodePools in NodeLifeCycleController.
+	NodePoolPartition nodelifecycleconfig.PoolPartitionConfiguration
+
 	// NodeBucketController holds configuration for NodeBucketController related features.
 	NodeBucketController nodebucketconfig.NodeBucketControllerConfiguration
 
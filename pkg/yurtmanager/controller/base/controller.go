@@ -115,8 +115,10 @@ func NewControllerInitializers() map[string]InitFunc {
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=list;watch
 // +kubebuilder:rbac:groups=core,resources=services,verbs=list;watch
 // +kubebuilder:rbac:groups=network.openyurt.io,resources=poolservices,verbs=list;watch
+// +kubebuilder:rbac:groups=network.openyurt.io,resources=addresspools,verbs=list;watch
 // +kubebuilder:rbac:groups=apps.openyurt.io,resources=nodepools,verbs=list;watch
 // +kubebuilder:rbac:groups=apps.openyurt.io,resources=nodebuckets,verbs=list;watch
+// +kubebuilder:rbac:groups=apps.openyurt.io,resources=poolresourcequotas,verbs=list;watch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=list;watch
 // +kubebuilder:rbac:groups=iot.openyurt.io,resources=platformadmins,verbs=list;watch
 // +kubebuilder:rbac:groups=apps.openyurt.io,resources=yurtappsets,verbs=list;watch
@@ -164,6 +166,11 @@ func SetupWithManager(ctx context.Context, c *config.CompletedConfig, m manager.
 			names.PodBindingController,
 			ControllersDisabledByDefault,
 			c.ComponentConfig.Generic.Controllers,
+		) ||
+		app.IsControllerEnabled(
+			names.NodePoolController,
+			ControllersDisabledByDefault,
+			c.ComponentConfig.Generic.Controllers,
 		) {
 		// Register spec.NodeName field indexers
 		if err := m.GetFieldIndexer().IndexField(context.TODO(), &v1.Pod{}, "spec.nodeName", func(rawObj client.Object) []string {
@@ -51,6 +51,7 @@ import (
 	appconfig "github.com/openyurtio/openyurt/cmd/yurt-manager/app/config"
 	"github.com/openyurtio/openyurt/cmd/yurt-manager/names"
 	k8sutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/daemonpodupdater/kubernetes"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/maintenancewindow"
 	podutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/pod"
 )
 
@@ -75,6 +76,9 @@ const (
 
 	// PodNeedUpgrade indicates whether the pod is able to upgrade.
 	PodNeedUpgrade corev1.PodConditionType = "PodNeedUpgrade"
+	// PodUpgradeScheduled indicates that the upgrade of pod is deferred to the next maintenance window,
+	// the maintenance window is declared by annotation "apps.openyurt.io/maintenance-window" on DaemonSet or NodePool.
+	PodUpgradeScheduled = maintenancewindow.PodUpgradeScheduled
 
 	// MaxUnavailableAnnotation is the annotation key added to DaemonSet to indicate
 	// the max unavailable pods number. It's used with "apps.openyurt.io/update-strategy=AdvancedRollingUpdate".
@@ -165,8 +169,10 @@ func add(mgr manager.Manager, cfg *appconfig.CompletedConfig, r reconcile.Reconc
 
 	// 2. Watch for deletion of pods. The reason we watch is that we don't want a daemon set to delete
 	// more pods until all the effects (expectations) of a daemon set's delete have been observed.
+	// The changes of postponement are watched as well, so a pod is upgraded once its postponement is cancelled.
 	updater := r.(*ReconcileDaemonpodupdater)
 	if err := c.Watch(source.Kind[client.Object](mgr.GetCache(), &corev1.Pod{}, &handler.Funcs{
+		UpdateFunc: updater.updatePod,
 		DeleteFunc: updater.deletePod,
 	})); err != nil {
 		return err
@@ -199,6 +205,7 @@ func daemonsetUpdate(evt event.UpdateEvent) bool {
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;update
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.openyurt.io,resources=nodepools,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a DaemonSet object and makes changes based on the state read
 // and what is in the DaemonSet.Spec
@@ -237,15 +244,18 @@ func (r *ReconcileDaemonpodupdater) Reconcile(_ context.Context, request reconci
 		return reconcile.Result{}, nil
 	}
 
+	// requeueAfter is the duration until the next maintenance window opens for pods waiting to upgrade
+	var requeueAfter time.Duration
+	var err error
 	switch strings.ToLower(v) {
 	case strings.ToLower(OTAUpdate):
-		if err := r.otaUpdate(instance); err != nil {
+		if requeueAfter, err = r.otaUpdate(instance); err != nil {
 			klog.Error(Format("could not OTA update DaemonSet %v pod: %v", request.NamespacedName, err))
 			return reconcile.Result{}, err
 		}
 
 	case strings.ToLower(AutoUpdate), strings.ToLower(AdvancedRollingUpdate):
-		if err := r.advancedRollingUpdate(instance); err != nil {
+		if requeueAfter, err = r.advancedRollingUpdate(instance); err != nil {
 			klog.Error(Format("could not advanced rolling update DaemonSet %v pod: %v", request.NamespacedName, err))
 			return reconcile.Result{}, err
 		}
@@ -254,7 +264,37 @@ func (r *ReconcileDaemonpodupdater) Reconcile(_ context.Context, request reconci
 		return reconcile.Result{}, fmt.Errorf("unknown update type %v", v)
 	}
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// updatePod enqueues the DaemonSet of the pod whose postponement of upgrade is changed
+func (r *ReconcileDaemonpodupdater) updatePod(ctx context.Context, evt event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	oldPod, ok := evt.ObjectOld.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	newPod, ok := evt.ObjectNew.(*corev1.Pod)
+	if !ok {
+		return
+	}
+
+	_, oldCond := podutil.GetPodCondition(&oldPod.Status, maintenancewindow.PodUpgradePostponed)
+	_, newCond := podutil.GetPodCondition(&newPod.Status, maintenancewindow.PodUpgradePostponed)
+	if oldCond == nil && newCond == nil || oldCond != nil && newCond != nil &&
+		oldCond.Status == newCond.Status && oldCond.Message == newCond.Message {
+		return
+	}
+
+	controllerRef := metav1.GetControllerOf(newPod)
+	if controllerRef == nil {
+		return
+	}
+	ds := r.resolveControllerRef(newPod.Namespace, controllerRef)
+	if ds == nil || !checkPrerequisites(ds) {
+		return
+	}
+	klog.V(5).Infof("Postponement of DaemonSet pod %s/%s is changed", newPod.Namespace, newPod.Name)
+	q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ds.Namespace, Name: ds.Name}})
 }
 
 func (r *ReconcileDaemonpodupdater) deletePod(ctx context.Context, evt event.DeleteEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
@@ -291,47 +331,100 @@ func (r *ReconcileDaemonpodupdater) deletePod(ctx context.Context, evt event.Del
 
 // otaUpdate compare every pod to its owner DaemonSet to check if pod is updatable
 // If pod is in line with the latest DaemonSet spec, set pod condition "PodNeedUpgrade" to "false"
-// while not, set pod condition "PodNeedUpgrade" to "true"
-func (r *ReconcileDaemonpodupdater) otaUpdate(ds *appsv1.DaemonSet) error {
+// while not, set pod condition "PodNeedUpgrade" to "true".
+// If the maintenance window of pod is closed, set pod condition "PodUpgradeScheduled" to "true" as well,
+// and the duration until the earliest maintenance window opens is returned.
+func (r *ReconcileDaemonpodupdater) otaUpdate(ds *appsv1.DaemonSet) (time.Duration, error) {
 	pods, err := GetDaemonsetPods(r.Client, ds)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
+	now := time.Now()
+	var requeueAfter time.Duration
 	for _, pod := range pods {
 		if err := SetPodUpgradeCondition(r.Client, ds, pod); err != nil {
-			return err
+			return 0, err
 		}
+
+		waiting, scheduledAt := false, time.Time{}
+		if IsPodUpgradeConditionTrue(pod.Status) && len(pod.Spec.NodeName) != 0 {
+			node := &corev1.Node{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+				if apierrors.IsNotFound(err) {
+					// the pod on a deleted node will be removed, go on with the other pods
+					continue
+				}
+				return 0, err
+			}
+			var open bool
+			if open, scheduledAt, err = r.maintenanceWindowOpen(ds, node, now); err != nil {
+				return 0, err
+			}
+			waiting = !open
+		}
+		if err := SetPodScheduledCondition(r.Client, pod, waiting, scheduledAt); err != nil {
+			return 0, err
+		}
+		requeueAfter = earlierRequeue(requeueAfter, waiting, scheduledAt.Sub(now))
 	}
-	return nil
+	return requeueAfter, nil
+}
+
+// maintenanceWindowOpen checks whether the maintenance windows of DaemonSet and the NodePool of node are open,
+// the time when all of them are expected to be open is returned if not.
+func (r *ReconcileDaemonpodupdater) maintenanceWindowOpen(ds *appsv1.DaemonSet, node *corev1.Node, now time.Time) (bool, time.Time, error) {
+	windows, err := maintenancewindow.ForNode(context.TODO(), r.Client, ds, node)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("could not get maintenance window for node %s, %v", node.Name, err)
+	}
+	open, next := maintenancewindow.Evaluate(windows, now)
+	return open, next, nil
+}
+
+// earlierRequeue returns the earlier one of current and d if waiting is true
+func earlierRequeue(current time.Duration, waiting bool, d time.Duration) time.Duration {
+	if !waiting {
+		return current
+	}
+	// requeue a little later than the window opens to make sure it's open
+	d += time.Second
+	if current == 0 || d < current {
+		return d
+	}
+	return current
 }
 
 // advancedRollingUpdate identifies the set of old pods to delete within the constraints imposed by the max-unavailable number.
 // Just ignore and do not calculate not-ready nodes.
-func (r *ReconcileDaemonpodupdater) advancedRollingUpdate(ds *appsv1.DaemonSet) error {
+// Pods on nodes whose maintenance window is closed and pods whose upgrade is postponed are not deleted,
+// and the duration until the earliest of them can be upgraded is returned.
+func (r *ReconcileDaemonpodupdater) advancedRollingUpdate(ds *appsv1.DaemonSet) (time.Duration, error) {
 	nodeToDaemonPods, err := r.getNodesToDaemonPods(ds)
 	if err != nil {
-		return fmt.Errorf("couldn't get node to daemon pod mapping for daemon set %q: %v", ds.Name, err)
+		return 0, fmt.Errorf("couldn't get node to daemon pod mapping for daemon set %q: %v", ds.Name, err)
 	}
 
 	// Calculate maxUnavailable specified by user, default is 1
 	maxUnavailable, err := r.maxUnavailableCounts(ds, nodeToDaemonPods)
 	if err != nil {
-		return fmt.Errorf("couldn't get maxUnavailable number for daemon set %q: %v", ds.Name, err)
+		return 0, fmt.Errorf("couldn't get maxUnavailable number for daemon set %q: %v", ds.Name, err)
 	}
 
 	var numUnavailable int
 	var allowedReplacementPods []string
 	var candidatePodsToDelete []string
+	var requeueAfter time.Duration
+	now := time.Now()
 
 	for nodeName, pods := range nodeToDaemonPods {
 		// Check if node is ready, ignore not-ready node
 		// this is a significant difference from the native DaemonSet controller
-		ready, err := NodeReadyByName(r.Client, nodeName)
-		if err != nil {
-			return fmt.Errorf("couldn't check node %q ready status, %v", nodeName, err)
+		node := &corev1.Node{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: nodeName}, node); err != nil {
+			return 0, fmt.Errorf("couldn't check node %q ready status, %v", nodeName, err)
 		}
-		if !ready {
+		if !NodeReady(&node.Status) {
 			continue
 		}
 
@@ -353,7 +446,27 @@ func (r *ReconcileDaemonpodupdater) advancedRollingUpdate(ds *appsv1.DaemonSet)
 				numUnavailable++
 			}
 		default:
-			// This pod is old, it is an update candidate
+			// This pod is old, it is an update candidate only when the maintenance window is open
+			open, scheduledAt, err := r.maintenanceWindowOpen(ds, node, now)
+			if err != nil {
+				return 0, err
+			}
+			if err := SetPodScheduledCondition(r.Client, oldPod, !open, scheduledAt); err != nil {
+				return 0, err
+			}
+			if !open {
+				klog.V(5).Infof("DaemonSet %s/%s pod %s on node %s is out of date, waiting for maintenance window until %s",
+					ds.Namespace, ds.Name, oldPod.Name, nodeName, scheduledAt.Format(time.RFC3339))
+				requeueAfter = earlierRequeue(requeueAfter, true, scheduledAt.Sub(now))
+				continue
+			}
+			if until, postponed := maintenancewindow.PostponedUntil(oldPod, now); postponed {
+				klog.V(5).Infof("DaemonSet %s/%s pod %s on node %s is out of date, its upgrade is postponed until %s",
+					ds.Namespace, ds.Name, oldPod.Name, nodeName, until.Format(time.RFC3339))
+				requeueAfter = earlierRequeue(requeueAfter, true, until.Sub(now))
+				continue
+			}
+
 			switch {
 			case !podutil.IsPodAvailable(oldPod, ds.Spec.MinReadySeconds, metav1.Time{Time: time.Now()}):
 				// The old pod isn't available, so it needs to be replaced
@@ -387,7 +500,7 @@ func (r *ReconcileDaemonpodupdater) advancedRollingUpdate(ds *appsv1.DaemonSet)
 	}
 	oldPodsToDelete := append(allowedReplacementPods, candidatePodsToDelete[:remainingUnavailable]...)
 
-	return r.syncPodsOnNodes(ds, oldPodsToDelete)
+	return requeueAfter, r.syncPodsOnNodes(ds, oldPodsToDelete)
 }
 
 // getNodesToDaemonPods returns a map from nodes to daemon pods (corresponding to ds) created for the nodes.
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
@@ -30,11 +31,15 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
 	k8sutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/daemonpodupdater/kubernetes"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/maintenancewindow"
 )
 
 const (
@@ -374,3 +379,201 @@ func TestController_maxUnavailableCounts(t *testing.T) {
 		})
 	}
 }
+
+func TestMaintenanceWindow(t *testing.T) {
+	// the window opens two hours later, so it must be closed now
+	opensAt := time.Now().UTC().Add(2 * time.Hour)
+	closed := fmt.Sprintf(`{"schedules":["%d %d * * *"],"duration":"1h"}`, opensAt.Minute(), opensAt.Hour())
+	open := `{"schedules":["* * * * *"],"duration":"1h"}`
+
+	tests := []struct {
+		name          string
+		strategy      string
+		window        string
+		wantDelete    bool
+		wantScheduled bool
+	}{
+		{
+			name:       "advanced rolling update in open window",
+			strategy:   AdvancedRollingUpdate,
+			window:     open,
+			wantDelete: true,
+		},
+		{
+			name:          "advanced rolling update in closed window",
+			strategy:      AdvancedRollingUpdate,
+			window:        closed,
+			wantScheduled: true,
+		},
+		{
+			name:     "ota in open window",
+			strategy: OTAUpdate,
+			window:   open,
+		},
+		{
+			name:          "ota in closed window",
+			strategy:      OTAUpdate,
+			window:        closed,
+			wantScheduled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newDaemonSet("ds", "foo/bar:v1")
+			setOnDelete(ds)
+			metav1.SetMetaDataAnnotation(&ds.ObjectMeta, UpdateAnnotation, tt.strategy)
+			metav1.SetMetaDataAnnotation(&ds.ObjectMeta, apps.AnnotationMaintenanceWindow, tt.window)
+			objs, _ := addNodesWithPods(1, 1, ds, true)
+			ds.Spec.Template.Spec.Containers[0].Image = "foo/bar:v2"
+
+			c := fakeclient.NewClientBuilder().WithObjects(ds).WithObjects(objs...).WithStatusSubresource(&corev1.Pod{}).Build()
+			podControl := &k8sutil.FakePodControl{}
+			r := &ReconcileDaemonpodupdater{
+				Client:       c,
+				expectations: k8sutil.NewControllerExpectations(),
+				podControl:   podControl,
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ds.Namespace, Name: ds.Name}}
+			result, err := r.Reconcile(context.TODO(), req)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tt.wantDelete, len(podControl.DeletePodName) != 0)
+			assert.Equal(t, tt.wantScheduled, result.RequeueAfter > 0)
+
+			pod := &corev1.Pod{}
+			assert.Equal(t, nil, c.Get(context.TODO(), client.ObjectKeyFromObject(objs[1]), pod))
+			assert.Equal(t, tt.wantScheduled, maintenancewindow.IsScheduled(pod))
+			if tt.strategy == OTAUpdate {
+				assert.Equal(t, !tt.wantScheduled, IsPodUpdatable(pod))
+			}
+		})
+	}
+}
+
+func TestOTAUpdateWithDeletedNode(t *testing.T) {
+	// the window opens two hours later, so it must be closed now
+	opensAt := time.Now().UTC().Add(2 * time.Hour)
+	closed := fmt.Sprintf(`{"schedules":["%d %d * * *"],"duration":"1h"}`, opensAt.Minute(), opensAt.Hour())
+
+	ds := newDaemonSet("ds", "foo/bar:v1")
+	setOnDelete(ds)
+	metav1.SetMetaDataAnnotation(&ds.ObjectMeta, UpdateAnnotation, OTAUpdate)
+	metav1.SetMetaDataAnnotation(&ds.ObjectMeta, apps.AnnotationMaintenanceWindow, closed)
+	objs, _ := addNodesWithPods(1, 2, ds, true)
+	ds.Spec.Template.Spec.Containers[0].Image = "foo/bar:v2"
+
+	// the node of the first pod has been deleted
+	c := fakeclient.NewClientBuilder().WithObjects(ds).WithObjects(objs[1:]...).WithStatusSubresource(&corev1.Pod{}).Build()
+	r := &ReconcileDaemonpodupdater{
+		Client:       c,
+		expectations: k8sutil.NewControllerExpectations(),
+		podControl:   &k8sutil.FakePodControl{},
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ds.Namespace, Name: ds.Name}}
+	result, err := r.Reconcile(context.TODO(), req)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, result.RequeueAfter > 0)
+
+	pod := &corev1.Pod{}
+	assert.Equal(t, nil, c.Get(context.TODO(), client.ObjectKeyFromObject(objs[3]), pod))
+	assert.Equal(t, true, maintenancewindow.IsScheduled(pod))
+}
+
+func TestPostponedUpgrade(t *testing.T) {
+	tests := []struct {
+		name       string
+		strategy   string
+		until      time.Time
+		wantDelete bool
+	}{
+		{
+			name:     "advanced rolling update of postponed pod",
+			strategy: AdvancedRollingUpdate,
+			until:    time.Now().Add(2 * time.Hour),
+		},
+		{
+			name:       "advanced rolling update of expired postponement",
+			strategy:   AdvancedRollingUpdate,
+			until:      time.Now().Add(-time.Hour),
+			wantDelete: true,
+		},
+		{
+			name:     "ota of postponed pod",
+			strategy: OTAUpdate,
+			until:    time.Now().Add(2 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newDaemonSet("ds", "foo/bar:v1")
+			setOnDelete(ds)
+			metav1.SetMetaDataAnnotation(&ds.ObjectMeta, UpdateAnnotation, tt.strategy)
+			objs, _ := addNodesWithPods(1, 1, ds, true)
+			ds.Spec.Template.Spec.Containers[0].Image = "foo/bar:v2"
+			postponed := objs[1].(*corev1.Pod)
+			postponed.Status.Conditions = append(postponed.Status.Conditions, *maintenancewindow.NewPostponedCondition(true, tt.until))
+
+			c := fakeclient.NewClientBuilder().WithObjects(ds).WithObjects(objs...).WithStatusSubresource(&corev1.Pod{}).Build()
+			podControl := &k8sutil.FakePodControl{}
+			r := &ReconcileDaemonpodupdater{
+				Client:       c,
+				expectations: k8sutil.NewControllerExpectations(),
+				podControl:   podControl,
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ds.Namespace, Name: ds.Name}}
+			result, err := r.Reconcile(context.TODO(), req)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tt.wantDelete, len(podControl.DeletePodName) != 0)
+
+			pod := &corev1.Pod{}
+			assert.Equal(t, nil, c.Get(context.TODO(), client.ObjectKeyFromObject(postponed), pod))
+			if tt.strategy == OTAUpdate {
+				assert.Equal(t, true, IsPodUpgradeConditionTrue(pod.Status))
+				assert.Equal(t, false, IsPodUpdatable(pod))
+			} else {
+				assert.Equal(t, !tt.wantDelete, result.RequeueAfter > 0)
+			}
+		})
+	}
+}
+
+func TestUpdatePod(t *testing.T) {
+	ds := newDaemonSet("ds", "foo/bar:v1")
+	setOnDelete(ds)
+	metav1.SetMetaDataAnnotation(&ds.ObjectMeta, UpdateAnnotation, AdvancedRollingUpdate)
+	objs, _ := addNodesWithPods(1, 1, ds, true)
+	oldPod := objs[1].(*corev1.Pod)
+	postponedPod := oldPod.DeepCopy()
+	postponedPod.Status.Conditions = append(postponedPod.Status.Conditions,
+		*maintenancewindow.NewPostponedCondition(true, time.Now().Add(time.Hour)))
+	cancelledPod := oldPod.DeepCopy()
+	cancelledPod.Status.Conditions = append(cancelledPod.Status.Conditions,
+		*maintenancewindow.NewPostponedCondition(false, time.Time{}))
+
+	tests := []struct {
+		name        string
+		oldPod      *corev1.Pod
+		newPod      *corev1.Pod
+		wantEnqueue bool
+	}{
+		{name: "postponement is not changed", oldPod: oldPod, newPod: oldPod.DeepCopy()},
+		{name: "upgrade is postponed", oldPod: oldPod, newPod: postponedPod, wantEnqueue: true},
+		{name: "postponement is cancelled", oldPod: postponedPod, newPod: cancelledPod, wantEnqueue: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fakeclient.NewClientBuilder().WithObjects(ds).Build()
+			r := &ReconcileDaemonpodupdater{Client: c}
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			defer q.ShutDown()
+
+			r.updatePod(context.TODO(), event.UpdateEvent{ObjectOld: tt.oldPod, ObjectNew: tt.newPod}, q)
+			assert.Equal(t, tt.wantEnqueue, q.Len() == 1)
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -31,6 +32,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/daemonpodupdater/kubernetes"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/maintenancewindow"
 	podutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/pod"
 )
 
@@ -139,6 +141,24 @@ func SetPodUpgradeCondition(c client.Client, ds *appsv1.DaemonSet, pod *corev1.P
 	return nil
 }
 
+// SetPodScheduledCondition sets pod condition "PodUpgradeScheduled", the condition is true when the upgrade
+// of pod is waiting for the maintenance window which opens at scheduledAt.
+func SetPodScheduledCondition(c client.Client, pod *corev1.Pod, waiting bool, scheduledAt time.Time) error {
+	// avoid adding the condition to pods which are never deferred
+	if _, old := podutil.GetPodCondition(&pod.Status, PodUpgradeScheduled); old == nil && !waiting {
+		return nil
+	}
+
+	cond := maintenancewindow.NewScheduledCondition(waiting, scheduledAt)
+	if change := podutil.UpdatePodCondition(&pod.Status, cond); change {
+		if err := c.Status().Update(context.TODO(), pod, &client.SubResourceUpdateOptions{}); err != nil {
+			return err
+		}
+		klog.Infof("set pod %q condition PodUpgradeScheduled to %v", pod.Name, waiting)
+	}
+	return nil
+}
+
 // checkPrerequisites checks that daemonset meets two conditions
 // 1. annotation "apps.openyurt.io/update-strategy"="AdvancedRollingUpdate" or "OTA"
 // 2. update strategy is "OnDelete"
@@ -231,8 +251,12 @@ func GetTargetNodeName(pod *corev1.Pod) (string, error) {
 }
 
 // IsPodUpdatable returns true if a pod is updatable; false otherwise.
+// A pod waiting for the next maintenance window or postponed by user is not updatable.
 func IsPodUpdatable(pod *corev1.Pod) bool {
-	return IsPodUpgradeConditionTrue(pod.Status)
+	if _, postponed := maintenancewindow.PostponedUntil(pod, time.Now()); postponed {
+		return false
+	}
+	return IsPodUpgradeConditionTrue(pod.Status) && !maintenancewindow.IsScheduled(pod)
 }
 
 // IsPodUpgradeConditionTrue returns true if a pod is updatable; false otherwise.
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"reflect"
 	"slices"
 
 	corev1 "k8s.io/api/core/v1"
@@ -102,7 +103,9 @@ func Add(ctx context.Context, cfg *appconfig.CompletedConfig, mgr manager.Manage
 				oldPool.Status.UnreadyNodeNum != newPool.Status.UnreadyNodeNum ||
 				oldPool.Spec.EnableLeaderElection != newPool.Spec.EnableLeaderElection ||
 				(oldPool.Spec.LeaderElectionStrategy == string(appsv1beta2.ElectionStrategyMark) &&
-					!maps.Equal(oldPool.Spec.LeaderNodeLabelSelector, newPool.Spec.LeaderNodeLabelSelector)) {
+					!maps.Equal(oldPool.Spec.LeaderNodeLabelSelector, newPool.Spec.LeaderNodeLabelSelector)) ||
+				(newPool.Spec.LeaderElectionStrategy == string(appsv1beta2.ElectionStrategyWeighted) &&
+					!reflect.DeepEqual(oldPool.Spec.LeaderElectionScoring, newPool.Spec.LeaderElectionScoring)) {
 				return true
 
 			}
@@ -137,6 +140,7 @@ type ReconcileHubLeader struct {
 
 // +kubebuilder:rbac:groups=apps.openyurt.io,resources=nodepools,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.openyurt.io,resources=nodepools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a HubLeader object and makes changes based on the state read
 // and what is in the HubLeader.Spec
@@ -212,6 +216,10 @@ func (r *ReconcileHubLeader) reconcileHubLeader(ctx context.Context, nodepool *a
 		}] = &n
 	}
 
+	if nodepool.Spec.LeaderElectionStrategy == string(appsv1beta2.ElectionStrategyWeighted) {
+		return r.reconcileWeightedLeaders(ctx, nodepool, leadersMap)
+	}
+
 	// Delete leaders that are not in leaders map
 	// They are either not ready or not longer the node list and need to be removed
 	leaderDeleteFn := func(leader appsv1beta2.Leader) bool {
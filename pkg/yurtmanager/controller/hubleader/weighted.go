@@ -0,0 +1,258 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hubleader
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	nodeutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/node"
+	nodepoolutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/nodepool"
+)
+
+const (
+	// nodeLeaseNamespace is the namespace of node leases, yurthub reports the cloud latency in them.
+	nodeLeaseNamespace = corev1.NamespaceNodeLease
+
+	// fullReadinessDuration is the ready duration for which a node gets the full ReadinessWeight
+	fullReadinessDuration = 24 * time.Hour
+	// latencyUnit is the unit of network latency penalized by LatencyWeight
+	latencyUnit = 100 * time.Millisecond
+
+	leadersElectedReason         = "LeadersElected"
+	insufficientCandidatesReason = "InsufficientCandidates"
+)
+
+// candidate is a ready node which can be elected as leader by weighted strategy
+type candidate struct {
+	leader appsv1beta2.Leader
+	score  float64
+	// domain is the failure domain of node, it's the value of label TopologyKey
+	domain string
+	// current is true if the node is a leader now
+	current bool
+}
+
+// effectiveScore returns the score with stickiness bonus for current leaders
+func (c *candidate) effectiveScore(stickinessPercent int32) float64 {
+	if !c.current {
+		return c.score
+	}
+	return c.score + math.Abs(c.score)*float64(stickinessPercent)/100
+}
+
+func (c *candidate) String() string {
+	if len(c.domain) == 0 {
+		return fmt.Sprintf("%s(score %.0f)", c.leader.NodeName, c.score)
+	}
+	return fmt.Sprintf("%s(score %.0f, domain %s)", c.leader.NodeName, c.score, c.domain)
+}
+
+// reconcileWeightedLeaders elects leaders from ready nodes of the pool by their scores,
+// and records the scores of the elected leaders in condition LeaderElectionScored.
+func (r *ReconcileHubLeader) reconcileWeightedLeaders(
+	ctx context.Context,
+	nodepool *appsv1beta2.NodePool,
+	candidateNodes map[appsv1beta2.Leader]*corev1.Node,
+) error {
+	scoring := nodepool.Spec.LeaderElectionScoring
+	if scoring == nil {
+		scoring = appsv1beta2.DefaultLeaderElectionScoring()
+	}
+
+	now := time.Now()
+	currentLeaders := sets.New(nodepool.Status.LeaderEndpoints...)
+	candidates := make([]*candidate, 0, len(candidateNodes))
+	for leader, node := range candidateNodes {
+		latency, err := r.getCloudLatency(ctx, node.Name)
+		if err != nil {
+			return err
+		}
+
+		c := &candidate{
+			leader:  leader,
+			score:   scoreNode(scoring, node, latency, now),
+			current: currentLeaders.Has(leader),
+		}
+		if len(scoring.TopologyKey) != 0 {
+			c.domain = node.Labels[scoring.TopologyKey]
+		}
+		candidates = append(candidates, c)
+	}
+
+	replicas := int(nodepool.Spec.LeaderReplicas)
+	elected := electWeightedLeaders(replicas, scoring.StickinessPercent, candidates)
+
+	updatedNodePool := nodepool.DeepCopy()
+	updatedNodePool.Status.LeaderEndpoints = make([]appsv1beta2.Leader, 0, len(elected))
+	for _, c := range elected {
+		updatedNodePool.Status.LeaderEndpoints = append(updatedNodePool.Status.LeaderEndpoints, c.leader)
+	}
+
+	leadersChanged := nodepoolutil.HasSliceContentChanged(nodepool.Status.LeaderEndpoints, updatedNodePool.Status.LeaderEndpoints)
+	conditionChanged := nodepoolutil.SetNodePoolCondition(&updatedNodePool.Status, newScoredCondition(replicas, elected, candidates))
+	if !leadersChanged && !conditionChanged {
+		return nil
+	}
+
+	if leadersChanged {
+		klog.Infof("NodePool %s elects leaders %v by weighted strategy", nodepool.Name, elected)
+		updatedNodePool.Status.LeaderLastElectedTime = metav1.Now()
+		updatedNodePool.Status.LeaderNum = int32(len(elected))
+	}
+	if err := r.Status().Update(ctx, updatedNodePool); err != nil {
+		klog.ErrorS(err, "Update NodePool status error", "nodepool", updatedNodePool.Name)
+		return err
+	}
+	return nil
+}
+
+// getCloudLatency returns the network latency between the node and cloud reported by yurthub,
+// zero is returned if the latency is not reported. The node lease is read from the manager cache,
+// which only holds the leases in kube-node-lease and raven working namespace, rather than all leases.
+func (r *ReconcileHubLeader) getCloudLatency(ctx context.Context, nodeName string) (time.Duration, error) {
+	lease := &coordinationv1.Lease{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: nodeLeaseNamespace, Name: nodeName}, lease); err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	v, ok := lease.Annotations[apps.AnnotationCloudLatency]
+	if !ok {
+		return 0, nil
+	}
+	ms, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || ms < 0 {
+		klog.Warningf("invalid cloud latency %q in lease of node %s, ignore it", v, nodeName)
+		return 0, nil
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// scoreNode calculates the score of node, see LeaderElectionScoring for the formula.
+func scoreNode(scoring *appsv1beta2.LeaderElectionScoring, node *corev1.Node, latency time.Duration, now time.Time) float64 {
+	score := float64(scoring.CPUWeight) * node.Status.Allocatable.Cpu().AsApproximateFloat64()
+	score += float64(scoring.MemoryWeight) * node.Status.Allocatable.Memory().AsApproximateFloat64() / (1 << 30)
+
+	for _, lw := range scoring.LabelWeights {
+		if v, ok := node.Labels[lw.Key]; ok && (len(lw.Value) == 0 || v == lw.Value) {
+			score += float64(lw.Weight)
+		}
+	}
+
+	if _, cond := nodeutil.GetNodeCondition(&node.Status, corev1.NodeReady); cond != nil && cond.Status == corev1.ConditionTrue {
+		readiness := float64(now.Sub(cond.LastTransitionTime.Time)) / float64(fullReadinessDuration)
+		score += float64(scoring.ReadinessWeight) * math.Max(0, math.Min(1, readiness))
+	}
+
+	score -= float64(scoring.LatencyWeight) * float64(latency) / float64(latencyUnit)
+	return score
+}
+
+// electWeightedLeaders elects n leaders with the highest scores. One leader is elected from each
+// failure domain first, then the remaining leaders are elected by scores regardless of domains.
+func electWeightedLeaders(n int, stickinessPercent int32, candidates []*candidate) []*candidate {
+	sorted := slices.Clone(candidates)
+	slices.SortStableFunc(sorted, func(a, b *candidate) int {
+		if c := cmp.Compare(b.effectiveScore(stickinessPercent), a.effectiveScore(stickinessPercent)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.leader.NodeName, b.leader.NodeName)
+	})
+
+	elected := make([]*candidate, 0, n)
+	electedNodes := sets.New[string]()
+	domains := sets.New[string]()
+	for _, c := range sorted {
+		if len(elected) >= n {
+			break
+		}
+		if len(c.domain) == 0 || domains.Has(c.domain) {
+			continue
+		}
+		domains.Insert(c.domain)
+		electedNodes.Insert(c.leader.NodeName)
+		elected = append(elected, c)
+	}
+
+	for _, c := range sorted {
+		if len(elected) >= n {
+			break
+		}
+		if electedNodes.Has(c.leader.NodeName) {
+			continue
+		}
+		electedNodes.Insert(c.leader.NodeName)
+		elected = append(elected, c)
+	}
+	return elected
+}
+
+// newScoredCondition returns condition LeaderElectionScored which records the elected leaders
+// and the best candidate which is not elected.
+func newScoredCondition(replicas int, elected, candidates []*candidate) appsv1beta2.NodePoolCondition {
+	cond := appsv1beta2.NodePoolCondition{
+		Type:   appsv1beta2.LeaderElectionScored,
+		Status: corev1.ConditionTrue,
+		Reason: leadersElectedReason,
+	}
+	if len(elected) < replicas {
+		cond.Status = corev1.ConditionFalse
+		cond.Reason = insufficientCandidatesReason
+	}
+
+	names := make([]string, 0, len(elected))
+	electedNodes := sets.New[string]()
+	for _, c := range elected {
+		names = append(names, c.String())
+		electedNodes.Insert(c.leader.NodeName)
+	}
+	cond.Message = fmt.Sprintf("elected %d/%d leaders: [%s]", len(elected), replicas, strings.Join(names, ", "))
+
+	var standby *candidate
+	for _, c := range candidates {
+		if electedNodes.Has(c.leader.NodeName) {
+			continue
+		}
+		if standby == nil || c.score > standby.score ||
+			(c.score == standby.score && c.leader.NodeName < standby.leader.NodeName) {
+			standby = c
+		}
+	}
+	if standby != nil {
+		cond.Message += fmt.Sprintf(", best standby: %s", standby)
+	}
+	return cond
+}
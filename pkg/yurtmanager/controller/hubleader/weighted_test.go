@@ -0,0 +1,252 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hubleader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openyurtio/openyurt/pkg/apis"
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+)
+
+func newScoredNode(name, ip, rack, cpu, memory string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				projectinfo.GetNodePoolLabel(): "factory",
+				"rack":                         rack,
+			},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: ip}},
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:               corev1.NodeReady,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-48 * time.Hour)),
+				},
+			},
+		},
+	}
+}
+
+func TestScoreNode(t *testing.T) {
+	now := time.Now()
+	scoring := &appsv1beta2.LeaderElectionScoring{
+		CPUWeight:       10,
+		MemoryWeight:    2,
+		LabelWeights:    []appsv1beta2.LabelWeight{{Key: "rack", Value: "a", Weight: 5}, {Key: "ssd", Weight: 3}},
+		ReadinessWeight: 20,
+		LatencyWeight:   10,
+	}
+
+	node := newScoredNode("node1", "10.0.0.1", "a", "4", "8Gi")
+	// 10*4 + 2*8 + 5 + 20
+	require.InDelta(t, 81, scoreNode(scoring, node, 0, now), 0.001)
+	// latency of 250ms is penalized by 25
+	require.InDelta(t, 56, scoreNode(scoring, node, 250*time.Millisecond, now), 0.001)
+
+	// node ready for 6 hours only gets a quarter of readiness weight
+	node.Status.Conditions[0].LastTransitionTime = metav1.NewTime(now.Add(-6 * time.Hour))
+	node.Labels["ssd"] = "true"
+	require.InDelta(t, 69, scoreNode(scoring, node, 0, now), 0.001)
+}
+
+func TestElectWeightedLeaders(t *testing.T) {
+	newCandidate := func(name, domain string, score float64, current bool) *candidate {
+		return &candidate{leader: appsv1beta2.Leader{NodeName: name}, domain: domain, score: score, current: current}
+	}
+
+	testCases := map[string]struct {
+		replicas   int
+		stickiness int32
+		candidates []*candidate
+		expected   []string
+	}{
+		"elect by score": {
+			replicas: 2,
+			candidates: []*candidate{
+				newCandidate("a", "", 10, false),
+				newCandidate("b", "", 30, false),
+				newCandidate("c", "", 20, false),
+			},
+			expected: []string{"b", "c"},
+		},
+		"spread across domains": {
+			replicas: 2,
+			candidates: []*candidate{
+				newCandidate("a", "rack1", 30, false),
+				newCandidate("b", "rack1", 20, false),
+				newCandidate("c", "rack2", 10, false),
+			},
+			expected: []string{"a", "c"},
+		},
+		"fill remaining leaders regardless of domains": {
+			replicas: 3,
+			candidates: []*candidate{
+				newCandidate("a", "rack1", 30, false),
+				newCandidate("b", "rack1", 20, false),
+				newCandidate("c", "rack2", 10, false),
+			},
+			expected: []string{"a", "c", "b"},
+		},
+		"current leader is kept by stickiness": {
+			replicas:   1,
+			stickiness: 20,
+			candidates: []*candidate{
+				newCandidate("a", "", 100, true),
+				newCandidate("b", "", 110, false),
+			},
+			expected: []string{"a"},
+		},
+		"current leader is replaced by much better candidate": {
+			replicas:   1,
+			stickiness: 20,
+			candidates: []*candidate{
+				newCandidate("a", "", 100, true),
+				newCandidate("b", "", 130, false),
+			},
+			expected: []string{"b"},
+		},
+		"not enough candidates": {
+			replicas:   3,
+			candidates: []*candidate{newCandidate("a", "", 1, false)},
+			expected:   []string{"a"},
+		},
+	}
+
+	for k, tc := range testCases {
+		t.Run(k, func(t *testing.T) {
+			elected := electWeightedLeaders(tc.replicas, tc.stickiness, tc.candidates)
+			names := make([]string, 0, len(elected))
+			for _, c := range elected {
+				names = append(names, c.leader.NodeName)
+			}
+			require.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestReconcileWeighted(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, apis.AddToScheme(scheme))
+
+	nodes := []client.Object{
+		newScoredNode("arm", "10.0.0.1", "rack1", "2", "2Gi"),
+		newScoredNode("x86-big", "10.0.0.2", "rack1", "16", "32Gi"),
+		newScoredNode("x86-far", "10.0.0.3", "rack2", "16", "32Gi"),
+		newScoredNode("x86-small", "10.0.0.4", "rack2", "8", "16Gi"),
+		// lease of x86-far reports 3s cloud latency
+		&coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   nodeLeaseNamespace,
+				Name:        "x86-far",
+				Annotations: map[string]string{apps.AnnotationCloudLatency: "3000"},
+			},
+		},
+	}
+	pool := &appsv1beta2.NodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "factory"},
+		Spec: appsv1beta2.NodePoolSpec{
+			Type:                   appsv1beta2.Edge,
+			EnableLeaderElection:   true,
+			LeaderElectionStrategy: string(appsv1beta2.ElectionStrategyWeighted),
+			LeaderElectionScoring: &appsv1beta2.LeaderElectionScoring{
+				CPUWeight:         10,
+				MemoryWeight:      2,
+				ReadinessWeight:   20,
+				LatencyWeight:     10,
+				TopologyKey:       "rack",
+				StickinessPercent: 20,
+			},
+			LeaderReplicas: 2,
+		},
+	}
+
+	c := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pool).
+		WithStatusSubresource(pool, &corev1.Node{}).
+		WithObjects(nodes...).
+		Build()
+	r := &ReconcileHubLeader{
+		Client:   c,
+		recorder: record.NewFakeRecorder(1000),
+	}
+	ctx := context.TODO()
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: pool.Name}}
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	var actualPool appsv1beta2.NodePool
+	require.NoError(t, r.Get(ctx, req.NamespacedName, &actualPool))
+	require.Equal(t, []appsv1beta2.Leader{
+		{NodeName: "x86-big", Address: "10.0.0.2"},
+		{NodeName: "x86-small", Address: "10.0.0.4"},
+	}, actualPool.Status.LeaderEndpoints)
+	require.Equal(t, int32(2), actualPool.Status.LeaderNum)
+	require.Len(t, actualPool.Status.Conditions, 1)
+	cond := actualPool.Status.Conditions[0]
+	require.Equal(t, appsv1beta2.LeaderElectionScored, cond.Type)
+	require.Equal(t, corev1.ConditionTrue, cond.Status)
+	require.Equal(t, "elected 2/2 leaders: [x86-big(score 244, domain rack1), x86-small(score 132, domain rack2)], "+
+		"best standby: arm(score 44, domain rack1)", cond.Message)
+
+	// a little better node joins, current leaders are kept by stickiness
+	newNode := newScoredNode("x86-new", "10.0.0.5", "rack2", "9", "16Gi")
+	require.NoError(t, c.Create(ctx, newNode))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, r.Get(ctx, req.NamespacedName, &actualPool))
+	require.Equal(t, "x86-small", actualPool.Status.LeaderEndpoints[1].NodeName)
+
+	// leader becomes not ready, the best candidate in the same domain is elected
+	leader := &corev1.Node{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "x86-small"}, leader))
+	leader.Status.Conditions[0].Status = corev1.ConditionFalse
+	require.NoError(t, c.Status().Update(ctx, leader))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, r.Get(ctx, req.NamespacedName, &actualPool))
+	require.Equal(t, []appsv1beta2.Leader{
+		{NodeName: "x86-big", Address: "10.0.0.2"},
+		{NodeName: "x86-new", Address: "10.0.0.5"},
+	}, actualPool.Status.LeaderEndpoints)
+}
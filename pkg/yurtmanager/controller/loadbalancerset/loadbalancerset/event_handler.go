@@ -18,8 +18,10 @@ package loadbalancerset
 
 import (
 	"context"
+	"reflect"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
@@ -32,6 +34,7 @@ import (
 	"github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
 	"github.com/openyurtio/openyurt/pkg/apis/network"
 	"github.com/openyurtio/openyurt/pkg/apis/network/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
 )
 
 func NewPoolServiceEventHandler() handler.EventHandler {
@@ -147,3 +150,83 @@ func nodePoolRelatedServiceEnqueue(c client.Client, object client.Object, q work
 		handlePoolServiceNormal(&item, q)
 	}
 }
+
+// NewNodeEventHandler enqueues the services of the nodepool when a node of it changes its readiness,
+// so that the virtual ips announced by a not ready node are taken over by other nodes.
+func NewNodeEventHandler(c client.Client) handler.EventHandler {
+	return handler.Funcs{
+		UpdateFunc: func(ctx context.Context, updateEvent event.UpdateEvent, limitingInterface workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			oldNode, ok := updateEvent.ObjectOld.(*v1.Node)
+			if !ok {
+				return
+			}
+			newNode, ok := updateEvent.ObjectNew.(*v1.Node)
+			if !ok {
+				return
+			}
+			if isNodeReady(oldNode) == isNodeReady(newNode) {
+				return
+			}
+			nodeRelatedServiceEnqueue(c, newNode, limitingInterface)
+		},
+		DeleteFunc: func(ctx context.Context, deleteEvent event.DeleteEvent, limitingInterface workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			nodeRelatedServiceEnqueue(c, deleteEvent.Object, limitingInterface)
+		},
+	}
+}
+
+// nodeRelatedServiceEnqueue enqueues the services which have pool services in the nodepool of node.
+func nodeRelatedServiceEnqueue(c client.Client, object client.Object, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	poolName := object.GetLabels()[projectinfo.GetNodePoolLabel()]
+	if len(poolName) == 0 {
+		return
+	}
+	nodePoolRelatedServiceEnqueue(c, &v1beta2.NodePool{ObjectMeta: metav1.ObjectMeta{Name: poolName}}, q)
+}
+
+func NewAddressPoolEventHandler(c client.Client) handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, createEvent event.CreateEvent, limitingInterface workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			addressPoolRelatedServiceEnqueue(c, createEvent.Object, limitingInterface)
+		},
+		UpdateFunc: func(ctx context.Context, updateEvent event.UpdateEvent, limitingInterface workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			oldAp := updateEvent.ObjectOld.(*v1alpha1.AddressPool)
+			newAp := updateEvent.ObjectNew.(*v1alpha1.AddressPool)
+			if oldAp.Spec.NodePool == newAp.Spec.NodePool && reflect.DeepEqual(oldAp.Spec.Addresses, newAp.Spec.Addresses) {
+				return
+			}
+			addressPoolRelatedServiceEnqueue(c, oldAp, limitingInterface)
+			addressPoolRelatedServiceEnqueue(c, newAp, limitingInterface)
+		},
+		DeleteFunc: func(ctx context.Context, deleteEvent event.DeleteEvent, limitingInterface workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			addressPoolRelatedServiceEnqueue(c, deleteEvent.Object, limitingInterface)
+		},
+		GenericFunc: func(ctx context.Context, genericEvent event.GenericEvent, limitingInterface workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			addressPoolRelatedServiceEnqueue(c, genericEvent.Object, limitingInterface)
+		},
+	}
+}
+
+// addressPoolRelatedServiceEnqueue enqueues the services which are allocated virtual ips in the nodepool of address pool.
+func addressPoolRelatedServiceEnqueue(c client.Client, object client.Object, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	ap, ok := object.(*v1alpha1.AddressPool)
+	if !ok {
+		return
+	}
+	poolServiceList := &v1alpha1.PoolServiceList{}
+
+	listSelector := client.MatchingLabels{
+		network.LabelNodePoolName: ap.Spec.NodePool,
+		labelManageBy:             names.LoadBalancerSetController}
+
+	if err := c.List(context.Background(), poolServiceList, listSelector); err != nil {
+		return
+	}
+
+	for _, item := range poolServiceList.Items {
+		if !isVIPPoolService(&item) {
+			continue
+		}
+		handlePoolServiceNormal(&item, q)
+	}
+}
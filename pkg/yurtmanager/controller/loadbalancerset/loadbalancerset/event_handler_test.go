@@ -30,6 +30,7 @@ import (
 	"github.com/openyurtio/openyurt/cmd/yurt-manager/names"
 	"github.com/openyurtio/openyurt/pkg/apis/network"
 	"github.com/openyurtio/openyurt/pkg/apis/network/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
 )
 
 func TestPoolServiceEventHandler(t *testing.T) {
@@ -205,3 +206,36 @@ func TestNodePoolEventHandler(t *testing.T) {
 	})
 
 }
+
+func TestNodeEventHandler(t *testing.T) {
+	scheme := initScheme(t)
+	ps1 := newPoolServiceWithServiceNameAndNodepoolName("mock1", "np123")
+	ps2 := newPoolServiceWithServiceNameAndNodepoolName("mock2", "np234")
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(ps1, ps2).Build()
+	f := NewNodeEventHandler(c)
+
+	readyNode := newReadyNode("node-a", true)
+	readyNode.Labels = map[string]string{projectinfo.GetNodePoolLabel(): "np123"}
+	notReadyNode := newReadyNode("node-a", false)
+	notReadyNode.Labels = map[string]string{projectinfo.GetNodePoolLabel(): "np123"}
+
+	t.Run("readiness change enqueue services of nodepool", func(t *testing.T) {
+		q := workqueue.NewTypedRateLimitingQueueWithConfig[reconcile.Request](workqueue.DefaultTypedControllerRateLimiter[reconcile.Request](), workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{Name: "pool_services"})
+
+		f.Update(context.Background(), event.UpdateEvent{ObjectOld: readyNode, ObjectNew: notReadyNode}, q)
+		assertAndDoneQueue(t, q, []string{v1.NamespaceDefault + "/" + "mock1"})
+
+		f.Delete(context.Background(), event.DeleteEvent{Object: readyNode}, q)
+		assertAndDoneQueue(t, q, []string{v1.NamespaceDefault + "/" + "mock1"})
+	})
+
+	t.Run("readiness not change not enqueue", func(t *testing.T) {
+		q := workqueue.NewTypedRateLimitingQueueWithConfig[reconcile.Request](workqueue.DefaultTypedControllerRateLimiter[reconcile.Request](), workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{Name: "pool_services"})
+
+		f.Update(context.Background(), event.UpdateEvent{ObjectOld: readyNode, ObjectNew: readyNode.DeepCopy()}, q)
+		assertAndDoneQueue(t, q, []string{})
+
+		f.Delete(context.Background(), event.DeleteEvent{Object: newReadyNode("node-b", true)}, q)
+		assertAndDoneQueue(t, q, []string{})
+	})
+}
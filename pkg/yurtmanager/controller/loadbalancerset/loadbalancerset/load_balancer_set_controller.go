@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -86,17 +87,22 @@ var _ reconcile.Reconciler = &ReconcileLoadBalancerSet{}
 // ReconcileLoadBalancerSet reconciles a PoolService object
 type ReconcileLoadBalancerSet struct {
 	client.Client
-	scheme   *runtime.Scheme
-	recorder record.EventRecorder
-	mapper   meta.RESTMapper
+	// apiReader reads PoolServices from kube-apiserver directly when virtual IPs are allocated, because
+	// the allocations made by the previous reconciles may not be synced to the cache yet.
+	apiReader client.Reader
+	scheme    *runtime.Scheme
+	recorder  record.EventRecorder
+	mapper    meta.RESTMapper
 
 	configuration config.LoadBalancerSetControllerConfiguration
+	vipLock       sync.Mutex
 }
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(c *appconfig.CompletedConfig, mgr manager.Manager) *ReconcileLoadBalancerSet {
 	return &ReconcileLoadBalancerSet{
 		Client:        yurtClient.GetClientByControllerNameOrDie(mgr, names.LoadBalancerSetController),
+		apiReader:     mgr.GetAPIReader(),
 		scheme:        mgr.GetScheme(),
 		mapper:        mgr.GetRESTMapper(),
 		recorder:      mgr.GetEventRecorderFor(names.LoadBalancerSetController),
@@ -151,6 +157,28 @@ func add(mgr manager.Manager, cfg *appconfig.CompletedConfig, r reconcile.Reconc
 		return err
 	}
 
+	err = c.Watch(
+		source.Kind[client.Object](
+			mgr.GetCache(),
+			&corev1.Node{},
+			NewNodeEventHandler(yurtClient.GetClientByControllerNameOrDie(mgr, names.LoadBalancerSetController)),
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		source.Kind[client.Object](
+			mgr.GetCache(),
+			&netv1alpha1.AddressPool{},
+			NewAddressPoolEventHandler(yurtClient.GetClientByControllerNameOrDie(mgr, names.LoadBalancerSetController)),
+		),
+	)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -158,6 +186,10 @@ func add(mgr manager.Manager, cfg *appconfig.CompletedConfig, r reconcile.Reconc
 // +kubebuilder:rbac:groups=core,resources=services/status,verbs=update
 // +kubebuilder:rbac:groups=network.openyurt.io,resources=poolservices,verbs=get;create;update;patch;delete
 // +kubebuilder:rbac:groups=network.openyurt.io,resources=poolservices/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=network.openyurt.io,resources=addresspools,verbs=get
+// +kubebuilder:rbac:groups=network.openyurt.io,resources=addresspools/status,verbs=get;update
+// +kubebuilder:rbac:groups=apps.openyurt.io,resources=nodepools,verbs=get
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get
 
 // Reconcile reads that state of the cluster for a PoolService object and makes changes based on the state read
 // and what is in the PoolService.Spec
@@ -190,6 +222,12 @@ func (r *ReconcileLoadBalancerSet) Reconcile(_ context.Context, request reconcil
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile service")
 	}
 
+	if isVIPService(copySvc) {
+		if err := r.allocateVIPs(copySvc); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "failed to allocate virtual ips")
+		}
+	}
+
 	if err := r.syncService(copySvc); err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "failed to sync service %s/%s", copySvc.Namespace, copySvc.Name)
 	}
@@ -255,6 +255,13 @@ func isNodePoolChange(oldNp, newNp *v1beta2.NodePool) bool {
 	if !reflect.DeepEqual(oldNp.Labels, newNp.Labels) {
 		return true
 	}
+	// nodes to announce virtual ips are elected from ready leaders or ready nodes of the nodepool
+	if !reflect.DeepEqual(oldNp.Status.LeaderEndpoints, newNp.Status.LeaderEndpoints) ||
+		!reflect.DeepEqual(oldNp.Status.Nodes, newNp.Status.Nodes) ||
+		oldNp.Status.ReadyNodeNum != newNp.Status.ReadyNodeNum ||
+		oldNp.Status.UnreadyNodeNum != newNp.Status.UnreadyNodeNum {
+		return true
+	}
 	return false
 }
 
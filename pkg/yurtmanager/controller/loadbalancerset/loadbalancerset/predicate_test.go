@@ -261,6 +261,15 @@ func TestNodePoolPredicated(t *testing.T) {
 		assertBool(t, true, f.Update(event.UpdateEvent{ObjectOld: np1, ObjectNew: np2}))
 	})
 
+	t.Run("update nodepool ready nodes predicated", func(t *testing.T) {
+		np1 := newNodepool("np123", "name=np124")
+		np1.Status.ReadyNodeNum = 2
+		np2 := newNodepool("np123", "name=np124")
+		np2.Status.ReadyNodeNum = 1
+		np2.Status.UnreadyNodeNum = 1
+		assertBool(t, true, f.Update(event.UpdateEvent{ObjectOld: np1, ObjectNew: np2}))
+	})
+
 	t.Run("update nodepool not predicated", func(t *testing.T) {
 		np1 := newNodepool("np123", "name=np124")
 		np2 := newNodepool("np123", "name=np124")
@@ -0,0 +1,413 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancerset
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/apis/network"
+	netv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/network/v1alpha1"
+)
+
+const (
+	// conditionTypeVIPAllocated is the condition type of PoolService which indicates whether the virtual IP is allocated.
+	conditionTypeVIPAllocated = "VIPAllocated"
+
+	reasonVIPAllocated      = "Allocated"
+	reasonNoAddressPool     = "NoAddressPool"
+	reasonAddressExhausted  = "AddressPoolExhausted"
+	reasonVIPNodeNotFound   = "NoAvailableNode"
+	poolServiceVIPConflict  = "VIPConflict"
+	vipConflictEventMessage = "The virtual IP %s of PoolService %s/%s is also allocated to PoolService %s, reallocate it"
+)
+
+// isVIPService checks if the PoolServices of service are allocated virtual IPs by the controller.
+func isVIPService(svc *corev1.Service) bool {
+	return svc.Spec.LoadBalancerClass != nil && *svc.Spec.LoadBalancerClass == network.VIPLoadBalancerClass
+}
+
+func isVIPPoolService(ps *netv1alpha1.PoolService) bool {
+	return ps.Spec.LoadBalancerClass != nil && *ps.Spec.LoadBalancerClass == network.VIPLoadBalancerClass
+}
+
+// allocateVIPs allocates virtual IPs from the AddressPools of NodePool for PoolServices of the service, and elects
+// the node to announce the virtual IP in each NodePool.
+func (r *ReconcileLoadBalancerSet) allocateVIPs(svc *corev1.Service) error {
+	// virtual IPs of the NodePool are shared by all services, so allocations are serialized
+	r.vipLock.Lock()
+	defer r.vipLock.Unlock()
+
+	poolServices, err := r.currentPoolServices(svc)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get current pool services for service %s/%s", svc.Namespace, svc.Name)
+	}
+
+	for i := range poolServices {
+		ps := &poolServices[i]
+		if !ps.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.allocateVIP(ps); err != nil {
+			return errors.Wrapf(err, "failed to allocate virtual ip for pool service %s/%s", ps.Namespace, ps.Name)
+		}
+	}
+	return r.syncAddressPoolsStatus()
+}
+
+func (r *ReconcileLoadBalancerSet) allocateVIP(ps *netv1alpha1.PoolService) error {
+	poolName := ps.Labels[network.LabelNodePoolName]
+	addresses, err := r.nodePoolAddresses(poolName)
+	if err != nil {
+		return err
+	}
+	owners, err := r.allocatedVIPs(poolName)
+	if err != nil {
+		return err
+	}
+
+	key := ps.Namespace + "/" + ps.Name
+	isUsed := func(ip string) bool {
+		owner, ok := owners[ip]
+		return ok && owner != key
+	}
+
+	var vip string
+	if len(ps.Status.LoadBalancer.Ingress) != 0 {
+		vip = ps.Status.LoadBalancer.Ingress[0].IP
+	}
+	if len(vip) != 0 {
+		if !addressesContain(addresses, vip) {
+			vip = ""
+		} else if isUsed(vip) {
+			// the PoolService with the smaller key keeps the virtual IP when it's allocated more than once
+			r.recorder.Eventf(ps, corev1.EventTypeWarning, poolServiceVIPConflict, vipConflictEventMessage, vip, ps.Namespace, ps.Name, owners[vip])
+			vip = ""
+		}
+	}
+	if len(vip) == 0 {
+		vip = nextFreeAddress(addresses, isUsed)
+	}
+
+	node, err := r.electVIPNode(ps, poolName)
+	if err != nil {
+		return err
+	}
+	if ps.Annotations[network.AnnotationVIPNode] != node {
+		if len(node) == 0 {
+			delete(ps.Annotations, network.AnnotationVIPNode)
+		} else {
+			if ps.Annotations == nil {
+				ps.Annotations = make(map[string]string)
+			}
+			ps.Annotations[network.AnnotationVIPNode] = node
+		}
+		if err := r.Update(context.Background(), ps); err != nil {
+			return errors.Wrapf(err, "failed to update virtual ip node of pool service")
+		}
+	}
+
+	condition := v1.Condition{
+		Type:    conditionTypeVIPAllocated,
+		Status:  v1.ConditionTrue,
+		Reason:  reasonVIPAllocated,
+		Message: fmt.Sprintf("virtual ip %s is announced by node %s", vip, node),
+	}
+	var ingress []corev1.LoadBalancerIngress
+	switch {
+	case len(addresses) == 0:
+		condition.Status, condition.Reason = v1.ConditionFalse, reasonNoAddressPool
+		condition.Message = fmt.Sprintf("no address pool for nodepool %s", poolName)
+	case len(vip) == 0:
+		condition.Status, condition.Reason = v1.ConditionFalse, reasonAddressExhausted
+		condition.Message = fmt.Sprintf("address pools for nodepool %s are exhausted", poolName)
+	case len(node) == 0:
+		condition.Status, condition.Reason = v1.ConditionFalse, reasonVIPNodeNotFound
+		condition.Message = fmt.Sprintf("no available node in nodepool %s to announce virtual ip %s", poolName, vip)
+		// keep the virtual ip until a node is available to announce it
+		ingress = []corev1.LoadBalancerIngress{{IP: vip}}
+	default:
+		ingress = []corev1.LoadBalancerIngress{{IP: vip}}
+	}
+	isConditionChanged := meta.SetStatusCondition(&ps.Status.Conditions, condition)
+	if !isConditionChanged && reflect.DeepEqual(ps.Status.LoadBalancer.Ingress, ingress) {
+		return nil
+	}
+	ps.Status.LoadBalancer.Ingress = ingress
+	klog.Info(Format("pool service %s/%s is allocated virtual ip %q on node %q", ps.Namespace, ps.Name, vip, node))
+	return r.Status().Update(context.Background(), ps)
+}
+
+// nodePoolAddresses returns the addresses of all AddressPools for the NodePool.
+func (r *ReconcileLoadBalancerSet) nodePoolAddresses(poolName string) ([]string, error) {
+	addressPoolList := &netv1alpha1.AddressPoolList{}
+	if err := r.List(context.Background(), addressPoolList); err != nil {
+		return nil, errors.Wrapf(err, "failed to list address pools")
+	}
+
+	sort.Slice(addressPoolList.Items, func(i, j int) bool {
+		return addressPoolList.Items[i].Name < addressPoolList.Items[j].Name
+	})
+	var addresses []string
+	for _, ap := range addressPoolList.Items {
+		if ap.Spec.NodePool == poolName && ap.DeletionTimestamp.IsZero() {
+			addresses = append(addresses, ap.Spec.Addresses...)
+		}
+	}
+	return addresses, nil
+}
+
+// allocatedVIPs returns the virtual IPs allocated to PoolServices in the NodePool and their owners. PoolServices
+// are read from kube-apiserver with vipLock held, so the allocations just made are never missed by a lagging cache.
+func (r *ReconcileLoadBalancerSet) allocatedVIPs(poolName string) (map[string]string, error) {
+	poolServiceList := &netv1alpha1.PoolServiceList{}
+	if err := r.apiReader.List(context.Background(), poolServiceList, client.MatchingLabels{network.LabelNodePoolName: poolName}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list pool services of nodepool %s", poolName)
+	}
+
+	owners := make(map[string]string)
+	for _, ps := range poolServiceList.Items {
+		if !isVIPPoolService(&ps) {
+			continue
+		}
+		key := ps.Namespace + "/" + ps.Name
+		for _, ingress := range ps.Status.LoadBalancer.Ingress {
+			if owner, ok := owners[ingress.IP]; !ok || key < owner {
+				owners[ingress.IP] = key
+			}
+		}
+	}
+	return owners, nil
+}
+
+// electVIPNode returns the node to announce the virtual IP of PoolService. Ready leader yurthubs of the NodePool are
+// preferred, otherwise all ready nodes are candidates, and the current node is kept as long as it's a candidate.
+// The virtual IP is taken over by another candidate once its node becomes not ready.
+func (r *ReconcileLoadBalancerSet) electVIPNode(ps *netv1alpha1.PoolService, poolName string) (string, error) {
+	np := &v1beta2.NodePool{}
+	if err := r.Get(context.Background(), types.NamespacedName{Name: poolName}, np); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get nodepool %s", poolName)
+	}
+
+	readyNodes := sets.New[string]()
+	for _, nodeName := range np.Status.Nodes {
+		node := &corev1.Node{}
+		if err := r.Get(context.Background(), types.NamespacedName{Name: nodeName}, node); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return "", errors.Wrapf(err, "failed to get node %s", nodeName)
+		}
+		if isNodeReady(node) {
+			readyNodes.Insert(nodeName)
+		}
+	}
+
+	candidates := sets.New[string]()
+	for _, leader := range np.Status.LeaderEndpoints {
+		if readyNodes.Has(leader.NodeName) {
+			candidates.Insert(leader.NodeName)
+		}
+	}
+	if candidates.Len() == 0 {
+		candidates = readyNodes
+	}
+
+	if current := ps.Annotations[network.AnnotationVIPNode]; candidates.Has(current) {
+		return current, nil
+	}
+	if candidates.Len() == 0 {
+		return "", nil
+	}
+	return sets.List(candidates)[0], nil
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// syncAddressPoolsStatus updates the number of allocated addresses of all AddressPools.
+func (r *ReconcileLoadBalancerSet) syncAddressPoolsStatus() error {
+	addressPoolList := &netv1alpha1.AddressPoolList{}
+	if err := r.List(context.Background(), addressPoolList); err != nil {
+		return errors.Wrapf(err, "failed to list address pools")
+	}
+	if len(addressPoolList.Items) == 0 {
+		return nil
+	}
+	poolServiceList := &netv1alpha1.PoolServiceList{}
+	if err := r.apiReader.List(context.Background(), poolServiceList); err != nil {
+		return errors.Wrapf(err, "failed to list pool services")
+	}
+
+	vips := make(map[string]sets.Set[string])
+	for _, ps := range poolServiceList.Items {
+		if !isVIPPoolService(&ps) {
+			continue
+		}
+		poolName := ps.Labels[network.LabelNodePoolName]
+		if vips[poolName] == nil {
+			vips[poolName] = sets.New[string]()
+		}
+		for _, ingress := range ps.Status.LoadBalancer.Ingress {
+			vips[poolName].Insert(ingress.IP)
+		}
+	}
+
+	for i := range addressPoolList.Items {
+		ap := &addressPoolList.Items[i]
+		var allocated int32
+		for vip := range vips[ap.Spec.NodePool] {
+			if addressesContain(ap.Spec.Addresses, vip) {
+				allocated++
+			}
+		}
+		if ap.Status.Allocated == allocated {
+			continue
+		}
+		ap.Status.Allocated = allocated
+		if err := r.Status().Update(context.Background(), ap); err != nil {
+			return errors.Wrapf(err, "failed to update status of address pool %s", ap.Name)
+		}
+	}
+	return nil
+}
+
+// ParseAddressRange parses an IP address, CIDR or range into the first and last IP addresses, the network and
+// broadcast addresses of IPv4 CIDRs are excluded.
+func ParseAddressRange(address string) (net.IP, net.IP, error) {
+	if ip := net.ParseIP(address); ip != nil {
+		return normalizeIP(ip), normalizeIP(ip), nil
+	}
+
+	if _, cidr, err := net.ParseCIDR(address); err == nil {
+		first := normalizeIP(cidr.IP)
+		last := make(net.IP, len(first))
+		for i := range first {
+			last[i] = first[i] | ^cidr.Mask[i]
+		}
+		if ones, bits := cidr.Mask.Size(); bits == 32 && bits-ones > 1 {
+			first, last = nextIP(first), prevIP(last)
+		}
+		return first, last, nil
+	}
+
+	if parts := strings.Split(address, "-"); len(parts) == 2 {
+		first, last := net.ParseIP(strings.TrimSpace(parts[0])), net.ParseIP(strings.TrimSpace(parts[1]))
+		if first != nil && last != nil {
+			first, last = normalizeIP(first), normalizeIP(last)
+			if len(first) == len(last) && bytes.Compare(first, last) <= 0 {
+				return first, last, nil
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("invalid address %q", address)
+}
+
+// nextFreeAddress returns the first address which is not used, or empty string if all addresses are used.
+func nextFreeAddress(addresses []string, isUsed func(ip string) bool) string {
+	for _, address := range addresses {
+		first, last, err := ParseAddressRange(address)
+		if err != nil {
+			klog.Warning(Format("skip %s", err.Error()))
+			continue
+		}
+		for ip := first; bytes.Compare(ip, last) <= 0; ip = nextIP(ip) {
+			if !isUsed(ip.String()) {
+				return ip.String()
+			}
+			if ip.Equal(last) {
+				break
+			}
+		}
+	}
+	return ""
+}
+
+// addressesContain checks if the ip is in the addresses.
+func addressesContain(addresses []string, ip string) bool {
+	target := net.ParseIP(ip)
+	if target == nil {
+		return false
+	}
+	target = normalizeIP(target)
+	for _, address := range addresses {
+		first, last, err := ParseAddressRange(address)
+		if err != nil || len(first) != len(target) {
+			continue
+		}
+		if bytes.Compare(first, target) <= 0 && bytes.Compare(target, last) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeIP(ip net.IP) net.IP {
+	if v4 := ip.To4(); v4 != nil {
+		return v4
+	}
+	return ip.To16()
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+func prevIP(ip net.IP) net.IP {
+	prev := make(net.IP, len(ip))
+	copy(prev, ip)
+	for i := len(prev) - 1; i >= 0; i-- {
+		prev[i]--
+		if prev[i] != 0xff {
+			break
+		}
+	}
+	return prev
+}
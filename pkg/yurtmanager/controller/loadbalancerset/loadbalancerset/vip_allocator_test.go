@@ -0,0 +1,355 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancerset
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openyurtio/openyurt/cmd/yurt-manager/names"
+	"github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/apis/network"
+	"github.com/openyurtio/openyurt/pkg/apis/network/v1alpha1"
+)
+
+var (
+	vipClass = network.VIPLoadBalancerClass
+)
+
+func newVIPService(name string) *corev1.Service {
+	svc := newService(v1.NamespaceDefault, name)
+	svc.UID = types.UID(name)
+	svc.Spec.LoadBalancerClass = &vipClass
+	return svc
+}
+
+func newAddressPool(name, poolName string, addresses ...string) *v1alpha1.AddressPool {
+	return &v1alpha1.AddressPool{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec:       v1alpha1.AddressPoolSpec{NodePool: poolName, Addresses: addresses},
+	}
+}
+
+func newVIPPoolService(name, poolName, vip string) *v1alpha1.PoolService {
+	ps := &v1alpha1.PoolService{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: v1.NamespaceDefault,
+			Name:      name,
+			Labels: map[string]string{
+				network.LabelNodePoolName: poolName,
+			},
+		},
+		Spec: v1alpha1.PoolServiceSpec{LoadBalancerClass: &vipClass},
+	}
+	if len(vip) != 0 {
+		ps.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: vip}}
+	}
+	return ps
+}
+
+func newReadyNode(name string, ready bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func TestReconcileLoadBalancerSet_AllocateVIPs(t *testing.T) {
+	np := newNodepool("np123", "name=np123,app=deploy")
+	np.Status.Nodes = []string{"node-b", "node-a", "node-c"}
+
+	testcases := map[string]struct {
+		leaders         []v1beta2.Leader
+		objs            []client.Object
+		currentVIP      string
+		currentNode     string
+		wantedVIP       string
+		wantedNode      string
+		wantedReason    string
+		wantedAllocated int32
+	}{
+		"no address pool for nodepool": {
+			objs:         []client.Object{newAddressPool("ap", "np234", "10.0.0.1")},
+			wantedNode:   "node-a",
+			wantedReason: reasonNoAddressPool,
+		},
+		"allocate the first address of cidr": {
+			objs:            []client.Object{newAddressPool("ap", "np123", "10.0.0.0/30")},
+			wantedVIP:       "10.0.0.1",
+			wantedNode:      "node-a",
+			wantedReason:    reasonVIPAllocated,
+			wantedAllocated: 1,
+		},
+		"allocate address of range": {
+			objs:            []client.Object{newAddressPool("ap", "np123", "10.0.0.5-10.0.0.6")},
+			wantedVIP:       "10.0.0.5",
+			wantedNode:      "node-a",
+			wantedReason:    reasonVIPAllocated,
+			wantedAllocated: 1,
+		},
+		"keep the allocated address": {
+			objs:            []client.Object{newAddressPool("ap", "np123", "10.0.0.5-10.0.0.6")},
+			currentVIP:      "10.0.0.6",
+			wantedVIP:       "10.0.0.6",
+			wantedNode:      "node-a",
+			wantedReason:    reasonVIPAllocated,
+			wantedAllocated: 1,
+		},
+		"reallocate the address which is not in pool": {
+			objs:            []client.Object{newAddressPool("ap", "np123", "10.0.0.5")},
+			currentVIP:      "10.0.0.6",
+			wantedVIP:       "10.0.0.5",
+			wantedNode:      "node-a",
+			wantedReason:    reasonVIPAllocated,
+			wantedAllocated: 1,
+		},
+		"skip the address used by other pool service": {
+			objs: []client.Object{
+				newAddressPool("ap", "np123", "10.0.0.5-10.0.0.6"),
+				newVIPPoolService("other-np123", "np123", "10.0.0.5"),
+			},
+			wantedVIP:       "10.0.0.6",
+			wantedNode:      "node-a",
+			wantedReason:    reasonVIPAllocated,
+			wantedAllocated: 2,
+		},
+		"reallocate the conflicted address owned by pool service with smaller name": {
+			objs: []client.Object{
+				newAddressPool("ap", "np123", "10.0.0.5-10.0.0.6"),
+				newVIPPoolService("a-np123", "np123", "10.0.0.5"),
+			},
+			currentVIP:      "10.0.0.5",
+			wantedVIP:       "10.0.0.6",
+			wantedNode:      "node-a",
+			wantedReason:    reasonVIPAllocated,
+			wantedAllocated: 2,
+		},
+		"address pool is exhausted": {
+			objs: []client.Object{
+				newAddressPool("ap", "np123", "10.0.0.5"),
+				newVIPPoolService("other-np123", "np123", "10.0.0.5"),
+			},
+			wantedNode:      "node-a",
+			wantedReason:    reasonAddressExhausted,
+			wantedAllocated: 1,
+		},
+		"prefer leader nodes": {
+			leaders:         []v1beta2.Leader{{NodeName: "node-c", Address: "192.168.0.3"}},
+			objs:            []client.Object{newAddressPool("ap", "np123", "10.0.0.1")},
+			wantedVIP:       "10.0.0.1",
+			wantedNode:      "node-c",
+			wantedReason:    reasonVIPAllocated,
+			wantedAllocated: 1,
+		},
+		"skip the not ready leader": {
+			leaders:         []v1beta2.Leader{{NodeName: "node-b", Address: "192.168.0.2"}},
+			objs:            []client.Object{newAddressPool("ap", "np123", "10.0.0.1")},
+			currentNode:     "node-b",
+			wantedVIP:       "10.0.0.1",
+			wantedNode:      "node-a",
+			wantedReason:    reasonVIPAllocated,
+			wantedAllocated: 1,
+		},
+		"keep the current node": {
+			objs:            []client.Object{newAddressPool("ap", "np123", "10.0.0.1")},
+			currentNode:     "node-c",
+			wantedVIP:       "10.0.0.1",
+			wantedNode:      "node-c",
+			wantedReason:    reasonVIPAllocated,
+			wantedAllocated: 1,
+		},
+		"move from the not ready node": {
+			objs:            []client.Object{newAddressPool("ap", "np123", "10.0.0.1")},
+			currentNode:     "node-b",
+			wantedVIP:       "10.0.0.1",
+			wantedNode:      "node-a",
+			wantedReason:    reasonVIPAllocated,
+			wantedAllocated: 1,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			svc := newVIPService(mockServiceName)
+			nodePool := np.DeepCopy()
+			nodePool.Status.LeaderEndpoints = tc.leaders
+			ps := newVIPPoolService(mockServiceName+"-np123", "np123", tc.currentVIP)
+			ps.Labels[network.LabelServiceName] = mockServiceName
+			ps.Labels[labelManageBy] = names.LoadBalancerSetController
+			if len(tc.currentNode) != 0 {
+				ps.Annotations = map[string]string{network.AnnotationVIPNode: tc.currentNode}
+			}
+
+			objs := append(tc.objs, svc, nodePool, ps, newReadyNode("node-a", true), newReadyNode("node-b", false), newReadyNode("node-c", true))
+			c := fakeclient.NewClientBuilder().
+				WithScheme(initScheme(t)).
+				WithObjects(objs...).
+				WithStatusSubresource(&v1alpha1.PoolService{}, &v1alpha1.AddressPool{}).
+				Build()
+			rc := &ReconcileLoadBalancerSet{
+				Client:    c,
+				apiReader: c,
+				recorder:  record.NewFakeRecorder(10),
+			}
+
+			assertErrNil(t, rc.allocateVIPs(svc))
+
+			current := &v1alpha1.PoolService{}
+			assertErrNil(t, c.Get(context.Background(), client.ObjectKeyFromObject(ps), current))
+			var vip string
+			if len(current.Status.LoadBalancer.Ingress) != 0 {
+				vip = current.Status.LoadBalancer.Ingress[0].IP
+			}
+			assertString(t, tc.wantedVIP, vip)
+			assertString(t, tc.wantedNode, current.Annotations[network.AnnotationVIPNode])
+
+			condition := meta.FindStatusCondition(current.Status.Conditions, conditionTypeVIPAllocated)
+			if condition == nil {
+				t.Fatalf("expected condition %s, but got nil", conditionTypeVIPAllocated)
+			}
+			assertString(t, tc.wantedReason, condition.Reason)
+
+			ap := &v1alpha1.AddressPool{}
+			assertErrNil(t, c.Get(context.Background(), types.NamespacedName{Name: "ap"}, ap))
+			if ap.Status.Allocated != tc.wantedAllocated {
+				t.Errorf("expected allocated %d, but got %d", tc.wantedAllocated, ap.Status.Allocated)
+			}
+		})
+	}
+}
+
+func TestReconcileLoadBalancerSet_AllocateVIPsWithLaggingCache(t *testing.T) {
+	np := newNodepool("np123", "name=np123,app=deploy")
+	np.Status.Nodes = []string{"node-a"}
+	svc := newVIPService(mockServiceName)
+	ps := newVIPPoolService(mockServiceName+"-np123", "np123", "")
+	ps.Labels[network.LabelServiceName] = mockServiceName
+	ps.Labels[labelManageBy] = names.LoadBalancerSetController
+	objs := []client.Object{newAddressPool("ap", "np123", "10.0.0.5-10.0.0.6"), svc, np, ps, newReadyNode("node-a", true)}
+
+	// the virtual ip allocated to other pool service is not synced to the cache yet
+	cached := fakeclient.NewClientBuilder().
+		WithScheme(initScheme(t)).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.PoolService{}, &v1alpha1.AddressPool{}).
+		Build()
+	apiReader := fakeclient.NewClientBuilder().
+		WithScheme(initScheme(t)).
+		WithObjects(append(objs, newVIPPoolService("other-np123", "np123", "10.0.0.5"))...).
+		Build()
+	rc := &ReconcileLoadBalancerSet{
+		Client:    cached,
+		apiReader: apiReader,
+		recorder:  record.NewFakeRecorder(10),
+	}
+
+	assertErrNil(t, rc.allocateVIPs(svc))
+
+	current := &v1alpha1.PoolService{}
+	assertErrNil(t, cached.Get(context.Background(), client.ObjectKeyFromObject(ps), current))
+	if len(current.Status.LoadBalancer.Ingress) == 0 {
+		t.Fatalf("expected virtual ip is allocated, but got none")
+	}
+	assertString(t, "10.0.0.6", current.Status.LoadBalancer.Ingress[0].IP)
+}
+
+func TestParseAddressRange(t *testing.T) {
+	testcases := map[string]struct {
+		address     string
+		wantedFirst string
+		wantedLast  string
+		wantedErr   bool
+	}{
+		"ipv4 address": {
+			address:     "10.0.0.1",
+			wantedFirst: "10.0.0.1",
+			wantedLast:  "10.0.0.1",
+		},
+		"ipv4 cidr without network and broadcast addresses": {
+			address:     "10.0.0.0/24",
+			wantedFirst: "10.0.0.1",
+			wantedLast:  "10.0.0.254",
+		},
+		"ipv4 /31 cidr": {
+			address:     "10.0.0.0/31",
+			wantedFirst: "10.0.0.0",
+			wantedLast:  "10.0.0.1",
+		},
+		"ipv6 cidr": {
+			address:     "fd00::/126",
+			wantedFirst: "fd00::",
+			wantedLast:  "fd00::3",
+		},
+		"ipv4 range": {
+			address:     "10.0.0.10-10.0.0.20",
+			wantedFirst: "10.0.0.10",
+			wantedLast:  "10.0.0.20",
+		},
+		"reversed range": {
+			address:   "10.0.0.20-10.0.0.10",
+			wantedErr: true,
+		},
+		"mixed family range": {
+			address:   "10.0.0.1-fd00::1",
+			wantedErr: true,
+		},
+		"invalid address": {
+			address:   "foo",
+			wantedErr: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			first, last, err := ParseAddressRange(tc.address)
+			if (err != nil) != tc.wantedErr {
+				t.Fatalf("expected error %v, but got %v", tc.wantedErr, err)
+			}
+			if tc.wantedErr {
+				return
+			}
+			assertString(t, tc.wantedFirst, first.String())
+			assertString(t, tc.wantedLast, last.String())
+		})
+	}
+}
+
+func TestNextFreeAddress(t *testing.T) {
+	used := map[string]bool{"10.0.0.255": true, "10.0.1.0": true}
+	isUsed := func(ip string) bool {
+		return used[ip]
+	}
+
+	assertString(t, "10.0.1.1", nextFreeAddress([]string{"10.0.0.255-10.0.1.2"}, isUsed))
+	assertString(t, "", nextFreeAddress([]string{"10.0.0.255-10.0.1.0"}, isUsed))
+	assertString(t, "10.0.0.3", nextFreeAddress([]string{"foo", "10.0.0.3"}, isUsed))
+	assertString(t, "", nextFreeAddress([]string{"255.255.255.255"}, func(string) bool { return true }))
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodebucket
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+)
+
+// resolveMaxNodesPerBucket returns the maximum number of nodes per NodeBucket for the NodePool,
+// the annotation of NodePool takes precedence over the configuration of controller.
+func (r *ReconcileNodeBucket) resolveMaxNodesPerBucket(pool *appsv1beta2.NodePool) int {
+	value, ok := pool.Annotations[apps.AnnotationNodeBucketMaxNodes]
+	if !ok {
+		return r.maxNodesPerBucket
+	}
+
+	maxNodes, err := strconv.Atoi(value)
+	if err != nil || maxNodes <= 0 {
+		klog.Warning(Format("invalid annotation %s=%s of nodepool(%s), use %d instead", apps.AnnotationNodeBucketMaxNodes, value, pool.Name, r.maxNodesPerBucket))
+		return r.maxNodesPerBucket
+	}
+	return maxNodes
+}
+
+// shardNodes groups nodes by the value of shard label key, all nodes are in the same shard
+// if shard label key is empty.
+func shardNodes(nodes []v1.Node, shardLabelKey string) map[string]sets.Set[string] {
+	shards := make(map[string]sets.Set[string])
+	for i := range nodes {
+		var shard string
+		if len(shardLabelKey) != 0 {
+			shard = nodes[i].Labels[shardLabelKey]
+		}
+		if shards[shard] == nil {
+			shards[shard] = sets.Set[string]{}
+		}
+		shards[shard].Insert(nodes[i].Name)
+	}
+	return shards
+}
+
+// shardBuckets groups NodeBuckets by their shard label, all NodeBuckets are in the same shard
+// if shard label key is empty.
+func shardBuckets(buckets []appsv1alpha1.NodeBucket, shardLabelKey string) map[string][]*appsv1alpha1.NodeBucket {
+	shards := make(map[string][]*appsv1alpha1.NodeBucket)
+	for i := range buckets {
+		var shard string
+		if len(shardLabelKey) != 0 {
+			shard = buckets[i].Labels[apps.NodeBucketShardLabel]
+		}
+		shards[shard] = append(shards[shard], &buckets[i])
+	}
+	return shards
+}
+
+// conciliateShardLabel makes the shard label of NodeBucket in line with the shard, it returns true
+// if the label is changed.
+func conciliateShardLabel(bucket *appsv1alpha1.NodeBucket, shardLabelKey, shard string) bool {
+	current, ok := bucket.Labels[apps.NodeBucketShardLabel]
+	if len(shardLabelKey) == 0 {
+		if !ok {
+			return false
+		}
+		delete(bucket.Labels, apps.NodeBucketShardLabel)
+		return true
+	}
+
+	if ok && current == shard {
+		return false
+	}
+	if bucket.Labels == nil {
+		bucket.Labels = make(map[string]string)
+	}
+	bucket.Labels[apps.NodeBucketShardLabel] = shard
+	return true
+}
+
+// hashNodeBuckets assigns nodes to NodeBuckets by rendezvous hashing: every node is assigned to the NodeBucket
+// with the highest hash of bucket name and node name. So only the NodeBucket of a node is changed when the node
+// is added or removed, and only nodes of the added or deleted NodeBuckets are moved when the number of NodeBuckets
+// is changed. The load of NodeBuckets is bounded: a node spills over to the NodeBucket with the next highest hash
+// if the NodeBucket with the highest hash has maxNodes nodes already. The number of NodeBuckets is increased as soon as there are more than maxNodes nodes per NodeBucket
+// on average, but it is decreased only when NodeBuckets are less than half full, so nodes which join and leave
+// the NodePool frequently would not lead to resizing of NodeBuckets.
+func hashNodeBuckets(
+	pool *appsv1beta2.NodePool,
+	desiredNodeSet sets.Set[string],
+	buckets []*appsv1alpha1.NodeBucket,
+	maxNodes int,
+) ([]*appsv1alpha1.NodeBucket, []*appsv1alpha1.NodeBucket, []*appsv1alpha1.NodeBucket, []*appsv1alpha1.NodeBucket) {
+	desiredNum := (desiredNodeSet.Len() + maxNodes - 1) / maxNodes
+	targetNum := len(buckets)
+	if targetNum < desiredNum || targetNum > 2*desiredNum {
+		targetNum = desiredNum
+	}
+
+	existingBuckets := make([]*appsv1alpha1.NodeBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		existingBuckets = append(existingBuckets, bucket.DeepCopy())
+	}
+	sort.Slice(existingBuckets, func(i, j int) bool {
+		return existingBuckets[i].Name < existingBuckets[j].Name
+	})
+
+	bucketsToDelete := []*appsv1alpha1.NodeBucket{}
+	if len(existingBuckets) > targetNum {
+		bucketsToDelete = append(bucketsToDelete, existingBuckets[targetNum:]...)
+		existingBuckets = existingBuckets[:targetNum]
+	}
+
+	bucketsToCreate := []*appsv1alpha1.NodeBucket{}
+	names := sets.Set[string]{}
+	for _, bucket := range existingBuckets {
+		names.Insert(bucket.Name)
+	}
+	for len(names) < targetNum {
+		name := newNodeBucketName(pool.Name)
+		if names.Has(name) {
+			continue
+		}
+		names.Insert(name)
+		bucket := newNodeBucket(pool)
+		bucket.Name = name
+		bucketsToCreate = append(bucketsToCreate, bucket)
+	}
+
+	assignments := make(map[string][]appsv1alpha1.Node, targetNum)
+	bucketNames := sets.List(names)
+	for _, nodeName := range sets.List(desiredNodeSet) {
+		// the NodeBucket with the highest hash may be full already, then the node spills over to
+		// the NodeBucket with the next highest hash, there is always enough room for all nodes
+		// because targetNum*maxNodes is not less than the number of nodes.
+		ranked := append([]string(nil), bucketNames...)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return rendezvousHash(ranked[i], nodeName) > rendezvousHash(ranked[j], nodeName)
+		})
+		for _, name := range ranked {
+			if len(assignments[name]) < maxNodes {
+				assignments[name] = append(assignments[name], appsv1alpha1.Node{Name: nodeName})
+				break
+			}
+		}
+	}
+
+	bucketsToUpdate := []*appsv1alpha1.NodeBucket{}
+	bucketsUnchanged := []*appsv1alpha1.NodeBucket{}
+	for _, bucket := range existingBuckets {
+		nodes := assignments[bucket.Name]
+		if sameNodes(bucket.Nodes, nodes) {
+			bucketsUnchanged = append(bucketsUnchanged, bucket)
+			continue
+		}
+		bucket.Nodes = append(make([]appsv1alpha1.Node, 0, len(nodes)), nodes...)
+		bucketsToUpdate = append(bucketsToUpdate, bucket)
+	}
+	for _, bucket := range bucketsToCreate {
+		bucket.Nodes = append(bucket.Nodes, assignments[bucket.Name]...)
+	}
+
+	klog.V(4).Infof("hashNodeBuckets for pool(%s), len(bucketsUnchanged)=%d, len(bucketsToCreate)=%d, len(bucketsToUpdate)=%d, len(bucketsToDelete)=%d",
+		pool.Name, len(bucketsUnchanged), len(bucketsToCreate), len(bucketsToUpdate), len(bucketsToDelete))
+	return bucketsToCreate, bucketsToUpdate, bucketsToDelete, bucketsUnchanged
+}
+
+func rendezvousHash(bucketName, nodeName string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(bucketName))
+	h.Write([]byte{0})
+	h.Write([]byte(nodeName))
+	return h.Sum64()
+}
+
+func sameNodes(current, desired []appsv1alpha1.Node) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	currentSet := sets.Set[string]{}
+	for _, node := range current {
+		currentSet.Insert(node.Name)
+	}
+	for _, node := range desired {
+		if !currentSet.Has(node.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+func newNodeBucketName(poolName string) string {
+	return fmt.Sprintf("%s-%s", poolName, rand.String(6))
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodebucket
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openyurtio/openyurt/pkg/apis"
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsalphav1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/nodebucket/config"
+)
+
+func newTestNode(name, pool string, labels map[string]string) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				projectinfo.GetNodePoolLabel(): pool,
+			},
+		},
+	}
+	for k, v := range labels {
+		node.Labels[k] = v
+	}
+	return node
+}
+
+func listBuckets(t *testing.T, c client.Client, pool string) []appsalphav1.NodeBucket {
+	buckets := new(appsalphav1.NodeBucketList)
+	if err := c.List(context.Background(), buckets, &client.MatchingLabels{LabelNodePoolName: pool}); err != nil {
+		t.Fatalf("could not list node buckets, %v", err)
+	}
+	return buckets.Items
+}
+
+func TestReconcileWithConsistentHash(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	apis.AddToScheme(scheme)
+
+	pool := &appsv1beta2.NodePool{ObjectMeta: metav1.ObjectMeta{Name: "hangzhou"}}
+	objs := []client.Object{pool}
+	for i := 1; i <= 6; i++ {
+		objs = append(objs, newTestNode(fmt.Sprintf("node%d", i), "hangzhou", nil))
+	}
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	r := &ReconcileNodeBucket{
+		Client:            c,
+		maxNodesPerBucket: 4,
+		assignmentPolicy:  config.ConsistentHashPolicy,
+	}
+	ctx := context.Background()
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "hangzhou"}}
+
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("could not reconcile pool, %v", err)
+	}
+	buckets := listBuckets(t, c, "hangzhou")
+	if len(buckets) != 2 {
+		t.Fatalf("expect 2 buckets, but got %d", len(buckets))
+	}
+	versions := make(map[string]string)
+	gotBucketNodes := sets.Set[string]{}
+	for i := range buckets {
+		versions[buckets[i].Name] = buckets[i].ResourceVersion
+		for _, node := range buckets[i].Nodes {
+			gotBucketNodes.Insert(node.Name)
+		}
+	}
+	if gotBucketNodes.Len() != 6 {
+		t.Errorf("expect 6 nodes in buckets, but got %v", gotBucketNodes.UnsortedList())
+	}
+
+	// adding a node only changes one bucket
+	if err := c.Create(ctx, newTestNode("node7", "hangzhou", nil)); err != nil {
+		t.Fatalf("could not create node, %v", err)
+	}
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("could not reconcile pool, %v", err)
+	}
+	buckets = listBuckets(t, c, "hangzhou")
+	if len(buckets) != 2 {
+		t.Fatalf("expect 2 buckets, but got %d", len(buckets))
+	}
+	changed := 0
+	for i := range buckets {
+		if versions[buckets[i].Name] != buckets[i].ResourceVersion {
+			changed++
+		}
+	}
+	if changed != 1 {
+		t.Errorf("expect 1 bucket is changed, but got %d", changed)
+	}
+
+	// buckets are not shrunk as long as they are at least half full
+	for i := 1; i <= 3; i++ {
+		if err := c.Delete(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node%d", i)}}); err != nil {
+			t.Fatalf("could not delete node, %v", err)
+		}
+	}
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("could not reconcile pool, %v", err)
+	}
+	if buckets = listBuckets(t, c, "hangzhou"); len(buckets) != 2 {
+		t.Errorf("expect 2 buckets, but got %d", len(buckets))
+	}
+}
+
+func TestReconcileWithShards(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	apis.AddToScheme(scheme)
+
+	testcases := map[string]struct {
+		policy                string
+		wantedNumberOfBuckets int
+	}{
+		"fill policy": {
+			policy:                config.FillPolicy,
+			wantedNumberOfBuckets: 3,
+		},
+		"consistent hash policy": {
+			policy:                config.ConsistentHashPolicy,
+			wantedNumberOfBuckets: 3,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			pool := &appsv1beta2.NodePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "hangzhou",
+					Annotations: map[string]string{
+						apps.AnnotationNodeBucketShardLabelKey: corev1.LabelTopologyZone,
+						apps.AnnotationNodeBucketMaxNodes:      "2",
+					},
+				},
+			}
+			c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+				pool,
+				newTestNode("node1", "hangzhou", map[string]string{corev1.LabelTopologyZone: "a"}),
+				newTestNode("node2", "hangzhou", map[string]string{corev1.LabelTopologyZone: "a"}),
+				newTestNode("node3", "hangzhou", map[string]string{corev1.LabelTopologyZone: "a"}),
+				newTestNode("node4", "hangzhou", map[string]string{corev1.LabelTopologyZone: "b"}),
+				&appsalphav1.NodeBucket{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "hangzhou-xxxxxx",
+						Labels: map[string]string{
+							LabelNodePoolName: "hangzhou",
+						},
+					},
+					Nodes: []appsalphav1.Node{{Name: "node4"}},
+				},
+			).Build()
+			r := &ReconcileNodeBucket{
+				Client:            c,
+				maxNodesPerBucket: 100,
+				assignmentPolicy:  tc.policy,
+			}
+
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "hangzhou"}}); err != nil {
+				t.Fatalf("could not reconcile pool, %v", err)
+			}
+
+			buckets := listBuckets(t, c, "hangzhou")
+			if len(buckets) != tc.wantedNumberOfBuckets {
+				t.Errorf("expect %d buckets, but got %d", tc.wantedNumberOfBuckets, len(buckets))
+			}
+			wantedShards := map[string]string{"node1": "a", "node2": "a", "node3": "a", "node4": "b"}
+			gotBucketNodes := sets.Set[string]{}
+			for i := range buckets {
+				// nodes are balanced among buckets only on average by consistent hashing
+				if tc.policy == config.FillPolicy && len(buckets[i].Nodes) > 2 {
+					t.Errorf("expect at most 2 nodes in bucket %s, but got %d", buckets[i].Name, len(buckets[i].Nodes))
+				}
+				for _, node := range buckets[i].Nodes {
+					gotBucketNodes.Insert(node.Name)
+					if shard := buckets[i].Labels[apps.NodeBucketShardLabel]; shard != wantedShards[node.Name] {
+						t.Errorf("expect node %s in shard %s, but got %s", node.Name, wantedShards[node.Name], shard)
+					}
+				}
+			}
+			if gotBucketNodes.Len() != len(wantedShards) {
+				t.Errorf("expect nodes %v, but got %v", wantedShards, gotBucketNodes.UnsortedList())
+			}
+		})
+	}
+}
+
+func TestHashNodeBucketsBoundedLoad(t *testing.T) {
+	pool := &appsv1beta2.NodePool{ObjectMeta: metav1.ObjectMeta{Name: "hangzhou"}}
+	testcases := map[string]struct {
+		nodes    int
+		maxNodes int
+	}{
+		"buckets are full": {
+			nodes:    40,
+			maxNodes: 4,
+		},
+		"the last bucket is not full": {
+			nodes:    97,
+			maxNodes: 8,
+		},
+		"one node per bucket": {
+			nodes:    16,
+			maxNodes: 1,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			desiredNodeSet := sets.Set[string]{}
+			for i := 0; i < tc.nodes; i++ {
+				desiredNodeSet.Insert(fmt.Sprintf("node%d", i))
+			}
+
+			bucketsToCreate, _, _, _ := hashNodeBuckets(pool, desiredNodeSet, nil, tc.maxNodes)
+			gotNodes := sets.Set[string]{}
+			for _, bucket := range bucketsToCreate {
+				if len(bucket.Nodes) > tc.maxNodes {
+					t.Errorf("expect at most %d nodes in bucket %s, but got %d", tc.maxNodes, bucket.Name, len(bucket.Nodes))
+				}
+				for _, node := range bucket.Nodes {
+					gotNodes.Insert(node.Name)
+				}
+			}
+			if !gotNodes.Equal(desiredNodeSet) {
+				t.Errorf("expect all %d nodes in buckets, but got %d", desiredNodeSet.Len(), gotNodes.Len())
+			}
+
+			// nodes are not moved when the buckets are reconciled again
+			_, bucketsToUpdate, _, _ := hashNodeBuckets(pool, desiredNodeSet, bucketsToCreate, tc.maxNodes)
+			if len(bucketsToUpdate) != 0 {
+				t.Errorf("expect no bucket is updated, but got %d", len(bucketsToUpdate))
+			}
+		})
+	}
+}
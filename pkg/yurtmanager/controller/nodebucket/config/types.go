@@ -16,8 +16,17 @@ limitations under the License.
 
 package config
 
+const (
+	// FillPolicy fills nodes into the NodeBuckets which have the least free space.
+	FillPolicy = "Fill"
+	// ConsistentHashPolicy assigns nodes to NodeBuckets by consistent hashing of node names, so adding
+	// or removing a node only changes one NodeBucket.
+	ConsistentHashPolicy = "ConsistentHash"
+)
+
 // NodeBucketControllerConfiguration contains elements describing NodeBucketController.
 type NodeBucketControllerConfiguration struct {
 	MaxNodesPerBucket           int32
 	ConcurrentNodeBucketWorkers int32
+	BucketAssignmentPolicy      string
 }
@@ -0,0 +1,62 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodebucket
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	nodeBucketControllerSubsystem = "nodebucket_controller"
+	bucketOperationsTotalKey      = "bucket_operations_total"
+	bucketsKey                    = "buckets"
+
+	operationCreate = "create"
+	operationUpdate = "update"
+	operationDelete = "delete"
+)
+
+var (
+	bucketOperationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: nodeBucketControllerSubsystem,
+			Name:      bucketOperationsTotalKey,
+			Help:      "Number of NodeBuckets created, updated and deleted per NodePool, every operation is watched by edge nodes.",
+		},
+		[]string{"nodepool", "operation"},
+	)
+	buckets = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: nodeBucketControllerSubsystem,
+			Name:      bucketsKey,
+			Help:      "Gauge measuring number of NodeBuckets per NodePool.",
+		},
+		[]string{"nodepool"},
+	)
+)
+
+var registerMetrics sync.Once
+
+// Register the metrics that are to be monitored, they are exposed by the metrics server of yurt-manager.
+func Register() {
+	registerMetrics.Do(func() {
+		ctrlmetrics.Registry.MustRegister(bucketOperationsTotal, buckets)
+	})
+}
@@ -19,13 +19,13 @@ package nodebucket
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"sort"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,9 +40,11 @@ import (
 	yurtClient "github.com/openyurtio/openyurt/cmd/yurt-manager/app/client"
 	appconfig "github.com/openyurtio/openyurt/cmd/yurt-manager/app/config"
 	"github.com/openyurtio/openyurt/cmd/yurt-manager/names"
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
 	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
 	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
 	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/nodebucket/config"
 )
 
 var (
@@ -65,7 +67,10 @@ func Add(_ context.Context, cfg *appconfig.CompletedConfig, mgr manager.Manager)
 	r := &ReconcileNodeBucket{
 		Client:            yurtClient.GetClientByControllerNameOrDie(mgr, names.NodeBucketController),
 		maxNodesPerBucket: int(cfg.ComponentConfig.NodeBucketController.MaxNodesPerBucket),
+		assignmentPolicy:  cfg.ComponentConfig.NodeBucketController.BucketAssignmentPolicy,
 	}
+	// Register prometheus metrics
+	Register()
 
 	// Create a new controller
 	c, err := controller.New(names.NodeBucketController, mgr, controller.Options{
@@ -93,7 +98,17 @@ func Add(_ context.Context, cfg *appconfig.CompletedConfig, mgr manager.Manager)
 			return true
 		},
 		UpdateFunc: func(updateEvent event.UpdateEvent) bool {
-			return false
+			// NodeBuckets should be resharded or resized when the annotations of NodePool are changed
+			oldPool, ok := updateEvent.ObjectOld.(*appsv1beta2.NodePool)
+			if !ok {
+				return false
+			}
+			newPool, ok := updateEvent.ObjectNew.(*appsv1beta2.NodePool)
+			if !ok {
+				return false
+			}
+			return oldPool.Annotations[apps.AnnotationNodeBucketShardLabelKey] != newPool.Annotations[apps.AnnotationNodeBucketShardLabelKey] ||
+				oldPool.Annotations[apps.AnnotationNodeBucketMaxNodes] != newPool.Annotations[apps.AnnotationNodeBucketMaxNodes]
 		},
 		DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
 			return false
@@ -125,7 +140,8 @@ func Add(_ context.Context, cfg *appconfig.CompletedConfig, mgr manager.Manager)
 			if oldNode.Labels[projectinfo.GetNodePoolLabel()] != newNode.Labels[projectinfo.GetNodePoolLabel()] {
 				return true
 			}
-			return false
+			// node may be moved into another shard when its labels are changed
+			return !reflect.DeepEqual(oldNode.Labels, newNode.Labels)
 		},
 		GenericFunc: func(evt event.GenericEvent) bool {
 			return false
@@ -162,6 +178,7 @@ var _ reconcile.Reconciler = &ReconcileNodeBucket{}
 type ReconcileNodeBucket struct {
 	client.Client
 	maxNodesPerBucket int
+	assignmentPolicy  string
 }
 
 // +kubebuilder:rbac:groups=apps.openyurt.io,resources=nodebuckets,verbs=get;create;update;patch;delete
@@ -176,6 +193,9 @@ func (r *ReconcileNodeBucket) Reconcile(ctx context.Context, request reconcile.R
 	ins := &appsv1beta2.NodePool{}
 	err := r.Get(context.TODO(), request.NamespacedName, ins)
 	if err != nil {
+		if errors.IsNotFound(err) {
+			buckets.DeleteLabelValues(request.Name)
+		}
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -183,17 +203,15 @@ func (r *ReconcileNodeBucket) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, nil
 	}
 
-	// 2. list all nodes in the NodePool and prepare node set
+	// 2. list all nodes in the NodePool and prepare node sets of shards
 	var currentNodeList v1.NodeList
 	if err := r.List(ctx, &currentNodeList, client.MatchingLabels(map[string]string{
 		projectinfo.GetNodePoolLabel(): ins.Name,
 	})); err != nil {
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
-	desiredNodeSet := sets.Set[string]{}
-	for i := range currentNodeList.Items {
-		desiredNodeSet.Insert(currentNodeList.Items[i].Name)
-	}
+	shardLabelKey := ins.Annotations[apps.AnnotationNodeBucketShardLabelKey]
+	desiredNodeSets := shardNodes(currentNodeList.Items, shardLabelKey)
 
 	// 3. list all exist NodeBuckets for the NodePool
 	var existingNodeBucketList appsv1alpha1.NodeBucketList
@@ -202,13 +220,43 @@ func (r *ReconcileNodeBucket) Reconcile(ctx context.Context, request reconcile.R
 	})); err != nil {
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
+	existingBuckets := shardBuckets(existingNodeBucketList.Items, shardLabelKey)
+
+	// 4. reconcile NodeBuckets of every shard based on nodes and existing NodeBuckets
+	maxNodes := r.resolveMaxNodesPerBucket(ins)
+	shards := sets.KeySet(desiredNodeSets).Union(sets.KeySet(existingBuckets))
+	var bucketsToCreate, bucketsToUpdate, bucketsToDelete, bucketsUnchanged []*appsv1alpha1.NodeBucket
+	for _, shard := range sets.List(shards) {
+		desiredNodeSet := desiredNodeSets[shard]
+		if desiredNodeSet == nil {
+			desiredNodeSet = sets.Set[string]{}
+		}
 
-	// 4. reconcile NodeBuckets based on nodes and existing NodeBuckets
-	bucketsToCreate, bucketsToUpdate, bucketsToDelete, bucketsUnchanged := r.reconcileNodeBuckets(
-		ins,
-		desiredNodeSet,
-		&existingNodeBucketList,
-	)
+		var toCreate, toUpdate, toDelete, unchanged []*appsv1alpha1.NodeBucket
+		if r.assignmentPolicy == config.ConsistentHashPolicy {
+			toCreate, toUpdate, toDelete, unchanged = hashNodeBuckets(ins, desiredNodeSet, existingBuckets[shard], maxNodes)
+		} else {
+			toCreate, toUpdate, toDelete, unchanged = r.reconcileNodeBuckets(ins, desiredNodeSet, existingBuckets[shard], maxNodes)
+		}
+
+		// shard label of NodeBuckets should be kept in line with the shard label key of NodePool
+		for _, bucket := range toCreate {
+			conciliateShardLabel(bucket, shardLabelKey, shard)
+		}
+		for _, bucket := range toUpdate {
+			conciliateShardLabel(bucket, shardLabelKey, shard)
+		}
+		for _, bucket := range unchanged {
+			if conciliateShardLabel(bucket, shardLabelKey, shard) {
+				toUpdate = append(toUpdate, bucket)
+			} else {
+				bucketsUnchanged = append(bucketsUnchanged, bucket)
+			}
+		}
+		bucketsToCreate = append(bucketsToCreate, toCreate...)
+		bucketsToUpdate = append(bucketsToUpdate, toUpdate...)
+		bucketsToDelete = append(bucketsToDelete, toDelete...)
+	}
 	klog.Infof(
 		"reconcile pool(%s): bucketsToCreate=%d, bucketsToUpdate=%d, bucketsToDelete=%d, bucketsUnchanged=%d",
 		ins.Name,
@@ -217,6 +265,7 @@ func (r *ReconcileNodeBucket) Reconcile(ctx context.Context, request reconcile.R
 		len(bucketsToDelete),
 		len(bucketsUnchanged),
 	)
+	buckets.WithLabelValues(ins.Name).Set(float64(len(bucketsToCreate) + len(bucketsToUpdate) + len(bucketsUnchanged)))
 
 	// 5.finalize creates, updates, and deletes buckets as specified
 	if err = finalize(ctx, r.Client, bucketsToCreate, bucketsToUpdate, bucketsToDelete); err != nil {
@@ -230,7 +279,8 @@ func (r *ReconcileNodeBucket) Reconcile(ctx context.Context, request reconcile.R
 func (r *ReconcileNodeBucket) reconcileNodeBuckets(
 	pool *appsv1beta2.NodePool,
 	desiredNodeSet sets.Set[string],
-	buckets *appsv1alpha1.NodeBucketList,
+	buckets []*appsv1alpha1.NodeBucket,
+	maxNodes int,
 ) ([]*appsv1alpha1.NodeBucket, []*appsv1alpha1.NodeBucket, []*appsv1alpha1.NodeBucket, []*appsv1alpha1.NodeBucket) {
 	bucketsUnchanged, bucketsToUpdate, bucketsToDelete, unFilledNodeSet := resolveExistingBuckets(
 		buckets,
@@ -245,7 +295,7 @@ func (r *ReconcileNodeBucket) reconcileNodeBuckets(
 	if unFilledNodeSet.Len() > 0 && len(bucketsToUpdate) > 0 {
 		sort.Sort(nodeBucketNodesLen(bucketsToUpdate))
 		for _, bucket := range bucketsToUpdate {
-			for unFilledNodeSet.Len() > 0 && len(bucket.Nodes) < r.maxNodesPerBucket {
+			for unFilledNodeSet.Len() > 0 && len(bucket.Nodes) < maxNodes {
 				nodeName, _ := unFilledNodeSet.PopAny()
 				bucket.Nodes = append(bucket.Nodes, appsv1alpha1.Node{Name: nodeName})
 			}
@@ -262,8 +312,8 @@ func (r *ReconcileNodeBucket) reconcileNodeBuckets(
 		var bucketToFill *appsv1alpha1.NodeBucket
 		var index int
 
-		if unFilledNodeSet.Len() < r.maxNodesPerBucket && len(bucketsUnchanged) > 0 {
-			index, bucketToFill = getBucketToFill(bucketsUnchanged, unFilledNodeSet.Len(), r.maxNodesPerBucket)
+		if unFilledNodeSet.Len() < maxNodes && len(bucketsUnchanged) > 0 {
+			index, bucketToFill = getBucketToFill(bucketsUnchanged, unFilledNodeSet.Len(), maxNodes)
 		}
 
 		// If we didn't find a bucketToFill, generate a new empty one.
@@ -276,7 +326,7 @@ func (r *ReconcileNodeBucket) reconcileNodeBuckets(
 		}
 
 		// Fill the bucket up with remaining nodes.
-		for unFilledNodeSet.Len() > 0 && len(bucketToFill.Nodes) < r.maxNodesPerBucket {
+		for unFilledNodeSet.Len() > 0 && len(bucketToFill.Nodes) < maxNodes {
 			nodeName, _ := unFilledNodeSet.PopAny()
 			bucketToFill.Nodes = append(bucketToFill.Nodes, appsv1alpha1.Node{Name: nodeName})
 		}
@@ -290,15 +340,15 @@ func (r *ReconcileNodeBucket) reconcileNodeBuckets(
 
 // resolveExistingBuckets iterates through existing node buckets to delete nodes no longer desired and update node buckets that have changed
 func resolveExistingBuckets(
-	buckets *appsv1alpha1.NodeBucketList,
+	buckets []*appsv1alpha1.NodeBucket,
 	desiredNodeSet sets.Set[string],
 ) ([]*appsv1alpha1.NodeBucket, []*appsv1alpha1.NodeBucket, []*appsv1alpha1.NodeBucket, sets.Set[string]) {
 	bucketsUnchanged := []*appsv1alpha1.NodeBucket{}
 	bucketsToUpdate := []*appsv1alpha1.NodeBucket{}
 	bucketsToDelete := []*appsv1alpha1.NodeBucket{}
 
-	for _, bucket := range buckets.Items {
-		copiedBucket := (&bucket).DeepCopy()
+	for _, bucket := range buckets {
+		copiedBucket := bucket.DeepCopy()
 		newNodes := []appsv1alpha1.Node{}
 		for _, node := range copiedBucket.Nodes {
 			if desiredNodeSet.Has(node.Name) {
@@ -366,7 +416,8 @@ func finalize(
 ) error {
 	// If there are buckets to create and delete, change the creates to updates of the buckets that would otherwise be deleted.
 	for i := 0; i < len(bucketsToDelete); {
-		if len(bucketsToCreate) == 0 {
+		// buckets named in advance are assigned by consistent hashing, they can not be renamed
+		if len(bucketsToCreate) == 0 || len(bucketsToCreate[len(bucketsToCreate)-1].Name) != 0 {
 			break
 		}
 		bucketToDelete := bucketsToDelete[i]
@@ -380,17 +431,21 @@ func finalize(
 
 	for _, bucket := range bucketsToCreate {
 		var collisionCount int
+		named := len(bucket.Name) != 0
 		for {
 			collisionCount++
-			bucket.Name = fmt.Sprintf("%s-%s", bucket.Labels[LabelNodePoolName], rand.String(6))
+			if !named {
+				bucket.Name = newNodeBucketName(bucket.Labels[LabelNodePoolName])
+			}
 			bucket.NumNodes = int32(len(bucket.Nodes))
 			if err := c.Create(ctx, bucket, &client.CreateOptions{}); err != nil {
-				if errors.IsAlreadyExists(err) && collisionCount < 5 {
+				if errors.IsAlreadyExists(err) && !named && collisionCount < 5 {
 					continue
 				}
 				klog.Errorf("could not create bucket(%s), %v", bucket.Name, err)
 				return err
 			}
+			bucketOperationsTotal.WithLabelValues(bucket.Labels[LabelNodePoolName], operationCreate).Inc()
 			break
 		}
 	}
@@ -401,6 +456,7 @@ func finalize(
 			klog.Errorf("could not update bucket(%s), %v", bucket.Name, err)
 			return err
 		}
+		bucketOperationsTotal.WithLabelValues(bucket.Labels[LabelNodePoolName], operationUpdate).Inc()
 	}
 
 	for _, bucket := range bucketsToDelete {
@@ -408,6 +464,7 @@ func finalize(
 			klog.Errorf("could not delete bucket(%s), %v", bucket.Name, err)
 			return err
 		}
+		bucketOperationsTotal.WithLabelValues(bucket.Labels[LabelNodePoolName], operationDelete).Inc()
 	}
 
 	return nil
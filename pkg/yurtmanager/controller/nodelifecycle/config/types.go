@@ -0,0 +1,35 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PoolPartitionConfiguration contains elements describing how NodeLifeCycleController
+// detects disconnected NodePools and handles the nodes in them.
+type PoolPartitionConfiguration struct {
+	// PoolDisconnectedThreshold is the fraction of nodes in a NodePool which stop renewing heartbeats
+	// together for the NodePool to be treated as disconnected.
+	PoolDisconnectedThreshold float32
+	// PoolHeartbeatCorrelationWindow is the max interval between the last heartbeats of nodes
+	// for them to be regarded as stopping renewing heartbeats together.
+	PoolHeartbeatCorrelationWindow metav1.Duration
+	// PoolDisconnectedEvictionRate is the number of nodes per second on which pods are evicted
+	// in a disconnected NodePool. Evictions are suppressed if it's 0.
+	PoolDisconnectedEvictionRate float32
+}
@@ -29,6 +29,7 @@ const (
 	zoneSizeKey             = "zone_size"
 	zoneNoUnhealthyNodesKey = "unhealthy_nodes_in_zone"
 	evictionsTotalKey       = "evictions_total"
+	poolDisconnectedKey     = "nodepool_disconnected"
 
 	updateNodeHealthKey     = "update_node_health_duration_seconds"
 	updateAllNodesHealthKey = "update_all_nodes_health_duration_seconds"
@@ -71,6 +72,15 @@ var (
 		},
 		[]string{"zone"},
 	)
+	poolDisconnected = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      nodeControllerSubsystem,
+			Name:           poolDisconnectedKey,
+			Help:           "Gauge measuring whether the NodePool is disconnected, 1 means disconnected.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"nodepool"},
+	)
 
 	updateNodeHealthDuration = metrics.NewHistogram(
 		&metrics.HistogramOpts{
@@ -101,6 +111,7 @@ func Register() {
 		legacyregistry.MustRegister(zoneSize)
 		legacyregistry.MustRegister(unhealthyNodes)
 		legacyregistry.MustRegister(evictionsTotal)
+		legacyregistry.MustRegister(poolDisconnected)
 		legacyregistry.MustRegister(updateNodeHealthDuration)
 		legacyregistry.MustRegister(updateAllNodesHealthDuration)
 	})
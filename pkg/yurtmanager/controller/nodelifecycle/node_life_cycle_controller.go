@@ -238,6 +238,11 @@ type ReconcileNodeLifeCycle struct {
 
 	zoneStates map[string]ZoneState
 
+	// disconnectedPools are NodePools whose nodes stop renewing heartbeats together, protected by evictorLock.
+	disconnectedPools map[string]bool
+	// workers that are responsible for tainting nodes in disconnected NodePools, protected by evictorLock.
+	poolNoExecuteTainter map[string]*scheduler.RateLimitedTimedQueue
+
 	getPodsAssignedToNode func(nodeName string) ([]*v1.Pod, error)
 
 	recorder record.EventRecorder
@@ -282,6 +287,10 @@ type ReconcileNodeLifeCycle struct {
 	largeClusterThreshold       int32
 	unhealthyZoneThreshold      float32
 
+	poolDisconnectedThreshold      float32
+	poolHeartbeatCorrelationWindow time.Duration
+	poolDisconnectedEvictionQPS    float32
+
 	nodeUpdateQueue workqueue.TypedInterface[string]
 	podUpdateQueue  workqueue.TypedRateLimitingInterface[podUpdateItem]
 }
@@ -291,6 +300,8 @@ type ReconcileNodeLifeCycle struct {
 // +kubebuilder:rbac:groups=core,resources=pods/status,verbs=update
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;delete
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get
+// +kubebuilder:rbac:groups=apps.openyurt.io,resources=nodepools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps.openyurt.io,resources=nodepools/status,verbs=update
 
 // Add creates a new CsrApprover Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
@@ -382,23 +393,28 @@ func GenGetPodsAssignedToNode(c client.Client) func(string) ([]*v1.Pod, error) {
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(cfg *appconfig.CompletedConfig, mgr manager.Manager) (*ReconcileNodeLifeCycle, error) {
 	nc := &ReconcileNodeLifeCycle{
-		controllerRuntimeClient:     yurtClient.GetClientByControllerNameOrDie(mgr, controllerName),
-		recorder:                    mgr.GetEventRecorderFor(controllerName),
-		now:                         metav1.Now,
-		knownNodeSet:                make(map[string]*v1.Node),
-		nodeHealthMap:               newNodeHealthMap(),
-		nodeUpdateWorkerSize:        scheduler.UpdateWorkerSize,
-		zoneNoExecuteTainter:        make(map[string]*scheduler.RateLimitedTimedQueue),
-		nodesToRetry:                sync.Map{},
-		zoneStates:                  make(map[string]ZoneState),
-		nodeMonitorPeriod:           metav1.Duration{Duration: 5 * time.Second}.Duration,
-		nodeStartupGracePeriod:      cfg.ComponentConfig.NodeLifeCycleController.NodeStartupGracePeriod.Duration,
-		nodeMonitorGracePeriod:      cfg.ComponentConfig.NodeLifeCycleController.NodeMonitorGracePeriod.Duration,
-		evictionLimiterQPS:          cfg.ComponentConfig.NodeLifeCycleController.NodeEvictionRate,
-		secondaryEvictionLimiterQPS: cfg.ComponentConfig.NodeLifeCycleController.SecondaryNodeEvictionRate,
-		largeClusterThreshold:       cfg.ComponentConfig.NodeLifeCycleController.LargeClusterSizeThreshold,
-		unhealthyZoneThreshold:      cfg.ComponentConfig.NodeLifeCycleController.UnhealthyZoneThreshold,
-		nodeUpdateQueue:             workqueue.NewTypedWithConfig(workqueue.TypedQueueConfig[string]{Name: "node_lifecycle_controller"}),
+		controllerRuntimeClient:        yurtClient.GetClientByControllerNameOrDie(mgr, controllerName),
+		recorder:                       mgr.GetEventRecorderFor(controllerName),
+		now:                            metav1.Now,
+		knownNodeSet:                   make(map[string]*v1.Node),
+		nodeHealthMap:                  newNodeHealthMap(),
+		nodeUpdateWorkerSize:           scheduler.UpdateWorkerSize,
+		zoneNoExecuteTainter:           make(map[string]*scheduler.RateLimitedTimedQueue),
+		nodesToRetry:                   sync.Map{},
+		zoneStates:                     make(map[string]ZoneState),
+		disconnectedPools:              make(map[string]bool),
+		poolNoExecuteTainter:           make(map[string]*scheduler.RateLimitedTimedQueue),
+		nodeMonitorPeriod:              metav1.Duration{Duration: 5 * time.Second}.Duration,
+		nodeStartupGracePeriod:         cfg.ComponentConfig.NodeLifeCycleController.NodeStartupGracePeriod.Duration,
+		nodeMonitorGracePeriod:         cfg.ComponentConfig.NodeLifeCycleController.NodeMonitorGracePeriod.Duration,
+		evictionLimiterQPS:             cfg.ComponentConfig.NodeLifeCycleController.NodeEvictionRate,
+		secondaryEvictionLimiterQPS:    cfg.ComponentConfig.NodeLifeCycleController.SecondaryNodeEvictionRate,
+		largeClusterThreshold:          cfg.ComponentConfig.NodeLifeCycleController.LargeClusterSizeThreshold,
+		unhealthyZoneThreshold:         cfg.ComponentConfig.NodeLifeCycleController.UnhealthyZoneThreshold,
+		poolDisconnectedThreshold:      cfg.ComponentConfig.NodePoolPartition.PoolDisconnectedThreshold,
+		poolHeartbeatCorrelationWindow: cfg.ComponentConfig.NodePoolPartition.PoolHeartbeatCorrelationWindow.Duration,
+		poolDisconnectedEvictionQPS:    cfg.ComponentConfig.NodePoolPartition.PoolDisconnectedEvictionRate,
+		nodeUpdateQueue:                workqueue.NewTypedWithConfig(workqueue.TypedQueueConfig[string]{Name: "node_lifecycle_controller"}),
 		podUpdateQueue: workqueue.NewTypedRateLimitingQueueWithConfig(
 			workqueue.DefaultTypedControllerRateLimiter[podUpdateItem](),
 			workqueue.TypedRateLimitingQueueConfig[podUpdateItem]{
@@ -541,30 +557,25 @@ func (nc *ReconcileNodeLifeCycle) doNoScheduleTaintingPass(ctx context.Context,
 }
 
 func (nc *ReconcileNodeLifeCycle) doNoExecuteTaintingPass(ctx context.Context) {
-	// Extract out the keys of the map in order to not hold
+	// Extract out the workers in order to not hold
 	// the evictorLock for the entire function and hold it
 	// only when necessary.
-	var zoneNoExecuteTainterKeys []string
+	var workers []*scheduler.RateLimitedTimedQueue
 	func() {
 		nc.evictorLock.Lock()
 		defer nc.evictorLock.Unlock()
 
-		zoneNoExecuteTainterKeys = make([]string, 0, len(nc.zoneNoExecuteTainter))
-		for k := range nc.zoneNoExecuteTainter {
-			zoneNoExecuteTainterKeys = append(zoneNoExecuteTainterKeys, k)
+		workers = make([]*scheduler.RateLimitedTimedQueue, 0, len(nc.zoneNoExecuteTainter)+len(nc.poolNoExecuteTainter))
+		for _, worker := range nc.zoneNoExecuteTainter {
+			workers = append(workers, worker)
+		}
+		// Workers of disconnected NodePools are removed when the pools are connected again,
+		// it's safe to drain a removed worker in this pass.
+		for _, worker := range nc.poolNoExecuteTainter {
+			workers = append(workers, worker)
 		}
 	}()
-	for _, k := range zoneNoExecuteTainterKeys {
-		var zoneNoExecuteTainterWorker *scheduler.RateLimitedTimedQueue
-		func() {
-			nc.evictorLock.Lock()
-			defer nc.evictorLock.Unlock()
-			// Extracting the value without checking if the key
-			// exists or not is safe to do here since zones do
-			// not get removed, and consequently pod evictors for
-			// these zones also do not get removed, only added.
-			zoneNoExecuteTainterWorker = nc.zoneNoExecuteTainter[k]
-		}()
+	for _, zoneNoExecuteTainterWorker := range workers {
 		// Function should return 'false' and a time after which it should be retried, or 'true' if it shouldn't (it succeeded).
 		zoneNoExecuteTainterWorker.Try(func(value scheduler.TimedValue) (bool, time.Duration) {
 			//node, err := nc.nodeLister.Get(value.Value)
@@ -651,6 +662,8 @@ func (nc *ReconcileNodeLifeCycle) monitorNodeHealth(ctx context.Context) error {
 
 	var zoneToNodeConditionsLock sync.Mutex
 	zoneToNodeConditions := map[string][]*v1.NodeCondition{}
+	var poolToHeartbeatsLock sync.Mutex
+	poolToHeartbeats := map[string][]poolNodeHeartbeat{}
 	updateNodeFunc := func(piece int) {
 		start := nc.now()
 		defer func() {
@@ -686,6 +699,19 @@ func (nc *ReconcileNodeLifeCycle) monitorNodeHealth(ctx context.Context) error {
 			zoneToNodeConditionsLock.Unlock()
 		}
 
+		if pool := getNodePoolName(node); len(pool) != 0 {
+			hb := poolNodeHeartbeat{
+				name: node.Name,
+				lost: currentReadyCondition != nil && currentReadyCondition.Status == v1.ConditionUnknown,
+			}
+			if nodeHealth := nc.nodeHealthMap.getDeepCopy(node.Name); nodeHealth != nil {
+				hb.probeTimestamp = nodeHealth.probeTimestamp
+			}
+			poolToHeartbeatsLock.Lock()
+			poolToHeartbeats[pool] = append(poolToHeartbeats[pool], hb)
+			poolToHeartbeatsLock.Unlock()
+		}
+
 		if currentReadyCondition != nil {
 			pods, err := nc.getPodsAssignedToNode(node.Name)
 			if err != nil {
@@ -730,6 +756,7 @@ func (nc *ReconcileNodeLifeCycle) monitorNodeHealth(ctx context.Context) error {
 	workqueue.ParallelizeUntil(ctx, nc.nodeUpdateWorkerSize, len(nodes), updateNodeFunc)
 
 	nc.handleDisruption(ctx, zoneToNodeConditions, nodes)
+	nc.handlePoolDisconnection(ctx, poolToHeartbeats, nodes)
 
 	return nil
 }
@@ -1204,19 +1231,25 @@ func (nc *ReconcileNodeLifeCycle) addPodEvictorForNewZone(node *v1.Node) {
 func (nc *ReconcileNodeLifeCycle) markNodeForTainting(node *v1.Node, status v1.ConditionStatus) bool {
 	nc.evictorLock.Lock()
 	defer nc.evictorLock.Unlock()
+	tainter := nc.noExecuteTainterFor(node)
+	if tainter == nil {
+		// Evictions are suppressed in the disconnected NodePool.
+		return false
+	}
+
 	if status == v1.ConditionFalse {
 		if !taintutils.TaintExists(node.Spec.Taints, NotReadyTaintTemplate) {
-			nc.zoneNoExecuteTainter[nodetopology.GetZoneKey(node)].Remove(node.Name)
+			tainter.Remove(node.Name)
 		}
 	}
 
 	if status == v1.ConditionUnknown {
 		if !taintutils.TaintExists(node.Spec.Taints, UnreachableTaintTemplate) {
-			nc.zoneNoExecuteTainter[nodetopology.GetZoneKey(node)].Remove(node.Name)
+			tainter.Remove(node.Name)
 		}
 	}
 
-	return nc.zoneNoExecuteTainter[nodetopology.GetZoneKey(node)].Add(node.Name, string(node.UID))
+	return tainter.Add(node.Name, string(node.UID))
 }
 
 func (nc *ReconcileNodeLifeCycle) markNodeAsReachable(ctx context.Context, node *v1.Node) (bool, error) {
@@ -1233,6 +1266,9 @@ func (nc *ReconcileNodeLifeCycle) markNodeAsReachable(ctx context.Context, node
 	nc.evictorLock.Lock()
 	defer nc.evictorLock.Unlock()
 
+	if tainter, ok := nc.poolNoExecuteTainter[getNodePoolName(node)]; ok {
+		tainter.Remove(node.Name)
+	}
 	return nc.zoneNoExecuteTainter[nodetopology.GetZoneKey(node)].Remove(node.Name), nil
 }
 
@@ -73,6 +73,8 @@ func newNodeLifecycleControllerFromClient(
 		nodeHealthMap:               newNodeHealthMap(),
 		nodeUpdateWorkerSize:        scheduler.UpdateWorkerSize,
 		zoneNoExecuteTainter:        make(map[string]*scheduler.RateLimitedTimedQueue),
+		disconnectedPools:           make(map[string]bool),
+		poolNoExecuteTainter:        make(map[string]*scheduler.RateLimitedTimedQueue),
 		nodesToRetry:                sync.Map{},
 		zoneStates:                  make(map[string]ZoneState),
 		nodeMonitorPeriod:           nodeMonitorPeriod,
@@ -0,0 +1,260 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelifecycle
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/flowcontrol"
+	nodetopology "k8s.io/component-helpers/node/topology"
+	"k8s.io/klog/v2"
+
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/nodelifecycle/scheduler"
+	nodepoolutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/nodepool"
+)
+
+const (
+	// minPoolSizeForPartition is the min number of nodes in a NodePool for detecting partition,
+	// a single node which stops renewing heartbeat can not be distinguished from node failure.
+	minPoolSizeForPartition = 2
+
+	poolDisconnectedReason = "HeartbeatsLost"
+	poolConnectedReason    = "HeartbeatsRenewed"
+)
+
+// poolNodeHeartbeat is the heartbeat state of a node in NodePool
+type poolNodeHeartbeat struct {
+	name string
+	// lost is true if the node stops renewing heartbeat, i.e. the Ready condition is Unknown
+	lost bool
+	// probeTimestamp is the last time when the heartbeat of node was observed
+	probeTimestamp metav1.Time
+}
+
+// getNodePoolName returns the NodePool of node, empty string is returned if node doesn't belong to any pool.
+func getNodePoolName(node *v1.Node) string {
+	return node.Labels[projectinfo.GetNodePoolLabel()]
+}
+
+// isPoolDisconnected returns true if the NodePool is disconnected, it must be called with evictorLock held.
+func (nc *ReconcileNodeLifeCycle) isPoolDisconnected(pool string) bool {
+	return len(pool) != 0 && nc.disconnectedPools[pool]
+}
+
+// noExecuteTainterFor returns the worker which is responsible for tainting the node, nodes in disconnected
+// NodePools are tainted by the pool worker, and nil is returned if evictions are suppressed in the pool.
+// It must be called with evictorLock held.
+func (nc *ReconcileNodeLifeCycle) noExecuteTainterFor(node *v1.Node) *scheduler.RateLimitedTimedQueue {
+	if pool := getNodePoolName(node); nc.isPoolDisconnected(pool) {
+		return nc.poolNoExecuteTainter[pool]
+	}
+	return nc.zoneNoExecuteTainter[nodetopology.GetZoneKey(node)]
+}
+
+// computePoolState returns true if the NodePool is disconnected from cloud. A NodePool is disconnected if
+// at least poolDisconnectedThreshold of its nodes stop renewing heartbeats within poolHeartbeatCorrelationWindow,
+// and none of the leader hubs of the pool keeps renewing heartbeat.
+func (nc *ReconcileNodeLifeCycle) computePoolState(pool *appsv1beta2.NodePool, heartbeats []poolNodeHeartbeat) (bool, string) {
+	if len(heartbeats) < minPoolSizeForPartition {
+		return false, fmt.Sprintf("at least %d nodes are required to detect partition, but pool has %d", minPoolSizeForPartition, len(heartbeats))
+	}
+
+	lostNodes := make(map[string]bool)
+	var lostTimestamps []time.Time
+	for _, hb := range heartbeats {
+		if hb.lost {
+			lostNodes[hb.name] = true
+			lostTimestamps = append(lostTimestamps, hb.probeTimestamp.Time)
+		}
+	}
+
+	// find the max number of nodes which lost heartbeats within the correlation window
+	slices.SortFunc(lostTimestamps, func(a, b time.Time) int { return a.Compare(b) })
+	together := 0
+	for i, j := 0, 0; j < len(lostTimestamps); j++ {
+		for lostTimestamps[j].Sub(lostTimestamps[i]) > nc.poolHeartbeatCorrelationWindow {
+			i++
+		}
+		together = max(together, j-i+1)
+	}
+
+	if together == 0 || float32(together)/float32(len(heartbeats)) < nc.poolDisconnectedThreshold {
+		return false, fmt.Sprintf("%d/%d nodes stopped renewing heartbeats together", together, len(heartbeats))
+	}
+
+	// the uplink of pool still works if any leader hub keeps renewing heartbeat
+	for _, leader := range pool.Status.LeaderEndpoints {
+		if !lostNodes[leader.NodeName] {
+			return false, fmt.Sprintf("%d/%d nodes stopped renewing heartbeats together, but leader hub %s is still renewing heartbeat",
+				together, len(heartbeats), leader.NodeName)
+		}
+	}
+	return true, fmt.Sprintf("%d/%d nodes stopped renewing heartbeats within %v", together, len(heartbeats), nc.poolHeartbeatCorrelationWindow)
+}
+
+// handlePoolDisconnection computes the connectivity state of NodePools, marks NodePools with PoolDisconnected condition,
+// and applies the pool-wide eviction policy to the nodes in disconnected NodePools:
+//   - when a NodePool becomes disconnected, its nodes are removed from the zone taint queues, and they are tainted
+//     by the pool worker with poolDisconnectedEvictionQPS. If the rate is 0, evictions are suppressed and the taints
+//     which have been added are removed,
+//   - when a NodePool is connected again, the probe timestamps of its nodes are reset so that they are not evicted
+//     before the grace period.
+func (nc *ReconcileNodeLifeCycle) handlePoolDisconnection(ctx context.Context, poolToHeartbeats map[string][]poolNodeHeartbeat, nodes []*v1.Node) {
+	nc.evictorLock.Lock()
+	for pool := range nc.disconnectedPools {
+		if _, ok := poolToHeartbeats[pool]; !ok {
+			poolDisconnected.WithLabelValues(pool).Set(0)
+			delete(nc.disconnectedPools, pool)
+			delete(nc.poolNoExecuteTainter, pool)
+		}
+	}
+	nc.evictorLock.Unlock()
+
+	for poolName, heartbeats := range poolToHeartbeats {
+		pool := &appsv1beta2.NodePool{}
+		if err := nc.controllerRuntimeClient.Get(ctx, types.NamespacedName{Name: poolName}, pool); err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.ErrorS(err, "could not get NodePool", "nodepool", poolName)
+			}
+			continue
+		}
+
+		disconnected, message := nc.computePoolState(pool, heartbeats)
+		nc.evictorLock.Lock()
+		wasDisconnected := nc.isPoolDisconnected(poolName)
+		nc.evictorLock.Unlock()
+
+		if disconnected && !wasDisconnected {
+			klog.InfoS("Controller detected that NodePool is disconnected", "nodepool", poolName, "reason", message)
+			nc.recorder.Eventf(pool, v1.EventTypeWarning, "PoolDisconnected", "NodePool %s is disconnected: %s", poolName, message)
+			nc.enterPoolDisconnection(ctx, poolName, nodes)
+		} else if !disconnected && wasDisconnected {
+			klog.InfoS("Controller detected that NodePool is connected again", "nodepool", poolName)
+			nc.recorder.Eventf(pool, v1.EventTypeNormal, "PoolReconnected", "NodePool %s is connected again", poolName)
+			nc.exitPoolDisconnection(poolName, nodes)
+		}
+
+		if disconnected {
+			poolDisconnected.WithLabelValues(poolName).Set(1)
+		} else {
+			poolDisconnected.WithLabelValues(poolName).Set(0)
+		}
+		if err := nc.updatePoolDisconnectedCondition(ctx, pool, disconnected); err != nil {
+			klog.ErrorS(err, "could not update PoolDisconnected condition of NodePool", "nodepool", poolName)
+		}
+	}
+}
+
+func (nc *ReconcileNodeLifeCycle) enterPoolDisconnection(ctx context.Context, poolName string, nodes []*v1.Node) {
+	poolNodes := filterPoolNodes(poolName, nodes)
+	func() {
+		nc.evictorLock.Lock()
+		defer nc.evictorLock.Unlock()
+		nc.disconnectedPools[poolName] = true
+		if nc.poolDisconnectedEvictionQPS > 0 {
+			nc.poolNoExecuteTainter[poolName] = scheduler.NewRateLimitedTimedQueue(
+				flowcontrol.NewTokenBucketRateLimiter(nc.poolDisconnectedEvictionQPS, scheduler.EvictionRateLimiterBurst))
+		}
+		// nodes will be queued by the pool worker in the next pass if they need to be tainted
+		for _, node := range poolNodes {
+			if tainter, ok := nc.zoneNoExecuteTainter[nodetopology.GetZoneKey(node)]; ok {
+				tainter.Remove(node.Name)
+			}
+		}
+	}()
+
+	if nc.poolDisconnectedEvictionQPS > 0 {
+		return
+	}
+	// evictions are suppressed, remove the taints which have been added before the partition is detected.
+	for _, node := range poolNodes {
+		if _, err := nc.markNodeAsReachable(ctx, node); err != nil {
+			klog.ErrorS(err, "could not remove taints from Node in disconnected NodePool", "node", klog.KObj(node))
+		}
+	}
+}
+
+func (nc *ReconcileNodeLifeCycle) exitPoolDisconnection(poolName string, nodes []*v1.Node) {
+	func() {
+		nc.evictorLock.Lock()
+		defer nc.evictorLock.Unlock()
+		delete(nc.disconnectedPools, poolName)
+		delete(nc.poolNoExecuteTainter, poolName)
+	}()
+
+	// update probe timestamps of nodes in the pool, so they are not evicted before grace period.
+	now := nc.now()
+	for _, node := range filterPoolNodes(poolName, nodes) {
+		v := nc.nodeHealthMap.getDeepCopy(node.Name)
+		if v == nil {
+			continue
+		}
+		v.probeTimestamp = now
+		v.readyTransitionTimestamp = now
+		nc.nodeHealthMap.set(node.Name, v)
+	}
+}
+
+// updatePoolDisconnectedCondition updates the PoolDisconnected condition of NodePool only when its status or reason
+// is changed. The message of condition doesn't contain the numbers of nodes which change with every heartbeat,
+// so the NodePool is not updated on every pass of the monitor, the details are recorded by events instead.
+func (nc *ReconcileNodeLifeCycle) updatePoolDisconnectedCondition(ctx context.Context, pool *appsv1beta2.NodePool, disconnected bool) error {
+	cond := appsv1beta2.NodePoolCondition{
+		Type:    appsv1beta2.PoolDisconnected,
+		Status:  v1.ConditionFalse,
+		Reason:  poolConnectedReason,
+		Message: "NodePool is connected to cloud",
+	}
+	if disconnected {
+		cond.Status = v1.ConditionTrue
+		cond.Reason = poolDisconnectedReason
+		cond.Message = fmt.Sprintf("nodes of NodePool stopped renewing heartbeats within %v, and none of the leader hubs is renewing heartbeat",
+			nc.poolHeartbeatCorrelationWindow)
+	}
+
+	for i := range pool.Status.Conditions {
+		existing := pool.Status.Conditions[i]
+		if existing.Type == cond.Type && existing.Status == cond.Status && existing.Reason == cond.Reason {
+			return nil
+		}
+	}
+
+	updatedPool := pool.DeepCopy()
+	if !nodepoolutil.SetNodePoolCondition(&updatedPool.Status, cond) {
+		return nil
+	}
+	return nc.controllerRuntimeClient.Status().Update(ctx, updatedPool)
+}
+
+func filterPoolNodes(poolName string, nodes []*v1.Node) []*v1.Node {
+	var poolNodes []*v1.Node
+	for _, node := range nodes {
+		if getNodePoolName(node) == poolName {
+			poolNodes = append(poolNodes, node)
+		}
+	}
+	return poolNodes
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openyurtio/openyurt/pkg/apis"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	taintutils "github.com/openyurtio/openyurt/pkg/util/taints"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/testutil"
+)
+
+func TestComputePoolState(t *testing.T) {
+	fakeNow := metav1.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	heartbeat := func(name string, lost bool, ago time.Duration) poolNodeHeartbeat {
+		return poolNodeHeartbeat{name: name, lost: lost, probeTimestamp: metav1.NewTime(fakeNow.Add(-ago))}
+	}
+
+	testCases := map[string]struct {
+		leaders      []string
+		heartbeats   []poolNodeHeartbeat
+		disconnected bool
+	}{
+		"single node pool": {
+			heartbeats: []poolNodeHeartbeat{heartbeat("node0", true, 0)},
+		},
+		"all nodes renew heartbeats": {
+			heartbeats: []poolNodeHeartbeat{
+				heartbeat("node0", false, 0),
+				heartbeat("node1", false, 0),
+			},
+		},
+		"all nodes lose heartbeats together": {
+			heartbeats: []poolNodeHeartbeat{
+				heartbeat("node0", true, 10*time.Second),
+				heartbeat("node1", true, 20*time.Second),
+				heartbeat("node2", true, 30*time.Second),
+			},
+			disconnected: true,
+		},
+		"nodes lose heartbeats one by one": {
+			heartbeats: []poolNodeHeartbeat{
+				heartbeat("node0", true, 0),
+				heartbeat("node1", true, 5*time.Minute),
+				heartbeat("node2", true, 10*time.Minute),
+			},
+		},
+		"most nodes lose heartbeats together": {
+			heartbeats: []poolNodeHeartbeat{
+				heartbeat("node0", true, 0),
+				heartbeat("node1", true, 10*time.Second),
+				heartbeat("node2", true, 20*time.Second),
+				heartbeat("node3", true, 30*time.Second),
+				heartbeat("node4", false, 0),
+			},
+			disconnected: true,
+		},
+		"leader hub keeps renewing heartbeat": {
+			leaders: []string{"node2"},
+			heartbeats: []poolNodeHeartbeat{
+				heartbeat("node0", true, 0),
+				heartbeat("node1", true, 0),
+				heartbeat("node2", false, 0),
+			},
+		},
+		"leader hub loses heartbeat too": {
+			leaders: []string{"node0"},
+			heartbeats: []poolNodeHeartbeat{
+				heartbeat("node0", true, 0),
+				heartbeat("node1", true, 0),
+			},
+			disconnected: true,
+		},
+	}
+
+	nc := &ReconcileNodeLifeCycle{
+		poolDisconnectedThreshold:      0.8,
+		poolHeartbeatCorrelationWindow: 30 * time.Second,
+	}
+	for k, tc := range testCases {
+		t.Run(k, func(t *testing.T) {
+			pool := &appsv1beta2.NodePool{}
+			for _, leader := range tc.leaders {
+				pool.Status.LeaderEndpoints = append(pool.Status.LeaderEndpoints, appsv1beta2.Leader{NodeName: leader})
+			}
+			if disconnected, message := nc.computePoolState(pool, tc.heartbeats); disconnected != tc.disconnected {
+				t.Errorf("expected disconnected %v, but got %v: %s", tc.disconnected, disconnected, message)
+			}
+		})
+	}
+}
+
+func TestHandlePoolDisconnection(t *testing.T) {
+	fakeNow := metav1.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newPoolNode := func(name string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					v1.LabelTopologyRegion:         "region1",
+					v1.LabelTopologyZone:           "zone1",
+					projectinfo.GetNodePoolLabel(): "edge",
+				},
+			},
+			Spec: v1.NodeSpec{
+				Taints: []v1.Taint{*UnreachableTaintTemplate},
+			},
+		}
+	}
+	nodes := []*v1.Node{newPoolNode("node0"), newPoolNode("node1")}
+	pool := &appsv1beta2.NodePool{ObjectMeta: metav1.ObjectMeta{Name: "edge"}}
+
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	apis.AddToScheme(scheme)
+	poolClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(pool).WithStatusSubresource(pool).Build()
+
+	ctx := context.TODO()
+	fakeNodeHandler := testutil.NewImprovedFakeNodeHandler(nodes, nil)
+	nc, _ := newNodeLifecycleControllerFromClient(
+		ctx,
+		fakeNodeHandler,
+		testRateLimiterQPS,
+		testRateLimiterQPS,
+		testLargeClusterThreshold,
+		testUnhealthyThreshold,
+		testNodeMonitorGracePeriod,
+		testNodeStartupGracePeriod,
+		testNodeMonitorPeriod,
+	)
+	nc.controllerRuntimeClient = poolClient
+	nc.now = func() metav1.Time { return fakeNow }
+	nc.poolDisconnectedThreshold = 0.8
+	nc.poolHeartbeatCorrelationWindow = 30 * time.Second
+	for _, node := range nodes {
+		nc.addPodEvictorForNewZone(node)
+		nc.nodeHealthMap.set(node.Name, &nodeHealthData{probeTimestamp: metav1.NewTime(fakeNow.Add(-time.Hour))})
+	}
+
+	// both nodes lose heartbeats together, evictions in the pool are suppressed
+	lost := []poolNodeHeartbeat{
+		{name: "node0", lost: true, probeTimestamp: metav1.NewTime(fakeNow.Add(-time.Hour))},
+		{name: "node1", lost: true, probeTimestamp: metav1.NewTime(fakeNow.Add(-time.Hour))},
+	}
+	nc.handlePoolDisconnection(ctx, map[string][]poolNodeHeartbeat{"edge": lost}, nodes)
+	if !nc.isPoolDisconnected("edge") {
+		t.Fatalf("expected pool edge to be disconnected")
+	}
+	if tainter := nc.noExecuteTainterFor(nodes[0]); tainter != nil {
+		t.Errorf("expected no tainter for nodes in disconnected pool when evictions are suppressed")
+	}
+	for _, node := range nodes {
+		current, err := fakeNodeHandler.DelegateNodeHandler.Get(ctx, node.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("could not get node %s: %v", node.Name, err)
+		}
+		if taintutils.TaintExists(current.Spec.Taints, UnreachableTaintTemplate) {
+			t.Errorf("expected unreachable taint to be removed from node %s", node.Name)
+		}
+	}
+	assertPoolDisconnectedCondition(t, nc, v1.ConditionTrue)
+
+	// one node renews heartbeat, the pool is connected again
+	renewed := []poolNodeHeartbeat{
+		lost[0],
+		{name: "node1", lost: false, probeTimestamp: fakeNow},
+	}
+	nc.handlePoolDisconnection(ctx, map[string][]poolNodeHeartbeat{"edge": renewed}, nodes)
+	if nc.isPoolDisconnected("edge") {
+		t.Fatalf("expected pool edge to be connected")
+	}
+	if v := nc.nodeHealthMap.getDeepCopy("node0"); !v.probeTimestamp.Equal(&fakeNow) {
+		t.Errorf("expected probe timestamp of node0 to be reset to %v, but got %v", fakeNow, v.probeTimestamp)
+	}
+	assertPoolDisconnectedCondition(t, nc, v1.ConditionFalse)
+
+	// the number of nodes renewing heartbeats changes, but the pool is not updated
+	before := &appsv1beta2.NodePool{}
+	if err := poolClient.Get(ctx, types.NamespacedName{Name: "edge"}, before); err != nil {
+		t.Fatalf("could not get pool: %v", err)
+	}
+	allRenewed := []poolNodeHeartbeat{
+		{name: "node0", lost: false, probeTimestamp: fakeNow},
+		renewed[1],
+	}
+	nc.handlePoolDisconnection(ctx, map[string][]poolNodeHeartbeat{"edge": allRenewed}, nodes)
+	after := &appsv1beta2.NodePool{}
+	if err := poolClient.Get(ctx, types.NamespacedName{Name: "edge"}, after); err != nil {
+		t.Fatalf("could not get pool: %v", err)
+	}
+	if before.ResourceVersion != after.ResourceVersion {
+		t.Errorf("expected pool not to be updated when PoolDisconnected condition is not changed")
+	}
+}
+
+func assertPoolDisconnectedCondition(t *testing.T, nc *ReconcileNodeLifeCycle, status v1.ConditionStatus) {
+	t.Helper()
+	pool := &appsv1beta2.NodePool{}
+	if err := nc.controllerRuntimeClient.Get(context.TODO(), types.NamespacedName{Name: "edge"}, pool); err != nil {
+		t.Fatalf("could not get pool: %v", err)
+	}
+	for _, cond := range pool.Status.Conditions {
+		if cond.Type == appsv1beta2.PoolDisconnected {
+			if cond.Status != status {
+				t.Errorf("expected PoolDisconnected condition %s, but got %s", status, cond.Status)
+			}
+			return
+		}
+	}
+	t.Errorf("PoolDisconnected condition is not found")
+}
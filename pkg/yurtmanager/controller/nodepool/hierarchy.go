@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"context"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog/v2"
+
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	nodepoolutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/nodepool"
+)
+
+// resolveHierarchy returns the ancestors of nodePool from its parent to the root, and the aggregated status
+// of its descendants. The status of child NodePools already aggregates their own descendants, so only
+// child NodePools are summed up.
+func (r *ReconcileNodePool) resolveHierarchy(ctx context.Context, nodePool *appsv1beta2.NodePool) ([]string, *appsv1beta2.NodePoolDescendantsStatus, error) {
+	var poolList appsv1beta2.NodePoolList
+	if err := r.List(ctx, &poolList); err != nil {
+		return nil, nil, err
+	}
+
+	ancestors, err := nodepoolutil.Ancestors(nodePool.Name, nodepoolutil.ParentsOf(poolList.Items))
+	if err != nil {
+		// nodes would be counted repeatedly if descendants in a cycle are aggregated
+		klog.Warning(Format("skip resolving hierarchy of nodepool(%s), %v", nodePool.Name, err))
+		return nil, nil, nil
+	}
+
+	children := nodepoolutil.ChildrenOf(nodePool.Name, poolList.Items)
+	if len(children) == 0 {
+		return ancestors, nil, nil
+	}
+
+	descendants := &appsv1beta2.NodePoolDescendantsStatus{ChildPools: children}
+	for i := range poolList.Items {
+		child := &poolList.Items[i]
+		if child.Spec.Parent != nodePool.Name || child.Name == nodePool.Name {
+			continue
+		}
+		descendants.ReadyNodeNum += child.Status.ReadyNodeNum
+		descendants.UnreadyNodeNum += child.Status.UnreadyNodeNum
+		if child.Status.Descendants != nil {
+			descendants.ReadyNodeNum += child.Status.Descendants.ReadyNodeNum
+			descendants.UnreadyNodeNum += child.Status.Descendants.UnreadyNodeNum
+		}
+	}
+	return ancestors, descendants, nil
+}
+
+// conciliateDescendants updates the aggregated status of descendants, it returns true if the status is changed.
+func conciliateDescendants(descendants *appsv1beta2.NodePoolDescendantsStatus, nodePool *appsv1beta2.NodePool) bool {
+	if apiequality.Semantic.DeepEqual(descendants, nodePool.Status.Descendants) {
+		return false
+	}
+	nodePool.Status.Descendants = descendants
+	return true
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openyurtio/openyurt/pkg/apis"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+)
+
+func TestReconcileHierarchy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	apis.AddToScheme(scheme)
+
+	newPool := func(name, parent string, ready, unready int32, descendants *appsv1beta2.NodePoolDescendantsStatus) *appsv1beta2.NodePool {
+		return &appsv1beta2.NodePool{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       appsv1beta2.NodePoolSpec{Type: appsv1beta2.Edge, Parent: parent},
+			Status:     appsv1beta2.NodePoolStatus{ReadyNodeNum: ready, UnreadyNodeNum: unready, Descendants: descendants},
+		}
+	}
+	pools := []client.Object{
+		newPool("china", "", 0, 0, nil),
+		newPool("east", "china", 0, 0, &appsv1beta2.NodePoolDescendantsStatus{ChildPools: []string{"hangzhou"}, ReadyNodeNum: 3, UnreadyNodeNum: 1}),
+		newPool("north", "china", 2, 0, nil),
+		newPool("hangzhou", "east", 3, 1, nil),
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node1",
+			Labels: map[string]string{
+				projectinfo.GetNodePoolLabel():             "hangzhou",
+				"ancestor.nodepool.openyurt.io/old-region": "true",
+			},
+		},
+	}
+
+	c := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pools...).
+		WithStatusSubresource(pools...).
+		WithObjects(node).
+		WithIndex(&corev1.Pod{}, "spec.nodeName", podIndexer).
+		Build()
+	r := &ReconcileNodePool{Client: c}
+	ctx := context.TODO()
+
+	// descendants of root pool are aggregated from child pools
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "china"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var root appsv1beta2.NodePool
+	if err := c.Get(ctx, types.NamespacedName{Name: "china"}, &root); err != nil {
+		t.Fatalf("could not get pool, %v", err)
+	}
+	wantedDescendants := &appsv1beta2.NodePoolDescendantsStatus{ChildPools: []string{"east", "north"}, ReadyNodeNum: 5, UnreadyNodeNum: 1}
+	if !reflect.DeepEqual(root.Status.Descendants, wantedDescendants) {
+		t.Errorf("expect descendants %#v, but got %#v", wantedDescendants, root.Status.Descendants)
+	}
+
+	// leaf pool and its nodes are labeled with ancestors
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "hangzhou"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	wantedLabels := map[string]string{
+		"ancestor.nodepool.openyurt.io/east":  "true",
+		"ancestor.nodepool.openyurt.io/china": "true",
+	}
+	var leaf appsv1beta2.NodePool
+	if err := c.Get(ctx, types.NamespacedName{Name: "hangzhou"}, &leaf); err != nil {
+		t.Fatalf("could not get pool, %v", err)
+	}
+	if !reflect.DeepEqual(leaf.Labels, wantedLabels) {
+		t.Errorf("expect pool labels %v, but got %v", wantedLabels, leaf.Labels)
+	}
+	if leaf.Status.Descendants != nil {
+		t.Errorf("expect no descendants for leaf pool, but got %#v", leaf.Status.Descendants)
+	}
+
+	var currentNode corev1.Node
+	if err := c.Get(ctx, types.NamespacedName{Name: "node1"}, &currentNode); err != nil {
+		t.Fatalf("could not get node, %v", err)
+	}
+	wantedLabels[projectinfo.GetNodePoolLabel()] = "hangzhou"
+	if !reflect.DeepEqual(currentNode.Labels, wantedLabels) {
+		t.Errorf("expect node labels %v, but got %v", wantedLabels, currentNode.Labels)
+	}
+}
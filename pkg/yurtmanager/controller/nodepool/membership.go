@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	nodepoolutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/nodepool"
+)
+
+// assignNodes assigns nodes to nodepools according to the nodeSelector of nodepools. Both nodes that match
+// the nodeSelector of nodePool and nodes in nodePool are checked, so nodes that don't match nodePool anymore
+// can be moved to other nodepools. Nodes which can not be assigned are annotated with the conflict, so the
+// NodePoolConflict event is emitted only once for a conflict instead of on every reconcile.
+func (r *ReconcileNodePool) assignNodes(ctx context.Context, nodePool *appsv1beta2.NodePool) error {
+	if nodePool.Spec.NodeSelector == nil {
+		return nil
+	}
+
+	var poolList appsv1beta2.NodePoolList
+	if err := r.List(ctx, &poolList); err != nil {
+		return err
+	}
+	var nodeList corev1.NodeList
+	if err := r.List(ctx, &nodeList); err != nil {
+		return err
+	}
+
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		current := node.Labels[projectinfo.GetNodePoolLabel()]
+		conflict, hasConflict := node.Annotations[apps.AnnotationNodePoolConflict]
+		if current != nodePool.Name && !hasConflict {
+			if matched, err := nodepoolutil.MatchNodeSelector(nodePool.Spec.NodeSelector, node); err != nil {
+				return err
+			} else if !matched {
+				continue
+			}
+		}
+
+		desired, _, err := nodepoolutil.SelectNodePool(node, poolList.Items)
+		if err != nil {
+			// the conflict has been reported already
+			if hasConflict && conflict == err.Error() {
+				continue
+			}
+			klog.Warning(Format("could not assign node(%s) to nodepool, %v", node.Name, err))
+			patch := client.MergeFrom(node.DeepCopy())
+			if node.Annotations == nil {
+				node.Annotations = make(map[string]string)
+			}
+			node.Annotations[apps.AnnotationNodePoolConflict] = err.Error()
+			if err := r.Patch(ctx, node, patch); err != nil {
+				klog.Error(Format("Patch Node %s error %v", node.Name, err))
+				return err
+			}
+			r.recorder.Event(node, corev1.EventTypeWarning, apps.NodePoolConflictEvent, err.Error())
+			continue
+		}
+		if desired == current && !hasConflict {
+			continue
+		}
+
+		patch := client.MergeFrom(node.DeepCopy())
+		delete(node.Annotations, apps.AnnotationNodePoolConflict)
+		if desired != current {
+			if node.Labels == nil {
+				node.Labels = make(map[string]string)
+			}
+			node.Labels[projectinfo.GetNodePoolLabel()] = desired
+		}
+		if err := r.Patch(ctx, node, patch); err != nil {
+			klog.Error(Format("Patch Node %s error %v", node.Name, err))
+			return err
+		}
+		if desired == current {
+			klog.Info(Format("the nodepool conflict of node(%s) is resolved", node.Name))
+			continue
+		}
+		klog.Info(Format("node(%s) is moved from nodepool(%s) to nodepool(%s)", node.Name, current, desired))
+		r.recorder.Eventf(node, corev1.EventTypeNormal, apps.NodePoolReassignedEvent,
+			"Node is moved from nodepool %q to nodepool %q by nodeSelector", current, desired)
+	}
+	return nil
+}
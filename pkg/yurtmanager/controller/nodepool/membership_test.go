@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openyurtio/openyurt/pkg/apis"
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+)
+
+func newSelectorPool(name string, selector *appsv1beta2.NodePoolNodeSelector) *appsv1beta2.NodePool {
+	return &appsv1beta2.NodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: appsv1beta2.NodePoolSpec{
+			Type:         appsv1beta2.Edge,
+			NodeSelector: selector,
+		},
+	}
+}
+
+func newSelectorNode(name, pool, zone, internalIP string) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{corev1.LabelTopologyZone: zone},
+		},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: internalIP}},
+		},
+	}
+	if len(pool) != 0 {
+		node.Labels[projectinfo.GetNodePoolLabel()] = pool
+	}
+	return node
+}
+
+func TestAssignNodes(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	apis.AddToScheme(scheme)
+
+	zoneSelector := func(zone string) *appsv1beta2.NodePoolNodeSelector {
+		return &appsv1beta2.NodePoolNodeSelector{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelTopologyZone: zone}},
+		}
+	}
+	objs := []client.Object{
+		newSelectorPool("zone-a", zoneSelector("a")),
+		newSelectorPool("zone-b", zoneSelector("b")),
+		newSelectorPool("lan", &appsv1beta2.NodePoolNodeSelector{InternalIPCIDRs: []string{"10.0.0.0/24"}}),
+		newSelectorPool("manual", nil),
+		// node without nodepool is assigned
+		newSelectorNode("node1", "", "a", "192.168.0.1"),
+		// node which doesn't match zone-a anymore is moved to zone-b
+		newSelectorNode("node2", "zone-a", "b", "192.168.0.2"),
+		// node in nodepool without nodeSelector is not moved
+		newSelectorNode("node3", "manual", "a", "192.168.0.3"),
+		// node matches both zone-a and lan
+		newSelectorNode("node4", "", "a", "10.0.0.4"),
+		// node doesn't match any nodepool
+		newSelectorNode("node5", "", "c", "192.168.0.5"),
+	}
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileNodePool{Client: c, recorder: recorder}
+
+	ctx := context.TODO()
+	for _, name := range []string{"zone-a", "zone-b", "lan", "manual"} {
+		var pool appsv1beta2.NodePool
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, &pool); err != nil {
+			t.Fatalf("could not get pool %s, %v", name, err)
+		}
+		if err := r.assignNodes(ctx, &pool); err != nil {
+			t.Fatalf("assignNodes(%s) error = %v", name, err)
+		}
+	}
+
+	wantedPools := map[string]string{
+		"node1": "zone-a",
+		"node2": "zone-b",
+		"node3": "manual",
+		"node4": "",
+		"node5": "",
+	}
+	for nodeName, wanted := range wantedPools {
+		var node corev1.Node
+		if err := c.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+			t.Fatalf("could not get node %s, %v", nodeName, err)
+		}
+		if got := node.Labels[projectinfo.GetNodePoolLabel()]; got != wanted {
+			t.Errorf("expect node %s in pool %q, but got %q", nodeName, wanted, got)
+		}
+	}
+
+	var reassigned, conflicts int
+	for len(recorder.Events) != 0 {
+		event := <-recorder.Events
+		if strings.Contains(event, apps.NodePoolReassignedEvent) {
+			reassigned++
+		} else if strings.Contains(event, apps.NodePoolConflictEvent) {
+			conflicts++
+		}
+	}
+	if reassigned != 2 {
+		t.Errorf("expect 2 reassigned events, but got %d", reassigned)
+	}
+	if conflicts != 1 {
+		t.Errorf("expect 1 conflict event for node4, but got %d", conflicts)
+	}
+
+	var node4 corev1.Node
+	if err := c.Get(ctx, types.NamespacedName{Name: "node4"}, &node4); err != nil {
+		t.Fatalf("could not get node node4, %v", err)
+	}
+	if _, ok := node4.Annotations[apps.AnnotationNodePoolConflict]; !ok {
+		t.Errorf("expect node4 annotated with nodepool conflict")
+	}
+
+	// the conflict is resolved when lan doesn't select node4 anymore
+	var lan appsv1beta2.NodePool
+	if err := c.Get(ctx, types.NamespacedName{Name: "lan"}, &lan); err != nil {
+		t.Fatalf("could not get pool lan, %v", err)
+	}
+	lan.Spec.NodeSelector.InternalIPCIDRs = []string{"10.0.1.0/24"}
+	if err := c.Update(ctx, &lan); err != nil {
+		t.Fatalf("could not update pool lan, %v", err)
+	}
+	var zoneA appsv1beta2.NodePool
+	if err := c.Get(ctx, types.NamespacedName{Name: "zone-a"}, &zoneA); err != nil {
+		t.Fatalf("could not get pool zone-a, %v", err)
+	}
+	if err := r.assignNodes(ctx, &zoneA); err != nil {
+		t.Fatalf("assignNodes(zone-a) error = %v", err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "node4"}, &node4); err != nil {
+		t.Fatalf("could not get node node4, %v", err)
+	}
+	if got := node4.Labels[projectinfo.GetNodePoolLabel()]; got != "zone-a" {
+		t.Errorf("expect node node4 in pool zone-a, but got %q", got)
+	}
+	if _, ok := node4.Annotations[apps.AnnotationNodePoolConflict]; ok {
+		t.Errorf("expect nodepool conflict annotation removed from node4")
+	}
+}
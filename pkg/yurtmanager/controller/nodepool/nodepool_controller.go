@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -34,14 +35,17 @@ import (
 	yurtClient "github.com/openyurtio/openyurt/cmd/yurt-manager/app/client"
 	"github.com/openyurtio/openyurt/cmd/yurt-manager/app/config"
 	"github.com/openyurtio/openyurt/cmd/yurt-manager/names"
+	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
 	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
 	"github.com/openyurtio/openyurt/pkg/projectinfo"
 	poolconfig "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/nodepool/config"
 	nodeutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/node"
+	nodepoolutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/nodepool"
 )
 
 var (
 	controllerResource = appsv1beta2.SchemeGroupVersion.WithResource("nodepools")
+	quotaResource      = appsv1alpha1.SchemeGroupVersion.WithResource("poolresourcequotas")
 )
 
 func Format(format string, args ...interface{}) string {
@@ -89,17 +93,46 @@ func Add(ctx context.Context, c *config.CompletedConfig, mgr manager.Manager) er
 		return err
 	}
 
+	// Watch for changes to NodePool hierarchy, parent aggregates the status of its children,
+	// and children are labeled with the ancestors
+	err = ctrl.Watch(source.Kind[client.Object](mgr.GetCache(), &appsv1beta2.NodePool{}, &EnqueueNodePoolHierarchy{
+		Reader: mgr.GetCache(),
+	}))
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes to Node
 	err = ctrl.Watch(source.Kind[client.Object](mgr.GetCache(), &corev1.Node{}, &EnqueueNodePoolForNode{
 		EnableSyncNodePoolConfigurations: r.cfg.EnableSyncNodePoolConfigurations,
 		Recorder:                         r.recorder,
+		Reader:                           mgr.GetCache(),
 	}))
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// Watch for changes to Pod, resource requests of pods are aggregated in NodePool status
+	err = ctrl.Watch(source.Kind[client.Object](mgr.GetCache(), &corev1.Pod{}, &EnqueueNodePoolForPod{
+		Reader: mgr.GetCache(),
+	}))
+	if err != nil {
+		return err
+	}
 
+	// Watch for changes to PoolResourceQuota if it's installed
+	if _, err := mgr.GetRESTMapper().KindFor(quotaResource); err != nil {
+		klog.Infof("resource %s doesn't exist, skip watching it", quotaResource.String())
+		return nil
+	}
+	return ctrl.Watch(source.Kind[client.Object](mgr.GetCache(), &appsv1alpha1.PoolResourceQuota{},
+		handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+			quota, ok := obj.(*appsv1alpha1.PoolResourceQuota)
+			if !ok || len(quota.Spec.NodePool) == 0 {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: quota.Spec.NodePool}}}
+		})))
 }
 
 type NodePoolRelatedAttributes struct {
@@ -111,6 +144,9 @@ type NodePoolRelatedAttributes struct {
 // +kubebuilder:rbac:groups=apps.openyurt.io,resources=nodepools,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.openyurt.io,resources=nodepools/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=list;watch
+// +kubebuilder:rbac:groups=apps.openyurt.io,resources=poolresourcequotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps.openyurt.io,resources=poolresourcequotas/status,verbs=update
 
 // Reconcile reads that state of the cluster for a NodePool object and makes changes based on the state read
 // and what is in the NodePool.Spec
@@ -128,6 +164,24 @@ func (r *ReconcileNodePool) Reconcile(ctx context.Context, req reconcile.Request
 	}
 	klog.V(5).Infof("NodePool %s: %#+v", nodePool.Name, nodePool)
 
+	// assign nodes to nodepools by nodeSelector before nodes of nodepool are listed
+	if err := r.assignNodes(ctx, &nodePool); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	ancestors, descendants, err := r.resolveHierarchy(ctx, &nodePool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// label nodepool with its ancestors, so it can be selected by ancestors
+	if labels, changed := nodepoolutil.ConciliateAncestorLabels(nodePool.Labels, ancestors); changed {
+		nodePool.Labels = labels
+		if err := r.Update(ctx, &nodePool); err != nil {
+			klog.Error(Format("Update NodePool %s error %v", nodePool.Name, err))
+			return ctrl.Result{}, err
+		}
+	}
+
 	var currentNodeList corev1.NodeList
 	if err := r.List(ctx, &currentNodeList, client.MatchingLabels(map[string]string{
 		projectinfo.GetNodePoolLabel(): nodePool.GetName(),
@@ -152,22 +206,37 @@ func (r *ReconcileNodePool) Reconcile(ctx context.Context, req reconcile.Request
 		}
 
 		// sync nodepool configurations into node
+		var updated bool
 		if r.cfg.EnableSyncNodePoolConfigurations {
-			updated, err := conciliateNode(&node, &nodePool)
-			if err != nil {
+			if updated, err = conciliateNode(&node, &nodePool); err != nil {
 				return ctrl.Result{}, err
 			}
-			if updated {
-				if err := r.Update(ctx, &node); err != nil {
-					klog.Error(Format("Update Node %s error %v", node.Name, err))
-					return ctrl.Result{}, err
-				}
+		}
+		// label node with ancestors of nodepool, so it can be scheduled by ancestors
+		if labels, changed := nodepoolutil.ConciliateAncestorLabels(node.Labels, ancestors); changed {
+			node.Labels = labels
+			updated = true
+		}
+		if updated {
+			if err := r.Update(ctx, &node); err != nil {
+				klog.Error(Format("Update Node %s error %v", node.Name, err))
+				return ctrl.Result{}, err
 			}
 		}
 	}
 
+	summary, usedByNamespace, err := r.summarizeResources(ctx, currentNodeList.Items)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.updatePoolResourceQuotas(ctx, nodePool.Name, usedByNamespace); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// always update the node pool status if necessary
 	needUpdate := conciliateNodePoolStatus(readyNode, notReadyNode, nodes, &nodePool)
+	needUpdate = conciliateResourceSummary(summary, &nodePool) || needUpdate
+	needUpdate = conciliateDescendants(descendants, &nodePool) || needUpdate
 	if needUpdate {
 		klog.V(5).Infof("nodepool(%s): (%#+v) will be updated", nodePool.Name, nodePool)
 		return ctrl.Result{}, r.Status().Update(ctx, &nodePool)
@@ -151,6 +151,7 @@ func TestReconcile(t *testing.T) {
 		WithObjects(pools...).
 		WithStatusSubresource(pools...).
 		WithObjects(nodes...).
+		WithIndex(&corev1.Pod{}, "spec.nodeName", podIndexer).
 		Build()
 	testcases := map[string]struct {
 		EnableSyncNodePoolConfigurations bool
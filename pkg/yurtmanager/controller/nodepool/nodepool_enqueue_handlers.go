@@ -18,7 +18,6 @@ package nodepool
 
 import (
 	"context"
-	"fmt"
 	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
@@ -26,17 +25,22 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
 	"github.com/openyurtio/openyurt/pkg/projectinfo"
 	nodeutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/node"
+	nodepoolutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/nodepool"
 )
 
 type EnqueueNodePoolForNode struct {
 	EnableSyncNodePoolConfigurations bool
 	Recorder                         record.EventRecorder
+	// Reader is used to list NodePools whose nodeSelector matches the node,
+	// so nodes can be assigned to NodePools automatically.
+	Reader client.Reader
 }
 
 // Create implements EventHandler
@@ -49,6 +53,7 @@ func (e *EnqueueNodePoolForNode) Create(ctx context.Context, evt event.CreateEve
 	}
 	klog.V(5).Info(Format("will enqueue nodepool as node(%s) has been created",
 		node.GetName()))
+	e.enqueueSelectingNodePools(ctx, node, q)
 	if np := node.Labels[projectinfo.GetNodePoolLabel()]; len(np) != 0 {
 		addNodePoolToWorkQueue(np, q)
 		return
@@ -75,6 +80,12 @@ func (e *EnqueueNodePoolForNode) Update(ctx context.Context, evt event.UpdateEve
 	newNp := newNode.Labels[projectinfo.GetNodePoolLabel()]
 	oldNp := oldNode.Labels[projectinfo.GetNodePoolLabel()]
 
+	// node may be selected by other nodepools after its labels or addresses are changed
+	if !reflect.DeepEqual(newNode.Labels, oldNode.Labels) ||
+		!reflect.DeepEqual(newNode.Status.Addresses, oldNode.Status.Addresses) {
+		e.enqueueSelectingNodePools(ctx, newNode, q)
+	}
+
 	// check the NodePoolLabel of node
 	if len(oldNp) == 0 && len(newNp) == 0 {
 		return
@@ -84,10 +95,12 @@ func (e *EnqueueNodePoolForNode) Update(ctx context.Context, evt event.UpdateEve
 		addNodePoolToWorkQueue(newNp, q)
 		return
 	} else if oldNp != newNp {
-		klog.Warningf("It is not allowed to change the NodePoolLabel of node, but pool of node(%s) is changed from %s to %s", newNode.Name, oldNp, newNp)
-		// emit a warning event
-		e.Recorder.Event(newNode.DeepCopy(), corev1.EventTypeWarning, apps.NodePoolChangedEvent,
-			fmt.Sprintf("It is not allowed to change the NodePoolLabel of node, but nodepool of node(%s) is changed from %s to %s", newNode.Name, oldNp, newNp))
+		// node is moved by the nodeSelector of nodepool, both nodepools should be reconciled
+		klog.V(4).Info(Format("node(%s) is moved from pool(%s) to pool(%s)", newNode.Name, oldNp, newNp))
+		addNodePoolToWorkQueue(oldNp, q)
+		if len(newNp) != 0 {
+			addNodePoolToWorkQueue(newNp, q)
+		}
 		return
 	}
 
@@ -137,6 +150,168 @@ func (e *EnqueueNodePoolForNode) Generic(ctx context.Context, evt event.GenericE
 	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
 }
 
+// enqueueSelectingNodePools enqueues the nodepools whose nodeSelector matches the node.
+func (e *EnqueueNodePoolForNode) enqueueSelectingNodePools(ctx context.Context, node *corev1.Node,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if e.Reader == nil {
+		return
+	}
+
+	var poolList appsv1beta2.NodePoolList
+	if err := e.Reader.List(ctx, &poolList); err != nil {
+		klog.V(4).Info(Format("could not list nodepools for node(%s), %v", node.Name, err))
+		return
+	}
+	for _, np := range nodepoolutil.MatchingNodePools(node, poolList.Items) {
+		addNodePoolToWorkQueue(np, q)
+	}
+}
+
+// EnqueueNodePoolHierarchy enqueues the parent of NodePool for aggregating the status of descendants,
+// and enqueues the children of NodePool when ancestors of them may be changed.
+type EnqueueNodePoolHierarchy struct {
+	Reader client.Reader
+}
+
+// Create implements EventHandler
+func (e *EnqueueNodePoolHierarchy) Create(ctx context.Context, evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	np, ok := evt.Object.(*appsv1beta2.NodePool)
+	if !ok {
+		return
+	}
+	if len(np.Spec.Parent) != 0 {
+		addNodePoolToWorkQueue(np.Spec.Parent, q)
+	}
+}
+
+// Update implements EventHandler
+func (e *EnqueueNodePoolHierarchy) Update(ctx context.Context, evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	newNp, ok := evt.ObjectNew.(*appsv1beta2.NodePool)
+	if !ok {
+		return
+	}
+	oldNp, ok := evt.ObjectOld.(*appsv1beta2.NodePool)
+	if !ok {
+		return
+	}
+
+	if len(newNp.Spec.Parent) != 0 {
+		addNodePoolToWorkQueue(newNp.Spec.Parent, q)
+	}
+	if len(oldNp.Spec.Parent) != 0 && oldNp.Spec.Parent != newNp.Spec.Parent {
+		addNodePoolToWorkQueue(oldNp.Spec.Parent, q)
+	}
+
+	// ancestors of children are changed along with the nodepool
+	if oldNp.Spec.Parent != newNp.Spec.Parent || !reflect.DeepEqual(oldNp.Labels, newNp.Labels) {
+		e.enqueueChildren(ctx, newNp.Name, q)
+	}
+}
+
+// Delete implements EventHandler
+func (e *EnqueueNodePoolHierarchy) Delete(ctx context.Context, evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	np, ok := evt.Object.(*appsv1beta2.NodePool)
+	if !ok {
+		return
+	}
+	if len(np.Spec.Parent) != 0 {
+		addNodePoolToWorkQueue(np.Spec.Parent, q)
+	}
+	e.enqueueChildren(ctx, np.Name, q)
+}
+
+// Generic implements EventHandler
+func (e *EnqueueNodePoolHierarchy) Generic(ctx context.Context, evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (e *EnqueueNodePoolHierarchy) enqueueChildren(ctx context.Context, name string,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	var poolList appsv1beta2.NodePoolList
+	if err := e.Reader.List(ctx, &poolList); err != nil {
+		klog.V(4).Info(Format("could not list nodepools for children of pool(%s), %v", name, err))
+		return
+	}
+	for _, child := range nodepoolutil.ChildrenOf(name, poolList.Items) {
+		addNodePoolToWorkQueue(child, q)
+	}
+}
+
+// EnqueueNodePoolForPod enqueues the NodePool of node on which the pod is running,
+// so the resource requests of pods in the NodePool can be aggregated.
+type EnqueueNodePoolForPod struct {
+	Reader client.Reader
+}
+
+// Create implements EventHandler
+func (e *EnqueueNodePoolForPod) Create(ctx context.Context, evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	pod, ok := evt.Object.(*corev1.Pod)
+	if !ok {
+		klog.Error(Format("could not assert runtime Object to v1.Pod"))
+		return
+	}
+	e.enqueueNodePoolForNode(ctx, pod.Spec.NodeName, q)
+}
+
+// Update implements EventHandler
+func (e *EnqueueNodePoolForPod) Update(ctx context.Context, evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	newPod, ok := evt.ObjectNew.(*corev1.Pod)
+	if !ok {
+		klog.Error(Format("could not assert runtime Object(%s) to v1.Pod", evt.ObjectNew.GetName()))
+		return
+	}
+	oldPod, ok := evt.ObjectOld.(*corev1.Pod)
+	if !ok {
+		klog.Error(Format("could not assert runtime Object(%s) to v1.Pod", evt.ObjectOld.GetName()))
+		return
+	}
+
+	// only scheduling and termination of pods change the resource requests in nodepool
+	if oldPod.Spec.NodeName != newPod.Spec.NodeName {
+		e.enqueueNodePoolForNode(ctx, oldPod.Spec.NodeName, q)
+		e.enqueueNodePoolForNode(ctx, newPod.Spec.NodeName, q)
+	} else if nodepoolutil.IsPodTerminated(oldPod) != nodepoolutil.IsPodTerminated(newPod) {
+		e.enqueueNodePoolForNode(ctx, newPod.Spec.NodeName, q)
+	}
+}
+
+// Delete implements EventHandler
+func (e *EnqueueNodePoolForPod) Delete(ctx context.Context, evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	pod, ok := evt.Object.(*corev1.Pod)
+	if !ok {
+		klog.Error(Format("could not assert runtime Object to v1.Pod"))
+		return
+	}
+	e.enqueueNodePoolForNode(ctx, pod.Spec.NodeName, q)
+}
+
+// Generic implements EventHandler
+func (e *EnqueueNodePoolForPod) Generic(ctx context.Context, evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (e *EnqueueNodePoolForPod) enqueueNodePoolForNode(ctx context.Context, nodeName string,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if len(nodeName) == 0 {
+		return
+	}
+
+	var node corev1.Node
+	if err := e.Reader.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+		klog.V(4).Info(Format("could not get node(%s) of pod, %v", nodeName, err))
+		return
+	}
+	if np := node.Labels[projectinfo.GetNodePoolLabel()]; len(np) != 0 {
+		addNodePoolToWorkQueue(np, q)
+	}
+}
+
 // addNodePoolToWorkQueue adds the nodepool the reconciler's workqueue
 func addNodePoolToWorkQueue(npName string,
 	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
@@ -130,7 +130,7 @@ func TestUpdate(t *testing.T) {
 					},
 				},
 			},
-			wantedNum: 0,
+			wantedNum: 2,
 		},
 		"pool of node is not changed": {
 			event: event.UpdateEvent{
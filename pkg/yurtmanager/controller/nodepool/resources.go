@@ -0,0 +1,122 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	nodepoolutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/nodepool"
+)
+
+// summarizeResources aggregates the allocatable resources of nodes and the requests of non-terminated pods
+// running on them, the requests are also aggregated by namespaces for PoolResourceQuota.
+func (r *ReconcileNodePool) summarizeResources(ctx context.Context, nodes []corev1.Node) (*appsv1beta2.NodePoolResourceSummary, map[string]corev1.ResourceList, error) {
+	allocatable := corev1.ResourceList{}
+	requested := corev1.ResourceList{}
+	usedByNamespace := make(map[string]corev1.ResourceList)
+	for i := range nodes {
+		nodepoolutil.AddResources(allocatable, nodepoolutil.NodeAllocatable(&nodes[i]))
+
+		var podList corev1.PodList
+		if err := r.List(ctx, &podList, &client.ListOptions{
+			FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": nodes[i].Name}),
+		}); err != nil {
+			return nil, nil, err
+		}
+		for j := range podList.Items {
+			pod := &podList.Items[j]
+			if nodepoolutil.IsPodTerminated(pod) {
+				continue
+			}
+			requests := nodepoolutil.PodRequests(pod)
+			nodepoolutil.AddResources(requested, requests)
+			if _, ok := usedByNamespace[pod.Namespace]; !ok {
+				usedByNamespace[pod.Namespace] = corev1.ResourceList{}
+			}
+			nodepoolutil.AddResources(usedByNamespace[pod.Namespace], requests)
+		}
+	}
+
+	if len(allocatable) == 0 && len(requested) == 0 {
+		return nil, usedByNamespace, nil
+	}
+	summary := &appsv1beta2.NodePoolResourceSummary{}
+	if len(allocatable) != 0 {
+		summary.Allocatable = allocatable
+	}
+	if len(requested) != 0 {
+		summary.Requested = requested
+	}
+	return summary, usedByNamespace, nil
+}
+
+// conciliateResourceSummary updates the resource summary of NodePool, it returns true if the summary is changed.
+func conciliateResourceSummary(summary *appsv1beta2.NodePoolResourceSummary, nodePool *appsv1beta2.NodePool) bool {
+	if apiequality.Semantic.DeepEqual(summary, nodePool.Status.ResourceSummary) {
+		return false
+	}
+	nodePool.Status.ResourceSummary = summary
+	return true
+}
+
+// updatePoolResourceQuotas updates the used resources of PoolResourceQuotas in the NodePool.
+func (r *ReconcileNodePool) updatePoolResourceQuotas(ctx context.Context, poolName string, usedByNamespace map[string]corev1.ResourceList) error {
+	var quotaList appsv1alpha1.PoolResourceQuotaList
+	if err := r.List(ctx, &quotaList); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	for i := range quotaList.Items {
+		quota := &quotaList.Items[i]
+		if quota.Spec.NodePool != poolName {
+			continue
+		}
+
+		// only the resources which are limited by quota are recorded
+		used := corev1.ResourceList{}
+		for name := range quota.Spec.Hard {
+			if q, ok := usedByNamespace[quota.Namespace][name]; ok {
+				used[name] = q.DeepCopy()
+			} else {
+				used[name] = resource.MustParse("0")
+			}
+		}
+		if apiequality.Semantic.DeepEqual(used, quota.Status.Used) {
+			continue
+		}
+
+		quota.Status.Used = used
+		if err := r.Status().Update(ctx, quota); err != nil {
+			klog.Error(Format("Update PoolResourceQuota %s/%s error %v", quota.Namespace, quota.Name, err))
+			return err
+		}
+	}
+	return nil
+}
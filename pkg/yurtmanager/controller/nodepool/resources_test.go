@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openyurtio/openyurt/pkg/apis"
+	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+)
+
+func podIndexer(rawObj client.Object) []string {
+	pod, ok := rawObj.(*corev1.Pod)
+	if !ok || len(pod.Spec.NodeName) == 0 {
+		return []string{}
+	}
+	return []string{pod.Spec.NodeName}
+}
+
+func newResourcePod(name, namespace, nodeName, cpu, memory string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestReconcileResourceSummary(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	apis.AddToScheme(scheme)
+
+	pool := &appsv1beta2.NodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "hangzhou"},
+		Spec:       appsv1beta2.NodePoolSpec{Type: appsv1beta2.Edge},
+	}
+	quota := &appsv1alpha1.PoolResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "tenant1"},
+		Spec: appsv1alpha1.PoolResourceQuotaSpec{
+			NodePool: "hangzhou",
+			Hard: corev1.ResourceList{
+				corev1.ResourceCPU:  resource.MustParse("4"),
+				corev1.ResourcePods: resource.MustParse("10"),
+			},
+		},
+	}
+	objs := []client.Object{
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{projectinfo.GetNodePoolLabel(): "hangzhou"}},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			}},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{projectinfo.GetNodePoolLabel(): "hangzhou"}},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			}},
+		},
+		newResourcePod("pod1", "tenant1", "node1", "1", "1Gi", corev1.PodRunning),
+		newResourcePod("pod2", "tenant1", "node2", "500m", "512Mi", corev1.PodRunning),
+		newResourcePod("pod3", "tenant2", "node2", "1", "1Gi", corev1.PodPending),
+		// terminated pod is not accounted
+		newResourcePod("pod4", "tenant1", "node1", "2", "2Gi", corev1.PodSucceeded),
+		// pod on node out of the pool is not accounted
+		newResourcePod("pod5", "tenant1", "node3", "2", "2Gi", corev1.PodRunning),
+	}
+
+	c := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pool, quota).
+		WithStatusSubresource(pool, quota).
+		WithObjects(objs...).
+		WithIndex(&corev1.Pod{}, "spec.nodeName", podIndexer).
+		Build()
+	r := &ReconcileNodePool{Client: c}
+	ctx := context.TODO()
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "hangzhou"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	var currentPool appsv1beta2.NodePool
+	if err := c.Get(ctx, types.NamespacedName{Name: "hangzhou"}, &currentPool); err != nil {
+		t.Fatalf("could not get pool, %v", err)
+	}
+	wantedSummary := &appsv1beta2.NodePoolResourceSummary{
+		Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("6"),
+			corev1.ResourceMemory: resource.MustParse("12Gi"),
+			corev1.ResourcePods:   resource.MustParse("220"),
+		},
+		Requested: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2500m"),
+			corev1.ResourceMemory: resource.MustParse("2560Mi"),
+			corev1.ResourcePods:   resource.MustParse("3"),
+		},
+	}
+	if !apiequality.Semantic.DeepEqual(wantedSummary, currentPool.Status.ResourceSummary) {
+		t.Errorf("expected resource summary %v, got %v", wantedSummary, currentPool.Status.ResourceSummary)
+	}
+
+	var currentQuota appsv1alpha1.PoolResourceQuota
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "tenant1", Name: "quota"}, &currentQuota); err != nil {
+		t.Fatalf("could not get quota, %v", err)
+	}
+	wantedUsed := corev1.ResourceList{
+		corev1.ResourceCPU:  resource.MustParse("1500m"),
+		corev1.ResourcePods: resource.MustParse("2"),
+	}
+	if !apiequality.Semantic.DeepEqual(wantedUsed, currentQuota.Status.Used) {
+		t.Errorf("expected quota used %v, got %v", wantedUsed, currentQuota.Status.Used)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"embed"
 	"encoding/json"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
@@ -94,6 +95,8 @@ type PlatformAdminControllerConfiguration struct {
 	SecurityConfigMaps             map[string][]corev1.ConfigMap
 	NoSectyConfigMaps              map[string][]corev1.ConfigMap
 	ConcurrentPlatformAdminWorkers int32
+	// UpgradeStepTimeout is how long a step of the version upgrade may take to become ready before rolling back
+	UpgradeStepTimeout time.Duration
 }
 
 func NewPlatformAdminControllerConfiguration() *PlatformAdminControllerConfiguration {
@@ -107,6 +110,7 @@ func NewPlatformAdminControllerConfiguration() *PlatformAdminControllerConfigura
 			SecurityConfigMaps:             make(map[string][]corev1.ConfigMap),
 			NoSectyConfigMaps:              make(map[string][]corev1.ConfigMap),
 			ConcurrentPlatformAdminWorkers: 3,
+			UpgradeStepTimeout:             10 * time.Minute,
 		}
 	)
 
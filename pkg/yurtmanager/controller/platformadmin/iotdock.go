@@ -49,20 +49,41 @@ func newYurtIoTDockComponent(platformAdmin *iotv1beta1.PlatformAdmin, platformAd
 		return nil, err
 	}
 
+	args := []string{
+		"--health-probe-bind-address=:8081",
+		"--metrics-bind-address=127.0.0.1:8080",
+		"--leader-elect=false",
+		fmt.Sprintf("--namespace=%s", ns),
+		fmt.Sprintf("--version=%s", platformAdmin.Spec.Version),
+		fmt.Sprintf("--readings-bind-address=:%d", utils.IotDockReadingsPort),
+	}
+	// The platform flag is only passed to the platforms other than edgex,
+	// so that the yurt-iot-dock images without the flag still work with edgex
+	if !isEdgeXPlatform(platformAdmin) {
+		args = append(args, fmt.Sprintf("--platform=%s", platformAdmin.Spec.Platform))
+	}
+	if isMQTTPlatform(platformAdmin) {
+		args = append(args, fmt.Sprintf("--mqtt-broker-address=%s", mqttBrokerAddress()))
+	}
+
+	labels := map[string]string{
+		"app":           utils.IotDockName,
+		"control-plane": utils.IotDockControlPlane,
+	}
+	readingsPort := corev1.ContainerPort{
+		Name:          "https-readings",
+		ContainerPort: utils.IotDockReadingsPort,
+		Protocol:      corev1.ProtocolTCP,
+	}
+
 	yurtIotDockComponent.Name = utils.IotDockName
 	yurtIotDockComponent.Deployment = &appsv1.DeploymentSpec{
 		Selector: &metav1.LabelSelector{
-			MatchLabels: map[string]string{
-				"app":           utils.IotDockName,
-				"control-plane": utils.IotDockControlPlane,
-			},
+			MatchLabels: labels,
 		},
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
-				Labels: map[string]string{
-					"app":           utils.IotDockName,
-					"control-plane": utils.IotDockControlPlane,
-				},
+				Labels:    labels,
 				Namespace: ns,
 			},
 			Spec: corev1.PodSpec{
@@ -71,13 +92,8 @@ func newYurtIoTDockComponent(platformAdmin *iotv1beta1.PlatformAdmin, platformAd
 						Name:            utils.IotDockName,
 						Image:           fmt.Sprintf("%s:%s", utils.IotDockImage, ver),
 						ImagePullPolicy: corev1.PullAlways,
-						Args: []string{
-							"--health-probe-bind-address=:8081",
-							"--metrics-bind-address=127.0.0.1:8080",
-							"--leader-elect=false",
-							fmt.Sprintf("--namespace=%s", ns),
-							fmt.Sprintf("--version=%s", platformAdmin.Spec.Version),
-						},
+						Args:            args,
+						Ports:           []corev1.ContainerPort{readingsPort},
 						LivenessProbe: &corev1.Probe{
 							InitialDelaySeconds: 15,
 							PeriodSeconds:       20,
@@ -120,8 +136,18 @@ func newYurtIoTDockComponent(platformAdmin *iotv1beta1.PlatformAdmin, platformAd
 			},
 		},
 	}
-	// YurtIoTDock doesn't need a service yet
-	yurtIotDockComponent.Service = nil
+	// The device readings API is exposed to the apps in the nodepool by the service
+	yurtIotDockComponent.Service = &corev1.ServiceSpec{
+		Ports: []corev1.ServicePort{
+			{
+				Name:       readingsPort.Name,
+				Protocol:   corev1.ProtocolTCP,
+				Port:       443,
+				TargetPort: intstr.FromString(readingsPort.Name),
+			},
+		},
+		Selector: labels,
+	}
 
 	return &yurtIotDockComponent, nil
 }
@@ -0,0 +1,122 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platformadmin
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	iotv1beta1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1beta1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/platformadmin/config"
+	utils "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/platformadmin/utils"
+)
+
+// mosquittoConfig listens on all interfaces without authentication, and persists the retained messages
+// which make up the device registry, so that they survive the restart of broker
+var mosquittoConfig = fmt.Sprintf(`listener %d
+allow_anonymous true
+persistence true
+persistence_location /mosquitto/data/
+`, utils.MQTTBrokerPort)
+
+// isMQTTPlatform checks whether the platformAdmin deploys the mqtt device registry
+func isMQTTPlatform(platformAdmin *iotv1beta1.PlatformAdmin) bool {
+	return platformAdmin.Spec.Platform == iotv1beta1.PlatformAdminPlatformMQTT
+}
+
+// mqttBrokerAddress returns the address of the broker service in the namespace of platformAdmin
+func mqttBrokerAddress() string {
+	return fmt.Sprintf("%s:%d", utils.MQTTBrokerName, utils.MQTTBrokerPort)
+}
+
+// newMQTTBrokerComponent initialize the configuration of the mosquitto broker which hosts the mqtt device registry
+func newMQTTBrokerComponent(version string) *config.Component {
+	labels := map[string]string{"app": utils.MQTTBrokerName}
+	port := corev1.ContainerPort{
+		Name:          fmt.Sprintf("tcp-%d", utils.MQTTBrokerPort),
+		ContainerPort: utils.MQTTBrokerPort,
+		Protocol:      corev1.ProtocolTCP,
+	}
+
+	return &config.Component{
+		Name: utils.MQTTBrokerName,
+		Service: &corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       port.Name,
+					Protocol:   corev1.ProtocolTCP,
+					Port:       utils.MQTTBrokerPort,
+					TargetPort: intstr.FromInt(utils.MQTTBrokerPort),
+				},
+			},
+			Selector: labels,
+		},
+		Deployment: &appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			// The persisted registry can not be shared by two brokers
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name:         "data",
+							VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            utils.MQTTBrokerName,
+							Image:           fmt.Sprintf("%s:%s", utils.MQTTBrokerImage, version),
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Command: []string{
+								"sh", "-c",
+								fmt.Sprintf("printf '%s' > /tmp/mosquitto.conf && exec mosquitto -c /tmp/mosquitto.conf", mosquittoConfig),
+							},
+							Ports: []corev1.ContainerPort{port},
+							ReadinessProbe: &corev1.Probe{
+								InitialDelaySeconds: 5,
+								PeriodSeconds:       10,
+								ProbeHandler: corev1.ProbeHandler{
+									TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(utils.MQTTBrokerPort)},
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "data", MountPath: "/mosquitto/data"},
+							},
+							SecurityContext: &corev1.SecurityContext{
+								AllowPrivilegeEscalation: ptr.To(false),
+							},
+						},
+					},
+					Hostname: utils.MQTTBrokerName,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platformadmin
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	iotv1beta1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1beta1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/platformadmin/config"
+)
+
+// renderComponents returns the components to deploy, the components defined in spec replace the ones in the framework
+// and the overrides in spec are applied on top of them. The framework itself is left untouched,
+// so removing an override from spec restores the component.
+func renderComponents(platformAdmin *iotv1beta1.PlatformAdmin, platformAdminFramework *PlatformAdminFramework) []*config.Component {
+	specComponents := make(map[string]iotv1beta1.Component, len(platformAdmin.Spec.Components))
+	for _, component := range platformAdmin.Spec.Components {
+		specComponents[component.Name] = component
+	}
+
+	components := make([]*config.Component, 0, len(platformAdminFramework.Components))
+	frameworkComponentSet := sets.New[string]()
+	for _, component := range platformAdminFramework.Components {
+		frameworkComponentSet.Insert(component.Name)
+		if sc, ok := specComponents[component.Name]; ok {
+			component = customizeComponent(component, &sc)
+		}
+		components = append(components, component)
+	}
+
+	// The components that are only defined in spec are not kept in the framework
+	for _, sc := range platformAdmin.Spec.Components {
+		if sc.Definition == nil || frameworkComponentSet.Has(sc.Name) {
+			continue
+		}
+		components = append(components, customizeComponent(nil, &sc))
+	}
+	return components
+}
+
+// customizeComponent applies the definition and the override of the component in spec
+func customizeComponent(component *config.Component, specComponent *iotv1beta1.Component) *config.Component {
+	if specComponent.Definition != nil {
+		component = &config.Component{
+			Name:       specComponent.Name,
+			Service:    specComponent.Definition.Service.DeepCopy(),
+			Deployment: specComponent.Definition.Deployment.DeepCopy(),
+		}
+	}
+	if specComponent.Override == nil || component.Deployment == nil {
+		return component
+	}
+
+	override := specComponent.Override
+	customized := &config.Component{
+		Name:       component.Name,
+		Service:    component.Service,
+		Deployment: component.Deployment.DeepCopy(),
+	}
+	podSpec := &customized.Deployment.Template.Spec
+
+	if len(podSpec.Containers) != 0 {
+		container := &podSpec.Containers[0]
+		for i := range podSpec.Containers {
+			if podSpec.Containers[i].Name == component.Name {
+				container = &podSpec.Containers[i]
+				break
+			}
+		}
+
+		if override.Image != "" {
+			container.Image = override.Image
+		}
+		container.Env = mergeEnv(container.Env, override.Env)
+		if override.Resources != nil {
+			container.Resources = *override.Resources.DeepCopy()
+		}
+	}
+
+	if len(override.NodeSelector) != 0 && podSpec.NodeSelector == nil {
+		podSpec.NodeSelector = make(map[string]string, len(override.NodeSelector))
+	}
+	for k, v := range override.NodeSelector {
+		podSpec.NodeSelector[k] = v
+	}
+	if override.Affinity != nil {
+		podSpec.Affinity = override.Affinity.DeepCopy()
+	}
+	for _, toleration := range override.Tolerations {
+		podSpec.Tolerations = append(podSpec.Tolerations, *toleration.DeepCopy())
+	}
+	return customized
+}
+
+// mergeEnv replaces the env vars with the same name and appends the others
+func mergeEnv(env, overrides []corev1.EnvVar) []corev1.EnvVar {
+	for _, override := range overrides {
+		replaced := false
+		for i := range env {
+			if env[i].Name == override.Name {
+				env[i] = *override.DeepCopy()
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			env = append(env, *override.DeepCopy())
+		}
+	}
+	return env
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platformadmin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	iotv1beta1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1beta1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/platformadmin/config"
+)
+
+func newTestComponent(name string) *config.Component {
+	return &config.Component{
+		Name: name,
+		Deployment: &appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "sidecar", Image: "sidecar:v1"},
+						{
+							Name:  name,
+							Image: name + ":v1",
+							Env:   []corev1.EnvVar{{Name: "LOGLEVEL", Value: "INFO"}, {Name: "PORT", Value: "59880"}},
+						},
+					},
+					NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+				},
+			},
+		},
+	}
+}
+
+func TestRenderComponents(t *testing.T) {
+	framework := &PlatformAdminFramework{
+		Components: []*config.Component{newTestComponent("edgex-core-data"), newTestComponent("edgex-ui-go")},
+	}
+	platformAdmin := &iotv1beta1.PlatformAdmin{
+		Spec: iotv1beta1.PlatformAdminSpec{
+			Components: []iotv1beta1.Component{
+				{
+					Name: "edgex-core-data",
+					Override: &iotv1beta1.ComponentOverride{
+						Image: "edgex-core-data:v2",
+						Env:   []corev1.EnvVar{{Name: "LOGLEVEL", Value: "DEBUG"}, {Name: "TRACE", Value: "true"}},
+						Resources: &corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+						},
+						NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"},
+						Tolerations:  []corev1.Toleration{{Key: "edge", Operator: corev1.TolerationOpExists}},
+					},
+				},
+				{
+					Name: "edgex-device-modbus",
+					Definition: &iotv1beta1.ComponentDefinition{
+						Deployment: *newTestComponent("edgex-device-modbus").Deployment,
+					},
+					Override: &iotv1beta1.ComponentOverride{Image: "edgex-device-modbus:v2"},
+				},
+			},
+		},
+	}
+
+	components := renderComponents(platformAdmin, framework)
+	assert.Len(t, components, 3)
+
+	coreData := components[0].Deployment.Template.Spec
+	assert.Equal(t, "sidecar:v1", coreData.Containers[0].Image)
+	assert.Equal(t, "edgex-core-data:v2", coreData.Containers[1].Image)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "LOGLEVEL", Value: "DEBUG"},
+		{Name: "PORT", Value: "59880"},
+		{Name: "TRACE", Value: "true"},
+	}, coreData.Containers[1].Env)
+	assert.Equal(t, "256Mi", coreData.Containers[1].Resources.Limits.Memory().String())
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux", "kubernetes.io/arch": "arm64"}, coreData.NodeSelector)
+	assert.Len(t, coreData.Tolerations, 1)
+
+	// the framework is left untouched
+	assert.Equal(t, "edgex-core-data:v1", framework.Components[0].Deployment.Template.Spec.Containers[1].Image)
+	assert.Equal(t, "INFO", framework.Components[0].Deployment.Template.Spec.Containers[1].Env[0].Value)
+	assert.Same(t, framework.Components[1], components[1])
+
+	assert.Equal(t, "edgex-device-modbus", components[2].Name)
+	assert.Equal(t, "edgex-device-modbus:v2", components[2].Deployment.Template.Spec.Containers[1].Image)
+}
@@ -249,7 +249,7 @@ func (r *ReconcilePlatformAdmin) reconcileDelete(ctx context.Context, platformAd
 	if err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "unexpected error while synchronizing customize framework for %s", platformAdmin.Namespace+"/"+platformAdmin.Name)
 	}
-	desiredComponents := platformAdminFramework.Components
+	desiredComponents := renderComponents(platformAdmin, platformAdminFramework)
 
 	for _, dc := range desiredComponents {
 		if err := r.Get(
@@ -316,6 +316,14 @@ func (r *ReconcilePlatformAdmin) reconcileNormal(ctx context.Context, platformAd
 		return reconcile.Result{}, errors.Wrapf(err, "unexpected error while synchronizing customize framework for %s", platformAdmin.Namespace+"/"+platformAdmin.Name)
 	}
 
+	// Decide which version the framework is rendered with, a version change is rolled out step by step
+	klog.V(4).Info(Format("ReconcileUpgrade PlatformAdmin %s/%s", platformAdmin.Namespace, platformAdmin.Name))
+	upgradeChanged, err := r.reconcileUpgrade(ctx, platformAdmin, platformAdminStatus, platformAdminFramework)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err,
+			"unexpected error while reconciling upgrade for %s", platformAdmin.Namespace+"/"+platformAdmin.Name)
+	}
+
 	// Reconcile configmap of edgex confiruation
 	klog.V(4).Info(Format("ReconcileConfigmap PlatformAdmin %s/%s", platformAdmin.Namespace, platformAdmin.Name))
 	if ok, err := r.reconcileConfigmap(ctx, platformAdmin, platformAdminStatus, platformAdminFramework); !ok {
@@ -348,6 +356,21 @@ func (r *ReconcilePlatformAdmin) reconcileNormal(ctx context.Context, platformAd
 		return reconcile.Result{}, err
 	}
 
+	// The yurtappsets just patched by the upgrade have not reported the status of the new template yet
+	if upgradeChanged {
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	// Move the upgrade to the next step once the components of the current step are ready
+	requeue, err := r.progressUpgrade(ctx, platformAdmin, platformAdminStatus, platformAdminFramework)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err,
+			"unexpected error while progressing upgrade for %s", platformAdmin.Namespace+"/"+platformAdmin.Name)
+	}
+	if requeue {
+		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
@@ -393,12 +416,11 @@ func (r *ReconcilePlatformAdmin) reconcileComponent(ctx context.Context, platfor
 	// Users can configure components in the framework,
 	// or they can choose to configure optional components directly in spec,
 	// which combines the two approaches and tells the controller if the framework needs to be updated.
-	needWriteFramework := r.calculateDesiredComponents(platformAdmin, platformAdminFramework)
-
-	defer func() {
-		platformAdminStatus.ReadyComponentNum = readyComponent
-		platformAdminStatus.UnreadyComponentNum = int32(len(platformAdminFramework.Components)) - readyComponent
-	}()
+	// During an upgrade the components are rendered step by step, so they are not recalculated here.
+	needWriteFramework := false
+	if !isUpgradeInProgress(platformAdminStatus.Upgrade) {
+		needWriteFramework = r.calculateDesiredComponents(platformAdminWithVersion(platformAdmin, platformAdminStatus.CurrentVersion), platformAdminFramework)
+	}
 
 	// The component in spec that does not exist in the framework, so the framework needs to be updated.
 	if needWriteFramework {
@@ -407,8 +429,16 @@ func (r *ReconcilePlatformAdmin) reconcileComponent(ctx context.Context, platfor
 		}
 	}
 
+	// The definitions and overrides in spec are applied on top of the framework
+	desiredComponents := renderComponents(platformAdmin, platformAdminFramework)
+
+	defer func() {
+		platformAdminStatus.ReadyComponentNum = readyComponent
+		platformAdminStatus.UnreadyComponentNum = int32(len(desiredComponents)) - readyComponent
+	}()
+
 	// Update the yurtappsets based on the desired components
-	for _, desiredComponent := range platformAdminFramework.Components {
+	for _, desiredComponent := range desiredComponents {
 		readyService := false
 		readyDeployment := false
 		needServices[desiredComponent.Name] = struct{}{}
@@ -507,7 +537,7 @@ func (r *ReconcilePlatformAdmin) reconcileComponent(ctx context.Context, platfor
 		}
 	}
 
-	return readyComponent == int32(len(platformAdminFramework.Components)), nil
+	return readyComponent == int32(len(desiredComponents)), nil
 }
 
 func (r *ReconcilePlatformAdmin) handleService(ctx context.Context, platformAdmin *iotv1beta1.PlatformAdmin, component *config.Component) (*corev1.Service, error) {
@@ -733,13 +763,15 @@ func (r *ReconcilePlatformAdmin) initFramework(ctx context.Context, platformAdmi
 
 	// Use standard configurations to build the framework
 	platformAdminFramework.security = platformAdmin.Spec.Security
-	if platformAdminFramework.security {
-		platformAdminFramework.ConfigMaps = r.Configuration.SecurityConfigMaps[platformAdmin.Spec.Version]
-		r.calculateDesiredComponents(platformAdmin, platformAdminFramework)
-	} else {
-		platformAdminFramework.ConfigMaps = r.Configuration.NoSectyConfigMaps[platformAdmin.Spec.Version]
-		r.calculateDesiredComponents(platformAdmin, platformAdminFramework)
+	// The standard configmaps are provided for edgex only
+	if isEdgeXPlatform(platformAdmin) {
+		if platformAdminFramework.security {
+			platformAdminFramework.ConfigMaps = r.Configuration.SecurityConfigMaps[platformAdmin.Spec.Version]
+		} else {
+			platformAdminFramework.ConfigMaps = r.Configuration.NoSectyConfigMaps[platformAdmin.Spec.Version]
+		}
 	}
+	r.calculateDesiredComponents(platformAdmin, platformAdminFramework)
 
 	// For better serialization, the serialization method of the Kubernetes runtime library is used
 	data, err := runtime.Encode(r.yamlSerializer, platformAdminFramework)
@@ -778,9 +810,20 @@ func (r *ReconcilePlatformAdmin) calculateDesiredComponents(platformAdmin *iotv1
 	needWriteFramework := false
 	desiredComponents := []*config.Component{}
 
-	// Find all the required components from spec and manifest
-	requiredComponentSet := config.ExtractRequiredComponentsName(&r.Configuration.Manifest, platformAdmin.Spec.Version)
+	// Find all the required components from spec and manifest,
+	// the manifest only describes the components of edgex, the mqtt platform requires its broker only
+	requiredComponentSet := sets.New[string]()
+	switch {
+	case isEdgeXPlatform(platformAdmin):
+		requiredComponentSet = config.ExtractRequiredComponentsName(&r.Configuration.Manifest, platformAdmin.Spec.Version)
+	case isMQTTPlatform(platformAdmin):
+		requiredComponentSet.Insert(util.MQTTBrokerName)
+	}
 	for _, component := range platformAdmin.Spec.Components {
+		// The components defined in spec are rendered from spec directly
+		if component.Definition != nil {
+			continue
+		}
 		requiredComponentSet.Insert(component.Name)
 	}
 
@@ -806,17 +849,9 @@ func (r *ReconcilePlatformAdmin) calculateDesiredComponents(platformAdmin *iotv1
 
 	// If a component needs to be added,
 	// check whether the corresponding template exists in the standard configuration library
-	if platformAdmin.Spec.Security {
-		for _, component := range r.Configuration.SecurityComponents[platformAdmin.Spec.Version] {
-			if addedComponentSet.Has(component.Name) {
-				desiredComponents = append(desiredComponents, component)
-			}
-		}
-	} else {
-		for _, component := range r.Configuration.NoSectyComponents[platformAdmin.Spec.Version] {
-			if addedComponentSet.Has(component.Name) {
-				desiredComponents = append(desiredComponents, component)
-			}
+	for _, component := range r.componentTemplates(platformAdmin, platformAdmin.Spec.Version) {
+		if addedComponentSet.Has(component.Name) {
+			desiredComponents = append(desiredComponents, component)
 		}
 	}
 
@@ -834,3 +869,8 @@ func (r *ReconcilePlatformAdmin) calculateDesiredComponents(platformAdmin *iotv1
 
 	return needWriteFramework
 }
+
+// isEdgeXPlatform checks whether the platformAdmin deploys edgex, the empty platform is treated as edgex for compatibility
+func isEdgeXPlatform(platformAdmin *iotv1beta1.PlatformAdmin) bool {
+	return platformAdmin.Spec.Platform == "" || platformAdmin.Spec.Platform == iotv1beta1.PlatformAdminPlatformEdgeX
+}
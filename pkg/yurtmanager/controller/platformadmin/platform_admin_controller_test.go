@@ -139,6 +139,72 @@ func TestReconcilePlatformAdmin(t *testing.T) {
 			expectedSvcNum: 4,
 			expectedErr:    false,
 		},
+		{
+			name: "create PlatformAdmin with custom component",
+			request: reconcile.Request{
+				NamespacedName: client.ObjectKey{
+					Name:      "custom-platformadmin",
+					Namespace: "default",
+				},
+			},
+			platformAdmin: &iotv1beta1.PlatformAdmin{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "custom-platformadmin",
+					Namespace: "default",
+				},
+				Spec: iotv1beta1.PlatformAdminSpec{
+					Version:   "minnesota",
+					NodePools: []string{"pool1"},
+					Components: []iotv1beta1.Component{
+						{
+							Name: "edgex-device-modbus",
+							Definition: &iotv1beta1.ComponentDefinition{
+								Service: &corev1.ServiceSpec{
+									Ports: []corev1.ServicePort{{Name: "http", Port: 59901}},
+								},
+								Deployment: apps.DeploymentSpec{
+									Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "edgex-device-modbus"}},
+									Template: corev1.PodTemplateSpec{
+										ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "edgex-device-modbus"}},
+										Spec: corev1.PodSpec{
+											Containers: []corev1.Container{{Name: "edgex-device-modbus", Image: "openyurt/device-modbus:3.0.0"}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedYasNum: 6,
+			expectedSvcNum: 5,
+			expectedErr:    false,
+		},
+		{
+			name: "create PlatformAdmin with mqtt platform",
+			request: reconcile.Request{
+				NamespacedName: client.ObjectKey{
+					Name:      "mqtt-platformadmin",
+					Namespace: "default",
+				},
+			},
+			platformAdmin: &iotv1beta1.PlatformAdmin{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mqtt-platformadmin",
+					Namespace: "default",
+				},
+				Spec: iotv1beta1.PlatformAdminSpec{
+					Version:    "2.0",
+					Platform:   iotv1beta1.PlatformAdminPlatformMQTT,
+					NodePools:  []string{"pool1"},
+					Components: []iotv1beta1.Component{{Name: "yurt-iot-dock"}},
+				},
+			},
+			expectedYasNum: 2,
+			// the mqtt broker and the device readings API of yurt-iot-dock
+			expectedSvcNum: 2,
+			expectedErr:    false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -177,6 +243,21 @@ func TestReconcilePlatformAdmin(t *testing.T) {
 			if err := fakeClient.List(context.TODO(), svcList); err == nil {
 				assert.Len(t, svcList.Items, tt.expectedSvcNum)
 			}
+
+			// only the platforms other than edgex are passed to yurt-iot-dock
+			for _, yas := range yasList.Items {
+				for _, container := range yas.Spec.WorkloadTemplate.DeploymentTemplate.Spec.Template.Spec.Containers {
+					if container.Name != "yurt-iot-dock" {
+						continue
+					}
+					if tt.platformAdmin.Spec.Platform == iotv1beta1.PlatformAdminPlatformMQTT {
+						assert.Contains(t, container.Args, "--platform=mqtt")
+						assert.Contains(t, container.Args, "--mqtt-broker-address=mqtt-broker:1883")
+					} else {
+						assert.NotContains(t, container.Args, "--platform=mqtt")
+					}
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,575 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platformadmin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1beta1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta1"
+	iotv1beta1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1beta1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/platformadmin/config"
+	util "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/platformadmin/utils"
+)
+
+const (
+	// FrameworkBackupKey is the key in the framework configmap that keeps the framework running before the upgrade
+	FrameworkBackupKey = "previous-framework"
+
+	UpgradeStepCore          = "core"
+	UpgradeStepDeviceService = "device-service"
+	UpgradeStepIotDock       = "yurt-iot-dock"
+
+	UpgradeSucceededReason = "UpgradeSucceeded"
+)
+
+// upgradeSteps is the order in which the components are upgraded, the device services register to the core services,
+// so they are upgraded after the core services, and yurt-iot-dock which talks to both of them is upgraded at last
+var upgradeSteps = []string{UpgradeStepCore, UpgradeStepDeviceService, UpgradeStepIotDock}
+
+// componentUpgradeStep returns the upgrade step that the component belongs to
+func componentUpgradeStep(name string) string {
+	switch {
+	case name == util.IotDockName:
+		return UpgradeStepIotDock
+	case strings.HasPrefix(name, "edgex-device-"):
+		return UpgradeStepDeviceService
+	default:
+		return UpgradeStepCore
+	}
+}
+
+// isUpgradeInProgress checks whether the components are being upgraded or rolled back
+func isUpgradeInProgress(upgrade *iotv1beta1.PlatformAdminUpgrade) bool {
+	return upgrade != nil && (upgrade.Phase == iotv1beta1.PlatformAdminUpgrading || upgrade.Phase == iotv1beta1.PlatformAdminUpgradeRollingBack)
+}
+
+// isStepTimeout checks whether the current step or the rollback is not ready within the timeout
+func isStepTimeout(upgrade *iotv1beta1.PlatformAdminUpgrade, timeout time.Duration) bool {
+	return upgrade.StepStartTime != nil && time.Since(upgrade.StepStartTime.Time) > timeout
+}
+
+// platformAdminWithVersion returns a copy of the PlatformAdmin that renders the components with the given version
+func platformAdminWithVersion(platformAdmin *iotv1beta1.PlatformAdmin, version string) *iotv1beta1.PlatformAdmin {
+	pa := platformAdmin.DeepCopy()
+	pa.Spec.Version = version
+	return pa
+}
+
+// reconcileUpgrade decides which version the framework is rendered with before the configmaps and components are reconciled.
+// A change of the version in spec starts an upgrade, which replaces the components step by step and
+// rolls them back to the previous version if a step is not ready in time.
+// It returns true if the upgrade moved to a new phase whose components have not been rolled out yet.
+func (r *ReconcilePlatformAdmin) reconcileUpgrade(ctx context.Context, platformAdmin *iotv1beta1.PlatformAdmin, platformAdminStatus *iotv1beta1.PlatformAdminStatus, platformAdminFramework *PlatformAdminFramework) (bool, error) {
+	defer setUpgradeCondition(platformAdmin, platformAdminStatus)
+
+	// The components of a new PlatformAdmin are rendered with the version in spec directly
+	if platformAdminStatus.CurrentVersion == "" {
+		platformAdminStatus.CurrentVersion = platformAdmin.Spec.Version
+	}
+
+	upgrade := platformAdminStatus.Upgrade
+	switch {
+	case isUpgradeInProgress(upgrade):
+		if upgrade.Phase == iotv1beta1.PlatformAdminUpgradeRollingBack {
+			if !isStepTimeout(upgrade, r.Configuration.UpgradeStepTimeout) {
+				return false, nil
+			}
+			// The restored components that are ready are left to progressUpgrade to finish the rollback
+			if ready, err := r.isComponentsUpdated(ctx, platformAdmin, platformAdminFramework); err != nil || ready {
+				return false, err
+			}
+			return false, r.failRollback(ctx, platformAdmin, platformAdminStatus, upgrade)
+		}
+		if upgrade.ToVersion != platformAdmin.Spec.Version {
+			return true, r.rollbackUpgrade(ctx, platformAdmin, upgrade, platformAdminFramework,
+				fmt.Sprintf("the target version is changed to %s", platformAdmin.Spec.Version))
+		}
+		if isStepTimeout(upgrade, r.Configuration.UpgradeStepTimeout) {
+			return true, r.rollbackUpgrade(ctx, platformAdmin, upgrade, platformAdminFramework,
+				fmt.Sprintf("step %s is not ready within %s", upgrade.Steps[upgrade.CurrentStep], r.Configuration.UpgradeStepTimeout))
+		}
+		return r.renderUpgradeStep(ctx, platformAdmin, upgrade, platformAdminFramework)
+	case platformAdmin.Spec.Version == platformAdminStatus.CurrentVersion:
+		return false, nil
+	case upgrade != nil && upgrade.FromVersion == platformAdminStatus.CurrentVersion && upgrade.ToVersion == platformAdmin.Spec.Version:
+		// The upgrade to this version has failed, keep the components until the version in spec is changed
+		return false, nil
+	}
+
+	return r.startUpgrade(ctx, platformAdmin, platformAdminStatus, platformAdminFramework)
+}
+
+// progressUpgrade moves the upgrade forward once all the components of the current step are ready,
+// it returns true if the PlatformAdmin needs to be reconciled again for the next step.
+func (r *ReconcilePlatformAdmin) progressUpgrade(ctx context.Context, platformAdmin *iotv1beta1.PlatformAdmin, platformAdminStatus *iotv1beta1.PlatformAdminStatus, platformAdminFramework *PlatformAdminFramework) (bool, error) {
+	defer setUpgradeCondition(platformAdmin, platformAdminStatus)
+
+	upgrade := platformAdminStatus.Upgrade
+	if !isUpgradeInProgress(upgrade) {
+		return false, nil
+	}
+
+	// The ready workloads reported by a yurtappset that has not observed the new template are the old ones
+	if ready, err := r.isComponentsUpdated(ctx, platformAdmin, platformAdminFramework); err != nil || !ready {
+		return true, err
+	}
+
+	if upgrade.Phase == iotv1beta1.PlatformAdminUpgradeRollingBack {
+		upgrade.Phase = iotv1beta1.PlatformAdminUpgradeRolledBack
+		r.recorder.Eventf(platformAdmin, corev1.EventTypeWarning, iotv1beta1.UpgradeRolledBackReason,
+			"The components are rolled back to version %s: %s", upgrade.FromVersion, upgrade.Message)
+		finishUpgrade(platformAdminStatus, upgrade)
+		return false, r.removeFrameworkBackup(ctx, platformAdmin)
+	}
+
+	if int(upgrade.CurrentStep)+1 < len(upgrade.Steps) {
+		klog.V(4).Info(Format("Step %s of upgrading PlatformAdmin %s/%s to %s is ready",
+			upgrade.Steps[upgrade.CurrentStep], platformAdmin.Namespace, platformAdmin.Name, upgrade.ToVersion))
+		upgrade.CurrentStep++
+		upgrade.StepStartTime = ptrNow()
+		return r.renderUpgradeStep(ctx, platformAdmin, upgrade, platformAdminFramework)
+	}
+
+	upgrade.Phase = iotv1beta1.PlatformAdminUpgradeSucceeded
+	upgrade.Message = ""
+	platformAdminStatus.CurrentVersion = upgrade.ToVersion
+	r.recorder.Eventf(platformAdmin, corev1.EventTypeNormal, UpgradeSucceededReason,
+		"The components are upgraded from version %s to %s", upgrade.FromVersion, upgrade.ToVersion)
+	finishUpgrade(platformAdminStatus, upgrade)
+	return false, r.removeFrameworkBackup(ctx, platformAdmin)
+}
+
+// startUpgrade checks that the components can be upgraded to the version in spec and keeps the running framework
+// so that it can be restored, an upgrade that does not pass the pre-flight checks leaves the components untouched.
+func (r *ReconcilePlatformAdmin) startUpgrade(ctx context.Context, platformAdmin *iotv1beta1.PlatformAdmin, platformAdminStatus *iotv1beta1.PlatformAdminStatus, platformAdminFramework *PlatformAdminFramework) (bool, error) {
+	upgrade := &iotv1beta1.PlatformAdminUpgrade{
+		FromVersion: platformAdminStatus.CurrentVersion,
+		ToVersion:   platformAdmin.Spec.Version,
+		StartTime:   ptrNow(),
+	}
+
+	target := r.renderTargetFramework(platformAdmin, platformAdminFramework, upgrade.FromVersion, upgrade.ToVersion)
+	if err := r.preflightUpgrade(platformAdmin, target, upgrade.ToVersion); err != nil {
+		upgrade.Phase = iotv1beta1.PlatformAdminUpgradeFailed
+		upgrade.Message = err.Error()
+		r.recorder.Eventf(platformAdmin, corev1.EventTypeWarning, iotv1beta1.UpgradePreflightFailedReason,
+			"Could not upgrade the components from version %s to %s: %v", upgrade.FromVersion, upgrade.ToVersion, err)
+		finishUpgrade(platformAdminStatus, upgrade)
+		return false, nil
+	}
+
+	if err := r.backupFramework(ctx, platformAdmin, platformAdminFramework); err != nil {
+		return false, err
+	}
+
+	upgrade.Phase = iotv1beta1.PlatformAdminUpgrading
+	upgrade.StepStartTime = upgrade.StartTime
+	stepSet := sets.New[string]()
+	for _, components := range [][]*config.Component{platformAdminFramework.Components, target.Components} {
+		for _, component := range components {
+			stepSet.Insert(componentUpgradeStep(component.Name))
+		}
+	}
+	for _, step := range upgradeSteps {
+		if stepSet.Has(step) {
+			upgrade.Steps = append(upgrade.Steps, step)
+		}
+	}
+	platformAdminStatus.Upgrade = upgrade
+	r.recorder.Eventf(platformAdmin, corev1.EventTypeNormal, iotv1beta1.UpgradingReason,
+		"Upgrading the components from version %s to %s in steps %s", upgrade.FromVersion, upgrade.ToVersion, strings.Join(upgrade.Steps, ","))
+
+	return r.renderUpgradeStep(ctx, platformAdmin, upgrade, platformAdminFramework)
+}
+
+// rollbackUpgrade restores the framework that ran before the upgrade
+func (r *ReconcilePlatformAdmin) rollbackUpgrade(ctx context.Context, platformAdmin *iotv1beta1.PlatformAdmin, upgrade *iotv1beta1.PlatformAdminUpgrade, platformAdminFramework *PlatformAdminFramework, reason string) error {
+	previous, err := r.readFrameworkBackup(ctx, platformAdmin)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		// The backup is lost, so the standard configuration of the previous version is used instead
+		previous = r.renderFramework(platformAdminWithVersion(platformAdmin, upgrade.FromVersion), &PlatformAdminFramework{})
+	}
+
+	platformAdminFramework.Components = previous.Components
+	platformAdminFramework.ConfigMaps = previous.ConfigMaps
+	if err := r.writeFramework(ctx, platformAdmin, platformAdminFramework); err != nil {
+		return err
+	}
+
+	upgrade.Phase = iotv1beta1.PlatformAdminUpgradeRollingBack
+	upgrade.Message = reason
+	upgrade.StepStartTime = ptrNow()
+	r.recorder.Eventf(platformAdmin, corev1.EventTypeWarning, iotv1beta1.UpgradeRollingBackReason,
+		"Rolling back the components to version %s: %s", upgrade.FromVersion, reason)
+	return nil
+}
+
+// failRollback gives up the rollback whose components are not ready in time, the restored framework is kept
+// and the upgrade ends as failed so that the version in spec has to be changed to start another upgrade.
+func (r *ReconcilePlatformAdmin) failRollback(ctx context.Context, platformAdmin *iotv1beta1.PlatformAdmin, platformAdminStatus *iotv1beta1.PlatformAdminStatus, upgrade *iotv1beta1.PlatformAdminUpgrade) error {
+	upgrade.Phase = iotv1beta1.PlatformAdminUpgradeFailed
+	upgrade.Message = fmt.Sprintf("the rollback to version %s is not ready within %s after %s",
+		upgrade.FromVersion, r.Configuration.UpgradeStepTimeout, upgrade.Message)
+	r.recorder.Eventf(platformAdmin, corev1.EventTypeWarning, iotv1beta1.UpgradeRollbackFailedReason,
+		"Could not roll back the components to version %s: %s", upgrade.FromVersion, upgrade.Message)
+	finishUpgrade(platformAdminStatus, upgrade)
+	return r.removeFrameworkBackup(ctx, platformAdmin)
+}
+
+// renderUpgradeStep writes the framework of the current step, the components of the finished steps and
+// the current one are rendered with the target version while the others keep running with the previous version.
+// It returns true if the framework is changed.
+func (r *ReconcilePlatformAdmin) renderUpgradeStep(ctx context.Context, platformAdmin *iotv1beta1.PlatformAdmin, upgrade *iotv1beta1.PlatformAdminUpgrade, platformAdminFramework *PlatformAdminFramework) (bool, error) {
+	previous, err := r.readFrameworkBackup(ctx, platformAdmin)
+	if err != nil {
+		return false, err
+	}
+	if previous == nil {
+		return false, fmt.Errorf("the framework of version %s is not found", upgrade.FromVersion)
+	}
+	target := r.renderTargetFramework(platformAdmin, previous, upgrade.FromVersion, upgrade.ToVersion)
+
+	current, err := runtime.Encode(r.yamlSerializer, platformAdminFramework)
+	if err != nil {
+		return false, err
+	}
+	platformAdminFramework.Components = mergeUpgradeStep(previous.Components, target.Components, upgrade.Steps[:upgrade.CurrentStep+1])
+	// The configmaps are read by the core services, so they are switched along with the first step
+	platformAdminFramework.ConfigMaps = target.ConfigMaps
+	desired, err := runtime.Encode(r.yamlSerializer, platformAdminFramework)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(current, desired) {
+		return false, nil
+	}
+	return true, r.writeFramework(ctx, platformAdmin, platformAdminFramework)
+}
+
+// mergeUpgradeStep picks the target components of the upgraded steps and the previous components of the others
+func mergeUpgradeStep(previous, target []*config.Component, upgradedSteps []string) []*config.Component {
+	upgraded := sets.New[string](upgradedSteps...)
+	targetComponents := make(map[string]*config.Component, len(target))
+	for _, component := range target {
+		targetComponents[component.Name] = component
+	}
+
+	components := make([]*config.Component, 0, len(previous)+len(target))
+	previousSet := sets.New[string]()
+	for _, component := range previous {
+		previousSet.Insert(component.Name)
+		if !upgraded.Has(componentUpgradeStep(component.Name)) {
+			components = append(components, component)
+		} else if tc, ok := targetComponents[component.Name]; ok {
+			components = append(components, tc)
+		}
+	}
+	for _, component := range target {
+		if !previousSet.Has(component.Name) && upgraded.Has(componentUpgradeStep(component.Name)) {
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
+// renderFramework renders the standard framework of the version in the PlatformAdmin,
+// the components already in the framework are kept if the version still requires them
+func (r *ReconcilePlatformAdmin) renderFramework(platformAdmin *iotv1beta1.PlatformAdmin, platformAdminFramework *PlatformAdminFramework) *PlatformAdminFramework {
+	platformAdminFramework.name = FrameworkName
+	platformAdminFramework.security = platformAdmin.Spec.Security
+	if isEdgeXPlatform(platformAdmin) {
+		if platformAdmin.Spec.Security {
+			platformAdminFramework.ConfigMaps = r.Configuration.SecurityConfigMaps[platformAdmin.Spec.Version]
+		} else {
+			platformAdminFramework.ConfigMaps = r.Configuration.NoSectyConfigMaps[platformAdmin.Spec.Version]
+		}
+	}
+	r.calculateDesiredComponents(platformAdmin, platformAdminFramework)
+	return platformAdminFramework
+}
+
+// renderTargetFramework renders the framework of the target version from the previous one,
+// the components that have a template in the target version are replaced by the template,
+// the others are customized by users and are kept as they are.
+func (r *ReconcilePlatformAdmin) renderTargetFramework(platformAdmin *iotv1beta1.PlatformAdmin, previous *PlatformAdminFramework, from, to string) *PlatformAdminFramework {
+	templates := sets.New[string]()
+	for _, component := range r.componentTemplates(platformAdmin, to) {
+		templates.Insert(component.Name)
+	}
+
+	target := &PlatformAdminFramework{ConfigMaps: previous.ConfigMaps}
+	var iotDock *config.Component
+	for _, component := range previous.Components {
+		switch {
+		case component.Name == util.IotDockName:
+			iotDock = upgradeIotDockComponent(component, from, to)
+		case !templates.Has(component.Name):
+			target.Components = append(target.Components, component)
+		}
+	}
+	r.renderFramework(platformAdminWithVersion(platformAdmin, to), target)
+
+	// The yurt-iot-dock customized by users is kept, only its version is changed
+	if iotDock != nil {
+		for i, component := range target.Components {
+			if component.Name == util.IotDockName {
+				target.Components[i] = iotDock
+			}
+		}
+	}
+	return target
+}
+
+// preflightUpgrade checks that the standard configuration of the target version exists
+// and the yurt-iot-dock is able to dock with it.
+func (r *ReconcilePlatformAdmin) preflightUpgrade(platformAdmin *iotv1beta1.PlatformAdmin, target *PlatformAdminFramework, to string) error {
+	// The version of the mqtt platform is the version of its broker
+	if isMQTTPlatform(platformAdmin) {
+		if !util.MQTTBrokerVersions.Has(to) {
+			return fmt.Errorf("version %s of mqtt broker is not supported, it must be one of %s", to, strings.Join(sets.List(util.MQTTBrokerVersions), ","))
+		}
+		return nil
+	}
+	// The components of other platforms are customized by users and do not depend on the version
+	if !isEdgeXPlatform(platformAdmin) {
+		return nil
+	}
+
+	if !config.ExtractVersionsName(&r.Configuration.Manifest).Has(to) {
+		return fmt.Errorf("version %s is not found in the manifest", to)
+	}
+	if len(r.componentTemplates(platformAdmin, to)) == 0 {
+		return fmt.Errorf("the component templates of version %s are not found", to)
+	}
+	configMaps := r.Configuration.NoSectyConfigMaps
+	if platformAdmin.Spec.Security {
+		configMaps = r.Configuration.SecurityConfigMaps
+	}
+	if _, ok := configMaps[to]; !ok {
+		return fmt.Errorf("the configmaps of version %s are not found", to)
+	}
+
+	targetSet := sets.New[string]()
+	for _, component := range target.Components {
+		targetSet.Insert(component.Name)
+	}
+	for _, name := range sets.List(config.ExtractRequiredComponentsName(&r.Configuration.Manifest, to)) {
+		if !targetSet.Has(name) {
+			return fmt.Errorf("the required component %s of version %s is not found", name, to)
+		}
+	}
+
+	// The yurt-iot-dock image may be overridden or defined in spec, so the components to deploy are checked
+	for _, component := range renderComponents(platformAdminWithVersion(platformAdmin, to), target) {
+		if component.Name != util.IotDockName || component.Deployment == nil {
+			continue
+		}
+		for _, container := range component.Deployment.Template.Spec.Containers {
+			if container.Name != util.IotDockName {
+				continue
+			}
+			if err := util.CheckIotDockCompatible(container.Image, to); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// componentTemplates returns the standard components of the version, the components of the mqtt platform
+// other than its broker are customized by users
+func (r *ReconcilePlatformAdmin) componentTemplates(platformAdmin *iotv1beta1.PlatformAdmin, version string) []*config.Component {
+	if isMQTTPlatform(platformAdmin) {
+		if !util.MQTTBrokerVersions.Has(version) {
+			return nil
+		}
+		return []*config.Component{newMQTTBrokerComponent(version)}
+	}
+	if !isEdgeXPlatform(platformAdmin) {
+		return nil
+	}
+	if platformAdmin.Spec.Security {
+		return r.Configuration.SecurityComponents[version]
+	}
+	return r.Configuration.NoSectyComponents[version]
+}
+
+// isComponentsUpdated checks whether the yurtappsets of all the components have rolled out their latest template
+func (r *ReconcilePlatformAdmin) isComponentsUpdated(ctx context.Context, platformAdmin *iotv1beta1.PlatformAdmin, platformAdminFramework *PlatformAdminFramework) (bool, error) {
+	for _, component := range renderComponents(platformAdmin, platformAdminFramework) {
+		if component.Deployment == nil {
+			continue
+		}
+		yas := &appsv1beta1.YurtAppSet{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: platformAdmin.Namespace, Name: platformAdmin.Name + "-" + component.Name}, yas); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		if yas.Status.ObservedGeneration < yas.Generation || yas.Status.ReadyWorkloads != yas.Status.TotalWorkloads {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// backupFramework keeps the framework in the framework configmap so that it can be restored if the upgrade fails
+func (r *ReconcilePlatformAdmin) backupFramework(ctx context.Context, platformAdmin *iotv1beta1.PlatformAdmin, platformAdminFramework *PlatformAdminFramework) error {
+	data, err := runtime.Encode(r.yamlSerializer, platformAdminFramework)
+	if err != nil {
+		klog.Error(Format("could not marshal framework for PlatformAdmin %s/%s", platformAdmin.Namespace, platformAdmin.Name))
+		return err
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: platformAdmin.Namespace, Name: FrameworkName}, cm); err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[FrameworkBackupKey] = string(data)
+	return r.Update(ctx, cm)
+}
+
+// readFrameworkBackup reads the framework that ran before the upgrade, it returns nil if there is no backup
+func (r *ReconcilePlatformAdmin) readFrameworkBackup(ctx context.Context, platformAdmin *iotv1beta1.PlatformAdmin) (*PlatformAdminFramework, error) {
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: platformAdmin.Namespace, Name: FrameworkName}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	data, ok := cm.Data[FrameworkBackupKey]
+	if !ok {
+		return nil, nil
+	}
+
+	previous := &PlatformAdminFramework{name: FrameworkName}
+	if err := runtime.DecodeInto(r.yamlSerializer, []byte(data), previous); err != nil {
+		klog.Error(Format("Decode framework backup for PlatformAdmin %s/%s error %v", platformAdmin.Namespace, platformAdmin.Name, err))
+		return nil, err
+	}
+	return previous, nil
+}
+
+// removeFrameworkBackup removes the backup after the upgrade is finished
+func (r *ReconcilePlatformAdmin) removeFrameworkBackup(ctx context.Context, platformAdmin *iotv1beta1.PlatformAdmin) error {
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: platformAdmin.Namespace, Name: FrameworkName}, cm); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if _, ok := cm.Data[FrameworkBackupKey]; !ok {
+		return nil
+	}
+	delete(cm.Data, FrameworkBackupKey)
+	return r.Update(ctx, cm)
+}
+
+// upgradeIotDockComponent points the yurt-iot-dock to the target version and keeps the customization of users,
+// the default image of the previous version is replaced with the default image of the target version
+func upgradeIotDockComponent(component *config.Component, from, to string) *config.Component {
+	upgraded := &config.Component{Name: component.Name}
+	if component.Service != nil {
+		upgraded.Service = component.Service.DeepCopy()
+	}
+	if component.Deployment == nil {
+		return upgraded
+	}
+	upgraded.Deployment = component.Deployment.DeepCopy()
+
+	containers := upgraded.Deployment.Template.Spec.Containers
+	for i := range containers {
+		if containers[i].Name != util.IotDockName {
+			continue
+		}
+		if containers[i].Image == fmt.Sprintf("%s:%s", util.IotDockImage, util.IotDockImageTag(from)) {
+			containers[i].Image = fmt.Sprintf("%s:%s", util.IotDockImage, util.IotDockImageTag(to))
+		}
+		for j, arg := range containers[i].Args {
+			if strings.HasPrefix(arg, "--version=") {
+				containers[i].Args[j] = fmt.Sprintf("--version=%s", to)
+			}
+		}
+	}
+	return upgraded
+}
+
+// finishUpgrade records the finished upgrade in the history
+func finishUpgrade(platformAdminStatus *iotv1beta1.PlatformAdminStatus, upgrade *iotv1beta1.PlatformAdminUpgrade) {
+	upgrade.CompletionTime = ptrNow()
+	platformAdminStatus.Upgrade = upgrade
+	platformAdminStatus.UpgradeHistory = append([]iotv1beta1.PlatformAdminUpgrade{*upgrade.DeepCopy()}, platformAdminStatus.UpgradeHistory...)
+	if len(platformAdminStatus.UpgradeHistory) > iotv1beta1.MaxPlatformAdminUpgradeHistory {
+		platformAdminStatus.UpgradeHistory = platformAdminStatus.UpgradeHistory[:iotv1beta1.MaxPlatformAdminUpgradeHistory]
+	}
+}
+
+// setUpgradeCondition reflects whether the components run with the version in spec
+func setUpgradeCondition(platformAdmin *iotv1beta1.PlatformAdmin, platformAdminStatus *iotv1beta1.PlatformAdminStatus) {
+	upgrade := platformAdminStatus.Upgrade
+	if !isUpgradeInProgress(upgrade) && platformAdmin.Spec.Version == platformAdminStatus.CurrentVersion {
+		util.SetPlatformAdminCondition(platformAdminStatus, util.NewPlatformAdminCondition(iotv1beta1.UpgradeCompletedCondition, corev1.ConditionTrue, "", ""))
+		return
+	}
+	if upgrade == nil {
+		return
+	}
+
+	var reason, message string
+	switch upgrade.Phase {
+	case iotv1beta1.PlatformAdminUpgrading:
+		reason = iotv1beta1.UpgradingReason
+		message = fmt.Sprintf("upgrading from version %s to %s", upgrade.FromVersion, upgrade.ToVersion)
+	case iotv1beta1.PlatformAdminUpgradeRollingBack:
+		reason, message = iotv1beta1.UpgradeRollingBackReason, upgrade.Message
+	case iotv1beta1.PlatformAdminUpgradeFailed:
+		// An upgrade rejected by the pre-flight checks never gets its steps
+		reason, message = iotv1beta1.UpgradePreflightFailedReason, upgrade.Message
+		if len(upgrade.Steps) != 0 {
+			reason = iotv1beta1.UpgradeRollbackFailedReason
+		}
+	case iotv1beta1.PlatformAdminUpgradeRolledBack:
+		reason, message = iotv1beta1.UpgradeRolledBackReason, upgrade.Message
+	default:
+		return
+	}
+	util.SetPlatformAdminCondition(platformAdminStatus, util.NewPlatformAdminCondition(iotv1beta1.UpgradeCompletedCondition, corev1.ConditionFalse, reason, message))
+}
+
+func ptrNow() *metav1.Time {
+	now := metav1.Now()
+	return &now
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platformadmin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openyurtio/openyurt/pkg/apis/apps/v1beta1"
+	iotv1beta1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1beta1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/platformadmin/config"
+	util "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/platformadmin/utils"
+)
+
+func newUpgradeTestReconciler(t *testing.T, platformAdmin *iotv1beta1.PlatformAdmin) (*ReconcilePlatformAdmin, client.Client) {
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(fakeScheme).
+		WithObjects(platformAdmin).
+		WithStatusSubresource(&iotv1beta1.PlatformAdmin{}).
+		Build()
+	r := &ReconcilePlatformAdmin{
+		Client:         fakeClient,
+		scheme:         fakeScheme,
+		recorder:       &fakeEventRecorder{},
+		yamlSerializer: kjson.NewSerializerWithOptions(kjson.DefaultMetaFactory, scheme.Scheme, scheme.Scheme, kjson.SerializerOptions{Yaml: true, Pretty: true}),
+		Configuration:  *config.NewPlatformAdminControllerConfiguration(),
+	}
+
+	// install the components of the initial version
+	reconcileOnce(t, r, platformAdmin)
+	return r, fakeClient
+}
+
+func reconcileOnce(t *testing.T, r *ReconcilePlatformAdmin, platformAdmin *iotv1beta1.PlatformAdmin) *iotv1beta1.PlatformAdmin {
+	_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(platformAdmin)})
+	require.NoError(t, err)
+
+	pa := &iotv1beta1.PlatformAdmin{}
+	require.NoError(t, r.Get(context.TODO(), client.ObjectKeyFromObject(platformAdmin), pa))
+	return pa
+}
+
+func setVersion(t *testing.T, c client.Client, platformAdmin *iotv1beta1.PlatformAdmin, version string) {
+	pa := &iotv1beta1.PlatformAdmin{}
+	require.NoError(t, c.Get(context.TODO(), client.ObjectKeyFromObject(platformAdmin), pa))
+	pa.Spec.Version = version
+	require.NoError(t, c.Update(context.TODO(), pa))
+}
+
+func componentImage(t *testing.T, c client.Client, platformAdmin *iotv1beta1.PlatformAdmin, component string) string {
+	yas := &v1beta1.YurtAppSet{}
+	require.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: platformAdmin.Namespace, Name: platformAdmin.Name + "-" + component}, yas))
+	return yas.Spec.WorkloadTemplate.DeploymentTemplate.Spec.Template.Spec.Containers[0].Image
+}
+
+func templateImage(t *testing.T, version, component string) string {
+	for _, cp := range config.NewPlatformAdminControllerConfiguration().NoSectyComponents[version] {
+		if cp.Name == component {
+			return cp.Deployment.Template.Spec.Containers[0].Image
+		}
+	}
+	t.Fatalf("no template of %s in version %s", component, version)
+	return ""
+}
+
+func newUpgradeTestPlatformAdmin() *iotv1beta1.PlatformAdmin {
+	return &iotv1beta1.PlatformAdmin{
+		ObjectMeta: metav1.ObjectMeta{Name: "edgex", Namespace: "default"},
+		Spec: iotv1beta1.PlatformAdminSpec{
+			Version:   "minnesota",
+			NodePools: []string{"pool1"},
+			Components: []iotv1beta1.Component{
+				{Name: "edgex-device-virtual"},
+				{Name: util.IotDockName},
+			},
+		},
+	}
+}
+
+func TestPlatformAdminUpgrade(t *testing.T) {
+	platformAdmin := newUpgradeTestPlatformAdmin()
+	r, c := newUpgradeTestReconciler(t, platformAdmin)
+
+	pa := reconcileOnce(t, r, platformAdmin)
+	assert.Equal(t, "minnesota", pa.Status.CurrentVersion)
+	assert.Nil(t, pa.Status.Upgrade)
+	cond := util.GetPlatformAdminCondition(pa.Status, iotv1beta1.UpgradeCompletedCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+
+	setVersion(t, c, platformAdmin, "napa")
+	pa = reconcileOnce(t, r, platformAdmin)
+	require.NotNil(t, pa.Status.Upgrade)
+	assert.Equal(t, iotv1beta1.PlatformAdminUpgrading, pa.Status.Upgrade.Phase)
+	assert.Equal(t, []string{UpgradeStepCore, UpgradeStepDeviceService, UpgradeStepIotDock}, pa.Status.Upgrade.Steps)
+	assert.Equal(t, "minnesota", pa.Status.CurrentVersion)
+
+	// the core services are upgraded before the device services
+	assert.Equal(t, templateImage(t, "napa", "edgex-core-command"), componentImage(t, c, platformAdmin, "edgex-core-command"))
+	assert.Equal(t, templateImage(t, "minnesota", "edgex-device-virtual"), componentImage(t, c, platformAdmin, "edgex-device-virtual"))
+
+	for i := 0; i < 10 && pa.Status.Upgrade.Phase == iotv1beta1.PlatformAdminUpgrading; i++ {
+		pa = reconcileOnce(t, r, platformAdmin)
+	}
+	assert.Equal(t, iotv1beta1.PlatformAdminUpgradeSucceeded, pa.Status.Upgrade.Phase)
+	assert.Equal(t, "napa", pa.Status.CurrentVersion)
+	require.Len(t, pa.Status.UpgradeHistory, 1)
+	assert.Equal(t, "minnesota", pa.Status.UpgradeHistory[0].FromVersion)
+	assert.Equal(t, "napa", pa.Status.UpgradeHistory[0].ToVersion)
+	assert.Equal(t, templateImage(t, "napa", "edgex-device-virtual"), componentImage(t, c, platformAdmin, "edgex-device-virtual"))
+
+	previous, err := r.readFrameworkBackup(context.TODO(), platformAdmin)
+	assert.NoError(t, err)
+	assert.Nil(t, previous)
+}
+
+func TestPlatformAdminUpgradeRollback(t *testing.T) {
+	platformAdmin := newUpgradeTestPlatformAdmin()
+	r, c := newUpgradeTestReconciler(t, platformAdmin)
+	r.Configuration.UpgradeStepTimeout = time.Nanosecond
+
+	setVersion(t, c, platformAdmin, "napa")
+	pa := reconcileOnce(t, r, platformAdmin)
+	require.NotNil(t, pa.Status.Upgrade)
+	assert.Equal(t, iotv1beta1.PlatformAdminUpgrading, pa.Status.Upgrade.Phase)
+
+	// the core step can not be ready within the timeout
+	pa = reconcileOnce(t, r, platformAdmin)
+	assert.Equal(t, iotv1beta1.PlatformAdminUpgradeRollingBack, pa.Status.Upgrade.Phase)
+	assert.Equal(t, templateImage(t, "minnesota", "edgex-core-command"), componentImage(t, c, platformAdmin, "edgex-core-command"))
+
+	pa = reconcileOnce(t, r, platformAdmin)
+	assert.Equal(t, iotv1beta1.PlatformAdminUpgradeRolledBack, pa.Status.Upgrade.Phase)
+	assert.Equal(t, "minnesota", pa.Status.CurrentVersion)
+	require.Len(t, pa.Status.UpgradeHistory, 1)
+	cond := util.GetPlatformAdminCondition(pa.Status, iotv1beta1.UpgradeCompletedCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, iotv1beta1.UpgradeRolledBackReason, cond.Reason)
+
+	// the failed upgrade is not retried until the version is changed again
+	pa = reconcileOnce(t, r, platformAdmin)
+	assert.Equal(t, iotv1beta1.PlatformAdminUpgradeRolledBack, pa.Status.Upgrade.Phase)
+	assert.Len(t, pa.Status.UpgradeHistory, 1)
+}
+
+func TestPlatformAdminUpgradeRollbackTimeout(t *testing.T) {
+	platformAdmin := newUpgradeTestPlatformAdmin()
+	r, c := newUpgradeTestReconciler(t, platformAdmin)
+	r.Configuration.UpgradeStepTimeout = time.Nanosecond
+
+	setVersion(t, c, platformAdmin, "napa")
+	reconcileOnce(t, r, platformAdmin)
+	pa := reconcileOnce(t, r, platformAdmin)
+	require.Equal(t, iotv1beta1.PlatformAdminUpgradeRollingBack, pa.Status.Upgrade.Phase)
+
+	// the restored core services can not be ready within the timeout
+	yas := &v1beta1.YurtAppSet{}
+	require.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: platformAdmin.Namespace, Name: platformAdmin.Name + "-edgex-core-command"}, yas))
+	yas.Status.TotalWorkloads = 1
+	yas.Status.ReadyWorkloads = 0
+	require.NoError(t, c.Update(context.TODO(), yas))
+
+	pa = reconcileOnce(t, r, platformAdmin)
+	assert.Equal(t, iotv1beta1.PlatformAdminUpgradeFailed, pa.Status.Upgrade.Phase)
+	assert.Contains(t, pa.Status.Upgrade.Message, "the rollback to version minnesota is not ready")
+	assert.Equal(t, "minnesota", pa.Status.CurrentVersion)
+	require.Len(t, pa.Status.UpgradeHistory, 1)
+	cond := util.GetPlatformAdminCondition(pa.Status, iotv1beta1.UpgradeCompletedCondition)
+	require.NotNil(t, cond)
+	assert.Equal(t, iotv1beta1.UpgradeRollbackFailedReason, cond.Reason)
+	assert.Equal(t, templateImage(t, "minnesota", "edgex-core-command"), componentImage(t, c, platformAdmin, "edgex-core-command"))
+
+	previous, err := r.readFrameworkBackup(context.TODO(), platformAdmin)
+	assert.NoError(t, err)
+	assert.Nil(t, previous)
+
+	// the failed upgrade is not retried until the version is changed again
+	pa = reconcileOnce(t, r, platformAdmin)
+	assert.Equal(t, iotv1beta1.PlatformAdminUpgradeFailed, pa.Status.Upgrade.Phase)
+	assert.Len(t, pa.Status.UpgradeHistory, 1)
+}
+
+func TestPlatformAdminUpgradePreflight(t *testing.T) {
+	platformAdmin := newUpgradeTestPlatformAdmin()
+	r, c := newUpgradeTestReconciler(t, platformAdmin)
+
+	setVersion(t, c, platformAdmin, "unknown")
+	pa := reconcileOnce(t, r, platformAdmin)
+	require.NotNil(t, pa.Status.Upgrade)
+	assert.Equal(t, iotv1beta1.PlatformAdminUpgradeFailed, pa.Status.Upgrade.Phase)
+	assert.Contains(t, pa.Status.Upgrade.Message, "not found in the manifest")
+	assert.Equal(t, "minnesota", pa.Status.CurrentVersion)
+	assert.Equal(t, templateImage(t, "minnesota", "edgex-core-command"), componentImage(t, c, platformAdmin, "edgex-core-command"))
+
+	// the yurt-iot-dock built by openyurt must support the target version
+	framework, err := r.readFramework(context.TODO(), pa)
+	require.NoError(t, err)
+	for _, component := range framework.Components {
+		if component.Name == util.IotDockName {
+			component.Deployment.Template.Spec.Containers[0].Image = util.IotDockImage + ":v1.4.0"
+		}
+	}
+	target := r.renderTargetFramework(pa, framework, "minnesota", "napa")
+	assert.ErrorContains(t, r.preflightUpgrade(pa, target, "napa"), "is not compatible with version napa")
+
+	// the yurt-iot-dock image overridden in spec is checked as well
+	framework, err = r.readFramework(context.TODO(), pa)
+	require.NoError(t, err)
+	target = r.renderTargetFramework(pa, framework, "minnesota", "napa")
+	require.NoError(t, r.preflightUpgrade(pa, target, "napa"))
+	for i := range pa.Spec.Components {
+		if pa.Spec.Components[i].Name == util.IotDockName {
+			pa.Spec.Components[i].Override = &iotv1beta1.ComponentOverride{Image: util.IotDockImage + ":v1.4.0"}
+		}
+	}
+	assert.ErrorContains(t, r.preflightUpgrade(pa, target, "napa"), "is not compatible with version napa")
+}
+
+func TestMergeUpgradeStep(t *testing.T) {
+	previous := []*config.Component{{Name: "edgex-core-data"}, {Name: "edgex-device-rest"}, {Name: "edgex-sys-mgmt-agent"}}
+	target := []*config.Component{{Name: "edgex-core-data"}, {Name: "edgex-device-rest"}, {Name: "edgex-core-common-config-bootstrapper"}}
+
+	names := func(components []*config.Component) []string {
+		var ns []string
+		for _, c := range components {
+			ns = append(ns, c.Name)
+		}
+		return ns
+	}
+
+	merged := mergeUpgradeStep(previous, target, []string{UpgradeStepCore})
+	assert.Equal(t, []string{"edgex-core-data", "edgex-device-rest", "edgex-core-common-config-bootstrapper"}, names(merged))
+	assert.Same(t, target[0], merged[0])
+	assert.Same(t, previous[1], merged[1])
+
+	merged = mergeUpgradeStep(previous, target, []string{UpgradeStepCore, UpgradeStepDeviceService})
+	assert.Same(t, target[1], merged[1])
+}
+
+func TestPlatformAdminMQTTUpgrade(t *testing.T) {
+	platformAdmin := &iotv1beta1.PlatformAdmin{
+		ObjectMeta: metav1.ObjectMeta{Name: "mqtt", Namespace: "default"},
+		Spec: iotv1beta1.PlatformAdminSpec{
+			Version:    "1.6",
+			Platform:   iotv1beta1.PlatformAdminPlatformMQTT,
+			NodePools:  []string{"pool1"},
+			Components: []iotv1beta1.Component{{Name: util.IotDockName}},
+		},
+	}
+	r, c := newUpgradeTestReconciler(t, platformAdmin)
+	assert.Equal(t, util.MQTTBrokerImage+":1.6", componentImage(t, c, platformAdmin, util.MQTTBrokerName))
+	assert.Equal(t, util.IotDockImage+":"+util.IotDockMQTTImageTag, componentImage(t, c, platformAdmin, util.IotDockName))
+
+	// the broker is upgraded to the target version, the yurt-iot-dock is kept
+	setVersion(t, c, platformAdmin, "2.0")
+	pa := reconcileOnce(t, r, platformAdmin)
+	for i := 0; i < 10 && pa.Status.Upgrade != nil && pa.Status.Upgrade.Phase == iotv1beta1.PlatformAdminUpgrading; i++ {
+		pa = reconcileOnce(t, r, platformAdmin)
+	}
+	require.NotNil(t, pa.Status.Upgrade)
+	assert.Equal(t, iotv1beta1.PlatformAdminUpgradeSucceeded, pa.Status.Upgrade.Phase)
+	assert.Equal(t, "2.0", pa.Status.CurrentVersion)
+	assert.Equal(t, util.MQTTBrokerImage+":2.0", componentImage(t, c, platformAdmin, util.MQTTBrokerName))
+	assert.Equal(t, util.IotDockImage+":"+util.IotDockMQTTImageTag, componentImage(t, c, platformAdmin, util.IotDockName))
+
+	// the unsupported broker version fails the preflight
+	setVersion(t, c, platformAdmin, "3.0")
+	pa = reconcileOnce(t, r, platformAdmin)
+	assert.Equal(t, iotv1beta1.PlatformAdminUpgradeFailed, pa.Status.Upgrade.Phase)
+	assert.Contains(t, pa.Status.Upgrade.Message, "version 3.0 of mqtt broker is not supported")
+	assert.Equal(t, util.MQTTBrokerImage+":2.0", componentImage(t, c, platformAdmin, util.MQTTBrokerName))
+}
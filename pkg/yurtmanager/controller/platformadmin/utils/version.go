@@ -17,6 +17,11 @@ limitations under the License.
 package util
 
 import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
 	iotv1beta1 "github.com/openyurtio/openyurt/pkg/apis/iot/v1beta1"
 )
 
@@ -24,17 +29,71 @@ const IotDockName = "yurt-iot-dock"
 const IotDockImage = "openyurt/yurt-iot-dock"
 const IotDockControlPlane = "platformadmin-controller"
 
+// IotDockReadingsPort is the port of the device readings API served by yurt-iot-dock
+const IotDockReadingsPort = 8443
+
+// DefaultIotDockImageTag is the tag of the yurt-iot-dock image for the edgex versions that are not recorded in iotDockReleases
+const DefaultIotDockImageTag = "v1.4.0"
+
+// IotDockMQTTImageTag is the tag of the default yurt-iot-dock image for the mqtt platform,
+// the mqtt device registry is not supported by the released images yet
+const IotDockMQTTImageTag = "latest"
+
+const MQTTBrokerName = "mqtt-broker"
+const MQTTBrokerImage = "eclipse-mosquitto"
+const MQTTBrokerPort = 1883
+
+// MQTTBrokerVersions records the versions of mosquitto broker which is able to host the mqtt device registry,
+// the version of PlatformAdmin is the version of broker for the mqtt platform
+var MQTTBrokerVersions = sets.New[string]("1.6", "2.0")
+
+// iotDockReleases records the edgex versions that each released yurt-iot-dock image is able to dock with,
+// yurt-iot-dock talks to edgex through the v2 api up to v1.4.0 and through the v3 api since then.
+var iotDockReleases = []struct {
+	tag      string
+	versions sets.Set[string]
+}{
+	{tag: "latest", versions: sets.New[string]("minnesota", "napa")},
+	{tag: "v1.4.0", versions: sets.New[string]("hanoi", "ireland", "jakarta", "kamakura", "levski")},
+}
+
 func DefaultVersion(platformAdmin *iotv1beta1.PlatformAdmin) (string, string, error) {
-	var (
-		ver string
-		ns  string
-	)
-
-	if platformAdmin.Spec.Version == "minnesota" {
-		ver = "latest"
-	} else {
-		ver = "v1.4.0"
+	if platformAdmin.Spec.Platform == iotv1beta1.PlatformAdminPlatformMQTT {
+		return IotDockMQTTImageTag, platformAdmin.Namespace, nil
+	}
+	return IotDockImageTag(platformAdmin.Spec.Version), platformAdmin.Namespace, nil
+}
+
+// IotDockImageTag returns the tag of the default yurt-iot-dock image for the edgex version
+func IotDockImageTag(version string) string {
+	for _, release := range iotDockReleases {
+		if release.versions.Has(version) {
+			return release.tag
+		}
+	}
+	return DefaultIotDockImageTag
+}
+
+// CheckIotDockCompatible checks whether the yurt-iot-dock image is able to dock with the edgex version,
+// the images that are not released by openyurt are built by users and can not be checked
+func CheckIotDockCompatible(image, version string) error {
+	name, tag := image, ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		name, tag = image[:i], image[i+1:]
+	}
+	if !strings.HasSuffix(name, IotDockImage) {
+		return nil
+	}
+
+	for _, release := range iotDockReleases {
+		if release.tag != tag {
+			continue
+		}
+		if !release.versions.Has(version) {
+			return fmt.Errorf("%s is not compatible with version %s, it supports %s",
+				image, version, strings.Join(sets.List(release.versions), ","))
+		}
+		return nil
 	}
-	ns = platformAdmin.Namespace
-	return ver, ns, nil
+	return nil
 }
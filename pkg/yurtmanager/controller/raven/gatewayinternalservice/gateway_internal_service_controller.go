@@ -163,6 +163,8 @@ func (r *ReconcileService) listExposedGateway(ctx context.Context) ([]*ravenv1be
 			exposedGateways = append(exposedGateways, gw.DeepCopy())
 		case ravenv1beta1.ExposeTypeLoadBalancer:
 			exposedGateways = append(exposedGateways, gw.DeepCopy())
+		case ravenv1beta1.ExposeTypeNodePort, ravenv1beta1.ExposeTypeStaticIPPool:
+			exposedGateways = append(exposedGateways, gw.DeepCopy())
 		default:
 			continue
 		}
@@ -16,7 +16,14 @@ limitations under the License.
 
 package config
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // GatewayPickupControllerConfiguration contains elements describing GatewayPickController.
 type GatewayPickupControllerConfiguration struct {
 	ConcurrentGatewayPickupWorkers int32
+	// EndpointFailoverPeriod is the period after the health lease of an active endpoint expires
+	// before the active endpoint is failed over to a standby endpoint.
+	EndpointFailoverPeriod metav1.Duration
 }
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gatewaypickup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	ravenv1beta1 "github.com/openyurtio/openyurt/pkg/apis/raven/v1beta1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/raven/util"
+)
+
+// maxFailoverRecords is the maximum number of failover records kept in the status of Gateway.
+const maxFailoverRecords = 10
+
+func endpointKey(endpointType, nodeName string) string {
+	return endpointType + "/" + nodeName
+}
+
+// resolveUnhealthyEndpoints returns the keys of endpoints whose health lease has expired for longer than the
+// failover period. Endpoints without health lease are regarded as healthy, because their raven agents may not
+// report health at all. The duration after which the first active endpoint would become unhealthy is returned
+// as well, so the Gateway can be reconciled in time for failover.
+func (r *ReconcileGateway) resolveUnhealthyEndpoints(ctx context.Context, gw *ravenv1beta1.Gateway, now time.Time) (sets.Set[string], time.Duration) {
+	active := sets.Set[string]{}
+	for _, aep := range gw.Status.ActiveEndpoints {
+		active.Insert(endpointKey(aep.Type, aep.NodeName))
+	}
+
+	unhealthy := sets.Set[string]{}
+	var requeueAfter time.Duration
+	for _, ep := range gw.Spec.Endpoints {
+		var lease coordinationv1.Lease
+		err := r.Get(ctx, types.NamespacedName{Namespace: util.WorkingNamespace, Name: util.EndpointHealthLeaseName(ep.Type, ep.NodeName)}, &lease)
+		if err != nil {
+			if !apierrs.IsNotFound(err) {
+				klog.Error(Format("unable to get health lease of endpoint %s/%s, error %s", ep.Type, ep.NodeName, err.Error()))
+			}
+			continue
+		}
+		if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+
+		deadline := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds)*time.Second + r.Configuration.EndpointFailoverPeriod.Duration)
+		if !now.Before(deadline) {
+			unhealthy.Insert(endpointKey(ep.Type, ep.NodeName))
+			continue
+		}
+		if active.Has(endpointKey(ep.Type, ep.NodeName)) && (requeueAfter == 0 || deadline.Sub(now) < requeueAfter) {
+			requeueAfter = deadline.Sub(now)
+		}
+	}
+	return unhealthy, requeueAfter
+}
+
+// recordFailover records failovers of active endpoints in the status of Gateway and emits events for them.
+// An active endpoint is failed over if it's replaced by another endpoint of the same type, or lost because of
+// its node or health.
+func (r *ReconcileGateway) recordFailover(gw *ravenv1beta1.Gateway, previous, current []*ravenv1beta1.Endpoint,
+	readyNodes sets.Set[string], unhealthy sets.Set[string], now time.Time) {
+	currentKeys := sets.Set[string]{}
+	for _, ep := range current {
+		currentKeys.Insert(endpointKey(ep.Type, ep.NodeName))
+	}
+	previousKeys := sets.Set[string]{}
+	for _, ep := range previous {
+		previousKeys.Insert(endpointKey(ep.Type, ep.NodeName))
+	}
+	specKeys := sets.Set[string]{}
+	for _, ep := range gw.Spec.Endpoints {
+		specKeys.Insert(endpointKey(ep.Type, ep.NodeName))
+	}
+
+	for _, prev := range previous {
+		// the endpoint is still active or removed from the Gateway
+		if currentKeys.Has(endpointKey(prev.Type, prev.NodeName)) || !specKeys.Has(endpointKey(prev.Type, prev.NodeName)) {
+			continue
+		}
+
+		var reason string
+		switch {
+		case !readyNodes.Has(prev.NodeName):
+			reason = ravenv1beta1.FailoverReasonNodeNotReady
+		case unhealthy.Has(endpointKey(prev.Type, prev.NodeName)):
+			reason = ravenv1beta1.FailoverReasonUnhealthy
+		default:
+			reason = ravenv1beta1.FailoverReasonPreempted
+		}
+
+		var to string
+		for _, ep := range current {
+			if ep.Type == prev.Type && !previousKeys.Has(endpointKey(ep.Type, ep.NodeName)) {
+				to = ep.NodeName
+				previousKeys.Insert(endpointKey(ep.Type, ep.NodeName))
+				break
+			}
+		}
+		if len(to) == 0 && reason == ravenv1beta1.FailoverReasonPreempted {
+			// the replicas are decreased
+			continue
+		}
+
+		gw.Status.Failovers = append(gw.Status.Failovers, ravenv1beta1.FailoverRecord{
+			Type:   prev.Type,
+			From:   prev.NodeName,
+			To:     to,
+			Reason: reason,
+			Time:   metav1.NewTime(now),
+		})
+		r.recorder.Event(gw.DeepCopy(), corev1.EventTypeWarning, ravenv1beta1.EventActiveEndpointFailover,
+			fmt.Sprintf("The active %s endpoint is failed over from node %s to node %q, reason: %s", prev.Type, prev.NodeName, to, reason))
+	}
+
+	if len(gw.Status.Failovers) > maxFailoverRecords {
+		gw.Status.Failovers = gw.Status.Failovers[len(gw.Status.Failovers)-maxFailoverRecords:]
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gatewaypickup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openyurtio/openyurt/pkg/apis"
+	"github.com/openyurtio/openyurt/pkg/apis/raven"
+	ravenv1beta1 "github.com/openyurtio/openyurt/pkg/apis/raven/v1beta1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/raven/gatewaypickup/config"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/raven/util"
+)
+
+func TestReconcileGateway_failover(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	apis.AddToScheme(scheme)
+
+	newNode := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{raven.LabelCurrentGateway: "gw-mock"},
+			},
+			Status: nodeReadyStatus,
+		}
+	}
+	newLease := func(nodeName string, renewTime time.Time) *coordinationv1.Lease {
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      util.EndpointHealthLeaseName(ravenv1beta1.Tunnel, nodeName),
+				Namespace: util.WorkingNamespace,
+				Labels:    map[string]string{raven.LabelCurrentGateway: "gw-mock"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				LeaseDurationSeconds: ptr.To[int32](10),
+				RenewTime:            &metav1.MicroTime{Time: renewTime},
+			},
+		}
+	}
+
+	testcases := map[string]struct {
+		priority       int
+		lease          *coordinationv1.Lease
+		wantedActive   string
+		wantedReason   string
+		wantedRequeued bool
+	}{
+		"active endpoint is healthy": {
+			lease:          newLease("node-1", time.Now()),
+			wantedActive:   "node-1",
+			wantedRequeued: true,
+		},
+		"active endpoint without health lease": {
+			wantedActive: "node-1",
+		},
+		"active endpoint is unhealthy": {
+			lease:        newLease("node-1", time.Now().Add(-time.Minute)),
+			wantedActive: "node-2",
+			wantedReason: ravenv1beta1.FailoverReasonUnhealthy,
+		},
+		"active endpoint is preempted by endpoint with higher priority": {
+			priority:     1,
+			wantedActive: "node-2",
+			wantedReason: ravenv1beta1.FailoverReasonPreempted,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			gw := &ravenv1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "gw-mock"},
+				Spec: ravenv1beta1.GatewaySpec{
+					TunnelConfig: ravenv1beta1.TunnelConfiguration{Replicas: 1},
+					Endpoints: []ravenv1beta1.Endpoint{
+						{NodeName: "node-1", Type: ravenv1beta1.Tunnel},
+						{NodeName: "node-2", Type: ravenv1beta1.Tunnel, Priority: tc.priority},
+					},
+				},
+				Status: ravenv1beta1.GatewayStatus{
+					ActiveEndpoints: []*ravenv1beta1.Endpoint{{NodeName: "node-1", Type: ravenv1beta1.Tunnel}},
+				},
+			}
+			objs := []client.Object{
+				gw,
+				newNode("node-1"),
+				newNode("node-2"),
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: util.RavenGlobalConfig, Namespace: util.WorkingNamespace},
+					Data:       map[string]string{util.RavenEnableTunnel: "true"},
+				},
+			}
+			if tc.lease != nil {
+				objs = append(objs, tc.lease)
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(gw).Build()
+			r := &ReconcileGateway{
+				Client:   c,
+				recorder: record.NewFakeRecorder(10),
+				Configuration: config.GatewayPickupControllerConfiguration{
+					EndpointFailoverPeriod: metav1.Duration{Duration: 5 * time.Second},
+				},
+			}
+
+			result, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "gw-mock"}})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if requeued := result.RequeueAfter > 0; requeued != tc.wantedRequeued {
+				t.Errorf("expect requeued %v, but got %v", tc.wantedRequeued, result.RequeueAfter)
+			}
+
+			var current ravenv1beta1.Gateway
+			if err := c.Get(context.TODO(), types.NamespacedName{Name: "gw-mock"}, &current); err != nil {
+				t.Fatalf("could not get gateway, %v", err)
+			}
+			if len(current.Status.ActiveEndpoints) != 1 || current.Status.ActiveEndpoints[0].NodeName != tc.wantedActive {
+				t.Errorf("expect active endpoint on %s, but got %v", tc.wantedActive, current.Status.ActiveEndpoints)
+			}
+			if len(tc.wantedReason) == 0 {
+				if len(current.Status.Failovers) != 0 {
+					t.Errorf("expect no failovers, but got %v", current.Status.Failovers)
+				}
+				return
+			}
+			if len(current.Status.Failovers) != 1 {
+				t.Fatalf("expect 1 failover, but got %v", current.Status.Failovers)
+			}
+			failover := current.Status.Failovers[0]
+			if failover.From != "node-1" || failover.To != "node-2" || failover.Reason != tc.wantedReason {
+				t.Errorf("expect failover from node-1 to node-2 for %s, but got %#v", tc.wantedReason, failover)
+			}
+		})
+	}
+}
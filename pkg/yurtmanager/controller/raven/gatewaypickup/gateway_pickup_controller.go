@@ -25,10 +25,12 @@ import (
 	"strings"
 	"time"
 
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -118,6 +120,15 @@ func add(mgr manager.Manager, cfg *appconfig.CompletedConfig, r reconcile.Reconc
 		return err
 	}
 
+	// Watch for changes to health Leases of endpoints
+	err = c.Watch(source.Kind[client.Object](mgr.GetCache(), &coordinationv1.Lease{}, &EnqueueGatewayForEndpointHealth{}, predicate.NewPredicateFuncs(
+		func(object client.Object) bool {
+			return object.GetNamespace() == util.WorkingNamespace && strings.HasPrefix(object.GetName(), util.EndpointHealthLeasePrefix+"-")
+		})))
+	if err != nil {
+		return err
+	}
+
 	err = c.Watch(source.Kind[client.Object](mgr.GetCache(), &corev1.ConfigMap{}, &EnqueueGatewayForRavenConfig{client: yurtClient.GetClientByControllerNameOrDie(mgr, names.GatewayPickupController)}, predicate.NewPredicateFuncs(
 		func(object client.Object) bool {
 			cm, ok := object.(*corev1.ConfigMap)
@@ -144,6 +155,7 @@ func add(mgr manager.Manager, cfg *appconfig.CompletedConfig, r reconcile.Reconc
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get
 //+kubebuilder:rbac:groups=crd.projectcalico.org,resources=blockaffinities,verbs=get
+//+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a Gateway object and makes changes based on the state read
 // and what is in the Gateway.Spec
@@ -168,7 +180,17 @@ func (r *ReconcileGateway) Reconcile(ctx context.Context, req reconcile.Request)
 	}
 
 	// 1. try to elect an active endpoint if possible
-	activeEp := r.electActiveEndpoint(nodeList, &gw)
+	now := time.Now()
+	unhealthy, requeueAfter := r.resolveUnhealthyEndpoints(ctx, &gw, now)
+	activeEp := r.electActiveEndpoint(nodeList, &gw, unhealthy)
+	readyNodes := sets.Set[string]{}
+	for _, v := range nodeList.Items {
+		if isNodeReady(v) {
+			readyNodes.Insert(v.Name)
+		}
+	}
+	r.recordFailover(&gw, gw.Status.ActiveEndpoints, activeEp, readyNodes, unhealthy, now)
+	inheritPublicAddress(&gw, gw.Status.ActiveEndpoints, activeEp)
 	r.recordEndpointEvent(&gw, gw.Status.ActiveEndpoints, activeEp)
 	gw.Status.ActiveEndpoints = activeEp
 	r.configEndpoints(ctx, &gw)
@@ -198,7 +220,8 @@ func (r *ReconcileGateway) Reconcile(ctx context.Context, req reconcile.Request)
 		klog.Error(Format("unable to update %s gateway.status, error %s", gw.GetName(), err.Error()))
 		return reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Second}, err
 	}
-	return reconcile.Result{}, nil
+	// the Gateway should be reconciled again when the health lease of active endpoint expires
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *ReconcileGateway) recordEndpointEvent(sourceObj *ravenv1beta1.Gateway, previous, current []*ravenv1beta1.Endpoint) {
@@ -228,9 +251,10 @@ func (r *ReconcileGateway) recordEndpointEvent(sourceObj *ravenv1beta1.Gateway,
 }
 
 // electActiveEndpoint tries to elect an active Endpoint.
-// If the current active endpoint remains valid, then we don't change it.
-// Otherwise, try to elect a new one.
-func (r *ReconcileGateway) electActiveEndpoint(nodeList corev1.NodeList, gw *ravenv1beta1.Gateway) []*ravenv1beta1.Endpoint {
+// If the current active endpoint remains valid and there is no available endpoint with higher priority,
+// then we don't change it. Otherwise, try to elect a new one. Endpoints on not ready nodes or with
+// unhealthy tunnel are not valid.
+func (r *ReconcileGateway) electActiveEndpoint(nodeList corev1.NodeList, gw *ravenv1beta1.Gateway, unhealthy sets.Set[string]) []*ravenv1beta1.Endpoint {
 	// get all ready nodes referenced by endpoints
 	readyNodes := make(map[string]*corev1.Node)
 	for _, v := range nodeList.Items {
@@ -243,17 +267,16 @@ func (r *ReconcileGateway) electActiveEndpoint(nodeList corev1.NodeList, gw *rav
 	enableProxy, enableTunnel := util.CheckServer(context.TODO(), r.Client)
 	eps := make([]*ravenv1beta1.Endpoint, 0)
 	if enableProxy {
-		eps = append(eps, electEndpoints(gw, ravenv1beta1.Proxy, readyNodes)...)
+		eps = append(eps, electEndpoints(gw, ravenv1beta1.Proxy, readyNodes, unhealthy)...)
 	}
 	if enableTunnel {
-		eps = append(eps, electEndpoints(gw, ravenv1beta1.Tunnel, readyNodes)...)
+		eps = append(eps, electEndpoints(gw, ravenv1beta1.Tunnel, readyNodes, unhealthy)...)
 	}
 	sort.Slice(eps, func(i, j int) bool { return eps[i].NodeName < eps[j].NodeName })
 	return eps
 }
 
-func electEndpoints(gw *ravenv1beta1.Gateway, endpointType string, readyNodes map[string]*corev1.Node) []*ravenv1beta1.Endpoint {
-	eps := make([]*ravenv1beta1.Endpoint, 0)
+func electEndpoints(gw *ravenv1beta1.Gateway, endpointType string, readyNodes map[string]*corev1.Node, unhealthy sets.Set[string]) []*ravenv1beta1.Endpoint {
 	var replicas int
 	switch endpointType {
 	case ravenv1beta1.Proxy:
@@ -265,48 +288,44 @@ func electEndpoints(gw *ravenv1beta1.Gateway, endpointType string, readyNodes ma
 	}
 
 	checkCandidates := func(ep *ravenv1beta1.Endpoint) bool {
-		if _, ok := readyNodes[ep.NodeName]; ok && ep.Type == endpointType {
+		if _, ok := readyNodes[ep.NodeName]; ok && ep.Type == endpointType && !unhealthy.Has(endpointKey(ep.Type, ep.NodeName)) {
 			return true
 		}
 		return false
 	}
 
-	// the current active endpoint is still competent.
-	candidates := make(map[string]*ravenv1beta1.Endpoint, 0)
+	// the current active endpoints are still competent, and they take precedence over
+	// standby endpoints with the same priority.
+	active := sets.Set[string]{}
 	for _, activeEndpoint := range gw.Status.ActiveEndpoints {
-		if checkCandidates(activeEndpoint) {
-			for _, ep := range gw.Spec.Endpoints {
-				if ep.NodeName == activeEndpoint.NodeName && ep.Type == activeEndpoint.Type {
-					candidates[activeEndpoint.NodeName] = ep.DeepCopy()
-				}
-			}
+		if activeEndpoint.Type == endpointType {
+			active.Insert(activeEndpoint.NodeName)
 		}
 	}
-	for _, aep := range candidates {
-		if len(eps) == replicas {
-			aepInfo, _ := getActiveEndpointsInfo(eps)
-			klog.V(4).Info(Format("elect %d active endpoints %s for gateway %s/%s",
-				len(eps), fmt.Sprintf("[%s]", strings.Join(aepInfo[ActiveEndpointsName], ",")), gw.GetNamespace(), gw.GetName()))
-			return eps
-		}
-		klog.V(1).Info(Format("node %s is active endpoints, type is %s", aep.NodeName, aep.Type))
-		klog.V(1).Info(Format("add node %v", aep.DeepCopy()))
-		eps = append(eps, aep.DeepCopy())
-	}
-
-	for _, ep := range gw.Spec.Endpoints {
-		if _, ok := candidates[ep.NodeName]; !ok && checkCandidates(&ep) {
-			if len(eps) == replicas {
-				aepInfo, _ := getActiveEndpointsInfo(eps)
-				klog.V(4).InfoS(Format("elect %d active endpoints %s for gateway %s/%s",
-					len(eps), fmt.Sprintf("[%s]", strings.Join(aepInfo[ActiveEndpointsName], ",")), gw.GetNamespace(), gw.GetName()))
-				return eps
+	candidates := make([]*ravenv1beta1.Endpoint, 0)
+	elected := sets.Set[string]{}
+	for _, isActive := range []bool{true, false} {
+		for i := range gw.Spec.Endpoints {
+			ep := &gw.Spec.Endpoints[i]
+			if active.Has(ep.NodeName) != isActive || elected.Has(ep.NodeName) || !checkCandidates(ep) {
+				continue
 			}
-			klog.V(1).Info(Format("node %s is active endpoints, type is %s", ep.NodeName, ep.Type))
-			klog.V(1).Info(Format("add node %v", ep.DeepCopy()))
-			eps = append(eps, ep.DeepCopy())
+			elected.Insert(ep.NodeName)
+			candidates = append(candidates, ep.DeepCopy())
 		}
 	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Priority > candidates[j].Priority })
+
+	eps := candidates
+	if len(eps) > replicas {
+		eps = eps[:replicas]
+	}
+	for _, ep := range eps {
+		klog.V(1).Info(Format("node %s is active endpoints, type is %s", ep.NodeName, ep.Type))
+	}
+	aepInfo, _ := getActiveEndpointsInfo(eps)
+	klog.V(4).Info(Format("elect %d active endpoints %s for gateway %s/%s",
+		len(eps), fmt.Sprintf("[%s]", strings.Join(aepInfo[ActiveEndpointsName], ",")), gw.GetNamespace(), gw.GetName()))
 	return eps
 }
 
@@ -372,6 +391,27 @@ func (r *ReconcileGateway) configEndpoints(ctx context.Context, gw *ravenv1beta1
 	}
 }
 
+// inheritPublicAddress keeps the public ip and port of active endpoints which are allocated by
+// gatewaypublicservice controller, as the elected endpoints are copied from the spec of gateway.
+func inheritPublicAddress(gw *ravenv1beta1.Gateway, previous, current []*ravenv1beta1.Endpoint) {
+	if !util.IsPublicAddressAllocated(gw.Spec.ExposeType) {
+		return
+	}
+	allocated := make(map[string]*ravenv1beta1.Endpoint)
+	for _, ep := range previous {
+		allocated[endpointKey(ep.Type, ep.NodeName)] = ep
+	}
+	for _, ep := range current {
+		if prev, ok := allocated[endpointKey(ep.Type, ep.NodeName)]; ok {
+			ep.PublicIP = prev.PublicIP
+			ep.PublicPort = prev.PublicPort
+		} else {
+			ep.PublicIP = ""
+			ep.PublicPort = 0
+		}
+	}
+}
+
 func (r *ReconcileGateway) addExtraAllowedSubnet(gw *ravenv1beta1.Gateway) {
 	if gw.Annotations == nil || gw.Annotations[util.ExtraAllowedSourceCIDRs] == "" {
 		return
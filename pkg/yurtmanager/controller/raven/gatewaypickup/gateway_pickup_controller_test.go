@@ -423,7 +423,7 @@ func TestReconcileGateway_electActiveEndpoint(t *testing.T) {
 	for _, v := range tt {
 		t.Run(v.name, func(t *testing.T) {
 			a := assert.New(t)
-			eps := mockReconciler.electActiveEndpoint(v.nodeList, v.gw)
+			eps := mockReconciler.electActiveEndpoint(v.nodeList, v.gw, nil)
 			a.Equal(len(v.expectedEps), len(eps))
 		})
 	}
@@ -542,3 +542,35 @@ func TestReconcileGateway_addExtraAllowedSubnet(t *testing.T) {
 		t.Errorf("failed add extra allowed subnet, expect %v, but get %v", expect.Status.Nodes, gw.Status.Nodes)
 	}
 }
+
+func TestInheritPublicAddress(t *testing.T) {
+	previous := []*ravenv1beta1.Endpoint{
+		{NodeName: "node-1", Type: ravenv1beta1.Tunnel, PublicIP: "10.0.0.1", PublicPort: 4500},
+	}
+	testcases := map[string]struct {
+		exposeType     string
+		wantedPublicIP map[string]string
+	}{
+		"public address is allocated for StaticIPPool gateway": {
+			exposeType:     ravenv1beta1.ExposeTypeStaticIPPool,
+			wantedPublicIP: map[string]string{"node-1": "10.0.0.1", "node-2": ""},
+		},
+		"public address is reported for LoadBalancer gateway": {
+			exposeType:     ravenv1beta1.ExposeTypeLoadBalancer,
+			wantedPublicIP: map[string]string{"node-1": "192.168.0.1", "node-2": "192.168.0.2"},
+		},
+	}
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			gw := &ravenv1beta1.Gateway{Spec: ravenv1beta1.GatewaySpec{ExposeType: tc.exposeType}}
+			current := []*ravenv1beta1.Endpoint{
+				{NodeName: "node-1", Type: ravenv1beta1.Tunnel, PublicIP: "192.168.0.1"},
+				{NodeName: "node-2", Type: ravenv1beta1.Tunnel, PublicIP: "192.168.0.2"},
+			}
+			inheritPublicAddress(gw, previous, current)
+			for _, ep := range current {
+				assert.Equal(t, tc.wantedPublicIP[ep.NodeName], ep.PublicIP, "public ip of %s", ep.NodeName)
+			}
+		})
+	}
+}
@@ -18,7 +18,9 @@ package gatewaypickup
 
 import (
 	"context"
+	"time"
 
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
@@ -178,3 +180,63 @@ func (e *EnqueueGatewayForRavenConfig) enqueueGateways(q workqueue.TypedRateLimi
 	}
 	return nil
 }
+
+// EnqueueGatewayForEndpointHealth enqueues the Gateway of endpoint health Lease when the Lease is created,
+// deleted or renewed after expiration. Expiration of Lease is handled by requeueing the Gateway.
+type EnqueueGatewayForEndpointHealth struct{}
+
+func (e *EnqueueGatewayForEndpointHealth) Create(ctx context.Context, evt event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	lease, ok := evt.Object.(*coordinationv1.Lease)
+	if !ok {
+		klog.Error(Format("could not assert runtime Object to v1.Lease"))
+		return
+	}
+	util.AddGatewayToWorkQueue(lease.Labels[raven.LabelCurrentGateway], q)
+}
+
+func (e *EnqueueGatewayForEndpointHealth) Update(ctx context.Context, evt event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	oldLease, ok := evt.ObjectOld.(*coordinationv1.Lease)
+	if !ok {
+		klog.Error(Format("could not assert runtime Object to v1.Lease"))
+		return
+	}
+	newLease, ok := evt.ObjectNew.(*coordinationv1.Lease)
+	if !ok {
+		klog.Error(Format("could not assert runtime Object to v1.Lease"))
+		return
+	}
+
+	if oldLease.Labels[raven.LabelCurrentGateway] != newLease.Labels[raven.LabelCurrentGateway] {
+		util.AddGatewayToWorkQueue(oldLease.Labels[raven.LabelCurrentGateway], q)
+		util.AddGatewayToWorkQueue(newLease.Labels[raven.LabelCurrentGateway], q)
+		return
+	}
+	if isLeaseRecovered(oldLease, newLease) {
+		klog.V(4).Info(Format("will enqueue gateway as endpoint health lease(%s) is renewed after expiration", newLease.Name))
+		util.AddGatewayToWorkQueue(newLease.Labels[raven.LabelCurrentGateway], q)
+	}
+}
+
+func (e *EnqueueGatewayForEndpointHealth) Delete(ctx context.Context, evt event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	lease, ok := evt.Object.(*coordinationv1.Lease)
+	if !ok {
+		klog.Error(Format("could not assert runtime Object to v1.Lease"))
+		return
+	}
+	util.AddGatewayToWorkQueue(lease.Labels[raven.LabelCurrentGateway], q)
+}
+
+func (e *EnqueueGatewayForEndpointHealth) Generic(ctx context.Context, evt event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+// isLeaseRecovered checks whether the Lease had expired before it was renewed.
+func isLeaseRecovered(oldLease, newLease *coordinationv1.Lease) bool {
+	if newLease.Spec.RenewTime == nil || newLease.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	if oldLease.Spec.RenewTime == nil || oldLease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expireTime := oldLease.Spec.RenewTime.Add(time.Duration(*oldLease.Spec.LeaseDurationSeconds) * time.Second)
+	return newLease.Spec.RenewTime.Time.After(expireTime)
+}
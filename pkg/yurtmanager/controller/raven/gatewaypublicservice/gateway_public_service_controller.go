@@ -34,6 +34,7 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -111,6 +112,24 @@ func add(mgr manager.Manager, cfg *appconfig.CompletedConfig, r reconcile.Reconc
 		return err
 	}
 
+	// Watch for changes to exposed services, the node ports are allocated when services are created
+	err = c.Watch(source.Kind[client.Object](mgr.GetCache(), &corev1.Service{}, handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, object client.Object) []reconcile.Request {
+			gwName := object.GetLabels()[raven.LabelCurrentGateway]
+			if gwName == "" || object.GetNamespace() != util.WorkingNamespace {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: gwName}}}
+		}), predicate.NewPredicateFuncs(
+		func(object client.Object) bool {
+			svc, ok := object.(*corev1.Service)
+			return ok && svc.Spec.Type == corev1.ServiceTypeNodePort
+		},
+	)))
+	if err != nil {
+		return err
+	}
+
 	//Watch for changes to raven agent
 	err = c.Watch(source.Kind[client.Object](mgr.GetCache(), &corev1.ConfigMap{}, &EnqueueRequestForConfigEvent{client: yurtClient.GetClientByControllerNameOrDie(mgr, names.GatewayPublicServiceController)}, predicate.NewPredicateFuncs(
 		func(object client.Object) bool {
@@ -137,6 +156,7 @@ func add(mgr manager.Manager, cfg *appconfig.CompletedConfig, r reconcile.Reconc
 // +kubebuilder:rbac:groups=core,resources=endpoints,verbs=get;create;update;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get
 // +kubebuilder:rbac:groups=raven.openyurt.io,resources=gateways,verbs=get
+// +kubebuilder:rbac:groups=raven.openyurt.io,resources=gateways/status,verbs=get;update
 
 // Reconcile reads that state of the cluster for a Gateway object and makes changes based on the state read
 // and what is in the Gateway.Spec
@@ -153,6 +173,20 @@ func (r *ReconcileService) Reconcile(ctx context.Context, req reconcile.Request)
 			return reconcile.Result{}, err
 		}
 	}
+	previous := gw.DeepCopy()
+	var requeueAfter time.Duration
+	if gw.Spec.ExposeType == ravenv1beta1.ExposeTypeStaticIPPool {
+		allocated, err := r.allocatePublicIPs(ctx, gw)
+		if err != nil {
+			err = fmt.Errorf("unable to allocate public ips: %s", err)
+			klog.Error(err.Error())
+			return reconcile.Result{Requeue: true, RequeueAfter: 2 * time.Second}, err
+		}
+		if !allocated {
+			requeueAfter = publicIPPoolRetryPeriod
+		}
+	}
+
 	svcRecord := newServiceRecord()
 	if err := r.reconcileService(ctx, gw.DeepCopy(), svcRecord, enableTunnel, enableProxy); err != nil {
 		err = fmt.Errorf("unable to reconcile service: %s", err)
@@ -165,7 +199,23 @@ func (r *ReconcileService) Reconcile(ctx context.Context, req reconcile.Request)
 		klog.Error(err.Error())
 		return reconcile.Result{Requeue: true, RequeueAfter: 2 * time.Second}, err
 	}
-	return reconcile.Result{}, nil
+
+	if gw.Spec.ExposeType == ravenv1beta1.ExposeTypeNodePort {
+		if err := r.resolveNodePortAddress(ctx, gw); err != nil {
+			err = fmt.Errorf("unable to resolve node port address: %s", err)
+			klog.Error(err.Error())
+			return reconcile.Result{Requeue: true, RequeueAfter: 2 * time.Second}, err
+		}
+	}
+
+	if util.HashObject(previous.Status.ActiveEndpoints) != util.HashObject(gw.Status.ActiveEndpoints) {
+		if err := r.Status().Update(ctx, gw); err != nil {
+			err = fmt.Errorf("unable to update public address of gateway %s: %s", gw.GetName(), err)
+			klog.Error(err.Error())
+			return reconcile.Result{Requeue: true, RequeueAfter: 2 * time.Second}, err
+		}
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *ReconcileService) getGateway(ctx context.Context, req reconcile.Request) (*ravenv1beta1.Gateway, error) {
@@ -364,7 +414,7 @@ func (r *ReconcileService) listService(ctx context.Context, gatewayName, gateway
 	}
 	newList := make([]corev1.Service, 0)
 	for _, val := range svcList.Items {
-		if val.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		if isExposedService(&val) {
 			newList = append(newList, val)
 		}
 	}
@@ -471,7 +521,7 @@ func acquiredSpecService(gateway *ravenv1beta1.Gateway, gatewayType string, prox
 	if gateway == nil {
 		return &corev1.ServiceList{Items: services}
 	}
-	if gateway.Spec.ExposeType != ravenv1beta1.ExposeTypeLoadBalancer {
+	if !util.IsExposedByService(gateway.Spec.ExposeType) {
 		return &corev1.ServiceList{Items: services}
 	}
 	for _, aep := range gateway.Status.ActiveEndpoints {
@@ -481,6 +531,9 @@ func acquiredSpecService(gateway *ravenv1beta1.Gateway, gatewayType string, prox
 		if aep.Port < 1 || aep.Port > 65535 {
 			continue
 		}
+		if gateway.Spec.ExposeType == ravenv1beta1.ExposeTypeStaticIPPool && aep.PublicIP == "" {
+			continue
+		}
 		switch aep.Type {
 		case ravenv1beta1.Proxy:
 			services = append(services, corev1.Service{
@@ -494,20 +547,7 @@ func acquiredSpecService(gateway *ravenv1beta1.Gateway, gatewayType string, prox
 					},
 					Annotations: map[string]string{"svc.openyurt.io/discard": "true"},
 				},
-				Spec: corev1.ServiceSpec{
-					Type:                  corev1.ServiceTypeLoadBalancer,
-					ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
-					Ports: []corev1.ServicePort{
-						{
-							Protocol: corev1.ProtocolTCP,
-							Port:     int32(aep.Port),
-							TargetPort: intstr.IntOrString{
-								Type:   intstr.Int,
-								IntVal: proxyPort,
-							},
-						},
-					},
-				},
+				Spec: exposedServiceSpec(gateway.Spec.ExposeType, aep, corev1.ProtocolTCP, proxyPort),
 			})
 		case ravenv1beta1.Tunnel:
 			services = append(services, corev1.Service{
@@ -521,26 +561,51 @@ func acquiredSpecService(gateway *ravenv1beta1.Gateway, gatewayType string, prox
 					},
 					Annotations: map[string]string{"svc.openyurt.io/discard": "true"},
 				},
-				Spec: corev1.ServiceSpec{
-					Type:                  corev1.ServiceTypeLoadBalancer,
-					ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
-					Ports: []corev1.ServicePort{
-						{
-							Protocol: corev1.ProtocolUDP,
-							Port:     int32(aep.Port),
-							TargetPort: intstr.IntOrString{
-								Type:   intstr.Int,
-								IntVal: tunnelPort,
-							},
-						},
-					},
-				},
+				Spec: exposedServiceSpec(gateway.Spec.ExposeType, aep, corev1.ProtocolUDP, tunnelPort),
 			})
 		}
 	}
 	return &corev1.ServiceList{Items: services}
 }
 
+// exposedServiceSpec returns the spec of service which exposes the active endpoint in the way of expose type.
+func exposedServiceSpec(exposeType string, aep *ravenv1beta1.Endpoint, protocol corev1.Protocol, targetPort int32) corev1.ServiceSpec {
+	port := corev1.ServicePort{
+		Protocol: protocol,
+		Port:     int32(aep.Port),
+		TargetPort: intstr.IntOrString{
+			Type:   intstr.Int,
+			IntVal: targetPort,
+		},
+	}
+	spec := corev1.ServiceSpec{
+		Type:                  corev1.ServiceTypeLoadBalancer,
+		ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+	}
+	switch exposeType {
+	case ravenv1beta1.ExposeTypeNodePort:
+		// keep the node port allocated before, so the public port of endpoint is stable
+		spec.Type = corev1.ServiceTypeNodePort
+		port.NodePort = int32(aep.PublicPort)
+	case ravenv1beta1.ExposeTypeStaticIPPool:
+		spec.Type = corev1.ServiceTypeClusterIP
+		spec.ExternalIPs = []string{aep.PublicIP}
+	}
+	spec.Ports = []corev1.ServicePort{port}
+	return spec
+}
+
+func isExposedService(svc *corev1.Service) bool {
+	switch svc.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeNodePort:
+		return true
+	case corev1.ServiceTypeClusterIP:
+		return len(svc.Spec.ExternalIPs) != 0
+	default:
+		return false
+	}
+}
+
 func classifyService(current, spec *corev1.ServiceList) (added, updated, deleted []*corev1.Service) {
 	added = make([]*corev1.Service, 0)
 	updated = make([]*corev1.Service, 0)
@@ -569,6 +634,7 @@ func classifyService(current, spec *corev1.ServiceList) (added, updated, deleted
 			if idx, ok := r[key]; ok {
 				updatedService := current.Items[idx].DeepCopy()
 				updatedService.Spec = *val.Spec.DeepCopy()
+				keepNodePorts(&current.Items[idx], updatedService)
 				updated = append(updated, updatedService)
 				delete(r, key)
 			} else {
@@ -584,6 +650,24 @@ func classifyService(current, spec *corev1.ServiceList) (added, updated, deleted
 	return added, updated, deleted
 }
 
+// keepNodePorts keeps the node ports allocated for the current service if they are not specified.
+func keepNodePorts(current, updated *corev1.Service) {
+	if updated.Spec.Type != corev1.ServiceTypeNodePort && updated.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return
+	}
+	for i := range updated.Spec.Ports {
+		if updated.Spec.Ports[i].NodePort != 0 {
+			continue
+		}
+		for _, port := range current.Spec.Ports {
+			if port.Protocol == updated.Spec.Ports[i].Protocol && port.Port == updated.Spec.Ports[i].Port {
+				updated.Spec.Ports[i].NodePort = port.NodePort
+				break
+			}
+		}
+	}
+}
+
 func classifyEndpoints(current, spec *corev1.EndpointsList) (added, updated, deleted []*corev1.Endpoints) {
 	added = make([]*corev1.Endpoints, 0)
 	updated = make([]*corev1.Endpoints, 0)
@@ -0,0 +1,256 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gatewaypublicservice
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/openyurtio/openyurt/pkg/apis/raven"
+	ravenv1beta1 "github.com/openyurtio/openyurt/pkg/apis/raven/v1beta1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/raven/util"
+)
+
+const (
+	PublicIPConflict      = "PublicIPConflict"
+	PublicIPPoolExhausted = "PublicIPPoolExhausted"
+	NodeExternalIPMissing = "NodeExternalIPMissing"
+
+	// publicIPPoolRetryPeriod is the period to retry allocating public ips when the pool is exhausted,
+	// because ips released by other gateways are not notified.
+	publicIPPoolRetryPeriod = 30 * time.Second
+)
+
+// publicIPUser is the object which uses a public ip, it's a gateway or a service.
+type publicIPUser struct {
+	kind string
+	name string
+}
+
+// allocatePublicIPs allocates public ips from the PublicIPPool of gateway for the active endpoints, and the
+// proxy and tunnel endpoints on the same node share the same ip. The ip allocated before is kept as long as it's
+// still in the pool and not used by services or other gateways, if two gateways use the same ip, the gateway
+// with the smaller name keeps it. It returns false if the pool is exhausted.
+func (r *ReconcileService) allocatePublicIPs(ctx context.Context, gw *ravenv1beta1.Gateway) (bool, error) {
+	users, err := r.listPublicIPUsers(ctx, gw.GetName())
+	if err != nil {
+		return false, err
+	}
+	isUsed := func(ip string) bool {
+		user, ok := users[ip]
+		return ok && !(user.kind == "gateway" && user.name > gw.GetName())
+	}
+
+	nodeIPs := make(map[string]string)
+	allocated := sets.Set[string]{}
+	for _, aep := range gw.Status.ActiveEndpoints {
+		if _, ok := nodeIPs[aep.NodeName]; ok {
+			continue
+		}
+		ip := net.ParseIP(aep.PublicIP)
+		if ip == nil || !poolContains(gw.Spec.PublicIPPool, ip) || allocated.Has(ip.String()) {
+			continue
+		}
+		if isUsed(ip.String()) {
+			user := users[ip.String()]
+			r.recorder.Event(gw, corev1.EventTypeWarning, PublicIPConflict,
+				fmt.Sprintf("The public ip %s of node %s is used by %s %s, reallocate it", ip.String(), aep.NodeName, user.kind, user.name))
+			continue
+		}
+		nodeIPs[aep.NodeName] = ip.String()
+		allocated.Insert(ip.String())
+	}
+
+	exhausted := false
+	for _, aep := range gw.Status.ActiveEndpoints {
+		ip, ok := nodeIPs[aep.NodeName]
+		if !ok {
+			ip = nextFreeIP(gw.Spec.PublicIPPool, func(ip string) bool {
+				return allocated.Has(ip) || isUsed(ip)
+			})
+			if len(ip) == 0 {
+				exhausted = true
+			} else {
+				allocated.Insert(ip)
+			}
+			nodeIPs[aep.NodeName] = ip
+		}
+		aep.PublicIP = ip
+		aep.PublicPort = 0
+		if len(ip) != 0 {
+			aep.PublicPort = aep.Port
+		}
+	}
+	if exhausted {
+		r.recorder.Event(gw, corev1.EventTypeWarning, PublicIPPoolExhausted,
+			fmt.Sprintf("The public ip pool of gateway %s is exhausted, some active endpoints are not exposed", gw.GetName()))
+	}
+	return !exhausted, nil
+}
+
+// listPublicIPUsers returns the public ips which are used by other gateways, and external ips or load balancer
+// ips of services which are not managed for the gateway.
+func (r *ReconcileService) listPublicIPUsers(ctx context.Context, gatewayName string) (map[string]publicIPUser, error) {
+	users := make(map[string]publicIPUser)
+	var gwList ravenv1beta1.GatewayList
+	if err := r.List(ctx, &gwList); err != nil {
+		return nil, fmt.Errorf("could not list gateways, error %s", err.Error())
+	}
+	for i := range gwList.Items {
+		gw := &gwList.Items[i]
+		if gw.GetName() == gatewayName {
+			continue
+		}
+		addresses := make([]string, 0)
+		for _, ep := range gw.Spec.Endpoints {
+			addresses = append(addresses, ep.PublicIP)
+		}
+		for _, aep := range gw.Status.ActiveEndpoints {
+			addresses = append(addresses, aep.PublicIP)
+		}
+		for _, address := range addresses {
+			if ip := net.ParseIP(address); ip != nil {
+				users[ip.String()] = publicIPUser{kind: "gateway", name: gw.GetName()}
+			}
+		}
+	}
+
+	var svcList corev1.ServiceList
+	if err := r.List(ctx, &svcList); err != nil {
+		return nil, fmt.Errorf("could not list services, error %s", err.Error())
+	}
+	for i := range svcList.Items {
+		svc := &svcList.Items[i]
+		if svc.Labels[raven.LabelCurrentGateway] == gatewayName {
+			continue
+		}
+		addresses := append([]string{}, svc.Spec.ExternalIPs...)
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			addresses = append(addresses, ingress.IP)
+		}
+		for _, address := range addresses {
+			if ip := net.ParseIP(address); ip != nil {
+				users[ip.String()] = publicIPUser{kind: "service", name: fmt.Sprintf("%s/%s", svc.GetNamespace(), svc.GetName())}
+			}
+		}
+	}
+	return users, nil
+}
+
+// resolveNodePortAddress sets the external ip of node and the node port of service as the public address
+// of active endpoints.
+func (r *ReconcileService) resolveNodePortAddress(ctx context.Context, gw *ravenv1beta1.Gateway) error {
+	nodePorts := make(map[string]int32)
+	for _, gatewayType := range []string{ravenv1beta1.Proxy, ravenv1beta1.Tunnel} {
+		svcList, err := r.listService(ctx, gw.GetName(), gatewayType)
+		if err != nil {
+			return fmt.Errorf("failed list service for gateway %s type %s , error %s", gw.GetName(), gatewayType, err.Error())
+		}
+		for _, svc := range svcList.Items {
+			epName := svc.Labels[util.LabelCurrentGatewayEndpoints]
+			if svc.Spec.Type != corev1.ServiceTypeNodePort || epName == "" || len(svc.Spec.Ports) == 0 {
+				continue
+			}
+			nodePorts[formatKey(epName, gatewayType)] = svc.Spec.Ports[0].NodePort
+		}
+	}
+
+	for _, aep := range gw.Status.ActiveEndpoints {
+		var node corev1.Node
+		if err := r.Get(ctx, types.NamespacedName{Name: aep.NodeName}, &node); err != nil {
+			klog.Error(Format("could not get node %s for get public address, error %s", aep.NodeName, err.Error()))
+			continue
+		}
+		aep.PublicIP = util.GetNodeExternalIP(node)
+		if len(aep.PublicIP) == 0 {
+			r.recorder.Event(gw, corev1.EventTypeWarning, NodeExternalIPMissing,
+				fmt.Sprintf("The node %s hosting the active endpoint has no external ip, it can't be exposed by node port", aep.NodeName))
+		}
+		// the node port is allocated when the service is created, keep the current one until the service is observed
+		if nodePort, ok := nodePorts[formatKey(aep.NodeName, aep.Type)]; ok {
+			aep.PublicPort = int(nodePort)
+		}
+	}
+	return nil
+}
+
+// poolContains checks if the ip is in the pool of public ips.
+func poolContains(pool []string, ip net.IP) bool {
+	for _, item := range pool {
+		if poolIP := net.ParseIP(item); poolIP != nil {
+			if poolIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		if _, cidr, err := net.ParseCIDR(item); err == nil && cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextFreeIP returns the first ip in the pool which is not used, the network and broadcast addresses of IPv4 CIDRs
+// are skipped. It returns empty string if the pool is exhausted.
+func nextFreeIP(pool []string, isUsed func(ip string) bool) string {
+	for _, item := range pool {
+		if ip := net.ParseIP(item); ip != nil {
+			if !isUsed(ip.String()) {
+				return ip.String()
+			}
+			continue
+		}
+		_, cidr, err := net.ParseCIDR(item)
+		if err != nil {
+			continue
+		}
+		ones, bits := cidr.Mask.Size()
+		skipEdges := bits == 32 && bits-ones > 1
+		broadcast := make(net.IP, len(cidr.IP))
+		for i := range cidr.IP {
+			broadcast[i] = cidr.IP[i] | ^cidr.Mask[i]
+		}
+		for ip := cidr.IP; cidr.Contains(ip); ip = nextIP(ip) {
+			if skipEdges && (ip.Equal(cidr.IP) || ip.Equal(broadcast)) {
+				continue
+			}
+			if !isUsed(ip.String()) {
+				return ip.String()
+			}
+		}
+	}
+	return ""
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
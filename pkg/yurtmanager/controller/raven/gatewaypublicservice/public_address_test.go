@@ -0,0 +1,223 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gatewaypublicservice
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openyurtio/openyurt/pkg/apis"
+	ravenv1beta1 "github.com/openyurtio/openyurt/pkg/apis/raven/v1beta1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/raven/util"
+)
+
+func newExposedGateway(name, exposeType string, pool []string, activeEndpoints ...*ravenv1beta1.Endpoint) *ravenv1beta1.Gateway {
+	return &ravenv1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: ravenv1beta1.GatewaySpec{
+			ExposeType:   exposeType,
+			PublicIPPool: pool,
+		},
+		Status: ravenv1beta1.GatewayStatus{ActiveEndpoints: activeEndpoints},
+	}
+}
+
+func newPublicAddressReconciler(objs ...client.Object) *ReconcileService {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	apis.AddToScheme(scheme)
+
+	objs = append(objs, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: util.RavenGlobalConfig, Namespace: util.WorkingNamespace},
+		Data:       map[string]string{util.RavenEnableTunnel: "true", util.RavenEnableProxy: "true"},
+	})
+	return &ReconcileService{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(&ravenv1beta1.Gateway{}).Build(),
+		recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func TestReconcileService_StaticIPPool(t *testing.T) {
+	testcases := map[string]struct {
+		pool           []string
+		publicIP       string
+		objs           []client.Object
+		wantedIP       string
+		wantedRequeued bool
+	}{
+		"allocate the first available ip of cidr": {
+			pool:     []string{"10.0.0.0/30"},
+			wantedIP: "10.0.0.1",
+		},
+		"keep the allocated ip": {
+			pool:     []string{"10.0.0.0/30"},
+			publicIP: "10.0.0.2",
+			wantedIP: "10.0.0.2",
+		},
+		"reallocate the ip which is not in pool": {
+			pool:     []string{"10.0.0.5"},
+			publicIP: "10.0.0.2",
+			wantedIP: "10.0.0.5",
+		},
+		"skip the ip used by service": {
+			pool: []string{"10.0.0.0/30"},
+			objs: []client.Object{
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+					Spec:       corev1.ServiceSpec{ExternalIPs: []string{"10.0.0.1"}},
+				},
+			},
+			wantedIP: "10.0.0.2",
+		},
+		"reallocate the ip used by gateway with smaller name": {
+			pool:     []string{"10.0.0.1", "10.0.0.2"},
+			publicIP: "10.0.0.1",
+			objs: []client.Object{
+				newExposedGateway("gw-a", ravenv1beta1.ExposeTypeStaticIPPool, []string{"10.0.0.1"},
+					&ravenv1beta1.Endpoint{NodeName: Node3Name, Type: ravenv1beta1.Tunnel, PublicIP: "10.0.0.1"}),
+			},
+			wantedIP: "10.0.0.2",
+		},
+		"keep the ip used by gateway with larger name": {
+			pool:     []string{"10.0.0.1", "10.0.0.2"},
+			publicIP: "10.0.0.1",
+			objs: []client.Object{
+				newExposedGateway("gw-z", ravenv1beta1.ExposeTypeStaticIPPool, []string{"10.0.0.1"},
+					&ravenv1beta1.Endpoint{NodeName: Node3Name, Type: ravenv1beta1.Tunnel, PublicIP: "10.0.0.1"}),
+			},
+			wantedIP: "10.0.0.1",
+		},
+		"pool is exhausted": {
+			pool: []string{"10.0.0.1"},
+			objs: []client.Object{
+				newExposedGateway("gw-a", ravenv1beta1.ExposeTypeStaticIPPool, []string{"10.0.0.1"},
+					&ravenv1beta1.Endpoint{NodeName: Node3Name, Type: ravenv1beta1.Tunnel, PublicIP: "10.0.0.1"}),
+			},
+			wantedRequeued: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			gw := newExposedGateway(MockGateway, ravenv1beta1.ExposeTypeStaticIPPool, tc.pool,
+				&ravenv1beta1.Endpoint{NodeName: Node1Name, Type: ravenv1beta1.Tunnel, Port: 4500, PublicIP: tc.publicIP},
+				&ravenv1beta1.Endpoint{NodeName: Node1Name, Type: ravenv1beta1.Proxy, Port: 10262, PublicIP: tc.publicIP})
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: Node1Name}}
+			r := newPublicAddressReconciler(append(tc.objs, gw, node)...)
+
+			result, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: MockGateway}})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if requeued := result.RequeueAfter > 0; requeued != tc.wantedRequeued {
+				t.Errorf("expect requeued %v, but got %v", tc.wantedRequeued, result.RequeueAfter)
+			}
+
+			var current ravenv1beta1.Gateway
+			if err := r.Get(context.TODO(), types.NamespacedName{Name: MockGateway}, &current); err != nil {
+				t.Fatalf("could not get gateway, %v", err)
+			}
+			for _, aep := range current.Status.ActiveEndpoints {
+				if aep.PublicIP != tc.wantedIP {
+					t.Errorf("expect public ip %q for %s endpoint, but got %q", tc.wantedIP, aep.Type, aep.PublicIP)
+				}
+				if len(tc.wantedIP) != 0 && aep.PublicPort != aep.Port {
+					t.Errorf("expect public port %d for %s endpoint, but got %d", aep.Port, aep.Type, aep.PublicPort)
+				}
+			}
+
+			svcList, err := r.listService(context.TODO(), MockGateway, ravenv1beta1.Tunnel)
+			if err != nil {
+				t.Fatalf("could not list services, %v", err)
+			}
+			if len(tc.wantedIP) == 0 {
+				if len(svcList.Items) != 0 {
+					t.Errorf("expect no services, but got %d", len(svcList.Items))
+				}
+				return
+			}
+			if len(svcList.Items) != 1 || len(svcList.Items[0].Spec.ExternalIPs) != 1 || svcList.Items[0].Spec.ExternalIPs[0] != tc.wantedIP {
+				t.Errorf("expect service with external ip %s, but got %v", tc.wantedIP, svcList.Items)
+			}
+		})
+	}
+}
+
+func TestReconcileService_NodePort(t *testing.T) {
+	gw := newExposedGateway(MockGateway, ravenv1beta1.ExposeTypeNodePort, nil,
+		&ravenv1beta1.Endpoint{NodeName: Node1Name, Type: ravenv1beta1.Tunnel, Port: 4500})
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: Node1Name},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: Node1Address},
+				{Type: corev1.NodeExternalIP, Address: "47.0.0.1"},
+			},
+		},
+	}
+	r := newPublicAddressReconciler(gw, node)
+	ctx := context.TODO()
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: MockGateway}}
+
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	svcList, err := r.listService(ctx, MockGateway, ravenv1beta1.Tunnel)
+	if err != nil {
+		t.Fatalf("could not list services, %v", err)
+	}
+	if len(svcList.Items) != 1 || svcList.Items[0].Spec.Type != corev1.ServiceTypeNodePort {
+		t.Fatalf("expect a NodePort service, but got %v", svcList.Items)
+	}
+
+	// node port is allocated by kube-apiserver
+	svc := svcList.Items[0].DeepCopy()
+	svc.Spec.Ports[0].NodePort = 30500
+	if err := r.Update(ctx, svc); err != nil {
+		t.Fatalf("could not update service, %v", err)
+	}
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	var current ravenv1beta1.Gateway
+	if err := r.Get(ctx, types.NamespacedName{Name: MockGateway}, &current); err != nil {
+		t.Fatalf("could not get gateway, %v", err)
+	}
+	aep := current.Status.ActiveEndpoints[0]
+	if aep.PublicIP != "47.0.0.1" || aep.PublicPort != 30500 {
+		t.Errorf("expect public address 47.0.0.1:30500, but got %s:%d", aep.PublicIP, aep.PublicPort)
+	}
+
+	// the node port is kept in the service
+	if svcList, err = r.listService(ctx, MockGateway, ravenv1beta1.Tunnel); err != nil {
+		t.Fatalf("could not list services, %v", err)
+	}
+	if nodePort := svcList.Items[0].Spec.Ports[0].NodePort; nodePort != 30500 {
+		t.Errorf("expect node port 30500, but got %d", nodePort)
+	}
+}
@@ -25,6 +25,10 @@ const (
 	GatewayProxyServiceNamePrefix  = "x-raven-proxy-svc"
 	GatewayTunnelServiceNamePrefix = "x-raven-tunnel-svc"
 	ExtraAllowedSourceCIDRs        = "raven.openyurt.io/extra-allowed-source-cidrs"
+	// EndpointHealthLeasePrefix is the name prefix of Leases in WorkingNamespace which are renewed by raven agent
+	// as long as the gateway endpoint passes health probing, the full name is <prefix>-<endpoint type>-<node name>
+	// and the Lease is labeled with raven.openyurt.io/gateway=<gateway name>.
+	EndpointHealthLeasePrefix = "raven-endpoint-health"
 
 	RavenProxyNodesConfig      = "edge-tunnel-nodes"
 	ProxyNodesKey              = "tunnel-nodes"
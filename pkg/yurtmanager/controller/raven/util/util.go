@@ -33,6 +33,8 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ravenv1beta1 "github.com/openyurtio/openyurt/pkg/apis/raven/v1beta1"
 )
 
 // GetNodeInternalIP returns internal ip of the given `node`.
@@ -47,6 +49,35 @@ func GetNodeInternalIP(node corev1.Node) string {
 	return ip
 }
 
+// GetNodeExternalIP returns external ip of the given `node`.
+func GetNodeExternalIP(node corev1.Node) string {
+	var ip string
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == corev1.NodeExternalIP && net.ParseIP(addr.Address) != nil {
+			ip = addr.Address
+			break
+		}
+	}
+	return ip
+}
+
+// IsExposedByService checks if the gateway with the expose type is exposed by services which are managed
+// by gatewaypublicservice controller.
+func IsExposedByService(exposeType string) bool {
+	switch exposeType {
+	case ravenv1beta1.ExposeTypeLoadBalancer, ravenv1beta1.ExposeTypeNodePort, ravenv1beta1.ExposeTypeStaticIPPool:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsPublicAddressAllocated checks if the public ip and port of active endpoints are allocated by
+// gatewaypublicservice controller for the gateway with the expose type.
+func IsPublicAddressAllocated(exposeType string) bool {
+	return exposeType == ravenv1beta1.ExposeTypeNodePort || exposeType == ravenv1beta1.ExposeTypeStaticIPPool
+}
+
 // AddGatewayToWorkQueue adds the Gateway the reconciler's workqueue
 func AddGatewayToWorkQueue(gwName string,
 	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
@@ -112,3 +143,8 @@ func computeHash(target string) string {
 func FormatName(name string) string {
 	return strings.Join([]string{name, fmt.Sprintf("%08x", rand.Uint32())}, "-")
 }
+
+// EndpointHealthLeaseName returns the name of the Lease which reports the health of gateway endpoint.
+func EndpointHealthLeaseName(endpointType, nodeName string) string {
+	return strings.Join([]string{EndpointHealthLeasePrefix, endpointType, nodeName}, "-")
+}
@@ -20,13 +20,19 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	"github.com/openyurtio/openyurt/pkg/yurthub/filter/servicetopology"
 	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/servicetopology/adapter"
 	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/servicetopology/util"
 )
@@ -84,3 +90,190 @@ func (e *EnqueueEndpointsliceForService) enqueueEndpointsliceForSvc(newSvc *core
 		})
 	}
 }
+
+// EnqueueSiblingsForEndpointslice enqueues the other endpointslices of a service when the aggregate readiness
+// of a topology zone is changed by the ready endpoints of an endpointslice. The servicetopology filter of yurthub
+// decides whether to fall back for all endpointslices of the service, so the other endpointslices should be
+// filtered again only when a zone gains its first ready endpoint or loses its last one.
+type EnqueueSiblingsForEndpointslice struct {
+	reader               client.Reader
+	endpointsliceAdapter adapter.Adapter
+}
+
+// Create implements EventHandler
+func (e *EnqueueSiblingsForEndpointslice) Create(ctx context.Context, evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	// endpoints of a new endpointslice usually become ready later, and the siblings
+	// will be enqueued by the update event.
+}
+
+// Update implements EventHandler
+func (e *EnqueueSiblingsForEndpointslice) Update(ctx context.Context, evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	oldReady, newReady := readyEndpointNodes(evt.ObjectOld), readyEndpointNodes(evt.ObjectNew)
+	if oldReady.Equal(newReady) {
+		return
+	}
+	e.enqueueSiblings(ctx, evt.ObjectNew, oldReady, newReady, q)
+}
+
+// Delete implements EventHandler
+func (e *EnqueueSiblingsForEndpointslice) Delete(ctx context.Context, evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	oldReady := readyEndpointNodes(evt.Object)
+	if oldReady.Len() == 0 {
+		return
+	}
+	e.enqueueSiblings(ctx, evt.Object, oldReady, sets.New[string](), q)
+}
+
+// Generic implements EventHandler
+func (e *EnqueueSiblingsForEndpointslice) Generic(ctx context.Context, evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (e *EnqueueSiblingsForEndpointslice) enqueueSiblings(ctx context.Context, obj client.Object, oldReady, newReady sets.Set[string],
+	q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	svcName := obj.GetLabels()[discoveryv1.LabelServiceName]
+	if len(svcName) == 0 {
+		return
+	}
+
+	svc := &corev1.Service{}
+	if err := e.reader.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: svcName}, svc); err != nil {
+		klog.V(4).Info(Format("could not get svc %s/%s, %v", obj.GetNamespace(), svcName, err))
+		return
+	}
+	if len(svc.Annotations[servicetopology.AnnotationServiceTopologyKey]) == 0 ||
+		len(svc.Annotations[servicetopology.AnnotationServiceTopologyFallback]) == 0 {
+		return
+	}
+
+	siblingsReady, err := e.siblingsReadyNodes(ctx, obj, svcName)
+	if err != nil {
+		klog.V(4).Info(Format("could not list endpointslices of svc %s/%s, %v", obj.GetNamespace(), svcName, err))
+		return
+	}
+	if !e.zoneReadinessChanged(ctx, svc, oldReady.Union(siblingsReady), newReady.Union(siblingsReady)) {
+		return
+	}
+
+	for _, key := range e.endpointsliceAdapter.GetEnqueueKeysBySvc(svc) {
+		ns, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			klog.Errorf("could not split key %s, %v", key, err)
+			continue
+		}
+		if name == obj.GetName() {
+			continue
+		}
+		q.Add(reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: ns, Name: name},
+		})
+	}
+}
+
+// siblingsReadyNodes returns the nodes of ready endpoints in the other endpointslices of the service
+func (e *EnqueueSiblingsForEndpointslice) siblingsReadyNodes(ctx context.Context, obj client.Object, svcName string) (sets.Set[string], error) {
+	opts := []client.ListOption{
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingLabels{discoveryv1.LabelServiceName: svcName},
+	}
+	nodes := sets.New[string]()
+	switch obj.(type) {
+	case *discoveryv1.EndpointSlice:
+		list := &discoveryv1.EndpointSliceList{}
+		if err := e.reader.List(ctx, list, opts...); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			if list.Items[i].Name != obj.GetName() {
+				nodes = nodes.Union(readyEndpointNodes(&list.Items[i]))
+			}
+		}
+	case *discoveryv1beta1.EndpointSlice:
+		list := &discoveryv1beta1.EndpointSliceList{}
+		if err := e.reader.List(ctx, list, opts...); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			if list.Items[i].Name != obj.GetName() {
+				nodes = nodes.Union(readyEndpointNodes(&list.Items[i]))
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// zoneReadinessChanged checks whether a topology zone of the service gains its first ready endpoint or loses
+// its last one. The zone of a node is the node itself if the service uses node topology in its topology key
+// or fallback scopes, otherwise it is the nodepool of the node.
+func (e *EnqueueSiblingsForEndpointslice) zoneReadinessChanged(ctx context.Context, svc *corev1.Service, oldReady, newReady sets.Set[string]) bool {
+	changed := oldReady.Difference(newReady).Union(newReady.Difference(oldReady))
+	if changed.Len() == 0 {
+		return false
+	}
+	if usesNodeTopology(svc) {
+		return true
+	}
+
+	zones := make(map[string]string)
+	zoneOf := func(nodeName string) string {
+		if zone, ok := zones[nodeName]; ok {
+			return zone
+		}
+		zone := nodeName
+		node := &corev1.Node{}
+		if err := e.reader.Get(ctx, types.NamespacedName{Name: nodeName}, node); err == nil &&
+			len(node.Labels[projectinfo.GetNodePoolLabel()]) != 0 {
+			zone = node.Labels[projectinfo.GetNodePoolLabel()]
+		}
+		zones[nodeName] = zone
+		return zone
+	}
+	readyZones := func(nodes sets.Set[string]) sets.Set[string] {
+		result := sets.New[string]()
+		for nodeName := range nodes {
+			result.Insert(zoneOf(nodeName))
+		}
+		return result
+	}
+	return !readyZones(oldReady).Equal(readyZones(newReady))
+}
+
+// usesNodeTopology checks whether the service uses node topology in its topology key or fallback scopes
+func usesNodeTopology(svc *corev1.Service) bool {
+	if svc.Annotations[servicetopology.AnnotationServiceTopologyKey] == servicetopology.AnnotationServiceTopologyValueNode {
+		return true
+	}
+	for _, group := range servicetopology.ParseFallbackScopes(svc.Annotations[servicetopology.AnnotationServiceTopologyFallback]) {
+		for _, scope := range group {
+			if scope.Scope == servicetopology.AnnotationServiceTopologyValueNode {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// readyEndpointNodes returns the nodes of ready endpoints in the endpointslice
+func readyEndpointNodes(obj client.Object) sets.Set[string] {
+	nodes := sets.New[string]()
+	switch v := obj.(type) {
+	case *discoveryv1.EndpointSlice:
+		for i := range v.Endpoints {
+			ready := v.Endpoints[i].Conditions.Ready == nil || *v.Endpoints[i].Conditions.Ready
+			if ready && v.Endpoints[i].NodeName != nil {
+				nodes.Insert(*v.Endpoints[i].NodeName)
+			}
+		}
+	case *discoveryv1beta1.EndpointSlice:
+		for i := range v.Endpoints {
+			ready := v.Endpoints[i].Conditions.Ready == nil || *v.Endpoints[i].Conditions.Ready
+			if ready && len(v.Endpoints[i].Topology[corev1.LabelHostname]) != 0 {
+				nodes.Insert(v.Endpoints[i].Topology[corev1.LabelHostname])
+			}
+		}
+	}
+	return nodes
+}
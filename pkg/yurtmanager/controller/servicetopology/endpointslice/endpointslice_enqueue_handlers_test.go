@@ -0,0 +1,163 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpointslice
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	"github.com/openyurtio/openyurt/pkg/yurthub/filter/servicetopology"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/servicetopology/adapter"
+)
+
+func newEndpointSlice(name, nodeName string, ready bool) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "svc1"},
+		},
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses:  []string{"10.244.1.2"},
+				NodeName:   ptr.To(nodeName),
+				Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(ready)},
+			},
+		},
+	}
+}
+
+func newNode(name, poolName string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{projectinfo.GetNodePoolLabel(): poolName},
+		},
+	}
+}
+
+func TestEnqueueSiblingsForEndpointslice(t *testing.T) {
+	nodePoolTopology := map[string]string{
+		servicetopology.AnnotationServiceTopologyKey:      servicetopology.AnnotationServiceTopologyValueNodePool,
+		servicetopology.AnnotationServiceTopologyFallback: servicetopology.AnnotationServiceTopologyValueCloud,
+	}
+	testcases := map[string]struct {
+		annotations map[string]string
+		siblingNode string
+		oldReady    bool
+		newReady    bool
+		wantedNum   int
+	}{
+		"the last ready endpoint of nodepool becomes not ready": {
+			annotations: nodePoolTopology,
+			siblingNode: "node3",
+			oldReady:    true,
+			newReady:    false,
+			wantedNum:   1,
+		},
+		"the first ready endpoint of nodepool becomes ready": {
+			annotations: nodePoolTopology,
+			siblingNode: "node3",
+			oldReady:    false,
+			newReady:    true,
+			wantedNum:   1,
+		},
+		"nodepool still has ready endpoints on other node": {
+			annotations: nodePoolTopology,
+			siblingNode: "node2",
+			oldReady:    true,
+			newReady:    false,
+			wantedNum:   0,
+		},
+		"node still has ready endpoints in sibling": {
+			annotations: nodePoolTopology,
+			siblingNode: "node1",
+			oldReady:    true,
+			newReady:    false,
+			wantedNum:   0,
+		},
+		"node topology with ready endpoints on other node of nodepool": {
+			annotations: map[string]string{
+				servicetopology.AnnotationServiceTopologyKey:      servicetopology.AnnotationServiceTopologyValueNode,
+				servicetopology.AnnotationServiceTopologyFallback: servicetopology.AnnotationServiceTopologyValueNodePool,
+			},
+			siblingNode: "node2",
+			oldReady:    true,
+			newReady:    false,
+			wantedNum:   1,
+		},
+		"ready endpoints not changed": {
+			annotations: nodePoolTopology,
+			siblingNode: "node3",
+			oldReady:    true,
+			newReady:    true,
+			wantedNum:   0,
+		},
+		"service without fallback topology": {
+			annotations: map[string]string{
+				servicetopology.AnnotationServiceTopologyKey: servicetopology.AnnotationServiceTopologyValueNodePool,
+			},
+			siblingNode: "node3",
+			oldReady:    true,
+			newReady:    false,
+			wantedNum:   0,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "svc1",
+					Namespace:   metav1.NamespaceDefault,
+					Annotations: tc.annotations,
+				},
+			}
+			c := fakeclient.NewClientBuilder().
+				WithObjects(svc, newNode("node1", "pool1"), newNode("node2", "pool1"), newNode("node3", "pool2"),
+					newEndpointSlice("svc1-a", "node1", tc.newReady), newEndpointSlice("svc1-b", tc.siblingNode, true)).Build()
+			handler := &EnqueueSiblingsForEndpointslice{
+				reader:               c,
+				endpointsliceAdapter: adapter.NewEndpointsV1Adapter(c),
+			}
+			q := workqueue.NewTypedRateLimitingQueue[reconcile.Request](workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			handler.Update(context.Background(), event.UpdateEvent{
+				ObjectOld: newEndpointSlice("svc1-a", "node1", tc.oldReady),
+				ObjectNew: newEndpointSlice("svc1-a", "node1", tc.newReady),
+			}, q)
+
+			if q.Len() != tc.wantedNum {
+				t.Fatalf("expect %d endpointslices enqueued, but got %d", tc.wantedNum, q.Len())
+			}
+			if q.Len() != 0 {
+				if req, _ := q.Get(); req.Name != "svc1-b" {
+					t.Errorf("expect sibling endpointslice svc1-b enqueued, but got %s", req.Name)
+				}
+			}
+		})
+	}
+}
@@ -63,6 +63,18 @@ func Add(ctx context.Context, cfg *appconfig.CompletedConfig, mgr manager.Manage
 		return err
 	}
 
+	// Watch for changes of ready endpoints in EndpointSlice
+	var endpointSlice client.Object = &discoveryv1beta1.EndpointSlice{}
+	if r.isSupportEndpointslicev1 {
+		endpointSlice = &discoveryv1.EndpointSlice{}
+	}
+	if err := c.Watch(source.Kind[client.Object](mgr.GetCache(), endpointSlice, &EnqueueSiblingsForEndpointslice{
+		reader:               r.Client,
+		endpointsliceAdapter: r.endpointsliceAdapter,
+	})); err != nil {
+		return err
+	}
+
 	klog.Infof("%s controller is added", names.ServiceTopologyEndpointSliceController)
 	return nil
 }
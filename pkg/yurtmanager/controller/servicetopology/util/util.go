@@ -22,11 +22,18 @@ import (
 	"github.com/openyurtio/openyurt/pkg/yurthub/filter/servicetopology"
 )
 
+// ServiceTopologyTypeChanged checks whether the annotations that affect how endpoints are filtered by
+// servicetopology filter of yurthub are changed, including the topology, fallback topologies and topology mode.
 func ServiceTopologyTypeChanged(oldSvc, newSvc *corev1.Service) bool {
-	oldType := oldSvc.Annotations[servicetopology.AnnotationServiceTopologyKey]
-	newType := newSvc.Annotations[servicetopology.AnnotationServiceTopologyKey]
-	if oldType == newType {
-		return false
+	for _, key := range []string{
+		servicetopology.AnnotationServiceTopologyKey,
+		servicetopology.AnnotationServiceTopologyFallback,
+		corev1.AnnotationTopologyMode,
+		corev1.DeprecatedAnnotationTopologyAwareHints,
+	} {
+		if oldSvc.Annotations[key] != newSvc.Annotations[key] {
+			return true
+		}
 	}
-	return true
+	return false
 }
@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openyurtio/openyurt/pkg/yurthub/filter/servicetopology"
+)
+
+func TestServiceTopologyTypeChanged(t *testing.T) {
+	newSvc := func(annotations map[string]string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc1", Annotations: annotations}}
+	}
+
+	testcases := map[string]struct {
+		oldSvc  *corev1.Service
+		newSvc  *corev1.Service
+		changed bool
+	}{
+		"topology is not changed": {
+			oldSvc: newSvc(map[string]string{servicetopology.AnnotationServiceTopologyKey: servicetopology.AnnotationServiceTopologyValueNodePool}),
+			newSvc: newSvc(map[string]string{servicetopology.AnnotationServiceTopologyKey: servicetopology.AnnotationServiceTopologyValueNodePool, "foo": "bar"}),
+		},
+		"topology is changed": {
+			oldSvc:  newSvc(nil),
+			newSvc:  newSvc(map[string]string{servicetopology.AnnotationServiceTopologyKey: servicetopology.AnnotationServiceTopologyValueNodePool}),
+			changed: true,
+		},
+		"fallback topology is changed": {
+			oldSvc:  newSvc(map[string]string{servicetopology.AnnotationServiceTopologyKey: servicetopology.AnnotationServiceTopologyValueNodePool}),
+			newSvc:  newSvc(map[string]string{servicetopology.AnnotationServiceTopologyKey: servicetopology.AnnotationServiceTopologyValueNodePool, servicetopology.AnnotationServiceTopologyFallback: servicetopology.AnnotationServiceTopologyValueCloud}),
+			changed: true,
+		},
+		"topology mode is changed": {
+			oldSvc:  newSvc(nil),
+			newSvc:  newSvc(map[string]string{corev1.AnnotationTopologyMode: "Auto"}),
+			changed: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			if changed := ServiceTopologyTypeChanged(tc.oldSvc, tc.newSvc); changed != tc.changed {
+				t.Errorf("expect changed %v, but got %v", tc.changed, changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenancewindow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+)
+
+const (
+	// PodUpgradeScheduled indicates that the upgrade of the pod is deferred to the next maintenance window.
+	PodUpgradeScheduled corev1.PodConditionType = "PodUpgradeScheduled"
+
+	// WaitingForMaintenanceWindowReason is the reason of condition PodUpgradeScheduled when
+	// the upgrade is waiting for the next maintenance window.
+	WaitingForMaintenanceWindowReason = "WaitingForMaintenanceWindow"
+
+	// PodUpgradePostponed indicates that the upgrade of the pod is postponed by the OTA API of YurtHub,
+	// the pod is not upgraded in both OTA and AdvancedRollingUpdate mode until the postponement expires.
+	PodUpgradePostponed corev1.PodConditionType = "PodUpgradePostponed"
+
+	// PostponedByUserReason is the reason of condition PodUpgradePostponed when the upgrade is postponed.
+	PostponedByUserReason = "PostponedByUser"
+
+	postponedMessagePrefix = "upgrade is postponed until "
+)
+
+// Policy is the value of annotation "apps.openyurt.io/maintenance-window", for example:
+// {"schedules":["0 1 * * *"],"duration":"4h","timeZone":"Asia/Shanghai"}
+// means that pods are only allowed to be upgraded between 01:00 and 05:00 in Asia/Shanghai.
+type Policy struct {
+	// Schedules are the standard cron expressions at which the maintenance window opens.
+	Schedules []string `json:"schedules"`
+	// Duration is how long the maintenance window keeps open, e.g. "4h".
+	Duration string `json:"duration"`
+	// TimeZone is the IANA time zone of schedules, UTC is used if it's not specified.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// Window is a parsed maintenance window
+type Window struct {
+	schedules []cron.Schedule
+	duration  time.Duration
+	location  *time.Location
+}
+
+// Parse parses the value of annotation "apps.openyurt.io/maintenance-window" into a Window
+func Parse(value string) (*Window, error) {
+	var policy Policy
+	if err := json.Unmarshal([]byte(value), &policy); err != nil {
+		return nil, fmt.Errorf("could not unmarshal maintenance window %q, %v", value, err)
+	}
+	if len(policy.Schedules) == 0 {
+		return nil, fmt.Errorf("schedules of maintenance window %q are empty", value)
+	}
+
+	duration, err := time.ParseDuration(policy.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration of maintenance window %q, %v", value, err)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("duration of maintenance window %q should be positive", value)
+	}
+
+	location := time.UTC
+	if len(policy.TimeZone) != 0 {
+		if location, err = time.LoadLocation(policy.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone of maintenance window %q, %v", value, err)
+		}
+	}
+
+	w := &Window{duration: duration, location: location}
+	for _, s := range policy.Schedules {
+		schedule, err := cron.ParseStandard(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q of maintenance window, %v", s, err)
+		}
+		w.schedules = append(w.schedules, schedule)
+	}
+	return w, nil
+}
+
+// IsOpen returns true if now is within the maintenance window
+func (w *Window) IsOpen(now time.Time) bool {
+	// the window is open if it was opened by any schedule during the last duration
+	start := now.In(w.location).Add(-w.duration)
+	for _, s := range w.schedules {
+		if !s.Next(start).After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen returns the time when the maintenance window opens next time, now is returned if it's open.
+func (w *Window) NextOpen(now time.Time) time.Time {
+	if w.IsOpen(now) {
+		return now
+	}
+
+	var next time.Time
+	for _, s := range w.schedules {
+		if t := s.Next(now.In(w.location)); next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	return next
+}
+
+// Location returns the time zone of the maintenance window
+func (w *Window) Location() *time.Location {
+	return w.location
+}
+
+// ForNode returns the maintenance windows which constrain upgrading pods of the workload on the node.
+// Windows can be declared by annotation on the workload and the NodePool of the node.
+func ForNode(ctx context.Context, c client.Client, workload metav1.Object, node *corev1.Node) ([]*Window, error) {
+	var windows []*Window
+	if v, ok := workload.GetAnnotations()[apps.AnnotationMaintenanceWindow]; ok {
+		w, err := Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+
+	poolName := node.Labels[projectinfo.GetNodePoolLabel()]
+	if len(poolName) == 0 {
+		return windows, nil
+	}
+	pool := &appsv1beta2.NodePool{}
+	if err := c.Get(ctx, types.NamespacedName{Name: poolName}, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return windows, nil
+		}
+		return nil, err
+	}
+	if v, ok := pool.Annotations[apps.AnnotationMaintenanceWindow]; ok {
+		w, err := Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("nodepool %s: %v", poolName, err)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// maxOverlapSearches bounds the search for the time when all maintenance windows are open, windows
+// which never overlap would be searched forever otherwise.
+const maxOverlapSearches = 1000
+
+// Evaluate returns true if all maintenance windows are open. If not, the first time at which all windows
+// are open is returned, it's in the time zone of the window which opens last. The search moves to the next
+// opening of a closed window until all windows are open at the same time, if windows never overlap, the last
+// time searched is returned as a lower bound.
+func Evaluate(windows []*Window, now time.Time) (bool, time.Time) {
+	next := now
+	for i := 0; i < maxOverlapSearches; i++ {
+		open := true
+		latest := next
+		for _, w := range windows {
+			if w.IsOpen(next) {
+				continue
+			}
+			open = false
+			if t := w.NextOpen(next); !t.Before(latest) {
+				latest = t
+			}
+		}
+		if open && i == 0 {
+			return true, time.Time{}
+		}
+		if open {
+			return false, next
+		}
+		next = latest
+	}
+	return false, next
+}
+
+// NewScheduledCondition returns the PodUpgradeScheduled condition, it is true with the scheduled time
+// in message when the upgrade is waiting for the next maintenance window.
+func NewScheduledCondition(waiting bool, scheduledAt time.Time) *corev1.PodCondition {
+	if !waiting {
+		return &corev1.PodCondition{
+			Type:   PodUpgradeScheduled,
+			Status: corev1.ConditionFalse,
+		}
+	}
+	return &corev1.PodCondition{
+		Type:    PodUpgradeScheduled,
+		Status:  corev1.ConditionTrue,
+		Reason:  WaitingForMaintenanceWindowReason,
+		Message: fmt.Sprintf("upgrade is scheduled for %s", scheduledAt.Format(time.RFC3339)),
+	}
+}
+
+// IsScheduled returns true if the upgrade of the pod is waiting for the next maintenance window
+func IsScheduled(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == PodUpgradeScheduled {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// NewPostponedCondition returns the PodUpgradePostponed condition, it is true with the time until which
+// the upgrade is postponed in message, and false when the postponement is cancelled.
+func NewPostponedCondition(postponed bool, until time.Time) *corev1.PodCondition {
+	if !postponed {
+		return &corev1.PodCondition{
+			Type:   PodUpgradePostponed,
+			Status: corev1.ConditionFalse,
+		}
+	}
+	return &corev1.PodCondition{
+		Type:    PodUpgradePostponed,
+		Status:  corev1.ConditionTrue,
+		Reason:  PostponedByUserReason,
+		Message: postponedMessagePrefix + until.UTC().Format(time.RFC3339),
+	}
+}
+
+// PostponedUntil returns the time until which the upgrade of the pod is postponed,
+// an expired postponement is ignored.
+func PostponedUntil(pod *corev1.Pod, now time.Time) (time.Time, bool) {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type != PodUpgradePostponed || cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		until, err := time.Parse(time.RFC3339, strings.TrimPrefix(cond.Message, postponedMessagePrefix))
+		if err != nil || !until.After(now) {
+			return time.Time{}, false
+		}
+		return until, true
+	}
+	return time.Time{}, false
+}
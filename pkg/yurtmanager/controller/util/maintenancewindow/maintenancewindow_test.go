@@ -0,0 +1,223 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenancewindow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openyurtio/openyurt/pkg/apis"
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+)
+
+const nightWindow = `{"schedules":["0 1 * * *"],"duration":"4h","timeZone":"Asia/Shanghai"}`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "valid window", value: nightWindow},
+		{name: "valid window in UTC", value: `{"schedules":["0 1 * * 6","0 2 * * 0"],"duration":"30m"}`},
+		{name: "invalid json", value: `0 1 * * *`, wantErr: true},
+		{name: "empty schedules", value: `{"duration":"1h"}`, wantErr: true},
+		{name: "invalid schedule", value: `{"schedules":["0 25 * * *"],"duration":"1h"}`, wantErr: true},
+		{name: "invalid duration", value: `{"schedules":["0 1 * * *"],"duration":"-1h"}`, wantErr: true},
+		{name: "invalid time zone", value: `{"schedules":["0 1 * * *"],"duration":"1h","timeZone":"Mars/Base"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.value)
+			assert.Equal(t, tt.wantErr, err != nil, "Parse() error = %v", err)
+		})
+	}
+}
+
+func TestWindow(t *testing.T) {
+	w, err := Parse(nightWindow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shanghai := w.Location()
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		wantOpen bool
+		wantNext time.Time
+	}{
+		{
+			name:     "before window",
+			now:      time.Date(2025, 3, 1, 23, 0, 0, 0, shanghai),
+			wantNext: time.Date(2025, 3, 2, 1, 0, 0, 0, shanghai),
+		},
+		{
+			name:     "window opens",
+			now:      time.Date(2025, 3, 2, 1, 0, 0, 0, shanghai),
+			wantOpen: true,
+		},
+		{
+			name:     "within window in another time zone",
+			now:      time.Date(2025, 3, 1, 20, 30, 0, 0, time.UTC),
+			wantOpen: true,
+		},
+		{
+			name:     "window closed",
+			now:      time.Date(2025, 3, 2, 5, 0, 0, 0, shanghai),
+			wantNext: time.Date(2025, 3, 3, 1, 0, 0, 0, shanghai),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantOpen, w.IsOpen(tt.now))
+			next := w.NextOpen(tt.now)
+			if tt.wantOpen {
+				assert.True(t, next.Equal(tt.now))
+			} else {
+				assert.True(t, next.Equal(tt.wantNext), "NextOpen() = %v, want %v", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	night, _ := Parse(nightWindow)
+	weekend, _ := Parse(`{"schedules":["0 0 * * 6"],"duration":"48h","timeZone":"Asia/Shanghai"}`)
+	shanghai := night.Location()
+
+	// Friday night, the night window is open but the weekend window is not, and the night
+	// window is closed when the weekend window opens, so both windows are open at 01:00 on Saturday
+	now := time.Date(2025, 2, 28, 2, 0, 0, 0, shanghai)
+	open, next := Evaluate([]*Window{night, weekend}, now)
+	assert.False(t, open)
+	assert.True(t, next.Equal(time.Date(2025, 3, 1, 1, 0, 0, 0, shanghai)), "Evaluate() next = %v", next)
+
+	// Saturday night, both windows are open
+	open, _ = Evaluate([]*Window{night, weekend}, now.Add(24*time.Hour))
+	assert.True(t, open)
+
+	// no window means always open
+	open, _ = Evaluate(nil, now)
+	assert.True(t, open)
+
+	// the night window is closed when the afternoon window opens, so both windows are open on Sunday night
+	afternoon, _ := Parse(`{"schedules":["0 12 * * 6"],"duration":"16h","timeZone":"Asia/Shanghai"}`)
+	open, next = Evaluate([]*Window{night, afternoon}, now)
+	assert.False(t, open)
+	assert.True(t, next.Equal(time.Date(2025, 3, 2, 1, 0, 0, 0, shanghai)), "Evaluate() next = %v", next)
+
+	// windows never overlap
+	noon, _ := Parse(`{"schedules":["0 12 * * *"],"duration":"1h","timeZone":"Asia/Shanghai"}`)
+	open, next = Evaluate([]*Window{night, noon}, now)
+	assert.False(t, open)
+	assert.True(t, next.After(now), "Evaluate() next = %v", next)
+}
+
+func TestForNode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = apis.AddToScheme(scheme)
+
+	pool := &appsv1beta2.NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "factory",
+			Annotations: map[string]string{apps.AnnotationMaintenanceWindow: nightWindow},
+		},
+	}
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(pool).Build()
+	workload := &metav1.ObjectMeta{
+		Annotations: map[string]string{apps.AnnotationMaintenanceWindow: `{"schedules":["0 0 * * 6"],"duration":"48h"}`},
+	}
+
+	tests := []struct {
+		name     string
+		workload metav1.Object
+		pool     string
+		want     int
+		wantErr  bool
+	}{
+		{name: "no window", workload: &metav1.ObjectMeta{}},
+		{name: "workload window", workload: workload, want: 1},
+		{name: "workload and nodepool windows", workload: workload, pool: "factory", want: 2},
+		{name: "nodepool not found", workload: &metav1.ObjectMeta{}, pool: "store"},
+		{
+			name:     "invalid workload window",
+			workload: &metav1.ObjectMeta{Annotations: map[string]string{apps.AnnotationMaintenanceWindow: "{}"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{}}}
+			if tt.pool != "" {
+				node.Labels[projectinfo.GetNodePoolLabel()] = tt.pool
+			}
+			windows, err := ForNode(context.TODO(), c, tt.workload, node)
+			assert.Equal(t, tt.wantErr, err != nil, "ForNode() error = %v", err)
+			assert.Equal(t, tt.want, len(windows))
+		})
+	}
+}
+
+func TestScheduledCondition(t *testing.T) {
+	at := time.Date(2025, 3, 2, 1, 0, 0, 0, time.UTC)
+	cond := NewScheduledCondition(true, at)
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	assert.Equal(t, "upgrade is scheduled for 2025-03-02T01:00:00Z", cond.Message)
+
+	pod := &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{*cond}}}
+	assert.True(t, IsScheduled(pod))
+	pod.Status.Conditions[0] = *NewScheduledCondition(false, time.Time{})
+	assert.False(t, IsScheduled(pod))
+}
+
+func TestPostponedCondition(t *testing.T) {
+	now := time.Date(2025, 3, 2, 1, 0, 0, 0, time.UTC)
+	cond := NewPostponedCondition(true, now.Add(2*time.Hour))
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	assert.Equal(t, "upgrade is postponed until 2025-03-02T03:00:00Z", cond.Message)
+
+	pod := &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{*cond}}}
+	until, ok := PostponedUntil(pod, now)
+	assert.True(t, ok)
+	assert.True(t, until.Equal(now.Add(2*time.Hour)))
+
+	_, ok = PostponedUntil(pod, now.Add(3*time.Hour))
+	assert.False(t, ok, "expired postponement should be ignored")
+
+	pod.Status.Conditions[0] = *NewPostponedCondition(false, time.Time{})
+	_, ok = PostponedUntil(pod, now)
+	assert.False(t, ok)
+
+	pod.Status.Conditions[0] = corev1.PodCondition{Type: PodUpgradePostponed, Status: corev1.ConditionTrue, Message: "invalid"}
+	_, ok = PostponedUntil(pod, now)
+	assert.False(t, ok)
+}
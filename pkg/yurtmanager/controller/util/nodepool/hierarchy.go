@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+)
+
+// Ancestors returns the ancestors of NodePool from its parent to the root, parents is the map from
+// NodePool names to their parents. An error is returned if the NodePool is in a cycle.
+func Ancestors(name string, parents map[string]string) ([]string, error) {
+	var ancestors []string
+	visited := map[string]bool{name: true}
+	for parent := parents[name]; len(parent) != 0; parent = parents[parent] {
+		if visited[parent] {
+			return nil, fmt.Errorf("nodepool %s is in a cycle: %s", name, strings.Join(append(ancestors, parent), "->"))
+		}
+		visited[parent] = true
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors, nil
+}
+
+// ParentsOf returns the map from NodePool names to their parents.
+func ParentsOf(pools []appsv1beta2.NodePool) map[string]string {
+	parents := make(map[string]string, len(pools))
+	for i := range pools {
+		parents[pools[i].Name] = pools[i].Spec.Parent
+	}
+	return parents
+}
+
+// ChildrenOf returns the sorted names of NodePools whose parent is the specified NodePool.
+func ChildrenOf(name string, pools []appsv1beta2.NodePool) []string {
+	var children []string
+	for i := range pools {
+		if pools[i].Spec.Parent == name && pools[i].Name != name {
+			children = append(children, pools[i].Name)
+		}
+	}
+	sort.Strings(children)
+	return children
+}
+
+// AncestorLabelKey returns the label key which is added to NodePools and nodes under the ancestor NodePool.
+func AncestorLabelKey(ancestor string) string {
+	return apps.NodePoolAncestorLabelPrefix + "/" + ancestor
+}
+
+// IsAncestorLabel returns true if the label key is an ancestor label of NodePool.
+func IsAncestorLabel(key string) bool {
+	return strings.HasPrefix(key, apps.NodePoolAncestorLabelPrefix+"/")
+}
+
+// ConciliateAncestorLabels makes the ancestor labels in labels the same as ancestors,
+// it returns the updated labels and true if the labels are changed.
+func ConciliateAncestorLabels(labels map[string]string, ancestors []string) (map[string]string, bool) {
+	wanted := make(map[string]bool, len(ancestors))
+	for _, ancestor := range ancestors {
+		wanted[AncestorLabelKey(ancestor)] = true
+	}
+
+	changed := false
+	for key := range labels {
+		if IsAncestorLabel(key) && !wanted[key] {
+			delete(labels, key)
+			changed = true
+		}
+	}
+	for key := range wanted {
+		if labels[key] != "true" {
+			if labels == nil {
+				labels = make(map[string]string)
+			}
+			labels[key] = "true"
+			changed = true
+		}
+	}
+	return labels, changed
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAncestors(t *testing.T) {
+	parents := map[string]string{
+		"hangzhou": "east",
+		"east":     "china",
+		"china":    "",
+		"a":        "b",
+		"b":        "a",
+	}
+
+	testcases := map[string]struct {
+		pool      string
+		wanted    []string
+		expectErr bool
+	}{
+		"root pool": {
+			pool: "china",
+		},
+		"leaf pool": {
+			pool:   "hangzhou",
+			wanted: []string{"east", "china"},
+		},
+		"pool in a cycle": {
+			pool:      "a",
+			expectErr: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			ancestors, err := Ancestors(tc.pool, parents)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expect error %v, but got %v", tc.expectErr, err)
+			}
+			if !reflect.DeepEqual(ancestors, tc.wanted) {
+				t.Errorf("expect ancestors %v, but got %v", tc.wanted, ancestors)
+			}
+		})
+	}
+}
+
+func TestConciliateAncestorLabels(t *testing.T) {
+	testcases := map[string]struct {
+		labels    map[string]string
+		ancestors []string
+		wanted    map[string]string
+		changed   bool
+	}{
+		"no ancestors": {
+			labels: map[string]string{"foo": "bar"},
+			wanted: map[string]string{"foo": "bar"},
+		},
+		"add ancestor labels to nil labels": {
+			ancestors: []string{"east"},
+			wanted:    map[string]string{AncestorLabelKey("east"): "true"},
+			changed:   true,
+		},
+		"replace stale ancestor labels": {
+			labels:    map[string]string{"foo": "bar", AncestorLabelKey("west"): "true"},
+			ancestors: []string{"east"},
+			wanted:    map[string]string{"foo": "bar", AncestorLabelKey("east"): "true"},
+			changed:   true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			labels, changed := ConciliateAncestorLabels(tc.labels, tc.ancestors)
+			if changed != tc.changed {
+				t.Errorf("expect changed %v, but got %v", tc.changed, changed)
+			}
+			if !reflect.DeepEqual(labels, tc.wanted) {
+				t.Errorf("expect labels %v, but got %v", tc.wanted, labels)
+			}
+		})
+	}
+}
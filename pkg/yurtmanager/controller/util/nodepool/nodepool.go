@@ -16,6 +16,12 @@ limitations under the License.
 
 package nodepool
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+)
+
 // HasSliceContentChanged checks if the content of the old and new slices has changed.
 func HasSliceContentChanged[T comparable](old, new []T) bool {
 	if len(old) != len(new) {
@@ -35,3 +41,27 @@ func HasSliceContentChanged[T comparable](old, new []T) bool {
 
 	return false
 }
+
+// SetNodePoolCondition adds or updates the condition in status, it returns true if the status is changed.
+func SetNodePoolCondition(status *appsv1beta2.NodePoolStatus, cond appsv1beta2.NodePoolCondition) bool {
+	for i := range status.Conditions {
+		existing := &status.Conditions[i]
+		if existing.Type != cond.Type {
+			continue
+		}
+		if existing.Status == cond.Status && existing.Reason == cond.Reason && existing.Message == cond.Message {
+			return false
+		}
+		if existing.Status != cond.Status {
+			existing.LastTransitionTime = metav1.Now()
+		}
+		existing.Status = cond.Status
+		existing.Reason = cond.Reason
+		existing.Message = cond.Message
+		return true
+	}
+
+	cond.LastTransitionTime = metav1.Now()
+	status.Conditions = append(status.Conditions, cond)
+	return true
+}
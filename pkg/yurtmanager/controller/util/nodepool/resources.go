@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	resourcehelper "k8s.io/component-helpers/resource"
+
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+)
+
+// AccountedResources are the resources which are aggregated in NodePool status and limited by PoolResourceQuota.
+var AccountedResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourcePods}
+
+// IsPodTerminated returns true if the pod doesn't consume resources of node anymore.
+func IsPodTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// PodRequests returns the cpu and memory requests of pod, and the pods resource is always 1.
+func PodRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{})
+	result := corev1.ResourceList{corev1.ResourcePods: *resource.NewQuantity(1, resource.DecimalSI)}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if q, ok := requests[name]; ok {
+			result[name] = q
+		}
+	}
+	return result
+}
+
+// NodeAllocatable returns the allocatable cpu, memory and pods of node.
+func NodeAllocatable(node *corev1.Node) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for _, name := range AccountedResources {
+		if q, ok := node.Status.Allocatable[name]; ok {
+			result[name] = q.DeepCopy()
+		}
+	}
+	return result
+}
+
+// AddResources adds the accounted resources of delta into list.
+func AddResources(list, delta corev1.ResourceList) {
+	for _, name := range AccountedResources {
+		q, ok := delta[name]
+		if !ok {
+			continue
+		}
+		if existing, ok := list[name]; ok {
+			existing.Add(q)
+			list[name] = existing
+		} else {
+			list[name] = q.DeepCopy()
+		}
+	}
+}
+
+// TargetNodePool returns the NodePool which the unscheduled pod targets by nodeSelector or required node affinity,
+// empty string is returned if the pod doesn't target exactly one NodePool.
+func TargetNodePool(pod *corev1.Pod) string {
+	poolLabel := projectinfo.GetNodePoolLabel()
+	if pool, ok := pod.Spec.NodeSelector[poolLabel]; ok {
+		return pool
+	}
+
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+
+	// node selector terms are ORed, so every term should select the same pool
+	var target string
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		var pool string
+		for _, expr := range term.MatchExpressions {
+			if expr.Key == poolLabel && expr.Operator == corev1.NodeSelectorOpIn && len(expr.Values) == 1 {
+				pool = expr.Values[0]
+				break
+			}
+		}
+		if len(pool) == 0 || (len(target) != 0 && pool != target) {
+			return ""
+		}
+		target = pool
+	}
+	return target
+}
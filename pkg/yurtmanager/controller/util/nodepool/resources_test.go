@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/nodepool"
+)
+
+func TestTargetNodePool(t *testing.T) {
+	poolTerm := func(operator corev1.NodeSelectorOperator, pools ...string) corev1.NodeSelectorTerm {
+		return corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: "kubernetes.io/os", Operator: corev1.NodeSelectorOpIn, Values: []string{"linux"}},
+				{Key: projectinfo.GetNodePoolLabel(), Operator: operator, Values: pools},
+			},
+		}
+	}
+	withAffinity := func(terms ...corev1.NodeSelectorTerm) corev1.PodSpec {
+		return corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}}}
+	}
+
+	tests := []struct {
+		name     string
+		spec     corev1.PodSpec
+		expected string
+	}{
+		{
+			name:     "no pool is targeted",
+			spec:     corev1.PodSpec{},
+			expected: "",
+		},
+		{
+			name:     "pool is selected by node selector",
+			spec:     corev1.PodSpec{NodeSelector: map[string]string{projectinfo.GetNodePoolLabel(): "hangzhou"}},
+			expected: "hangzhou",
+		},
+		{
+			name:     "pool is selected by node affinity",
+			spec:     withAffinity(poolTerm(corev1.NodeSelectorOpIn, "hangzhou")),
+			expected: "hangzhou",
+		},
+		{
+			name:     "all terms select the same pool",
+			spec:     withAffinity(poolTerm(corev1.NodeSelectorOpIn, "hangzhou"), poolTerm(corev1.NodeSelectorOpIn, "hangzhou")),
+			expected: "hangzhou",
+		},
+		{
+			name:     "terms select different pools",
+			spec:     withAffinity(poolTerm(corev1.NodeSelectorOpIn, "hangzhou"), poolTerm(corev1.NodeSelectorOpIn, "beijing")),
+			expected: "",
+		},
+		{
+			name:     "multiple pools are selected",
+			spec:     withAffinity(poolTerm(corev1.NodeSelectorOpIn, "hangzhou", "beijing")),
+			expected: "",
+		},
+		{
+			name:     "pool is excluded",
+			spec:     withAffinity(poolTerm(corev1.NodeSelectorOpNotIn, "hangzhou")),
+			expected: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, nodepool.TargetNodePool(&corev1.Pod{Spec: tc.spec}))
+		})
+	}
+}
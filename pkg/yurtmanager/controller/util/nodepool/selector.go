@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+)
+
+// MatchNodeSelector returns true if the node matches all requirements of the nodeSelector of NodePool.
+// A nil or empty nodeSelector doesn't match any node.
+func MatchNodeSelector(selector *appsv1beta2.NodePoolNodeSelector, node *corev1.Node) (bool, error) {
+	if selector == nil || (selector.LabelSelector == nil && len(selector.InternalIPCIDRs) == 0) {
+		return false, nil
+	}
+
+	if selector.LabelSelector != nil {
+		s, err := metav1.LabelSelectorAsSelector(selector.LabelSelector)
+		if err != nil {
+			return false, err
+		}
+		if !s.Matches(labels.Set(node.Labels)) {
+			return false, nil
+		}
+	}
+
+	if len(selector.InternalIPCIDRs) != 0 {
+		var internalIPs []net.IP
+		for _, addr := range node.Status.Addresses {
+			if addr.Type == corev1.NodeInternalIP {
+				if ip := net.ParseIP(addr.Address); ip != nil {
+					internalIPs = append(internalIPs, ip)
+				}
+			}
+		}
+
+		matched := false
+		for _, cidr := range selector.InternalIPCIDRs {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return false, err
+			}
+			for _, ip := range internalIPs {
+				if ipNet.Contains(ip) {
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// MatchingNodePools returns the sorted names of NodePools whose nodeSelector matches the node,
+// NodePools with invalid nodeSelector are skipped.
+func MatchingNodePools(node *corev1.Node, pools []appsv1beta2.NodePool) []string {
+	var matched []string
+	for i := range pools {
+		if ok, err := MatchNodeSelector(pools[i].Spec.NodeSelector, node); err == nil && ok {
+			matched = append(matched, pools[i].Name)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+// SelectNodePool returns the NodePool which the node should belong to according to the nodeSelector of NodePools,
+// and the names of NodePools that match the node.
+// The current NodePool of node is kept if it still matches the node, or no NodePool matches the node, or it has
+// no nodeSelector which means the node is added into it manually. Otherwise, the node is assigned to the only
+// NodePool that matches it. An error is returned along with the current NodePool if the node can't be assigned,
+// e.g. it matches several NodePools.
+func SelectNodePool(node *corev1.Node, pools []appsv1beta2.NodePool) (string, []string, error) {
+	current := node.Labels[projectinfo.GetNodePoolLabel()]
+	matched := MatchingNodePools(node, pools)
+	if len(matched) == 0 {
+		return current, matched, nil
+	}
+
+	var currentPool, targetPool *appsv1beta2.NodePool
+	for i := range pools {
+		if pools[i].Name == current {
+			currentPool = &pools[i]
+		}
+		if pools[i].Name == matched[0] {
+			targetPool = &pools[i]
+		}
+	}
+
+	if len(current) != 0 {
+		for _, name := range matched {
+			if name == current {
+				return current, matched, nil
+			}
+		}
+		if currentPool != nil && currentPool.Spec.NodeSelector == nil {
+			return current, matched, nil
+		}
+	}
+
+	if len(matched) > 1 {
+		return current, matched, fmt.Errorf("node %s matches the nodeSelector of several nodepools: %s", node.Name, strings.Join(matched, ","))
+	}
+
+	// the hostnetwork label of node can not be changed once it's set
+	if hostNetwork, ok := node.Labels[apps.NodePoolHostNetworkLabel]; ok && (hostNetwork == "true") != targetPool.Spec.HostNetwork {
+		return current, matched, fmt.Errorf("node %s can not be moved into nodepool %s, because hostNetwork of nodepool is %t",
+			node.Name, targetPool.Name, targetPool.Spec.HostNetwork)
+	}
+	return matched[0], matched, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodepool
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+)
+
+func TestSelectNodePool(t *testing.T) {
+	pools := []appsv1beta2.NodePool{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "zone-a"},
+			Spec: appsv1beta2.NodePoolSpec{
+				NodeSelector: &appsv1beta2.NodePoolNodeSelector{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelTopologyZone: "a"}},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "lan"},
+			Spec: appsv1beta2.NodePoolSpec{
+				HostNetwork: true,
+				NodeSelector: &appsv1beta2.NodePoolNodeSelector{
+					InternalIPCIDRs: []string{"10.0.0.0/24", "10.0.1.0/24"},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "manual"},
+		},
+	}
+
+	testcases := map[string]struct {
+		labels     map[string]string
+		internalIP string
+		wanted     string
+		matched    int
+		expectErr  bool
+	}{
+		"node doesn't match any nodepool": {
+			labels: map[string]string{corev1.LabelTopologyZone: "b"},
+		},
+		"node matches by labels": {
+			labels:  map[string]string{corev1.LabelTopologyZone: "a"},
+			wanted:  "zone-a",
+			matched: 1,
+		},
+		"node matches by internal ip": {
+			internalIP: "10.0.1.5",
+			wanted:     "lan",
+			matched:    1,
+		},
+		"node is kept in current matched nodepool": {
+			labels:     map[string]string{projectinfo.GetNodePoolLabel(): "lan", corev1.LabelTopologyZone: "a"},
+			internalIP: "10.0.0.5",
+			wanted:     "lan",
+			matched:    2,
+		},
+		"node is kept in nodepool without node selector": {
+			labels:  map[string]string{projectinfo.GetNodePoolLabel(): "manual", corev1.LabelTopologyZone: "a"},
+			wanted:  "manual",
+			matched: 1,
+		},
+		"node matches several nodepools": {
+			labels:     map[string]string{corev1.LabelTopologyZone: "a"},
+			internalIP: "10.0.0.5",
+			matched:    2,
+			expectErr:  true,
+		},
+		"node can not be moved into nodepool with different host network": {
+			labels:     map[string]string{projectinfo.GetNodePoolLabel(): "zone-a", apps.NodePoolHostNetworkLabel: "false"},
+			internalIP: "10.0.0.5",
+			wanted:     "zone-a",
+			matched:    1,
+			expectErr:  true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: tc.labels}}
+			if len(tc.internalIP) != 0 {
+				node.Status.Addresses = []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: tc.internalIP}}
+			}
+
+			pool, matched, err := SelectNodePool(node, pools)
+			if (err != nil) != tc.expectErr {
+				t.Errorf("expect error %v, but got %v", tc.expectErr, err)
+			}
+			if pool != tc.wanted {
+				t.Errorf("expect nodepool %q, but got %q", tc.wanted, pool)
+			}
+			if len(matched) != tc.matched {
+				t.Errorf("expect %d matched nodepools, but got %v", tc.matched, matched)
+			}
+		})
+	}
+}
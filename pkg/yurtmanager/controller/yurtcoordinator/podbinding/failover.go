@@ -0,0 +1,230 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbinding
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	nodeutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/node"
+)
+
+const (
+	// failoverRecheckInterval is the interval for checking again whether the pod can be failed over
+	// when there is no healthy node to host it.
+	failoverRecheckInterval = time.Minute
+
+	failedOverReason = "FailedOver"
+)
+
+// failoverPolicy declares when and where pods bound to a failed node are failed over.
+type failoverPolicy struct {
+	// after is the duration for which the node is NotReady before pods are failed over
+	after time.Duration
+	// backupPool is the NodePool to which pods can be failed over besides the NodePool of the failed node
+	backupPool string
+}
+
+// resolveFailoverPolicy resolves the failover policy from annotations of pod and NodePool, the annotations
+// of pod take precedence. nil is returned if failover is not enabled.
+func resolveFailoverPolicy(pod *corev1.Pod, pool *appsv1beta2.NodePool) (*failoverPolicy, error) {
+	lookup := func(key string) string {
+		if v, ok := pod.Annotations[key]; ok {
+			return v
+		}
+		if pool != nil {
+			return pool.Annotations[key]
+		}
+		return ""
+	}
+
+	after := lookup(apps.AnnotationFailoverAfter)
+	if len(after) == 0 {
+		return nil, nil
+	}
+	duration, err := time.ParseDuration(after)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse failover duration %s, %w", after, err)
+	}
+	if duration <= 0 {
+		return nil, nil
+	}
+
+	return &failoverPolicy{
+		after:      duration,
+		backupPool: lookup(apps.AnnotationFailoverBackupPool),
+	}, nil
+}
+
+// isFailedOver returns true if the pod has been failed over from its node.
+func isFailedOver(pod *corev1.Pod) bool {
+	_, ok := pod.Annotations[apps.AnnotationFailoverTime]
+	return ok
+}
+
+// nodeNotReadySince returns the time since when the node is NotReady, false is returned if the node is ready.
+func nodeNotReadySince(node *corev1.Node) (time.Time, bool) {
+	_, cond := nodeutil.GetNodeCondition(&node.Status, corev1.NodeReady)
+	if cond == nil || cond.Status == corev1.ConditionTrue {
+		return time.Time{}, false
+	}
+	return cond.LastTransitionTime.Time, true
+}
+
+// failoverIfNeeded checks whether the pod bound to the autonomous node should be failed over, and the pod is marked
+// with failover annotation if so. Pods are failed over only when the node has been NotReady for the failover
+// duration and there are healthy nodes to host them. The second return value is the duration after which the
+// pod should be checked again.
+func (r *ReconcilePodBinding) failoverIfNeeded(ctx context.Context, pod *corev1.Pod, node *corev1.Node) (bool, time.Duration, error) {
+	since, notReady := nodeNotReadySince(node)
+	if !notReady {
+		return false, 0, nil
+	}
+
+	poolName := node.Labels[projectinfo.GetNodePoolLabel()]
+	pool, err := r.getNodePool(ctx, poolName)
+	if err != nil {
+		return false, 0, err
+	}
+
+	policy, err := resolveFailoverPolicy(pod, pool)
+	if err != nil {
+		klog.Errorf("could not resolve failover policy of pod(%s/%s), %v", pod.Namespace, pod.Name, err)
+		return false, 0, nil
+	} else if policy == nil {
+		return false, 0, nil
+	}
+
+	if elapsed := time.Since(since); elapsed < policy.after {
+		return false, policy.after - elapsed, nil
+	}
+
+	targets, err := r.findFailoverTargets(ctx, node, pool, policy.backupPool)
+	if err != nil {
+		return false, 0, err
+	} else if len(targets) == 0 {
+		klog.Infof("pod(%s/%s) is not failed over from node %s, because there is no healthy node to host it", pod.Namespace, pod.Name, node.Name)
+		return false, failoverRecheckInterval, nil
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	// the replacement pods are scheduled to the target NodePools by the node affinity added by pod webhook
+	pod.Annotations[apps.AnnotationFailoverTime] = time.Now().UTC().Format(time.RFC3339)
+	pod.Annotations[apps.AnnotationFailoverTargetPools] = strings.Join(targets, ",")
+	r.recorder.Eventf(pod, corev1.EventTypeNormal, failedOverReason,
+		"Node %s has been NotReady for more than %v, pod will be evicted and replaced in nodepool %s", node.Name, policy.after, strings.Join(targets, " or "))
+	klog.Infof("pod(%s/%s) is failed over from node %s to nodepool %v", pod.Namespace, pod.Name, node.Name, targets)
+	return true, 0, nil
+}
+
+// recoverIfNeeded clears the failover state of pod if its node becomes ready before the pod is evicted, so that
+// the pod tolerates the node failure again. true is returned if the state is cleared.
+func recoverIfNeeded(pod *corev1.Pod, node *corev1.Node) bool {
+	if !isFailedOver(pod) || pod.DeletionTimestamp != nil {
+		return false
+	}
+	if _, notReady := nodeNotReadySince(node); notReady {
+		return false
+	}
+	delete(pod.Annotations, apps.AnnotationFailoverTime)
+	delete(pod.Annotations, apps.AnnotationFailoverTargetPools)
+	klog.Infof("node %s of failed over pod(%s/%s) is ready again, the failover is cancelled", node.Name, pod.Namespace, pod.Name)
+	return true
+}
+
+// findFailoverTargets returns the NodePools which can host the pods failed over from the node. Pods are not failed
+// over if the NodePool of node is disconnected or the majority of the other nodes in it are not ready, because the
+// node may be in an outage of the site rather than failed permanently.
+func (r *ReconcilePodBinding) findFailoverTargets(ctx context.Context, node *corev1.Node, pool *appsv1beta2.NodePool, backupPool string) ([]string, error) {
+	var targets []string
+	if pool != nil {
+		for _, cond := range pool.Status.Conditions {
+			if cond.Type == appsv1beta2.PoolDisconnected && cond.Status == corev1.ConditionTrue {
+				return nil, nil
+			}
+		}
+
+		others, ready, err := r.countReadyNodes(ctx, pool.Name, node.Name)
+		if err != nil {
+			return nil, err
+		}
+		if others != 0 && ready*2 <= others {
+			return nil, nil
+		}
+		if ready != 0 {
+			targets = append(targets, pool.Name)
+		}
+	}
+
+	if len(backupPool) != 0 && (pool == nil || backupPool != pool.Name) {
+		_, ready, err := r.countReadyNodes(ctx, backupPool, node.Name)
+		if err != nil {
+			return nil, err
+		}
+		if ready != 0 {
+			targets = append(targets, backupPool)
+		}
+	}
+	return targets, nil
+}
+
+// countReadyNodes returns the number of nodes and ready nodes in the NodePool except the specified node.
+func (r *ReconcilePodBinding) countReadyNodes(ctx context.Context, poolName, exceptNode string) (int, int, error) {
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabels{projectinfo.GetNodePoolLabel(): poolName}); err != nil {
+		return 0, 0, err
+	}
+
+	var total, ready int
+	for i := range nodes.Items {
+		if nodes.Items[i].Name == exceptNode {
+			continue
+		}
+		total++
+		if _, cond := nodeutil.GetNodeCondition(&nodes.Items[i].Status, corev1.NodeReady); cond != nil && cond.Status == corev1.ConditionTrue {
+			ready++
+		}
+	}
+	return total, ready, nil
+}
+
+func (r *ReconcilePodBinding) getNodePool(ctx context.Context, poolName string) (*appsv1beta2.NodePool, error) {
+	if len(poolName) == 0 {
+		return nil, nil
+	}
+
+	pool := &appsv1beta2.NodePool{}
+	if err := r.Get(ctx, client.ObjectKey{Name: poolName}, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pool, nil
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podbinding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openyurtio/openyurt/pkg/apis"
+	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+)
+
+func newFailoverNode(name, pool string, ready corev1.ConditionStatus, since time.Duration) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				projectinfo.GetEdgeWorkerLabelKey(): "true",
+				projectinfo.GetNodePoolLabel():      pool,
+			},
+			Annotations: map[string]string{
+				projectinfo.GetNodeAutonomyDurationAnnotation(): "0",
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:               corev1.NodeReady,
+					Status:             ready,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+				},
+			},
+		},
+	}
+}
+
+func TestResolveFailoverPolicy(t *testing.T) {
+	pool := &appsv1beta2.NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "hangzhou",
+			Annotations: map[string]string{
+				apps.AnnotationFailoverAfter:      "1h",
+				apps.AnnotationFailoverBackupPool: "shanghai",
+			},
+		},
+	}
+
+	testcases := map[string]struct {
+		podAnnotations map[string]string
+		pool           *appsv1beta2.NodePool
+		expected       *failoverPolicy
+		expectErr      bool
+	}{
+		"failover is not enabled": {},
+		"policy of nodepool": {
+			pool:     pool,
+			expected: &failoverPolicy{after: time.Hour, backupPool: "shanghai"},
+		},
+		"annotations of pod take precedence": {
+			podAnnotations: map[string]string{apps.AnnotationFailoverAfter: "10m"},
+			pool:           pool,
+			expected:       &failoverPolicy{after: 10 * time.Minute, backupPool: "shanghai"},
+		},
+		"failover is disabled by pod": {
+			podAnnotations: map[string]string{apps.AnnotationFailoverAfter: "0s"},
+			pool:           pool,
+		},
+		"invalid duration": {
+			podAnnotations: map[string]string{apps.AnnotationFailoverAfter: "1day"},
+			expectErr:      true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tc.podAnnotations}}
+			policy, err := resolveFailoverPolicy(pod, tc.pool)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expect error %v, but got %v", tc.expectErr, err)
+			}
+			if tc.expected == nil {
+				if policy != nil {
+					t.Errorf("expect no policy, but got %v", *policy)
+				}
+				return
+			}
+			if policy == nil || *policy != *tc.expected {
+				t.Errorf("expect policy %v, but got %v", *tc.expected, policy)
+			}
+		})
+	}
+}
+
+func TestReconcileFailover(t *testing.T) {
+	originalSeconds := int64(300)
+	newPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod1",
+				Namespace: metav1.NamespaceDefault,
+				Annotations: map[string]string{
+					originalNotReadyTolerationDurationAnnotation: "300",
+				},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: "node1",
+				Tolerations: []corev1.Toleration{
+					{
+						Key:      corev1.TaintNodeNotReady,
+						Operator: corev1.TolerationOpExists,
+						Effect:   corev1.TaintEffectNoExecute,
+					},
+				},
+			},
+		}
+	}
+	newPool := func(name string, disconnected bool) *appsv1beta2.NodePool {
+		pool := &appsv1beta2.NodePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					apps.AnnotationFailoverAfter:      "30m",
+					apps.AnnotationFailoverBackupPool: "backup",
+				},
+			},
+		}
+		if disconnected {
+			pool.Status.Conditions = []appsv1beta2.NodePoolCondition{{Type: appsv1beta2.PoolDisconnected, Status: corev1.ConditionTrue}}
+		}
+		return pool
+	}
+
+	testcases := map[string]struct {
+		objects      []client.Object
+		failedOver   bool
+		targetPools  string
+		requeueAfter bool
+	}{
+		"node is ready": {
+			objects: []client.Object{
+				newPool("hangzhou", false),
+				newFailoverNode("node1", "hangzhou", corev1.ConditionTrue, time.Hour),
+				newFailoverNode("node2", "hangzhou", corev1.ConditionTrue, time.Hour),
+			},
+		},
+		"node is not ready for failover duration": {
+			objects: []client.Object{
+				newPool("hangzhou", false),
+				newFailoverNode("node1", "hangzhou", corev1.ConditionUnknown, 10*time.Minute),
+				newFailoverNode("node2", "hangzhou", corev1.ConditionTrue, time.Hour),
+			},
+			requeueAfter: true,
+		},
+		"failover to other node in the same nodepool": {
+			objects: []client.Object{
+				newPool("hangzhou", false),
+				newFailoverNode("node1", "hangzhou", corev1.ConditionUnknown, time.Hour),
+				newFailoverNode("node2", "hangzhou", corev1.ConditionTrue, time.Hour),
+			},
+			failedOver:  true,
+			targetPools: "hangzhou",
+		},
+		"majority of other nodes in nodepool are not ready": {
+			objects: []client.Object{
+				newPool("hangzhou", false),
+				newFailoverNode("node1", "hangzhou", corev1.ConditionUnknown, time.Hour),
+				newFailoverNode("node2", "hangzhou", corev1.ConditionTrue, time.Hour),
+				newFailoverNode("node3", "hangzhou", corev1.ConditionUnknown, time.Hour),
+				newFailoverNode("node4", "hangzhou", corev1.ConditionUnknown, time.Hour),
+				newFailoverNode("node5", "backup", corev1.ConditionTrue, time.Hour),
+			},
+			requeueAfter: true,
+		},
+		"nodepool is disconnected": {
+			objects: []client.Object{
+				newPool("hangzhou", true),
+				newFailoverNode("node1", "hangzhou", corev1.ConditionUnknown, time.Hour),
+				newFailoverNode("node2", "hangzhou", corev1.ConditionTrue, time.Hour),
+			},
+			requeueAfter: true,
+		},
+		"other nodes in nodepool are not ready": {
+			objects: []client.Object{
+				newPool("hangzhou", false),
+				newFailoverNode("node1", "hangzhou", corev1.ConditionUnknown, time.Hour),
+				newFailoverNode("node2", "hangzhou", corev1.ConditionUnknown, time.Hour),
+				newFailoverNode("node3", "backup", corev1.ConditionTrue, time.Hour),
+			},
+			requeueAfter: true,
+		},
+		"failover to backup nodepool": {
+			objects: []client.Object{
+				newPool("hangzhou", false),
+				newFailoverNode("node1", "hangzhou", corev1.ConditionUnknown, time.Hour),
+				newFailoverNode("node3", "backup", corev1.ConditionTrue, time.Hour),
+			},
+			failedOver:  true,
+			targetPools: "backup",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	apis.AddToScheme(scheme)
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(newPod()).WithObjects(tc.objects...).Build()
+			reconciler := ReconcilePodBinding{
+				Client:   c,
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "pod1"}}
+			result, err := reconciler.Reconcile(context.TODO(), req)
+			if err != nil {
+				t.Fatalf("failed to reconcile, %v", err)
+			}
+			if (result.RequeueAfter != 0) != tc.requeueAfter {
+				t.Errorf("expect requeue %v, but got %v", tc.requeueAfter, result.RequeueAfter)
+			}
+
+			currentPod := &corev1.Pod{}
+			if err := c.Get(context.TODO(), req.NamespacedName, currentPod); err != nil {
+				t.Fatalf("couldn't get current pod, %v", err)
+			}
+			if isFailedOver(currentPod) != tc.failedOver {
+				t.Errorf("expect pod failed over %v, but got %v", tc.failedOver, isFailedOver(currentPod))
+			}
+
+			if pools := currentPod.Annotations[apps.AnnotationFailoverTargetPools]; pools != tc.targetPools {
+				t.Errorf("expect failover target pools %q, but got %q", tc.targetPools, pools)
+			}
+
+			seconds := currentPod.Spec.Tolerations[0].TolerationSeconds
+			if tc.failedOver && (seconds == nil || *seconds != originalSeconds) {
+				t.Errorf("expect toleration seconds to be restored to %d, but got %v", originalSeconds, seconds)
+			} else if !tc.failedOver && seconds != nil {
+				t.Errorf("expect pod to tolerate node failure forever, but got %d", *seconds)
+			}
+		})
+	}
+}
+
+func TestReconcileFailoverRecovered(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod1",
+			Namespace: metav1.NamespaceDefault,
+			Annotations: map[string]string{
+				originalNotReadyTolerationDurationAnnotation: "300",
+				apps.AnnotationFailoverTime:                  time.Now().UTC().Format(time.RFC3339),
+				apps.AnnotationFailoverTargetPools:           "hangzhou",
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node1",
+			Tolerations: []corev1.Toleration{
+				{
+					Key:      corev1.TaintNodeNotReady,
+					Operator: corev1.TolerationOpExists,
+					Effect:   corev1.TaintEffectNoExecute,
+				},
+			},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	apis.AddToScheme(scheme)
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).
+		WithObjects(pod, newFailoverNode("node1", "hangzhou", corev1.ConditionTrue, time.Minute)).Build()
+	reconciler := ReconcilePodBinding{
+		Client:   c,
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "pod1"}}
+	if _, err := reconciler.Reconcile(context.TODO(), req); err != nil {
+		t.Fatalf("failed to reconcile, %v", err)
+	}
+
+	currentPod := &corev1.Pod{}
+	if err := c.Get(context.TODO(), req.NamespacedName, currentPod); err != nil {
+		t.Fatalf("couldn't get current pod, %v", err)
+	}
+	if isFailedOver(currentPod) || len(currentPod.Annotations[apps.AnnotationFailoverTargetPools]) != 0 {
+		t.Errorf("expect failover state to be cleared, but got annotations %v", currentPod.Annotations)
+	}
+	if seconds := currentPod.Spec.Tolerations[0].TolerationSeconds; seconds != nil {
+		t.Errorf("expect pod to tolerate node failure forever again, but got %d", *seconds)
+	}
+}
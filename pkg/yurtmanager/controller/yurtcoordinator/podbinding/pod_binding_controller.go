@@ -27,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -60,6 +61,7 @@ var (
 
 type ReconcilePodBinding struct {
 	client.Client
+	recorder record.EventRecorder
 }
 
 // Add creates a PodBingding controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
@@ -68,7 +70,8 @@ func Add(ctx context.Context, cfg *appconfig.CompletedConfig, mgr manager.Manage
 	klog.Infof("podbinding-controller add controller %s", controllerKind.String())
 
 	reconciler := &ReconcilePodBinding{
-		Client: yurtClient.GetClientByControllerNameOrDie(mgr, names.PodBindingController),
+		Client:   yurtClient.GetClientByControllerNameOrDie(mgr, names.PodBindingController),
+		recorder: mgr.GetEventRecorderFor(names.PodBindingController),
 	}
 
 	c, err := controller.New(names.PodBindingController, mgr, controller.Options{
@@ -121,12 +124,14 @@ func Add(ctx context.Context, cfg *appconfig.CompletedConfig, mgr manager.Manage
 				return false
 			}
 
-			// only enqueue if autonomy annotations changed
+			// only enqueue if autonomy annotations or node readiness changed
 			if (oldNode.Annotations[projectinfo.GetAutonomyAnnotation()] != newNode.Annotations[projectinfo.GetAutonomyAnnotation()]) ||
 				(oldNode.Annotations[projectinfo.GetNodeAutonomyDurationAnnotation()] != newNode.Annotations[projectinfo.GetNodeAutonomyDurationAnnotation()]) {
 				return true
 			}
-			return false
+			_, oldNotReady := nodeNotReadySince(oldNode)
+			_, newNotReady := nodeNotReadySince(newNode)
+			return oldNotReady != newNotReady
 		},
 		GenericFunc: func(evt event.GenericEvent) bool {
 			return false
@@ -196,8 +201,9 @@ func Add(ctx context.Context, cfg *appconfig.CompletedConfig, mgr manager.Manage
 	return nil
 }
 
-// +kubebuilder:rbac:groups="",resources=nodes,verbs=get
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;update
+// +kubebuilder:rbac:groups=apps.openyurt.io,resources=nodepools,verbs=get;list;watch
 
 // Reconcile reads that state of Node in cluster and makes changes if node autonomy state has been changed
 func (r *ReconcilePodBinding) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
@@ -207,31 +213,46 @@ func (r *ReconcilePodBinding) Reconcile(ctx context.Context, req reconcile.Reque
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
 
-	if err := r.reconcilePod(pod); err != nil {
+	requeueAfter, err := r.reconcilePod(pod)
+	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
-func (r *ReconcilePodBinding) reconcilePod(pod *corev1.Pod) error {
+// reconcilePod updates the toleration seconds of pod according to node autonomy setting, and returns
+// the duration after which the pod should be reconciled again for failover.
+func (r *ReconcilePodBinding) reconcilePod(pod *corev1.Pod) (time.Duration, error) {
 	// skip pod which is not assigned to node
 	if len(pod.Spec.NodeName) == 0 {
-		return nil
+		return 0, nil
 	}
 
 	node := &corev1.Node{}
 	if err := r.Get(context.Background(), client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
-		return client.IgnoreNotFound(err)
+		return 0, client.IgnoreNotFound(err)
 	}
 
 	// skip pods which don't run on edge nodes
 	if node.Labels[projectinfo.GetEdgeWorkerLabelKey()] != "true" {
-		return nil
+		return 0, nil
 	}
 
 	storedPod := pod.DeepCopy()
-	if isAutonomous, duration := resolveNodeAutonomySetting(node); isAutonomous {
+	var requeueAfter time.Duration
+	isAutonomous, duration := resolveNodeAutonomySetting(node)
+	recoverIfNeeded(pod, node)
+	if isAutonomous && !isFailedOver(pod) {
+		// pods which are failed over from the node don't tolerate the node failure anymore
+		failedOver, after, err := r.failoverIfNeeded(context.TODO(), pod, node)
+		if err != nil {
+			return 0, err
+		}
+		isAutonomous, requeueAfter = !failedOver, after
+	}
+
+	if isAutonomous && !isFailedOver(pod) {
 		// update pod tolerationSeconds according to node autonomy annotation,
 		// store the original toleration seconds into pod annotations.
 		for i := range pod.Spec.Tolerations {
@@ -265,10 +286,10 @@ func (r *ReconcilePodBinding) reconcilePod(pod *corev1.Pod) error {
 	if !reflect.DeepEqual(storedPod, pod) {
 		if err := r.Update(context.TODO(), pod, &client.UpdateOptions{}); err != nil {
 			klog.Errorf("could not update pod(%s/%s), %v", pod.Namespace, pod.Name, err)
-			return err
+			return 0, err
 		}
 	}
-	return nil
+	return requeueAfter, nil
 }
 
 func (r *ReconcilePodBinding) getPodsAssignedToNode(name string) ([]corev1.Pod, error) {
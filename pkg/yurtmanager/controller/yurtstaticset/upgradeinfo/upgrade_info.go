@@ -19,15 +19,18 @@ package upgradeinfo
 import (
 	"bytes"
 	"context"
-	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/kubectl/pkg/util/podutils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/maintenancewindow"
 	podutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/pod"
 	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/yurtstaticset/util"
 )
@@ -59,11 +62,32 @@ type UpgradeInfo struct {
 	// Indicate the worker pod status
 	WorkerPodStatusPhase corev1.PodPhase
 
+	// Indicate whether the latest worker pod on the node is failed.
+	// If true, the upgrade on this node should not be retried automatically.
+	WorkerPodFailed bool
+
 	// Indicate whether the worker pod need to be delete
 	WorkerPodDeleteNeeded bool
 
 	// Indicate whether the node is ready. It's used in AdvancedRollingUpdate mode.
 	NodeReady bool
+
+	// Indicate whether the node is selected as a canary node. It's used in AdvancedRollingUpdate mode.
+	Canary bool
+
+	// Indicate whether the maintenance window of the node is closed. If true, the static pod
+	// can not be upgraded until ScheduledAt.
+	WaitingForMaintenanceWindow bool
+
+	// The time when the maintenance window of the node opens next time
+	ScheduledAt time.Time
+
+	// Indicate whether the upgrade of the static pod is postponed by the OTA API of YurtHub.
+	// If true, the static pod can not be upgraded until PostponedUntil.
+	Postponed bool
+
+	// The time until which the upgrade of the static pod is postponed
+	PostponedUntil time.Time
 }
 
 // New constructs the upgrade information for nodes which have the target static pod
@@ -93,9 +117,7 @@ func New(c client.Client, instance *appsv1alpha1.YurtStaticSet, workerPodName, h
 		name := workerPodName + instance.Name
 		if strings.Contains(pod.Name, name) {
 			// initialize worker pod info
-			if err := initWorkerPodInfo(nodeName, hash, &podList.Items[i], infos); err != nil {
-				return nil, err
-			}
+			initWorkerPodInfo(nodeName, hash, &podList.Items[i], infos)
 		}
 	}
 
@@ -124,16 +146,34 @@ func initStaticPodInfo(instance *appsv1alpha1.YurtStaticSet, c client.Client, no
 		infos[nodeName].StaticPodReady = true
 	}
 
-	// Sets the ready status for every node which has the target static pod
-	ready, err := util.NodeReadyByName(c, nodeName)
+	// Sets whether the upgrade of the static pod is postponed
+	infos[nodeName].PostponedUntil, infos[nodeName].Postponed = maintenancewindow.PostponedUntil(pod, time.Now())
+
+	// Sets the ready status and canary flag for every node which has the target static pod
+	node := &corev1.Node{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: nodeName}, node); err != nil {
+		return err
+	}
+	infos[nodeName].NodeReady = util.NodeReady(node)
+
+	canary, err := util.IsCanaryNode(instance.Spec.UpgradeStrategy.Canary, node)
+	if err != nil {
+		return err
+	}
+	infos[nodeName].Canary = canary
+
+	// Sets whether the static pod on the node is allowed to be upgraded now
+	windows, err := maintenancewindow.ForNode(context.TODO(), c, instance, node)
 	if err != nil {
 		return err
 	}
-	infos[nodeName].NodeReady = ready
+	open, next := maintenancewindow.Evaluate(windows, time.Now())
+	infos[nodeName].WaitingForMaintenanceWindow = !open
+	infos[nodeName].ScheduledAt = next
 	return nil
 }
 
-func initWorkerPodInfo(nodeName, hash string, pod *corev1.Pod, infos map[string]*UpgradeInfo) error {
+func initWorkerPodInfo(nodeName, hash string, pod *corev1.Pod, infos map[string]*UpgradeInfo) {
 	if info := infos[nodeName]; info == nil {
 		infos[nodeName] = &UpgradeInfo{}
 	}
@@ -142,8 +182,9 @@ func initWorkerPodInfo(nodeName, hash string, pod *corev1.Pod, infos map[string]
 	infos[nodeName].WorkerPodStatusPhase = pod.Status.Phase
 	switch pod.Status.Phase {
 	case corev1.PodFailed:
-		// The worker pod is failed, then some irreparable failure has occurred. Just stop reconcile and update status
-		return fmt.Errorf("fail to init worker pod info, cause worker pod %s failed", pod.Name)
+		// The worker pod is failed, then some irreparable failure has occurred on this node.
+		// The upgrade should be stopped and the failure is reported in the status
+		infos[nodeName].WorkerPodFailed = true
 	case corev1.PodSucceeded:
 		// The worker pod is succeeded, then this node must be up-to-date. Just delete this worker pod
 		infos[nodeName].WorkerPodDeleteNeeded = true
@@ -155,8 +196,8 @@ func initWorkerPodInfo(nodeName, hash string, pod *corev1.Pod, infos map[string]
 	if pod.Annotations[StaticPodHashAnnotation] != hash {
 		// If the worker pod is not up-to-date, then it can be recreated directly
 		infos[nodeName].WorkerPodDeleteNeeded = true
+		infos[nodeName].WorkerPodFailed = false
 	}
-	return nil
 }
 
 // match check if the given YurtStaticSet's template matches the pod.
@@ -230,15 +271,60 @@ func match(instance *appsv1alpha1.YurtStaticSet, pod *corev1.Pod) bool {
 // ReadyUpgradeWaitingNodes gets those nodes that satisfied
 // 1. node is ready
 // 2. node needs to be upgraded
-// 3. no latest worker pod running on the node
-// On these nodes, new worker pods need to be created for AdvancedRollingUpdate mode
+// 3. no latest worker pod running or failed on the node
+// 4. maintenance window of the node is open and the upgrade is not postponed
+// On these nodes, new worker pods need to be created for AdvancedRollingUpdate mode.
+// The canary nodes are listed first, and nodes are sorted by name in each group.
 func ReadyUpgradeWaitingNodes(infos map[string]*UpgradeInfo) []string {
 	var nodes []string
 	for node, info := range infos {
-		if info.UpgradeNeeded && !info.WorkerPodRunning && info.NodeReady {
+		if info.UpgradeNeeded && !info.WorkerPodRunning && !info.WorkerPodFailed && info.NodeReady &&
+			!info.WaitingForMaintenanceWindow && !info.Postponed {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if infos[nodes[i]].Canary != infos[nodes[j]].Canary {
+			return infos[nodes[i]].Canary
+		}
+		return nodes[i] < nodes[j]
+	})
+	return nodes
+}
+
+// FailedWorkerNodes gets nodes on which the latest worker pod is failed
+func FailedWorkerNodes(infos map[string]*UpgradeInfo) []string {
+	var nodes []string
+	for node, info := range infos {
+		if info.WorkerPodFailed {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// CanaryUpgradeNeededNodes gets the canary nodes that are not running the latest static pod
+func CanaryUpgradeNeededNodes(infos map[string]*UpgradeInfo) []string {
+	var nodes []string
+	for node, info := range infos {
+		if info.Canary && info.StaticPod != nil && info.UpgradeNeeded {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// MaintenanceWindowWaitingNodes gets nodes that need to be upgraded but the maintenance window is closed
+func MaintenanceWindowWaitingNodes(infos map[string]*UpgradeInfo) []string {
+	var nodes []string
+	for node, info := range infos {
+		if info.StaticPod != nil && info.UpgradeNeeded && info.WaitingForMaintenanceWindow {
 			nodes = append(nodes, node)
 		}
 	}
+	sort.Strings(nodes)
 	return nodes
 }
 
@@ -221,6 +221,81 @@ func TestNodes(t *testing.T) {
 	})
 }
 
+func TestCanaryAndFailedWorkerNodes(t *testing.T) {
+	spi := map[string]*UpgradeInfo{
+		"node1": {StaticPod: &corev1.Pod{}, UpgradeNeeded: true, NodeReady: true},
+		"node2": {StaticPod: &corev1.Pod{}, UpgradeNeeded: true, NodeReady: true, Canary: true},
+		"node3": {StaticPod: &corev1.Pod{}, UpgradeNeeded: true, NodeReady: true, WorkerPod: &corev1.Pod{}, WorkerPodFailed: true},
+		"node4": {StaticPod: &corev1.Pod{}, UpgradeNeeded: true, NodeReady: true, Canary: true},
+		"node5": {StaticPod: &corev1.Pod{}, Canary: true},
+	}
+
+	if got := ReadyUpgradeWaitingNodes(spi); !reflect.DeepEqual(got, []string{"node2", "node4", "node1"}) {
+		t.Errorf("ReadyUpgradeWaitingNodes = %v, want canary nodes first", got)
+	}
+	if got := CanaryUpgradeNeededNodes(spi); !reflect.DeepEqual(got, []string{"node2", "node4"}) {
+		t.Errorf("CanaryUpgradeNeededNodes = %v, want [node2 node4]", got)
+	}
+	if got := FailedWorkerNodes(spi); !reflect.DeepEqual(got, []string{"node3"}) {
+		t.Errorf("FailedWorkerNodes = %v, want [node3]", got)
+	}
+}
+
+func TestMaintenanceWindowWaitingNodes(t *testing.T) {
+	spi := map[string]*UpgradeInfo{
+		"node1": {StaticPod: &corev1.Pod{}, UpgradeNeeded: true, NodeReady: true},
+		"node2": {StaticPod: &corev1.Pod{}, UpgradeNeeded: true, NodeReady: true, WaitingForMaintenanceWindow: true},
+		"node3": {StaticPod: &corev1.Pod{}, NodeReady: true, WaitingForMaintenanceWindow: true},
+	}
+
+	if got := ReadyUpgradeWaitingNodes(spi); !reflect.DeepEqual(got, []string{"node1"}) {
+		t.Errorf("ReadyUpgradeWaitingNodes = %v, want [node1]", got)
+	}
+	if got := MaintenanceWindowWaitingNodes(spi); !reflect.DeepEqual(got, []string{"node2"}) {
+		t.Errorf("MaintenanceWindowWaitingNodes = %v, want [node2]", got)
+	}
+}
+
+func TestPostponedNodes(t *testing.T) {
+	spi := map[string]*UpgradeInfo{
+		"node1": {StaticPod: &corev1.Pod{}, UpgradeNeeded: true, NodeReady: true},
+		"node2": {StaticPod: &corev1.Pod{}, UpgradeNeeded: true, NodeReady: true, Postponed: true},
+	}
+
+	if got := ReadyUpgradeWaitingNodes(spi); !reflect.DeepEqual(got, []string{"node1"}) {
+		t.Errorf("ReadyUpgradeWaitingNodes = %v, want [node1]", got)
+	}
+}
+
+func TestInitWorkerPodInfo(t *testing.T) {
+	tests := []struct {
+		name       string
+		phase      corev1.PodPhase
+		hash       string
+		wantFailed bool
+		wantDelete bool
+	}{
+		{name: "latest worker failed", phase: corev1.PodFailed, hash: "latest", wantFailed: true},
+		{name: "out-of-date worker failed", phase: corev1.PodFailed, hash: "old", wantDelete: true},
+		{name: "latest worker succeeded", phase: corev1.PodSucceeded, hash: "latest", wantDelete: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			infos := make(map[string]*UpgradeInfo)
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{StaticPodHashAnnotation: tt.hash}},
+				Status:     corev1.PodStatus{Phase: tt.phase},
+			}
+			initWorkerPodInfo("node1", "latest", pod, infos)
+			if infos["node1"].WorkerPodFailed != tt.wantFailed || infos["node1"].WorkerPodDeleteNeeded != tt.wantDelete {
+				t.Errorf("got failed %v delete %v, want failed %v delete %v", infos["node1"].WorkerPodFailed,
+					infos["node1"].WorkerPodDeleteNeeded, tt.wantFailed, tt.wantDelete)
+			}
+		})
+	}
+}
+
 func hasCommonElement(a []string, b map[string]struct{}) bool {
 	if len(a) != len(b) {
 		return false
@@ -22,11 +22,14 @@ import (
 	"fmt"
 	"hash"
 	"hash/fnv"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/rand"
@@ -34,6 +37,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/maintenancewindow"
 	nodeutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/node"
 	podutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/pod"
 )
@@ -116,9 +121,39 @@ func NodeReadyByName(c client.Client, nodeName string) (bool, error) {
 		return false, err
 	}
 
+	return NodeReady(node), nil
+}
+
+// NodeReady check if the given node is ready
+func NodeReady(node *corev1.Node) bool {
 	_, nc := nodeutil.GetNodeCondition(&node.Status, corev1.NodeReady)
 
-	return nc != nil && nc.Status == corev1.ConditionTrue, nil
+	return nc != nil && nc.Status == corev1.ConditionTrue
+}
+
+// IsCanaryNode check if the given node is selected by the canary strategy,
+// either by node labels or by the NodePool it belongs to
+func IsCanaryNode(canary *appsv1alpha1.YurtStaticSetCanaryStrategy, node *corev1.Node) (bool, error) {
+	if canary == nil {
+		return false, nil
+	}
+
+	if pool, ok := node.Labels[projectinfo.GetNodePoolLabel()]; ok && slices.Contains(canary.NodePools, pool) {
+		return true, nil
+	}
+
+	if canary.NodeSelector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(canary.NodeSelector)
+	if err != nil {
+		return false, err
+	}
+	// an empty selector should not select all nodes as canary nodes
+	if selector.Empty() {
+		return false, nil
+	}
+	return selector.Matches(labels.Set(node.Labels)), nil
 }
 
 // SetPodUpgradeCondition set pod condition `PodNeedUpgrade` to the specified value
@@ -135,3 +170,21 @@ func SetPodUpgradeCondition(c client.Client, status corev1.ConditionStatus, pod
 
 	return nil
 }
+
+// SetPodScheduledCondition set pod condition `PodUpgradeScheduled`, it's true when the upgrade
+// of pod is waiting for the maintenance window which opens at scheduledAt
+func SetPodScheduledCondition(c client.Client, waiting bool, scheduledAt time.Time, pod *corev1.Pod) error {
+	// avoid adding the condition to pods which are never deferred
+	if _, old := podutil.GetPodCondition(&pod.Status, maintenancewindow.PodUpgradeScheduled); old == nil && !waiting {
+		return nil
+	}
+
+	cond := maintenancewindow.NewScheduledCondition(waiting, scheduledAt)
+	if change := podutil.UpdatePodCondition(&pod.Status, cond); change {
+		if err := c.Status().Update(context.TODO(), pod, &client.SubResourceUpdateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
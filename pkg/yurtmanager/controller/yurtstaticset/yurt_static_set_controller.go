@@ -19,7 +19,10 @@ package yurtstaticset
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	kerr "k8s.io/apimachinery/pkg/api/errors"
@@ -43,6 +46,7 @@ import (
 	appconfig "github.com/openyurtio/openyurt/cmd/yurt-manager/app/config"
 	"github.com/openyurtio/openyurt/cmd/yurt-manager/names"
 	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
+	upgradeutil "github.com/openyurtio/openyurt/pkg/node-servant/static-pod-upgrade/util"
 	nodeutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/node"
 	podutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/pod"
 	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/yurtstaticset/config"
@@ -69,6 +73,8 @@ const (
 	UpgradeWorkerContainerName = "upgrade-worker"
 
 	ArgTmpl = "/usr/local/bin/node-servant static-pod-upgrade --name=%s --namespace=%s --manifest=%s --hash=%s --mode=%s"
+	// HealthCheckArgTmpl is appended to ArgTmpl if the health check of new static pod is specified
+	HealthCheckArgTmpl = " --health-check=%s"
 )
 
 // upgradeWorker is the pod template used for static pod upgrade
@@ -97,7 +103,8 @@ var (
 						MountPath: configMapVolumeMountPath,
 					},
 				},
-				ImagePullPolicy: corev1.PullIfNotPresent,
+				ImagePullPolicy:          corev1.PullIfNotPresent,
+				TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 				SecurityContext: &corev1.SecurityContext{
 					Privileged: &True,
 				},
@@ -266,6 +273,7 @@ func nodeTurnReady(evt event.UpdateEvent) bool {
 //+kubebuilder:rbac:groups=core,resources=pods/status,verbs=update;patch
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;update;patch
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.openyurt.io,resources=nodepools,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a YurtStaticSet object and makes changes based on the state read
 // and what is in the YurtStaticSet.Spec
@@ -334,17 +342,14 @@ func (r *ReconcileYurtStaticSet) Reconcile(_ context.Context, request reconcile.
 	// The later upgrade operation is conducted based on upgradeInfos
 	upgradeInfos, err := upgradeinfo.New(r.Client, instance, UpgradeWorkerPodPrefix, latestHash)
 	if err != nil {
-		// The worker pod is failed, then some irreparable failure has occurred. Just stop reconcile and update status
-		if strings.Contains(err.Error(), "could not init worker pod") {
-			r.recorder.Eventf(instance, corev1.EventTypeWarning, "YurtStaticSet Upgrade Failed", err.Error())
-			klog.Error(err.Error())
-			return reconcile.Result{}, err
-		}
-
 		klog.Error(Format("could not get static pod and worker pod upgrade info for nodes of YurtStaticSet %v, %v",
 			request.NamespacedName, err))
 		return ctrl.Result{}, err
 	}
+	oldNodeStatuses := instance.Status.NodeStatuses
+	setNodeStatuses(instance, upgradeInfos, latestHash)
+	r.recordNodeEvents(instance, oldNodeStatuses)
+
 	totalNumber = int32(len(upgradeInfos))
 	// There are no nodes running target static pods in the cluster
 	if totalNumber == 0 {
@@ -368,6 +373,14 @@ func (r *ReconcileYurtStaticSet) Reconcile(_ context.Context, request reconcile.
 		return r.updateYurtStaticSetStatus(instance, totalNumber, readyNumber, upgradedNumber)
 	}
 
+	// The worker pod is failed, then some irreparable failure has occurred. Just stop upgrade and update status
+	if failedNodes := upgradeinfo.FailedWorkerNodes(upgradeInfos); len(failedNodes) != 0 {
+		r.recorder.Eventf(instance, corev1.EventTypeWarning, "YurtStaticSet Upgrade Failed",
+			"upgrade worker failed on nodes %v", failedNodes)
+		klog.Error(Format("Stop upgrade of YurtStaticSet %v, cause upgrade worker failed on nodes %v", request.NamespacedName, failedNodes))
+		return r.updateYurtStaticSetStatus(instance, totalNumber, readyNumber, upgradedNumber)
+	}
+
 	switch strings.ToLower(string(instance.Spec.UpgradeStrategy.Type)) {
 	// AdvancedRollingUpdate Upgrade is to automate the upgrade process for the target static pods on ready nodes
 	// It supports rolling update and the max-unavailable number can be specified by users
@@ -377,11 +390,26 @@ func (r *ReconcileYurtStaticSet) Reconcile(_ context.Context, request reconcile.
 			return r.updateYurtStaticSetStatus(instance, totalNumber, readyNumber, upgradedNumber)
 		}
 
-		if err := r.advancedRollingUpdate(instance, upgradeInfos, latestHash); err != nil {
+		requeueAfter, err := r.advancedRollingUpdate(instance, upgradeInfos, latestHash, upgradedNumber)
+		if err != nil {
 			klog.Error(Format("could not AdvancedRollingUpdate upgrade of YurtStaticSet %v, %v", request.NamespacedName, err))
 			return ctrl.Result{}, err
 		}
-		return r.updateYurtStaticSetStatus(instance, totalNumber, readyNumber, upgradedNumber)
+		windowRequeueAfter, err := r.syncScheduledConditions(upgradeInfos, time.Now())
+		if err != nil {
+			klog.Error(Format("could not set scheduled condition of YurtStaticSet %v, %v", request.NamespacedName, err))
+			return ctrl.Result{}, err
+		}
+		for _, after := range []time.Duration{windowRequeueAfter, postponedRequeueAfter(upgradeInfos, time.Now())} {
+			if requeueAfter == 0 || (after > 0 && after < requeueAfter) {
+				requeueAfter = after
+			}
+		}
+		result, err := r.updateYurtStaticSetStatus(instance, totalNumber, readyNumber, upgradedNumber)
+		if err == nil && requeueAfter > 0 {
+			result.RequeueAfter = requeueAfter
+		}
+		return result, err
 
 	// OTA Upgrade can help users control the timing of static pods upgrade
 	// It will set PodNeedUpgrade condition and work with YurtHub component
@@ -390,14 +418,33 @@ func (r *ReconcileYurtStaticSet) Reconcile(_ context.Context, request reconcile.
 			klog.Error(Format("could not OTA upgrade of YurtStaticSet %v, %v", request.NamespacedName, err))
 			return ctrl.Result{}, err
 		}
-		return r.updateYurtStaticSetStatus(instance, totalNumber, readyNumber, upgradedNumber)
+		requeueAfter, err := r.syncScheduledConditions(upgradeInfos, time.Now())
+		if err != nil {
+			klog.Error(Format("could not set scheduled condition of YurtStaticSet %v, %v", request.NamespacedName, err))
+			return ctrl.Result{}, err
+		}
+		result, err := r.updateYurtStaticSetStatus(instance, totalNumber, readyNumber, upgradedNumber)
+		if err == nil && requeueAfter > 0 {
+			result.RequeueAfter = requeueAfter
+		}
+		return result, err
 	}
 
 	return ctrl.Result{}, nil
 }
 
-// syncConfigMap moves the target yurtstaticset's corresponding configmap to the latest state
+// syncConfigMap moves the target yurtstaticset's corresponding configmap to the latest state.
+// The health check is set on the configmap as well, so it can be performed by YurtHub in OTA mode.
 func (r *ReconcileYurtStaticSet) syncConfigMap(instance *appsv1alpha1.YurtStaticSet, hash, data string) error {
+	var healthCheck string
+	if hc := instance.Spec.UpgradeStrategy.HealthCheck; hc != nil {
+		encoded, err := upgradeutil.EncodeHealthCheck(hc)
+		if err != nil {
+			return err
+		}
+		healthCheck = encoded
+	}
+
 	cmName := util.WithConfigMapPrefix(instance.Name)
 	cm := &corev1.ConfigMap{}
 	if err := r.Get(context.TODO(), types.NamespacedName{Name: cmName, Namespace: instance.Namespace}, cm); err != nil {
@@ -416,6 +463,9 @@ func (r *ReconcileYurtStaticSet) syncConfigMap(instance *appsv1alpha1.YurtStatic
 					instance.Spec.StaticPodManifest: data,
 				},
 			}
+			if len(healthCheck) != 0 {
+				cm.Annotations[upgradeutil.StaticPodHealthCheckAnnotation] = healthCheck
+			}
 			if err := r.Create(context.TODO(), cm, &client.CreateOptions{}); err != nil {
 				return err
 			}
@@ -424,9 +474,20 @@ func (r *ReconcileYurtStaticSet) syncConfigMap(instance *appsv1alpha1.YurtStatic
 		return err
 	}
 
-	// if the hash value in the annotation of the cm does not match the latest hash, then update the data in the cm
-	if cm.Annotations[StaticPodHashAnnotation] != hash {
+	// if the hash value or the health check in the annotations of the cm does not match the latest one, then update the cm
+	if cm.Annotations[StaticPodHashAnnotation] != hash || cm.Annotations[upgradeutil.StaticPodHealthCheckAnnotation] != healthCheck {
+		if cm.Annotations == nil {
+			cm.Annotations = map[string]string{}
+		}
 		cm.Annotations[StaticPodHashAnnotation] = hash
+		if len(healthCheck) != 0 {
+			cm.Annotations[upgradeutil.StaticPodHealthCheckAnnotation] = healthCheck
+		} else {
+			delete(cm.Annotations, upgradeutil.StaticPodHealthCheckAnnotation)
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
 		cm.Data[instance.Spec.StaticPodManifest] = data
 
 		if err := r.Update(context.TODO(), cm, &client.UpdateOptions{}); err != nil {
@@ -437,20 +498,56 @@ func (r *ReconcileYurtStaticSet) syncConfigMap(instance *appsv1alpha1.YurtStatic
 	return nil
 }
 
-// advancedRollingUpdate automatically rolling upgrade the target static pods in cluster
-func (r *ReconcileYurtStaticSet) advancedRollingUpdate(instance *appsv1alpha1.YurtStaticSet, infos map[string]*upgradeinfo.UpgradeInfo, hash string) error {
+// advancedRollingUpdate automatically rolling upgrade the target static pods in cluster.
+// The canary nodes are upgraded first, and the other nodes are upgraded only after the canary
+// is approved or passes the health gate. At most `total - partition` nodes will be upgraded.
+// It returns a positive duration if the reconciliation should be retried after it.
+func (r *ReconcileYurtStaticSet) advancedRollingUpdate(instance *appsv1alpha1.YurtStaticSet, infos map[string]*upgradeinfo.UpgradeInfo,
+	hash string, upgradedNumber int32) (time.Duration, error) {
+	strategy := &instance.Spec.UpgradeStrategy
+	instance.Status.Paused = false
+
 	// readyUpgradeWaitingNodes represents nodes that need to create worker pods
 	readyUpgradeWaitingNodes := upgradeinfo.ReadyUpgradeWaitingNodes(infos)
 
+	if strategy.Canary != nil {
+		if canaryNodes := upgradeinfo.CanaryUpgradeNeededNodes(infos); len(canaryNodes) != 0 {
+			// only canary nodes can be upgraded until all of them are running the latest static pods
+			readyUpgradeWaitingNodes = slices.DeleteFunc(readyUpgradeWaitingNodes, func(node string) bool {
+				return !infos[node].Canary
+			})
+		} else {
+			promoted, after := canaryPromoted(strategy.Canary, infos, hash, time.Now())
+			if !promoted {
+				klog.V(4).Info(Format("Canary nodes of YurtStaticSet %s/%s are upgraded, wait for approval of revision %s",
+					instance.Namespace, instance.Name, hash))
+				instance.Status.Paused = true
+				return after, nil
+			}
+		}
+	}
+
+	// the nodes kept on the old revision by partition are not upgraded, and the nodes whose
+	// worker pods are still running will be upgraded soon, so both are excluded from the quota
+	if strategy.Partition != nil {
+		remaining := int(int32(len(infos)) - *strategy.Partition - upgradedNumber - upgradingNumber(infos))
+		if remaining <= 0 {
+			return 0, nil
+		}
+		if remaining < len(readyUpgradeWaitingNodes) {
+			readyUpgradeWaitingNodes = readyUpgradeWaitingNodes[:remaining]
+		}
+	}
+
 	waitingNumber := len(readyUpgradeWaitingNodes)
 	if waitingNumber == 0 {
-		return nil
+		return 0, nil
 	}
 
 	// max is the maximum number of nodes can be upgraded in current round in AdvancedRollingUpdate upgrade mode
-	max, err := util.UnavailableCount(&instance.Spec.UpgradeStrategy, len(infos))
+	max, err := util.UnavailableCount(strategy, len(infos))
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if waitingNumber < max {
@@ -460,9 +557,56 @@ func (r *ReconcileYurtStaticSet) advancedRollingUpdate(instance *appsv1alpha1.Yu
 	readyUpgradeWaitingNodes = readyUpgradeWaitingNodes[:max]
 	if err := createUpgradeWorker(r.Client, instance, readyUpgradeWaitingNodes, hash,
 		string(appsv1alpha1.AdvancedRollingUpdateUpgradeStrategyType), r.Configuration.UpgradeWorkerImage); err != nil {
-		return err
+		return 0, err
 	}
-	return nil
+	return 0, nil
+}
+
+// upgradingNumber returns the number of nodes which are not upgraded yet but have running worker pods of
+// the latest revision, the outdated worker pods will be deleted, so their nodes are not counted.
+func upgradingNumber(infos map[string]*upgradeinfo.UpgradeInfo) int32 {
+	var n int32
+	for _, info := range infos {
+		if info.UpgradeNeeded && info.WorkerPodRunning && !info.WorkerPodDeleteNeeded {
+			n++
+		}
+	}
+	return n
+}
+
+// canaryPromoted checks whether the rollout can continue to the non-canary nodes. The rollout is promoted
+// if the latest revision is approved, or all canary static pods have been ready for AutoPromoteSeconds.
+// If the health gate is not passed yet, it also returns the duration to wait for the gate.
+func canaryPromoted(canary *appsv1alpha1.YurtStaticSetCanaryStrategy, infos map[string]*upgradeinfo.UpgradeInfo,
+	hash string, now time.Time) (bool, time.Duration) {
+	if canary.ApprovedRevision == hash {
+		return true, 0
+	}
+
+	if canary.AutoPromoteSeconds == nil {
+		return false, 0
+	}
+
+	var readySince time.Time
+	for _, info := range infos {
+		if !info.Canary || info.StaticPod == nil {
+			continue
+		}
+		_, cond := podutil.GetPodCondition(&info.StaticPod.Status, corev1.PodReady)
+		if !info.StaticPodReady || cond == nil {
+			// the health gate is checked again when the canary static pod changes
+			return false, 0
+		}
+		if cond.LastTransitionTime.Time.After(readySince) {
+			readySince = cond.LastTransitionTime.Time
+		}
+	}
+
+	wait := readySince.Add(time.Duration(*canary.AutoPromoteSeconds) * time.Second).Sub(now)
+	if wait > 0 {
+		return false, wait
+	}
+	return true, 0
 }
 
 // otaUpgrade adds condition PodNeedUpgrade to the target static pods
@@ -486,6 +630,44 @@ func (r *ReconcileYurtStaticSet) otaUpgrade(infos map[string]*upgradeinfo.Upgrad
 	return nil
 }
 
+// syncScheduledConditions sets condition PodUpgradeScheduled of the static pods whose upgrade is waiting for
+// the maintenance window, and returns the duration until the earliest maintenance window opens.
+func (r *ReconcileYurtStaticSet) syncScheduledConditions(infos map[string]*upgradeinfo.UpgradeInfo, now time.Time) (time.Duration, error) {
+	var requeueAfter time.Duration
+	for _, info := range infos {
+		if info.StaticPod == nil {
+			continue
+		}
+		waiting := info.UpgradeNeeded && info.WaitingForMaintenanceWindow
+		if err := util.SetPodScheduledCondition(r.Client, waiting, info.ScheduledAt, info.StaticPod); err != nil {
+			return 0, err
+		}
+		if !waiting {
+			continue
+		}
+		// requeue a little later than the window opens to make sure it's open
+		if after := info.ScheduledAt.Sub(now) + time.Second; requeueAfter == 0 || after < requeueAfter {
+			requeueAfter = after
+		}
+	}
+	return requeueAfter, nil
+}
+
+// postponedRequeueAfter returns the duration until the earliest postponed upgrade of static pods is allowed,
+// the cancellation of a postponement is observed by watching the static pods.
+func postponedRequeueAfter(infos map[string]*upgradeinfo.UpgradeInfo, now time.Time) time.Duration {
+	var requeueAfter time.Duration
+	for _, info := range infos {
+		if !info.UpgradeNeeded || !info.Postponed {
+			continue
+		}
+		if after := info.PostponedUntil.Sub(now) + time.Second; requeueAfter == 0 || after < requeueAfter {
+			requeueAfter = after
+		}
+	}
+	return requeueAfter
+}
+
 // removeUnusedPods delete pods, include two situations: out-of-date worker pods and succeeded worker pods
 func (r *ReconcileYurtStaticSet) removeUnusedPods(pods []*corev1.Pod) error {
 	for _, pod := range pods {
@@ -515,8 +697,16 @@ func createUpgradeWorker(c client.Client, instance *appsv1alpha1.YurtStaticSet,
 				},
 			},
 		})
-		pod.Spec.Containers[0].Args = []string{fmt.Sprintf(ArgTmpl, util.Hyphen(instance.Name, node), instance.Namespace,
-			instance.Spec.StaticPodManifest, hash, mode)}
+		args := fmt.Sprintf(ArgTmpl, util.Hyphen(instance.Name, node), instance.Namespace,
+			instance.Spec.StaticPodManifest, hash, mode)
+		if hc := instance.Spec.UpgradeStrategy.HealthCheck; hc != nil {
+			encoded, err := upgradeutil.EncodeHealthCheck(hc)
+			if err != nil {
+				return err
+			}
+			args += fmt.Sprintf(HealthCheckArgTmpl, encoded)
+		}
+		pod.Spec.Containers[0].Args = []string{args}
 		pod.Spec.Containers[0].Image = img
 		if err := controllerutil.SetControllerReference(instance, pod, c.Scheme()); err != nil {
 			return err
@@ -544,6 +734,115 @@ func (r *ReconcileYurtStaticSet) updateYurtStaticSetStatus(instance *appsv1alpha
 	return reconcile.Result{}, nil
 }
 
+// setNodeStatuses records the upgrade progress on each node into the status of instance
+func setNodeStatuses(instance *appsv1alpha1.YurtStaticSet, infos map[string]*upgradeinfo.UpgradeInfo, hash string) {
+	var canaryNumber int32
+	nodeStatuses := make([]appsv1alpha1.YurtStaticSetNodeStatus, 0, len(infos))
+	for node, info := range infos {
+		// only nodes that are running the static pod are recorded
+		if info.StaticPod == nil {
+			continue
+		}
+
+		status := appsv1alpha1.YurtStaticSetNodeStatus{
+			NodeName: node,
+			Canary:   info.Canary,
+			Revision: info.StaticPod.Annotations[StaticPodHashAnnotation],
+			Ready:    info.StaticPodReady,
+		}
+		if info.Canary {
+			canaryNumber++
+		}
+		if info.WorkerPod != nil && !info.WorkerPodDeleteNeeded {
+			status.WorkerPod = info.WorkerPod.Name
+		}
+
+		switch {
+		case info.WorkerPodFailed:
+			status.Phase = appsv1alpha1.NodeUpgradeFailed
+			if workerPodExitCode(info.WorkerPod) == upgradeutil.RevertedExitCode {
+				status.Phase = appsv1alpha1.NodeUpgradeReverted
+			}
+			status.WorkerPod = info.WorkerPod.Name
+			status.Message = workerPodFailureMessage(info.WorkerPod)
+		case !info.UpgradeNeeded:
+			status.Phase = appsv1alpha1.NodeUpgradeUpgraded
+		case info.WorkerPodRunning && !info.WorkerPodDeleteNeeded:
+			status.Phase = appsv1alpha1.NodeUpgradeUpgrading
+		case info.WaitingForMaintenanceWindow:
+			status.Phase = appsv1alpha1.NodeUpgradePending
+			status.Message = fmt.Sprintf("waiting for maintenance window, upgrade is scheduled for %s",
+				info.ScheduledAt.Format(time.RFC3339))
+		case info.Postponed:
+			status.Phase = appsv1alpha1.NodeUpgradePending
+			status.Message = fmt.Sprintf("upgrade is postponed until %s", info.PostponedUntil.Format(time.RFC3339))
+		default:
+			status.Phase = appsv1alpha1.NodeUpgradePending
+		}
+		nodeStatuses = append(nodeStatuses, status)
+	}
+	sort.Slice(nodeStatuses, func(i, j int) bool {
+		return nodeStatuses[i].NodeName < nodeStatuses[j].NodeName
+	})
+
+	instance.Status.UpdateRevision = hash
+	instance.Status.CanaryNumber = canaryNumber
+	instance.Status.NodeStatuses = nodeStatuses
+}
+
+// workerPodExitCode gets the exit code of the terminated upgrade worker container, -1 is returned if not terminated
+func workerPodExitCode(pod *corev1.Pod) int32 {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == UpgradeWorkerContainerName && cs.State.Terminated != nil {
+			return cs.State.Terminated.ExitCode
+		}
+	}
+	return -1
+}
+
+// recordNodeEvents records events on nodes whose upgrade phase changed to a final phase
+func (r *ReconcileYurtStaticSet) recordNodeEvents(instance *appsv1alpha1.YurtStaticSet, oldStatuses []appsv1alpha1.YurtStaticSetNodeStatus) {
+	oldPhases := make(map[string]appsv1alpha1.YurtStaticSetNodeUpgradePhase, len(oldStatuses))
+	for _, s := range oldStatuses {
+		oldPhases[s.NodeName] = s.Phase
+	}
+
+	for _, s := range instance.Status.NodeStatuses {
+		if oldPhases[s.NodeName] == s.Phase {
+			continue
+		}
+		// kubelet uses node name as the uid of node when recording node events
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: s.NodeName, UID: types.UID(s.NodeName)}}
+		switch s.Phase {
+		case appsv1alpha1.NodeUpgradeReverted:
+			r.recorder.Eventf(node, corev1.EventTypeWarning, "StaticPodUpgradeReverted",
+				"upgrade of static pod %s/%s to revision %s is reverted: %s", instance.Namespace, instance.Name, instance.Status.UpdateRevision, s.Message)
+		case appsv1alpha1.NodeUpgradeFailed:
+			r.recorder.Eventf(node, corev1.EventTypeWarning, "StaticPodUpgradeFailed",
+				"upgrade of static pod %s/%s to revision %s failed: %s", instance.Namespace, instance.Name, instance.Status.UpdateRevision, s.Message)
+		case appsv1alpha1.NodeUpgradeUpgraded:
+			// only record the event if the node was upgrading in last round
+			if oldPhases[s.NodeName] == appsv1alpha1.NodeUpgradeUpgrading {
+				r.recorder.Eventf(node, corev1.EventTypeNormal, "StaticPodUpgraded",
+					"static pod %s/%s is upgraded to revision %s", instance.Namespace, instance.Name, instance.Status.UpdateRevision)
+			}
+		}
+	}
+}
+
+// workerPodFailureMessage gets the reason of the failed worker pod from its status
+func workerPodFailureMessage(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Terminated != nil && cs.State.Terminated.Message != "" {
+			return cs.State.Terminated.Message
+		}
+	}
+	if pod.Status.Message != "" {
+		return pod.Status.Message
+	}
+	return fmt.Sprintf("upgrade worker pod %s failed", pod.Name)
+}
+
 // deleteConfigMap delete the configMap if YurtStaticSet is deleting
 func (r *ReconcileYurtStaticSet) deleteConfigMap(name, namespace string) error {
 	cmName := util.WithConfigMapPrefix(name)
@@ -18,7 +18,10 @@ package yurtstaticset
 
 import (
 	"context"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,12 +29,17 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
+	upgradeutil "github.com/openyurtio/openyurt/pkg/node-servant/static-pod-upgrade/util"
+	podutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/pod"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/yurtstaticset/upgradeinfo"
 	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/yurtstaticset/util"
 )
 
@@ -218,3 +226,352 @@ func TestReconcileYurtStaticSetDeleteConfigMap(t *testing.T) {
 		})
 	}
 }
+
+func listWorkerNodes(t *testing.T, c client.Client) map[string]struct{} {
+	podList := &corev1.PodList{}
+	if err := c.List(context.TODO(), podList, client.InNamespace(metav1.NamespaceDefault)); err != nil {
+		t.Fatalf("failed to list pods, %v", err)
+	}
+	nodes := make(map[string]struct{})
+	for _, pod := range podList.Items {
+		if strings.HasPrefix(pod.Name, UpgradeWorkerPodPrefix) {
+			nodes[pod.Spec.NodeName] = struct{}{}
+		}
+	}
+	return nodes
+}
+
+func TestReconcileCanaryAndPartition(t *testing.T) {
+	staticPods := prepareStaticPods()
+	for _, pod := range staticPods {
+		pod.SetAnnotations(map[string]string{podutil.ConfigSourceAnnotationKey: "file"})
+	}
+	nodes := prepareNodes()
+	nodes[0].SetLabels(map[string]string{"canary": "true"})
+	maxUnavailable := intstr.FromString("100%")
+	instance := &appsv1alpha1.YurtStaticSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TestStaticPodName,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: appsv1alpha1.YurtStaticSetSpec{
+			StaticPodManifest: "nginx",
+			UpgradeStrategy: appsv1alpha1.YurtStaticSetUpgradeStrategy{
+				Type:           appsv1alpha1.AdvancedRollingUpdateUpgradeStrategyType,
+				MaxUnavailable: &maxUnavailable,
+				Partition:      ptr.To[int32](1),
+				Canary: &appsv1alpha1.YurtStaticSetCanaryStrategy{
+					NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+				},
+			},
+			Template: corev1.PodTemplateSpec{},
+		},
+	}
+	hash := util.ComputeHash(&instance.Spec.Template)
+
+	scheme := runtime.NewScheme()
+	if err := appsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal("Fail to add yurt custom resource")
+	}
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal("Fail to add kubernetes clint-go custom resource")
+	}
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(instance).WithStatusSubresource(instance).
+		WithObjects(staticPods...).WithObjects(nodes...).Build()
+	rsp := ReconcileYurtStaticSet{
+		Client:   c,
+		scheme:   scheme,
+		recorder: record.NewFakeRecorder(10),
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: TestStaticPodName}}
+
+	// 1. only the canary node is upgraded in the first round
+	if _, err := rsp.Reconcile(context.TODO(), req); err != nil {
+		t.Fatalf("failed to reconcile, %v", err)
+	}
+	if got := listWorkerNodes(t, c); len(got) != 1 || got["node1"] != struct{}{} {
+		t.Fatalf("expect worker pod only on canary node1, got %v", got)
+	}
+
+	// 2. the canary node is upgraded, the rollout is paused until approval
+	worker := &corev1.Pod{}
+	workerName := UpgradeWorkerPodPrefix + TestStaticPodName + "-" + util.Hyphen("node1", hash)
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: workerName}, worker); err != nil {
+		t.Fatalf("failed to get worker pod, %v", err)
+	}
+	worker.Status.Phase = corev1.PodSucceeded
+	if err := c.Status().Update(context.TODO(), worker); err != nil {
+		t.Fatalf("failed to update worker pod, %v", err)
+	}
+	canaryPod := staticPods[0].(*corev1.Pod)
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(canaryPod), canaryPod); err != nil {
+		t.Fatalf("failed to get static pod, %v", err)
+	}
+	metav1.SetMetaDataAnnotation(&canaryPod.ObjectMeta, StaticPodHashAnnotation, hash)
+	if err := c.Update(context.TODO(), canaryPod); err != nil {
+		t.Fatalf("failed to update static pod, %v", err)
+	}
+
+	if _, err := rsp.Reconcile(context.TODO(), req); err != nil {
+		t.Fatalf("failed to reconcile, %v", err)
+	}
+	if got := listWorkerNodes(t, c); len(got) != 0 {
+		t.Fatalf("expect no worker pods while paused, got %v", got)
+	}
+	if err := c.Get(context.TODO(), req.NamespacedName, instance); err != nil {
+		t.Fatalf("failed to get YurtStaticSet, %v", err)
+	}
+	if !instance.Status.Paused || instance.Status.UpdateRevision != hash || instance.Status.CanaryNumber != 1 {
+		t.Fatalf("unexpected status %+v", instance.Status)
+	}
+	if len(instance.Status.NodeStatuses) != 4 || instance.Status.NodeStatuses[0].Phase != appsv1alpha1.NodeUpgradeUpgraded ||
+		instance.Status.NodeStatuses[1].Phase != appsv1alpha1.NodeUpgradePending {
+		t.Fatalf("unexpected node statuses %+v", instance.Status.NodeStatuses)
+	}
+
+	// 3. after approval, the other nodes are upgraded except the one kept by partition
+	instance.Spec.UpgradeStrategy.Canary.ApprovedRevision = hash
+	if err := c.Update(context.TODO(), instance); err != nil {
+		t.Fatalf("failed to update YurtStaticSet, %v", err)
+	}
+	if _, err := rsp.Reconcile(context.TODO(), req); err != nil {
+		t.Fatalf("failed to reconcile, %v", err)
+	}
+	expect := map[string]struct{}{"node2": {}, "node3": {}}
+	if got := listWorkerNodes(t, c); !reflect.DeepEqual(got, expect) {
+		t.Fatalf("expect worker pods on %v, got %v", expect, got)
+	}
+}
+
+func TestAdvancedRollingUpdatePartitionWithRunningWorker(t *testing.T) {
+	maxUnavailable := intstr.FromString("100%")
+	instance := &appsv1alpha1.YurtStaticSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TestStaticPodName,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: appsv1alpha1.YurtStaticSetSpec{
+			StaticPodManifest: "nginx",
+			UpgradeStrategy: appsv1alpha1.YurtStaticSetUpgradeStrategy{
+				Type:           appsv1alpha1.AdvancedRollingUpdateUpgradeStrategyType,
+				MaxUnavailable: &maxUnavailable,
+				Partition:      ptr.To[int32](1),
+			},
+		},
+	}
+	testcases := map[string]struct {
+		node2  *upgradeinfo.UpgradeInfo
+		expect map[string]struct{}
+	}{
+		// node1 is upgraded and node2 is being upgraded, so only one of node3 and node4 can be upgraded
+		"worker pod of the latest revision is running": {
+			node2:  &upgradeinfo.UpgradeInfo{StaticPod: &corev1.Pod{}, NodeReady: true, UpgradeNeeded: true, WorkerPodRunning: true},
+			expect: map[string]struct{}{"node3": {}},
+		},
+		// the outdated worker pod on node2 will be deleted, so both node3 and node4 can be upgraded
+		"outdated worker pod is running": {
+			node2: &upgradeinfo.UpgradeInfo{StaticPod: &corev1.Pod{}, NodeReady: true, UpgradeNeeded: true, WorkerPodRunning: true,
+				WorkerPodDeleteNeeded: true},
+			expect: map[string]struct{}{"node3": {}, "node4": {}},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			infos := map[string]*upgradeinfo.UpgradeInfo{
+				"node1": {StaticPod: &corev1.Pod{}, NodeReady: true},
+				"node2": tc.node2,
+				"node3": {StaticPod: &corev1.Pod{}, NodeReady: true, UpgradeNeeded: true},
+				"node4": {StaticPod: &corev1.Pod{}, NodeReady: true, UpgradeNeeded: true},
+			}
+
+			scheme := runtime.NewScheme()
+			if err := appsv1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal("Fail to add yurt custom resource")
+			}
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatal("Fail to add kubernetes clint-go custom resource")
+			}
+			c := fakeclient.NewClientBuilder().WithScheme(scheme).Build()
+			rsp := ReconcileYurtStaticSet{
+				Client: c,
+				scheme: scheme,
+			}
+
+			if _, err := rsp.advancedRollingUpdate(instance, infos, "hash", 1); err != nil {
+				t.Fatalf("failed to upgrade, %v", err)
+			}
+			if got := listWorkerNodes(t, c); !reflect.DeepEqual(got, tc.expect) {
+				t.Fatalf("expect worker pods on %v, got %v", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestSyncConfigMapHealthCheck(t *testing.T) {
+	instance := &appsv1alpha1.YurtStaticSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TestStaticPodName,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: appsv1alpha1.YurtStaticSetSpec{
+			StaticPodManifest: "nginx",
+			UpgradeStrategy: appsv1alpha1.YurtStaticSetUpgradeStrategy{
+				Type: appsv1alpha1.OTAUpgradeStrategyType,
+				HealthCheck: &appsv1alpha1.YurtStaticSetHealthCheck{
+					ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(80)}},
+				},
+			},
+		},
+	}
+
+	c := fakeclient.NewClientBuilder().Build()
+	r := &ReconcileYurtStaticSet{Client: c}
+	cmKey := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: util.WithConfigMapPrefix(TestStaticPodName)}
+	for _, hc := range []*appsv1alpha1.YurtStaticSetHealthCheck{instance.Spec.UpgradeStrategy.HealthCheck, nil} {
+		instance.Spec.UpgradeStrategy.HealthCheck = hc
+		if err := r.syncConfigMap(instance, "hash", "data"); err != nil {
+			t.Fatalf("failed to sync configmap, %v", err)
+		}
+
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(context.TODO(), cmKey, cm); err != nil {
+			t.Fatalf("failed to get configmap, %v", err)
+		}
+		encoded := cm.Annotations[upgradeutil.StaticPodHealthCheckAnnotation]
+		if hc == nil {
+			if len(encoded) != 0 {
+				t.Errorf("expect no health check on configmap, but got %s", encoded)
+			}
+			continue
+		}
+		got, err := upgradeutil.DecodeHealthCheck(encoded)
+		if err != nil || !reflect.DeepEqual(got, hc) {
+			t.Errorf("expect health check %v on configmap, but got %v, %v", hc, got, err)
+		}
+	}
+}
+
+func TestCanaryPromoted(t *testing.T) {
+	now := time.Now()
+	readyPod := func(since time.Time) *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+			Type:               corev1.PodReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(since),
+		}}}}
+	}
+	infos := map[string]*upgradeinfo.UpgradeInfo{
+		"node1": {StaticPod: readyPod(now.Add(-30 * time.Second)), StaticPodReady: true, Canary: true},
+		"node2": {StaticPod: readyPod(now.Add(-90 * time.Second)), StaticPodReady: true, Canary: true},
+		"node3": {StaticPod: &corev1.Pod{}, UpgradeNeeded: true},
+	}
+
+	tests := []struct {
+		name         string
+		canary       *appsv1alpha1.YurtStaticSetCanaryStrategy
+		wantPromoted bool
+		wantWait     time.Duration
+	}{
+		{
+			name:         "approved revision",
+			canary:       &appsv1alpha1.YurtStaticSetCanaryStrategy{ApprovedRevision: "hash"},
+			wantPromoted: true,
+		},
+		{
+			name:   "stale approved revision without health gate",
+			canary: &appsv1alpha1.YurtStaticSetCanaryStrategy{ApprovedRevision: "old"},
+		},
+		{
+			name:         "health gate passed",
+			canary:       &appsv1alpha1.YurtStaticSetCanaryStrategy{AutoPromoteSeconds: ptr.To[int32](20)},
+			wantPromoted: true,
+		},
+		{
+			name:     "health gate not passed",
+			canary:   &appsv1alpha1.YurtStaticSetCanaryStrategy{AutoPromoteSeconds: ptr.To[int32](60)},
+			wantWait: 30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promoted, wait := canaryPromoted(tt.canary, infos, "hash", now)
+			if promoted != tt.wantPromoted || wait != tt.wantWait {
+				t.Errorf("canaryPromoted() = %v, %v, want %v, %v", promoted, wait, tt.wantPromoted, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestSetNodeStatusesAndEvents(t *testing.T) {
+	instance := &appsv1alpha1.YurtStaticSet{
+		ObjectMeta: metav1.ObjectMeta{Name: TestStaticPodName, Namespace: metav1.NamespaceDefault},
+		Status: appsv1alpha1.YurtStaticSetStatus{NodeStatuses: []appsv1alpha1.YurtStaticSetNodeStatus{
+			{NodeName: "node1", Phase: appsv1alpha1.NodeUpgradeUpgrading},
+			{NodeName: "node2", Phase: appsv1alpha1.NodeUpgradeUpgrading},
+		}},
+	}
+	workerPod := func(exitCode int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: UpgradeWorkerContainerName,
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: exitCode,
+						Message:  "health check failed",
+					}},
+				}},
+			},
+		}
+	}
+	infos := map[string]*upgradeinfo.UpgradeInfo{
+		"node1": {StaticPod: &corev1.Pod{}, UpgradeNeeded: true, WorkerPod: workerPod(upgradeutil.RevertedExitCode), WorkerPodFailed: true},
+		"node2": {StaticPod: &corev1.Pod{}, UpgradeNeeded: true, WorkerPod: workerPod(1), WorkerPodFailed: true},
+	}
+
+	oldStatuses := instance.Status.NodeStatuses
+	setNodeStatuses(instance, infos, "hash")
+	if got := instance.Status.NodeStatuses[0]; got.Phase != appsv1alpha1.NodeUpgradeReverted || got.Message != "health check failed" {
+		t.Errorf("unexpected status of node1 %+v", got)
+	}
+	if got := instance.Status.NodeStatuses[1]; got.Phase != appsv1alpha1.NodeUpgradeFailed {
+		t.Errorf("unexpected status of node2 %+v", got)
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileYurtStaticSet{recorder: recorder}
+	r.recordNodeEvents(instance, oldStatuses)
+	r.recordNodeEvents(instance, instance.Status.NodeStatuses)
+	if len(recorder.Events) != 2 {
+		t.Errorf("expect 2 node events, got %d", len(recorder.Events))
+	}
+	if e := <-recorder.Events; !strings.Contains(e, "StaticPodUpgradeReverted") {
+		t.Errorf("unexpected event %s", e)
+	}
+}
+
+func TestPostponedStaticPod(t *testing.T) {
+	now := time.Now()
+	instance := &appsv1alpha1.YurtStaticSet{
+		ObjectMeta: metav1.ObjectMeta{Name: TestStaticPodName, Namespace: metav1.NamespaceDefault},
+	}
+	infos := map[string]*upgradeinfo.UpgradeInfo{
+		"node1": {StaticPod: &corev1.Pod{}, NodeReady: true, UpgradeNeeded: true, Postponed: true, PostponedUntil: now.Add(time.Hour)},
+		"node2": {StaticPod: &corev1.Pod{}, NodeReady: true, UpgradeNeeded: true, Postponed: true, PostponedUntil: now.Add(2 * time.Hour)},
+		"node3": {StaticPod: &corev1.Pod{}, NodeReady: true, Postponed: true, PostponedUntil: now.Add(time.Minute)},
+	}
+
+	setNodeStatuses(instance, infos, "hash")
+	if got := instance.Status.NodeStatuses[0]; got.Phase != appsv1alpha1.NodeUpgradePending ||
+		!strings.Contains(got.Message, "upgrade is postponed until") {
+		t.Errorf("unexpected status of node1 %+v", got)
+	}
+
+	// the static pod which is up-to-date doesn't need to be requeued
+	if got := postponedRequeueAfter(infos, now); got != time.Hour+time.Second {
+		t.Errorf("expect requeue after %v, got %v", time.Hour+time.Second, got)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	yurtClient "github.com/openyurtio/openyurt/cmd/yurt-manager/app/client"
+	"github.com/openyurtio/openyurt/cmd/yurt-manager/names"
+	"github.com/openyurtio/openyurt/pkg/apis/network/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/webhook/util"
+)
+
+// SetupWebhookWithManager sets up Cluster webhooks. mutate path, validate path, error
+func (webhook *AddressPoolHandler) SetupWebhookWithManager(mgr ctrl.Manager) (string, string, error) {
+	// init
+	webhook.Client = yurtClient.GetClientByControllerNameOrDie(mgr, names.LoadBalancerSetController)
+
+	return util.RegisterWebhook(mgr, &v1alpha1.AddressPool{}, webhook)
+}
+
+// +kubebuilder:webhook:path=/validate-network-openyurt-io-v1alpha1-addresspool,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1,groups=network.openyurt.io,resources=addresspools,verbs=create;update,versions=v1alpha1,name=validate.network.v1alpha1.addresspool.openyurt.io
+
+// AddressPoolHandler implements a validating webhook for AddressPool.
+type AddressPoolHandler struct {
+	Client client.Client
+}
+
+var _ webhook.CustomValidator = &AddressPoolHandler{}
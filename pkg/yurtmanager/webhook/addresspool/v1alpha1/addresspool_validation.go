@@ -0,0 +1,174 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openyurtio/openyurt/pkg/apis/network/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/controller/loadbalancerset/loadbalancerset"
+)
+
+const (
+	AddressPoolKind = "AddressPool"
+)
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (webhook *AddressPoolHandler) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	ap, ok := obj.(*v1alpha1.AddressPool)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an AddressPool but got a %T", obj))
+	}
+
+	return nil, webhook.validate(ctx, ap)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (webhook *AddressPoolHandler) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	newAP, ok := newObj.(*v1alpha1.AddressPool)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an AddressPool but got a %T", newObj))
+	}
+	if _, ok := oldObj.(*v1alpha1.AddressPool); !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an AddressPool but got a %T", oldObj))
+	}
+
+	return nil, webhook.validate(ctx, newAP)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (webhook *AddressPoolHandler) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (webhook *AddressPoolHandler) validate(ctx context.Context, ap *v1alpha1.AddressPool) error {
+	allErrs := validateAddressPoolSpec(&ap.Spec)
+	if len(allErrs) == 0 {
+		var pools v1alpha1.AddressPoolList
+		if err := webhook.Client.List(ctx, &pools); err != nil {
+			return apierrors.NewInternalError(fmt.Errorf("could not list address pools, %v", err))
+		}
+		allErrs = append(allErrs, validateAddressPoolOverlap(ap, pools.Items)...)
+	}
+
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind(AddressPoolKind).GroupKind(), ap.Name, allErrs)
+	}
+	return nil
+}
+
+// validateAddressPoolSpec validates the AddressPool spec, the addresses should be valid and not overlap
+// with each other.
+func validateAddressPoolSpec(spec *v1alpha1.AddressPoolSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.NodePool == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec").Child("nodePool"), "nodePool is required"))
+	}
+
+	addressesPath := field.NewPath("spec").Child("addresses")
+	if len(spec.Addresses) == 0 {
+		allErrs = append(allErrs, field.Required(addressesPath, "at least one address is required"))
+	}
+
+	ranges := make([]addressRange, 0, len(spec.Addresses))
+	for i, address := range spec.Addresses {
+		r, err := parseAddressRange(address)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(addressesPath.Index(i), address,
+				"should be an IP address, CIDR or range such as 192.168.0.10-192.168.0.20"))
+			continue
+		}
+		for j := range ranges {
+			if ranges[j].overlaps(r) {
+				allErrs = append(allErrs, field.Invalid(addressesPath.Index(i), address,
+					fmt.Sprintf("overlaps with address %s", ranges[j].address)))
+				break
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	return allErrs
+}
+
+// validateAddressPoolOverlap checks that the addresses of the AddressPool don't overlap with other AddressPools,
+// otherwise the same virtual IP may be allocated to PoolServices in different NodePools.
+func validateAddressPoolOverlap(ap *v1alpha1.AddressPool, pools []v1alpha1.AddressPool) field.ErrorList {
+	var allErrs field.ErrorList
+
+	addressesPath := field.NewPath("spec").Child("addresses")
+	for i, address := range ap.Spec.Addresses {
+		r, err := parseAddressRange(address)
+		if err != nil {
+			continue
+		}
+		if other, pool, found := findOverlappedAddress(r, ap.Name, pools); found {
+			allErrs = append(allErrs, field.Invalid(addressesPath.Index(i), address,
+				fmt.Sprintf("overlaps with address %s of address pool %s", other, pool)))
+		}
+	}
+
+	return allErrs
+}
+
+// findOverlappedAddress returns the first address of other AddressPools which overlaps with the address range.
+func findOverlappedAddress(r addressRange, name string, pools []v1alpha1.AddressPool) (string, string, bool) {
+	for _, pool := range pools {
+		if pool.Name == name {
+			continue
+		}
+		for _, address := range pool.Spec.Addresses {
+			o, err := parseAddressRange(address)
+			if err != nil {
+				continue
+			}
+			if r.overlaps(o) {
+				return address, pool.Name, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+type addressRange struct {
+	address     string
+	first, last net.IP
+}
+
+func parseAddressRange(address string) (addressRange, error) {
+	first, last, err := loadbalancerset.ParseAddressRange(address)
+	if err != nil {
+		return addressRange{}, err
+	}
+	return addressRange{address: address, first: first, last: last}, nil
+}
+
+func (r addressRange) overlaps(o addressRange) bool {
+	if len(r.first) != len(o.first) {
+		return false
+	}
+	return bytes.Compare(r.first, o.last) <= 0 && bytes.Compare(o.first, r.last) <= 0
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openyurtio/openyurt/pkg/apis/network/v1alpha1"
+)
+
+func newAddressPool(name, nodePool string, addresses ...string) *v1alpha1.AddressPool {
+	return &v1alpha1.AddressPool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.AddressPoolSpec{
+			NodePool:  nodePool,
+			Addresses: addresses,
+		},
+	}
+}
+
+func TestValidateCreate(t *testing.T) {
+	existing := newAddressPool("hangzhou", "hangzhou", "192.168.0.0/28", "fd00::1-fd00::10")
+
+	testcases := map[string]struct {
+		obj     runtime.Object
+		errCode int
+	}{
+		"it is not an address pool": {
+			obj:     &corev1.Node{},
+			errCode: http.StatusBadRequest,
+		},
+		"valid address pool": {
+			obj:     newAddressPool("beijing", "beijing", "192.168.1.100", "192.168.1.0/28", "192.168.2.1-192.168.2.10"),
+			errCode: 0,
+		},
+		"node pool is missing": {
+			obj:     newAddressPool("beijing", "", "192.168.1.10"),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"addresses are missing": {
+			obj:     newAddressPool("beijing", "beijing"),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"invalid address": {
+			obj:     newAddressPool("beijing", "beijing", "192.168.1.300"),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"reversed address range": {
+			obj:     newAddressPool("beijing", "beijing", "192.168.1.10-192.168.1.1"),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"addresses overlap in the pool": {
+			obj:     newAddressPool("beijing", "beijing", "192.168.1.0/28", "192.168.1.5-192.168.1.20"),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"addresses overlap with other pool": {
+			obj:     newAddressPool("beijing", "beijing", "192.168.0.14-192.168.0.20"),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"ipv6 addresses overlap with other pool": {
+			obj:     newAddressPool("beijing", "beijing", "fd00::10"),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"excluded broadcast address doesn't overlap": {
+			obj:     newAddressPool("beijing", "beijing", "192.168.0.15"),
+			errCode: 0,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			handler := &AddressPoolHandler{
+				Client: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(existing.DeepCopy()).Build(),
+			}
+			_, err := handler.ValidateCreate(context.TODO(), tc.obj)
+			checkErrCode(t, err, tc.errCode)
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	existing := newAddressPool("hangzhou", "hangzhou", "192.168.0.0/28")
+
+	testcases := map[string]struct {
+		oldObj  runtime.Object
+		newObj  runtime.Object
+		errCode int
+	}{
+		"old object is not an address pool": {
+			oldObj:  &corev1.Node{},
+			newObj:  existing,
+			errCode: http.StatusBadRequest,
+		},
+		"update addresses of itself": {
+			oldObj:  existing,
+			newObj:  newAddressPool("hangzhou", "hangzhou", "192.168.0.0/27"),
+			errCode: 0,
+		},
+		"update addresses to overlap with other pool": {
+			oldObj:  newAddressPool("beijing", "beijing", "192.168.1.1"),
+			newObj:  newAddressPool("beijing", "beijing", "192.168.0.1"),
+			errCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			handler := &AddressPoolHandler{
+				Client: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(existing.DeepCopy()).Build(),
+			}
+			_, err := handler.ValidateUpdate(context.TODO(), tc.oldObj, tc.newObj)
+			checkErrCode(t, err, tc.errCode)
+		})
+	}
+}
+
+func checkErrCode(t *testing.T, err error, errCode int) {
+	t.Helper()
+	if errCode == 0 {
+		if err != nil {
+			t.Errorf("expect no error, but got %v", err)
+		}
+		return
+	}
+
+	statusErr, ok := err.(*apierrors.StatusError)
+	if !ok {
+		t.Fatalf("expect a status error with code %d, but got %v", errCode, err)
+	}
+	if int(statusErr.Status().Code) != errCode {
+		t.Errorf("expect error code %d, but got %d: %v", errCode, statusErr.Status().Code, err)
+	}
+}
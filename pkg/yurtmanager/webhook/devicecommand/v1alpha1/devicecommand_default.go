@@ -0,0 +1,59 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+)
+
+// Default satisfies the defaulting webhook interface.
+func (webhook *DeviceCommandHandler) Default(ctx context.Context, obj runtime.Object) error {
+	dc, ok := obj.(*v1alpha1.DeviceCommand)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a DeviceCommand but got a %T", obj))
+	}
+
+	if len(dc.Spec.Action) == 0 {
+		dc.Spec.Action = v1alpha1.DeviceCommandActionSet
+	}
+	if dc.Spec.TimeoutSeconds == nil {
+		timeout := v1alpha1.DefaultDeviceCommandTimeoutSeconds
+		dc.Spec.TimeoutSeconds = &timeout
+	}
+	if dc.Spec.BackoffLimit == nil {
+		backoffLimit := v1alpha1.DefaultDeviceCommandBackoffLimit
+		dc.Spec.BackoffLimit = &backoffLimit
+	}
+
+	// the issuer is always recorded from the request, so it can not be forged by the user
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+	if req.Operation == admissionv1.Create {
+		dc.Spec.Issuer = req.UserInfo.Username
+	}
+	return nil
+}
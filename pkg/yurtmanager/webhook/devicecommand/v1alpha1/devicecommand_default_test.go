@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+)
+
+func newRequestContext(op admissionv1.Operation, username string) context.Context {
+	return admission.NewContextWithRequest(context.TODO(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: op,
+			UserInfo:  authenticationv1.UserInfo{Username: username},
+		},
+	})
+}
+
+func TestDefault(t *testing.T) {
+	testcases := map[string]struct {
+		ctx      context.Context
+		obj      runtime.Object
+		errCode  int
+		expected *v1alpha1.DeviceCommandSpec
+	}{
+		"it is not a devicecommand": {
+			ctx:     newRequestContext(admissionv1.Create, "alice"),
+			obj:     &corev1.Pod{},
+			errCode: http.StatusBadRequest,
+		},
+		"request is not in context": {
+			ctx:     context.TODO(),
+			obj:     &v1alpha1.DeviceCommand{},
+			errCode: http.StatusBadRequest,
+		},
+		"set defaults and issuer on create": {
+			ctx: newRequestContext(admissionv1.Create, "alice"),
+			obj: &v1alpha1.DeviceCommand{
+				Spec: v1alpha1.DeviceCommandSpec{
+					NodePool: "hangzhou",
+					Command:  "switch",
+					Issuer:   "bob",
+				},
+			},
+			expected: &v1alpha1.DeviceCommandSpec{
+				NodePool:       "hangzhou",
+				Command:        "switch",
+				Action:         v1alpha1.DeviceCommandActionSet,
+				TimeoutSeconds: ptr.To(v1alpha1.DefaultDeviceCommandTimeoutSeconds),
+				BackoffLimit:   ptr.To(v1alpha1.DefaultDeviceCommandBackoffLimit),
+				Issuer:         "alice",
+			},
+		},
+		"keep specified values": {
+			ctx: newRequestContext(admissionv1.Create, "alice"),
+			obj: &v1alpha1.DeviceCommand{
+				Spec: v1alpha1.DeviceCommandSpec{
+					NodePool:       "hangzhou",
+					Command:        "switch",
+					Action:         v1alpha1.DeviceCommandActionGet,
+					TimeoutSeconds: ptr.To[int32](5),
+					BackoffLimit:   ptr.To[int32](0),
+				},
+			},
+			expected: &v1alpha1.DeviceCommandSpec{
+				NodePool:       "hangzhou",
+				Command:        "switch",
+				Action:         v1alpha1.DeviceCommandActionGet,
+				TimeoutSeconds: ptr.To[int32](5),
+				BackoffLimit:   ptr.To[int32](0),
+				Issuer:         "alice",
+			},
+		},
+		"issuer is not changed on update": {
+			ctx: newRequestContext(admissionv1.Update, "alice"),
+			obj: &v1alpha1.DeviceCommand{
+				Spec: v1alpha1.DeviceCommandSpec{
+					NodePool:       "hangzhou",
+					Command:        "switch",
+					Action:         v1alpha1.DeviceCommandActionSet,
+					TimeoutSeconds: ptr.To[int32](5),
+					BackoffLimit:   ptr.To[int32](1),
+					Issuer:         "bob",
+				},
+			},
+			expected: &v1alpha1.DeviceCommandSpec{
+				NodePool:       "hangzhou",
+				Command:        "switch",
+				Action:         v1alpha1.DeviceCommandActionSet,
+				TimeoutSeconds: ptr.To[int32](5),
+				BackoffLimit:   ptr.To[int32](1),
+				Issuer:         "bob",
+			},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			w := &DeviceCommandHandler{}
+			err := w.Default(tc.ctx, tc.obj)
+			if tc.errCode != 0 {
+				statusErr, ok := err.(*errors.StatusError)
+				if !ok || tc.errCode != int(statusErr.Status().Code) {
+					t.Errorf("Expected error code %d, got %v", tc.errCode, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			dc := tc.obj.(*v1alpha1.DeviceCommand)
+			if !reflect.DeepEqual(&dc.Spec, tc.expected) {
+				t.Errorf("Expected spec %#v, got %#v", tc.expected, dc.Spec)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/yurtmanager/webhook/util"
+)
+
+const (
+	WebhookName = "devicecommand"
+)
+
+// SetupWebhookWithManager sets up Cluster webhooks. mutate path, validate path, error
+func (webhook *DeviceCommandHandler) SetupWebhookWithManager(mgr ctrl.Manager) (string, string, error) {
+	// iot/v1alpha1 is not added to the scheme of yurt-manager, because PlatformAdmin/v1alpha1 can
+	// not be converted, so only the DeviceCommand kinds are registered here.
+	if !mgr.GetScheme().Recognizes(v1alpha1.GroupVersion.WithKind("DeviceCommand")) {
+		mgr.GetScheme().AddKnownTypes(v1alpha1.GroupVersion, &v1alpha1.DeviceCommand{}, &v1alpha1.DeviceCommandList{})
+	}
+
+	return util.RegisterWebhook(mgr, &v1alpha1.DeviceCommand{}, webhook)
+}
+
+// +kubebuilder:webhook:path=/validate-iot-openyurt-io-v1alpha1-devicecommand,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1,groups=iot.openyurt.io,resources=devicecommands,verbs=create;update,versions=v1alpha1,name=validate.iot.v1alpha1.devicecommand.openyurt.io
+// +kubebuilder:webhook:path=/mutate-iot-openyurt-io-v1alpha1-devicecommand,mutating=true,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1,groups=iot.openyurt.io,resources=devicecommands,verbs=create,versions=v1alpha1,name=mutate.iot.v1alpha1.devicecommand.openyurt.io
+
+// DeviceCommandHandler implements a validating and defaulting webhook for DeviceCommand.
+type DeviceCommandHandler struct{}
+
+var _ webhook.CustomDefaulter = &DeviceCommandHandler{}
+var _ webhook.CustomValidator = &DeviceCommandHandler{}
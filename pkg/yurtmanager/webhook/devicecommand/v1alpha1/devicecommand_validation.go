@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+)
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (webhook *DeviceCommandHandler) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	dc, ok := obj.(*v1alpha1.DeviceCommand)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a DeviceCommand but got a %T", obj))
+	}
+
+	if allErrs := validateDeviceCommandSpec(&dc.Spec); len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("DeviceCommand").GroupKind(), dc.Name, allErrs)
+	}
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (webhook *DeviceCommandHandler) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	newDc, ok := newObj.(*v1alpha1.DeviceCommand)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a DeviceCommand but got a %T", newObj))
+	}
+	oldDc, ok := oldObj.(*v1alpha1.DeviceCommand)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a DeviceCommand but got a %T", oldObj))
+	}
+
+	// a command is a record of what was issued, so its spec can not be changed
+	if !apiequality.Semantic.DeepEqual(newDc.Spec, oldDc.Spec) {
+		allErrs := field.ErrorList{field.Forbidden(field.NewPath("spec"), "spec of DeviceCommand is immutable")}
+		return nil, apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("DeviceCommand").GroupKind(), newDc.Name, allErrs)
+	}
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (webhook *DeviceCommandHandler) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateDeviceCommandSpec(spec *v1alpha1.DeviceCommandSpec) field.ErrorList {
+	var errList field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if len(spec.NodePool) == 0 {
+		errList = append(errList, field.Required(specPath.Child("nodePool"), "nodePool of DeviceCommand is required"))
+	}
+	if len(spec.Command) == 0 {
+		errList = append(errList, field.Required(specPath.Child("command"), "command of DeviceCommand is required"))
+	}
+
+	// the target devices are selected either by name or by labels
+	if len(spec.DeviceName) == 0 && spec.Selector == nil {
+		errList = append(errList, field.Required(specPath.Child("deviceName"), "one of deviceName and selector is required"))
+	} else if len(spec.DeviceName) != 0 && spec.Selector != nil {
+		errList = append(errList, field.Forbidden(specPath.Child("selector"), "deviceName and selector can not be set at the same time"))
+	} else if spec.Selector != nil {
+		errList = append(errList, metav1validation.ValidateLabelSelector(spec.Selector, metav1validation.LabelSelectorValidationOptions{}, specPath.Child("selector"))...)
+	}
+
+	switch spec.Action {
+	case v1alpha1.DeviceCommandActionSet, v1alpha1.DeviceCommandActionGet:
+	default:
+		errList = append(errList, field.NotSupported(specPath.Child("action"), spec.Action,
+			[]string{string(v1alpha1.DeviceCommandActionSet), string(v1alpha1.DeviceCommandActionGet)}))
+	}
+
+	if spec.TimeoutSeconds != nil && *spec.TimeoutSeconds <= 0 {
+		errList = append(errList, field.Invalid(specPath.Child("timeoutSeconds"), *spec.TimeoutSeconds, "timeoutSeconds must be greater than 0"))
+	}
+	if spec.BackoffLimit != nil && *spec.BackoffLimit < 0 {
+		errList = append(errList, field.Invalid(specPath.Child("backoffLimit"), *spec.BackoffLimit, "backoffLimit must not be negative"))
+	}
+	return errList
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/openyurtio/openyurt/pkg/apis/iot/v1alpha1"
+)
+
+func newDeviceCommand(mutate func(spec *v1alpha1.DeviceCommandSpec)) *v1alpha1.DeviceCommand {
+	dc := &v1alpha1.DeviceCommand{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec: v1alpha1.DeviceCommandSpec{
+			NodePool:       "hangzhou",
+			DeviceName:     "sensor",
+			Command:        "switch",
+			Action:         v1alpha1.DeviceCommandActionSet,
+			Value:          "on",
+			TimeoutSeconds: ptr.To[int32](30),
+			BackoffLimit:   ptr.To[int32](3),
+			Issuer:         "alice",
+		},
+	}
+	if mutate != nil {
+		mutate(&dc.Spec)
+	}
+	return dc
+}
+
+func TestValidateCreate(t *testing.T) {
+	testcases := map[string]struct {
+		obj     runtime.Object
+		errCode int
+	}{
+		"it is not a devicecommand": {
+			obj:     &corev1.Pod{},
+			errCode: http.StatusBadRequest,
+		},
+		"valid command on a device": {
+			obj: newDeviceCommand(nil),
+		},
+		"valid command on selected devices": {
+			obj: newDeviceCommand(func(spec *v1alpha1.DeviceCommandSpec) {
+				spec.DeviceName = ""
+				spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"type": "light"}}
+			}),
+		},
+		"nodepool is empty": {
+			obj: newDeviceCommand(func(spec *v1alpha1.DeviceCommandSpec) {
+				spec.NodePool = ""
+			}),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"command is empty": {
+			obj: newDeviceCommand(func(spec *v1alpha1.DeviceCommandSpec) {
+				spec.Command = ""
+			}),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"no target devices": {
+			obj: newDeviceCommand(func(spec *v1alpha1.DeviceCommandSpec) {
+				spec.DeviceName = ""
+			}),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"both device name and selector are set": {
+			obj: newDeviceCommand(func(spec *v1alpha1.DeviceCommandSpec) {
+				spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"type": "light"}}
+			}),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"invalid selector": {
+			obj: newDeviceCommand(func(spec *v1alpha1.DeviceCommandSpec) {
+				spec.DeviceName = ""
+				spec.Selector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "type", Operator: "Unknown"}}}
+			}),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"unsupported action": {
+			obj: newDeviceCommand(func(spec *v1alpha1.DeviceCommandSpec) {
+				spec.Action = "Reset"
+			}),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"invalid timeout": {
+			obj: newDeviceCommand(func(spec *v1alpha1.DeviceCommandSpec) {
+				spec.TimeoutSeconds = ptr.To[int32](0)
+			}),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"negative backoff limit": {
+			obj: newDeviceCommand(func(spec *v1alpha1.DeviceCommandSpec) {
+				spec.BackoffLimit = ptr.To[int32](-1)
+			}),
+			errCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			w := &DeviceCommandHandler{}
+			_, err := w.ValidateCreate(context.TODO(), tc.obj)
+			checkErrCode(t, err, tc.errCode)
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	testcases := map[string]struct {
+		oldObj  runtime.Object
+		newObj  runtime.Object
+		errCode int
+	}{
+		"old object is not a devicecommand": {
+			oldObj:  &corev1.Pod{},
+			newObj:  newDeviceCommand(nil),
+			errCode: http.StatusBadRequest,
+		},
+		"new object is not a devicecommand": {
+			oldObj:  newDeviceCommand(nil),
+			newObj:  &corev1.Pod{},
+			errCode: http.StatusBadRequest,
+		},
+		"spec is not changed": {
+			oldObj: newDeviceCommand(nil),
+			newObj: newDeviceCommand(nil),
+		},
+		"value is changed": {
+			oldObj: newDeviceCommand(nil),
+			newObj: newDeviceCommand(func(spec *v1alpha1.DeviceCommandSpec) {
+				spec.Value = "off"
+			}),
+			errCode: http.StatusUnprocessableEntity,
+		},
+		"issuer is changed": {
+			oldObj: newDeviceCommand(nil),
+			newObj: newDeviceCommand(func(spec *v1alpha1.DeviceCommandSpec) {
+				spec.Issuer = "bob"
+			}),
+			errCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			w := &DeviceCommandHandler{}
+			_, err := w.ValidateUpdate(context.TODO(), tc.oldObj, tc.newObj)
+			checkErrCode(t, err, tc.errCode)
+		})
+	}
+}
+
+func checkErrCode(t *testing.T, err error, errCode int) {
+	t.Helper()
+	if errCode == 0 {
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		return
+	}
+	statusErr, ok := err.(*errors.StatusError)
+	if !ok || errCode != int(statusErr.Status().Code) {
+		t.Errorf("Expected error code %d, got %v", errCode, err)
+	}
+}
@@ -67,18 +67,33 @@ func validate(g *v1beta1.Gateway) (admission.Warnings, error) {
 	var errList field.ErrorList
 
 	if g.Spec.ExposeType != "" {
-		if g.Spec.ExposeType != v1beta1.ExposeTypeLoadBalancer && g.Spec.ExposeType != v1beta1.ExposeTypePublicIP {
-			fldPath := field.NewPath("spec").Child("exposeType")
-			errList = append(errList, field.Invalid(fldPath, g.Spec.ExposeType, "the 'exposeType' field is irregularity"))
-		}
-		if g.Spec.ExposeType == v1beta1.ExposeTypeLoadBalancer || g.Spec.ExposeType == v1beta1.ExposeTypePublicIP {
+		switch g.Spec.ExposeType {
+		case v1beta1.ExposeTypeLoadBalancer, v1beta1.ExposeTypePublicIP, v1beta1.ExposeTypeNodePort, v1beta1.ExposeTypeStaticIPPool:
 			for i, ep := range g.Spec.Endpoints {
 				if ep.UnderNAT {
 					fldPath := field.NewPath("spec").Child(fmt.Sprintf("endpoints[%d]", i)).Child("underNAT")
 					errList = append(errList, field.Invalid(fldPath, ep.UnderNAT, fmt.Sprintf("the 'underNAT' field for exposed gateway %s/%s must be false", g.Namespace, g.Name)))
 				}
 			}
+		default:
+			fldPath := field.NewPath("spec").Child("exposeType")
+			errList = append(errList, field.Invalid(fldPath, g.Spec.ExposeType, "the 'exposeType' field is irregularity"))
+		}
+	}
+
+	if g.Spec.ExposeType == v1beta1.ExposeTypeStaticIPPool {
+		fldPath := field.NewPath("spec").Child("publicIPPool")
+		if len(g.Spec.PublicIPPool) == 0 {
+			errList = append(errList, field.Required(fldPath, fmt.Sprintf("the 'publicIPPool' field must not be empty when the 'exposeType' field is %s", v1beta1.ExposeTypeStaticIPPool)))
+		}
+		for i, item := range g.Spec.PublicIPPool {
+			if err := validateIPOrCIDR(item); err != nil {
+				errList = append(errList, field.Invalid(fldPath.Index(i), item, "the 'publicIPPool' field must be a list of validate IP addresses or CIDRs"))
+			}
 		}
+	} else if len(g.Spec.PublicIPPool) != 0 {
+		fldPath := field.NewPath("spec").Child("publicIPPool")
+		errList = append(errList, field.Forbidden(fldPath, fmt.Sprintf("the 'publicIPPool' field can only be set when the 'exposeType' field is %s", v1beta1.ExposeTypeStaticIPPool)))
 	}
 
 	if g.Spec.TunnelConfig.Replicas > 1 {
@@ -143,3 +158,13 @@ func validateIP(ip string) error {
 	}
 	return fmt.Errorf("invalid ip address: %s", ip)
 }
+
+func validateIPOrCIDR(s string) error {
+	if _, _, err := net.ParseCIDR(s); err == nil {
+		return nil
+	}
+	if net.ParseIP(s) != nil {
+		return nil
+	}
+	return fmt.Errorf("invalid ip address or cidr: %s", s)
+}
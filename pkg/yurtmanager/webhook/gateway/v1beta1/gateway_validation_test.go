@@ -47,6 +47,36 @@ func TestGatewayHandler_ValidateCreate(t *testing.T) {
 			obj:            mockGatewayWithExposeType(v1beta1.ExposeTypeLoadBalancer, true),
 			expectedErrMsg: "the 'underNAT' field for exposed gateway",
 		},
+		{
+			name:           "should return error when NodePort Gateway has underNAT endpoints",
+			obj:            mockGatewayWithExposeType(v1beta1.ExposeTypeNodePort, true),
+			expectedErrMsg: "the 'underNAT' field for exposed gateway",
+		},
+		{
+			name:           "should return error when StaticIPPool Gateway has no public ip pool",
+			obj:            mockGatewayWithPublicIPPool(v1beta1.ExposeTypeStaticIPPool),
+			expectedErrMsg: "the 'publicIPPool' field must not be empty",
+		},
+		{
+			name:           "should return error when StaticIPPool Gateway has invalid public ip pool",
+			obj:            mockGatewayWithPublicIPPool(v1beta1.ExposeTypeStaticIPPool, "1.1.1.1", "1.1.1.0/33"),
+			expectedErrMsg: "the 'publicIPPool' field must be a list of validate IP addresses or CIDRs",
+		},
+		{
+			name:           "should return error when public ip pool is set for LoadBalancer Gateway",
+			obj:            mockGatewayWithPublicIPPool(v1beta1.ExposeTypeLoadBalancer, "1.1.1.1"),
+			expectedErrMsg: "the 'publicIPPool' field can only be set when the 'exposeType' field is StaticIPPool",
+		},
+		{
+			name:           "should pass when StaticIPPool Gateway has valid public ip pool",
+			obj:            mockGatewayWithPublicIPPool(v1beta1.ExposeTypeStaticIPPool, "1.1.1.1", "2.2.2.0/30"),
+			expectedErrMsg: "",
+		},
+		{
+			name:           "should pass when object is a valid NodePort Gateway",
+			obj:            mockGatewayWithExposeType(v1beta1.ExposeTypeNodePort, false),
+			expectedErrMsg: "",
+		},
 		{
 			name:           "should return error when Gateway TunnelConfig.Replicas >1",
 			obj:            mockGatewayWithReplicas(2),
@@ -207,6 +237,13 @@ func mockGatewayWithExposeType(ExposeType string, UnderNAT bool) *v1beta1.Gatewa
 	return g
 }
 
+func mockGatewayWithPublicIPPool(exposeType string, pool ...string) *v1beta1.Gateway {
+	g := mockGateway()
+	g.Spec.ExposeType = exposeType
+	g.Spec.PublicIPPool = pool
+	return g
+}
+
 func mockGatewayWithNameChange() *v1beta1.Gateway {
 	g := mockGateway()
 	g.Name = "new-name"
@@ -28,6 +28,7 @@ import (
 	"github.com/openyurtio/openyurt/pkg/apis/apps"
 	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
 	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	nodepoolutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/nodepool"
 )
 
 // Default satisfies the defaulting webhook interface.
@@ -40,11 +41,20 @@ func (webhook *NodeHandler) Default(ctx context.Context, obj runtime.Object) err
 	npName := node.Labels[projectinfo.GetNodePoolLabel()]
 	if len(npName) == 0 {
 		npName = node.Labels[apps.DesiredNodePoolLabel]
-		if len(npName) != 0 {
-			node.Labels[projectinfo.GetNodePoolLabel()] = npName
-		} else {
-			return nil
+		if len(npName) == 0 {
+			// assign node to the NodePool whose nodeSelector matches the labels of node set at join time
+			var poolList appsv1beta2.NodePoolList
+			if err := webhook.Client.List(ctx, &poolList); err != nil {
+				return err
+			}
+			if npName, _, _ = nodepoolutil.SelectNodePool(node, poolList.Items); len(npName) == 0 {
+				return nil
+			}
 		}
+		if node.Labels == nil {
+			node.Labels = make(map[string]string)
+		}
+		node.Labels[projectinfo.GetNodePoolLabel()] = npName
 	}
 
 	var np appsv1beta2.NodePool
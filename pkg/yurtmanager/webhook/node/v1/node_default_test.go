@@ -37,10 +37,11 @@ import (
 
 func TestDefault(t *testing.T) {
 	testcases := map[string]struct {
-		node    runtime.Object
-		pool    *appsv1beta2.NodePool
-		errCode int
-		errMsg  string
+		node       runtime.Object
+		pool       *appsv1beta2.NodePool
+		errCode    int
+		errMsg     string
+		wantedPool string
 	}{
 		"it is not a node": {
 			node:    &corev1.Pod{},
@@ -62,6 +63,29 @@ func TestDefault(t *testing.T) {
 			errCode: 0,
 			errMsg:  "not found",
 		},
+		"assign node to nodepool by node selector": {
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+					Labels: map[string]string{
+						corev1.LabelTopologyZone: "a",
+					},
+				},
+			},
+			pool:       newZonePool("zone-a", "a"),
+			wantedPool: "zone-a",
+		},
+		"node doesn't match node selector": {
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+					Labels: map[string]string{
+						corev1.LabelTopologyZone: "b",
+					},
+				},
+			},
+			pool: newZonePool("zone-a", "a"),
+		},
 		"add labels for node": {
 			node: &corev1.Node{
 				ObjectMeta: metav1.ObjectMeta{
@@ -80,7 +104,8 @@ func TestDefault(t *testing.T) {
 					HostNetwork: true,
 				},
 			},
-			errCode: 0,
+			wantedPool: "shanghai",
+			errCode:    0,
 		},
 	}
 
@@ -119,6 +144,10 @@ func TestDefault(t *testing.T) {
 			} else if tc.errCode != 0 && len(tc.errMsg) != 0 {
 				t.Errorf("Expected error code %d, errmsg %s, got %v", tc.errCode, tc.errMsg, err)
 			}
+
+			if node, ok := tc.node.(*corev1.Node); ok && node.Labels[projectinfo.GetNodePoolLabel()] != tc.wantedPool && tc.errCode == 0 && len(tc.errMsg) == 0 {
+				t.Errorf("Expected nodepool %q, got %q", tc.wantedPool, node.Labels[projectinfo.GetNodePoolLabel()])
+			}
 		})
 	}
 }
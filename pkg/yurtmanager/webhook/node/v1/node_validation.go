@@ -27,7 +27,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
 	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	nodepoolutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/nodepool"
 )
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
@@ -46,7 +48,12 @@ func (webhook *NodeHandler) ValidateUpdate(ctx context.Context, oldObj, newObj r
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a Node} but got a %T", oldObj))
 	}
 
-	if allErrs := validateNodeUpdate(newNode, oldNode); len(allErrs) > 0 {
+	reassigned, err := webhook.isReassignedBySelector(ctx, newNode, oldNode)
+	if err != nil {
+		return nil, err
+	}
+
+	if allErrs := validateNodeUpdate(newNode, oldNode, reassigned); len(allErrs) > 0 {
 		return nil, apierrors.NewInvalid(v1.SchemeGroupVersion.WithKind("Node").GroupKind(), newNode.Name, allErrs)
 	}
 
@@ -58,15 +65,40 @@ func (webhook *NodeHandler) ValidateDelete(_ context.Context, obj runtime.Object
 	return nil, nil
 }
 
-func validateNodeUpdate(newNode, oldNode *v1.Node) field.ErrorList {
+// isReassignedBySelector returns true if the NodePoolLabel of node is changed to the NodePool
+// which the node should belong to according to the nodeSelector of NodePools.
+func (webhook *NodeHandler) isReassignedBySelector(ctx context.Context, newNode, oldNode *v1.Node) (bool, error) {
+	oldNp := oldNode.Labels[projectinfo.GetNodePoolLabel()]
+	newNp := newNode.Labels[projectinfo.GetNodePoolLabel()]
+	if len(oldNp) == 0 || len(newNp) == 0 || oldNp == newNp {
+		return false, nil
+	}
+
+	var poolList appsv1beta2.NodePoolList
+	if err := webhook.Client.List(ctx, &poolList); err != nil {
+		return false, err
+	}
+
+	// select nodepool for the node as if it's still in the old nodepool
+	node := newNode.DeepCopy()
+	node.Labels[projectinfo.GetNodePoolLabel()] = oldNp
+	desired, _, err := nodepoolutil.SelectNodePool(node, poolList.Items)
+	if err != nil {
+		return false, nil
+	}
+	return desired == newNp, nil
+}
+
+func validateNodeUpdate(newNode, oldNode *v1.Node, reassigned bool) field.ErrorList {
 	oldNp := oldNode.Labels[projectinfo.GetNodePoolLabel()]
 	newNp := newNode.Labels[projectinfo.GetNodePoolLabel()]
 	oldNpHostNetwork := oldNode.Labels[apps.NodePoolHostNetworkLabel]
 	newNpHostNetwork := newNode.Labels[apps.NodePoolHostNetworkLabel]
 
 	var errList field.ErrorList
-	// it is not allowed to change NodePoolLabel if it has been set
-	if len(oldNp) != 0 && oldNp != newNp {
+	// it is not allowed to change NodePoolLabel if it has been set, except that
+	// the node is moved by the nodeSelector of NodePools
+	if len(oldNp) != 0 && oldNp != newNp && !reassigned {
 		errList = append(errList, field.Forbidden(field.NewPath("metadata").Child("labels").Child(projectinfo.GetNodePoolLabel()), "apps.openyurt.io/nodepool can not be changed"))
 	}
 
@@ -25,11 +25,27 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/openyurtio/openyurt/pkg/apis"
 	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
 	"github.com/openyurtio/openyurt/pkg/projectinfo"
 )
 
+func newZonePool(name, zone string) *appsv1beta2.NodePool {
+	return &appsv1beta2.NodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: appsv1beta2.NodePoolSpec{
+			Type: appsv1beta2.Edge,
+			NodeSelector: &appsv1beta2.NodePoolNodeSelector{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelTopologyZone: zone}},
+			},
+		},
+	}
+}
+
 func TestValidateUpdate(t *testing.T) {
 	testcases := map[string]struct {
 		oldNode runtime.Object
@@ -63,6 +79,44 @@ func TestValidateUpdate(t *testing.T) {
 			},
 			errCode: http.StatusUnprocessableEntity,
 		},
+		"node is moved by node selector": {
+			oldNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						projectinfo.GetNodePoolLabel(): "zone-a",
+						corev1.LabelTopologyZone:       "b",
+					},
+				},
+			},
+			newNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						projectinfo.GetNodePoolLabel(): "zone-b",
+						corev1.LabelTopologyZone:       "b",
+					},
+				},
+			},
+			errCode: 0,
+		},
+		"node is moved to nodepool which doesn't select it": {
+			oldNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						projectinfo.GetNodePoolLabel(): "zone-a",
+						corev1.LabelTopologyZone:       "a",
+					},
+				},
+			},
+			newNode: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						projectinfo.GetNodePoolLabel(): "zone-b",
+						corev1.LabelTopologyZone:       "a",
+					},
+				},
+			},
+			errCode: http.StatusUnprocessableEntity,
+		},
 		"node pool host network is changed": {
 			oldNode: &corev1.Node{
 				ObjectMeta: metav1.ObjectMeta{
@@ -115,9 +169,14 @@ func TestValidateUpdate(t *testing.T) {
 		},
 	}
 
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	apis.AddToScheme(scheme)
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(newZonePool("zone-a", "a"), newZonePool("zone-b", "b")).Build()
+
 	for k, tc := range testcases {
 		t.Run(k, func(t *testing.T) {
-			h := &NodeHandler{}
+			h := &NodeHandler{Client: c}
 			_, err := h.ValidateUpdate(context.TODO(), tc.oldNode, tc.newNode)
 			if tc.errCode == 0 && err != nil {
 				t.Errorf("Expected error code %d, got %v", tc.errCode, err)
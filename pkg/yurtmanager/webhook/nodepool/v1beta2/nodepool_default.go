@@ -61,6 +61,11 @@ func (webhook *NodePoolHandler) Default(ctx context.Context, obj runtime.Object)
 		np.Spec.LeaderElectionStrategy = string(v1beta2.ElectionStrategyRandom)
 	}
 
+	// Set default scoring for weighted election strategy
+	if np.Spec.LeaderElectionStrategy == string(v1beta2.ElectionStrategyWeighted) && np.Spec.LeaderElectionScoring == nil {
+		np.Spec.LeaderElectionScoring = v1beta2.DefaultLeaderElectionScoring()
+	}
+
 	// Set default LeaderReplicas
 	if np.Spec.LeaderReplicas <= 0 {
 		np.Spec.LeaderReplicas = 1
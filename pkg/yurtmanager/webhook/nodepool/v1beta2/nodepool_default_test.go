@@ -224,6 +224,55 @@ func TestDefault(t *testing.T) {
 				},
 			},
 		},
+		"nodepool has weighted election strategy without scoring": {
+			obj: &v1beta2.NodePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+				},
+				Spec: v1beta2.NodePoolSpec{
+					Type:                   v1beta2.Edge,
+					LeaderElectionStrategy: string(v1beta2.ElectionStrategyWeighted),
+					LeaderReplicas:         2,
+				},
+			},
+			wantedNodePool: &v1beta2.NodePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+					Labels: map[string]string{
+						"nodepool.openyurt.io/type": "edge",
+					},
+				},
+				Spec: v1beta2.NodePoolSpec{
+					Type:                   v1beta2.Edge,
+					LeaderElectionStrategy: string(v1beta2.ElectionStrategyWeighted),
+					LeaderElectionScoring: &v1beta2.LeaderElectionScoring{
+						CPUWeight:         10,
+						MemoryWeight:      2,
+						ReadinessWeight:   20,
+						LatencyWeight:     10,
+						StickinessPercent: 20,
+					},
+					LeaderReplicas: 2,
+					PoolScopeMetadata: []metav1.GroupVersionResource{
+						{
+							Group:    "",
+							Version:  "v1",
+							Resource: "services",
+						},
+						{
+							Group:    "discovery.k8s.io",
+							Version:  "v1",
+							Resource: "endpointslices",
+						},
+					},
+				},
+				Status: v1beta2.NodePoolStatus{
+					ReadyNodeNum:   0,
+					UnreadyNodeNum: 0,
+					Nodes:          []string{},
+				},
+			},
+		},
 		"nodepool has no pool scope metadata": {
 			obj: &v1beta2.NodePool{
 				ObjectMeta: metav1.ObjectMeta{
@@ -20,17 +20,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	appsv1beta2 "github.com/openyurtio/openyurt/pkg/apis/apps/v1beta2"
 	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	nodepoolutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/nodepool"
 )
 
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
@@ -44,6 +49,10 @@ func (webhook *NodePoolHandler) ValidateCreate(ctx context.Context, obj runtime.
 		return nil, apierrors.NewInvalid(appsv1beta2.GroupVersion.WithKind("NodePool").GroupKind(), np.Name, allErrs)
 	}
 
+	if allErrs := validateNodePoolParent(webhook.Client, np); len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(appsv1beta2.GroupVersion.WithKind("NodePool").GroupKind(), np.Name, allErrs)
+	}
+
 	return nil, nil
 }
 
@@ -69,6 +78,16 @@ func (webhook *NodePoolHandler) ValidateUpdate(
 		)
 	}
 
+	if newNp.Spec.Parent != oldNp.Spec.Parent {
+		if allErrs := validateNodePoolParent(webhook.Client, newNp); len(allErrs) > 0 {
+			return nil, apierrors.NewForbidden(
+				appsv1beta2.GroupVersion.WithResource("nodepools").GroupResource(),
+				newNp.Name,
+				allErrs[0],
+			)
+		}
+	}
+
 	return nil, nil
 }
 
@@ -131,21 +150,131 @@ func validateNodePoolSpec(spec *appsv1beta2.NodePoolSpec) field.ErrorList {
 		}
 	}
 
-	// Check leader election strategy has been set to Random or Mark
+	if allErrs := validateNodeSelector(spec.NodeSelector, field.NewPath("spec").Child("nodeSelector")); allErrs != nil {
+		return allErrs
+	}
+
+	// Check leader election strategy has been set to Random, Mark or Weighted
 	switch spec.LeaderElectionStrategy {
 	case string(appsv1beta2.ElectionStrategyRandom), string(appsv1beta2.ElectionStrategyMark):
+		if spec.LeaderElectionScoring != nil {
+			return []*field.Error{
+				field.Forbidden(
+					field.NewPath("spec").Child("leaderElectionScoring"),
+					"leaderElectionScoring can only be set when leaderElectionStrategy is Weighted",
+				),
+			}
+		}
 		return nil
+	case string(appsv1beta2.ElectionStrategyWeighted):
+		return validateLeaderElectionScoring(spec.LeaderElectionScoring, field.NewPath("spec").Child("leaderElectionScoring"))
 	default:
 		return []*field.Error{
 			field.Invalid(
 				field.NewPath("spec").Child("leaderElectionStrategy"),
 				spec.LeaderElectionStrategy,
-				"leaderElectionStrategy should be Random or Mark",
+				"leaderElectionStrategy should be Random, Mark or Weighted",
 			),
 		}
 	}
 }
 
+// validateLeaderElectionScoring validates the scoring of weighted leader election.
+func validateLeaderElectionScoring(scoring *appsv1beta2.LeaderElectionScoring, fldPath *field.Path) field.ErrorList {
+	if scoring == nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	weights := []struct {
+		name  string
+		value int32
+	}{
+		{"cpuWeight", scoring.CPUWeight},
+		{"memoryWeight", scoring.MemoryWeight},
+		{"readinessWeight", scoring.ReadinessWeight},
+		{"latencyWeight", scoring.LatencyWeight},
+		{"stickinessPercent", scoring.StickinessPercent},
+	}
+	for _, w := range weights {
+		if w.value < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(w.name), w.value, "should be non-negative"))
+		}
+	}
+
+	for i, lw := range scoring.LabelWeights {
+		for _, msg := range validation.IsQualifiedName(lw.Key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("labelWeights").Index(i).Child("key"), lw.Key, msg))
+		}
+	}
+
+	if len(scoring.TopologyKey) != 0 {
+		for _, msg := range validation.IsQualifiedName(scoring.TopologyKey) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("topologyKey"), scoring.TopologyKey, msg))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs
+}
+
+// validateNodeSelector validates the nodeSelector which is used for assigning nodes to the NodePool.
+func validateNodeSelector(selector *appsv1beta2.NodePoolNodeSelector, fldPath *field.Path) field.ErrorList {
+	if selector == nil {
+		return nil
+	}
+
+	if selector.LabelSelector == nil && len(selector.InternalIPCIDRs) == 0 {
+		return field.ErrorList{field.Required(fldPath, "labelSelector or internalIPCIDRs should be specified")}
+	}
+
+	allErrs := field.ErrorList{}
+	if selector.LabelSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(selector.LabelSelector,
+			metav1validation.LabelSelectorValidationOptions{}, fldPath.Child("labelSelector"))...)
+	}
+	for i, cidr := range selector.InternalIPCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("internalIPCIDRs").Index(i), cidr, err.Error()))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs
+}
+
+// validateNodePoolParent validates the parent of NodePool, the parent name should be able to be used in
+// ancestor labels, and NodePools should not be in a cycle.
+func validateNodePoolParent(cli client.Client, np *appsv1beta2.NodePool) field.ErrorList {
+	parent := np.Spec.Parent
+	if len(parent) == 0 {
+		return nil
+	}
+
+	fldPath := field.NewPath("spec").Child("parent")
+	if parent == np.Name {
+		return field.ErrorList{field.Invalid(fldPath, parent, "nodepool can not be the parent of itself")}
+	}
+	if msgs := validation.IsQualifiedName(nodepoolutil.AncestorLabelKey(parent)); len(msgs) != 0 {
+		return field.ErrorList{field.Invalid(fldPath, parent, strings.Join(msgs, "; "))}
+	}
+
+	pools := appsv1beta2.NodePoolList{}
+	if err := cli.List(context.TODO(), &pools); err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	parents := nodepoolutil.ParentsOf(pools.Items)
+	parents[np.Name] = parent
+	if _, err := nodepoolutil.Ancestors(np.Name, parents); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, parent, err.Error())}
+	}
+	return nil
+}
+
 // validateNodePoolSpecUpdate tests if required fields in the NodePool spec are set.
 func validateNodePoolSpecUpdate(spec, oldSpec *appsv1beta2.NodePoolSpec) field.ErrorList {
 	if allErrs := validateNodePoolSpec(spec); allErrs != nil {
@@ -190,5 +319,17 @@ func validateNodePoolDeletion(cli client.Client, np *appsv1beta2.NodePool) field
 			field.Forbidden(field.NewPath("metadata").Child("name"),
 				"cannot remove nonempty pool, please drain the pool before deleting")})
 	}
+
+	pools := appsv1beta2.NodePoolList{}
+	if err := cli.List(context.TODO(), &pools); err != nil {
+		return field.ErrorList([]*field.Error{
+			field.Forbidden(field.NewPath("metadata").Child("name"),
+				"could not get child pools of the pool")})
+	}
+	if children := nodepoolutil.ChildrenOf(np.Name, pools.Items); len(children) != 0 {
+		return field.ErrorList([]*field.Error{
+			field.Forbidden(field.NewPath("metadata").Child("name"),
+				fmt.Sprintf("cannot remove pool with child pools %s, please remove child pools before deleting", strings.Join(children, ",")))})
+	}
 	return nil
 }
@@ -19,6 +19,7 @@ package v1beta2
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -82,6 +83,97 @@ func TestValidateCreate(t *testing.T) {
 			},
 			errcode: http.StatusUnprocessableEntity,
 		},
+		"weighted leader election strategy": {
+			pool: &appsv1beta2.NodePool{
+				Spec: appsv1beta2.NodePoolSpec{
+					Type:                   appsv1beta2.Edge,
+					LeaderElectionStrategy: string(appsv1beta2.ElectionStrategyWeighted),
+					LeaderElectionScoring: &appsv1beta2.LeaderElectionScoring{
+						CPUWeight:    10,
+						LabelWeights: []appsv1beta2.LabelWeight{{Key: "node.kubernetes.io/instance-type", Value: "x86", Weight: 50}},
+						TopologyKey:  "topology.kubernetes.io/zone",
+					},
+				},
+			},
+			errcode: 0,
+		},
+		"negative weight of leader election scoring": {
+			pool: &appsv1beta2.NodePool{
+				Spec: appsv1beta2.NodePoolSpec{
+					Type:                   appsv1beta2.Edge,
+					LeaderElectionStrategy: string(appsv1beta2.ElectionStrategyWeighted),
+					LeaderElectionScoring:  &appsv1beta2.LeaderElectionScoring{LatencyWeight: -1},
+				},
+			},
+			errcode: http.StatusUnprocessableEntity,
+		},
+		"invalid topology key of leader election scoring": {
+			pool: &appsv1beta2.NodePool{
+				Spec: appsv1beta2.NodePoolSpec{
+					Type:                   appsv1beta2.Edge,
+					LeaderElectionStrategy: string(appsv1beta2.ElectionStrategyWeighted),
+					LeaderElectionScoring:  &appsv1beta2.LeaderElectionScoring{TopologyKey: "-&#rack"},
+				},
+			},
+			errcode: http.StatusUnprocessableEntity,
+		},
+		"leader election scoring with random strategy": {
+			pool: &appsv1beta2.NodePool{
+				Spec: appsv1beta2.NodePoolSpec{
+					Type:                   appsv1beta2.Edge,
+					LeaderElectionStrategy: string(appsv1beta2.ElectionStrategyRandom),
+					LeaderElectionScoring:  &appsv1beta2.LeaderElectionScoring{CPUWeight: 1},
+				},
+			},
+			errcode: http.StatusUnprocessableEntity,
+		},
+		"nodepool with node selector": {
+			pool: &appsv1beta2.NodePool{
+				Spec: appsv1beta2.NodePoolSpec{
+					Type:                   appsv1beta2.Edge,
+					LeaderElectionStrategy: string(appsv1beta2.ElectionStrategyRandom),
+					NodeSelector: &appsv1beta2.NodePoolNodeSelector{
+						LabelSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"topology.kubernetes.io/zone": "hangzhou-a"}},
+						InternalIPCIDRs: []string{"192.168.0.0/16"},
+					},
+				},
+			},
+			errcode: 0,
+		},
+		"empty node selector": {
+			pool: &appsv1beta2.NodePool{
+				Spec: appsv1beta2.NodePoolSpec{
+					Type:                   appsv1beta2.Edge,
+					LeaderElectionStrategy: string(appsv1beta2.ElectionStrategyRandom),
+					NodeSelector:           &appsv1beta2.NodePoolNodeSelector{},
+				},
+			},
+			errcode: http.StatusUnprocessableEntity,
+		},
+		"invalid cidr of node selector": {
+			pool: &appsv1beta2.NodePool{
+				Spec: appsv1beta2.NodePoolSpec{
+					Type:                   appsv1beta2.Edge,
+					LeaderElectionStrategy: string(appsv1beta2.ElectionStrategyRandom),
+					NodeSelector:           &appsv1beta2.NodePoolNodeSelector{InternalIPCIDRs: []string{"192.168.0.1"}},
+				},
+			},
+			errcode: http.StatusUnprocessableEntity,
+		},
+		"invalid label selector of node selector": {
+			pool: &appsv1beta2.NodePool{
+				Spec: appsv1beta2.NodePoolSpec{
+					Type:                   appsv1beta2.Edge,
+					LeaderElectionStrategy: string(appsv1beta2.ElectionStrategyRandom),
+					NodeSelector: &appsv1beta2.NodePoolNodeSelector{
+						LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "topology.kubernetes.io/zone", Operator: metav1.LabelSelectorOpIn},
+						}},
+					},
+				},
+			},
+			errcode: http.StatusUnprocessableEntity,
+		},
 	}
 
 	handler := &NodePoolHandler{}
@@ -268,6 +360,15 @@ func prepareNodePools() []client.Object {
 				Labels: map[string]string{
 					"region": "beijing",
 				},
+				Parent: "north",
+			},
+		},
+		&appsv1beta2.NodePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "north",
+			},
+			Spec: appsv1beta2.NodePoolSpec{
+				Type: appsv1beta2.Edge,
 			},
 		},
 	}
@@ -305,6 +406,14 @@ func TestValidateDelete(t *testing.T) {
 			},
 			errcode: http.StatusForbidden,
 		},
+		"delete a nodepool with child pools": {
+			pool: &appsv1beta2.NodePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "north",
+				},
+			},
+			errcode: http.StatusForbidden,
+		},
 		"it is not a nodepool": {
 			pool:    &corev1.Node{},
 			errcode: http.StatusBadRequest,
@@ -327,3 +436,52 @@ func TestValidateDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateNodePoolParent(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal("Fail to add kubernetes clint-go custom resource")
+	}
+	apis.AddToScheme(scheme)
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(prepareNodePools()...).Build()
+
+	testcases := map[string]struct {
+		name      string
+		parent    string
+		expectErr bool
+	}{
+		"nodepool without parent": {
+			name: "shanghai",
+		},
+		"nodepool with parent": {
+			name:   "shanghai",
+			parent: "north",
+		},
+		"nodepool is parent of itself": {
+			name:      "shanghai",
+			parent:    "shanghai",
+			expectErr: true,
+		},
+		"nodepools are in a cycle": {
+			name:      "north",
+			parent:    "beijing",
+			expectErr: true,
+		},
+		"parent can not be used in label": {
+			name:      "shanghai",
+			parent:    strings.Repeat("a", 64),
+			expectErr: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			np := &appsv1beta2.NodePool{
+				ObjectMeta: metav1.ObjectMeta{Name: tc.name},
+				Spec:       appsv1beta2.NodePoolSpec{Parent: tc.parent},
+			}
+			errs := validateNodePoolParent(c, np)
+			assert.Equal(t, tc.expectErr, len(errs) != 0, "Expected error %v, got %v", tc.expectErr, errs)
+		})
+	}
+}
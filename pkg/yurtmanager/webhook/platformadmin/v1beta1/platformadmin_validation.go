@@ -22,7 +22,12 @@ import (
 	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -95,6 +100,11 @@ func (webhook *PlatformAdminHandler) validate(
 		return specErrs
 	}
 
+	// verify the custom components and overrides
+	if componentErrs := webhook.validatePlatformAdminComponents(platformAdmin); componentErrs != nil {
+		return componentErrs
+	}
+
 	// verify that the poolname nodepool
 	if nodePoolErrs := webhook.validatePlatformAdminWithNodePools(ctx, platformAdmin); nodePoolErrs != nil {
 		return nodePoolErrs
@@ -103,15 +113,26 @@ func (webhook *PlatformAdminHandler) validate(
 }
 
 func (webhook *PlatformAdminHandler) validatePlatformAdminSpec(platformAdmin *v1beta1.PlatformAdmin) field.ErrorList {
-	// TODO: Need to divert traffic based on the type of platform
-
-	// Verify that the platform is supported
-	if platformAdmin.Spec.Platform != v1beta1.PlatformAdminPlatformEdgeX {
+	switch platformAdmin.Spec.Platform {
+	case v1beta1.PlatformAdminPlatformEdgeX:
+	case v1beta1.PlatformAdminPlatformMQTT:
+		// the version of the mqtt platform is the version of the broker which hosts the device registry
+		if util.MQTTBrokerVersions.Has(platformAdmin.Spec.Version) {
+			return nil
+		}
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "version"),
+				platformAdmin.Spec.Version,
+				"must be one of "+strings.Join(sets.List(util.MQTTBrokerVersions), ","),
+			),
+		}
+	default:
 		return field.ErrorList{
 			field.Invalid(
 				field.NewPath("spec", "platform"),
 				platformAdmin.Spec.Platform,
-				"must be "+v1beta1.PlatformAdminPlatformEdgeX,
+				"must be one of "+strings.Join([]string{v1beta1.PlatformAdminPlatformEdgeX, v1beta1.PlatformAdminPlatformMQTT}, ","),
 			),
 		}
 	}
@@ -132,6 +153,89 @@ func (webhook *PlatformAdminHandler) validatePlatformAdminSpec(platformAdmin *v1
 	}
 }
 
+func (webhook *PlatformAdminHandler) validatePlatformAdminComponents(platformAdmin *v1beta1.PlatformAdmin) field.ErrorList {
+	var errs field.ErrorList
+	names := sets.New[string]()
+	for i, component := range platformAdmin.Spec.Components {
+		fldPath := field.NewPath("spec", "components").Index(i)
+
+		// The component name is used as the name of its service
+		for _, msg := range validation.IsDNS1035Label(component.Name) {
+			errs = append(errs, field.Invalid(fldPath.Child("name"), component.Name, msg))
+		}
+		if names.Has(component.Name) {
+			errs = append(errs, field.Duplicate(fldPath.Child("name"), component.Name))
+		}
+		names.Insert(component.Name)
+
+		if component.Override != nil {
+			errs = append(errs, validateComponentOverride(component.Override, fldPath.Child("override"))...)
+		}
+		if component.Definition != nil {
+			errs = append(errs, validateComponentDefinition(component.Definition, fldPath.Child("definition"))...)
+		}
+	}
+	return errs
+}
+
+func validateComponentOverride(override *v1beta1.ComponentOverride, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if override.Image != strings.TrimSpace(override.Image) {
+		errs = append(errs, field.Invalid(fldPath.Child("image"), override.Image, "must not have leading or trailing whitespace"))
+	}
+
+	for i, env := range override.Env {
+		for _, msg := range validation.IsEnvVarName(env.Name) {
+			errs = append(errs, field.Invalid(fldPath.Child("env").Index(i).Child("name"), env.Name, msg))
+		}
+	}
+
+	if override.Resources != nil {
+		for name, request := range override.Resources.Requests {
+			if limit, ok := override.Resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+				errs = append(errs, field.Invalid(fldPath.Child("resources", "requests").Key(string(name)), request.String(),
+					fmt.Sprintf("must be less than or equal to %s limit of %s", name, limit.String())))
+			}
+		}
+	}
+
+	errs = append(errs, metav1validation.ValidateLabels(override.NodeSelector, fldPath.Child("nodeSelector"))...)
+	return errs
+}
+
+func validateComponentDefinition(definition *v1beta1.ComponentDefinition, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	deploymentPath := fldPath.Child("deployment")
+
+	containers := definition.Deployment.Template.Spec.Containers
+	if len(containers) == 0 {
+		errs = append(errs, field.Required(deploymentPath.Child("template", "spec", "containers"), "must have at least one container"))
+	}
+	for i, container := range containers {
+		containerPath := deploymentPath.Child("template", "spec", "containers").Index(i)
+		if container.Name == "" {
+			errs = append(errs, field.Required(containerPath.Child("name"), ""))
+		}
+		if container.Image == "" {
+			errs = append(errs, field.Required(containerPath.Child("image"), ""))
+		}
+	}
+
+	// The yurtappset creates the deployments with the selector, so it must select the pods of the template
+	if definition.Deployment.Selector == nil {
+		errs = append(errs, field.Required(deploymentPath.Child("selector"), ""))
+	} else if selector, err := metav1.LabelSelectorAsSelector(definition.Deployment.Selector); err != nil {
+		errs = append(errs, field.Invalid(deploymentPath.Child("selector"), definition.Deployment.Selector, err.Error()))
+	} else if selector.Empty() || !selector.Matches(labels.Set(definition.Deployment.Template.Labels)) {
+		errs = append(errs, field.Invalid(deploymentPath.Child("selector"), definition.Deployment.Selector, "must match the labels of the template"))
+	}
+
+	if definition.Service != nil && len(definition.Service.Ports) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("service", "ports"), "must have at least one port"))
+	}
+	return errs
+}
+
 func (webhook *PlatformAdminHandler) validatePlatformAdminWithNodePools(
 	ctx context.Context,
 	platformAdmin *v1beta1.PlatformAdmin,
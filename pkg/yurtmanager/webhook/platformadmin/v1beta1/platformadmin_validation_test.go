@@ -24,7 +24,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -160,6 +163,36 @@ func TestValidateCreate(t *testing.T) {
 			},
 			errCode: 0,
 		},
+		{
+			name:   "should get no err when Platform is mqtt and version is a supported broker version",
+			client: NewFakeClient(buildClient(buildNodePool(), buildPlatformAdmin())).Build(),
+			obj: &v1beta1.PlatformAdmin{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "beijing-PlatformAdmin",
+				},
+				Spec: v1beta1.PlatformAdminSpec{
+					NodePools: []string{"beijing"},
+					Platform:  v1beta1.PlatformAdminPlatformMQTT,
+					Version:   "2.0",
+				},
+			},
+			errCode: 0,
+		},
+		{
+			name:   "should get StatusUnprocessableEntityError when Platform is mqtt and version is not supported",
+			client: NewFakeClient(buildClient(buildNodePool(), buildPlatformAdmin())).Build(),
+			obj: &v1beta1.PlatformAdmin{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "beijing-PlatformAdmin",
+				},
+				Spec: v1beta1.PlatformAdminSpec{
+					NodePools: []string{"beijing"},
+					Platform:  v1beta1.PlatformAdminPlatformMQTT,
+					Version:   "v2",
+				},
+			},
+			errCode: http.StatusUnprocessableEntity,
+		},
 	}
 
 	manifest := &config.Manifest{
@@ -293,6 +326,121 @@ func TestValidateUpdate(t *testing.T) {
 	}
 }
 
+// TestValidatePlatformAdminComponents tests the validation of the custom components and overrides.
+func TestValidatePlatformAdminComponents(t *testing.T) {
+	definition := func() *v1beta1.ComponentDefinition {
+		return &v1beta1.ComponentDefinition{
+			Service: &corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Name: "http", Port: 59999}},
+			},
+			Deployment: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "edgex-device-modbus"}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "edgex-device-modbus"}},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "edgex-device-modbus", Image: "edgexfoundry/device-modbus:3.1.1"}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		components []v1beta1.Component
+		errFields  []string
+	}{
+		{
+			name: "valid overrides and definitions",
+			components: []v1beta1.Component{
+				{
+					Name: "edgex-core-data",
+					Override: &v1beta1.ComponentOverride{
+						Image: "edgexfoundry/core-data:3.1.1",
+						Env:   []corev1.EnvVar{{Name: "WRITABLE_LOGLEVEL", Value: "DEBUG"}},
+						Resources: &corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+							Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+						},
+						NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"},
+					},
+				},
+				{Name: "edgex-device-modbus", Definition: definition()},
+			},
+		},
+		{
+			name:       "invalid and duplicated names",
+			components: []v1beta1.Component{{Name: "Core_Data"}, {Name: "edgex-ui-go"}, {Name: "edgex-ui-go"}},
+			errFields:  []string{"spec.components[0].name", "spec.components[2].name"},
+		},
+		{
+			name: "invalid override",
+			components: []v1beta1.Component{{
+				Name: "edgex-core-data",
+				Override: &v1beta1.ComponentOverride{
+					Image: " edgexfoundry/core-data:3.1.1",
+					Env:   []corev1.EnvVar{{Name: "1=LOGLEVEL"}},
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+					},
+					NodeSelector: map[string]string{"invalid key": "value"},
+				},
+			}},
+			errFields: []string{
+				"spec.components[0].override.image",
+				"spec.components[0].override.env[0].name",
+				"spec.components[0].override.resources.requests[memory]",
+				"spec.components[0].override.nodeSelector",
+			},
+		},
+		{
+			name: "invalid definition",
+			components: []v1beta1.Component{{
+				Name: "edgex-device-modbus",
+				Definition: func() *v1beta1.ComponentDefinition {
+					d := definition()
+					d.Service.Ports = nil
+					d.Deployment.Selector.MatchLabels["app"] = "other"
+					d.Deployment.Template.Spec.Containers[0].Image = ""
+					return d
+				}(),
+			}},
+			errFields: []string{
+				"spec.components[0].definition.deployment.template.spec.containers[0].image",
+				"spec.components[0].definition.deployment.selector",
+				"spec.components[0].definition.service.ports",
+			},
+		},
+		{
+			name: "definition without containers",
+			components: []v1beta1.Component{{
+				Name: "edgex-device-modbus",
+				Definition: func() *v1beta1.ComponentDefinition {
+					d := definition()
+					d.Deployment.Template.Spec.Containers = nil
+					return d
+				}(),
+			}},
+			errFields: []string{"spec.components[0].definition.deployment.template.spec.containers"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := &PlatformAdminHandler{}
+			errs := handler.validatePlatformAdminComponents(&v1beta1.PlatformAdmin{
+				Spec: v1beta1.PlatformAdminSpec{Components: tc.components},
+			})
+			fields := []string{}
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			assert.ElementsMatch(t, tc.errFields, fields)
+		})
+	}
+}
+
 // TestValidateDelete tests the ValidateDelete method of PlatformAdminHandler.
 func TestValidateDelete(t *testing.T) {
 	handler := &PlatformAdminHandler{}
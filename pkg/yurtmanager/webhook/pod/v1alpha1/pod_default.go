@@ -19,19 +19,44 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
 )
 
 const (
 	NodePoolHostNetworkLabelKey       = "nodepool.openyurt.io/hostnetwork"
 	NodePoolHostNetworkLabelForbidden = "true"
+
+	// PodControllerUIDIndex is the field index of pods by the uid of their controller
+	PodControllerUIDIndex = "metadata.ownerReferences.controller.uid"
+
+	// failoverReplacementExpiration is the duration after the failover within which the replacement pods
+	// are steered to the failover target NodePools, the failover is considered as done afterwards.
+	failoverReplacementExpiration = 30 * time.Minute
 )
 
+// IndexPodByControllerUID is the indexer function of PodControllerUIDIndex
+func IndexPodByControllerUID(obj client.Object) []string {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil {
+		return []string{}
+	}
+	return []string{string(owner.UID)}
+}
+
 // Default implements builder.CustomDefaulter.
 func (webhook *PodHandler) Default(ctx context.Context, obj runtime.Object) error {
 	pod, ok := obj.(*corev1.Pod)
@@ -40,16 +65,89 @@ func (webhook *PodHandler) Default(ctx context.Context, obj runtime.Object) erro
 	}
 
 	// Add NodeAffinity to pods in order to avoid pods to be scheduled on the nodes in the hostNetwork mode NodePool
-	if pod.Annotations[apps.AnnotationExcludeHostNetworkPool] != "true" {
-		return nil
+	if pod.Annotations[apps.AnnotationExcludeHostNetworkPool] == "true" {
+		addRequiredNodeSelectorRequirement(pod, corev1.NodeSelectorRequirement{
+			Key:      NodePoolHostNetworkLabelKey,
+			Operator: corev1.NodeSelectorOpNotIn,
+			Values:   []string{NodePoolHostNetworkLabelForbidden},
+		})
+	}
+
+	// Add NodeAffinity to the replacement pod of a failed over pod in order to schedule it to the target NodePools
+	failedPod, pools, err := webhook.failoverTargetPools(ctx, pod, time.Now())
+	if err != nil {
+		return err
 	}
+	if len(pools) != 0 {
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[apps.AnnotationFailoverReplacementOf] = failedPod
+		addRequiredNodeSelectorRequirement(pod, corev1.NodeSelectorRequirement{
+			Key:      projectinfo.GetNodePoolLabel(),
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   pools,
+		})
+	}
+	return nil
+}
 
-	excludeHostNetworkRequirement := corev1.NodeSelectorRequirement{
-		Key:      NodePoolHostNetworkLabelKey,
-		Operator: corev1.NodeSelectorOpNotIn,
-		Values:   []string{NodePoolHostNetworkLabelForbidden},
+// failoverTargetPools finds the pod which is failed over from its node and not replaced yet among the pods owned
+// by the same controller as pod, and returns its name and target NodePools. Each failed over pod is replaced by
+// one pod, and failovers which happened more than failoverReplacementExpiration ago are ignored, so that the
+// later pods of the controller are not steered when the failed over pods are stuck in terminating.
+func (webhook *PodHandler) failoverTargetPools(ctx context.Context, pod *corev1.Pod, now time.Time) (string, []string, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind == "DaemonSet" || len(pod.Spec.NodeName) != 0 {
+		return "", nil, nil
 	}
 
+	var podList corev1.PodList
+	if err := webhook.Client.List(ctx, &podList, client.InNamespace(pod.Namespace),
+		client.MatchingFields{PodControllerUIDIndex: string(owner.UID)}); err != nil {
+		return "", nil, err
+	}
+
+	replaced := sets.New[string]()
+	var failedPods []*corev1.Pod
+	for i := range podList.Items {
+		sibling := &podList.Items[i]
+		if name, ok := sibling.Annotations[apps.AnnotationFailoverReplacementOf]; ok {
+			replaced.Insert(name)
+		}
+		failoverTime, ok := sibling.Annotations[apps.AnnotationFailoverTime]
+		if !ok {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, failoverTime); err != nil {
+			klog.Warningf("could not parse failover time of pod(%s/%s), %v", sibling.Namespace, sibling.Name, err)
+			continue
+		} else if now.Sub(t) > failoverReplacementExpiration {
+			continue
+		}
+		failedPods = append(failedPods, sibling)
+	}
+
+	sort.Slice(failedPods, func(i, j int) bool { return failedPods[i].Name < failedPods[j].Name })
+	for _, failedPod := range failedPods {
+		if replaced.Has(failedPod.Name) {
+			continue
+		}
+		pools := sets.New[string]()
+		for _, pool := range strings.Split(failedPod.Annotations[apps.AnnotationFailoverTargetPools], ",") {
+			if len(pool) != 0 {
+				pools.Insert(pool)
+			}
+		}
+		if pools.Len() != 0 {
+			return failedPod.Name, sets.List(pools), nil
+		}
+	}
+	return "", nil, nil
+}
+
+// addRequiredNodeSelectorRequirement adds the requirement to every required node selector term of pod.
+func addRequiredNodeSelectorRequirement(pod *corev1.Pod, requirement corev1.NodeSelectorRequirement) {
 	if pod.Spec.Affinity == nil {
 		pod.Spec.Affinity = &corev1.Affinity{}
 	}
@@ -77,21 +175,17 @@ func (webhook *PodHandler) Default(ctx context.Context, obj runtime.Object) erro
 	for i, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
 		needToAddAffinity := true
 		for _, expr := range term.MatchExpressions {
-			if expr.Key == NodePoolHostNetworkLabelKey && expr.Operator == corev1.NodeSelectorOpNotIn {
-				if len(expr.Values) == 1 && expr.Values[0] == NodePoolHostNetworkLabelForbidden {
-					needToAddAffinity = false
-					break
-				}
+			if reflect.DeepEqual(expr, requirement) {
+				needToAddAffinity = false
+				break
 			}
 		}
 
 		if needToAddAffinity {
 			pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[i].MatchExpressions = append(
 				pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[i].MatchExpressions,
-				excludeHostNetworkRequirement,
+				requirement,
 			)
 		}
 	}
-
-	return nil
 }
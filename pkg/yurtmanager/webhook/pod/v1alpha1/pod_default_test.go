@@ -20,12 +20,17 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/openyurtio/openyurt/pkg/apis/apps"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
 )
 
 func TestDefault(t *testing.T) {
@@ -490,3 +495,74 @@ func TestDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultFailoverReplacement(t *testing.T) {
+	newOwnedPod := func(name, kind, ownerUID string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   metav1.NamespaceDefault,
+				Annotations: annotations,
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: kind, Name: "owner", UID: types.UID(ownerUID), Controller: ptr.To(true)},
+				},
+			},
+		}
+	}
+	failoverAnnotations := func(failoverTime time.Time) map[string]string {
+		return map[string]string{
+			apps.AnnotationFailoverTime:        failoverTime.UTC().Format(time.RFC3339),
+			apps.AnnotationFailoverTargetPools: "hangzhou,backup",
+		}
+	}
+	c := fakeclient.NewClientBuilder().
+		WithIndex(&corev1.Pod{}, PodControllerUIDIndex, IndexPodByControllerUID).
+		WithObjects(
+			newOwnedPod("failed", "ReplicaSet", "rs-uid", failoverAnnotations(time.Now())),
+			newOwnedPod("expired", "ReplicaSet", "expired-uid", failoverAnnotations(time.Now().Add(-time.Hour))),
+			newOwnedPod("ds-failed", "DaemonSet", "ds-uid", failoverAnnotations(time.Now())),
+		).Build()
+	h := PodHandler{Client: c}
+
+	replacement := newOwnedPod("replacement", "ReplicaSet", "rs-uid", nil)
+	if err := h.Default(context.TODO(), replacement); err != nil {
+		t.Fatalf("expect no error, but got %v", err)
+	}
+	expected := &corev1.NodeSelector{
+		NodeSelectorTerms: []corev1.NodeSelectorTerm{
+			{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{
+						Key:      projectinfo.GetNodePoolLabel(),
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{"backup", "hangzhou"},
+					},
+				},
+			},
+		},
+	}
+	if replacement.Spec.Affinity == nil || !reflect.DeepEqual(replacement.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution, expected) {
+		t.Errorf("expect replacement pod to be scheduled to failover target pools, but got %#+v", replacement.Spec.Affinity)
+	}
+	if replacement.Annotations[apps.AnnotationFailoverReplacementOf] != "failed" {
+		t.Errorf("expect replacement pod to be marked as the replacement of failed pod, but got %v", replacement.Annotations)
+	}
+	if err := c.Create(context.TODO(), replacement); err != nil {
+		t.Fatalf("expect no error, but got %v", err)
+	}
+
+	for _, pod := range []*corev1.Pod{
+		// the failed over pod has been replaced
+		newOwnedPod("next", "ReplicaSet", "rs-uid", nil),
+		newOwnedPod("unrelated", "ReplicaSet", "other-uid", nil),
+		newOwnedPod("expired-replacement", "ReplicaSet", "expired-uid", nil),
+		newOwnedPod("ds-replacement", "DaemonSet", "ds-uid", nil),
+	} {
+		if err := h.Default(context.TODO(), pod); err != nil {
+			t.Fatalf("expect no error, but got %v", err)
+		}
+		if pod.Spec.Affinity != nil {
+			t.Errorf("expect no affinity for pod %s, but got %#+v", pod.Name, pod.Spec.Affinity)
+		}
+	}
+}
@@ -17,10 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
+
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	yurtClient "github.com/openyurtio/openyurt/cmd/yurt-manager/app/client"
 	"github.com/openyurtio/openyurt/pkg/yurtmanager/webhook/util"
 )
 
@@ -30,13 +34,27 @@ const (
 
 // SetupWebhookWithManager sets up Cluster webhooks. mutate path, validate path, error
 func (webhook *PodHandler) SetupWebhookWithManager(mgr ctrl.Manager) (string, string, error) {
+	// init
+	// the webhook reads pods, nodes and PoolResourceQuotas with its own service account
+	webhook.Client = yurtClient.GetClientByControllerNameOrDie(mgr, WebhookName)
+	if err := mgr.GetFieldIndexer().IndexField(context.TODO(), &corev1.Pod{}, PodControllerUIDIndex, IndexPodByControllerUID); err != nil {
+		return "", "", err
+	}
+
 	return util.RegisterWebhook(mgr, &corev1.Pod{}, webhook)
 }
 
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=list
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get
+// +kubebuilder:rbac:groups=apps.openyurt.io,resources=poolresourcequotas,verbs=list
+
+// +kubebuilder:webhook:path=/validate-core-openyurt-io-v1-pod,mutating=false,failurePolicy=ignore,sideEffects=None,admissionReviewVersions=v1;v1beta1,groups="",resources=pods,verbs=create,versions=v1,name=validate.core.v1.pod.openyurt.io
 // +kubebuilder:webhook:path=/mutate-core-openyurt-io-v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,admissionReviewVersions=v1;v1beta1,groups="",resources=pods,verbs=create,versions=v1,name=mutate.core.v1.pod.openyurt.io
 
 // PodHandler implements a validating and defaulting webhook for Cluster.
 type PodHandler struct {
+	Client client.Client
 }
 
 var _ webhook.CustomDefaulter = &PodHandler{}
+var _ webhook.CustomValidator = &PodHandler{}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	nodepoolutil "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/util/nodepool"
+)
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type.
+// Pods which target a NodePool are rejected if they exceed the PoolResourceQuota of their namespace in the pool.
+// The check is best effort: the usage is the status of PoolResourceQuota aggregated by the nodepool controller
+// from the pods bound to nodes, so the pods which are pending or created concurrently are not counted, and the
+// quota can be exceeded until the status catches up.
+func (webhook *PodHandler) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a Pod but got a %T", obj))
+	}
+
+	pool, err := webhook.nodePoolOfPod(ctx, pod)
+	if err != nil || len(pool) == 0 {
+		return nil, err
+	}
+
+	var quotaList appsv1alpha1.PoolResourceQuotaList
+	if err := webhook.Client.List(ctx, &quotaList, client.InNamespace(pod.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	requested := nodepoolutil.PodRequests(pod)
+	for i := range quotaList.Items {
+		quota := &quotaList.Items[i]
+		if quota.Spec.NodePool != pool {
+			continue
+		}
+		if exceeded := exceededResources(quota.Spec.Hard, quota.Status.Used, requested); len(exceeded) != 0 {
+			return nil, apierrors.NewForbidden(corev1.Resource("pods"), pod.Name,
+				fmt.Errorf("exceeded pool quota: %s, requested: %s, used: %s, limited: %s",
+					quota.Name, formatResources(requested, exceeded), formatResources(quota.Status.Used, exceeded), formatResources(quota.Spec.Hard, exceeded)))
+		}
+	}
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type.
+func (webhook *PodHandler) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
+func (webhook *PodHandler) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// nodePoolOfPod returns the NodePool of node which the pod is bound to, or the NodePool which the pod targets
+// if it's not scheduled.
+func (webhook *PodHandler) nodePoolOfPod(ctx context.Context, pod *corev1.Pod) (string, error) {
+	if len(pod.Spec.NodeName) == 0 {
+		return nodepoolutil.TargetNodePool(pod), nil
+	}
+
+	var node corev1.Node
+	if err := webhook.Client.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, &node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return node.Labels[projectinfo.GetNodePoolLabel()], nil
+}
+
+// exceededResources returns the names of resources whose hard limits are exceeded when requested is added to used.
+func exceededResources(hard, used, requested corev1.ResourceList) []corev1.ResourceName {
+	var exceeded []corev1.ResourceName
+	for name, limit := range hard {
+		req, ok := requested[name]
+		if !ok || req.IsZero() {
+			continue
+		}
+		total := req.DeepCopy()
+		if q, ok := used[name]; ok {
+			total.Add(q)
+		}
+		if total.Cmp(limit) > 0 {
+			exceeded = append(exceeded, name)
+		}
+	}
+	sort.Slice(exceeded, func(i, j int) bool { return exceeded[i] < exceeded[j] })
+	return exceeded
+}
+
+func formatResources(list corev1.ResourceList, names []corev1.ResourceName) string {
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		q := list[name]
+		parts = append(parts, fmt.Sprintf("%s=%s", name, q.String()))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the License);
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an AS IS BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openyurtio/openyurt/pkg/apis"
+	appsv1alpha1 "github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+)
+
+func newQuotaPod(name, nodeName, pool, cpu string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+				},
+			}},
+		},
+	}
+	if len(pool) != 0 {
+		pod.Spec.NodeSelector = map[string]string{projectinfo.GetNodePoolLabel(): pool}
+	}
+	return pod
+}
+
+func TestValidateCreate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	apis.AddToScheme(scheme)
+
+	objs := []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{projectinfo.GetNodePoolLabel(): "hangzhou"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{projectinfo.GetNodePoolLabel(): "beijing"}}},
+		&appsv1alpha1.PoolResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: metav1.NamespaceDefault},
+			Spec: appsv1alpha1.PoolResourceQuotaSpec{
+				NodePool: "hangzhou",
+				Hard: corev1.ResourceList{
+					corev1.ResourceCPU:  resource.MustParse("2"),
+					corev1.ResourcePods: resource.MustParse("3"),
+				},
+			},
+			Status: appsv1alpha1.PoolResourceQuotaStatus{
+				Used: corev1.ResourceList{
+					corev1.ResourceCPU:  resource.MustParse("1500m"),
+					corev1.ResourcePods: resource.MustParse("2"),
+				},
+			},
+		},
+	}
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	webhook := &PodHandler{Client: c}
+
+	testcases := map[string]struct {
+		pod    *corev1.Pod
+		errMsg string
+	}{
+		"pod doesn't target any pool": {
+			pod: newQuotaPod("new", "", "", "4"),
+		},
+		"pod targets pool without quota": {
+			pod: newQuotaPod("new", "", "beijing", "4"),
+		},
+		"pod fits in quota": {
+			pod: newQuotaPod("new", "", "hangzhou", "500m"),
+		},
+		"pod exceeds cpu quota": {
+			pod:    newQuotaPod("new", "", "hangzhou", "600m"),
+			errMsg: "exceeded pool quota: quota, requested: cpu=600m, used: cpu=1500m, limited: cpu=2",
+		},
+		"pod bound to node exceeds cpu quota": {
+			pod:    newQuotaPod("new", "node1", "", "1"),
+			errMsg: "exceeded pool quota: quota, requested: cpu=1, used: cpu=1500m, limited: cpu=2",
+		},
+		"pod targets pool by node affinity": {
+			pod: func() *corev1.Pod {
+				pod := newQuotaPod("new", "", "", "1")
+				pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{
+								Key:      projectinfo.GetNodePoolLabel(),
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{"hangzhou"},
+							}},
+						}},
+					},
+				}}
+				return pod
+			}(),
+			errMsg: "exceeded pool quota",
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			_, err := webhook.ValidateCreate(context.TODO(), tc.pod)
+			if len(tc.errMsg) == 0 {
+				if err != nil {
+					t.Errorf("expect no error, but got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("expect error %q, but got %v", tc.errMsg, err)
+			}
+		})
+	}
+}
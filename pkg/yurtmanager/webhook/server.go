@@ -30,6 +30,8 @@ import (
 	"github.com/openyurtio/openyurt/cmd/yurt-manager/app/config"
 	"github.com/openyurtio/openyurt/cmd/yurt-manager/names"
 	controller "github.com/openyurtio/openyurt/pkg/yurtmanager/controller/base"
+	v1alpha1addresspool "github.com/openyurtio/openyurt/pkg/yurtmanager/webhook/addresspool/v1alpha1"
+	v1alpha1devicecommand "github.com/openyurtio/openyurt/pkg/yurtmanager/webhook/devicecommand/v1alpha1"
 	v1endpoints "github.com/openyurtio/openyurt/pkg/yurtmanager/webhook/endpoints/v1"
 	v1endpointslice "github.com/openyurtio/openyurt/pkg/yurtmanager/webhook/endpointslice/v1"
 	v1beta1gateway "github.com/openyurtio/openyurt/pkg/yurtmanager/webhook/gateway/v1beta1"
@@ -75,11 +77,13 @@ func init() {
 	addControllerWebhook(names.YurtAppSetController, &v1beta1yurtappset.YurtAppSetHandler{})
 	//addControllerWebhook(names.PlatformAdminController, &v1alpha2platformadmin.PlatformAdminHandler{})
 	addControllerWebhook(names.PlatformAdminController, &v1beta1platformadmin.PlatformAdminHandler{})
+	addControllerWebhook(names.LoadBalancerSetController, &v1alpha1addresspool.AddressPoolHandler{})
 
 	independentWebhooks[v1node.WebhookName] = &v1node.NodeHandler{}
 	independentWebhooks[v1alpha1pod.WebhookName] = &v1alpha1pod.PodHandler{}
 	independentWebhooks[v1endpoints.WebhookName] = &v1endpoints.EndpointsHandler{}
 	independentWebhooks[v1endpointslice.WebhookName] = &v1endpointslice.EndpointSliceHandler{}
+	independentWebhooks[v1alpha1devicecommand.WebhookName] = &v1alpha1devicecommand.DeviceCommandHandler{}
 }
 
 // Note !!! @kadisi
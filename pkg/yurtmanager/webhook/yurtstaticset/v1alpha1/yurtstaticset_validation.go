@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
@@ -122,9 +123,104 @@ func validateYurtStaticSetSpec(spec *v1alpha1.YurtStaticSetSpec) field.ErrorList
 			"max-unavailable is required in AdvancedRollingUpdate mode"))
 	}
 
+	allErrs = append(allErrs, validateRolloutControls(strategy, field.NewPath("spec").Child("upgradeStrategy"))...)
+
 	if allErrs != nil {
 		return allErrs
 	}
 
 	return nil
 }
+
+// validateRolloutControls validates the partition and canary settings of the upgrade strategy.
+func validateRolloutControls(strategy *v1alpha1.YurtStaticSetUpgradeStrategy, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	advanced := strings.EqualFold(string(strategy.Type), string(v1alpha1.AdvancedRollingUpdateUpgradeStrategyType))
+
+	if strategy.Partition != nil {
+		if !advanced {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("partition"),
+				"partition is only supported in AdvancedRollingUpdate mode"))
+		} else if *strategy.Partition < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("partition"), *strategy.Partition,
+				"partition must be greater than or equal to 0"))
+		}
+	}
+
+	if strategy.HealthCheck != nil {
+		allErrs = append(allErrs, validateHealthCheck(strategy.HealthCheck, fldPath.Child("healthCheck"))...)
+		// the health check of OTA upgrade is performed by YurtHub, which doesn't share the PID namespace of host
+		if !advanced && strategy.HealthCheck.Exec != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("healthCheck", "exec"),
+				"exec health check is only supported in AdvancedRollingUpdate mode"))
+		}
+	}
+
+	canary := strategy.Canary
+	if canary == nil {
+		return allErrs
+	}
+	canaryPath := fldPath.Child("canary")
+	if !advanced {
+		return append(allErrs, field.Forbidden(canaryPath, "canary is only supported in AdvancedRollingUpdate mode"))
+	}
+
+	if canary.NodeSelector == nil && len(canary.NodePools) == 0 {
+		allErrs = append(allErrs, field.Required(canaryPath,
+			"either nodeSelector or nodePools is required to select canary nodes"))
+	}
+	if canary.NodeSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(canary.NodeSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(canaryPath.Child("nodeSelector"), canary.NodeSelector, err.Error()))
+		}
+	}
+	if canary.AutoPromoteSeconds != nil && *canary.AutoPromoteSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(canaryPath.Child("autoPromoteSeconds"), *canary.AutoPromoteSeconds,
+			"autoPromoteSeconds must be greater than or equal to 0"))
+	}
+
+	return allErrs
+}
+
+// validateHealthCheck validates the health check of the new static pod.
+func validateHealthCheck(hc *v1alpha1.YurtStaticSetHealthCheck, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if hc.GRPC != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("grpc"), "grpc health check is not supported"))
+	}
+
+	actions := 0
+	if hc.Exec != nil {
+		actions++
+		if len(hc.Exec.Command) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("exec", "command"), "exec command is required"))
+		}
+	}
+	if hc.HTTPGet != nil {
+		actions++
+	}
+	if hc.TCPSocket != nil {
+		actions++
+	}
+	if actions != 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath, "",
+			"exactly one of exec, httpGet and tcpSocket must be specified"))
+	}
+
+	for _, f := range []struct {
+		name  string
+		value int32
+	}{
+		{"timeoutSeconds", hc.TimeoutSeconds},
+		{"periodSeconds", hc.PeriodSeconds},
+		{"successThreshold", hc.SuccessThreshold},
+		{"deadlineSeconds", hc.DeadlineSeconds},
+	} {
+		if f.value < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(f.name), f.value, f.name+" must be greater than or equal to 0"))
+		}
+	}
+
+	return allErrs
+}
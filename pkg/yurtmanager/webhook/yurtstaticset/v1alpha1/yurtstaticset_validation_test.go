@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 
 	"github.com/openyurtio/openyurt/pkg/apis/apps/v1alpha1"
@@ -77,6 +78,138 @@ func TestYurtStaticSetHandler_ValidateCreate(t *testing.T) {
 			expectError: true,
 			errorMsg:    "max-unavailable is required in AdvancedRollingUpdate mode",
 		},
+		{
+			name: "should fail when partition is negative",
+			obj: &v1alpha1.YurtStaticSet{
+				Spec: v1alpha1.YurtStaticSetSpec{
+					StaticPodManifest: "manifest",
+					UpgradeStrategy: v1alpha1.YurtStaticSetUpgradeStrategy{
+						Type:           v1alpha1.AdvancedRollingUpdateUpgradeStrategyType,
+						MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+						Partition:      ptr.To[int32](-1),
+					},
+				},
+			},
+			expectError: true,
+			errorMsg:    "partition must be greater than or equal to 0",
+		},
+		{
+			name: "should fail when canary is used in OTA mode",
+			obj: &v1alpha1.YurtStaticSet{
+				Spec: v1alpha1.YurtStaticSetSpec{
+					StaticPodManifest: "manifest",
+					UpgradeStrategy: v1alpha1.YurtStaticSetUpgradeStrategy{
+						Type:   v1alpha1.OTAUpgradeStrategyType,
+						Canary: &v1alpha1.YurtStaticSetCanaryStrategy{NodePools: []string{"hangzhou"}},
+					},
+				},
+			},
+			expectError: true,
+			errorMsg:    "canary is only supported in AdvancedRollingUpdate mode",
+		},
+		{
+			name: "should fail when canary selects no nodes",
+			obj: &v1alpha1.YurtStaticSet{
+				Spec: v1alpha1.YurtStaticSetSpec{
+					StaticPodManifest: "manifest",
+					UpgradeStrategy: v1alpha1.YurtStaticSetUpgradeStrategy{
+						Type:           v1alpha1.AdvancedRollingUpdateUpgradeStrategyType,
+						MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+						Canary:         &v1alpha1.YurtStaticSetCanaryStrategy{},
+					},
+				},
+			},
+			expectError: true,
+			errorMsg:    "either nodeSelector or nodePools is required to select canary nodes",
+		},
+		{
+			name: "should fail when health check has multiple actions",
+			obj: &v1alpha1.YurtStaticSet{
+				Spec: v1alpha1.YurtStaticSetSpec{
+					StaticPodManifest: "manifest",
+					UpgradeStrategy: v1alpha1.YurtStaticSetUpgradeStrategy{
+						Type:           v1alpha1.AdvancedRollingUpdateUpgradeStrategyType,
+						MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+						HealthCheck: &v1alpha1.YurtStaticSetHealthCheck{
+							ProbeHandler: corev1.ProbeHandler{
+								HTTPGet:   &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt32(10267)},
+								TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(10267)},
+							},
+						},
+					},
+				},
+			},
+			expectError: true,
+			errorMsg:    "exactly one of exec, httpGet and tcpSocket must be specified",
+		},
+		{
+			name: "should fail when exec health check is used in OTA mode",
+			obj: &v1alpha1.YurtStaticSet{
+				Spec: v1alpha1.YurtStaticSetSpec{
+					StaticPodManifest: "manifest",
+					UpgradeStrategy: v1alpha1.YurtStaticSetUpgradeStrategy{
+						Type: v1alpha1.OTAUpgradeStrategyType,
+						HealthCheck: &v1alpha1.YurtStaticSetHealthCheck{
+							ProbeHandler: corev1.ProbeHandler{
+								Exec: &corev1.ExecAction{Command: []string{"true"}},
+							},
+						},
+					},
+				},
+			},
+			expectError: true,
+			errorMsg:    "exec health check is only supported in AdvancedRollingUpdate mode",
+		},
+		{
+			name: "should pass when tcp health check is used in OTA mode",
+			obj: &v1alpha1.YurtStaticSet{
+				Spec: v1alpha1.YurtStaticSetSpec{
+					StaticPodManifest: "manifest",
+					UpgradeStrategy: v1alpha1.YurtStaticSetUpgradeStrategy{
+						Type: v1alpha1.OTAUpgradeStrategyType,
+						HealthCheck: &v1alpha1.YurtStaticSetHealthCheck{
+							ProbeHandler: corev1.ProbeHandler{
+								TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(10267)},
+							},
+						},
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: buildValidPod().ObjectMeta,
+						Spec:       buildValidPod().Spec,
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "should pass when canary and partition are valid",
+			obj: &v1alpha1.YurtStaticSet{
+				Spec: v1alpha1.YurtStaticSetSpec{
+					StaticPodManifest: "manifest",
+					UpgradeStrategy: v1alpha1.YurtStaticSetUpgradeStrategy{
+						Type:           v1alpha1.AdvancedRollingUpdateUpgradeStrategyType,
+						MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+						Partition:      ptr.To[int32](1),
+						Canary: &v1alpha1.YurtStaticSetCanaryStrategy{
+							NodeSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"canary": "true"},
+							},
+							AutoPromoteSeconds: ptr.To[int32](300),
+						},
+						HealthCheck: &v1alpha1.YurtStaticSetHealthCheck{
+							ProbeHandler: corev1.ProbeHandler{
+								HTTPGet: &corev1.HTTPGetAction{Path: "/v1/healthz", Port: intstr.FromInt32(10267)},
+							},
+						},
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: buildValidPod().ObjectMeta,
+						Spec:       buildValidPod().Spec,
+					},
+				},
+			},
+			expectError: false,
+		},
 		{
 			name: "should pass when YurtStaticSet is valid",
 			obj: &v1alpha1.YurtStaticSet{
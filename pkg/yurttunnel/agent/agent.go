@@ -18,6 +18,9 @@ package agent
 
 import (
 	"crypto/tls"
+	"time"
+
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/session"
 )
 
 // TunnelAgent sets up tunnel to TunnelServer, receive requests
@@ -26,14 +29,20 @@ type TunnelAgent interface {
 	Run(<-chan struct{})
 }
 
-// NewTunnelAgent generates a new TunnelAgent
+// NewTunnelAgent generates a new TunnelAgent, the connections to the
+// TunnelServer are carried by resumable sessions if sessionCfg is not nil.
 func NewTunnelAgent(tlsCfg *tls.Config,
-	tunnelServerAddr, nodeName, agentIdentifiers string) TunnelAgent {
+	tunnelServerAddr, nodeName, agentIdentifiers string,
+	syncInterval, probeInterval time.Duration,
+	sessionCfg *session.Config) TunnelAgent {
 	ata := anpTunnelAgent{
 		tlsCfg:           tlsCfg,
 		tunnelServerAddr: tunnelServerAddr,
 		nodeName:         nodeName,
 		agentIdentifiers: agentIdentifiers,
+		syncInterval:     syncInterval,
+		probeInterval:    probeInterval,
+		sessionCfg:       sessionCfg,
 	}
 
 	return &ata
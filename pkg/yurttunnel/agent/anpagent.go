@@ -26,6 +26,8 @@ import (
 	anpagent "sigs.k8s.io/apiserver-network-proxy/pkg/agent"
 
 	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/agent/metrics"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/session"
 )
 
 // anpTunnelAgent implements the TunnelAgent using the
@@ -35,20 +37,37 @@ type anpTunnelAgent struct {
 	tunnelServerAddr string
 	nodeName         string
 	agentIdentifiers string
+	syncInterval     time.Duration
+	probeInterval    time.Duration
+	sessionCfg       *session.Config
 }
 
 var _ TunnelAgent = &anpTunnelAgent{}
 
 // RunAgent runs the yurttunnel-agent which will try to connect yurttunnel-server
 func (ata *anpTunnelAgent) Run(stopChan <-chan struct{}) {
-	dialOption := grpc.WithTransportCredentials(credentials.NewTLS(ata.tlsCfg))
+	creds := credentials.NewTLS(ata.tlsCfg)
+	if ata.sessionCfg != nil {
+		creds = session.NewTransportCredentials(creds)
+	}
+	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if ata.sessionCfg != nil {
+		// the tls connection is established on top of the resumable
+		// session, so it survives the loss of the tcp connection.
+		sessionCfg := *ata.sessionCfg
+		sessionCfg.OnReconnect = metrics.Metrics.IncSessionReconnects
+		sessionCfg.OnResume = metrics.Metrics.IncResumedSessions
+		sessionCfg.OnExpire = metrics.Metrics.IncExpiredSessions
+		dialOptions = append(dialOptions, grpc.WithContextDialer(session.NewDialer(&sessionCfg, nil)))
+	}
+
 	cc := &anpagent.ClientSetConfig{
 		Address:                 ata.tunnelServerAddr,
 		AgentID:                 ata.nodeName,
 		AgentIdentifiers:        ata.agentIdentifiers,
-		SyncInterval:            5 * time.Second,
-		ProbeInterval:           5 * time.Second,
-		DialOptions:             []grpc.DialOption{dialOption},
+		SyncInterval:            ata.syncInterval,
+		ProbeInterval:           ata.probeInterval,
+		DialOptions:             dialOptions,
 		ServiceAccountTokenPath: "",
 	}
 
@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+)
+
+var (
+	namespace = strings.ReplaceAll(projectinfo.GetTunnelName(), "-", "_")
+	subsystem = "agent"
+)
+
+var (
+	// Metrics provides access to all tunnel agent metrics.
+	Metrics = newTunnelAgentMetrics()
+)
+
+type TunnelAgentMetrics struct {
+	sessionReconnectsCounter prometheus.Counter
+	sessionResumedCounter    prometheus.Counter
+	sessionExpiredCounter    prometheus.Counter
+}
+
+func newTunnelAgentMetrics() *TunnelAgentMetrics {
+	sessionReconnectsCounter := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "session_reconnects_total",
+			Help:      "the number of attempts to reconnect tunnel server for resuming sessions",
+		})
+	sessionResumedCounter := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "resumed_sessions_total",
+			Help:      "the number of sessions resumed after the connection to tunnel server is lost",
+		})
+	sessionExpiredCounter := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "expired_sessions_total",
+			Help:      "the number of sessions closed because they could not be resumed in time",
+		})
+
+	prometheus.MustRegister(sessionReconnectsCounter)
+	prometheus.MustRegister(sessionResumedCounter)
+	prometheus.MustRegister(sessionExpiredCounter)
+	return &TunnelAgentMetrics{
+		sessionReconnectsCounter: sessionReconnectsCounter,
+		sessionResumedCounter:    sessionResumedCounter,
+		sessionExpiredCounter:    sessionExpiredCounter,
+	}
+}
+
+func (tam *TunnelAgentMetrics) IncSessionReconnects() {
+	tam.sessionReconnectsCounter.Inc()
+}
+
+func (tam *TunnelAgentMetrics) IncResumedSessions() {
+	tam.sessionResumedCounter.Inc()
+}
+
+func (tam *TunnelAgentMetrics) IncExpiredSessions() {
+	tam.sessionExpiredCounter.Inc()
+}
@@ -0,0 +1,451 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accesspolicy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	coreinformer "k8s.io/client-go/informers/core/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/constants"
+	hw "github.com/openyurtio/openyurt/pkg/yurttunnel/handlerwrapper"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/util"
+)
+
+const (
+	// minBurstBytes is the min burst of bandwidth limiters, so that a single
+	// write of common size doesn't need to be split.
+	minBurstBytes = 32 * 1024
+	// limiterIdleTTL is how long the bandwidth limiter of a node is kept
+	// after its last request, so that the bursts of successive requests
+	// are still limited, and the limiters of unresolved hosts don't pile up.
+	limiterIdleTTL = 5 * time.Minute
+)
+
+// accessPolicyMiddleware restricts the requests proxied to edge nodes by the
+// access policy, and limits the concurrency and bandwidth per node.
+type accessPolicyMiddleware struct {
+	sync.RWMutex
+	policy             *Policy
+	nodeLister         corelisters.NodeLister
+	getNodesByIP       func(nodeIP string) ([]*corev1.Node, error)
+	nodeInformerSynced cache.InformerSynced
+	cmInformerSynced   cache.InformerSynced
+
+	limitsLock sync.Mutex
+	inflight   map[string]int
+	limiters   map[string]*nodeLimiter
+	lastPruned time.Time
+	clock      clock.Clock
+}
+
+// nodeLimiter is the bandwidth limiter of a node and the last time it's used.
+type nodeLimiter struct {
+	*rate.Limiter
+	lastUsed time.Time
+}
+
+func NewAccessPolicyMiddleware() hw.Middleware {
+	return &accessPolicyMiddleware{
+		policy:   &Policy{},
+		inflight: make(map[string]int),
+		limiters: make(map[string]*nodeLimiter),
+		clock:    clock.RealClock{},
+	}
+}
+
+func (apm *accessPolicyMiddleware) Name() string {
+	return "accessPolicyMiddleware"
+}
+
+// WrapHandler checks requests against the access policy before they are
+// proxied to the edge node.
+func (apm *accessPolicyMiddleware) WrapHandler(handler http.Handler) http.Handler {
+	// wait for nodes and configmaps have synced
+	if !cache.WaitForCacheSync(wait.NeverStop, apm.nodeInformerSynced, apm.cmInformerSynced) {
+		klog.Error("could not sync node or configmap cache")
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		apm.RLock()
+		policy := apm.policy
+		apm.RUnlock()
+
+		nodeName, nodePool, port, resolved := apm.resolveTarget(req)
+		clientName := getRequestClient(req)
+		if !resolved && len(policy.NodePools) != 0 {
+			// the rule of nodepool can not be skipped by the requests whose
+			// target node is unknown.
+			deny(w, req, http.StatusForbidden, clientName, nodeName, nodePool, "the target node can not be resolved")
+			return
+		}
+		rule := policy.ruleFor(nodePool)
+		if rule == nil {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		reqType := getRequestType(req)
+		if ok, reason := rule.allows(reqType, clientName, port); !ok {
+			deny(w, req, http.StatusForbidden, clientName, nodeName, nodePool, reason)
+			return
+		}
+
+		if !apm.acquire(nodeName, rule.MaxConcurrentRequests) {
+			deny(w, req, http.StatusTooManyRequests, clientName, nodeName, nodePool,
+				fmt.Sprintf("the number of concurrent requests exceeds %d", rule.MaxConcurrentRequests))
+			return
+		}
+		defer apm.release(nodeName)
+
+		if rule.MaxBytesPerSecond > 0 {
+			w = &limitedResponseWriter{
+				ResponseWriter: w,
+				ctx:            req.Context(),
+				limiter:        apm.limiterFor(nodeName, rule.MaxBytesPerSecond),
+			}
+		}
+		klog.V(4).Infof("access policy allows %s request %s from client %s to node %s", reqType, req.URL.String(), clientName, nodeName)
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// SetSharedInformerFactory init node lister and configmap event handler for WrapHandler
+func (apm *accessPolicyMiddleware) SetSharedInformerFactory(factory informers.SharedInformerFactory) error {
+	if factory == nil {
+		return errors.New("shared informer factory should not be nil")
+	}
+
+	nodeInformer := factory.Core().V1().Nodes()
+	apm.nodeLister = nodeInformer.Lister()
+	// the node ip index is added by localhost proxy middleware, nodes can
+	// only be resolved by node name if the index doesn't exist.
+	apm.getNodesByIP = func(nodeIP string) ([]*corev1.Node, error) {
+		objs, err := nodeInformer.Informer().GetIndexer().ByIndex(constants.NodeIPKeyIndex, nodeIP)
+		if err != nil {
+			return nil, err
+		}
+
+		nodes := make([]*corev1.Node, 0, len(objs))
+		for _, obj := range objs {
+			if node, ok := obj.(*corev1.Node); ok {
+				nodes = append(nodes, node)
+			}
+		}
+		return nodes, nil
+	}
+	apm.nodeInformerSynced = nodeInformer.Informer().HasSynced
+
+	cmInformer := factory.InformerFor(&corev1.ConfigMap{}, newConfigMapInformer)
+	cmInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    apm.addConfigMap,
+		UpdateFunc: apm.updateConfigMap,
+		DeleteFunc: apm.deleteConfigMap,
+	})
+	apm.cmInformerSynced = cmInformer.HasSynced
+
+	return nil
+}
+
+// newConfigMapInformer creates a shared index informer that returns only interested configmaps
+func newConfigMapInformer(cs clientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	selector := fmt.Sprintf("metadata.name=%v", util.YurttunnelServerDnatConfigMapName)
+	tweakListOptions := func(options *metav1.ListOptions) {
+		options.FieldSelector = selector
+	}
+	return coreinformer.NewFilteredConfigMapInformer(cs, util.YurttunnelServerDnatConfigMapNs, resyncPeriod, nil, tweakListOptions)
+}
+
+// addConfigMap handle configmap add event
+func (apm *accessPolicyMiddleware) addConfigMap(obj interface{}) {
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	if cm.DeletionTimestamp != nil {
+		return
+	}
+	klog.V(2).Infof("handle configmap add event for %v/%v to update access policy", cm.Namespace, cm.Name)
+	apm.replacePolicy(cm.Data[util.YurtTunnelAccessPolicy])
+}
+
+// updateConfigMap handle configmap update event
+func (apm *accessPolicyMiddleware) updateConfigMap(oldObj, newObj interface{}) {
+	oldConfigMap, ok := oldObj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	newConfigMap, ok := newObj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+
+	if oldConfigMap.Data[util.YurtTunnelAccessPolicy] == newConfigMap.Data[util.YurtTunnelAccessPolicy] {
+		return
+	}
+
+	klog.V(2).Infof("handle configmap update event for %v/%v to update access policy", newConfigMap.Namespace, newConfigMap.Name)
+	apm.replacePolicy(newConfigMap.Data[util.YurtTunnelAccessPolicy])
+}
+
+// deleteConfigMap handle configmap delete event, all requests are allowed
+// without the configmap.
+func (apm *accessPolicyMiddleware) deleteConfigMap(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return
+	}
+	klog.V(2).Infof("handle configmap delete event for %v/%v to reset access policy", cm.Namespace, cm.Name)
+	apm.Lock()
+	defer apm.Unlock()
+	apm.policy = &Policy{}
+}
+
+// replacePolicy replaces the access policy, the current policy is kept if
+// the new one is invalid.
+func (apm *accessPolicyMiddleware) replacePolicy(policyStr string) {
+	policy, err := parsePolicy(policyStr)
+	if err != nil {
+		klog.Errorf("could not update access policy, keep using the current one, %v", err)
+		return
+	}
+
+	apm.Lock()
+	defer apm.Unlock()
+	apm.policy = policy
+}
+
+// resolveTarget returns the name, nodepool and port of the node that the
+// request will be proxied to, and whether the node is resolved. The node is
+// resolved by the proxy host header, or by the host of request which is the
+// node name(resolved by yurt-tunnel-dns) or node ip. If the node can not be
+// resolved, the host of request is used as node name.
+func (apm *accessPolicyMiddleware) resolveTarget(req *http.Request) (string, string, string, bool) {
+	host, port, err := net.SplitHostPort(req.Host)
+	if err != nil {
+		host = req.Host
+	}
+
+	var node *corev1.Node
+	nodeName := req.Header.Get(constants.ProxyHostHeaderKey)
+	if name, _, err := net.SplitHostPort(nodeName); err == nil {
+		nodeName = name
+	}
+	if len(nodeName) != 0 {
+		if node, err = apm.nodeLister.Get(nodeName); err != nil {
+			klog.V(4).Infof("could not get node %s for access policy, %v", nodeName, err)
+		}
+	} else if n, err := apm.nodeLister.Get(host); err == nil {
+		node = n
+		nodeName = node.Name
+	} else if nodes, err := apm.getNodesByIP(host); err == nil && len(nodes) == 1 {
+		node = nodes[0]
+		nodeName = node.Name
+	}
+
+	if len(nodeName) == 0 {
+		nodeName = host
+	}
+	if node == nil {
+		return nodeName, "", port, false
+	}
+	return nodeName, node.Labels[projectinfo.GetNodePoolLabel()], port, true
+}
+
+// acquire takes a slot of concurrent requests to the node, limit <= 0 means
+// no limitation.
+func (apm *accessPolicyMiddleware) acquire(nodeName string, limit int) bool {
+	apm.limitsLock.Lock()
+	defer apm.limitsLock.Unlock()
+	if limit > 0 && apm.inflight[nodeName] >= limit {
+		return false
+	}
+	apm.inflight[nodeName]++
+	return true
+}
+
+func (apm *accessPolicyMiddleware) release(nodeName string) {
+	apm.limitsLock.Lock()
+	defer apm.limitsLock.Unlock()
+	apm.inflight[nodeName]--
+	if apm.inflight[nodeName] <= 0 {
+		delete(apm.inflight, nodeName)
+	}
+	if limiter, ok := apm.limiters[nodeName]; ok {
+		limiter.lastUsed = apm.clock.Now()
+	}
+}
+
+// limiterFor returns the bandwidth limiter shared by the requests to the
+// node, the limit of the limiter is updated when the policy changes. The
+// limiter is kept after the requests complete, so that successive requests
+// can't bypass the limit, and it's pruned once idle for limiterIdleTTL.
+func (apm *accessPolicyMiddleware) limiterFor(nodeName string, bytesPerSecond int64) *rate.Limiter {
+	apm.limitsLock.Lock()
+	defer apm.limitsLock.Unlock()
+	now := apm.clock.Now()
+	apm.pruneLimiters(now)
+
+	burst := int(max(bytesPerSecond, minBurstBytes))
+	limiter, ok := apm.limiters[nodeName]
+	if !ok {
+		limiter = &nodeLimiter{Limiter: rate.NewLimiter(rate.Limit(bytesPerSecond), burst)}
+		apm.limiters[nodeName] = limiter
+	} else if limiter.Limit() != rate.Limit(bytesPerSecond) {
+		limiter.SetLimit(rate.Limit(bytesPerSecond))
+		limiter.SetBurst(burst)
+	}
+	limiter.lastUsed = now
+	return limiter.Limiter
+}
+
+// pruneLimiters drops the limiters of nodes without in-flight requests which
+// are idle for limiterIdleTTL, it runs at most once per limiterIdleTTL and is
+// called with limitsLock held.
+func (apm *accessPolicyMiddleware) pruneLimiters(now time.Time) {
+	if now.Sub(apm.lastPruned) < limiterIdleTTL {
+		return
+	}
+	apm.lastPruned = now
+	for nodeName, limiter := range apm.limiters {
+		if apm.inflight[nodeName] == 0 && now.Sub(limiter.lastUsed) >= limiterIdleTTL {
+			delete(apm.limiters, nodeName)
+		}
+	}
+}
+
+// deny logs the denied request and responds with the reason.
+func deny(w http.ResponseWriter, req *http.Request, code int, clientName, nodeName, nodePool, reason string) {
+	klog.Warningf("access policy denied request %s %s from client %s(%s) to node %s in nodepool %q, %s",
+		req.Method, req.URL.String(), clientName, req.RemoteAddr, nodeName, nodePool, reason)
+	http.Error(w, fmt.Sprintf("request to node %s is denied by tunnel server access policy: %s", nodeName, reason), code)
+}
+
+// waitN waits until n bytes are allowed by the limiter.
+func waitN(ctx context.Context, limiter *rate.Limiter, n int) error {
+	for n > 0 {
+		chunk := min(n, limiter.Burst())
+		if err := limiter.WaitN(ctx, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+// limitedResponseWriter limits the bandwidth of responses, including the
+// streams of hijacked connections for exec and port-forward requests.
+type limitedResponseWriter struct {
+	http.ResponseWriter
+	ctx     context.Context
+	limiter *rate.Limiter
+}
+
+func (lw *limitedResponseWriter) Write(b []byte) (int, error) {
+	if err := waitN(lw.ctx, lw.limiter, len(b)); err != nil {
+		return 0, err
+	}
+	return lw.ResponseWriter.Write(b)
+}
+
+func (lw *limitedResponseWriter) Flush() {
+	if flusher, ok := lw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (lw *limitedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := lw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer is not a http.Hijacker")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	lc := newLimitedConn(conn, lw.limiter)
+
+	// the data buffered by the reader of the hijacked connection has been
+	// received already, it's read ahead of the limited connection.
+	buffered, err := rw.Reader.Peek(rw.Reader.Buffered())
+	if err != nil {
+		lc.Close()
+		return nil, nil, err
+	}
+	reader := io.MultiReader(bytes.NewReader(bytes.Clone(buffered)), lc)
+	return lc, bufio.NewReadWriter(bufio.NewReader(reader), bufio.NewWriter(lc)), nil
+}
+
+// limitedConn limits the bandwidth in both directions of a hijacked
+// connection, the waits for the limiter are cancelled when it's closed.
+type limitedConn struct {
+	net.Conn
+	limiter *rate.Limiter
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func newLimitedConn(conn net.Conn, limiter *rate.Limiter) *limitedConn {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &limitedConn{Conn: conn, limiter: limiter, ctx: ctx, cancel: cancel}
+}
+
+func (lc *limitedConn) Read(b []byte) (int, error) {
+	n, err := lc.Conn.Read(b)
+	if n > 0 {
+		if werr := waitN(lc.ctx, lc.limiter, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return n, err
+}
+
+func (lc *limitedConn) Write(b []byte) (int, error) {
+	if err := waitN(lc.ctx, lc.limiter, len(b)); err != nil {
+		return 0, err
+	}
+	return lc.Conn.Write(b)
+}
+
+func (lc *limitedConn) Close() error {
+	lc.cancel()
+	return lc.Conn.Close()
+}
@@ -0,0 +1,409 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accesspolicy
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	testingclock "k8s.io/utils/clock/testing"
+
+	"github.com/openyurtio/openyurt/pkg/projectinfo"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/constants"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/util"
+)
+
+const testPolicy = `
+default:
+  allowedRequestTypes: ["logs", "exec", "port-forward", "metrics"]
+nodePools:
+  hangzhou:
+    allowedRequestTypes: ["logs", "metrics"]
+    allowedClients: ["kube-apiserver-kubelet-client"]
+    allowedPorts: [10250]
+    maxConcurrentRequests: 1
+`
+
+func newNode(name, ip, nodePool string) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{},
+		},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: ip},
+			},
+		},
+	}
+	if len(nodePool) != 0 {
+		node.Labels[projectinfo.GetNodePoolLabel()] = nodePool
+	}
+	return node
+}
+
+func nodeInternalIP(obj interface{}) ([]string, error) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return []string{}, nil
+	}
+	return []string{node.Status.Addresses[0].Address}, nil
+}
+
+func newTestHandler(t *testing.T, policy string, handler http.Handler) (*accessPolicyMiddleware, http.Handler) {
+	client := fake.NewSimpleClientset(
+		newNode("edge-1", "192.168.0.1", "hangzhou"),
+		newNode("edge-2", "192.168.0.2", "beijing"),
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      util.YurttunnelServerDnatConfigMapName,
+				Namespace: util.YurttunnelServerDnatConfigMapNs,
+			},
+			Data: map[string]string{
+				util.YurtTunnelAccessPolicy: policy,
+			},
+		},
+	)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	if err := factory.Core().V1().Nodes().Informer().AddIndexers(cache.Indexers{constants.NodeIPKeyIndex: nodeInternalIP}); err != nil {
+		t.Fatalf("could not add node indexer, %v", err)
+	}
+
+	apm := NewAccessPolicyMiddleware().(*accessPolicyMiddleware)
+	if err := apm.SetSharedInformerFactory(factory); err != nil {
+		t.Fatalf("could not set shared informer factory, %v", err)
+	}
+	stopCh := make(chan struct{})
+	t.Cleanup(func() {
+		close(stopCh)
+	})
+	factory.Start(stopCh)
+	return apm, apm.WrapHandler(handler)
+}
+
+func newRequest(host, path, clientName string, header map[string]string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "https://"+host+path, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	if len(clientName) != 0 {
+		req.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: clientName}}},
+		}
+	} else {
+		req.TLS = nil
+	}
+	return req
+}
+
+func TestAccessPolicy(t *testing.T) {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	_, handler := newTestHandler(t, testPolicy, okHandler)
+
+	testcases := map[string]struct {
+		req        *http.Request
+		statusCode int
+	}{
+		"logs request to node in nodepool": {
+			req:        newRequest("192.168.0.1:10250", "/containerLogs/default/foo/bar", "kube-apiserver-kubelet-client", nil),
+			statusCode: http.StatusOK,
+		},
+		"exec request to node in nodepool": {
+			req:        newRequest("192.168.0.1:10250", "/exec/default/foo/bar", "kube-apiserver-kubelet-client", nil),
+			statusCode: http.StatusForbidden,
+		},
+		"request from not allowed client": {
+			req:        newRequest("192.168.0.1:10250", "/metrics/cadvisor", "prometheus", nil),
+			statusCode: http.StatusForbidden,
+		},
+		"anonymous request": {
+			req:        newRequest("192.168.0.1:10250", "/metrics", "", nil),
+			statusCode: http.StatusForbidden,
+		},
+		"request to not allowed port": {
+			req:        newRequest("192.168.0.1:9100", "/metrics", "kube-apiserver-kubelet-client", nil),
+			statusCode: http.StatusForbidden,
+		},
+		"request to node resolved by proxy host header": {
+			req: newRequest("127.0.0.1:10250", "/exec/default/foo/bar", "kube-apiserver-kubelet-client", map[string]string{
+				constants.ProxyHostHeaderKey: "edge-1:10250",
+			}),
+			statusCode: http.StatusForbidden,
+		},
+		"exec request to node with default rule": {
+			req:        newRequest("192.168.0.2:10250", "/exec/default/foo/bar", "prometheus", nil),
+			statusCode: http.StatusOK,
+		},
+		"other request to node with default rule": {
+			req:        newRequest("192.168.0.2:10250", "/configz", "prometheus", nil),
+			statusCode: http.StatusForbidden,
+		},
+		"request to node resolved by host name": {
+			req:        newRequest("edge-1:10250", "/exec/default/foo/bar", "kube-apiserver-kubelet-client", nil),
+			statusCode: http.StatusForbidden,
+		},
+		"logs request to node resolved by host name": {
+			req:        newRequest("edge-1:10250", "/containerLogs/default/foo/bar", "kube-apiserver-kubelet-client", nil),
+			statusCode: http.StatusOK,
+		},
+		"request to unknown node with nodepool rules": {
+			req:        newRequest("10.0.0.1:9100", "/metrics", "", nil),
+			statusCode: http.StatusForbidden,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tc.req)
+			if w.Code != tc.statusCode {
+				t.Errorf("expect status code %d, but got %d", tc.statusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestAccessPolicyUnknownNodeWithDefaultRule(t *testing.T) {
+	_, handler := newTestHandler(t, `
+default:
+  allowedRequestTypes: ["metrics"]
+`, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("10.0.0.1:9100", "/metrics", "", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expect status code %d, but got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestAccessPolicyNotConfigured(t *testing.T) {
+	_, handler := newTestHandler(t, "", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("192.168.0.1:10255", "/exec/default/foo/bar", "", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expect status code %d, but got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestAccessPolicyConcurrency(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	_, handler := newTestHandler(t, testPolicy, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	first := httptest.NewRecorder()
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(first, newRequest("192.168.0.1:10250", "/containerLogs/default/foo/bar", "kube-apiserver-kubelet-client", nil))
+	}()
+	<-started
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, newRequest("192.168.0.1:10250", "/containerLogs/default/foo/bar", "kube-apiserver-kubelet-client", nil))
+	if second.Code != http.StatusTooManyRequests {
+		t.Errorf("expect status code %d, but got %d", http.StatusTooManyRequests, second.Code)
+	}
+
+	close(release)
+	wg.Wait()
+	if first.Code != http.StatusOK {
+		t.Errorf("expect status code %d, but got %d", http.StatusOK, first.Code)
+	}
+}
+
+func TestAccessPolicyBandwidth(t *testing.T) {
+	apm, handler := newTestHandler(t, `
+default:
+  maxBytesPerSecond: 65536
+`, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write(make([]byte, 96*1024))
+	}))
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newRequest("192.168.0.2:10250", "/containerLogs/default/foo/bar", "", nil))
+	// the first 64KB is allowed by the burst, and the remaining 32KB takes 0.5s
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("expect response to be throttled, but it only takes %v", elapsed)
+	}
+	if w.Body.Len() != 96*1024 {
+		t.Errorf("expect %d bytes in response, but got %d", 96*1024, w.Body.Len())
+	}
+	if len(apm.inflight) != 0 {
+		t.Errorf("expect no in-flight requests, but got %d", len(apm.inflight))
+	}
+	if len(apm.limiters) != 1 {
+		t.Errorf("expect limiter is kept after the request completes, but got %d limiters", len(apm.limiters))
+	}
+}
+
+func TestPruneLimiters(t *testing.T) {
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	apm := NewAccessPolicyMiddleware().(*accessPolicyMiddleware)
+	apm.clock = fakeClock
+
+	apm.limiterFor("edge-1", 1024)
+	if !apm.acquire("edge-2", 0) {
+		t.Fatalf("expect request to edge-2 is allowed")
+	}
+	apm.limiterFor("edge-2", 1024)
+
+	fakeClock.Step(limiterIdleTTL)
+	apm.limiterFor("edge-3", 1024)
+	if _, ok := apm.limiters["edge-1"]; ok {
+		t.Errorf("expect idle limiter of edge-1 is pruned")
+	}
+	if _, ok := apm.limiters["edge-2"]; !ok {
+		t.Errorf("expect limiter of edge-2 with in-flight requests is kept")
+	}
+
+	apm.release("edge-2")
+	fakeClock.Step(limiterIdleTTL)
+	apm.limiterFor("edge-3", 1024)
+	if len(apm.limiters) != 1 {
+		t.Errorf("expect only limiter of edge-3 is kept, but got %d limiters", len(apm.limiters))
+	}
+}
+
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+func (r *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.conn, r.rw, nil
+}
+
+func TestLimitedResponseWriterHijack(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	// the data buffered by the reader of hijacked connection
+	reader := bufio.NewReader(io.MultiReader(strings.NewReader("hello"), server))
+	if _, err := reader.Peek(5); err != nil {
+		t.Fatalf("could not fill the reader, %v", err)
+	}
+	lw := &limitedResponseWriter{
+		ResponseWriter: &hijackableRecorder{
+			ResponseRecorder: httptest.NewRecorder(),
+			conn:             server,
+			rw:               bufio.NewReadWriter(reader, bufio.NewWriter(server)),
+		},
+		ctx:     context.Background(),
+		limiter: rate.NewLimiter(1, 1),
+	}
+
+	conn, rw, err := lw.Hijack()
+	if err != nil {
+		t.Fatalf("could not hijack, %v", err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(rw, buf); err != nil || string(buf) != "hello" {
+		t.Errorf("expect buffered data hello, but got %q, %v", buf, err)
+	}
+
+	// the write throttled by the limiter is cancelled when the connection is closed
+	written := make(chan error, 1)
+	go func() {
+		_, err := rw.Write(make([]byte, 10))
+		if err == nil {
+			err = rw.Flush()
+		}
+		written <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	conn.Close()
+	select {
+	case err := <-written:
+		if err == nil {
+			t.Errorf("expect write fails after the connection is closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("expect throttled write is cancelled after the connection is closed")
+	}
+}
+
+func TestDeleteConfigMap(t *testing.T) {
+	apm := NewAccessPolicyMiddleware().(*accessPolicyMiddleware)
+	apm.replacePolicy(testPolicy)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.YurttunnelServerDnatConfigMapName,
+			Namespace: util.YurttunnelServerDnatConfigMapNs,
+		},
+	}
+	apm.deleteConfigMap(cache.DeletedFinalStateUnknown{Key: "kube-system/" + cm.Name, Obj: cm})
+	if rule := apm.policy.ruleFor("hangzhou"); rule != nil {
+		t.Errorf("expect all requests are allowed after configmap is deleted, but got rule %v", rule)
+	}
+}
+
+func TestReplacePolicy(t *testing.T) {
+	apm := NewAccessPolicyMiddleware().(*accessPolicyMiddleware)
+
+	apm.replacePolicy(testPolicy)
+	if rule := apm.policy.ruleFor("hangzhou"); rule == nil || rule.MaxConcurrentRequests != 1 {
+		t.Fatalf("expect rule of nodepool hangzhou is loaded, but got %v", rule)
+	}
+
+	invalidPolicies := map[string]string{
+		"unknown request type": "default:\n  allowedRequestTypes: [\"proxy\"]\n",
+		"unknown field":        "default:\n  allowedPaths: [\"/logs\"]\n",
+		"invalid port":         "nodePools:\n  hangzhou:\n    allowedPorts: [70000]\n",
+		"negative limit":       "default:\n  maxBytesPerSecond: -1\n",
+	}
+	for k, policy := range invalidPolicies {
+		apm.replacePolicy(policy)
+		if rule := apm.policy.ruleFor("hangzhou"); rule == nil || rule.MaxConcurrentRequests != 1 {
+			t.Errorf("%s: expect current policy is kept, but got %v", k, rule)
+		}
+	}
+
+	apm.replacePolicy("")
+	if rule := apm.policy.ruleFor("hangzhou"); rule != nil {
+		t.Errorf("expect no rule after policy is removed, but got %v", rule)
+	}
+}
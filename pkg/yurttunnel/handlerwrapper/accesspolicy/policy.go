@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accesspolicy
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"sigs.k8s.io/yaml"
+)
+
+// RequestType is the kind of request proxied to the edge node
+type RequestType string
+
+const (
+	RequestTypeLogs        RequestType = "logs"
+	RequestTypeExec        RequestType = "exec"
+	RequestTypePortForward RequestType = "port-forward"
+	RequestTypeMetrics     RequestType = "metrics"
+	RequestTypeOther       RequestType = "other"
+)
+
+var (
+	// requestTypesByPathPrefix maps the first segment of kubelet api path to request type
+	requestTypesByPathPrefix = map[string]RequestType{
+		"containerLogs": RequestTypeLogs,
+		"logs":          RequestTypeLogs,
+		"exec":          RequestTypeExec,
+		"attach":        RequestTypeExec,
+		"run":           RequestTypeExec,
+		"portForward":   RequestTypePortForward,
+		"metrics":       RequestTypeMetrics,
+		"stats":         RequestTypeMetrics,
+	}
+
+	knownRequestTypes = sets.New(RequestTypeLogs, RequestTypeExec, RequestTypePortForward, RequestTypeMetrics, RequestTypeOther)
+)
+
+// Policy is the access policy configured in the access-policy field of
+// tunnel server configmap, e.g.
+//
+//	default:
+//	  allowedRequestTypes: ["logs", "exec", "port-forward", "metrics"]
+//	nodePools:
+//	  hangzhou:
+//	    allowedRequestTypes: ["logs", "metrics"]
+//	    allowedClients: ["kube-apiserver-kubelet-client"]
+//	    allowedPorts: [10250]
+//	    maxConcurrentRequests: 10
+//	    maxBytesPerSecond: 1048576
+//
+// The rule of a NodePool applies to all nodes in the NodePool, and the
+// default rule applies to the nodes that don't belong to any NodePool in
+// the policy. No restriction is applied if the policy is empty. If any
+// NodePool rule is configured, the requests whose target node can not be
+// resolved are denied.
+type Policy struct {
+	Default   *Rule           `json:"default,omitempty"`
+	NodePools map[string]Rule `json:"nodePools,omitempty"`
+}
+
+// Rule restricts the requests proxied to a node, empty fields mean no restriction.
+type Rule struct {
+	// AllowedRequestTypes are the allowed request types: logs, exec,
+	// port-forward, metrics and other.
+	AllowedRequestTypes []RequestType `json:"allowedRequestTypes,omitempty"`
+	// AllowedClients are the allowed client identities. The identity of a
+	// request is the common name of the client certificate presented to the
+	// tunnel server, or system:anonymous without it. Note that the requests
+	// from kube-apiserver always carry its kubelet client certificate, so the
+	// end users behind kube-apiserver can not be told apart by this field.
+	AllowedClients []string `json:"allowedClients,omitempty"`
+	// AllowedPorts are the allowed target ports on the node.
+	AllowedPorts []int32 `json:"allowedPorts,omitempty"`
+	// MaxConcurrentRequests is the max number of in-flight requests per node.
+	MaxConcurrentRequests int `json:"maxConcurrentRequests,omitempty"`
+	// MaxBytesPerSecond is the max bandwidth per node, in bytes per second.
+	MaxBytesPerSecond int64 `json:"maxBytesPerSecond,omitempty"`
+}
+
+// parsePolicy parses and validates the access policy.
+func parsePolicy(data string) (*Policy, error) {
+	policy := &Policy{}
+	if len(strings.TrimSpace(data)) == 0 {
+		return policy, nil
+	}
+	if err := yaml.UnmarshalStrict([]byte(data), policy); err != nil {
+		return nil, fmt.Errorf("could not parse access policy, %w", err)
+	}
+
+	if policy.Default != nil {
+		if err := policy.Default.validate(); err != nil {
+			return nil, fmt.Errorf("default rule is invalid, %w", err)
+		}
+	}
+	for pool, rule := range policy.NodePools {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("rule of nodepool %s is invalid, %w", pool, err)
+		}
+	}
+	return policy, nil
+}
+
+func (r *Rule) validate() error {
+	for _, t := range r.AllowedRequestTypes {
+		if !knownRequestTypes.Has(t) {
+			return fmt.Errorf("unknown request type %q, supported types are %v", t, sets.List(knownRequestTypes))
+		}
+	}
+	for _, port := range r.AllowedPorts {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("port %d is out of range", port)
+		}
+	}
+	if r.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("maxConcurrentRequests %d should not be negative", r.MaxConcurrentRequests)
+	}
+	if r.MaxBytesPerSecond < 0 {
+		return fmt.Errorf("maxBytesPerSecond %d should not be negative", r.MaxBytesPerSecond)
+	}
+	return nil
+}
+
+// ruleFor returns the rule for nodes in the specified nodepool, nil means
+// no restriction.
+func (p *Policy) ruleFor(nodePool string) *Rule {
+	if len(nodePool) != 0 {
+		if rule, ok := p.NodePools[nodePool]; ok {
+			return &rule
+		}
+	}
+	return p.Default
+}
+
+// allows checks the request against the rule and returns the reason if the
+// request is denied.
+func (r *Rule) allows(reqType RequestType, clientName, port string) (bool, string) {
+	if len(r.AllowedRequestTypes) != 0 && !slices.Contains(r.AllowedRequestTypes, reqType) {
+		return false, fmt.Sprintf("request type %s is not allowed", reqType)
+	}
+	if len(r.AllowedClients) != 0 && !slices.Contains(r.AllowedClients, clientName) {
+		return false, fmt.Sprintf("client %s is not allowed", clientName)
+	}
+	if len(r.AllowedPorts) != 0 {
+		p, err := strconv.ParseInt(port, 10, 32)
+		if err != nil || !slices.Contains(r.AllowedPorts, int32(p)) {
+			return false, fmt.Sprintf("port %s is not allowed", port)
+		}
+	}
+	return true, ""
+}
+
+// getRequestType resolves the request type from the kubelet api path.
+func getRequestType(req *http.Request) RequestType {
+	parts := strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 2)
+	if t, ok := requestTypesByPathPrefix[parts[0]]; ok {
+		return t
+	}
+	return RequestTypeOther
+}
+
+// getRequestClient returns the common name of the verified client certificate.
+// The logs, exec and port-forward requests proxied by kube-apiserver always
+// present its kubelet client certificate, so the client of them is the
+// identity of kube-apiserver rather than the end user who sends the request,
+// and allowedClients can only tell kube-apiserver from the clients that
+// connect to the tunnel server directly, e.g. prometheus.
+func getRequestClient(req *http.Request) string {
+	if req.TLS != nil && len(req.TLS.PeerCertificates) != 0 {
+		if cn := req.TLS.PeerCertificates[0].Subject.CommonName; len(cn) != 0 {
+			return cn
+		}
+	}
+	return user.Anonymous
+}
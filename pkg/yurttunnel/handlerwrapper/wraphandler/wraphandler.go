@@ -22,6 +22,7 @@ import (
 	"k8s.io/klog/v2"
 
 	hw "github.com/openyurtio/openyurt/pkg/yurttunnel/handlerwrapper"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/handlerwrapper/accesspolicy"
 	"github.com/openyurtio/openyurt/pkg/yurttunnel/handlerwrapper/initializer"
 	"github.com/openyurtio/openyurt/pkg/yurttunnel/handlerwrapper/localhostproxy"
 	"github.com/openyurtio/openyurt/pkg/yurttunnel/handlerwrapper/tracerequest"
@@ -43,6 +44,10 @@ func InitHandlerWrappers(mi initializer.MiddlewareInitializer, isIPv6 bool) (hw.
 	// then the middleware m2 will be called before the mw1
 	wrappers = append(wrappers, tracerequest.NewTraceReqMiddleware())
 	wrappers = append(wrappers, localhostproxy.NewLocalHostProxyMiddleware(isIPv6))
+	// access policy middleware is registered after localhost proxy middleware,
+	// so that it can resolve the node of request by the node ip index
+	// added by localhost proxy middleware.
+	wrappers = append(wrappers, accesspolicy.NewAccessPolicyMiddleware())
 
 	// init all of wrappers
 	for i := range wrappers {
@@ -35,6 +35,8 @@ import (
 	"github.com/openyurtio/openyurt/pkg/yurttunnel/constants"
 	hw "github.com/openyurtio/openyurt/pkg/yurttunnel/handlerwrapper"
 	wh "github.com/openyurtio/openyurt/pkg/yurttunnel/handlerwrapper/wraphandler"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/server/metrics"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/session"
 )
 
 // anpTunnelServer implements the TunnelServer interface using the
@@ -50,6 +52,8 @@ type anpTunnelServer struct {
 	proxyClientTlsCfg        *tls.Config
 	wrappers                 hw.HandlerWrappers
 	proxyStrategy            string
+	agentKeepAlive           keepalive.ServerParameters
+	sessionResumeTimeout     time.Duration
 }
 
 var _ TunnelServer = &anpTunnelServer{}
@@ -90,7 +94,7 @@ func (ats *anpTunnelServer) Run() error {
 	}
 
 	// 3. start the agent server
-	agentServerErr := runAgentServer(ats.tlsCfg, ats.serverAgentAddr, proxyServer)
+	agentServerErr := runAgentServer(ats.tlsCfg, ats.serverAgentAddr, proxyServer, ats.agentKeepAlive, ats.sessionResumeTimeout)
 	if agentServerErr != nil {
 		return fmt.Errorf("could not run agent server: %w", agentServerErr)
 	}
@@ -172,21 +176,21 @@ func runMasterServer(handler http.Handler,
 // runAgentServer runs a grpc server that handles connections from the yurttunel-agent
 // NOTE agent server is responsible for managing grpc connection yurttunel-server
 // and yurttunnel-agent, and the proxy server is responsible for redirecting requests
-// to corresponding yurttunel-agent
+// to corresponding yurttunel-agent. The agents with session resumption enabled
+// are served through resumable sessions, and the others through plain
+// tcp connections.
 func runAgentServer(tlsCfg *tls.Config,
 	agentServerAddr string,
-	proxyServer *anpserver.ProxyServer) error {
-	serverOption := grpc.Creds(credentials.NewTLS(tlsCfg))
-
-	ka := keepalive.ServerParameters{
-		// Ping the client if it is idle for `Time` seconds to ensure the
-		// connection is still active
-		Time: constants.YurttunnelANPGrpcKeepAliveTimeSec * time.Second,
-		// Wait `Timeout` second for the ping ack before assuming the
-		// connection is dead
-		Timeout: constants.YurttunnelANPGrpcKeepAliveTimeoutSec * time.Second,
-	}
-
+	proxyServer *anpserver.ProxyServer,
+	ka keepalive.ServerParameters,
+	sessionResumeTimeout time.Duration) error {
+	// the resume key of sessions is bound after the tls handshake, the
+	// plain connections are not affected.
+	serverOption := grpc.Creds(session.NewTransportCredentials(credentials.NewTLS(tlsCfg)))
+
+	// Ping the client if it is idle for `Time` to ensure the connection is
+	// still active, and wait `Timeout` for the ping ack before assuming the
+	// connection is dead
 	grpcServer := grpc.NewServer(serverOption,
 		grpc.KeepaliveParams(ka))
 
@@ -196,6 +200,11 @@ func runAgentServer(tlsCfg *tls.Config,
 	if err != nil {
 		return fmt.Errorf("could not listen to agent on %s: %w", agentServerAddr, err)
 	}
-	go grpcServer.Serve(listener)
+	sessionListener := session.NewListener(listener, &session.Config{
+		ResumeTimeout: sessionResumeTimeout,
+		OnResume:      metrics.Metrics.IncResumedSessions,
+		OnExpire:      metrics.Metrics.IncExpiredSessions,
+	})
+	go grpcServer.Serve(sessionListener)
 	return nil
 }
@@ -38,6 +38,8 @@ type TunnelServerMetrics struct {
 	proxyingRequestsCollector *prometheus.GaugeVec
 	proxyingRequestsGauge     prometheus.Gauge
 	cloudNodeGauge            prometheus.Gauge
+	sessionResumedCounter     prometheus.Counter
+	sessionExpiredCounter     prometheus.Counter
 }
 
 func newTunnelServerMetrics() *TunnelServerMetrics {
@@ -63,14 +65,32 @@ func newTunnelServerMetrics() *TunnelServerMetrics {
 			Name:      "cloud_nodes_counter",
 			Help:      "counter of cloud nodes that do not run tunnel agent",
 		})
+	sessionResumedCounter := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "resumed_sessions_total",
+			Help:      "the number of agent sessions resumed after the connection is lost",
+		})
+	sessionExpiredCounter := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "expired_sessions_total",
+			Help:      "the number of agent sessions closed because they were not resumed in time",
+		})
 
 	prometheus.MustRegister(proxyingRequestsCollector)
 	prometheus.MustRegister(proxyingRequestsGauge)
 	prometheus.MustRegister(cloudNodeGauge)
+	prometheus.MustRegister(sessionResumedCounter)
+	prometheus.MustRegister(sessionExpiredCounter)
 	return &TunnelServerMetrics{
 		proxyingRequestsCollector: proxyingRequestsCollector,
 		proxyingRequestsGauge:     proxyingRequestsGauge,
 		cloudNodeGauge:            cloudNodeGauge,
+		sessionResumedCounter:     sessionResumedCounter,
+		sessionExpiredCounter:     sessionExpiredCounter,
 	}
 }
 
@@ -93,3 +113,11 @@ func (tsm *TunnelServerMetrics) DecInFlightRequests(verb, path string) {
 func (tsm *TunnelServerMetrics) ObserveCloudNodes(cnt int) {
 	tsm.cloudNodeGauge.Set(float64(cnt))
 }
+
+func (tsm *TunnelServerMetrics) IncResumedSessions() {
+	tsm.sessionResumedCounter.Inc()
+}
+
+func (tsm *TunnelServerMetrics) IncExpiredSessions() {
+	tsm.sessionExpiredCounter.Inc()
+}
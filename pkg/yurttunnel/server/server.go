@@ -18,6 +18,9 @@ package server
 
 import (
 	"crypto/tls"
+	"time"
+
+	"google.golang.org/grpc/keepalive"
 
 	hw "github.com/openyurtio/openyurt/pkg/yurttunnel/handlerwrapper"
 )
@@ -39,7 +42,9 @@ func NewTunnelServer(
 	tlsCfg *tls.Config,
 	proxyClientTlsCfg *tls.Config,
 	wrappers hw.HandlerWrappers,
-	proxyStrategy string) TunnelServer {
+	proxyStrategy string,
+	agentKeepAlive keepalive.ServerParameters,
+	sessionResumeTimeout time.Duration) TunnelServer {
 	ats := anpTunnelServer{
 		egressSelectorEnabled:    egressSelectorEnabled,
 		interceptorServerUDSFile: interceptorServerUDSFile,
@@ -51,6 +56,8 @@ func NewTunnelServer(
 		proxyClientTlsCfg:        proxyClientTlsCfg,
 		wrappers:                 wrappers,
 		proxyStrategy:            proxyStrategy,
+		agentKeepAlive:           agentKeepAlive,
+		sessionResumeTimeout:     sessionResumeTimeout,
 	}
 	return &ats
 }
@@ -0,0 +1,197 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DialFunc dials a new underlying connection to addr.
+type DialFunc func(ctx context.Context, addr string) (net.Conn, error)
+
+type dialer struct {
+	cfg  *Config
+	dial DialFunc
+}
+
+// NewDialer returns a dial function that establishes resumable sessions over
+// the connections created by dial, it can be used with grpc.WithContextDialer.
+// If dial is nil, tcp connections are used.
+func NewDialer(cfg *Config, dial DialFunc) DialFunc {
+	if dial == nil {
+		dial = func(ctx context.Context, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+		}
+	}
+	d := &dialer{cfg: cfg, dial: dial}
+	return d.DialContext
+}
+
+// DialContext starts a new session with the server at addr.
+func (d *dialer) DialContext(ctx context.Context, addr string) (net.Conn, error) {
+	keepalive := d.cfg.keepaliveInterval()
+	raw, rp, err := d.handshake(ctx, addr, hello{keepalive: keepalive})
+	if err != nil {
+		return nil, err
+	}
+	if rp.status != statusNew {
+		raw.Close()
+		return nil, fmt.Errorf("could not start session, unexpected status %d", rp.status)
+	}
+
+	c := newConn(rp.token, keepalive, raw)
+	c.onDetach = func(c *Conn, _ uint64, err error) {
+		go d.resume(c, addr, err)
+	}
+	if err := c.attach(raw, rp.recvd); err != nil {
+		return nil, err
+	}
+	klog.V(2).Infof("session %s is started with %s", c, addr)
+	return c, nil
+}
+
+// handshake dials a new underlying connection and exchanges hello and reply
+// messages on it.
+func (d *dialer) handshake(ctx context.Context, addr string, h hello) (net.Conn, reply, error) {
+	var rp reply
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+	raw, err := d.dial(ctx, addr)
+	if err != nil {
+		return nil, rp, err
+	}
+
+	deadline, _ := ctx.Deadline()
+	raw.SetDeadline(deadline)
+	if err := writeHello(raw, h); err != nil {
+		raw.Close()
+		return nil, rp, fmt.Errorf("could not send session hello, %w", err)
+	}
+	if rp, err = readReply(raw); err != nil {
+		raw.Close()
+		return nil, rp, fmt.Errorf("could not read session reply, %w", err)
+	}
+	raw.SetDeadline(time.Time{})
+	return raw, rp, nil
+}
+
+// resume keeps dialing the server until the session is resumed or the
+// resume timeout is reached.
+func (d *dialer) resume(c *Conn, addr string, cause error) {
+	klog.Warningf("session %s lost its connection to %s, %v, try to resume it", c, addr, cause)
+	if !c.resumable() {
+		klog.Errorf("could not resume session %s, %v", c, ErrSessionNotResumable)
+		c.closeWith(ErrSessionNotResumable, false)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), d.cfg.resumeTimeout())
+	defer cancel()
+
+	backoff := d.cfg.Backoff
+	if backoff.Duration <= 0 {
+		backoff = NewBackoff(DefaultReconnectBackoff, DefaultMaxReconnectBackoff)
+	}
+	for {
+		if c.isClosed() {
+			return
+		}
+		callHook(d.cfg.OnReconnect)
+		err := d.resumeOnce(ctx, c, addr)
+		if err == nil {
+			callHook(d.cfg.OnResume)
+			klog.Infof("session %s is resumed with %s", c, addr)
+			return
+		}
+		if err == ErrSessionUnknown || err == ErrSessionNotResumable {
+			klog.Errorf("could not resume session %s, %v", c, err)
+			c.closeWith(err, false)
+			callHook(d.cfg.OnExpire)
+			return
+		}
+		klog.V(2).Infof("could not resume session %s, %v", c, err)
+
+		timer := time.NewTimer(backoff.Step())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			klog.Errorf("session %s is not resumed within %v, close it", c, d.cfg.resumeTimeout())
+			c.closeWith(ErrSessionExpired, false)
+			callHook(d.cfg.OnExpire)
+			return
+		case <-c.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// resumeOnce dials the server and resumes the session with the proof of
+// the resume key.
+func (d *dialer) resumeOnce(ctx context.Context, c *Conn, addr string) error {
+	h := hello{
+		token:     c.token,
+		recvd:     c.recvdOffset(),
+		keepalive: c.keepalive,
+	}
+	raw, rp, err := d.handshake(ctx, addr, h)
+	if err != nil {
+		return err
+	}
+
+	if rp.status == statusChallenge {
+		proof, ok := c.resumeProof(rp.token, h.recvd)
+		if !ok {
+			raw.Close()
+			return ErrSessionNotResumable
+		}
+		deadline, _ := ctx.Deadline()
+		raw.SetDeadline(minTime(deadline, time.Now().Add(handshakeTimeout)))
+		if _, err := raw.Write(proof); err != nil {
+			raw.Close()
+			return fmt.Errorf("could not send proof of resume key, %w", err)
+		}
+		if rp, err = readReply(raw); err != nil {
+			raw.Close()
+			return fmt.Errorf("could not read session reply, %w", err)
+		}
+		raw.SetDeadline(time.Time{})
+	}
+
+	switch rp.status {
+	case statusResumed:
+		return c.attach(raw, rp.recvd)
+	case statusUnknown:
+		raw.Close()
+		return ErrSessionUnknown
+	default:
+		raw.Close()
+		return fmt.Errorf("unexpected session status %d", rp.status)
+	}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if !a.IsZero() && a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,572 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// frame types exchanged on the underlying connection
+	frameData  byte = 1
+	frameAck   byte = 2
+	frameClose byte = 3
+
+	// maxFrameSize is the largest payload carried by a single data frame
+	maxFrameSize = 32 * 1024
+	// maxUnackedBytes bounds the replay buffer, Write blocks when it is full
+	maxUnackedBytes = 8 * 1024 * 1024
+	// ackThreshold is the amount of received bytes that triggers an
+	// acknowledgement before the next keepalive tick
+	ackThreshold = 64 * 1024
+	// keepaliveMisses is the number of keepalive intervals without any frame
+	// from the peer after which the underlying connection is considered dead
+	keepaliveMisses = 3
+)
+
+var (
+	// ErrSessionExpired is returned when a session could not be resumed
+	// within the resume timeout.
+	ErrSessionExpired = errors.New("session expired")
+	// ErrSessionUnknown is returned when the peer does not know the session
+	// any more, e.g. the tunnel server has restarted.
+	ErrSessionUnknown = errors.New("session is unknown to the peer")
+	// ErrSessionNotResumable is returned when the connection of a session is
+	// lost before the resume key is bound to the session.
+	ErrSessionNotResumable = errors.New("session is not resumable without resume key")
+)
+
+// link is an underlying connection that a session is attached to.
+type link struct {
+	raw        net.Conn
+	sent       uint64
+	ackPending bool
+	done       chan struct{}
+	once       sync.Once
+}
+
+func (l *link) stop() {
+	l.once.Do(func() {
+		close(l.done)
+		l.raw.Close()
+	})
+}
+
+// Conn is a net.Conn that survives the loss of its underlying connection.
+// Every byte written is kept in a replay buffer until the peer acknowledges
+// it, so after the underlying connection is replaced by a new one, both
+// sides replay what the other side has not received yet and the stream
+// carried on top of the session (TLS and gRPC) never notices the blip.
+type Conn struct {
+	token      [tokenLen]byte
+	keepalive  time.Duration
+	localAddr  net.Addr
+	remoteAddr net.Addr
+	// onDetach is called when the underlying connection is lost while the
+	// session is still open, epoch identifies the lost connection.
+	onDetach func(c *Conn, epoch uint64, err error)
+	// onClose is called once when the session is closed.
+	onClose func(c *Conn)
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	link *link
+	// resumeKey is exported from the tls connection on top of the session,
+	// the session can not be resumed without it.
+	resumeKey []byte
+	// epoch is increased every time the session is attached to a new
+	// underlying connection.
+	epoch uint64
+	// unacked holds the written bytes that are not acknowledged by the peer,
+	// acked is the stream offset of unacked[0].
+	unacked []byte
+	acked   uint64
+	// readable holds the received bytes that are not read yet, recvd is the
+	// total number of bytes received in the session.
+	readable    []byte
+	recvd       uint64
+	recvdUnsent uint64
+	closed      bool
+	closeErr    error
+	done        chan struct{}
+
+	readDeadline  time.Time
+	writeDeadline time.Time
+	readTimer     *time.Timer
+	writeTimer    *time.Timer
+}
+
+var _ net.Conn = &Conn{}
+
+func newConn(token [tokenLen]byte, keepalive time.Duration, raw net.Conn) *Conn {
+	c := &Conn{
+		token:      token,
+		keepalive:  keepalive,
+		localAddr:  raw.LocalAddr(),
+		remoteAddr: raw.RemoteAddr(),
+		done:       make(chan struct{}),
+	}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// String returns the session token in hex, it is used for logging.
+func (c *Conn) String() string {
+	return hex.EncodeToString(c.token[:])
+}
+
+// Read reads data received in the session. It blocks while the session is
+// detached from its underlying connection.
+func (c *Conn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.readable) == 0 {
+		if c.closed {
+			if c.closeErr == nil {
+				return 0, io.EOF
+			}
+			return 0, c.closeErr
+		}
+		if deadlineExceeded(c.readDeadline) {
+			return 0, os.ErrDeadlineExceeded
+		}
+		c.cond.Wait()
+	}
+
+	n := copy(b, c.readable)
+	c.readable = c.readable[n:]
+	if len(c.readable) == 0 {
+		c.readable = nil
+	}
+	return n, nil
+}
+
+// Write queues data in the replay buffer of the session, the data is sent
+// to the peer as soon as the session is attached to an underlying connection.
+func (c *Conn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for len(b) > 0 {
+		for !c.closed && len(c.unacked) >= maxUnackedBytes {
+			if deadlineExceeded(c.writeDeadline) {
+				return n, os.ErrDeadlineExceeded
+			}
+			c.cond.Wait()
+		}
+		if c.closed {
+			if c.closeErr == nil {
+				return n, net.ErrClosed
+			}
+			return n, c.closeErr
+		}
+
+		chunk := min(maxUnackedBytes-len(c.unacked), len(b))
+		c.unacked = append(c.unacked, b[:chunk]...)
+		b = b[chunk:]
+		n += chunk
+		c.cond.Broadcast()
+	}
+	return n, nil
+}
+
+// Close closes the session, the data in the replay buffer is flushed to
+// the peer before the underlying connection is closed.
+func (c *Conn) Close() error {
+	c.closeWith(nil, true)
+	return nil
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+func (c *Conn) SetDeadline(t time.Time) error {
+	c.SetReadDeadline(t)
+	c.SetWriteDeadline(t)
+	return nil
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	c.readTimer = c.resetTimer(c.readTimer, t)
+	return nil
+}
+
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeDeadline = t
+	c.writeTimer = c.resetTimer(c.writeTimer, t)
+	return nil
+}
+
+// resetTimer wakes up the blocked readers and writers when the deadline t
+// is reached.
+func (c *Conn) resetTimer(timer *time.Timer, t time.Time) *time.Timer {
+	if timer != nil {
+		timer.Stop()
+	}
+	c.cond.Broadcast()
+	if t.IsZero() {
+		return nil
+	}
+	return time.AfterFunc(time.Until(t), func() {
+		c.mu.Lock()
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	})
+}
+
+func deadlineExceeded(t time.Time) bool {
+	return !t.IsZero() && !time.Now().Before(t)
+}
+
+// bindTLS derives the resume key of the session from the tls connection
+// established on top of it, both peers get the same key while others can't.
+func (c *Conn) bindTLS(state tls.ConnectionState) error {
+	key, err := state.ExportKeyingMaterial(resumeKeyLabel, c.token[:], resumeKeyLen)
+	if err != nil {
+		return err
+	}
+	c.setResumeKey(key)
+	return nil
+}
+
+func (c *Conn) setResumeKey(key []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resumeKey = key
+}
+
+// resumable reports whether the resume key is bound to the session.
+func (c *Conn) resumable() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.resumeKey) != 0
+}
+
+// resumeProof returns the proof of resume key for the nonce and the received
+// offset, false is returned if the session has no resume key.
+func (c *Conn) resumeProof(nonce [tokenLen]byte, recvd uint64) ([]byte, bool) {
+	c.mu.Lock()
+	key := c.resumeKey
+	c.mu.Unlock()
+	if len(key) == 0 {
+		return nil, false
+	}
+	return computeProof(key, c.token, nonce, recvd), true
+}
+
+// isClosed reports whether the session has been closed.
+func (c *Conn) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// closeWith closes the session with err. If graceful is true, the pending
+// data is flushed and a close frame is sent to the peer before the
+// underlying connection is closed.
+func (c *Conn) closeWith(err error, graceful bool) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	c.closed = true
+	c.closeErr = err
+	close(c.done)
+	l := c.link
+	if !graceful {
+		c.link = nil
+	}
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	if l != nil && !graceful {
+		l.stop()
+	}
+	if c.onClose != nil {
+		c.onClose(c)
+	}
+}
+
+// detachForResume drops the current underlying connection of the session
+// and returns the number of bytes received in the session, which is used by
+// the peer to decide where to start replaying.
+func (c *Conn) detachForResume() (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return 0, net.ErrClosed
+	}
+	if c.link != nil {
+		c.link.stop()
+		c.link = nil
+		c.cond.Broadcast()
+	}
+	return c.recvd, nil
+}
+
+// recvdOffset returns the number of bytes received in the session.
+func (c *Conn) recvdOffset() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recvd
+}
+
+// attach attaches the session to the underlying connection raw. peerRecvd is
+// the number of bytes the peer has received in the session, all the bytes
+// after it are replayed on raw.
+func (c *Conn) attach(raw net.Conn, peerRecvd uint64) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		raw.Close()
+		return net.ErrClosed
+	}
+	if c.link != nil {
+		c.link.stop()
+		c.link = nil
+	}
+	if peerRecvd < c.acked || peerRecvd > c.acked+uint64(len(c.unacked)) {
+		c.mu.Unlock()
+		raw.Close()
+		err := fmt.Errorf("peer has received %d bytes, but only bytes in range [%d, %d] can be replayed",
+			peerRecvd, c.acked, c.acked+uint64(len(c.unacked)))
+		c.closeWith(err, false)
+		return err
+	}
+	c.trim(peerRecvd)
+
+	l := &link{
+		raw:  raw,
+		sent: peerRecvd,
+		// announce the received offset on the new connection at once
+		ackPending: true,
+		done:       make(chan struct{}),
+	}
+	c.link = l
+	c.epoch++
+	c.localAddr = raw.LocalAddr()
+	c.remoteAddr = raw.RemoteAddr()
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	go c.readLoop(l)
+	go c.sendLoop(l)
+	go c.keepaliveLoop(l)
+	return nil
+}
+
+// detach is called when the underlying connection of link l fails.
+func (c *Conn) detach(l *link, err error) {
+	l.stop()
+
+	c.mu.Lock()
+	if c.link != l {
+		c.mu.Unlock()
+		return
+	}
+	c.link = nil
+	closed := c.closed
+	epoch := c.epoch
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	if !closed && c.onDetach != nil {
+		c.onDetach(c, epoch, err)
+	}
+}
+
+// expireIfDetached closes the session if it has not been attached to a new
+// underlying connection since epoch.
+func (c *Conn) expireIfDetached(epoch uint64) bool {
+	c.mu.Lock()
+	expired := !c.closed && c.link == nil && c.epoch == epoch
+	c.mu.Unlock()
+
+	if expired {
+		c.closeWith(ErrSessionExpired, false)
+	}
+	return expired
+}
+
+// trim drops the bytes acknowledged by the peer from the replay buffer.
+func (c *Conn) trim(offset uint64) {
+	if offset <= c.acked {
+		return
+	}
+	n := min(offset-c.acked, uint64(len(c.unacked)))
+	c.unacked = c.unacked[n:]
+	if len(c.unacked) == 0 {
+		c.unacked = nil
+	}
+	c.acked += n
+}
+
+// readLoop reads frames from the underlying connection of link l.
+func (c *Conn) readLoop(l *link) {
+	r := bufio.NewReader(l.raw)
+	var hdr [8]byte
+	for {
+		l.raw.SetReadDeadline(time.Now().Add(keepaliveMisses * c.keepalive))
+		frameType, err := r.ReadByte()
+		if err != nil {
+			c.detach(l, err)
+			return
+		}
+
+		switch frameType {
+		case frameData:
+			if _, err := io.ReadFull(r, hdr[:4]); err != nil {
+				c.detach(l, err)
+				return
+			}
+			size := binary.BigEndian.Uint32(hdr[:4])
+			if size == 0 || size > maxFrameSize {
+				c.detach(l, fmt.Errorf("invalid data frame size %d", size))
+				return
+			}
+			payload := make([]byte, size)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				c.detach(l, err)
+				return
+			}
+
+			c.mu.Lock()
+			if c.link != l {
+				c.mu.Unlock()
+				return
+			}
+			c.readable = append(c.readable, payload...)
+			c.recvd += uint64(size)
+			c.recvdUnsent += uint64(size)
+			if c.recvdUnsent >= ackThreshold {
+				l.ackPending = true
+			}
+			c.cond.Broadcast()
+			c.mu.Unlock()
+		case frameAck:
+			if _, err := io.ReadFull(r, hdr[:8]); err != nil {
+				c.detach(l, err)
+				return
+			}
+			c.mu.Lock()
+			if c.link != l {
+				c.mu.Unlock()
+				return
+			}
+			c.trim(binary.BigEndian.Uint64(hdr[:8]))
+			c.cond.Broadcast()
+			c.mu.Unlock()
+		case frameClose:
+			c.closeWith(nil, false)
+			return
+		default:
+			c.detach(l, fmt.Errorf("unknown frame type %d", frameType))
+			return
+		}
+	}
+}
+
+// sendLoop is the only writer of the underlying connection of link l. It
+// sends acknowledgements and the data in the replay buffer that has not
+// been sent on l yet.
+func (c *Conn) sendLoop(l *link) {
+	buf := make([]byte, 0, 13+5+maxFrameSize)
+	for {
+		c.mu.Lock()
+		for c.link == l && !c.closed && !l.ackPending && l.sent >= c.acked+uint64(len(c.unacked)) {
+			c.cond.Wait()
+		}
+		if c.link != l {
+			c.mu.Unlock()
+			return
+		}
+
+		buf = buf[:0]
+		if l.ackPending {
+			buf = append(buf, frameAck)
+			buf = binary.BigEndian.AppendUint64(buf, c.recvd)
+			l.ackPending = false
+			c.recvdUnsent = 0
+		}
+		if end := c.acked + uint64(len(c.unacked)); l.sent < end {
+			start := l.sent - c.acked
+			size := min(end-l.sent, maxFrameSize)
+			buf = append(buf, frameData)
+			buf = binary.BigEndian.AppendUint32(buf, uint32(size))
+			buf = append(buf, c.unacked[start:start+size]...)
+			l.sent += size
+		}
+		closing := c.closed && len(buf) == 0
+		if closing {
+			buf = append(buf, frameClose)
+		}
+		c.mu.Unlock()
+
+		l.raw.SetWriteDeadline(time.Now().Add(keepaliveMisses * c.keepalive))
+		if _, err := l.raw.Write(buf); err != nil {
+			c.detach(l, err)
+			return
+		}
+		if closing {
+			l.stop()
+			return
+		}
+	}
+}
+
+// keepaliveLoop makes sendLoop send an acknowledgement every keepalive
+// interval, so the peer can tell a silent connection from a dead one.
+func (c *Conn) keepaliveLoop(l *link) {
+	ticker := time.NewTicker(c.keepalive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			if c.link != l {
+				c.mu.Unlock()
+				return
+			}
+			l.ackPending = true
+			c.cond.Broadcast()
+			c.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/credentials"
+	"k8s.io/klog/v2"
+)
+
+// transportCredentials binds the resume key to the session after the tls
+// handshake on top of it is finished.
+type transportCredentials struct {
+	credentials.TransportCredentials
+}
+
+// NewTransportCredentials wraps the tls credentials of grpc, so that the
+// sessions carrying the tls connections can be resumed. The connections
+// that are not sessions are handled by creds as they are.
+func NewTransportCredentials(creds credentials.TransportCredentials) credentials.TransportCredentials {
+	return &transportCredentials{TransportCredentials: creds}
+}
+
+func (tc *transportCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, authInfo, err := tc.TransportCredentials.ClientHandshake(ctx, authority, rawConn)
+	if err == nil {
+		bindResumeKey(rawConn, authInfo)
+	}
+	return conn, authInfo, err
+}
+
+func (tc *transportCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, authInfo, err := tc.TransportCredentials.ServerHandshake(rawConn)
+	if err == nil {
+		bindResumeKey(rawConn, authInfo)
+	}
+	return conn, authInfo, err
+}
+
+func (tc *transportCredentials) Clone() credentials.TransportCredentials {
+	return &transportCredentials{TransportCredentials: tc.TransportCredentials.Clone()}
+}
+
+func bindResumeKey(rawConn net.Conn, authInfo credentials.AuthInfo) {
+	c, ok := rawConn.(*Conn)
+	if !ok {
+		return
+	}
+	tlsInfo, ok := authInfo.(credentials.TLSInfo)
+	if !ok {
+		klog.Warningf("session %s is not resumable, %s connection is carried on it", c, authInfo.AuthType())
+		return
+	}
+	if err := c.bindTLS(tlsInfo.State); err != nil {
+		klog.Warningf("session %s is not resumable, could not export resume key, %v", c, err)
+	}
+}
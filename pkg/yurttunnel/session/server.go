@@ -0,0 +1,296 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// listener serves resumable sessions over the connections accepted by the
+// wrapped listener. Connections that don't start with the session preface
+// are returned as they are, so agents without session resumption keep
+// working.
+type listener struct {
+	net.Listener
+	cfg *Config
+
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu       sync.Mutex
+	sessions map[[tokenLen]byte]*Conn
+	// detached holds the sessions waiting to be resumed
+	detached map[[tokenLen]byte]struct{}
+}
+
+// NewListener wraps inner with a listener that accepts resumable sessions.
+func NewListener(inner net.Listener, cfg *Config) net.Listener {
+	l := &listener{
+		Listener: inner,
+		cfg:      cfg,
+		conns:    make(chan net.Conn),
+		done:     make(chan struct{}),
+		sessions: make(map[[tokenLen]byte]*Conn),
+		detached: make(map[[tokenLen]byte]struct{}),
+	}
+	go l.acceptLoop()
+	return l
+}
+
+// Accept returns the next new session or plain connection.
+func (l *listener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes the wrapped listener and all of the sessions.
+func (l *listener) Close() error {
+	err := l.Listener.Close()
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+
+	l.mu.Lock()
+	sessions := make([]*Conn, 0, len(l.sessions))
+	for _, c := range l.sessions {
+		sessions = append(sessions, c)
+	}
+	l.mu.Unlock()
+	for _, c := range sessions {
+		c.Close()
+	}
+	return err
+}
+
+func (l *listener) acceptLoop() {
+	for {
+		raw, err := l.Listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				l.closeOnce.Do(func() {
+					close(l.done)
+				})
+				return
+			}
+			klog.Errorf("could not accept connection, %v", err)
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		go l.serve(raw)
+	}
+}
+
+// serve reads the preface of raw and starts or resumes a session on it.
+func (l *listener) serve(raw net.Conn) {
+	raw.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	preface := make([]byte, len(magic))
+	if _, err := io.ReadFull(raw, preface); err != nil {
+		klog.V(2).Infof("could not read preface from %s, %v", raw.RemoteAddr(), err)
+		raw.Close()
+		return
+	}
+	if string(preface) != magic {
+		raw.SetReadDeadline(time.Time{})
+		l.deliver(&prefaceConn{Conn: raw, preface: preface})
+		return
+	}
+
+	raw.SetDeadline(time.Now().Add(handshakeTimeout))
+	h, err := readHello(raw)
+	if err != nil {
+		klog.Errorf("could not read session hello from %s, %v", raw.RemoteAddr(), err)
+		raw.Close()
+		return
+	}
+	if h.token == [tokenLen]byte{} {
+		l.startSession(raw, h)
+	} else {
+		l.resumeSession(raw, h)
+	}
+}
+
+func (l *listener) startSession(raw net.Conn, h hello) {
+	var token [tokenLen]byte
+	if _, err := rand.Read(token[:]); err != nil {
+		klog.Errorf("could not generate session token, %v", err)
+		raw.Close()
+		return
+	}
+	keepalive := h.keepalive
+	if keepalive <= 0 {
+		keepalive = DefaultKeepaliveInterval
+	}
+
+	c := newConn(token, keepalive, raw)
+	c.onDetach = l.onDetach
+	c.onClose = l.onClose
+	if err := writeReply(raw, reply{status: statusNew, token: token}); err != nil {
+		klog.Errorf("could not reply session hello to %s, %v", raw.RemoteAddr(), err)
+		raw.Close()
+		return
+	}
+	raw.SetDeadline(time.Time{})
+
+	l.mu.Lock()
+	l.sessions[token] = c
+	l.mu.Unlock()
+	if err := c.attach(raw, h.recvd); err != nil {
+		klog.Errorf("could not start session %s, %v", c, err)
+		return
+	}
+	klog.V(2).Infof("session %s is started by %s", c, raw.RemoteAddr())
+	l.deliver(c)
+}
+
+// resumeSession challenges the agent to prove the knowledge of the resume
+// key, and resumes the session on raw only if the proof is valid, so that
+// the session can't be taken over by anyone who has seen its token.
+func (l *listener) resumeSession(raw net.Conn, h hello) {
+	l.mu.Lock()
+	c, ok := l.sessions[h.token]
+	l.mu.Unlock()
+	if !ok {
+		klog.Warningf("session %x from %s is unknown", h.token, raw.RemoteAddr())
+		rejectResume(raw, h.token)
+		return
+	}
+
+	var nonce [tokenLen]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		klog.Errorf("could not generate nonce for session %s, %v", c, err)
+		raw.Close()
+		return
+	}
+	if err := writeReply(raw, reply{status: statusChallenge, token: nonce}); err != nil {
+		klog.Errorf("could not challenge session %s from %s, %v", c, raw.RemoteAddr(), err)
+		raw.Close()
+		return
+	}
+	proof := make([]byte, proofLen)
+	if _, err := io.ReadFull(raw, proof); err != nil {
+		klog.Errorf("could not read proof of session %s from %s, %v", c, raw.RemoteAddr(), err)
+		raw.Close()
+		return
+	}
+	if expected, ok := c.resumeProof(nonce, h.recvd); !ok || !hmac.Equal(proof, expected) {
+		klog.Warningf("session %s from %s is not resumed, invalid proof of resume key", c, raw.RemoteAddr())
+		rejectResume(raw, h.token)
+		return
+	}
+
+	recvd, err := c.detachForResume()
+	if err != nil {
+		klog.Warningf("session %s from %s is unknown, %v", c, raw.RemoteAddr(), err)
+		rejectResume(raw, h.token)
+		return
+	}
+	if err := writeReply(raw, reply{status: statusResumed, token: h.token, recvd: recvd}); err != nil {
+		klog.Errorf("could not reply session hello to %s, %v", raw.RemoteAddr(), err)
+		raw.Close()
+		return
+	}
+	raw.SetDeadline(time.Time{})
+	if err := c.attach(raw, h.recvd); err != nil {
+		klog.Errorf("could not resume session %s, %v", c, err)
+		return
+	}
+	l.mu.Lock()
+	delete(l.detached, c.token)
+	l.mu.Unlock()
+	callHook(l.cfg.OnResume)
+	klog.Infof("session %s is resumed by %s", c, raw.RemoteAddr())
+}
+
+func rejectResume(raw net.Conn, token [tokenLen]byte) {
+	writeReply(raw, reply{status: statusUnknown, token: token})
+	raw.Close()
+}
+
+func (l *listener) deliver(c net.Conn) {
+	select {
+	case l.conns <- c:
+	case <-l.done:
+		c.Close()
+	}
+}
+
+// onDetach expires the session if it is not resumed within the resume timeout.
+// The sessions without resume key, e.g. the tls handshake is not finished on
+// them, and the sessions beyond MaxDetachedSessions are closed at once.
+func (l *listener) onDetach(c *Conn, epoch uint64, err error) {
+	if !c.resumable() {
+		klog.V(2).Infof("session %s lost its connection before it's resumable, %v, close it", c, err)
+		c.closeWith(ErrSessionNotResumable, false)
+		return
+	}
+	l.mu.Lock()
+	if _, ok := l.detached[c.token]; !ok && len(l.detached) >= l.cfg.maxDetachedSessions() {
+		l.mu.Unlock()
+		klog.Warningf("session %s lost its connection, %v, close it as %d sessions are waiting to be resumed", c, err, l.cfg.maxDetachedSessions())
+		c.closeWith(ErrSessionExpired, false)
+		callHook(l.cfg.OnExpire)
+		return
+	}
+	l.detached[c.token] = struct{}{}
+	l.mu.Unlock()
+
+	klog.Warningf("session %s lost its connection, %v, wait %v for it to be resumed", c, err, l.cfg.resumeTimeout())
+	time.AfterFunc(l.cfg.resumeTimeout(), func() {
+		if c.expireIfDetached(epoch) {
+			klog.Warningf("session %s is not resumed within %v, close it", c, l.cfg.resumeTimeout())
+			callHook(l.cfg.OnExpire)
+		}
+	})
+}
+
+func (l *listener) onClose(c *Conn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.sessions[c.token] == c {
+		delete(l.sessions, c.token)
+		delete(l.detached, c.token)
+	}
+}
+
+// prefaceConn returns the bytes read for detecting the session preface
+// before reading from the connection.
+type prefaceConn struct {
+	net.Conn
+	preface []byte
+}
+
+func (pc *prefaceConn) Read(b []byte) (int, error) {
+	if len(pc.preface) > 0 {
+		n := copy(b, pc.preface)
+		pc.preface = pc.preface[n:]
+		return n, nil
+	}
+	return pc.Conn.Read(b)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package session implements resumable sessions between yurt-tunnel-agent
+// and yurt-tunnel-server. A session sits between the TCP connection and the
+// TLS/gRPC connection of the tunnel, when the TCP connection breaks, the
+// agent dials a new one and resumes the session with its token, so the
+// gRPC connection and the streams proxied through it, like kubectl logs -f
+// and kubectl exec, survive short network blips.
+//
+// The token is sent in cleartext, so a session can only be resumed by the
+// peer that proves the knowledge of the resume key, which is exported from
+// the TLS connection established on top of the session. The sessions are
+// kept in the memory of each tunnel server, so with multiple tunnel servers
+// behind a load balancer, the load balancer should route the connections of
+// an agent to the same server (e.g. by client ip affinity), otherwise the
+// session is unknown to the server and the agent starts a new one.
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// magic is the preface sent by agents that support session resumption,
+	// connections without it are served as plain connections.
+	magic           = "YTRS"
+	protocolVersion = 1
+	tokenLen        = 16
+
+	// length of the hello message after magic: version, token, received
+	// offset and keepalive interval in milliseconds
+	helloLen = 1 + tokenLen + 8 + 4
+	// length of the reply message: status, token and received offset
+	replyLen = 1 + tokenLen + 8
+
+	statusNew     byte = 0
+	statusResumed byte = 1
+	statusUnknown byte = 2
+	// statusChallenge asks the agent to prove the knowledge of the resume
+	// key, the token field of the reply carries the nonce.
+	statusChallenge byte = 3
+
+	// resumeKeyLabel is the label for exporting the resume key from the
+	// tls connection established on top of the session.
+	resumeKeyLabel = "EXPORTER-openyurt-tunnel-session-resume"
+	resumeKeyLen   = 32
+	proofLen       = sha256.Size
+
+	handshakeTimeout = 10 * time.Second
+
+	DefaultResumeTimeout       = 30 * time.Second
+	DefaultKeepaliveInterval   = 5 * time.Second
+	DefaultReconnectBackoff    = 1 * time.Second
+	DefaultMaxReconnectBackoff = 10 * time.Second
+	DefaultMaxDetachedSessions = 1000
+)
+
+// Config is the configuration of resumable sessions.
+type Config struct {
+	// ResumeTimeout is how long a session is kept after its underlying
+	// connection is lost. The agent gives up resuming the session and the
+	// server forgets it after this timeout.
+	ResumeTimeout time.Duration
+	// KeepaliveInterval is the interval of keepalive frames on the underlying
+	// connection, a connection without any frame from the peer for three
+	// intervals is considered dead. It is set by the agent and the server
+	// follows the interval of each agent.
+	KeepaliveInterval time.Duration
+	// Backoff is used by the agent between attempts to resume a session.
+	Backoff wait.Backoff
+	// MaxDetachedSessions is the max number of sessions kept by the server
+	// while waiting for them to be resumed, the sessions detached beyond it
+	// are closed at once.
+	MaxDetachedSessions int
+
+	// OnReconnect is called by the agent before each attempt to resume a session.
+	OnReconnect func()
+	// OnResume is called when a session is resumed.
+	OnResume func()
+	// OnExpire is called when a session expires without being resumed.
+	OnExpire func()
+}
+
+// NewBackoff returns a backoff that starts with base and doubles up to max.
+func NewBackoff(base, max time.Duration) wait.Backoff {
+	return wait.Backoff{
+		Duration: base,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    int(^uint(0) >> 1),
+		Cap:      max,
+	}
+}
+
+func (cfg *Config) keepaliveInterval() time.Duration {
+	if cfg.KeepaliveInterval <= 0 {
+		return DefaultKeepaliveInterval
+	}
+	return cfg.KeepaliveInterval
+}
+
+func (cfg *Config) resumeTimeout() time.Duration {
+	if cfg.ResumeTimeout <= 0 {
+		return DefaultResumeTimeout
+	}
+	return cfg.ResumeTimeout
+}
+
+func (cfg *Config) maxDetachedSessions() int {
+	if cfg.MaxDetachedSessions <= 0 {
+		return DefaultMaxDetachedSessions
+	}
+	return cfg.MaxDetachedSessions
+}
+
+func callHook(hook func()) {
+	if hook != nil {
+		hook()
+	}
+}
+
+// hello is sent by the agent to start or resume a session, a zero token
+// starts a new session.
+type hello struct {
+	token     [tokenLen]byte
+	recvd     uint64
+	keepalive time.Duration
+}
+
+func writeHello(w io.Writer, h hello) error {
+	buf := make([]byte, 0, len(magic)+helloLen)
+	buf = append(buf, magic...)
+	buf = append(buf, protocolVersion)
+	buf = append(buf, h.token[:]...)
+	buf = binary.BigEndian.AppendUint64(buf, h.recvd)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(h.keepalive.Milliseconds()))
+	_, err := w.Write(buf)
+	return err
+}
+
+// readHello reads the hello message that follows magic.
+func readHello(r io.Reader) (hello, error) {
+	var h hello
+	buf := make([]byte, helloLen)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return h, err
+	}
+	if buf[0] != protocolVersion {
+		return h, fmt.Errorf("unsupported session protocol version %d", buf[0])
+	}
+	copy(h.token[:], buf[1:1+tokenLen])
+	h.recvd = binary.BigEndian.Uint64(buf[1+tokenLen:])
+	h.keepalive = time.Duration(binary.BigEndian.Uint32(buf[1+tokenLen+8:])) * time.Millisecond
+	return h, nil
+}
+
+// reply is sent by the server in response to hello.
+type reply struct {
+	status byte
+	token  [tokenLen]byte
+	recvd  uint64
+}
+
+func writeReply(w io.Writer, rp reply) error {
+	buf := make([]byte, 0, replyLen)
+	buf = append(buf, rp.status)
+	buf = append(buf, rp.token[:]...)
+	buf = binary.BigEndian.AppendUint64(buf, rp.recvd)
+	_, err := w.Write(buf)
+	return err
+}
+
+func readReply(r io.Reader) (reply, error) {
+	var rp reply
+	buf := make([]byte, replyLen)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return rp, err
+	}
+	rp.status = buf[0]
+	copy(rp.token[:], buf[1:1+tokenLen])
+	rp.recvd = binary.BigEndian.Uint64(buf[1+tokenLen:])
+	return rp, nil
+}
+
+// computeProof returns the proof of the resume key for resuming the session
+// of token with the nonce of server and the received offset in hello.
+func computeProof(key []byte, token, nonce [tokenLen]byte, recvd uint64) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(token[:])
+	mac.Write(nonce[:])
+	mac.Write(binary.BigEndian.AppendUint64(nil, recvd))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,357 @@
+/*
+Copyright 2025 The OpenYurt Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package session
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingDialer dials tcp connections and records them, so tests can
+// break the underlying connection of a session.
+type recordingDialer struct {
+	mu       sync.Mutex
+	conns    []net.Conn
+	disabled bool
+}
+
+func (rd *recordingDialer) dial(ctx context.Context, addr string) (net.Conn, error) {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	if rd.disabled {
+		return nil, errors.New("network is unreachable")
+	}
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err == nil {
+		rd.conns = append(rd.conns, conn)
+	}
+	return conn, err
+}
+
+func (rd *recordingDialer) breakConns(disable bool) {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	for _, conn := range rd.conns {
+		conn.Close()
+	}
+	rd.conns = nil
+	rd.disabled = disable
+}
+
+func (rd *recordingDialer) enable() {
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	rd.disabled = false
+}
+
+func newTestListener(t *testing.T, cfg *Config) net.Listener {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen, %v", err)
+	}
+	l := NewListener(inner, cfg)
+	t.Cleanup(func() {
+		l.Close()
+	})
+	return l
+}
+
+// testResumeKey stands for the key exported from the tls connection on top
+// of the session.
+var testResumeKey = bytes.Repeat([]byte{0x5a}, resumeKeyLen)
+
+// echo copies everything received on the accepted connection back to it.
+func echo(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		if c, ok := conn.(*Conn); ok {
+			c.setResumeKey(testResumeKey)
+		}
+		go func() {
+			io.Copy(conn, conn)
+			conn.Close()
+		}()
+	}
+}
+
+// dialTestSession dials a session with the resume key bound, and waits for
+// the server to accept it.
+func dialTestSession(t *testing.T, dial DialFunc, addr string) *Conn {
+	conn, err := dial(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("could not dial, %v", err)
+	}
+	c := conn.(*Conn)
+	c.setResumeKey(testResumeKey)
+	t.Cleanup(func() {
+		c.Close()
+	})
+
+	msg := []byte("ping")
+	if _, err := c.Write(msg); err != nil {
+		t.Fatalf("could not write, %v", err)
+	}
+	if _, err := io.ReadFull(c, make([]byte, len(msg))); err != nil {
+		t.Fatalf("could not read, %v", err)
+	}
+	return c
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for the condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSessionResume(t *testing.T) {
+	var serverResumed, clientResumed, reconnects atomic.Int32
+	l := newTestListener(t, &Config{
+		ResumeTimeout: 5 * time.Second,
+		OnResume:      func() { serverResumed.Add(1) },
+	})
+	go echo(l)
+
+	rd := &recordingDialer{}
+	dial := NewDialer(&Config{
+		ResumeTimeout:     5 * time.Second,
+		KeepaliveInterval: 100 * time.Millisecond,
+		Backoff:           NewBackoff(50*time.Millisecond, 200*time.Millisecond),
+		OnReconnect:       func() { reconnects.Add(1) },
+		OnResume:          func() { clientResumed.Add(1) },
+	}, rd.dial)
+	conn := dialTestSession(t, dial, l.Addr().String())
+
+	const chunkSize, rounds = 200 * 1024, 3
+	expected := make([]byte, 0, chunkSize*rounds)
+	received := make(chan []byte)
+	go func() {
+		buf := make([]byte, chunkSize*rounds)
+		n, _ := io.ReadFull(conn, buf)
+		received <- buf[:n]
+	}()
+
+	for round := 0; round < rounds; round++ {
+		chunk := bytes.Repeat([]byte{byte('a' + round)}, chunkSize)
+		if _, err := conn.Write(chunk); err != nil {
+			t.Fatalf("could not write round %d, %v", round, err)
+		}
+		expected = append(expected, chunk...)
+		// break the connection in the middle of the stream, and keep the
+		// network down for a while in the second round
+		rd.breakConns(round == 1)
+		if round == 1 {
+			time.Sleep(300 * time.Millisecond)
+			rd.enable()
+		}
+		waitFor(t, func() bool { return clientResumed.Load() == int32(round+1) })
+	}
+
+	select {
+	case got := <-received:
+		if !bytes.Equal(got, expected) {
+			t.Fatalf("expect %d bytes echoed in order, but got %d bytes", len(expected), len(got))
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timeout waiting for the echoed data")
+	}
+
+	if serverResumed.Load() != 3 {
+		t.Errorf("expect 3 resumed sessions on server, but got %d", serverResumed.Load())
+	}
+	if reconnects.Load() <= 3 {
+		t.Errorf("expect more than 3 reconnects, but got %d", reconnects.Load())
+	}
+}
+
+func TestSessionExpired(t *testing.T) {
+	var serverExpired, clientExpired atomic.Int32
+	l := newTestListener(t, &Config{
+		ResumeTimeout: 200 * time.Millisecond,
+		OnExpire:      func() { serverExpired.Add(1) },
+	})
+	go echo(l)
+
+	rd := &recordingDialer{}
+	dial := NewDialer(&Config{
+		ResumeTimeout:     300 * time.Millisecond,
+		KeepaliveInterval: 100 * time.Millisecond,
+		Backoff:           NewBackoff(50*time.Millisecond, 100*time.Millisecond),
+		OnExpire:          func() { clientExpired.Add(1) },
+	}, rd.dial)
+	conn := dialTestSession(t, dial, l.Addr().String())
+
+	rd.breakConns(true)
+	if _, err := conn.Read(make([]byte, 1)); !errors.Is(err, ErrSessionExpired) {
+		t.Fatalf("expect session expired error, but got %v", err)
+	}
+	if _, err := conn.Write([]byte("ping")); !errors.Is(err, ErrSessionExpired) {
+		t.Fatalf("expect session expired error on write, but got %v", err)
+	}
+
+	waitFor(t, func() bool { return clientExpired.Load() == 1 && serverExpired.Load() == 1 })
+}
+
+func TestSessionUnknown(t *testing.T) {
+	l := newTestListener(t, &Config{})
+	go echo(l)
+
+	rd := &recordingDialer{}
+	conn := dialTestSession(t, NewDialer(&Config{
+		KeepaliveInterval: 100 * time.Millisecond,
+		Backoff:           NewBackoff(50*time.Millisecond, 100*time.Millisecond),
+	}, rd.dial), l.Addr().String())
+
+	// the server forgets the session, e.g. after a restart
+	sl := l.(*listener)
+	sl.mu.Lock()
+	sl.sessions = make(map[[tokenLen]byte]*Conn)
+	sl.mu.Unlock()
+
+	rd.breakConns(false)
+	if _, err := conn.Read(make([]byte, 1)); !errors.Is(err, ErrSessionUnknown) {
+		t.Fatalf("expect session unknown error, but got %v", err)
+	}
+}
+
+func TestSessionNotResumable(t *testing.T) {
+	l := newTestListener(t, &Config{})
+	go echo(l)
+
+	rd := &recordingDialer{}
+	conn, err := NewDialer(&Config{
+		KeepaliveInterval: 100 * time.Millisecond,
+		Backoff:           NewBackoff(50*time.Millisecond, 100*time.Millisecond),
+	}, rd.dial)(context.Background(), l.Addr().String())
+	if err != nil {
+		t.Fatalf("could not dial, %v", err)
+	}
+	defer conn.Close()
+
+	// the connection is lost before the tls handshake binds the resume key
+	rd.breakConns(false)
+	if _, err := conn.Read(make([]byte, 1)); !errors.Is(err, ErrSessionNotResumable) {
+		t.Fatalf("expect session not resumable error, but got %v", err)
+	}
+}
+
+func TestSessionResumeWithoutProof(t *testing.T) {
+	l := newTestListener(t, &Config{})
+	go echo(l)
+
+	conn := dialTestSession(t, NewDialer(&Config{
+		KeepaliveInterval: 100 * time.Millisecond,
+	}, nil), l.Addr().String())
+
+	// someone who has seen the token tries to take over the session
+	raw, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("could not dial, %v", err)
+	}
+	defer raw.Close()
+	raw.SetDeadline(time.Now().Add(5 * time.Second))
+	if err := writeHello(raw, hello{token: conn.token, keepalive: time.Second}); err != nil {
+		t.Fatalf("could not write hello, %v", err)
+	}
+	rp, err := readReply(raw)
+	if err != nil || rp.status != statusChallenge {
+		t.Fatalf("expect challenge, but got status %d, %v", rp.status, err)
+	}
+	if _, err := raw.Write(make([]byte, proofLen)); err != nil {
+		t.Fatalf("could not write proof, %v", err)
+	}
+	if rp, err = readReply(raw); err != nil || rp.status != statusUnknown {
+		t.Fatalf("expect session is not resumed, but got status %d, %v", rp.status, err)
+	}
+
+	// the session is still served on its own connection
+	msg := []byte("still alive")
+	if _, err := conn.Write(msg); err != nil {
+		t.Fatalf("could not write, %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	got := make([]byte, len(msg))
+	if _, err := io.ReadFull(conn, got); err != nil || !bytes.Equal(got, msg) {
+		t.Fatalf("expect %q echoed, but got %q, %v", msg, got, err)
+	}
+}
+
+func TestMaxDetachedSessions(t *testing.T) {
+	var serverExpired atomic.Int32
+	l := newTestListener(t, &Config{
+		ResumeTimeout:       time.Minute,
+		MaxDetachedSessions: 1,
+		OnExpire:            func() { serverExpired.Add(1) },
+	})
+	go echo(l)
+
+	rd := &recordingDialer{}
+	dial := NewDialer(&Config{
+		ResumeTimeout:     time.Minute,
+		KeepaliveInterval: 100 * time.Millisecond,
+		Backoff:           NewBackoff(50*time.Millisecond, 100*time.Millisecond),
+	}, rd.dial)
+	dialTestSession(t, dial, l.Addr().String())
+	dialTestSession(t, dial, l.Addr().String())
+
+	// only one of the sessions is kept for resumption
+	rd.breakConns(true)
+	waitFor(t, func() bool { return serverExpired.Load() == 1 })
+	sl := l.(*listener)
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	if len(sl.detached) != 1 || len(sl.sessions) != 1 {
+		t.Errorf("expect 1 detached session, but got %d detached in %d sessions", len(sl.detached), len(sl.sessions))
+	}
+}
+
+func TestPlainConnection(t *testing.T) {
+	l := newTestListener(t, &Config{})
+	go echo(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("could not dial, %v", err)
+	}
+	defer conn.Close()
+
+	msg := []byte("\x16\x03\x01 plain connection")
+	if _, err := conn.Write(msg); err != nil {
+		t.Fatalf("could not write, %v", err)
+	}
+	got := make([]byte, len(msg))
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatalf("could not read, %v", err)
+	}
+	if !bytes.Equal(got, msg) {
+		t.Errorf("expect %q, but got %q", msg, got)
+	}
+}
@@ -41,6 +41,7 @@ const (
 	YurttunnelServerDnatConfigMapNs = "kube-system"
 	yurttunnelServerDnatDataKey     = "dnat-ports-pair"
 	YurtTunnelLocalHostProxyPorts   = "localhost-proxy-ports"
+	YurtTunnelAccessPolicy          = "access-policy"
 	yurttunnelServerHTTPProxyPorts  = "http-proxy-ports"
 	yurttunnelServerHTTPSProxyPorts = "https-proxy-ports"
 	PortsSeparator                  = ","
@@ -29,6 +29,7 @@ import (
 	"testing"
 	"time"
 
+	"google.golang.org/grpc/keepalive"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 	anpserver "sigs.k8s.io/apiserver-network-proxy/pkg/server"
@@ -37,6 +38,7 @@ import (
 	hw "github.com/openyurtio/openyurt/pkg/yurttunnel/handlerwrapper"
 	tr "github.com/openyurtio/openyurt/pkg/yurttunnel/handlerwrapper/tracerequest"
 	ts "github.com/openyurtio/openyurt/pkg/yurttunnel/server"
+	"github.com/openyurtio/openyurt/pkg/yurttunnel/session"
 )
 
 const (
@@ -173,6 +175,8 @@ func startTunnelServer(t *testing.T) {
 		&tlsCfg,
 		wrappers,                                /* hw.HandlerWrappers */
 		string(anpserver.ProxyStrategyDestHost), /* proxyStrategy */
+		keepalive.ServerParameters{Time: 10 * time.Second, Timeout: 5 * time.Second}, /* agentKeepAlive */
+		30*time.Second, /* sessionResumeTimeout */
 	)
 	tunnelServer.Run()
 	klog.Info("[TEST] Yurttunnel Server is running")
@@ -191,6 +195,9 @@ func startTunnelAgent(t *testing.T) {
 		fmt.Sprintf(":%d", ServerAgentPort), /* tunnelServerAddr */
 		"dummy-agent",                       /* nodeName */
 		"ipv4=127.0.0.1&host=localhost",     /* agentIdentifiers */
+		5*time.Second,                       /* syncInterval */
+		5*time.Second,                       /* probeInterval */
+		&session.Config{},                   /* sessionCfg */
 	)
 	tunnelAgent.Run(wait.NeverStop)
 	klog.Info("[TEST] Yurttunnel Agent is running")